@@ -262,6 +262,7 @@ To use this filter, the search query must contain \`type:diff\` or \`type:commit
 - \`select:file\`
 - \`select:file.directory\`
 - \`select:file.path\`
+- \`select:file.rollup\`
 - \`select:content\`
 - \`select:symbol.symboltype\`
 
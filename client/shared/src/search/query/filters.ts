@@ -10,6 +10,7 @@ import type { Filter, Literal } from './token'
 export enum FilterType {
     after = 'after',
     archived = 'archived',
+    at = 'at',
     author = 'author',
     before = 'before',
     case = 'case',
@@ -21,6 +22,7 @@ export enum FilterType {
     fork = 'fork',
     lang = 'lang',
     message = 'message',
+    multiline = 'multiline',
     patterntype = 'patterntype',
     repo = 'repo',
     repohascommitafter = 'repohascommitafter',
@@ -191,6 +193,11 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         discreteValues: () => ['yes', 'only', 'no'].map(value => ({ label: value })),
         singular: true,
     },
+    [FilterType.at]: {
+        description: 'Search each repository at its last commit before a point in time, e.g. 2025-06-01, or "3 months ago"',
+        placeholder: '"3 months ago"',
+        singular: true,
+    },
     [FilterType.author]: {
         negatable: true,
         description: negated => `${negated ? 'Exclude' : 'Include only'} commits or diffs authored by a user.`,
@@ -270,6 +277,12 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
             `${negated ? 'Exclude' : 'Include only'} Commits with messages matching a certain string`,
         placeholder: '"content"',
     },
+    [FilterType.multiline]: {
+        description: 'Let "." in regular expression patterns match newlines, so a match can span multiple lines.',
+        discreteValues: () => ['yes', 'no'].map(value => ({ label: value })),
+        default: 'no',
+        singular: true,
+    },
     [FilterType.patterntype]: {
         discreteValues: () => ['regexp', 'structural', 'literal', 'standard'].map(value => ({ label: value })),
         description: 'The pattern type (standard, regexp, literal, structural) in use',
@@ -55,7 +55,7 @@ export const PREDICATES: Access[] = [
             },
             {
                 name: 'has',
-                fields: [{ name: 'content' }, { name: 'owner' }],
+                fields: [{ name: 'content' }, { name: 'owner' }, { name: 'symbol' }],
             },
         ],
     },
@@ -241,6 +241,12 @@ export const predicateCompletion = (field: string): Completion[] => {
                 asSnippet: true,
                 description: 'Search only inside files that have a contributor that matches a pattern',
             },
+            {
+                label: 'has.symbol(...)',
+                insertText: 'has.symbol(${1})',
+                asSnippet: true,
+                description: 'Search only inside files that define a symbol whose name matches a pattern',
+            },
         ]
     }
     return []
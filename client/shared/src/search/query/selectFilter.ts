@@ -11,7 +11,7 @@ export const SELECTORS: Access[] = [
     },
     {
         name: 'file',
-        fields: [{ name: 'directory' }, { name: 'path' }, { name: 'owners' }],
+        fields: [{ name: 'directory' }, { name: 'path' }, { name: 'owners' }, { name: 'rollup' }],
     },
     {
         name: 'content',
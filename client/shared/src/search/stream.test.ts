@@ -0,0 +1,58 @@
+import { lastValueFrom, of } from 'rxjs'
+import { describe, expect, test } from 'vitest'
+
+import { type SearchEvent, type SearchMatch, switchAggregateSearchResults } from './stream'
+
+describe('switchAggregateSearchResults', () => {
+    const aggregate = (...events: SearchEvent[]): Promise<SearchMatch[]> =>
+        lastValueFrom(of(...events).pipe(switchAggregateSearchResults)).then(results => results.results)
+
+    test('sums up directory rollups', async () => {
+        expect(
+            await aggregate(
+                {
+                    type: 'matches',
+                    data: [{ type: 'path', repository: 'r', commit: 'c', path: 'a/', fileCount: 2, matchCount: 3 }],
+                },
+                {
+                    type: 'matches',
+                    data: [
+                        { type: 'path', repository: 'r', commit: 'c', path: 'a/', fileCount: 1, matchCount: 1 },
+                        { type: 'path', repository: 'r', commit: 'c', path: 'b/', fileCount: 1, matchCount: 1 },
+                    ],
+                }
+            )
+        ).toEqual([
+            { type: 'path', repository: 'r', commit: 'c', path: 'a/', fileCount: 3, matchCount: 4 },
+            { type: 'path', repository: 'r', commit: 'c', path: 'b/', fileCount: 1, matchCount: 1 },
+        ])
+    })
+
+    test('sums up directory rollups with a zero file count', async () => {
+        // A file which was already counted only adds its matches.
+        expect(
+            await aggregate(
+                {
+                    type: 'matches',
+                    data: [{ type: 'path', repository: 'r', commit: 'c', path: 'a/', fileCount: 1, matchCount: 1 }],
+                },
+                {
+                    type: 'matches',
+                    data: [{ type: 'path', repository: 'r', commit: 'c', path: 'a/', fileCount: 0, matchCount: 2 }],
+                }
+            )
+        ).toEqual([{ type: 'path', repository: 'r', commit: 'c', path: 'a/', fileCount: 1, matchCount: 3 }])
+    })
+
+    test('keeps path matches', async () => {
+        expect(
+            await aggregate(
+                { type: 'matches', data: [{ type: 'path', repository: 'r', commit: 'c', path: 'a/x.go' }] },
+                { type: 'matches', data: [{ type: 'path', repository: 'r', commit: 'c', path: 'a/x.go' }] }
+            )
+        ).toEqual([
+            { type: 'path', repository: 'r', commit: 'c', path: 'a/x.go' },
+            { type: 'path', repository: 'r', commit: 'c', path: 'a/x.go' },
+        ])
+    })
+})
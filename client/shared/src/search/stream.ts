@@ -49,6 +49,12 @@ export interface PathMatch {
     branches?: string[]
     commit?: string
     debug?: string
+    /**
+     * Set for directory rollups (select:file.rollup). The counts are deltas
+     * which are summed up over all events for the same directory.
+     */
+    fileCount?: number
+    matchCount?: number
 }
 
 export interface ContentMatch {
@@ -324,6 +330,47 @@ export const emptyAggregateResults: AggregateStreamingSearchResults = {
     },
 }
 
+const isDirectoryRollup = (match: SearchMatch): match is PathMatch =>
+    match.type === 'path' && match.fileCount !== undefined
+
+/**
+ * Appends newMatches to results. Directory rollups for a directory which is
+ * already in results are summed up into the existing match.
+ */
+function appendMatches(results: SearchMatch[], newMatches: SearchMatch[]): SearchMatch[] {
+    if (!newMatches.some(isDirectoryRollup)) {
+        return results.concat(newMatches)
+    }
+
+    const merged = [...results]
+    const directoryIndex = new Map<string, number>()
+    for (const [index, match] of merged.entries()) {
+        if (isDirectoryRollup(match)) {
+            directoryIndex.set(`${match.repository}@${match.commit ?? ''}:${match.path}`, index)
+        }
+    }
+    for (const match of newMatches) {
+        if (!isDirectoryRollup(match)) {
+            merged.push(match)
+            continue
+        }
+        const key = `${match.repository}@${match.commit ?? ''}:${match.path}`
+        const index = directoryIndex.get(key)
+        if (index === undefined) {
+            directoryIndex.set(key, merged.length)
+            merged.push(match)
+            continue
+        }
+        const existing = merged[index] as PathMatch
+        merged[index] = {
+            ...existing,
+            fileCount: (existing.fileCount ?? 0) + (match.fileCount ?? 0),
+            matchCount: (existing.matchCount ?? 0) + (match.matchCount ?? 0),
+        }
+    }
+    return merged
+}
+
 /**
  * Converts a stream of SearchEvents into AggregateStreamingSearchResults
  */
@@ -341,7 +388,7 @@ export const switchAggregateSearchResults: OperatorFunction<SearchEvent, Aggrega
                             return {
                                 ...results,
                                 // Matches are additive
-                                results: results.results.concat(newEvent.value.data),
+                                results: appendMatches(results.results, newEvent.value.data),
                             }
                         }
 
@@ -699,3 +746,21 @@ export function streamComputeQuery(query: string): Observable<string[]> {
         )
     })
 }
+
+/**
+ * Runs a compute diff replace like `content:replace.diff(a -> b)` to
+ * completion and returns all its diffs as a single patch, or as a batch spec
+ * that applies them.
+ */
+export async function fetchComputeDiffs(query: string, format: 'patch' | 'batch-spec'): Promise<Blob> {
+    const response = await fetch(`${computeStreamUrl}?q=${encodeURIComponent(query)}&format=${format}`, {
+        method: 'GET',
+        headers: {
+            'X-Requested-With': 'Sourcegraph',
+        },
+    })
+    if (!response.ok) {
+        throw new Error(await response.text())
+    }
+    return response.blob()
+}
@@ -6,8 +6,11 @@ import BitbucketIcon from 'mdi-react/BitbucketIcon'
 import GithubIcon from 'mdi-react/GithubIcon'
 import GitIcon from 'mdi-react/GitIcon'
 import GitLabIcon from 'mdi-react/GitlabIcon'
+import HexagonOutlineIcon from 'mdi-react/HexagonOutlineIcon'
+import LanguageCsharpIcon from 'mdi-react/LanguageCsharpIcon'
 import LanguageGoIcon from 'mdi-react/LanguageGoIcon'
 import LanguageJavaIcon from 'mdi-react/LanguageJavaIcon'
+import LanguagePhpIcon from 'mdi-react/LanguagePhpIcon'
 import LanguagePythonIcon from 'mdi-react/LanguagePythonIcon'
 import LanguageRubyIcon from 'mdi-react/LanguageRubyIcon'
 import LanguageRustIcon from 'mdi-react/LanguageRustIcon'
@@ -22,16 +25,20 @@ import azureDevOpsSchemaJSON from '../../../../../schema/azuredevops.schema.json
 import bitbucketCloudSchemaJSON from '../../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../../schema/bitbucket_server.schema.json'
 import gerritSchemaJSON from '../../../../../schema/gerrit.schema.json'
+import giteaSchemaJSON from '../../../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../../schema/gitolite.schema.json'
 import goModulesSchemaJSON from '../../../../../schema/go-modules.schema.json'
+import hexPackagesSchemaJSON from '../../../../../schema/hex-packages.schema.json'
 import jvmPackagesSchemaJSON from '../../../../../schema/jvm-packages.schema.json'
 import npmPackagesSchemaJSON from '../../../../../schema/npm-packages.schema.json'
+import nugetPackagesSchemaJSON from '../../../../../schema/nuget-packages.schema.json'
 import otherExternalServiceSchemaJSON from '../../../../../schema/other_external_service.schema.json'
 import pagureSchemaJSON from '../../../../../schema/pagure.schema.json'
 import perforceSchemaJSON from '../../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../../schema/phabricator.schema.json'
+import phpPackagesSchemaJSON from '../../../../../schema/php-packages.schema.json'
 import pythonPackagesJSON from '../../../../../schema/python-packages.schema.json'
 import rubyPackagesSchemaJSON from '../../../../../schema/ruby-packages.schema.json'
 import rustPackagesJSON from '../../../../../schema/rust-packages.schema.json'
@@ -1379,6 +1386,39 @@ const GERRIT: AddExternalServiceOptions = {
     status: 'beta',
 }
 
+const GITEA: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.GITEA,
+    title: 'Gitea',
+    icon: GitIcon,
+    jsonSchema: giteaSchemaJSON,
+    defaultDisplayName: 'Gitea',
+    defaultConfig: `{
+  "url": "https://gitea.example.com",
+  "token": "<access token>",
+  "orgs": []
+}`,
+    Instructions: () => (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>url</Field> to the URL of the Gitea or Forgejo instance.
+                </li>
+                <li>
+                    Create an access token with the <Code>read:repository</Code> and <Code>read:organization</Code>{' '}
+                    scopes in the user settings of Gitea, and set it as <Field>token</Field>.
+                </li>
+                <li>
+                    Optionally, use <Field>orgs</Field> and <Field>repos</Field> to limit syncing to specific
+                    organizations and repositories. If neither is set, all repositories the token can access are
+                    synced.
+                </li>
+            </ol>
+        </div>
+    ),
+    editorActions: [],
+    status: 'beta',
+}
+
 const AZUREDEVOPS: AddExternalServiceOptions = {
     kind: ExternalServiceKind.AZUREDEVOPS,
     title: 'Azure DevOps',
@@ -1583,6 +1623,105 @@ const RUBY_PACKAGES: AddExternalServiceOptions = {
     editorActions: [],
 }
 
+const NUGET_PACKAGES: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.NUGETPACKAGES,
+    title: 'NuGet Dependencies',
+    icon: LanguageCsharpIcon,
+    jsonSchema: nugetPackagesSchemaJSON,
+    defaultDisplayName: 'NuGet Dependencies',
+    defaultConfig: `{
+  "repository": "https://api.nuget.org/v3/index.json",
+  "dependencies": ["Newtonsoft.Json@13.0.3"]
+}`,
+    Instructions: () => (
+        <div>
+            <ol>
+                <li>
+                    Set <Field>repository</Field> to the URL of the NuGet V3 service index of the package feed. The URL
+                    https://api.nuget.org/v3/index.json is used if the field <Code>"repository"</Code> is empty.
+                </li>
+                <li>
+                    Use the syntax <Code>"PACKAGE_ID@PACKAGE_VERSION"</Code> to list a dependency for the{' '}
+                    <Code>"dependencies"</Code> field.
+                </li>
+                <li>
+                    The field <Code>"repository"</Code> is redacted because it can include <Code>admin:password</Code>{' '}
+                    credentials.
+                </li>
+            </ol>
+            <Text>⚠️ NuGet package repositories are visible by all users of the Sourcegraph instance.</Text>
+            <Text>⚠️ It is only possible to register one NuGet packages code host per Sourcegraph instance.</Text>
+        </div>
+    ),
+    editorActions: [],
+}
+
+const PHP_PACKAGES: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.PHPPACKAGES,
+    title: 'PHP Dependencies',
+    icon: LanguagePhpIcon,
+    jsonSchema: phpPackagesSchemaJSON,
+    defaultDisplayName: 'PHP Dependencies',
+    defaultConfig: `{
+  "repository": "https://repo.packagist.org",
+  "dependencies": ["monolog/monolog@3.5.0"]
+}`,
+    Instructions: () => (
+        <div>
+            <ol>
+                <li>
+                    Set <Field>repository</Field> to the URL of a Composer repository. The URL
+                    https://repo.packagist.org is used if the field <Code>"repository"</Code> is empty.
+                </li>
+                <li>
+                    Use the syntax <Code>"VENDOR/PACKAGE@VERSION"</Code> to list a dependency for the{' '}
+                    <Code>"dependencies"</Code> field.
+                </li>
+                <li>
+                    The field <Code>"repository"</Code> is redacted because it can include <Code>admin:password</Code>{' '}
+                    credentials.
+                </li>
+            </ol>
+            <Text>⚠️ PHP package repositories are visible by all users of the Sourcegraph instance.</Text>
+            <Text>⚠️ It is only possible to register one PHP packages code host per Sourcegraph instance.</Text>
+        </div>
+    ),
+    editorActions: [],
+}
+
+const HEX_PACKAGES: AddExternalServiceOptions = {
+    kind: ExternalServiceKind.HEXPACKAGES,
+    title: 'Hex Dependencies',
+    icon: HexagonOutlineIcon,
+    jsonSchema: hexPackagesSchemaJSON,
+    defaultDisplayName: 'Hex Dependencies',
+    defaultConfig: `{
+  "repository": "https://repo.hex.pm",
+  "dependencies": ["phoenix@1.7.10"]
+}`,
+    Instructions: () => (
+        <div>
+            <ol>
+                <li>
+                    Set <Field>repository</Field> to the URL of a Hex repository or mirror. The URL https://repo.hex.pm
+                    is used if the field <Code>"repository"</Code> is empty.
+                </li>
+                <li>
+                    Use the syntax <Code>"PACKAGE_NAME@PACKAGE_VERSION"</Code> to list a dependency for the{' '}
+                    <Code>"dependencies"</Code> field.
+                </li>
+                <li>
+                    The field <Code>"repository"</Code> is redacted because it can include <Code>admin:password</Code>{' '}
+                    credentials.
+                </li>
+            </ol>
+            <Text>⚠️ Hex package repositories are visible by all users of the Sourcegraph instance.</Text>
+            <Text>⚠️ It is only possible to register one Hex packages code host per Sourcegraph instance.</Text>
+        </div>
+    ),
+    editorActions: [],
+}
+
 export const codeHostExternalServices: Record<string, AddExternalServiceOptions> = {
     github: GITHUB,
     ghapp: GITHUB_APP,
@@ -1594,6 +1733,7 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     gitolite: GITOLITE,
     git: GENERIC_GIT,
     gerrit: GERRIT,
+    gitea: GITEA,
     azuredevops: AZUREDEVOPS,
     phabricator: PHABRICATOR_SERVICE,
     ...(window.context?.experimentalFeatures?.perforce !== 'disabled' ? { perforce: PERFORCE } : {}),
@@ -1604,6 +1744,9 @@ export const nonCodeHostExternalServices: Record<string, AddExternalServiceOptio
     ...(window.context?.experimentalFeatures?.pythonPackages === 'enabled' ? { pythonPackages: PYTHON_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.rustPackages === 'enabled' ? { rustPackages: RUST_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.rubyPackages === 'enabled' ? { rubyPackages: RUBY_PACKAGES } : {}),
+    ...(window.context?.experimentalFeatures?.nugetPackages === 'enabled' ? { nugetPackages: NUGET_PACKAGES } : {}),
+    ...(window.context?.experimentalFeatures?.phpPackages === 'enabled' ? { phpPackages: PHP_PACKAGES } : {}),
+    ...(window.context?.experimentalFeatures?.hexPackages === 'enabled' ? { hexPackages: HEX_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.goPackages === 'enabled' ? { goModules: GO_MODULES } : {}),
     ...(window.context?.experimentalFeatures?.jvmPackages === 'enabled' ? { jvmPackages: JVM_PACKAGES } : {}),
     ...(window.context?.experimentalFeatures?.npmPackages === 'enabled' ? { npmPackages: NPM_PACKAGES } : {}),
@@ -1626,6 +1769,7 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
     [ExternalServiceKind.PERFORCE]: PERFORCE,
     [ExternalServiceKind.GERRIT]: GERRIT,
+    [ExternalServiceKind.GITEA]: GITEA,
     [ExternalServiceKind.PAGURE]: PAGURE,
     [ExternalServiceKind.GOMODULES]: GO_MODULES,
     [ExternalServiceKind.JVMPACKAGES]: JVM_PACKAGES,
@@ -1633,6 +1777,9 @@ export const defaultExternalServices: Record<ExternalServiceKind, AddExternalSer
     [ExternalServiceKind.PYTHONPACKAGES]: PYTHON_PACKAGES,
     [ExternalServiceKind.RUSTPACKAGES]: RUST_PACKAGES,
     [ExternalServiceKind.RUBYPACKAGES]: RUBY_PACKAGES,
+    [ExternalServiceKind.NUGETPACKAGES]: NUGET_PACKAGES,
+    [ExternalServiceKind.PHPPACKAGES]: PHP_PACKAGES,
+    [ExternalServiceKind.HEXPACKAGES]: HEX_PACKAGES,
 }
 
 export const externalRepoIcon = (
@@ -60,6 +60,11 @@ const scopeRequirements: Record<ExternalServiceKind, JSX.Element> = {
         </span>
     ),
     [ExternalServiceKind.GERRIT]: <span />,
+    [ExternalServiceKind.GITEA]: (
+        <span>
+            with <Code>read:user</Code>, <Code>read:organization</Code> and <Code>write:repository</Code> scopes.
+        </span>
+    ),
     [ExternalServiceKind.PERFORCE]: <span>with the ability to shelve changelists.</span>,
     // These are just for type completeness and serve as placeholders for a bright future.
     [ExternalServiceKind.GITOLITE]: <span>Unsupported</span>,
@@ -67,6 +72,9 @@ const scopeRequirements: Record<ExternalServiceKind, JSX.Element> = {
     [ExternalServiceKind.PYTHONPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.RUSTPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.RUBYPACKAGES]: <span>Unsupported</span>,
+    [ExternalServiceKind.NUGETPACKAGES]: <span>Unsupported</span>,
+    [ExternalServiceKind.PHPPACKAGES]: <span>Unsupported</span>,
+    [ExternalServiceKind.HEXPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.JVMPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.NPMPACKAGES]: <span>Unsupported</span>,
     [ExternalServiceKind.PHABRICATOR]: <span>Unsupported</span>,
@@ -18,6 +18,7 @@ const configInstructionLinks: Record<ExternalServiceKind, string> = {
     [ExternalServiceKind.AZUREDEVOPS]: 'unsupported',
     [ExternalServiceKind.BITBUCKETCLOUD]: 'unsupported',
     [ExternalServiceKind.GERRIT]: 'unsupported',
+    [ExternalServiceKind.GITEA]: 'unsupported',
     [ExternalServiceKind.GITOLITE]: 'unsupported',
     [ExternalServiceKind.GOMODULES]: 'unsupported',
     [ExternalServiceKind.JVMPACKAGES]: 'unsupported',
@@ -29,6 +30,9 @@ const configInstructionLinks: Record<ExternalServiceKind, string> = {
     [ExternalServiceKind.PYTHONPACKAGES]: 'unsupported',
     [ExternalServiceKind.RUSTPACKAGES]: 'unsupported',
     [ExternalServiceKind.RUBYPACKAGES]: 'unsupported',
+    [ExternalServiceKind.NUGETPACKAGES]: 'unsupported',
+    [ExternalServiceKind.PHPPACKAGES]: 'unsupported',
+    [ExternalServiceKind.HEXPACKAGES]: 'unsupported',
 }
 
 export interface CodeHostSshPublicKeyProps {
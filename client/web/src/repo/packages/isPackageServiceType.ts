@@ -12,6 +12,9 @@ export function isPackageServiceType(serviceType?: string): boolean {
         case 'npmPackages':
         case 'pythonPackages':
         case 'rubyPackages':
+        case 'nugetPackages':
+        case 'phpPackages':
+        case 'hexPackages':
         case 'goModules':
         case 'rustPackages': {
             return true
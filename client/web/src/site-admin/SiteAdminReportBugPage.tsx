@@ -12,16 +12,20 @@ import azureDevOpsJSON from '../../../../schema/azuredevops.schema.json'
 import bitbucketCloudSchemaJSON from '../../../../schema/bitbucket_cloud.schema.json'
 import bitbucketServerSchemaJSON from '../../../../schema/bitbucket_server.schema.json'
 import gerritSchemaJSON from '../../../../schema/gerrit.schema.json'
+import giteaSchemaJSON from '../../../../schema/gitea.schema.json'
 import githubSchemaJSON from '../../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../../schema/gitolite.schema.json'
 import goModulesSchemaJSON from '../../../../schema/go-modules.schema.json'
 import jvmPackagesSchemaJSON from '../../../../schema/jvm-packages.schema.json'
+import hexPackagesSchemaJSON from '../../../../schema/hex-packages.schema.json'
 import npmPackagesSchemaJSON from '../../../../schema/npm-packages.schema.json'
+import nugetPackagesSchemaJSON from '../../../../schema/nuget-packages.schema.json'
 import otherExternalServiceSchemaJSON from '../../../../schema/other_external_service.schema.json'
 import pagureSchemaJSON from '../../../../schema/pagure.schema.json'
 import perforceSchemaJSON from '../../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../../schema/phabricator.schema.json'
+import phpPackagesSchemaJSON from '../../../../schema/php-packages.schema.json'
 import pythonPackagesSchemaJSON from '../../../../schema/python-packages.schema.json'
 import rubyPackagesSchemaJSON from '../../../../schema/ruby-packages.schema.json'
 import rustPackagesSchemaJSON from '../../../../schema/rust-packages.schema.json'
@@ -47,6 +51,7 @@ const externalServices: Record<ExternalServiceKind, JSONSchema> = {
     BITBUCKETCLOUD: bitbucketCloudSchemaJSON,
     BITBUCKETSERVER: bitbucketServerSchemaJSON,
     GERRIT: gerritSchemaJSON,
+    GITEA: giteaSchemaJSON,
     GITHUB: githubSchemaJSON,
     GITLAB: gitlabSchemaJSON,
     GITOLITE: gitoliteSchemaJSON,
@@ -56,6 +61,9 @@ const externalServices: Record<ExternalServiceKind, JSONSchema> = {
     PYTHONPACKAGES: pythonPackagesSchemaJSON,
     RUSTPACKAGES: rustPackagesSchemaJSON,
     RUBYPACKAGES: rubyPackagesSchemaJSON,
+    NUGETPACKAGES: nugetPackagesSchemaJSON,
+    PHPPACKAGES: phpPackagesSchemaJSON,
+    HEXPACKAGES: hexPackagesSchemaJSON,
     OTHER: otherExternalServiceSchemaJSON,
     PERFORCE: perforceSchemaJSON,
     PHABRICATOR: phabricatorSchemaJSON,
@@ -3,5 +3,8 @@ export const isPackagesEnabled = (): boolean =>
     window.context?.experimentalFeatures?.goPackages === 'enabled' ||
     window.context?.experimentalFeatures?.jvmPackages === 'enabled' ||
     window.context?.experimentalFeatures?.rubyPackages === 'enabled' ||
+    window.context?.experimentalFeatures?.nugetPackages === 'enabled' ||
+    window.context?.experimentalFeatures?.phpPackages === 'enabled' ||
+    window.context?.experimentalFeatures?.hexPackages === 'enabled' ||
     window.context?.experimentalFeatures?.pythonPackages === 'enabled' ||
     window.context?.experimentalFeatures?.rustPackages === 'enabled'
@@ -50,6 +50,9 @@ type GitTreeEntryResolver struct {
 	// stat is this tree entry's file info. Its Name method must return the full path relative to
 	// the root, not the basename.
 	stat fs.FileInfo
+
+	submoduleOnce sync.Once
+	submodule     *gitSubmoduleResolver
 }
 
 type GitTreeEntryResolverOpts struct {
@@ -313,11 +316,7 @@ func (r *GitTreeEntryResolver) url(ctx context.Context) *url.URL {
 
 	tr, ctx := trace.New(ctx, "GitTreeEntryResolver.url", attribute.Bool("submodule", true))
 	defer tr.End()
-	submoduleURL := submodule.URL()
-	if strings.HasPrefix(submoduleURL, "../") {
-		submoduleURL = path.Join(r.Repository().Name(), submoduleURL)
-	}
-	repoName, err := cloneURLToRepoName(ctx, r.db, submoduleURL)
+	repoName, err := submodule.resolveTargetName(ctx)
 	if err != nil {
 		log15.Error("Failed to resolve submodule repository name from clone URL", "cloneURL", submodule.URL(), "err", err)
 		return &url.URL{}
@@ -396,10 +395,17 @@ func (r *GitTreeEntryResolver) RawZipArchiveURL() string {
 }
 
 func (r *GitTreeEntryResolver) Submodule() *gitSubmoduleResolver {
-	if submoduleInfo, ok := r.stat.Sys().(gitdomain.Submodule); ok {
-		return &gitSubmoduleResolver{submodule: submoduleInfo}
-	}
-	return nil
+	r.submoduleOnce.Do(func() {
+		if submoduleInfo, ok := r.stat.Sys().(gitdomain.Submodule); ok {
+			r.submodule = &gitSubmoduleResolver{
+				db:              r.db,
+				gitserverClient: r.gitserverClient,
+				repoName:        r.commit.repoResolver.RepoName(),
+				submodule:       submoduleInfo,
+			}
+		}
+	})
+	return r.submodule
 }
 
 func cloneURLToRepoName(ctx context.Context, db database.DB, cloneURL string) (_ string, err error) {
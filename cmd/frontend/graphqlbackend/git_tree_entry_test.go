@@ -6,10 +6,14 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
@@ -30,6 +34,93 @@ func TestGitTreeEntry_RawZipArchiveURL(t *testing.T) {
 	}
 }
 
+func TestGitTreeEntry_Submodule(t *testing.T) {
+	ctx := context.Background()
+
+	repos := dbmocks.NewMockRepoStore()
+	repos.GetFirstRepoNameByCloneURLFunc.SetDefaultHook(func(_ context.Context, cloneURL string) (api.RepoName, error) {
+		if cloneURL == "https://github.com/foo/lib.git" {
+			return "github.com/foo/lib", nil
+		}
+		return "", nil
+	})
+	repos.GetByNameFunc.SetDefaultHook(func(_ context.Context, name api.RepoName) (*types.Repo, error) {
+		if name == "github.com/foo/lib" {
+			return &types.Repo{ID: 2, Name: name}, nil
+		}
+		return nil, &database.RepoNotFoundErr{Name: name}
+	})
+	repos.GetFunc.SetDefaultReturn(&types.Repo{ID: 2, Name: "github.com/foo/lib"}, nil)
+	externalServices := dbmocks.NewMockExternalServiceStore()
+	externalServices.ListFunc.SetDefaultReturn(nil, nil)
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+	db.ExternalServicesFunc.SetDefaultReturn(externalServices)
+
+	gitserverClient := gitserver.NewMockClient()
+	gitserverClient.ResolveRevisionFunc.SetDefaultHook(func(_ context.Context, repo api.RepoName, spec string, _ gitserver.ResolveRevisionOptions) (api.CommitID, error) {
+		return api.CommitID(spec), nil
+	})
+
+	newEntry := func(submoduleURL string, submoduleRepo api.RepoName) *GitTreeEntryResolver {
+		return NewGitTreeEntryResolver(db, gitserverClient, GitTreeEntryResolverOpts{
+			Commit: &GitCommitResolver{
+				repoResolver: NewRepositoryResolver(db, gitserverClient, &types.Repo{ID: 1, Name: "github.com/foo/app"}),
+			},
+			Stat: &fileutil.FileInfo{
+				Name_: "vendor/lib",
+				Mode_: gitdomain.ModeSubmodule,
+				Sys_: gitdomain.Submodule{
+					URL:      submoduleURL,
+					Path:     "vendor/lib",
+					CommitID: "deadbeef",
+					Repo:     submoduleRepo,
+				},
+			},
+		})
+	}
+
+	t.Run("known repository", func(t *testing.T) {
+		submodule := newEntry("https://github.com/foo/lib.git", "").Submodule()
+		repo, err := submodule.Repository(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+		assert.Equal(t, "github.com/foo/lib", repo.Name())
+
+		target, err := submodule.Target(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, target)
+		assert.Equal(t, GitObjectID("deadbeef"), target.OID())
+	})
+
+	t.Run("repository resolved by gitserver", func(t *testing.T) {
+		submodule := newEntry("git@mirror.example.com:foo/lib.git", "github.com/foo/lib").Submodule()
+		repo, err := submodule.Repository(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, repo)
+		assert.Equal(t, "github.com/foo/lib", repo.Name())
+	})
+
+	t.Run("unknown repository", func(t *testing.T) {
+		submodule := newEntry("https://git.example.com/lib.git", "").Submodule()
+		repo, err := submodule.Repository(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, repo)
+
+		target, err := submodule.Target(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("not a submodule", func(t *testing.T) {
+		entry := NewGitTreeEntryResolver(db, gitserverClient, GitTreeEntryResolverOpts{
+			Commit: &GitCommitResolver{},
+			Stat:   CreateFileInfo("README.md", false),
+		})
+		assert.Nil(t, entry.Submodule())
+	})
+}
+
 func TestGitTreeEntry_Content(t *testing.T) {
 	wantPath := "foobar.md"
 	wantContent := "foobar"
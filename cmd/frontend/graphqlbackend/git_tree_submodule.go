@@ -1,11 +1,33 @@
 package graphqlbackend
 
 import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 type gitSubmoduleResolver struct {
+	db              database.DB
+	gitserverClient gitserver.Client
+	// repoName is the name of the repository that contains the submodule.
+	repoName  api.RepoName
 	submodule gitdomain.Submodule
+
+	targetNameOnce sync.Once
+	targetName     string
+	targetNameErr  error
+
+	targetRepoOnce sync.Once
+	targetRepo     *types.Repo
+	targetRepoErr  error
 }
 
 func (r *gitSubmoduleResolver) URL() string {
@@ -19,3 +41,61 @@ func (r *gitSubmoduleResolver) Commit() string {
 func (r *gitSubmoduleResolver) Path() string {
 	return r.submodule.Path
 }
+
+// Repository returns the repository the submodule points to, or nil if that
+// repository isn't known to this instance or not visible to the current user.
+func (r *gitSubmoduleResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := r.resolveTargetRepo(ctx)
+	if err != nil || repo == nil {
+		return nil, err
+	}
+	return NewRepositoryResolver(r.db, r.gitserverClient, repo), nil
+}
+
+// Target returns the commit the submodule points to, or nil if its repository
+// isn't known to this instance or doesn't contain the commit.
+func (r *gitSubmoduleResolver) Target(ctx context.Context) (*GitCommitResolver, error) {
+	repo, err := r.Repository(ctx)
+	if err != nil || repo == nil {
+		return nil, err
+	}
+	return repo.Commit(ctx, &RepositoryCommitArgs{Rev: r.Commit()})
+}
+
+// resolveTargetName returns the name the clone URL of the submodule maps to
+// according to the configured code hosts. The repository doesn't need to exist.
+func (r *gitSubmoduleResolver) resolveTargetName(ctx context.Context) (string, error) {
+	r.targetNameOnce.Do(func() {
+		if r.submodule.Repo != "" {
+			// gitserver already resolved the repository.
+			r.targetName = string(r.submodule.Repo)
+			return
+		}
+		cloneURL := r.submodule.URL
+		if strings.HasPrefix(cloneURL, "../") {
+			cloneURL = path.Join(string(r.repoName), cloneURL)
+		}
+		r.targetName, r.targetNameErr = cloneURLToRepoName(ctx, r.db, cloneURL)
+	})
+	return r.targetName, r.targetNameErr
+}
+
+func (r *gitSubmoduleResolver) resolveTargetRepo(ctx context.Context) (*types.Repo, error) {
+	r.targetRepoOnce.Do(func() {
+		name, err := r.resolveTargetName(ctx)
+		if err != nil {
+			// The submodule points to a code host that isn't configured, so
+			// its repository can't be known either.
+			return
+		}
+		// 🚨 SECURITY: The repos store only returns repositories the current
+		// user has access to.
+		repo, err := r.db.Repos().GetByName(ctx, api.RepoName(name))
+		if err != nil && !errcode.IsNotFound(err) {
+			r.targetRepoErr = err
+			return
+		}
+		r.targetRepo = repo
+	})
+	return r.targetRepo, r.targetRepoErr
+}
@@ -22,40 +22,33 @@ import (
 )
 
 func TestGitTree_History(t *testing.T) {
-	gitserver.ClientMocks.LocalGitserver = true
-	defer gitserver.ResetClientMocks()
-
-	commands := []string{
-		// |- file1    (added)
-		// `- dir1     (added)
-		//    `- file2 (added)
-		"echo -n infile1 > file1",
-		"touch --date=2006-01-02T15:04:05Z file1 || touch -t 200601021704.05 file1",
-		"mkdir dir1",
-		"echo -n infile2 > dir1/file2",
-		"touch --date=2006-01-02T15:04:05Z dir1/file2 || touch -t 200601021704.05 dir1/file2",
-		"git add file1 dir1/file2",
-		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_AUTHOR_DATE=2006-01-02T15:04:05Z GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit1 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
-
-		// |- file1     (modified)
-		// `- dir1      (modified)
-		//    |- file2  (unchanged)
-		//    `- file3  (added)
-		"echo -n infile3 > dir1/file3",
-		"touch --date=2006-01-02T15:04:05Z dir1/file3 || touch -t 200601021704.05 dir1/file3",
-		"git add dir1/file2 dir1/file3",
-		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_AUTHOR_DATE=2006-01-02T15:04:05Z GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit2 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
-	}
-	repoName := gitserver.MakeGitRepository(t, commands...)
-
 	ctx := context.Background()
-	gs := gitserver.NewTestClient(t)
 	db := dbmocks.NewMockDB()
+	gs := gitserver.NewMockClient()
+
+	// |- file1     (added in commit1)
+	// `- dir1      (added in commit1)
+	//    |- file2  (added in commit1)
+	//    `- file3  (added in commit2)
+	oid := api.CommitID("b6602ca96bdc0ab647278577a3c6edcb8fe18fb0")
+	commit1 := &gitdomain.Commit{ID: "d38233a79e037d2ab8170b0d0bc0aa438473e6da", Message: "commit1"}
+	commit2 := &gitdomain.Commit{ID: oid, Message: "commit2", Parents: []api.CommitID{commit1.ID}}
+	gs.ReadDirFunc.SetDefaultReturn([]fs.FileInfo{
+		CreateFileInfo("file1", false),
+		CreateFileInfo("dir1", true),
+	}, nil)
+	gs.CommitsFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, opt gitserver.CommitsOptions) ([]*gitdomain.Commit, error) {
+		switch opt.Path {
+		case "file1":
+			return []*gitdomain.Commit{commit1}, nil
+		case "dir1":
+			return []*gitdomain.Commit{commit2, commit1}, nil
+		default:
+			return nil, errors.Newf("unexpected path %q", opt.Path)
+		}
+	})
 
-	oid, err := gs.ResolveRevision(ctx, repoName, "HEAD", gitserver.ResolveRevisionOptions{})
-	require.NoError(t, err)
-
-	rr := NewRepositoryResolver(db, gs, &types.Repo{Name: repoName})
+	rr := NewRepositoryResolver(db, gs, &types.Repo{Name: "repo"})
 	gcr := NewGitCommitResolver(db, gs, rr, oid, nil)
 
 	tree, err := gcr.Tree(ctx, &TreeArgs{Path: ""})
@@ -33,6 +33,9 @@ var externalServiceToPackageSchemeMap = map[string]string{
 	extsvc.KindPythonPackages: dependencies.PythonPackagesScheme,
 	extsvc.KindRustPackages:   dependencies.RustPackagesScheme,
 	extsvc.KindRubyPackages:   dependencies.RubyPackagesScheme,
+	extsvc.KindNuGetPackages:  dependencies.NuGetPackagesScheme,
+	extsvc.KindPHPPackages:    dependencies.PHPPackagesScheme,
+	extsvc.KindHexPackages:    dependencies.HexPackagesScheme,
 }
 
 var packageSchemeToExternalServiceMap = map[string]string{
@@ -42,6 +45,9 @@ var packageSchemeToExternalServiceMap = map[string]string{
 	dependencies.PythonPackagesScheme: extsvc.KindPythonPackages,
 	dependencies.RustPackagesScheme:   extsvc.KindRustPackages,
 	dependencies.RubyPackagesScheme:   extsvc.KindRubyPackages,
+	dependencies.NuGetPackagesScheme:  extsvc.KindNuGetPackages,
+	dependencies.PHPPackagesScheme:    extsvc.KindPHPPackages,
+	dependencies.HexPackagesScheme:    extsvc.KindHexPackages,
 }
 
 func (r *schemaResolver) PackageRepoReferences(ctx context.Context, args *PackageRepoReferenceConnectionArgs) (_ *packageRepoReferenceConnectionResolver, err error) {
@@ -252,6 +258,16 @@ func dependencyRepoToRepoName(dep dependencies.PackageRepoReference) (repoName a
 			return "", err
 		}
 		repoName = pkg.RepoName()
+	case "scip-dotnet":
+		repoName = reposource.ParseNuGetPackageFromName(dep.Name).RepoName()
+	case "scip-php":
+		pkg, err := reposource.ParsePHPPackageFromName(dep.Name)
+		if err != nil {
+			return "", err
+		}
+		repoName = pkg.RepoName()
+	case "hex":
+		repoName = reposource.ParseHexPackageFromName(dep.Name).RepoName()
 	}
 
 	return repoName, nil
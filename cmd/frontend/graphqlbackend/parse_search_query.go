@@ -91,17 +91,20 @@ func outputJobTree(
 	db database.DB,
 	logger log.Logger,
 ) (string, error) {
-	plan, err := query.Pipeline(query.Init(args.Query, searchType))
+	settings, err := settings.CurrentUserFinal(ctx, db)
 	if err != nil {
 		return "", err
 	}
 
-	settings, err := settings.CurrentUserFinal(ctx, db)
+	plan, err := query.Pipeline(query.InitWithMacros(args.Query, searchType, settings.SearchMacros))
 	if err != nil {
 		return "", err
 	}
 
 	inputs := &search.Inputs{
+		Plan:                plan,
+		Query:               plan.ToQ(),
+		OriginalQuery:       args.Query,
 		UserSettings:        settings,
 		PatternType:         searchType,
 		Protocol:            search.Streaming,
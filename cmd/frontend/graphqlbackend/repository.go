@@ -304,6 +304,31 @@ func (r *RepositoryResolver) Changelist(ctx context.Context, args *RepositoryCha
 	), nil
 }
 
+type RepositoryMercurialChangesetArgs struct {
+	ID string
+}
+
+func (r *RepositoryResolver) MercurialChangeset(ctx context.Context, args *RepositoryMercurialChangesetArgs) (_ *GitCommitResolver, err error) {
+	tr, ctx := trace.New(ctx, "RepositoryResolver.MercurialChangeset",
+		attribute.String("changeset", args.ID))
+	defer tr.EndWithErr(&err)
+
+	repo, err := r.repo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	commitID, err := r.gitserverClient.HgChangesetCommit(ctx, repo.Name, args.ID)
+	if err != nil {
+		if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return r.CommitFromID(ctx, &RepositoryCommitArgs{Rev: string(commitID)}, commitID)
+}
+
 func (r *RepositoryResolver) FirstEverCommit(ctx context.Context) (_ *GitCommitResolver, err error) {
 	tr, ctx := trace.New(ctx, "RepositoryResolver.FirstEverCommit")
 	defer tr.EndWithErr(&err)
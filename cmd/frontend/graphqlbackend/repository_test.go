@@ -120,6 +120,52 @@ func TestRepository_Changelist(t *testing.T) {
 	})
 }
 
+func TestRepository_MercurialChangeset(t *testing.T) {
+	repo := &types.Repo{ID: 2, Name: "hg.example.com/repo"}
+
+	repos := dbmocks.NewMockRepoStore()
+	repos.GetFunc.SetDefaultReturn(repo, nil)
+	repos.GetByNameFunc.SetDefaultReturn(repo, nil)
+
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	gitserverClient := gitserver.NewMockClient()
+	gitserverClient.HgChangesetCommitFunc.SetDefaultHook(func(_ context.Context, repoName api.RepoName, changeset string) (api.CommitID, error) {
+		assert.Equal(t, repo.Name, repoName)
+		if changeset == "0123456789ab" {
+			return exampleCommitSHA1, nil
+		}
+		return "", &gitdomain.RevisionNotFoundError{Repo: repoName, Spec: changeset}
+	})
+
+	RunTest(t, &Test{
+		Schema: mustParseGraphQLSchemaWithClient(t, db, gitserverClient),
+		Query: `
+				{
+					repository(name: "hg.example.com/repo") {
+						found: mercurialChangeset(id: "0123456789ab") {
+							oid
+						}
+						missing: mercurialChangeset(id: "ba9876543210") {
+							oid
+						}
+					}
+				}
+			`,
+		ExpectedResult: fmt.Sprintf(`
+				{
+					"repository": {
+						"found": {
+							"oid": "%s"
+						},
+						"missing": null
+					}
+				}
+			`, exampleCommitSHA1),
+	})
+}
+
 func TestRepositoryHydration(t *testing.T) {
 	t.Parallel()
 
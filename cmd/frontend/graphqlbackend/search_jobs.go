@@ -13,6 +13,8 @@ type SearchJobsResolver interface {
 	// Mutations
 	CreateSearchJob(ctx context.Context, args *CreateSearchJobArgs) (SearchJobResolver, error)
 	CancelSearchJob(ctx context.Context, args *CancelSearchJobArgs) (*EmptyResponse, error)
+	RetrySearchJob(ctx context.Context, args *RetrySearchJobArgs) (SearchJobResolver, error)
+	SetSearchJobSchedule(ctx context.Context, args *SetSearchJobScheduleArgs) (SearchJobResolver, error)
 	DeleteSearchJob(ctx context.Context, args *DeleteSearchJobArgs) (*EmptyResponse, error)
 
 	// Queries
@@ -33,12 +35,15 @@ type ValidateSearchJobQueryResolver interface {
 }
 
 type CreateSearchJobArgs struct {
-	Query string
+	Query    string
+	Format   string
+	Schedule *string
 }
 
 type SearchJobResolver interface {
 	ID() graphql.ID
 	Query() string
+	Format() string
 	State(ctx context.Context) string
 	Creator(ctx context.Context) (*UserResolver, error)
 	CreatedAt() gqlutil.DateTime
@@ -47,6 +52,16 @@ type SearchJobResolver interface {
 	URL(ctx context.Context) (*string, error)
 	LogURL(ctx context.Context) (*string, error)
 	RepoStats(ctx context.Context) (SearchJobStatsResolver, error)
+	Schedule() *string
+	NextRunAt() *gqlutil.DateTime
+	ResultsDiff() SearchJobResultsDiffResolver
+}
+
+type SearchJobResultsDiffResolver interface {
+	Added() int32
+	Removed() int32
+	ComputedAt() gqlutil.DateTime
+	URL() (string, error)
 }
 
 type SearchJobStatsResolver interface {
@@ -75,7 +90,13 @@ type DeleteSearchJobArgs struct {
 }
 
 type RetrySearchJobArgs struct {
-	ID graphql.ID
+	ID   graphql.ID
+	Mode string
+}
+
+type SetSearchJobScheduleArgs struct {
+	ID       graphql.ID
+	Schedule *string
 }
 
 type SearchJobArgs struct {
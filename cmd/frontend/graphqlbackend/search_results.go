@@ -157,6 +157,23 @@ func matchesToResolvers(db database.DB, matches []result.Match) []SearchResultRe
 			})
 		case *result.OwnerMatch:
 			// todo(own): add OwnerSearchResultResolver
+		case *result.CompareMatch:
+			// Revision compares are only returned by the streaming API.
+		case *result.DirectoryMatch:
+			// Directories are returned as files with the directory as path.
+			resolvers = append(resolvers, &FileMatchResolver{
+				db: db,
+				FileMatch: result.FileMatch{
+					File: result.File{
+						InputRev: v.InputRev,
+						Repo:     v.Repo,
+						CommitID: v.CommitID,
+						Path:     v.Path,
+					},
+					LimitHit: v.LimitHit,
+				},
+				RepoResolver: getRepoResolver(v.Repo),
+			})
 		}
 	}
 	return resolvers
@@ -297,8 +314,8 @@ loop:
 	for _, r := range sr.Matches {
 		r := r // shadow so it doesn't change in the goroutine
 		switch m := r.(type) {
-		case *result.RepoMatch, *result.OwnerMatch:
-			// We don't care about repo or owner results here.
+		case *result.RepoMatch, *result.OwnerMatch, *result.DirectoryMatch, *result.CompareMatch:
+			// We don't care about repo, owner, directory or compare results here.
 			continue
 		case *result.CommitMatch:
 			// Diff searches are cheap, because we implicitly have author date info.
@@ -283,6 +283,7 @@ func makeExternalAPI(db database.DB, logger sglog.Logger, schema *graphql.Schema
 			CodeInsightsDataExportHandler:   enterprise.CodeInsightsDataExportHandler,
 			SearchJobsDataExportHandler:     enterprise.SearchJobsDataExportHandler,
 			SearchJobsLogsHandler:           enterprise.SearchJobsLogsHandler,
+			SearchJobsDiffExportHandler:     enterprise.SearchJobsDiffExportHandler,
 			NewDotcomLicenseCheckHandler:    enterprise.NewDotcomLicenseCheckHandler,
 			NewChatCompletionsStreamHandler: enterprise.NewChatCompletionsStreamHandler,
 			NewCodeCompletionsHandler:       enterprise.NewCodeCompletionsHandler,
@@ -3,6 +3,8 @@ package resolvers
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/inconshreveable/log15" //nolint:logging // TODO move all logging to sourcegraph/log
 	"github.com/sourcegraph/log"
@@ -167,6 +169,9 @@ func toComputeResultResolver(result compute.Result, repoResolver *gql.Repository
 		return &computeResultResolver{result: toComputeMatchContextResolver(r, repoResolver, path, commit)}
 	case *compute.Text:
 		return &computeResultResolver{result: toComputeTextResolver(r, repoResolver, path, commit)}
+	case *compute.FileDiff:
+		// The GraphQL API has no diff result, so diffs are returned as text.
+		return &computeResultResolver{result: toComputeTextResolver(&compute.Text{Value: r.Value, Kind: r.Kind}, repoResolver, path, commit)}
 	default:
 		panic(fmt.Sprintf("unsupported compute result %T", r))
 	}
@@ -180,6 +185,10 @@ func pathAndCommitFromResult(m result.Match) (string, string) {
 		return "", string(v.Commit.ID)
 	case *result.RepoMatch:
 		return "", v.Rev
+	case *result.DirectoryMatch:
+		return v.Path, string(v.CommitID)
+	case *result.CompareMatch:
+		return v.Path, string(v.HeadCommit)
 	}
 	return "", ""
 }
@@ -197,6 +206,10 @@ func toResultResolverList(ctx context.Context, cmd compute.Command, matches []re
 		return resolver
 	}
 
+	if _, ok := cmd.(*compute.Aggregate); ok {
+		return aggregateResolverList(ctx, gitserverClient, cmd, matches)
+	}
+
 	results := make([]gql.ComputeResultResolver, 0, len(matches))
 	for _, m := range matches {
 		computeResult, err := cmd.Run(ctx, gitserverClient, m)
@@ -217,6 +230,27 @@ func toResultResolverList(ctx context.Context, cmd compute.Command, matches []re
 	return results, nil
 }
 
+// aggregateResolverList returns the totals of an aggregate command over all
+// matches as a single text result, with a line of the group and its count for
+// each group.
+func aggregateResolverList(ctx context.Context, gitserverClient gitserver.Client, cmd compute.Command, matches []result.Match) ([]gql.ComputeResultResolver, error) {
+	aggregator := compute.NewAggregator()
+	for _, m := range matches {
+		computeResult, err := cmd.Run(ctx, gitserverClient, m)
+		if err != nil {
+			return nil, err
+		}
+		aggregator.Add(computeResult)
+	}
+
+	var b strings.Builder
+	for _, g := range aggregator.Totals(math.MaxInt).Groups {
+		fmt.Fprintf(&b, "%s\t%d\n", g.Value, g.Count)
+	}
+	text := &compute.Text{Value: b.String(), Kind: "aggregate"}
+	return []gql.ComputeResultResolver{&computeResultResolver{result: toComputeTextResolver(text, nil, "", "")}}, nil
+}
+
 // NewBatchComputeImplementer is a function that abstracts away the need to have a
 // handle on (*schemaResolver) Compute.
 func NewBatchComputeImplementer(ctx context.Context, logger log.Logger, db database.DB, args *gql.ComputeArgs) ([]gql.ComputeResultResolver, error) {
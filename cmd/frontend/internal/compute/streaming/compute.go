@@ -23,14 +23,18 @@ func toComputeResult(ctx context.Context, gitserverClient gitserver.Client, cmd
 			if err != nil {
 				return nil, err
 			}
-			out = append(out, runResult)
+			if runResult != nil {
+				out = append(out, runResult)
+			}
 		}
 	} else {
 		runResult, err := cmd.Run(ctx, gitserverClient, match)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, runResult)
+		if runResult != nil {
+			out = append(out, runResult)
+		}
 	}
 	return out, nil
 }
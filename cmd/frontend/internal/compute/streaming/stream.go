@@ -2,6 +2,8 @@ package streaming
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -13,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/compute"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	streamclient "github.com/sourcegraph/sourcegraph/internal/search/streaming/client"
 	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -24,6 +27,10 @@ import (
 // and this is best avoided on large instances like Sourcegraph.com
 const maxRequestDuration = time.Minute
 
+// maxAggregateGroups is the number of groups with the highest counts that
+// aggregate commands send. The counts of other groups are summed.
+const maxAggregateGroups = 500
+
 // NewComputeStreamHandler is an http handler which streams back compute results.
 func NewComputeStreamHandler(logger log.Logger, db database.DB) http.Handler {
 	return &streamHandler{
@@ -55,19 +62,24 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	tr, ctx := trace.New(ctx, "compute.ServeStream", attribute.String("query", args.Query))
 	defer tr.EndWithErr(&err)
 
-	eventWriter, err := streamhttp.NewWriter(w)
+	computeQuery, err := compute.Parse(args.Query)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	computeQuery, err := compute.Parse(args.Query)
+	searchQuery, err := computeQuery.ToSearchQuery()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	searchQuery, err := computeQuery.ToSearchQuery()
+	if args.Format != formatStream {
+		err = h.serveDownload(ctx, w, args, searchQuery, computeQuery.Command)
+		return
+	}
+
+	eventWriter, err := streamhttp.NewWriter(w)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -99,7 +111,18 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	matchesBuf := streamhttp.NewJSONArrayBuf(32*1024, func(data []byte) error {
 		return eventWriter.EventBytes("results", data)
 	})
+
+	// Aggregate commands send the running totals of all groups instead of a
+	// result per match.
+	var aggregator *compute.Aggregator
+	if _, ok := computeQuery.Command.(*compute.Aggregate); ok {
+		aggregator = compute.NewAggregator()
+	}
+
 	matchesFlush := func() {
+		if aggregator != nil && aggregator.Dirty() {
+			_ = matchesBuf.Append(aggregator.Totals(maxAggregateGroups))
+		}
 		if err := matchesBuf.Flush(); err != nil {
 			// EOF
 			return
@@ -121,11 +144,15 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		progress.Stats.Update(&event.Stats)
 
 		for _, result := range event.Results {
+			if aggregator != nil {
+				aggregator.Add(result)
+				continue
+			}
 			_ = matchesBuf.Append(result)
 		}
 
 		// Instantly send results if we have not sent any yet.
-		if first && matchesBuf.Len() > 0 {
+		if first && (matchesBuf.Len() > 0 || aggregator != nil && aggregator.Dirty()) {
 			first = false
 			matchesFlush()
 		}
@@ -178,9 +205,74 @@ LOOP:
 	_ = eventWriter.Event("progress", progress.Final())
 }
 
+// serveDownload runs a diff replace to completion and writes all its diffs
+// as a single file in args.Format.
+func (h *streamHandler) serveDownload(ctx context.Context, w http.ResponseWriter, args *args, searchQuery string, cmd compute.Command) error {
+	if replace, ok := cmd.(*compute.Replace); !ok || !replace.Diff {
+		err := errors.Errorf("format %q requires a diff replace like content:replace.diff(a -> b)", args.Format)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return err
+	}
+
+	var diffs []*compute.FileDiff
+	var truncated bool
+	events, getResults := NewComputeStream(ctx, h.logger, h.db, searchQuery, cmd)
+	for event := range events {
+		if event.Stats.IsLimitHit || event.Stats.Status.Any(search.RepoStatusLimitHit|search.RepoStatusTimedOut) {
+			truncated = true
+		}
+		for _, result := range event.Results {
+			if diff, ok := result.(*compute.FileDiff); ok {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+	if _, err := getResults(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		// A partial patch is worse than none, since it looks complete.
+		http.Error(w, "the query took too long to compute all diffs", http.StatusGatewayTimeout)
+		return err
+	}
+	if truncated {
+		// Same as above, the search stopped before it found all matches.
+		err := errors.New("the query hit a result limit, add count:all to compute all diffs")
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return err
+	}
+
+	var content, filename, contentType string
+	switch args.Format {
+	case formatPatch:
+		content, filename, contentType = compute.Patch(diffs), "compute.patch", "text/x-diff"
+	case formatBatchSpec:
+		spec, err := compute.BatchSpec(args.Query, diffs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return err
+		}
+		content, filename, contentType = spec, "compute.batch.yaml", "application/yaml"
+	}
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	_, err := io.WriteString(w, content)
+	return err
+}
+
+const (
+	formatStream    = "stream"
+	formatPatch     = "patch"
+	formatBatchSpec = "batch-spec"
+)
+
 type args struct {
 	Query   string
 	Display int
+	// Format is formatStream to stream results as events, or formatPatch
+	// or formatBatchSpec to download the diffs of a diff replace.
+	Format string
 }
 
 func parseURLQuery(q url.Values) (*args, error) {
@@ -193,13 +285,20 @@ func parseURLQuery(q url.Values) (*args, error) {
 	}
 
 	a := args{
-		Query: get("q", ""),
+		Query:  get("q", ""),
+		Format: get("format", formatStream),
 	}
 
 	if a.Query == "" {
 		return nil, errors.New("no query found")
 	}
 
+	switch a.Format {
+	case formatStream, formatPatch, formatBatchSpec:
+	default:
+		return nil, errors.Errorf("format must be one of %q, %q or %q, got %q", formatStream, formatPatch, formatBatchSpec, a.Format)
+	}
+
 	display := get("display", "-1") // TODO(rvantonder): Currently unused; implement a limit for compute results.
 	var err error
 	if a.Display, err = strconv.Atoi(display); err != nil {
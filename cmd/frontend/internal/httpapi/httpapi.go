@@ -76,6 +76,7 @@ type Handlers struct {
 	// Search jobs
 	SearchJobsDataExportHandler http.Handler
 	SearchJobsLogsHandler       http.Handler
+	SearchJobsDiffExportHandler http.Handler
 
 	// Dotcom license check
 	NewDotcomLicenseCheckHandler enterprise.NewDotcomLicenseCheckHandler
@@ -162,7 +163,10 @@ func NewHandler(
 	m.Path("/insights/export/{id}").Methods("GET").Handler(trace.Route(handlers.CodeInsightsDataExportHandler))
 	m.Path("/search/stream").Methods("GET").Handler(trace.Route(frontendsearch.StreamHandler(db)))
 	m.Path("/search/export/{id}.csv").Methods("GET").Handler(trace.Route(handlers.SearchJobsDataExportHandler))
+	m.Path("/search/export/{id}.jsonl").Methods("GET").Handler(trace.Route(handlers.SearchJobsDataExportHandler))
 	m.Path("/search/export/{id}.log").Methods("GET").Handler(trace.Route(handlers.SearchJobsLogsHandler))
+	m.Path("/search/export/{id}/diff.csv").Methods("GET").Handler(trace.Route(handlers.SearchJobsDiffExportHandler))
+	m.Path("/search/export/{id}/diff.jsonl").Methods("GET").Handler(trace.Route(handlers.SearchJobsDiffExportHandler))
 
 	m.Path("/completions/stream").Methods("POST").Handler(trace.Route(handlers.NewChatCompletionsStreamHandler()))
 	m.Path("/completions/code").Methods("POST").Handler(trace.Route(handlers.NewCodeCompletionsHandler()))
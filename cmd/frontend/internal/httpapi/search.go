@@ -570,6 +570,8 @@ func (a *indexStatusUpdateArgs) ToProto() *proto.UpdateIndexStatusRequest {
 	}
 }
 
+// repoPathRanksToProto converts r to the response of the DocumentRanks RPC
+// used by zoekt. Zoekt doesn't use r.DefinitionPaths, so they are dropped.
 func repoPathRanksToProto(r *citypes.RepoPathRanks) *proto.DocumentRanksResponse {
 	paths := make(map[string]float64, len(r.Paths))
 	for path, counts := range r.Paths {
@@ -603,7 +603,9 @@ func TestRepoPathRanks_RoundTrip(t *testing.T) {
 	f := func(original citypes.RepoPathRanks) bool {
 		converted := repoPathRanksFromProto(repoPathRanksToProto(&original))
 
-		if diff = cmp.Diff(&original, converted); diff != "" {
+		// Zoekt doesn't use the definition paths, so they aren't part of
+		// the gRPC response.
+		if diff = cmp.Diff(&original, converted, cmpopts.IgnoreFields(citypes.RepoPathRanks{}, "DefinitionPaths")); diff != "" {
 			return false
 		}
 
@@ -23,7 +23,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
-func setupMockGSClient(t *testing.T, wantRev api.CommitID, returnErr error, hunks []*gitserver.Hunk) gitserver.Client {
+func setupMockGSClient(t *testing.T, wantRev api.CommitID, returnErr error, hunks []*gitdomain.Hunk) gitserver.Client {
 	hunkReader := gitserver.NewMockHunkReader(hunks, returnErr)
 	gsClient := gitserver.NewMockClient()
 	gsClient.GetCommitFunc.SetDefaultHook(
@@ -55,7 +55,7 @@ func setupMockGSClient(t *testing.T, wantRev api.CommitID, returnErr error, hunk
 func TestStreamBlame(t *testing.T) {
 	logger, _ := logtest.Captured(t)
 
-	hunks := []*gitserver.Hunk{
+	hunks := []*gitdomain.Hunk{
 		{
 			StartLine: 1,
 			EndLine:   2,
@@ -196,7 +196,7 @@ func TestStreamBlame(t *testing.T) {
 			"Repo": "github.com/bob/foo",
 			"path": "foo.c",
 		})
-		gsClient := setupMockGSClient(t, "efgh", nil, []*gitserver.Hunk{
+		gsClient := setupMockGSClient(t, "efgh", nil, []*gitdomain.Hunk{
 			{
 				StartLine: 1,
 				EndLine:   2,
@@ -245,7 +245,7 @@ func TestStreamBlame(t *testing.T) {
 			"Repo": "foo",
 			"path": "foo.c",
 		})
-		gsClient := setupMockGSClient(t, "efgh", nil, []*gitserver.Hunk{
+		gsClient := setupMockGSClient(t, "efgh", nil, []*gitdomain.Hunk{
 			{
 				StartLine: 1,
 				EndLine:   2,
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -33,14 +34,46 @@ func ServeSearchJobDownload(logger log.Logger, svc *service.Service) http.Handle
 			return
 		}
 
-		csvWriterTo, err := svc.GetSearchJobCSVWriterTo(r.Context(), int64(jobID))
+		writerTo, format, err := svc.GetSearchJobResultsWriterTo(r.Context(), int64(jobID))
 		if err != nil {
 			httpError(w, err)
 			return
 		}
 
-		filename := filenamePrefix(jobID) + ".csv"
-		writeCSV(logger.With(log.Int("jobID", jobID)), w, filename, csvWriterTo)
+		filename := filenamePrefix(jobID) + "." + format.FileExtension()
+		if format == types.OutputFormatJSONLines {
+			writeFile(logger.With(log.Int("jobID", jobID)), w, "application/jsonl", filename, writerTo)
+			return
+		}
+		writeCSV(logger.With(log.Int("jobID", jobID)), w, filename, writerTo)
+	}
+}
+
+// ServeSearchJobDiffDownload serves the diff between the latest scheduled run
+// of a search job and the run before.
+func ServeSearchJobDiffDownload(logger log.Logger, svc *service.Service) http.HandlerFunc {
+	logger = logger.With(log.String("handler", "ServeSearchJobDiffDownload"))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobIDStr := mux.Vars(r)["id"]
+		jobID, err := strconv.Atoi(jobIDStr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		writerTo, format, err := svc.GetSearchJobDiffWriterTo(r.Context(), int64(jobID))
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+
+		filename := filenamePrefix(jobID) + ".diff." + format.FileExtension()
+		if format == types.OutputFormatJSONLines {
+			writeFile(logger.With(log.Int("jobID", jobID)), w, "application/jsonl", filename, writerTo)
+			return
+		}
+		writeCSV(logger.With(log.Int("jobID", jobID)), w, filename, writerTo)
 	}
 }
 
@@ -67,12 +100,16 @@ func ServeSearchJobLogs(logger log.Logger, svc *service.Service) http.HandlerFun
 }
 
 func writeCSV(logger log.Logger, w http.ResponseWriter, filenameNoQuotes string, writerTo io.WriterTo) {
-	w.Header().Set("Content-Type", "text/csv")
+	writeFile(logger, w, "text/csv", filenameNoQuotes, writerTo)
+}
+
+func writeFile(logger log.Logger, w http.ResponseWriter, contentType, filenameNoQuotes string, writerTo io.WriterTo) {
+	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filenameNoQuotes))
 	w.WriteHeader(200)
 	n, err := writerTo.WriteTo(w)
 	if err != nil {
-		logger.Warn("failed while writing search job csv response", log.String("filename", filenameNoQuotes), log.Int64("bytesWritten", n), log.Error(err))
+		logger.Warn("failed while writing search job response", log.String("filename", filenameNoQuotes), log.Int64("bytesWritten", n), log.Error(err))
 	}
 }
 
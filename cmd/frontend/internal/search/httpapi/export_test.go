@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
 	"github.com/sourcegraph/sourcegraph/lib/iterator"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -67,7 +68,7 @@ func TestServeSearchJobDownload(t *testing.T) {
 		userCtx := actor.WithActor(context.Background(), &actor.Actor{
 			UID: userID,
 		})
-		_, err = svc.CreateSearchJob(userCtx, "1@rev1")
+		_, err = svc.CreateSearchJob(userCtx, "1@rev1", types.OutputFormatCSV)
 		require.NoError(t, err)
 
 		req, err := http.NewRequest(http.MethodGet, "/1.csv", nil)
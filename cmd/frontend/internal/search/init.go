@@ -46,6 +46,7 @@ func Init(
 	enterpriseServices.SearchJobsResolver = resolvers.New(logger, db, svc)
 	enterpriseServices.SearchJobsDataExportHandler = httpapi.ServeSearchJobDownload(logger, svc)
 	enterpriseServices.SearchJobsLogsHandler = httpapi.ServeSearchJobLogs(logger, svc)
+	enterpriseServices.SearchJobsDiffExportHandler = httpapi.ServeSearchJobDiffDownload(logger, svc)
 
 	return nil
 }
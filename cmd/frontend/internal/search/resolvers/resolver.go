@@ -17,8 +17,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	exhaustivetypes "github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
 // Resolver is the GraphQL resolver of all things related to search jobs.
@@ -40,7 +42,42 @@ func New(logger log.Logger, db database.DB, svc *service.Service) graphqlbackend
 var _ graphqlbackend.SearchJobsResolver = &Resolver{}
 
 func (r *Resolver) CreateSearchJob(ctx context.Context, args *graphqlbackend.CreateSearchJobArgs) (graphqlbackend.SearchJobResolver, error) {
-	job, err := r.svc.CreateSearchJob(ctx, args.Query)
+	format, err := exhaustivetypes.ParseOutputFormat(args.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := pointers.Deref(args.Schedule, "")
+	if schedule != "" {
+		// Validate before creating the job, so we don't end up with a job
+		// without the schedule the user asked for.
+		if _, err := exhaustivetypes.ParseSchedule(schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	job, err := r.svc.CreateSearchJob(ctx, args.Query, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule != "" {
+		job, err = r.svc.SetSearchJobSchedule(ctx, job.ID, schedule)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newSearchJobResolver(r.db, r.svc, job), nil
+}
+
+func (r *Resolver) SetSearchJobSchedule(ctx context.Context, args *graphqlbackend.SetSearchJobScheduleArgs) (graphqlbackend.SearchJobResolver, error) {
+	jobID, err := UnmarshalSearchJobID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := r.svc.SetSearchJobSchedule(ctx, jobID, pointers.Deref(args.Schedule, ""))
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +94,25 @@ func (r *Resolver) CancelSearchJob(ctx context.Context, args *graphqlbackend.Can
 	return &graphqlbackend.EmptyResponse{}, r.svc.CancelSearchJob(ctx, jobID)
 }
 
+func (r *Resolver) RetrySearchJob(ctx context.Context, args *graphqlbackend.RetrySearchJobArgs) (graphqlbackend.SearchJobResolver, error) {
+	jobID, err := UnmarshalSearchJobID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := exhaustivetypes.ParseRetryMode(args.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := r.svc.RetrySearchJob(ctx, jobID, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSearchJobResolver(r.db, r.svc, job), nil
+}
+
 func (r *Resolver) DeleteSearchJob(ctx context.Context, args *graphqlbackend.DeleteSearchJobArgs) (*graphqlbackend.EmptyResponse, error) {
 	jobID, err := UnmarshalSearchJobID(args.ID)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -63,6 +64,10 @@ func (r *searchJobResolver) Creator(ctx context.Context) (*graphqlbackend.UserRe
 	return graphqlbackend.NewUserResolver(ctx, r.db, user), nil
 }
 
+func (r *searchJobResolver) Format() string {
+	return strings.ToUpper(string(r.Job.Format))
+}
+
 func (r *searchJobResolver) CreatedAt() gqlutil.DateTime {
 	return *gqlutil.FromTime(r.Job.CreatedAt)
 }
@@ -77,7 +82,7 @@ func (r *searchJobResolver) FinishedAt(ctx context.Context) *gqlutil.DateTime {
 
 func (r *searchJobResolver) URL(ctx context.Context) (*string, error) {
 	if r.Job.State == types.JobStateCompleted {
-		exportPath, err := url.JoinPath(conf.Get().ExternalURL, fmt.Sprintf("/.api/search/export/%d.csv", r.Job.ID))
+		exportPath, err := url.JoinPath(conf.Get().ExternalURL, fmt.Sprintf("/.api/search/export/%d.%s", r.Job.ID, r.Job.Format.FileExtension()))
 		if err != nil {
 			return nil, err
 		}
@@ -104,3 +109,41 @@ func (r *searchJobResolver) RepoStats(ctx context.Context) (graphqlbackend.Searc
 	}
 	return &searchJobStatsResolver{repoRevStats}, nil
 }
+
+func (r *searchJobResolver) Schedule() *string {
+	if r.Job.Schedule == "" {
+		return nil
+	}
+	return pointers.Ptr(r.Job.Schedule)
+}
+
+func (r *searchJobResolver) NextRunAt() *gqlutil.DateTime {
+	return gqlutil.FromTime(r.Job.NextRunAt)
+}
+
+func (r *searchJobResolver) ResultsDiff() graphqlbackend.SearchJobResultsDiffResolver {
+	if r.Job.Diff.ComputedAt.IsZero() {
+		return nil
+	}
+	return &searchJobResultsDiffResolver{job: r.Job}
+}
+
+type searchJobResultsDiffResolver struct {
+	job *types.ExhaustiveSearchJob
+}
+
+func (r *searchJobResultsDiffResolver) Added() int32 {
+	return r.job.Diff.Added
+}
+
+func (r *searchJobResultsDiffResolver) Removed() int32 {
+	return r.job.Diff.Removed
+}
+
+func (r *searchJobResultsDiffResolver) ComputedAt() gqlutil.DateTime {
+	return *gqlutil.FromTime(r.job.Diff.ComputedAt)
+}
+
+func (r *searchJobResultsDiffResolver) URL() (string, error) {
+	return url.JoinPath(conf.Get().ExternalURL, fmt.Sprintf("/.api/search/export/%d/diff.%s", r.job.ID, r.job.Format.FileExtension()))
+}
@@ -286,6 +286,10 @@ func fromMatch(match result.Match, repoCache map[api.RepoID]*types.SearchedRepo,
 		return fromCommit(v, repoCache)
 	case *result.OwnerMatch:
 		return fromOwner(v)
+	case *result.DirectoryMatch:
+		return fromDirectoryMatch(v, repoCache)
+	case *result.CompareMatch:
+		return fromCompareMatch(v, repoCache)
 	default:
 		panic(fmt.Sprintf("unknown match type %T", v))
 	}
@@ -326,6 +330,61 @@ func fromPathMatch(fm *result.FileMatch, repoCache map[api.RepoID]*types.Searche
 	return pathEvent
 }
 
+func fromDirectoryMatch(dm *result.DirectoryMatch, repoCache map[api.RepoID]*types.SearchedRepo) *streamhttp.EventPathMatch {
+	pathEvent := &streamhttp.EventPathMatch{
+		Type:         streamhttp.PathMatchType,
+		Path:         dm.Path,
+		Repository:   string(dm.Repo.Name),
+		RepositoryID: int32(dm.Repo.ID),
+		Commit:       string(dm.CommitID),
+		FileCount:    pointers.Ptr(dm.FileCount),
+		MatchCount:   dm.MatchCount,
+	}
+
+	if r, ok := repoCache[dm.Repo.ID]; ok {
+		pathEvent.RepoStars = r.Stars
+		pathEvent.RepoLastFetched = r.LastFetched
+	}
+
+	if dm.InputRev != nil {
+		pathEvent.Branches = []string{*dm.InputRev}
+	}
+
+	return pathEvent
+}
+
+func fromCompareMatch(cm *result.CompareMatch, repoCache map[api.RepoID]*types.SearchedRepo) *streamhttp.EventCompareMatch {
+	lines := make([]streamhttp.EventCompareLine, 0, len(cm.Lines))
+	for _, l := range cm.Lines {
+		lines = append(lines, streamhttp.EventCompareLine{
+			Status:           string(l.Status),
+			Line:             l.Preview,
+			BaseLineNumber:   l.BaseLineNumber,
+			HeadLineNumber:   l.HeadLineNumber,
+			OffsetAndLengths: l.OffsetAndLengths,
+		})
+	}
+
+	compareEvent := &streamhttp.EventCompareMatch{
+		Type:         streamhttp.CompareMatchType,
+		Path:         cm.Path,
+		Repository:   string(cm.Repo.Name),
+		RepositoryID: int32(cm.Repo.ID),
+		BaseRevision: cm.BaseRev,
+		HeadRevision: cm.HeadRev,
+		BaseCommit:   string(cm.BaseCommit),
+		HeadCommit:   string(cm.HeadCommit),
+		Lines:        lines,
+	}
+
+	if r, ok := repoCache[cm.Repo.ID]; ok {
+		compareEvent.RepoStars = r.Stars
+		compareEvent.RepoLastFetched = r.LastFetched
+	}
+
+	return compareEvent
+}
+
 func fromChunkMatches(cms result.ChunkMatches) []streamhttp.ChunkMatch {
 	res := make([]streamhttp.ChunkMatch, 0, len(cms))
 	for _, cm := range cms {
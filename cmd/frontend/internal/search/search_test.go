@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -284,3 +285,17 @@ func mkRepoMatch(id int) *result.RepoMatch {
 		Name: api2.RepoName(fmt.Sprintf("repo%d", id)),
 	}
 }
+
+func TestFromDirectoryMatch(t *testing.T) {
+	dm := &result.DirectoryMatch{
+		Path: "a/",
+		Repo: types.MinimalRepo{ID: 1, Name: "r"},
+	}
+	b, err := json.Marshal(fromDirectoryMatch(dm, nil))
+	require.NoError(t, err)
+
+	// Deltas which only add matches of files that were already counted have
+	// a zero file count. It must still be sent, clients rely on it to tell
+	// directories apart from other path matches.
+	require.JSONEq(t, `{"type":"path","path":"a/","repositoryID":1,"repository":"r","fileCount":0}`, string(b))
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/hostname"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/internal/wrexec"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -289,7 +290,9 @@ func cleanupRepos(
 		name := gitserverfs.RepoNameFromDir(reposDir, dir)
 		addr := addrForRepo(ctx, name, gitServerAddrs)
 
-		if hostnameMatch(shardID, addr) {
+		// Secondary copies of the repo are kept on the shards that follow the
+		// primary shard.
+		if ownsRepo(ctx, shardID, gitServerAddrs, name) {
 			return false, nil
 		}
 
@@ -339,6 +342,21 @@ func cleanupRepos(
 		}
 
 		repoName := gitserverfs.RepoNameFromDir(reposDir, dir)
+
+		// Corrupt secondary copies are removed without touching the state of
+		// the primary copy, and fetched again by the replica syncer.
+		if _, replica := replicaPrimary(ctx, shardID, gitServerAddrs, repoName); replica {
+			logger.Info("removing corrupt replica", log.String("repo", string(dir)), log.String("reason", reason))
+			if err := gitserverfs.RemoveRepoDirectory(ctx, logger, db, shardID, reposDir, dir, false); err != nil {
+				return true, err
+			}
+			if err := db.GitserverRepos().SetReplicaCloneStatus(ctx, repoName, types.CloneStatusNotCloned, shardID); err != nil {
+				logger.Warn("failed to set replica clone status", log.String("repo", string(repoName)), log.Error(err))
+			}
+			reposRemoved.WithLabelValues(reason).Inc()
+			return true, nil
+		}
+
 		err = db.GitserverRepos().LogCorruption(ctx, repoName, fmt.Sprintf("sourcegraph detected corrupt repo: %s", reason), shardID)
 		if err != nil {
 			logger.Warn("failed to log repo corruption", log.String("repo", string(repoName)), log.Error(err))
@@ -1078,13 +1096,17 @@ func pruneIfNeeded(rcf *wrexec.RecordingCommandFactory, reposDir string, dir com
 
 func needsMaintenance(dir common.GitDir) (bool, string, error) {
 	// Bitmaps store reachability information about the set of objects in a
-	// packfile which speeds up clone and fetch operations.
-	hasBm, err := hasBitmap(dir)
-	if err != nil {
-		return false, "", err
-	}
-	if !hasBm {
-		return true, "bitmap", nil
+	// packfile which speeds up clone and fetch operations. Git doesn't write
+	// bitmaps when repacking partial clones, so we don't require one for them.
+	// Otherwise they would be repacked on every janitor run.
+	if !git.IsPartialClone(dir) {
+		hasBm, err := hasBitmap(dir)
+		if err != nil {
+			return false, "", err
+		}
+		if !hasBm {
+			return true, "bitmap", nil
+		}
 	}
 
 	// The commit-graph file is a supplemental data structure that accelerates
@@ -1175,6 +1197,9 @@ func tooManyPackfiles(dir common.GitDir, limit int) (bool, error) {
 	}
 	count := 0
 	for _, p := range packs {
+		// Packs fetched lazily into partial clones are counted like any other
+		// pack, so that repacking consolidates them into one promisor pack.
+		//
 		// Because we know p has the extension .pack, we can slice it off directly
 		// instead of using strings.TrimSuffix and filepath.Ext. Benchmarks showed that
 		// this option is 20x faster than strings.TrimSuffix(file, filepath.Ext(file))
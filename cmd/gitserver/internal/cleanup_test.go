@@ -305,6 +305,20 @@ func TestGitGCAuto(t *testing.T) {
 	repo = filepath.Join(root, "garbage-repo")
 	runCmd(t, root, "git", "clone", "--bare", wdRepo, filepath.Join(repo, ".git"))
 
+	// And a partial clone, whose blobs are missing.
+	runCmd(t, wdRepo, "git", "config", "uploadpack.allowFilter", "true")
+	partialRepo := filepath.Join(root, "partial-garbage-repo")
+	runCmd(t, root, "git", "clone", "--bare", "--filter=blob:none", "file://"+wdRepo, filepath.Join(partialRepo, ".git"))
+	countMissingObjects := func() int {
+		t.Helper()
+		out := runCmd(t, partialRepo, "git", "rev-list", "--objects", "--all", "--missing=print")
+		return strings.Count(out, "\n?")
+	}
+	missing := countMissingObjects()
+	if missing == 0 {
+		t.Fatalf("expected the partial clone to miss blobs")
+	}
+
 	// `git count-objects -v` can indicate objects, packs, etc.
 	// We'll run this before and after to verify that an action
 	// was taken by `git gc --auto`.
@@ -336,6 +350,15 @@ func TestGitGCAuto(t *testing.T) {
 	if !strings.Contains(countObjects(), "count: 0") {
 		t.Fatalf("expected git to report no objects, but found some")
 	}
+
+	// Verify that the partial clone kept its promisor pack and didn't fetch
+	// its missing blobs.
+	if !git.IsPartialClone(common.GitDir(filepath.Join(partialRepo, ".git"))) {
+		t.Fatalf("expected the partial clone to keep its promisor pack")
+	}
+	if got := countMissingObjects(); got != missing {
+		t.Fatalf("expected %d missing objects in the partial clone, got %d", missing, got)
+	}
 }
 
 func TestCleanupExpired(t *testing.T) {
@@ -1086,6 +1109,18 @@ func TestTooManyPackFiles(t *testing.T) {
 			file: "b.keep",
 			want: false,
 		},
+		{
+			name: "3 packs, with 1 keep file",
+			file: "c.pack",
+			want: true,
+		},
+		{
+			// Packs fetched lazily into partial clones are promisor packs,
+			// which are repacked like any other.
+			name: "3 packs, with 1 keep file and 1 promisor file",
+			file: "c.promisor",
+			want: true,
+		},
 	}
 
 	for _, c := range cases {
@@ -1180,6 +1215,30 @@ git commit-graph write --reachable --changed-paths
 	if needed {
 		t.Fatal("this repo doesn't need maintenance")
 	}
+
+	// Git doesn't write bitmaps for partial clones, so they don't need one.
+	reposDir := t.TempDir()
+	makePartialClone(t, reposDir, "partial", `echo acont > afile
+git add afile
+git commit -am amsg
+`)
+	gitDir = gitserverfs.RepoDirFromName(reposDir, "partial")
+	cmd = exec.Command("git", "commit-graph", "write", "--reachable", "--changed-paths")
+	cmd.Dir = gitDir.Path()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("out=%s, err=%s", out, err)
+	}
+
+	needed, reason, err = needsMaintenance(gitDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reason != "skipped" {
+		t.Fatalf("want %s, got %s", "skipped", reason)
+	}
+	if needed {
+		t.Fatal("partial clones without a bitmap don't need maintenance")
+	}
 }
 
 func TestPruneIfNeeded(t *testing.T) {
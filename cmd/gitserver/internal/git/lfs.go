@@ -0,0 +1,132 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+)
+
+// LFSPointerMaxSize is the maximum size of a Git LFS pointer file. Larger
+// blobs are never treated as pointers. This is the same limit git-lfs uses.
+const LFSPointerMaxSize = 1024
+
+const lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// LFSPointer is a Git LFS pointer file, which is stored in the repository in
+// place of the contents of a file tracked by Git LFS.
+type LFSPointer struct {
+	// OID is the hex-encoded SHA-256 hash of the contents of the file.
+	OID string
+	// Size is the size of the contents of the file in bytes.
+	Size int64
+}
+
+// ParseLFSPointer parses the contents of a blob as a Git LFS pointer file. It
+// returns false if the blob isn't a pointer.
+func ParseLFSPointer(b []byte) (LFSPointer, bool) {
+	if len(b) >= LFSPointerMaxSize {
+		return LFSPointer{}, false
+	}
+
+	// A pointer consists of the version line followed by "key value" lines,
+	// one of which must be the oid and one the size.
+	// https://github.com/git-lfs/git-lfs/blob/main/docs/spec.md
+	s := bufio.NewScanner(bytes.NewReader(b))
+	if !s.Scan() || s.Text() != lfsPointerVersion {
+		return LFSPointer{}, false
+	}
+	var p LFSPointer
+	var hasSize bool
+	for s.Scan() {
+		key, value, ok := strings.Cut(s.Text(), " ")
+		if !ok {
+			return LFSPointer{}, false
+		}
+		switch key {
+		case "oid":
+			oid, ok := strings.CutPrefix(value, "sha256:")
+			if !ok || !isLFSOID(oid) {
+				return LFSPointer{}, false
+			}
+			p.OID = oid
+		case "size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || size < 0 {
+				return LFSPointer{}, false
+			}
+			p.Size, hasSize = size, true
+		}
+	}
+	if p.OID == "" || !hasSize {
+		return LFSPointer{}, false
+	}
+	return p, true
+}
+
+func isLFSOID(oid string) bool {
+	if len(oid) != 64 {
+		return false
+	}
+	for _, c := range oid {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// LFSObjectPath returns the path at which the contents of the Git LFS object
+// with the given OID are stored. This is the same layout git-lfs uses, so that
+// the objects can be read by its filters.
+func LFSObjectPath(dir common.GitDir, oid string) string {
+	return dir.Path("lfs", "objects", oid[0:2], oid[2:4], oid)
+}
+
+// LFSAttributesPath returns the path of the attributes file that applies the
+// Git LFS filter to all files of a repository, see LFSFilterArgs.
+func LFSAttributesPath(dir common.GitDir) string {
+	return dir.Path("lfs", "attributes")
+}
+
+// HasLFSObjects returns true if Git LFS objects were fetched for the
+// repository in dir.
+func HasLFSObjects(dir common.GitDir) bool {
+	_, err := os.Stat(LFSAttributesPath(dir))
+	return err == nil
+}
+
+// OpenLFSObject opens the contents of the Git LFS object p points to. It
+// returns an error satisfying os.IsNotExist if the object wasn't fetched.
+func OpenLFSObject(dir common.GitDir, p LFSPointer) (*os.File, error) {
+	f, err := os.Open(LFSObjectPath(dir, p.OID))
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := f.Stat(); err != nil || fi.Size() != p.Size {
+		f.Close()
+		return nil, os.ErrNotExist
+	}
+	return f, nil
+}
+
+// LFSFilterArgs returns the git options that make commands like git archive
+// return the contents of the Git LFS objects that were fetched for the
+// repository in dir instead of their pointer files. Pointers to objects that
+// weren't fetched are returned as is, git-lfs never downloads anything.
+//
+// The filter is applied to all files, not only the ones tracked by Git LFS
+// according to .gitattributes, because archives are created with
+// --worktree-attributes and bare repositories have no working tree. git-lfs
+// passes files that aren't pointers through unchanged.
+func LFSFilterArgs(dir common.GitDir) []string {
+	return []string{
+		"-c", "core.attributesFile=" + LFSAttributesPath(dir),
+		"-c", "filter.lfs.process=git-lfs filter-process --skip",
+		"-c", "filter.lfs.smudge=git-lfs smudge --skip -- %f",
+		"-c", "filter.lfs.required=false",
+	}
+}
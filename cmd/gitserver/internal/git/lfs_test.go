@@ -0,0 +1,58 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLFSPointer(t *testing.T) {
+	const oid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+
+	for _, tc := range []struct {
+		name    string
+		content string
+		want    LFSPointer
+		wantOK  bool
+	}{
+		{
+			name:    "pointer",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 12345\n",
+			want:    LFSPointer{OID: oid, Size: 12345},
+			wantOK:  true,
+		},
+		{
+			name:    "extensions",
+			content: "version https://git-lfs.github.com/spec/v1\next-0-foo sha256:" + oid + "\noid sha256:" + oid + "\nsize 1\n",
+			want:    LFSPointer{OID: oid, Size: 1},
+			wantOK:  true,
+		},
+		{
+			name:    "unknown version",
+			content: "version https://hawser.github.com/spec/v1\noid sha256:" + oid + "\nsize 12345\n",
+		},
+		{
+			name:    "missing size",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\n",
+		},
+		{
+			name:    "invalid oid",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:../../etc/passwd\nsize 12345\n",
+		},
+		{
+			name:    "too large",
+			content: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 12345\n" + strings.Repeat("x", LFSPointerMaxSize),
+		},
+		{
+			name:    "not a pointer",
+			content: "package main\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := ParseLFSPointer([]byte(tc.content))
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package git
+
+import (
+	"path/filepath"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+)
+
+// PartialCloneRemote is the name of the promisor remote of partially cloned
+// repositories. Git fetches objects that were filtered out during the clone
+// lazily from this remote.
+//
+// 🚨 SECURITY: The URL of the remote is never written to the repository's
+// config because it may contain credentials. Commands that need to reach the
+// remote get it through the environment, see PromisorRemoteEnv.
+const PartialCloneRemote = "origin"
+
+// IsPartialClone returns true if the repository in dir was cloned with an
+// object filter. Every pack fetched from a promisor remote is accompanied by a
+// .promisor file, and git repack keeps it when consolidating those packs.
+func IsPartialClone(dir common.GitDir) bool {
+	promisorPacks, err := filepath.Glob(dir.Path("objects", "pack", "*.promisor"))
+	return err == nil && len(promisorPacks) > 0
+}
+
+// PromisorRemoteEnv returns the environment variables that configure the URL
+// of PartialCloneRemote for a single git invocation and all git processes it
+// spawns, such as the ones that lazily fetch missing objects.
+func PromisorRemoteEnv(remoteURL string) []string {
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=remote." + PartialCloneRemote + ".url",
+		"GIT_CONFIG_VALUE_0=" + remoteURL,
+	}
+}
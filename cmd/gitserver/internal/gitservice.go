@@ -88,6 +88,13 @@ func (s *Server) gitServiceHandler() *gitservice.Handler {
 			cmd.Stdout = flowrateWriter(logger, cmd.Stdout)
 		},
 
+		// Secondary copies of a partial clone fetch the objects they are
+		// missing from the primary, which in turn may have to fetch them from
+		// the code host.
+		RepoCommandHook: func(ctx context.Context, repo string, cmd *exec.Cmd) {
+			s.configurePromisorRemote(ctx, logger, api.RepoName(repo), cmd)
+		},
+
 		Trace: func(ctx context.Context, svc, repo, protocol string) func(error) {
 			start := time.Now()
 			metricServiceRunning.WithLabelValues(svc).Inc()
@@ -1,6 +1,7 @@
 package inttests
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"bytes"
 	"context"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 
@@ -24,10 +26,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/blobcache"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
 	internalgrpc "github.com/sourcegraph/sourcegraph/internal/grpc"
 	"github.com/sourcegraph/sourcegraph/internal/grpc/defaults"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 	"github.com/sourcegraph/sourcegraph/internal/wrexec"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -373,3 +377,60 @@ filemode=true
 
 	return dir
 }
+
+func TestClient_ArchiveReader_BlobCache(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+
+	repo := MakeGitRepository(t,
+		"echo ignored > ignored.txt",
+		"echo '$Format:%H$' > subst.txt",
+		"printf 'ignored.txt export-ignore\\nsubst.txt export-subst\\n' > .gitattributes",
+		"git add -A",
+		"git commit -m commit1",
+	)
+	ctx := context.Background()
+	client := gitserver.NewTestClient(t).WithClientSource(source)
+	commitID, err := client.ResolveRevision(ctx, repo, "HEAD", gitserver.ResolveRevisionOptions{})
+	require.NoError(t, err)
+
+	readFiles := func(rc io.ReadCloser) map[string]string {
+		t.Helper()
+		defer rc.Close()
+		files := make(map[string]string)
+		tr := tar.NewReader(rc)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			files[hdr.Name] = string(content)
+		}
+		return files
+	}
+
+	rc, err := client.ArchiveReader(ctx, repo, gitserver.ArchiveOptions{Treeish: string(commitID), Format: gitserver.ArchiveFormatTar})
+	require.NoError(t, err)
+	archive := readFiles(rc)
+
+	cache := blobcache.New(observation.TestContextTB(t), client, t.TempDir(), 0)
+	rc, err = cache.TarReader(ctx, repo, commitID)
+	require.NoError(t, err)
+	cached := readFiles(rc)
+
+	// gitserver creates archives with --worktree-attributes, so the
+	// .gitattributes files of the repository are ignored. Neither archive
+	// drops export-ignore files or expands export-subst placeholders.
+	want := map[string]string{
+		".gitattributes": "ignored.txt export-ignore\nsubst.txt export-subst\n",
+		"ignored.txt":    "ignored\n",
+		"subst.txt":      "$Format:%H$\n",
+	}
+	require.Equal(t, want, archive)
+	require.Equal(t, want, cached)
+}
@@ -3,10 +3,15 @@ package inttests
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -14,6 +19,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func TestGetCommits(t *testing.T) {
@@ -225,5 +232,1088 @@ func getTestSubRepoPermsChecker(noAccessPaths ...string) authz.SubRepoPermission
 		}
 		return authz.Read, nil
 	})
+	usePermissionsForFilePermissionsFunc(checker)
 	return checker
 }
+
+var (
+	fileWithAccess      = "file-with-access"
+	fileWithoutAccess   = "file-without-access"
+	NonExistentCommitID = api.CommitID(strings.Repeat("a", 40))
+)
+
+func TestRepository_HasCommitAfter(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+
+	testCases := []struct {
+		label                 string
+		commitDates           []string
+		after                 string
+		revspec               string
+		want, wantSubRepoTest bool
+	}{
+		{
+			label: "after specific date",
+			commitDates: []string{
+				"2006-01-02T15:04:05Z",
+				"2007-01-02T15:04:05Z",
+				"2008-01-02T15:04:05Z",
+			},
+			after:           "2006-01-02T15:04:05Z",
+			revspec:         "master",
+			want:            true,
+			wantSubRepoTest: true,
+		},
+		{
+			label: "after 1 year ago",
+			commitDates: []string{
+				"2016-01-02T15:04:05Z",
+				"2017-01-02T15:04:05Z",
+				"2017-01-02T15:04:06Z",
+			},
+			after:           "1 year ago",
+			revspec:         "master",
+			want:            false,
+			wantSubRepoTest: false,
+		},
+		{
+			label: "after too recent date",
+			commitDates: []string{
+				"2006-01-02T15:04:05Z",
+				"2007-01-02T15:04:05Z",
+				"2008-01-02T15:04:05Z",
+			},
+			after:           "2010-01-02T15:04:05Z",
+			revspec:         "HEAD",
+			want:            false,
+			wantSubRepoTest: false,
+		},
+		{
+			label: "commit 1 second after",
+			commitDates: []string{
+				"2006-01-02T15:04:05Z",
+				"2007-01-02T15:04:05Z",
+				"2007-01-02T15:04:06Z",
+			},
+			after:           "2007-01-02T15:04:05Z",
+			revspec:         "HEAD",
+			want:            true,
+			wantSubRepoTest: false,
+		},
+		{
+			label: "after 10 years ago",
+			commitDates: []string{
+				"2016-01-02T15:04:05Z",
+				"2017-01-02T15:04:05Z",
+				"2017-01-02T15:04:06Z",
+			},
+			after:           "10 years ago",
+			revspec:         "HEAD",
+			want:            true,
+			wantSubRepoTest: true,
+		},
+	}
+
+	t.Run("basic", func(t *testing.T) {
+		client := gitserver.NewTestClient(t).WithClientSource(source)
+		for _, tc := range testCases {
+			t.Run(tc.label, func(t *testing.T) {
+				gitCommands := make([]string, len(tc.commitDates))
+				for i, date := range tc.commitDates {
+					gitCommands[i] = fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=%s git commit --allow-empty -m foo --author='a <a@a.com>'", date)
+				}
+				repo := MakeGitRepository(t, gitCommands...)
+				got, err := client.HasCommitAfter(ctx, repo, tc.after, tc.revspec)
+				if err != nil || got != tc.want {
+					t.Errorf("got %t hascommitafter, want %t", got, tc.want)
+				}
+			})
+		}
+	})
+
+	t.Run("with sub-repo permissions", func(t *testing.T) {
+		for _, tc := range testCases {
+			t.Run(tc.label, func(t *testing.T) {
+				gitCommands := make([]string, len(tc.commitDates))
+				for i, date := range tc.commitDates {
+					fileName := fmt.Sprintf("file%d", i)
+					gitCommands = append(gitCommands, fmt.Sprintf("touch %s", fileName), fmt.Sprintf("git add %s", fileName))
+					gitCommands = append(gitCommands, fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=%s git commit -m commit%d --author='a <a@a.com>'", date, i))
+				}
+				// Case where user can't view commit 2, but can view commits 0 and 1. In each test case the result should match the case where no sub-repo perms enabled
+				checker := getTestSubRepoPermsChecker("file2")
+				client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+				repo := MakeGitRepository(t, gitCommands...)
+				got, err := client.HasCommitAfter(ctx, repo, tc.after, tc.revspec)
+				if err != nil {
+					t.Errorf("got error: %s", err)
+				}
+				if got != tc.want {
+					t.Errorf("got %t hascommitafter, want %t", got, tc.want)
+				}
+
+				// Case where user can't view commit 1 or commit 2, which will mean in some cases since HasCommitAfter will be false due to those commits not being visible.
+				checker = getTestSubRepoPermsChecker("file1", "file2")
+				client = gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+				got, err = client.HasCommitAfter(ctx, repo, tc.after, tc.revspec)
+				if err != nil {
+					t.Errorf("got error: %s", err)
+				}
+				if got != tc.wantSubRepoTest {
+					t.Errorf("got %t hascommitafter, want %t", got, tc.wantSubRepoTest)
+				}
+			})
+		}
+	})
+}
+
+func TestRepository_FirstEverCommit(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+
+	testCases := []struct {
+		commitDates []string
+		want        string
+	}{
+		{
+			commitDates: []string{
+				"2006-01-02T15:04:05Z",
+				"2007-01-02T15:04:05Z",
+				"2008-01-02T15:04:05Z",
+			},
+			want: "2006-01-02T15:04:05Z",
+		},
+		{
+			commitDates: []string{
+				"2007-01-02T15:04:05Z", // Don't think this is possible, but if it is we still want the first commit (not strictly "oldest")
+				"2006-01-02T15:04:05Z",
+				"2007-01-02T15:04:06Z",
+			},
+			want: "2007-01-02T15:04:05Z",
+		},
+	}
+	client := gitserver.NewTestClient(t).WithClientSource(source)
+	t.Run("basic", func(t *testing.T) {
+		for _, tc := range testCases {
+			gitCommands := make([]string, len(tc.commitDates))
+			for i, date := range tc.commitDates {
+				gitCommands[i] = fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=%s git commit --allow-empty -m foo --author='a <a@a.com>'", date)
+			}
+
+			repo := MakeGitRepository(t, gitCommands...)
+			gotCommit, err := client.FirstEverCommit(ctx, repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := gotCommit.Committer.Date.Format(time.RFC3339)
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		}
+	})
+
+	// Added for awareness if this error message changes. Insights skip over empty repos and check against error message
+	t.Run("empty repo", func(t *testing.T) {
+		repo := MakeGitRepository(t)
+		_, err := client.FirstEverCommit(ctx, repo)
+		wantErr := `git command [rev-list --reverse --date-order --max-parents=0 HEAD] failed (output: ""): exit status 129`
+		if !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("expected :%s, got :%s", wantErr, err)
+		}
+	})
+
+	t.Run("with sub-repo permissions", func(t *testing.T) {
+		clientWithoutAccessFirstCommit := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(getTestSubRepoPermsChecker("file0"))
+		clientWithAccessFirstCommit := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(getTestSubRepoPermsChecker("file1"))
+		for _, tc := range testCases {
+			gitCommands := make([]string, 0, len(tc.commitDates))
+			for i, date := range tc.commitDates {
+				fileName := fmt.Sprintf("file%d", i)
+				gitCommands = append(gitCommands, fmt.Sprintf("touch %s", fileName))
+				gitCommands = append(gitCommands, fmt.Sprintf("git add %s", fileName))
+				gitCommands = append(gitCommands, fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=%s git commit -m foo --author='a <a@a.com>'", date))
+			}
+
+			repo := MakeGitRepository(t, gitCommands...)
+
+			// Try to get first commit when user doesn't have permission to view
+			_, err := clientWithoutAccessFirstCommit.FirstEverCommit(ctx, repo)
+			if !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+				t.Errorf("expected a RevisionNotFoundError since the user does not have access to view this commit, got :%s", err)
+			}
+			// Try to get first commit when user does have permission to view, should succeed
+			gotCommit, err := clientWithAccessFirstCommit.FirstEverCommit(ctx, repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := gotCommit.Committer.Date.Format(time.RFC3339)
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+			// Internal actor should always have access and ignore sub-repo permissions
+			newCtx := actor.WithActor(context.Background(), &actor.Actor{
+				UID:      1,
+				Internal: true,
+			})
+			gotCommit, err = clientWithoutAccessFirstCommit.FirstEverCommit(newCtx, repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = gotCommit.Committer.Date.Format(time.RFC3339)
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		}
+	})
+}
+
+func TestCommitExists(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+	testCommitExists := func(label string, gitCommands []string, commitID, nonExistentCommitID api.CommitID, checker authz.SubRepoPermissionChecker) {
+		t.Run(label, func(t *testing.T) {
+			client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+			repo := MakeGitRepository(t, gitCommands...)
+
+			exists, err := client.CommitExists(ctx, repo, commitID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !exists {
+				t.Fatal("Should exist")
+			}
+
+			exists, err = client.CommitExists(ctx, repo, nonExistentCommitID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists {
+				t.Fatal("Should not exist")
+			}
+		})
+	}
+
+	gitCommands := []string{
+		"git commit --allow-empty -m foo",
+	}
+	testCommitExists("basic", gitCommands, "ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8", NonExistentCommitID, nil)
+	gitCommandsWithFiles := getGitCommandsWithFiles(fileWithAccess, fileWithoutAccess)
+	commitIDWithAccess := api.CommitID("da50eed82c8ff3c17bb642000d8aad9d434283c1")
+	commitIDWithoutAccess := api.CommitID("ee7773505e98390e809cbf518b2a92e4748b0187")
+	// Test that the commit ID the user has access to exists, and CommitExists returns false for the commit ID the user
+	// doesn't have access to (since a file was modified in the commit that the user doesn't have permissions to view)
+	testCommitExists("with sub-repo permissions filtering", gitCommandsWithFiles, commitIDWithAccess, commitIDWithoutAccess, getTestSubRepoPermsChecker(fileWithoutAccess))
+}
+
+func TestRepository_GetCommit(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+	gitCommands := []string{
+		"git commit --allow-empty -m foo",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+	}
+	gitCommandsWithFiles := getGitCommandsWithFiles(fileWithAccess, fileWithoutAccess)
+
+	type testCase struct {
+		gitCmds               []string
+		id                    api.CommitID
+		wantCommit            *gitdomain.Commit
+		noEnsureRevision      bool
+		revisionNotFoundError bool
+	}
+
+	runGetCommitTests := func(checker authz.SubRepoPermissionChecker, tests map[string]testCase) {
+		for label, test := range tests {
+			t.Run(label, func(t *testing.T) {
+				var noEnsureRevision bool
+				source := gitserver.NewTestClientSource(t, GitserverAddresses, func(o *gitserver.TestClientSourceOptions) {
+					o.ClientFunc = func(conn *grpc.ClientConn) proto.GitserverServiceClient {
+						c := proto.NewGitserverServiceClient(conn)
+						mc := gitserver.NewMockGitserverServiceClientFrom(c)
+						mc.CommitsFunc.SetDefaultHook(func(ctx context.Context, req *proto.CommitsRequest, opts ...grpc.CallOption) (proto.GitserverService_CommitsClient, error) {
+							// Track the value of NoEnsureRevision we pass to gitserver
+							noEnsureRevision = req.GetNoEnsureRevision()
+							return c.Commits(ctx, req, opts...)
+						})
+						return mc
+					}
+				})
+				client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+
+				testRepo := MakeGitRepository(t, test.gitCmds...)
+
+				resolveRevisionOptions := gitserver.ResolveRevisionOptions{
+					NoEnsureRevision: test.noEnsureRevision,
+				}
+				commit, err := client.GetCommit(ctx, testRepo, test.id, resolveRevisionOptions)
+				if err != nil {
+					if test.revisionNotFoundError {
+						if !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+							t.Errorf("%s: GetCommit: expected a RevisionNotFoundError, got %s", label, err)
+						}
+						return
+					}
+					t.Errorf("%s: GetCommit: %s", label, err)
+				}
+
+				if !CommitsEqual(commit, test.wantCommit) {
+					t.Errorf("%s: got commit == %+v, want %+v", label, commit, test.wantCommit)
+					return
+				}
+
+				// Test that trying to get a nonexistent commit returns RevisionNotFoundError.
+				if _, err := client.GetCommit(ctx, testRepo, NonExistentCommitID, resolveRevisionOptions); !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+					t.Errorf("%s: for nonexistent commit: got err %v, want RevisionNotFoundError", label, err)
+				}
+
+				if noEnsureRevision != test.noEnsureRevision {
+					t.Fatalf("Expected %t, got %t", test.noEnsureRevision, noEnsureRevision)
+				}
+			})
+		}
+	}
+
+	wantGitCommit := &gitdomain.Commit{
+		ID:        "b266c7e3ca00b1a17ad0b1449825d0854225c007",
+		Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+		Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
+		Message:   "bar",
+		Parents:   []api.CommitID{"ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
+	}
+	tests := map[string]testCase{
+		"git cmd with NoEnsureRevision false": {
+			gitCmds:          gitCommands,
+			id:               "b266c7e3ca00b1a17ad0b1449825d0854225c007",
+			wantCommit:       wantGitCommit,
+			noEnsureRevision: false,
+		},
+		"git cmd with NoEnsureRevision true": {
+			gitCmds:          gitCommands,
+			id:               "b266c7e3ca00b1a17ad0b1449825d0854225c007",
+			wantCommit:       wantGitCommit,
+			noEnsureRevision: true,
+		},
+	}
+	// Run basic tests w/o sub-repo permissions checker
+	runGetCommitTests(nil, tests)
+	checker := getTestSubRepoPermsChecker(fileWithoutAccess)
+	// Add test cases with file names for sub-repo permissions testing
+	tests["with sub-repo permissions and access to file"] = testCase{
+		gitCmds: gitCommandsWithFiles,
+		id:      "da50eed82c8ff3c17bb642000d8aad9d434283c1",
+		wantCommit: &gitdomain.Commit{
+			ID:        "da50eed82c8ff3c17bb642000d8aad9d434283c1",
+			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Message:   "commit1",
+		},
+		noEnsureRevision: true,
+	}
+	tests["with sub-repo permissions and NO access to file"] = testCase{
+		gitCmds:               gitCommandsWithFiles,
+		id:                    "ee7773505e98390e809cbf518b2a92e4748b0187",
+		wantCommit:            &gitdomain.Commit{},
+		noEnsureRevision:      true,
+		revisionNotFoundError: true,
+	}
+	// Run test w/ sub-repo permissions filtering
+	runGetCommitTests(checker, tests)
+}
+
+func TestRepository_Commits(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+
+	// TODO(sqs): test gitserver.CommitsOptions.Base
+
+	gitCommands := []string{
+		"git commit --allow-empty -m foo",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+	}
+	wantGitCommits := []*gitdomain.Commit{
+		{
+			ID:        "b266c7e3ca00b1a17ad0b1449825d0854225c007",
+			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+			Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
+			Message:   "bar",
+			Parents:   []api.CommitID{"ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
+		},
+		{
+			ID:        "ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8",
+			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Message:   "foo",
+			Parents:   nil,
+		},
+	}
+	tests := map[string]struct {
+		repo        api.RepoName
+		id          api.CommitID
+		wantCommits []*gitdomain.Commit
+		wantTotal   uint
+	}{
+		"git cmd": {
+			repo:        MakeGitRepository(t, gitCommands...),
+			id:          "b266c7e3ca00b1a17ad0b1449825d0854225c007",
+			wantCommits: wantGitCommits,
+			wantTotal:   2,
+		},
+	}
+	client := gitserver.NewTestClient(t).WithClientSource(source)
+	runCommitsTests := func(checker authz.SubRepoPermissionChecker) {
+		for label, test := range tests {
+			t.Run(label, func(t *testing.T) {
+				testCommits(ctx, label, test.repo, gitserver.CommitsOptions{Range: string(test.id)}, checker, test.wantCommits, t)
+
+				// Test that trying to get a nonexistent commit returns RevisionNotFoundError.
+				if _, err := client.Commits(ctx, test.repo, gitserver.CommitsOptions{Range: string(NonExistentCommitID)}); !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+					t.Errorf("%s: for nonexistent commit: got err %v, want RevisionNotFoundError", label, err)
+				}
+			})
+		}
+	}
+	runCommitsTests(nil)
+	checker := getTestSubRepoPermsChecker()
+	runCommitsTests(checker)
+}
+
+func TestCommits_SubRepoPerms(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+	gitCommands := []string{
+		"touch file1",
+		"git add file1",
+		"git commit -m commit1",
+		"touch file2",
+		"git add file2",
+		"touch file2.2",
+		"git add file2.2",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m commit2 --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+		"touch file3",
+		"git add file3",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m commit3 --author='a <a@a.com>' --date 2006-01-02T15:04:07Z",
+	}
+	repo := MakeGitRepository(t, gitCommands...)
+
+	tests := map[string]struct {
+		wantCommits   []*gitdomain.Commit
+		opt           gitserver.CommitsOptions
+		wantTotal     uint
+		noAccessPaths []string
+	}{
+		"if no read perms on at least one file in the commit should filter out commit": {
+			wantTotal: 2,
+			wantCommits: []*gitdomain.Commit{
+				{
+					ID:        "b96d097108fa49e339ca88bc97ab07f833e62131",
+					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
+					Message:   "commit2",
+					Parents:   []api.CommitID{"d38233a79e037d2ab8170b0d0bc0aa438473e6da"},
+				},
+				{
+					ID:        "d38233a79e037d2ab8170b0d0bc0aa438473e6da",
+					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+					Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+					Message:   "commit1",
+				},
+			},
+			noAccessPaths: []string{"file2", "file3"},
+		},
+		"sub-repo perms with path (w/ no access) specified should return no commits": {
+			wantTotal: 1,
+			opt: gitserver.CommitsOptions{
+				Path: "file2",
+			},
+			wantCommits:   []*gitdomain.Commit{},
+			noAccessPaths: []string{"file2", "file3"},
+		},
+		"sub-repo perms with path (w/ access) specified should return that commit": {
+			wantTotal: 1,
+			opt: gitserver.CommitsOptions{
+				Path: "file1",
+			},
+			wantCommits: []*gitdomain.Commit{
+				{
+					ID:        "d38233a79e037d2ab8170b0d0bc0aa438473e6da",
+					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+					Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+					Message:   "commit1",
+				},
+			},
+			noAccessPaths: []string{"file2", "file3"},
+		},
+	}
+
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			checker := getTestSubRepoPermsChecker(test.noAccessPaths...)
+			client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+			commits, err := client.Commits(ctx, repo, test.opt)
+			if err != nil {
+				t.Errorf("%s: Commits(): %s", label, err)
+				return
+			}
+
+			if len(commits) != len(test.wantCommits) {
+				t.Errorf("%s: got %d commits, want %d", label, len(commits), len(test.wantCommits))
+			}
+
+			checkCommits(t, commits, test.wantCommits)
+		})
+	}
+}
+
+func TestCommits_SubRepoPerms_ReturnNCommits(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+	gitCommands := []string{
+		"touch file1",
+		"git add file1",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:01Z git commit -m commit1 --author='a <a@a.com>' --date 2006-01-02T15:04:01Z",
+		"touch file2",
+		"git add file2",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:02Z git commit -m commit2 --author='a <a@a.com>' --date 2006-01-02T15:04:02Z",
+		"echo foo > file1",
+		"git add file1",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:03Z git commit -m commit3 --author='a <a@a.com>' --date 2006-01-02T15:04:03Z",
+		"echo asdf > file1",
+		"git add file1",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:04Z git commit -m commit4 --author='a <a@a.com>' --date 2006-01-02T15:04:04Z",
+		"echo bar > file1",
+		"git add file1",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit5 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"echo asdf2 > file2",
+		"git add file2",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit -m commit6 --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+		"echo bazz > file1",
+		"git add file1",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m commit7 --author='a <a@a.com>' --date 2006-01-02T15:04:07Z",
+		"echo bazz > file2",
+		"git add file2",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:08Z git commit -m commit8 --author='a <a@a.com>' --date 2006-01-02T15:04:08Z",
+	}
+
+	tests := map[string]struct {
+		repo          api.RepoName
+		wantCommits   []*gitdomain.Commit
+		opt           gitserver.CommitsOptions
+		wantTotal     uint
+		noAccessPaths []string
+	}{
+		"return the requested number of commits": {
+			repo:      MakeGitRepository(t, gitCommands...),
+			wantTotal: 3,
+			opt: gitserver.CommitsOptions{
+				N: 3,
+			},
+			wantCommits: []*gitdomain.Commit{
+				{
+					ID:        "61dbc35f719c53810904a2d359309d4e1e98a6be",
+					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
+					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
+					Message:   "commit7",
+					Parents:   []api.CommitID{"66566c8aa223f3e1b94ebe09e6cdb14c3a5bfb36"},
+				},
+				{
+					ID:        "2e6b2c94293e9e339f781b2a2f7172e15460f88c",
+					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+					Parents: []api.CommitID{
+						"9a7ec70986d657c4c86d6ac476f0c5181ece509a",
+					},
+					Message: "commit5",
+				},
+				{
+					ID:        "9a7ec70986d657c4c86d6ac476f0c5181ece509a",
+					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:04Z")},
+					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:04Z")},
+					Message:   "commit4",
+					Parents: []api.CommitID{
+						"f3fa8cf6ec56d0469402523385d6ca4b7cb222d8",
+					},
+				},
+			},
+			noAccessPaths: []string{"file2"},
+		},
+	}
+
+	for label, test := range tests {
+		t.Run(label, func(t *testing.T) {
+			checker := getTestSubRepoPermsChecker(test.noAccessPaths...)
+			client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+			commits, err := client.Commits(ctx, test.repo, test.opt)
+			if err != nil {
+				t.Errorf("%s: Commits(): %s", label, err)
+				return
+			}
+
+			if diff := cmp.Diff(test.wantCommits, commits); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestRepository_Commits_options(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, actor.FromUser(42))
+
+	gitCommands := []string{
+		"git commit --allow-empty -m foo",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:08Z git commit --allow-empty -m qux --author='a <a@a.com>' --date 2006-01-02T15:04:08Z",
+	}
+	wantGitCommits := []*gitdomain.Commit{
+		{
+			ID:        "b266c7e3ca00b1a17ad0b1449825d0854225c007",
+			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+			Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
+			Message:   "bar",
+			Parents:   []api.CommitID{"ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
+		},
+	}
+	wantGitCommits2 := []*gitdomain.Commit{
+		{
+			ID:        "ade564eba4cf904492fb56dcd287ac633e6e082c",
+			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:08Z")},
+			Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:08Z")},
+			Message:   "qux",
+			Parents:   []api.CommitID{"b266c7e3ca00b1a17ad0b1449825d0854225c007"},
+		},
+	}
+	tests := map[string]struct {
+		opt         gitserver.CommitsOptions
+		wantCommits []*gitdomain.Commit
+		wantTotal   uint
+	}{
+		"git cmd": {
+			opt:         gitserver.CommitsOptions{Range: "ade564eba4cf904492fb56dcd287ac633e6e082c", N: 1, Skip: 1},
+			wantCommits: wantGitCommits,
+			wantTotal:   1,
+		},
+		"git cmd Head": {
+			opt: gitserver.CommitsOptions{
+				Range: "b266c7e3ca00b1a17ad0b1449825d0854225c007...ade564eba4cf904492fb56dcd287ac633e6e082c",
+			},
+			wantCommits: wantGitCommits2,
+			wantTotal:   1,
+		},
+		"before": {
+			opt: gitserver.CommitsOptions{
+				Before: "2006-01-02T15:04:07Z",
+				Range:  "HEAD",
+				N:      1,
+			},
+			wantCommits: []*gitdomain.Commit{
+				{
+					ID:        "b266c7e3ca00b1a17ad0b1449825d0854225c007",
+					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
+					Message:   "bar",
+					Parents:   []api.CommitID{"ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
+				},
+			},
+			wantTotal: 1,
+		},
+	}
+	runCommitsTests := func(checker authz.SubRepoPermissionChecker) {
+		for label, test := range tests {
+			t.Run(label, func(t *testing.T) {
+				repo := MakeGitRepository(t, gitCommands...)
+				testCommits(ctx, label, repo, test.opt, checker, test.wantCommits, t)
+			})
+		}
+		// Added for awareness if this error message changes. Insights record last repo indexing and consider empty
+		// repos a success case.
+		subRepo := ""
+		if checker != nil {
+			subRepo = " sub repo enabled"
+		}
+		t.Run("empty repo"+subRepo, func(t *testing.T) {
+			repo := MakeGitRepository(t)
+			before := ""
+			after := time.Date(2022, 11, 11, 12, 10, 0, 4, time.UTC).Format(time.RFC3339)
+			client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+			_, err := client.Commits(ctx, repo, gitserver.CommitsOptions{N: 0, DateOrder: true, NoEnsureRevision: true, After: after, Before: before})
+			if err == nil {
+				t.Error("expected error, got nil")
+			}
+			wantErr := `exit status 128 (stderr: "fatal: your current branch 'master' does not have any commits yet\n")`
+			if err.Error() != wantErr {
+				t.Errorf("expected:%v got:%v", wantErr, err.Error())
+			}
+		})
+	}
+	runCommitsTests(nil)
+	checker := getTestSubRepoPermsChecker()
+	runCommitsTests(checker)
+}
+
+func TestRepository_Commits_options_firstParent(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), actor.FromUser(42))
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	client := gitserver.NewTestClient(t).WithClientSource(source)
+
+	// The commit on the feature branch is older than the merge, but newer than
+	// the last commit made on master before the merge.
+	repo := MakeGitRepository(t,
+		"GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m root --date 2006-01-02T15:04:05Z",
+		"git checkout -b feature",
+		"GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m feature --date 2006-01-02T15:04:07Z",
+		"git checkout master",
+		"GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit --allow-empty -m main --date 2006-01-02T15:04:06Z",
+		"GIT_COMMITTER_DATE=2006-01-02T15:04:09Z git merge --no-ff -m merge feature",
+	)
+
+	for _, tc := range []struct {
+		firstParent bool
+		want        gitdomain.Message
+	}{
+		{firstParent: false, want: "feature"},
+		{firstParent: true, want: "main"},
+	} {
+		commits, err := client.Commits(ctx, repo, gitserver.CommitsOptions{
+			Range:       "master",
+			Before:      "2006-01-02T15:04:08Z",
+			N:           1,
+			FirstParent: tc.firstParent,
+		})
+		require.NoError(t, err)
+		require.Len(t, commits, 1)
+		require.Equal(t, tc.want, commits[0].Message, "firstParent=%t", tc.firstParent)
+	}
+}
+
+func TestRepository_Commits_options_path(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+
+	gitCommands := []string{
+		"git commit --allow-empty -m commit1",
+		"touch file1",
+		"touch --date=2006-01-02T15:04:05Z file1 || touch -t " + Times[0] + " file1",
+		"git add file1",
+		"git commit -m commit2",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m commit3 --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+	}
+	wantGitCommits := []*gitdomain.Commit{
+		{
+			ID:        "546a3ef26e581624ef997cb8c0ba01ee475fc1dc",
+			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Message:   "commit2",
+			Parents:   []api.CommitID{"a04652fa1998a0a7d2f2f77ecb7021de943d3aab"},
+		},
+	}
+	tests := map[string]struct {
+		opt         gitserver.CommitsOptions
+		wantCommits []*gitdomain.Commit
+	}{
+		"git cmd Path 0": {
+			opt: gitserver.CommitsOptions{
+				Range: "master",
+				Path:  "doesnt-exist",
+			},
+			wantCommits: nil,
+		},
+		"git cmd Path 1": {
+			opt: gitserver.CommitsOptions{
+				Range: "master",
+				Path:  "file1",
+			},
+			wantCommits: wantGitCommits,
+		},
+		"git cmd non utf8": {
+			opt: gitserver.CommitsOptions{
+				Range:  "master",
+				Author: "a\xc0rn",
+			},
+			wantCommits: nil,
+		},
+	}
+
+	runCommitsTest := func(checker authz.SubRepoPermissionChecker) {
+		for label, test := range tests {
+			t.Run(label, func(t *testing.T) {
+				repo := MakeGitRepository(t, gitCommands...)
+				testCommits(ctx, label, repo, test.opt, checker, test.wantCommits, t)
+			})
+		}
+	}
+	runCommitsTest(nil)
+	checker := getTestSubRepoPermsChecker()
+	runCommitsTest(checker)
+}
+
+func TestRefDescriptions(t *testing.T) { // KEEP
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+	client := gitserver.NewTestClient(t).WithClientSource(source)
+	gitCommands := append(getGitCommandsWithFiles("file1", "file2"), "git checkout -b my-other-branch")
+	gitCommands = append(gitCommands, getGitCommandsWithFiles("file1-b2", "file2-b2")...)
+	gitCommands = append(gitCommands, "git checkout -b my-branch-no-access")
+	gitCommands = append(gitCommands, getGitCommandsWithFiles("file", "file-with-no-access")...)
+	repo := MakeGitRepository(t, gitCommands...)
+
+	makeBranch := func(name, createdDate string, isDefaultBranch bool) gitdomain.RefDescription {
+		return gitdomain.RefDescription{Name: name, Type: gitdomain.RefTypeBranch, IsDefaultBranch: isDefaultBranch, CreatedDate: mustParseDate(createdDate, t)}
+	}
+
+	t.Run("basic", func(t *testing.T) {
+		refDescriptions, err := client.RefDescriptions(ctx, repo)
+		if err != nil {
+			t.Errorf("err calling RefDescriptions: %s", err)
+		}
+		expectedRefDescriptions := map[string][]gitdomain.RefDescription{
+			"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": {makeBranch("master", "2006-01-02T15:04:05Z", false)},
+			"9d7a382983098eed6cf911bd933dfacb13116e42": {makeBranch("my-other-branch", "2006-01-02T15:04:05Z", false)},
+			"7cf006d0599531db799c08d3b00d7fd06da33015": {makeBranch("my-branch-no-access", "2006-01-02T15:04:05Z", true)},
+		}
+		if diff := cmp.Diff(expectedRefDescriptions, refDescriptions); diff != "" {
+			t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("with sub-repo enabled", func(t *testing.T) {
+		checker := getTestSubRepoPermsChecker("file-with-no-access")
+		client2 := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+		refDescriptions, err := client2.RefDescriptions(ctx, repo)
+		if err != nil {
+			t.Errorf("err calling RefDescriptions: %s", err)
+		}
+		expectedRefDescriptions := map[string][]gitdomain.RefDescription{
+			"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": {makeBranch("master", "2006-01-02T15:04:05Z", false)},
+			"9d7a382983098eed6cf911bd933dfacb13116e42": {makeBranch("my-other-branch", "2006-01-02T15:04:05Z", false)},
+		}
+		if diff := cmp.Diff(expectedRefDescriptions, refDescriptions); diff != "" {
+			t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestCommitsUniqueToBranch(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+	gitCommands := append([]string{"git checkout -b my-branch"}, getGitCommandsWithFiles("file1", "file2")...)
+	gitCommands = append(gitCommands, getGitCommandsWithFiles("file3", "file-with-no-access")...)
+	repo := MakeGitRepository(t, gitCommands...)
+
+	t.Run("basic", func(t *testing.T) {
+		client := gitserver.NewTestClient(t).WithClientSource(source)
+		commits, err := client.CommitsUniqueToBranch(ctx, repo, "my-branch", true, &time.Time{})
+		if err != nil {
+			t.Errorf("err calling RefDescriptions: %s", err)
+		}
+		expectedCommits := map[string]time.Time{
+			"2775e60f523d3151a2a34ffdc659f500d0e73022": *mustParseDate("2006-01-02T15:04:05-00:00", t),
+			"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": *mustParseDate("2006-01-02T15:04:05-00:00", t),
+			"791ce7cd8ca2d855e12f47f8692a62bc42477edc": *mustParseDate("2006-01-02T15:04:05-00:00", t),
+			"d38233a79e037d2ab8170b0d0bc0aa438473e6da": *mustParseDate("2006-01-02T15:04:05-00:00", t),
+		}
+		if diff := cmp.Diff(expectedCommits, commits); diff != "" {
+			t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("with sub-repo enabled", func(t *testing.T) {
+		checker := getTestSubRepoPermsChecker("file-with-no-access")
+		client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+		commits, err := client.CommitsUniqueToBranch(ctx, repo, "my-branch", true, &time.Time{})
+		if err != nil {
+			t.Errorf("err calling RefDescriptions: %s", err)
+		}
+		expectedCommits := map[string]time.Time{
+			"2775e60f523d3151a2a34ffdc659f500d0e73022": *mustParseDate("2006-01-02T15:04:05-00:00", t),
+			"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": *mustParseDate("2006-01-02T15:04:05-00:00", t),
+			"d38233a79e037d2ab8170b0d0bc0aa438473e6da": *mustParseDate("2006-01-02T15:04:05-00:00", t),
+		}
+		if diff := cmp.Diff(expectedCommits, commits); diff != "" {
+			t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestCommitDate(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := actor.WithActor(context.Background(), &actor.Actor{
+		UID: 1,
+	})
+	gitCommands := getGitCommandsWithFiles("file1", "file2")
+	repo := MakeGitRepository(t, gitCommands...)
+
+	t.Run("basic", func(t *testing.T) {
+		client := gitserver.NewTestClient(t).WithClientSource(source)
+		_, date, commitExists, err := client.CommitDate(ctx, repo, "d38233a79e037d2ab8170b0d0bc0aa438473e6da")
+		if err != nil {
+			t.Errorf("error fetching CommitDate: %s", err)
+		}
+		if !commitExists {
+			t.Errorf("commit should exist")
+		}
+		if !date.Equal(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)) {
+			t.Errorf("unexpected date: %s", date)
+		}
+	})
+
+	t.Run("with sub-repo permissions enabled", func(t *testing.T) {
+		checker := getTestSubRepoPermsChecker("file1")
+		client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+		_, date, commitExists, err := client.CommitDate(ctx, repo, "d38233a79e037d2ab8170b0d0bc0aa438473e6da")
+		if err != nil {
+			t.Errorf("error fetching CommitDate: %s", err)
+		}
+		if commitExists {
+			t.Errorf("expect commit to not exist since the user doesn't have access")
+		}
+		if !date.IsZero() {
+			t.Errorf("expected date to be empty, got: %s", date)
+		}
+	})
+}
+
+func TestRepository_Branches_IncludeCommit(t *testing.T) {
+	gitCommands := []string{
+		"git commit --allow-empty -m foo0",
+		"git checkout -b b0",
+		"GIT_COMMITTER_NAME=b GIT_COMMITTER_EMAIL=b@b.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit --allow-empty -m foo1 --author='b <b@b.com>' --date 2006-01-02T15:04:06Z",
+	}
+	wantBranches := []*gitdomain.Branch{
+		{
+			Name: "b0", Head: "c4a53701494d1d788b1ceeb8bf32e90224962473",
+			Commit: &gitdomain.Commit{
+				ID:        "c4a53701494d1d788b1ceeb8bf32e90224962473",
+				Author:    gitdomain.Signature{Name: "b", Email: "b@b.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+				Committer: &gitdomain.Signature{Name: "b", Email: "b@b.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
+				Message:   "foo1",
+				Parents:   []api.CommitID{"a3c1537db9797215208eec56f8e7c9c37f8358ca"},
+			},
+		},
+		{
+			Name: "master", Head: "a3c1537db9797215208eec56f8e7c9c37f8358ca",
+			Commit: &gitdomain.Commit{
+				ID:        "a3c1537db9797215208eec56f8e7c9c37f8358ca",
+				Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+				Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: gitserver.MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+				Message:   "foo0",
+				Parents:   nil,
+			},
+		},
+	}
+
+	testBranches(t, gitCommands, wantBranches, gitserver.BranchesOptions{IncludeCommit: true})
+}
+
+func testBranches(t *testing.T, gitCommands []string, wantBranches []*gitdomain.Branch, options gitserver.BranchesOptions) {
+	t.Helper()
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+
+	repo := MakeGitRepository(t, gitCommands...)
+	gotBranches, err := gitserver.NewTestClient(t).WithClientSource(source).ListBranches(context.Background(), repo, options)
+	require.Nil(t, err)
+
+	sort.Sort(gitdomain.Branches(wantBranches))
+	sort.Sort(gitdomain.Branches(gotBranches))
+
+	if diff := cmp.Diff(wantBranches, gotBranches); diff != "" {
+		t.Fatalf("Branch mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func testCommits(ctx context.Context, label string, repo api.RepoName, opt gitserver.CommitsOptions, checker authz.SubRepoPermissionChecker, wantCommits []*gitdomain.Commit, t *testing.T) {
+	t.Helper()
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+	commits, err := client.Commits(ctx, repo, opt)
+	if err != nil {
+		t.Errorf("%s: Commits(): %s", label, err)
+		return
+	}
+
+	if len(commits) != len(wantCommits) {
+		t.Errorf("%s: got %d commits, want %d", label, len(commits), len(wantCommits))
+	}
+	checkCommits(t, commits, wantCommits)
+}
+
+func checkCommits(t *testing.T, commits, wantCommits []*gitdomain.Commit) {
+	t.Helper()
+	for i := 0; i < len(commits) || i < len(wantCommits); i++ {
+		var gotC, wantC *gitdomain.Commit
+		if i < len(commits) {
+			gotC = commits[i]
+		}
+		if i < len(wantCommits) {
+			wantC = wantCommits[i]
+		}
+		if diff := cmp.Diff(gotC, wantC); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+}
+
+func CommitsEqual(a, b *gitdomain.Commit) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	if a.Author.Date != b.Author.Date {
+		return false
+	}
+	a.Author.Date = b.Author.Date
+	if ac, bc := a.Committer, b.Committer; ac != nil && bc != nil {
+		if ac.Date != bc.Date {
+			return false
+		}
+		ac.Date = bc.Date
+	} else if !(ac == nil && bc == nil) {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func getGitCommandsWithFileLists(filenamesPerCommit ...[]string) []string {
+	cmds := make([]string, 0, len(filenamesPerCommit)*3)
+	for i, filenames := range filenamesPerCommit {
+		for _, fn := range filenames {
+			cmds = append(cmds,
+				fmt.Sprintf("touch %s", fn),
+				fmt.Sprintf("echo my_content_%d > %s", i, fn),
+				fmt.Sprintf("git add %s", fn))
+		}
+		cmds = append(cmds,
+			fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05=%dZ git commit -m commit%d --author='a <a@a.com>' --date 2006-01-02T15:04:0%dZ", i, i, i))
+	}
+	return cmds
+}
+
+func makeGitCommit(commitMessage string, seconds int) string {
+	return fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05=%dZ git commit -m %s --author='a <a@a.com>' --date 2006-01-02T15:04:0%dZ", seconds, commitMessage, seconds)
+}
+
+func usePermissionsForFilePermissionsFunc(m *authz.MockSubRepoPermissionChecker) {
+	m.FilePermissionsFuncFunc.SetDefaultHook(func(ctx context.Context, userID int32, repo api.RepoName) (authz.FilePermissionFunc, error) {
+		return func(path string) (authz.Perms, error) {
+			return m.Permissions(ctx, userID, authz.RepoContent{Repo: repo, Path: path})
+		}, nil
+	})
+}
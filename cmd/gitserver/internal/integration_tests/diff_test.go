@@ -0,0 +1,116 @@
+package inttests
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	godiff "github.com/sourcegraph/go-diff/diff"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+func TestDiffWithSubRepoFiltering(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	ctx := context.Background()
+	ctx = actor.WithActor(ctx, &actor.Actor{
+		UID: 1,
+	})
+
+	cmds := getGitCommandsWithFileLists([]string{"file0"}, []string{"file1", "file1.1"}, []string{"file2"}, []string{"file3", "file3.3"})
+	checker := getTestSubRepoPermsChecker("file1.1", "file2")
+	testCases := []struct {
+		label               string
+		extraGitCommands    []string
+		expectedDiffFiles   []string
+		expectedFileStat    *godiff.Stat
+		rangeOverAllCommits bool
+	}{
+		{
+			label:               "adding files",
+			expectedDiffFiles:   []string{"file1", "file3", "file3.3"},
+			expectedFileStat:    &godiff.Stat{Added: 3},
+			rangeOverAllCommits: true,
+		},
+		{
+			label: "changing filename",
+			extraGitCommands: []string{
+				"mv file1.1 file_can_access",
+				"git add file_can_access",
+				makeGitCommit("rename", 7),
+			},
+			expectedDiffFiles: []string{"file_can_access"},
+			expectedFileStat:  &godiff.Stat{Added: 1},
+		},
+		{
+			label: "file modified",
+			extraGitCommands: []string{
+				"echo new_file_content > file2",
+				"echo more_new_file_content > file1",
+				"git add file2",
+				"git add file1",
+				makeGitCommit("edit_files", 7),
+			},
+			expectedDiffFiles: []string{"file1"}, // file2 is updated but user doesn't have access
+			expectedFileStat:  &godiff.Stat{Changed: 1},
+		},
+		{
+			label: "diff for commit w/ no access returns empty result",
+			extraGitCommands: []string{
+				"echo new_file_content > file2",
+				"git add file2",
+				makeGitCommit("no_access", 7),
+			},
+			expectedDiffFiles: []string{},
+			expectedFileStat:  &godiff.Stat{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.label, func(t *testing.T) {
+			repo := MakeGitRepository(t, append(cmds, tc.extraGitCommands...)...)
+			c := gitserver.NewTestClient(t).WithClientSource(source)
+			commits, err := c.Commits(ctx, repo, gitserver.CommitsOptions{})
+			if err != nil {
+				t.Fatalf("err fetching commits: %s", err)
+			}
+			baseCommit := commits[1]
+			headCommit := commits[0]
+			if tc.rangeOverAllCommits {
+				baseCommit = commits[len(commits)-1]
+			}
+
+			c = c.WithChecker(checker)
+			iter, err := c.Diff(ctx, gitserver.DiffOptions{Base: string(baseCommit.ID), Head: string(headCommit.ID), Repo: repo})
+			if err != nil {
+				t.Fatalf("error fetching diff: %s", err)
+			}
+			defer iter.Close()
+
+			stat := &godiff.Stat{}
+			fileNames := make([]string, 0, 3)
+			for {
+				file, err := iter.Next()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Error(err)
+				}
+
+				fileNames = append(fileNames, file.NewName)
+
+				fileStat := file.Stat()
+				stat.Added += fileStat.Added
+				stat.Changed += fileStat.Changed
+				stat.Deleted += fileStat.Deleted
+			}
+			if diff := cmp.Diff(fileNames, tc.expectedDiffFiles); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(stat, tc.expectedFileStat); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -61,6 +61,7 @@ func InitGitserver() {
 	db := dbmocks.NewMockDB()
 	db.GitserverReposFunc.SetDefaultReturn(dbmocks.NewMockGitserverRepoStore())
 	db.FeatureFlagsFunc.SetDefaultReturn(dbmocks.NewMockFeatureFlagStore())
+	db.ExternalServicesFunc.SetDefaultReturn(dbmocks.NewMockExternalServiceStore())
 
 	r := dbmocks.NewMockRepoStore()
 	r.GetByNameFunc.SetDefaultHook(func(ctx context.Context, repoName api.RepoName) (*types.Repo, error) {
@@ -71,6 +72,14 @@ func InitGitserver() {
 			},
 		}, nil
 	})
+	// The repositories created by InitGitRepository are the ones known to
+	// the instance.
+	r.GetFirstRepoNameByCloneURLFunc.SetDefaultHook(func(ctx context.Context, cloneURL string) (api.RepoName, error) {
+		if filepath.Dir(cloneURL) != filepath.Join(root, "remotes") {
+			return "", nil
+		}
+		return api.RepoName(filepath.Base(cloneURL)), nil
+	})
 	db.ReposFunc.SetDefaultReturn(r)
 
 	s := server.Server{
@@ -83,11 +92,12 @@ func InitGitserver() {
 		GetVCSSyncer: func(ctx context.Context, name api.RepoName) (vcssyncer.VCSSyncer, error) {
 			return vcssyncer.NewGitRepoSyncer(logger, wrexec.NewNoOpRecordingCommandFactory()), nil
 		},
-		GlobalBatchLogSemaphore: semaphore.NewWeighted(32),
-		DB:                      db,
-		RecordingCommandFactory: wrexec.NewNoOpRecordingCommandFactory(),
-		Locker:                  server.NewRepositoryLocker(),
-		RPSLimiter:              ratelimit.NewInstrumentedLimiter("GitserverTest", rate.NewLimiter(100, 10)),
+		GlobalBatchLogSemaphore:        semaphore.NewWeighted(32),
+		DB:                             db,
+		RecordingCommandFactory:        wrexec.NewNoOpRecordingCommandFactory(),
+		Locker:                         server.NewRepositoryLocker(),
+		BlockExecOfNewTypedRPCCommands: true,
+		RPSLimiter:                     ratelimit.NewInstrumentedLimiter("GitserverTest", rate.NewLimiter(100, 10)),
 	}
 
 	grpcServer := defaults.NewServer(logger)
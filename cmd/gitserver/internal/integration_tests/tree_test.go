@@ -3,14 +3,19 @@ package inttests
 import (
 	"bytes"
 	"context"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"sort"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -20,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -349,6 +355,9 @@ func TestRepository_FileSystem_gitSubmodules(t *testing.T) {
 			if si.CommitID != submodCommit {
 				t.Errorf("%s: (Submodule).CommitID: got %q, want %q", label, si.CommitID, submodCommit)
 			}
+			if want := api.RepoName(filepath.Base(submodDir)); si.Repo != want {
+				t.Errorf("%s: (Submodule).Repo: got %q, want %q", label, si.Repo, want)
+			}
 		}
 
 		// Check the submodule fs.FileInfo both when it's returned by
@@ -423,3 +432,211 @@ func TestReadDir_SubRepoFiltering(t *testing.T) {
 	assert.Equal(t, "file1", files[0].Name())
 	assert.False(t, files[0].IsDir())
 }
+
+func TestReadBlobs(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+
+	repo := MakeGitRepository(t,
+		"echo abcd > file1",
+		"touch empty",
+		"git add file1 empty",
+		"git commit -m commit1",
+	)
+	ctx := context.Background()
+	client := gitserver.NewTestClient(t).WithClientSource(source)
+	commitID, err := client.ResolveRevision(ctx, repo, "HEAD", gitserver.ResolveRevisionOptions{})
+	require.NoError(t, err)
+
+	fis, err := client.ReadDir(ctx, repo, commitID, "", false)
+	require.NoError(t, err)
+	require.Len(t, fis, 2)
+	oids := make([]gitdomain.OID, 0, len(fis))
+	for _, fi := range fis {
+		oids = append(oids, fi.Sys().(gitdomain.ObjectInfo).OID())
+	}
+
+	read := func(oids []gitdomain.OID) (map[gitdomain.OID]string, error) {
+		blobs := make(map[gitdomain.OID]string)
+		err := client.ReadBlobs(ctx, repo, oids, func(oid gitdomain.OID, size int64, r io.Reader) error {
+			data, err := io.ReadAll(r)
+			require.Equal(t, size, int64(len(data)))
+			blobs[oid] = string(data)
+			return err
+		})
+		return blobs, err
+	}
+
+	blobs, err := read(oids)
+	require.NoError(t, err)
+	require.Equal(t, map[gitdomain.OID]string{oids[0]: "", oids[1]: "abcd\n"}, blobs)
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := read([]gitdomain.OID{oids[0], {1}})
+		require.True(t, errors.HasType(err, &gitdomain.RevisionNotFoundError{}), err)
+	})
+
+	t.Run("sub-repo permissions", func(t *testing.T) {
+		checker := authz.NewMockSubRepoPermissionChecker()
+		checker.EnabledFunc.SetDefaultReturn(true)
+		checker.EnabledForRepoFunc.SetDefaultReturn(true, nil)
+		ctx := actor.WithActor(ctx, &actor.Actor{UID: 1})
+		err := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker).ReadBlobs(ctx, repo, oids, func(gitdomain.OID, int64, io.Reader) error { return nil })
+		require.Error(t, err)
+	})
+}
+
+func TestRepository_FileSystem_Symlinks(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+
+	gitCommands := []string{
+		"touch file1",
+		"mkdir dir1",
+		"ln -s file1 link1",
+		"ln -s ../file1 dir1/link2",
+		"touch --date=2006-01-02T15:04:05Z file1 link1 dir1/link2 || touch -t " + Times[0] + " file1 link1 dir1/link2",
+		"git add link1 file1 dir1/link2",
+		"git commit -m commit1",
+	}
+
+	// map of path to size of content
+	symlinks := map[string]int64{
+		"link1":      5, // file1
+		"dir1/link2": 8, // ../file1
+	}
+
+	repo := MakeGitRepository(t, gitCommands...)
+
+	client := gitserver.NewTestClient(t).WithClientSource(source)
+
+	ctx := context.Background()
+
+	commitID, err := client.ResolveRevision(ctx, repo, "HEAD", gitserver.ResolveRevisionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// file1 should be a file.
+	file1Info, err := client.Stat(ctx, repo, commitID, "file1")
+	if err != nil {
+		t.Fatalf("fs.Stat(file1): %s", err)
+	}
+	if !file1Info.Mode().IsRegular() {
+		t.Errorf("file1 Stat !IsRegular (mode: %o)", file1Info.Mode())
+	}
+
+	checkSymlinkFileInfo := func(name string, link fs.FileInfo) {
+		t.Helper()
+		if link.Mode()&os.ModeSymlink == 0 {
+			t.Errorf("link mode is not symlink (mode: %o)", link.Mode())
+		}
+		if link.Name() != name {
+			t.Errorf("got link.Name() == %q, want %q", link.Name(), name)
+		}
+	}
+
+	// Check symlinks are links
+	for symlink := range symlinks {
+		fi, err := client.Stat(ctx, repo, commitID, symlink)
+		if err != nil {
+			t.Fatalf("fs.Stat(%s): %s", symlink, err)
+		}
+		if runtime.GOOS != "windows" {
+			// TODO(alexsaveliev) make it work on Windows too
+			checkSymlinkFileInfo(symlink, fi)
+		}
+	}
+
+	// Also check the FileInfo returned by ReadDir to ensure it's
+	// consistent with the FileInfo returned by lStat.
+	entries, err := client.ReadDir(ctx, repo, commitID, ".", false)
+	if err != nil {
+		t.Fatalf("fs.ReadDir(.): %s", err)
+	}
+	found := false
+	for _, entry := range entries {
+		if entry.Name() == "link1" {
+			found = true
+			if runtime.GOOS != "windows" {
+				checkSymlinkFileInfo("link1", entry)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("readdir did not return link1")
+	}
+
+	for symlink, size := range symlinks {
+		fi, err := client.Stat(ctx, repo, commitID, symlink)
+		if err != nil {
+			t.Fatalf("fs.Stat(%s): %s", symlink, err)
+		}
+		if fi.Mode()&fs.ModeSymlink == 0 {
+			t.Errorf("%s Stat is not a symlink (mode: %o)", symlink, fi.Mode())
+		}
+		if fi.Name() != symlink {
+			t.Errorf("got Name %q, want %q", fi.Name(), symlink)
+		}
+		if fi.Size() != size {
+			t.Errorf("got %s Size %d, want %d", symlink, fi.Size(), size)
+		}
+	}
+}
+
+func TestStat(t *testing.T) {
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+
+	gitCommands := []string{
+		"mkdir dir1",
+		"touch dir1/file1",
+		"git add dir1/file1",
+		"git commit -m commit1",
+	}
+
+	repo := MakeGitRepository(t, gitCommands...)
+	checker := authz.NewMockSubRepoPermissionChecker()
+	// Start disabled
+	checker.EnabledFunc.SetDefaultHook(func() bool {
+		return false
+	})
+	client := gitserver.NewTestClient(t).WithClientSource(source).WithChecker(checker)
+
+	ctx := context.Background()
+
+	commitID, err := client.ResolveRevision(ctx, repo, "HEAD", gitserver.ResolveRevisionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileInfo, err := client.Stat(ctx, repo, commitID, "dir1/file1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "dir1/file1"
+	if diff := cmp.Diff(want, fileInfo.Name()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	ctx = actor.WithActor(ctx, &actor.Actor{
+		UID: 1,
+	})
+
+	// With filtering
+	checker.EnabledFunc.SetDefaultHook(func() bool {
+		return true
+	})
+	checker.PermissionsFunc.SetDefaultHook(func(ctx context.Context, i int32, content authz.RepoContent) (authz.Perms, error) {
+		if strings.HasPrefix(content.Path, "dir2") {
+			return authz.Read, nil
+		}
+		return authz.None, nil
+	})
+	usePermissionsForFilePermissionsFunc(checker)
+	_, err = client.Stat(ctx, repo, commitID, "dir1/file1")
+	if err == nil {
+		t.Fatal(err)
+	}
+	want = "ls-tree dir1/file1: file does not exist"
+	if diff := cmp.Diff(want, err.Error()); diff != "" {
+		t.Fatal(diff)
+	}
+}
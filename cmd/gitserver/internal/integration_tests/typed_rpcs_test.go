@@ -0,0 +1,202 @@
+package inttests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var typedRPCsGitCommands = []string{
+	"echo -n 'line1\n' > file1",
+	"git add file1",
+	"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit1 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	"git checkout -b feature",
+	"echo 'line2' >> file1",
+	"GIT_COMMITTER_NAME=b GIT_COMMITTER_EMAIL=b@b.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit -am commit2 --author='b <b@b.com>' --date 2006-01-02T15:04:06Z",
+	"git checkout master",
+	"mkdir dir",
+	"echo -n 'infile2' > dir/file2",
+	"git add dir/file2",
+	"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m commit3 --author='a <a@a.com>' --date 2006-01-02T15:04:07Z",
+}
+
+func TestTypedRPCs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	repo := MakeGitRepository(t, typedRPCsGitCommands...)
+	source := gitserver.NewTestClientSource(t, GitserverAddresses)
+	client := gitserver.NewTestClient(t).WithClientSource(source)
+
+	resolve := func(t *testing.T, spec string) api.CommitID {
+		t.Helper()
+		commit, err := client.ResolveRevision(ctx, repo, spec, gitserver.ResolveRevisionOptions{})
+		require.NoError(t, err)
+		return commit
+	}
+	master := resolve(t, "master")
+	feature := resolve(t, "feature")
+	first := resolve(t, "master~1")
+
+	t.Run("MergeBase", func(t *testing.T) {
+		base, err := client.MergeBase(ctx, repo, master, feature)
+		require.NoError(t, err)
+		assert.Equal(t, first, base)
+
+		_, err = client.MergeBase(ctx, repo, master, "notfound")
+		assert.True(t, errors.HasType(err, &gitdomain.RevisionNotFoundError{}), "got %v", err)
+	})
+
+	t.Run("StreamBlameFile", func(t *testing.T) {
+		hr, err := client.StreamBlameFile(ctx, repo, "file1", &gitserver.BlameOptions{NewestCommit: feature})
+		require.NoError(t, err)
+		defer hr.Close()
+
+		var hunks []*gitdomain.Hunk
+		for {
+			hunk, err := hr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			hunks = append(hunks, hunk)
+		}
+		require.Len(t, hunks, 2)
+		authors := map[string]int{}
+		for _, hunk := range hunks {
+			authors[hunk.Author.Name] = hunk.StartLine
+		}
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, authors)
+
+		readHunks := func(t *testing.T, opt *gitserver.BlameOptions) []*gitdomain.Hunk {
+			t.Helper()
+			hr, err := client.StreamBlameFile(ctx, repo, "file1", opt)
+			require.NoError(t, err)
+			defer hr.Close()
+			var hunks []*gitdomain.Hunk
+			for {
+				hunk, err := hr.Read()
+				if errors.Is(err, io.EOF) {
+					return hunks
+				}
+				require.NoError(t, err)
+				hunks = append(hunks, hunk)
+			}
+		}
+
+		// An empty commit blames HEAD, where file1 only has the first line.
+		hunks = readHunks(t, nil)
+		require.Len(t, hunks, 1)
+		assert.Equal(t, first, hunks[0].CommitID)
+		assert.Equal(t, "commit1", hunks[0].Message)
+
+		// A zero end line blames up to the end of the file.
+		hunks = readHunks(t, &gitserver.BlameOptions{NewestCommit: feature, StartLine: 2})
+		require.Len(t, hunks, 1)
+		assert.Equal(t, 2, hunks[0].StartLine)
+		assert.Equal(t, "b", hunks[0].Author.Name)
+
+		hr, err = client.StreamBlameFile(ctx, repo, "notafile", &gitserver.BlameOptions{NewestCommit: feature})
+		require.NoError(t, err)
+		_, err = hr.Read()
+		assert.True(t, errors.Is(err, os.ErrNotExist), "got %v", err)
+		hr.Close()
+
+		hr, err = client.StreamBlameFile(ctx, repo, "file1", &gitserver.BlameOptions{NewestCommit: "notfound"})
+		require.NoError(t, err)
+		_, err = hr.Read()
+		assert.True(t, errors.HasType(err, &gitdomain.RevisionNotFoundError{}), "got %v", err)
+		hr.Close()
+	})
+
+	t.Run("LsFiles", func(t *testing.T) {
+		files, err := client.LsFiles(ctx, repo, master)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/file2", "file1"}, files)
+
+		files, err = client.LsFiles(ctx, repo, master, gitdomain.PathspecLiteral("dir/file2"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dir/file2"}, files)
+	})
+
+	t.Run("Commits", func(t *testing.T) {
+		commits, err := client.Commits(ctx, repo, gitserver.CommitsOptions{Range: string(feature), Path: "file1"})
+		require.NoError(t, err)
+		require.Len(t, commits, 2)
+		assert.Equal(t, "commit2", string(commits[0].Message))
+		assert.Equal(t, "commit1", string(commits[1].Message))
+
+		_, err = client.Commits(ctx, repo, gitserver.CommitsOptions{Range: "notfound", NoEnsureRevision: true})
+		assert.True(t, errors.HasType(err, &gitdomain.RevisionNotFoundError{}), "got %v", err)
+	})
+
+	t.Run("DiffSymbols", func(t *testing.T) {
+		changes, err := client.DiffSymbols(ctx, repo, first, master)
+		require.NoError(t, err)
+		assert.Equal(t, []gitdomain.PathStatus{{Path: "dir/file2", Status: gitdomain.AddedAMD}}, changes)
+
+		changes, err = client.DiffSymbols(ctx, repo, master, feature)
+		require.NoError(t, err)
+		assert.Equal(t, []gitdomain.PathStatus{
+			{Path: "dir/file2", Status: gitdomain.DeletedAMD},
+			{Path: "file1", Status: gitdomain.ModifiedAMD},
+		}, changes)
+	})
+
+	t.Run("ContributorCount", func(t *testing.T) {
+		counts, err := client.ContributorCount(ctx, repo, gitserver.ContributorOptions{Range: string(feature)})
+		require.NoError(t, err)
+		assert.Equal(t, []*gitdomain.ContributorCount{
+			{Name: "a", Email: "a@a.com", Count: 1},
+			{Name: "b", Email: "b@b.com", Count: 1},
+		}, counts)
+	})
+
+	t.Run("ReadDir", func(t *testing.T) {
+		fis, err := client.ReadDir(ctx, repo, master, "", true)
+		require.NoError(t, err)
+		var entries []string
+		for _, fi := range fis {
+			entries = append(entries, fmt.Sprintf("%s %s %d", fi.Name(), fi.Mode(), fi.Size()))
+		}
+		assert.Equal(t, []string{
+			"dir d--------- 0",
+			"dir/file2 -rw-r--r-- 7",
+			"file1 -rw-r--r-- 6",
+		}, entries)
+
+		_, err = client.ReadDir(ctx, repo, master, "notadir", false)
+		assert.True(t, os.IsNotExist(err), "got %v", err)
+	})
+
+	t.Run("Exec rejects commands with a dedicated RPC", func(t *testing.T) {
+		grpcClient, err := source.ClientForRepo(ctx, "", repo)
+		require.NoError(t, err)
+
+		for _, args := range [][][]byte{
+			{[]byte("blame"), []byte("--porcelain"), []byte(master), []byte("--"), []byte("file1")},
+			{[]byte("cat-file"), []byte("--batch")},
+			{[]byte("log"), []byte(gitdomain.CommitLogFormat), []byte(master)},
+			{[]byte("ls-tree"), []byte("--long"), []byte("--full-name"), []byte("-z"), []byte(master)},
+			{[]byte("diff"), []byte("-z"), []byte("--name-status"), []byte("--no-renames"), []byte(master), []byte(master)},
+		} {
+			stream, err := grpcClient.Exec(ctx, &proto.ExecRequest{Repo: string(repo), Args: args})
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), "got %v", err)
+		}
+	})
+}
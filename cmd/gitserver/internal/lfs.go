@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"io"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
+)
+
+// printsSingleBlob returns true if args are the arguments of a git command
+// that prints the contents of a single blob, as used to read files.
+func printsSingleBlob(args []string) bool {
+	switch {
+	case len(args) == 2 && args[0] == "show":
+		// git show <commit>:<path>
+		return strings.Contains(args[1], ":")
+	case len(args) == 3 && args[0] == "cat-file" && args[1] == "-p":
+		// git cat-file -p <oid>
+		return true
+	}
+	return false
+}
+
+// lfsPointerWriter replaces the output of a command that prints a single
+// blob with the contents of the Git LFS object it points to, if the blob is a
+// Git LFS pointer and the object was fetched. All other output is written
+// unchanged.
+//
+// Output is held back until it is either too large to be a pointer or Flush
+// is called.
+type lfsPointerWriter struct {
+	w   io.Writer
+	dir common.GitDir
+
+	buf []byte
+	// passthrough is true once the output is known not to be a pointer.
+	passthrough bool
+}
+
+func (lw *lfsPointerWriter) Write(p []byte) (int, error) {
+	if lw.passthrough {
+		return lw.w.Write(p)
+	}
+	lw.buf = append(lw.buf, p...)
+	if len(lw.buf) < git.LFSPointerMaxSize {
+		return len(p), nil
+	}
+	lw.passthrough = true
+	buf := lw.buf
+	lw.buf = nil
+	if _, err := lw.w.Write(buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes the output that was held back, or the contents of the Git LFS
+// object it points to. It must be called once the command exited.
+func (lw *lfsPointerWriter) Flush() error {
+	buf := lw.buf
+	lw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if p, ok := git.ParseLFSPointer(buf); ok {
+		if f, err := git.OpenLFSObject(lw.dir, p); err == nil {
+			defer f.Close()
+			_, err := io.Copy(lw.w, f)
+			return err
+		}
+	}
+	_, err := lw.w.Write(buf)
+	return err
+}
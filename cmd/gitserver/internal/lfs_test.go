@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
+	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
+)
+
+func TestPrintsSingleBlob(t *testing.T) {
+	assert.True(t, printsSingleBlob([]string{"show", "deadbeef:README.md"}))
+	assert.True(t, printsSingleBlob([]string{"cat-file", "-p", "deadbeef"}))
+	assert.False(t, printsSingleBlob([]string{"show", "deadbeef"}))
+	assert.False(t, printsSingleBlob([]string{"archive", "--format=tar", "HEAD"}))
+	assert.False(t, printsSingleBlob([]string{"cat-file", "--batch"}))
+}
+
+func TestLFSPointerWriter(t *testing.T) {
+	dir := common.GitDir(t.TempDir())
+	const oid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+	path := git.LFSObjectPath(dir, oid)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("hello world\n"), 0o644))
+
+	write := func(chunks ...string) string {
+		t.Helper()
+		var buf bytes.Buffer
+		lw := &lfsPointerWriter{w: &buf, dir: dir}
+		for _, chunk := range chunks {
+			n, err := lw.Write([]byte(chunk))
+			require.NoError(t, err)
+			require.Equal(t, len(chunk), n)
+		}
+		require.NoError(t, lw.Flush())
+		return buf.String()
+	}
+
+	pointer := fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, len("hello world\n"))
+	assert.Equal(t, "hello world\n", write(pointer[:10], pointer[10:]))
+
+	// Pointers to objects that weren't fetched are returned unchanged.
+	missing := strings.Replace(pointer, oid, strings.Repeat("0", 64), 1)
+	assert.Equal(t, missing, write(missing))
+	sizeMismatch := strings.Replace(pointer, "size 12", "size 13", 1)
+	assert.Equal(t, sizeMismatch, write(sizeMismatch))
+
+	large := strings.Repeat("x", git.LFSPointerMaxSize+10)
+	assert.Equal(t, large+"tail", write(large[:100], large[100:], "tail"))
+	assert.Equal(t, "", write())
+}
+
+type fakeGetBlobsServer struct {
+	proto.GitserverService_GetBlobsServer
+	resps []*proto.GetBlobsResponse
+}
+
+func (s *fakeGetBlobsServer) Send(resp *proto.GetBlobsResponse) error {
+	s.resps = append(s.resps, resp)
+	return nil
+}
+
+func TestSendMaybeLFSBlob(t *testing.T) {
+	dir := common.GitDir(t.TempDir())
+	const oid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+	path := git.LFSObjectPath(dir, oid)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("hello world\n"), 0o644))
+
+	send := func(blob string) (size int64, data string) {
+		t.Helper()
+		ss := &fakeGetBlobsServer{}
+		require.NoError(t, sendMaybeLFSBlob(ss, dir, "blob", []byte(blob)))
+		require.Len(t, ss.resps, 1)
+		require.Equal(t, "blob", ss.resps[0].GetOid())
+		return ss.resps[0].GetSize(), string(ss.resps[0].GetData())
+	}
+
+	// The size of the blob is the size of the object the pointer points to.
+	pointer := fmt.Sprintf("version https://git-lfs.github.com/spec/v1\noid sha256:%s\nsize %d\n", oid, len("hello world\n"))
+	size, data := send(pointer)
+	assert.Equal(t, int64(len("hello world\n")), size)
+	assert.Equal(t, "hello world\n", data)
+
+	// Pointers to objects that weren't fetched are sent unchanged.
+	missing := strings.Replace(pointer, oid, strings.Repeat("0", 64), 1)
+	size, data = send(missing)
+	assert.Equal(t, int64(len(missing)), size)
+	assert.Equal(t, missing, data)
+
+	size, data = send("not a pointer")
+	assert.Equal(t, int64(len("not a pointer")), size)
+	assert.Equal(t, "not a pointer", data)
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/executil"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/gitserverfs"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+const (
+	// promisorRemoteURLCacheSize is the number of partially cloned repos whose
+	// remote URL is cached.
+	promisorRemoteURLCacheSize = 1000
+
+	// promisorRemoteURLCacheTTL is how long a cached remote URL is used. It
+	// bounds how long commands keep using credentials that were rotated.
+	promisorRemoteURLCacheTTL = time.Minute
+)
+
+type promisorRemoteURLCacheEntry struct {
+	url       *vcs.URL
+	fetchedAt time.Time
+}
+
+// promisorRemoteURLCache caches the remote URLs of partially cloned repos. The
+// zero value is ready to use.
+type promisorRemoteURLCache struct {
+	once  sync.Once
+	cache *lru.Cache[api.RepoName, promisorRemoteURLCacheEntry]
+}
+
+// promisorRemoteURL returns the URL that git commands running in the partial
+// clone of repo lazily fetch missing objects from. Most commands run on a
+// partial clone don't need it, so the URL is cached to avoid looking it up in
+// the database for each of them.
+func (s *Server) promisorRemoteURL(ctx context.Context, repo api.RepoName) (*vcs.URL, error) {
+	c := &s.promisorRemoteURLs
+	c.once.Do(func() {
+		// lru.New only fails for non-positive sizes.
+		c.cache, _ = lru.New[api.RepoName, promisorRemoteURLCacheEntry](promisorRemoteURLCacheSize)
+	})
+
+	if e, ok := c.cache.Get(repo); ok && time.Since(e.fetchedAt) < promisorRemoteURLCacheTTL {
+		return e.url, nil
+	}
+
+	remoteURL, err := s.getRemoteURL(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Add(repo, promisorRemoteURLCacheEntry{url: remoteURL, fetchedAt: time.Now()})
+	return remoteURL, nil
+}
+
+// configurePromisorRemote configures cmd, which runs in the repo directory of
+// repo, to lazily fetch missing objects if repo is a partial clone.
+func (s *Server) configurePromisorRemote(ctx context.Context, logger log.Logger, repo api.RepoName, cmd *exec.Cmd) {
+	if !git.IsPartialClone(gitserverfs.RepoDirFromName(s.ReposDir, repo)) {
+		return
+	}
+	remoteURL, err := s.promisorRemoteURL(ctx, repo)
+	if err != nil {
+		logger.Warn("failed to get remote URL for partial clone, missing objects can't be fetched", log.String("repo", string(repo)), log.Error(err))
+		return
+	}
+	// git upload-pack disables lazy fetches by default, so that clients can't
+	// make the server fetch objects on their behalf. Only other gitservers
+	// reach this endpoint.
+	cmd.Env = append(cmd.Env, git.PromisorRemoteEnv(remoteURL.String())...)
+	cmd.Env = append(cmd.Env, "GIT_NO_LAZY_FETCH=0")
+	executil.ConfigureRemoteGitCommand(cmd)
+}
+
+// promisorRemoteConfigurer returns a function which configures git commands
+// running in the repo directory of repo to lazily fetch missing objects. It
+// returns nil if repo is not a partial clone or its remote URL is unknown.
+func (s *Server) promisorRemoteConfigurer(ctx context.Context, logger log.Logger, repo api.RepoName) func(*exec.Cmd) {
+	if !git.IsPartialClone(gitserverfs.RepoDirFromName(s.ReposDir, repo)) {
+		return nil
+	}
+	remoteURL, err := s.promisorRemoteURL(ctx, repo)
+	if err != nil {
+		logger.Warn("failed to get remote URL for partial clone, missing objects can't be fetched", log.String("repo", string(repo)), log.Error(err))
+		return nil
+	}
+	env := git.PromisorRemoteEnv(remoteURL.String())
+	return func(cmd *exec.Cmd) {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, env...)
+		executil.ConfigureRemoteGitCommand(cmd)
+	}
+}
@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/gitserverfs"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/vcssyncer"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+var replicaSyncCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_replica_sync_total",
+	Help: "Incremented each time a replica of a repo is cloned or fetched from its primary gitserver",
+}, []string{"type", "success"})
+
+// replicaPrimary returns the address of the primary gitserver of repo if the
+// gitserver identified by shardID holds a secondary copy of it.
+func replicaPrimary(ctx context.Context, shardID string, addrs gitserver.GitserverAddresses, repo api.RepoName) (string, bool) {
+	replicas := addrs.AddrsForRepo(ctx, filepath.Base(os.Args[0]), repo)
+	if len(replicas) < 2 || hostnameMatch(shardID, replicas[0]) {
+		return "", false
+	}
+	for _, addr := range replicas[1:] {
+		if hostnameMatch(shardID, addr) {
+			return replicas[0], true
+		}
+	}
+	return "", false
+}
+
+// ownsRepo returns true if the gitserver identified by shardID holds either
+// the primary or a secondary copy of repo.
+func ownsRepo(ctx context.Context, shardID string, addrs gitserver.GitserverAddresses, repo api.RepoName) bool {
+	for _, addr := range addrs.AddrsForRepo(ctx, filepath.Base(os.Args[0]), repo) {
+		if hostnameMatch(shardID, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryAddrsForShard returns the addresses of all gitservers whose repos
+// have a secondary copy on the gitserver identified by shardID. Secondary
+// copies are kept by the gitservers that follow the primary in the list of
+// addresses, so these are the gitservers that precede shardID.
+func primaryAddrsForShard(shardID string, addrs gitserver.GitserverAddresses) []string {
+	n := min(addrs.ReplicationFactor, len(addrs.Addresses))
+	if n < 2 {
+		return nil
+	}
+	for i, addr := range addrs.Addresses {
+		if !hostnameMatch(shardID, addr) {
+			continue
+		}
+		primaries := make([]string, 0, n-1)
+		for j := 1; j < n; j++ {
+			primaries = append(primaries, addrs.Addresses[(i-j+len(addrs.Addresses))%len(addrs.Addresses)])
+		}
+		return primaries
+	}
+	return nil
+}
+
+// replicaPrimary returns the address of the primary gitserver of repo if this
+// gitserver holds a secondary copy of it.
+func (s *Server) replicaPrimary(ctx context.Context, repo api.RepoName) (string, bool) {
+	return replicaPrimary(ctx, s.Hostname, gitserver.NewGitserverAddresses(conf.Get()), repo)
+}
+
+// replicaRemoteURL returns the URL under which the primary gitserver serves
+// repo to its replicas.
+func replicaRemoteURL(primary string, repo api.RepoName) (*vcs.URL, error) {
+	return vcs.ParseURL((&url.URL{Scheme: "http", Host: primary, Path: "/git/" + string(repo)}).String())
+}
+
+// getVCSSyncer returns the VCS syncer for repo. Secondary copies are always
+// fetched with git from the primary gitserver, regardless of the kind of code
+// host the repo comes from. They are partial clones if the primary copy is.
+func (s *Server) getVCSSyncer(ctx context.Context, repo api.RepoName) (vcssyncer.VCSSyncer, error) {
+	if _, ok := s.replicaPrimary(ctx, repo); ok {
+		originURL, err := s.originURL(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		return vcssyncer.NewReplicaGitRepoSyncer(s.Logger, s.RecordingCommandFactory, originURL), nil
+	}
+	return s.GetVCSSyncer(ctx, repo)
+}
+
+// NewReplicaSyncer returns a periodic goroutine that clones and fetches the
+// secondary copies this gitserver holds from their primary gitserver whenever
+// the primary copy changed since the last fetch.
+func (s *Server) NewReplicaSyncer(ctx context.Context, interval time.Duration, batchSize int) goroutine.BackgroundRoutine {
+	logger := s.Logger.Scoped("replica-syncer")
+
+	return goroutine.NewPeriodicGoroutine(
+		actor.WithInternalActor(ctx),
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			return s.syncReplicas(ctx, logger, gitserver.NewGitserverAddresses(conf.Get()), batchSize)
+		}),
+		goroutine.WithName("gitserver.replica-syncer"),
+		goroutine.WithDescription("fetches outdated repository replicas from their primary gitserver"),
+		goroutine.WithInterval(interval),
+	)
+}
+
+func (s *Server) syncReplicas(ctx context.Context, logger log.Logger, addrs gitserver.GitserverAddresses, batchSize int) error {
+	primaries := primaryAddrsForShard(s.Hostname, addrs)
+	if len(primaries) == 0 {
+		return nil
+	}
+
+	// The shard ID of the primary in the gitserver_repos table is its hostname,
+	// which may only be a prefix of its address.
+	var shardIDs []string
+	for _, addr := range primaries {
+		shardIDs = append(shardIDs, shardIDsForAddr(addr)...)
+	}
+
+	repos, err := s.DB.GitserverRepos().ListStaleReplicas(ctx, s.Hostname, shardIDs, batchSize)
+	if err != nil {
+		return errors.Wrap(err, "listing stale replicas")
+	}
+
+	for _, repo := range repos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// The shard ID of the primary can be outdated if the list of gitservers
+		// changed recently.
+		if _, ok := replicaPrimary(ctx, s.Hostname, addrs, repo); !ok {
+			continue
+		}
+
+		typ := "fetch"
+		if repoCloned(gitserverfs.RepoDirFromName(s.ReposDir, repo)) {
+			err = s.doRepoUpdate(ctx, repo, "")
+		} else {
+			typ = "clone"
+			_, err = s.CloneRepo(ctx, repo, CloneOptions{Block: true})
+		}
+		replicaSyncCounter.WithLabelValues(typ, strconv.FormatBool(err == nil)).Inc()
+		if err != nil {
+			logger.Warn("failed to sync replica", log.String("repo", string(repo)), log.String("type", typ), log.Error(err))
+		}
+	}
+
+	return nil
+}
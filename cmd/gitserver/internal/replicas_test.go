@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/gitserverfs"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/vcssyncer"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/wrexec"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestReplicaPrimary(t *testing.T) {
+	ctx := context.Background()
+	addrs := gitserver.GitserverAddresses{
+		Addresses:         []string{"gitserver-0.gitserver:3178", "gitserver-1.gitserver:3178", "gitserver-2.gitserver:3178"},
+		PinnedServers:     map[string]string{"github.com/foo/bar": "gitserver-2.gitserver:3178"},
+		ReplicationFactor: 2,
+	}
+
+	for _, tc := range []struct {
+		shardID     string
+		wantPrimary string
+		wantReplica bool
+		wantOwns    bool
+	}{
+		{shardID: "gitserver-2", wantOwns: true},
+		{shardID: "gitserver-0", wantPrimary: "gitserver-2.gitserver:3178", wantReplica: true, wantOwns: true},
+		{shardID: "gitserver-0.gitserver:3178", wantPrimary: "gitserver-2.gitserver:3178", wantReplica: true, wantOwns: true},
+		{shardID: "gitserver-1"},
+	} {
+		t.Run(tc.shardID, func(t *testing.T) {
+			primary, replica := replicaPrimary(ctx, tc.shardID, addrs, "github.com/foo/bar")
+			assert.Equal(t, tc.wantPrimary, primary)
+			assert.Equal(t, tc.wantReplica, replica)
+			assert.Equal(t, tc.wantOwns, ownsRepo(ctx, tc.shardID, addrs, "github.com/foo/bar"))
+		})
+	}
+
+	t.Run("replication disabled", func(t *testing.T) {
+		addrs := addrs
+		addrs.ReplicationFactor = 1
+		_, replica := replicaPrimary(ctx, "gitserver-0", addrs, "github.com/foo/bar")
+		assert.False(t, replica)
+		assert.False(t, ownsRepo(ctx, "gitserver-0", addrs, "github.com/foo/bar"))
+	})
+}
+
+func TestPrimaryAddrsForShard(t *testing.T) {
+	addrs := gitserver.GitserverAddresses{
+		Addresses:         []string{"gitserver-0:3178", "gitserver-1:3178", "gitserver-2:3178", "gitserver-3:3178"},
+		ReplicationFactor: 3,
+	}
+	assert.Equal(t, []string{"gitserver-0:3178", "gitserver-3:3178"}, primaryAddrsForShard("gitserver-1", addrs))
+	assert.Equal(t, []string{"gitserver-3:3178", "gitserver-2:3178"}, primaryAddrsForShard("gitserver-0", addrs))
+	assert.Nil(t, primaryAddrsForShard("gitserver-4", addrs))
+
+	addrs.ReplicationFactor = 10
+	assert.Equal(t, []string{"gitserver-0:3178", "gitserver-3:3178", "gitserver-2:3178"}, primaryAddrsForShard("gitserver-1", addrs))
+
+	addrs.ReplicationFactor = 0
+	assert.Nil(t, primaryAddrsForShard("gitserver-1", addrs))
+}
+
+func TestShardIDsForAddr(t *testing.T) {
+	shardIDs := shardIDsForAddr("gitserver-0.gitserver.svc:3178")
+	assert.Equal(t, []string{"gitserver-0.gitserver.svc:3178", "gitserver-0.gitserver.svc", "gitserver-0.gitserver", "gitserver-0"}, shardIDs)
+	for _, shardID := range shardIDs {
+		assert.True(t, hostnameMatch(shardID, "gitserver-0.gitserver.svc:3178"), shardID)
+	}
+}
+
+func TestReplicaRemoteURL(t *testing.T) {
+	u, err := replicaRemoteURL("gitserver-0.gitserver:3178", "github.com/foo/bar")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://gitserver-0.gitserver:3178/git/github.com/foo/bar", u.String())
+}
+
+func TestReplicaOfPartialClone(t *testing.T) {
+	runGit := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return string(out)
+	}
+
+	origin := t.TempDir()
+	runGit(origin, "init", "--initial-branch=main")
+	runGit(origin, "config", "uploadpack.allowFilter", "true")
+	runGit(origin, "config", "uploadpack.allowAnySHA1InWant", "true")
+	require.NoError(t, os.WriteFile(filepath.Join(origin, "main.go"), []byte("package main"), 0o644))
+	runGit(origin, "add", ".")
+	runGit(origin, "-c", "user.name=a", "-c", "user.email=a@a.com", "commit", "-m", "init")
+	originURL, err := vcs.ParseURL("file://" + origin)
+	require.NoError(t, err)
+
+	vcssyncer.TestPartialCloneMappings = []*schema.PartialCloneMapping{{DomainPath: origin}}
+	t.Cleanup(func() { vcssyncer.TestPartialCloneMappings = nil })
+
+	// The primary copy is a partial clone that doesn't store the URL of the
+	// code host in its config.
+	ctx := context.Background()
+	repo := api.RepoName("example.com/monorepo")
+	primaryReposDir := t.TempDir()
+	primaryDir := gitserverfs.RepoDirFromName(primaryReposDir, repo)
+	runGit(primaryReposDir, "clone", "--bare", "--filter=blob:none", originURL.String(), primaryDir.Path())
+	runGit(primaryDir.Path(), "config", "--unset", "remote."+git.PartialCloneRemote+".url")
+	require.True(t, git.IsPartialClone(primaryDir))
+
+	primary := makeTestServer(ctx, t, primaryReposDir, "", nil)
+	primary.GetRemoteURLFunc = func(context.Context, api.RepoName) (string, error) {
+		return originURL.String(), nil
+	}
+	ts := httptest.NewServer(http.StripPrefix("/git", primary.gitServiceHandler()))
+	t.Cleanup(ts.Close)
+	primaryURL, err := vcs.ParseURL(ts.URL + "/git/" + string(repo))
+	require.NoError(t, err)
+
+	// The secondary copy is cloned from the primary with the same filter.
+	secondaryDir := common.GitDir(filepath.Join(t.TempDir(), ".git"))
+	syncer := vcssyncer.NewReplicaGitRepoSyncer(logtest.Scoped(t), wrexec.NewNoOpRecordingCommandFactory(), originURL)
+	require.NoError(t, syncer.Clone(ctx, repo, primaryURL, secondaryDir, secondaryDir.Path(), io.Discard))
+	assert.True(t, git.IsPartialClone(secondaryDir))
+
+	// Blobs missing from both copies are fetched lazily by the secondary from
+	// the primary, which in turn fetches them from the code host.
+	cmd := exec.Command("git", "show", "refs/heads/main:main.go")
+	secondaryDir.Set(cmd)
+	cmd.Env = append(os.Environ(), git.PromisorRemoteEnv(primaryURL.String())...)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	assert.Equal(t, "package main", string(out))
+}
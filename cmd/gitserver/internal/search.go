@@ -136,13 +136,15 @@ func (s *Server) search(ctx context.Context, args *protocol.SearchRequest, onMat
 	}
 
 	searcher := &search.CommitSearcher{
-		Logger:               s.Logger,
-		RepoName:             args.Repo,
-		RepoDir:              dir.Path(),
-		Revisions:            args.Revisions,
-		Query:                mt,
-		IncludeDiff:          args.IncludeDiff,
-		IncludeModifiedFiles: args.IncludeModifiedFiles || hasDiffModifiesFile,
+		Logger:                s.Logger,
+		RepoName:              args.Repo,
+		RepoDir:               dir.Path(),
+		Revisions:             args.Revisions,
+		Query:                 mt,
+		IncludeDiff:           args.IncludeDiff,
+		IncludeModifiedFiles:  args.IncludeModifiedFiles || hasDiffModifiesFile,
+		IncludeContainingRefs: args.IncludeContainingRefs,
+		ConfigureCommand:      s.promisorRemoteConfigurer(ctx, s.Logger, args.Repo),
 	}
 
 	return hitLimit.Load(), searcher.Search(ctx, limitedOnMatch)
@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestSearch_PartialClone(t *testing.T) {
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/monorepo")
+	remote := makePartialClone(t, reposDir, repo, `echo 'package main' > main.go
+git add main.go
+git commit -m init
+echo 'func hello() {}' >> main.go
+git commit -am hello
+`)
+
+	ctx := context.Background()
+	s := makeTestServer(ctx, t, reposDir, remote, nil)
+
+	// Diff search reads the blobs of both commits, which the partial clone
+	// has to fetch from the remote.
+	var messages []string
+	_, err := s.search(ctx, &protocol.SearchRequest{
+		Repo:        repo,
+		Revisions:   []protocol.RevisionSpecifier{{RevSpec: "HEAD"}},
+		Query:       &protocol.DiffMatches{Expr: "hello"},
+		IncludeDiff: true,
+		Limit:       10,
+	}, func(match *protocol.CommitMatch) error {
+		messages = append(messages, match.Message.Content)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"hello"}, messages)
+}
@@ -51,6 +51,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/limiter"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -157,6 +158,12 @@ type Server struct {
 	// Locker is used to lock repositories while fetching to prevent concurrent work.
 	Locker RepositoryLocker
 
+	// BlockExecOfNewTypedRPCCommands makes Exec also reject the git log,
+	// ls-tree and diff invocations served by the Commits, ReadDir and
+	// DiffSymbols RPCs. It can be enabled once no clients from before those
+	// RPCs existed are left.
+	BlockExecOfNewTypedRPCCommands bool
+
 	// skipCloneForTests is set by tests to avoid clones.
 	skipCloneForTests bool
 
@@ -182,6 +189,10 @@ type Server struct {
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
 
+	// promisorRemoteURLs caches the remote URLs of partially cloned repos, see
+	// promisorRemoteURL.
+	promisorRemoteURLs promisorRemoteURLCache
+
 	// GlobalBatchLogSemaphore is a semaphore shared between all requests to ensure that a
 	// maximum number of Git subprocesses are active for all /batch-log requests combined.
 	GlobalBatchLogSemaphore *semaphore.Weighted
@@ -489,6 +500,18 @@ func (s *Server) serverContext() (context.Context, context.CancelFunc) {
 }
 
 func (s *Server) getRemoteURL(ctx context.Context, name api.RepoName) (*vcs.URL, error) {
+	// Secondary copies of a repo are fetched from the primary gitserver, not
+	// from the code host.
+	if primary, ok := s.replicaPrimary(ctx, name); ok {
+		return replicaRemoteURL(primary, name)
+	}
+
+	return s.originURL(ctx, name)
+}
+
+// originURL returns the URL of the repo on its code host. Unlike getRemoteURL,
+// it doesn't point to the primary gitserver for secondary copies.
+func (s *Server) originURL(ctx context.Context, name api.RepoName) (*vcs.URL, error) {
 	remoteURL, err := s.GetRemoteURLFunc(ctx, name)
 	if err != nil {
 		return nil, errors.Wrap(err, "GetRemoteURLFunc")
@@ -544,7 +567,7 @@ func (s *Server) isRepoCloneable(ctx context.Context, repo api.RepoName) (protoc
 		return protocol.IsRepoCloneableResponse{}, errors.Wrap(err, "getRemoteURL")
 	}
 
-	syncer, err := s.GetVCSSyncer(ctx, repo)
+	syncer, err := s.getVCSSyncer(ctx, repo)
 	if err != nil {
 		return protocol.IsRepoCloneableResponse{}, errors.Wrap(err, "GetVCSSyncer")
 	}
@@ -1155,24 +1178,59 @@ func (s *Server) exec(ctx context.Context, logger log.Logger, req *protocol.Exec
 		}
 	}
 
+	// Files stored with Git LFS are returned with the contents of their
+	// objects if those were fetched, see vcssyncer.FetchLFSObjects.
+	args := req.Args
+	var lfsW *lfsPointerWriter
+	if git.HasLFSObjects(dir) {
+		if len(args) > 0 && args[0] == "archive" {
+			args = append(git.LFSFilterArgs(dir), args...)
+		} else if printsSingleBlob(args) {
+			lfsW = &lfsPointerWriter{w: w, dir: dir}
+			w = lfsW
+		}
+	}
+
 	var stderrBuf bytes.Buffer
 	stdoutW := &writeCounter{w: w}
 	stderrW := &writeCounter{w: &limitWriter{W: &stderrBuf, N: 1024}}
 
 	cmdStart = time.Now()
-	cmd := s.RecordingCommandFactory.Command(ctx, s.Logger, string(repoName), "git", req.Args...)
+	cmd := s.RecordingCommandFactory.Command(ctx, s.Logger, string(repoName), "git", args...)
 	dir.Set(cmd.Unwrap())
 	cmd.Unwrap().Stdout = stdoutW
 	cmd.Unwrap().Stderr = stderrW
 	cmd.Unwrap().Stdin = bytes.NewReader(req.Stdin)
 
+	// Partial clones lazily fetch missing objects from the code host, for
+	// example when reading a file or creating an archive.
+	var redactor *urlredactor.URLRedactor
+	if git.IsPartialClone(dir) {
+		if remoteURL, err := s.promisorRemoteURL(ctx, repoName); err != nil {
+			logger.Warn("failed to get remote URL for partial clone, missing objects can't be fetched", log.Error(err))
+		} else {
+			redactor = urlredactor.New(remoteURL)
+			cmd.Unwrap().Env = append(os.Environ(), git.PromisorRemoteEnv(remoteURL.String())...)
+			executil.ConfigureRemoteGitCommand(cmd.Unwrap())
+			cmd = cmd.WithRedactorFunc(redactor.Redact)
+		}
+	}
+
 	exitStatus, execErr = executil.RunCommand(ctx, cmd)
+	if lfsW != nil {
+		if err := lfsW.Flush(); err != nil && execErr == nil {
+			execErr = err
+		}
+	}
 
 	status = strconv.Itoa(exitStatus)
 	stdoutN = stdoutW.n
 	stderrN = stderrW.n
 
 	stderr := stderrBuf.String()
+	if redactor != nil {
+		stderr = redactor.Redact(stderr)
+	}
 	s.logIfCorrupt(ctx, repoName, dir, stderr)
 
 	return execStatus{
@@ -1242,6 +1300,15 @@ func setLastFetched(ctx context.Context, db database.DB, shardID string, dir com
 	})
 }
 
+func setReplicaLastFetched(ctx context.Context, db database.DB, shardID string, dir common.GitDir, name api.RepoName) error {
+	lastFetched, err := repoLastFetched(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get last fetched for %s", name)
+	}
+
+	return db.GitserverRepos().SetReplicaLastFetched(ctx, name, lastFetched, shardID)
+}
+
 // setLastErrorNonFatal will set the last_error column for the repo in the gitserver table.
 func (s *Server) setLastErrorNonFatal(ctx context.Context, name api.RepoName, err error) {
 	var errString string
@@ -1249,6 +1316,13 @@ func (s *Server) setLastErrorNonFatal(ctx context.Context, name api.RepoName, er
 		errString = err.Error()
 	}
 
+	if _, ok := s.replicaPrimary(ctx, name); ok {
+		if err := s.DB.GitserverRepos().SetReplicaLastError(ctx, name, errString, s.Hostname); err != nil {
+			s.Logger.Warn("Setting replica last error in DB", log.Error(err))
+		}
+		return
+	}
+
 	if err := s.DB.GitserverRepos().SetLastError(ctx, name, errString, s.Hostname); err != nil {
 		s.Logger.Warn("Setting last error in DB", log.Error(err))
 	}
@@ -1256,6 +1330,11 @@ func (s *Server) setLastErrorNonFatal(ctx context.Context, name api.RepoName, er
 
 func (s *Server) logIfCorrupt(ctx context.Context, repo api.RepoName, dir common.GitDir, stderr string) {
 	if checkMaybeCorruptRepo(s.Logger, s.RecordingCommandFactory, repo, s.ReposDir, dir, stderr) {
+		// The corruption log tracks the primary copy of the repo. Corrupt
+		// secondary copies are removed by the janitor and fetched again.
+		if _, ok := s.replicaPrimary(ctx, repo); ok {
+			return
+		}
 		reason := stderr
 		if err := s.DB.GitserverRepos().LogCorruption(ctx, repo, reason, s.Hostname); err != nil {
 			s.Logger.Warn("failed to log repo corruption", log.String("repo", string(repo)), log.Error(err))
@@ -1343,7 +1422,7 @@ func (s *Server) CloneRepo(ctx context.Context, repo api.RepoName, opts CloneOpt
 		s.setLastErrorNonFatal(s.ctx, repo, err)
 	}()
 
-	syncer, err := s.GetVCSSyncer(ctx, repo)
+	syncer, err := s.getVCSSyncer(ctx, repo)
 	if err != nil {
 		return "", errors.Wrap(err, "get VCS syncer")
 	}
@@ -1463,15 +1542,23 @@ func (s *Server) doClone(
 	defer os.RemoveAll(tmpDir)
 	tmpPath := filepath.Join(tmpDir, ".git")
 
+	// Secondary copies track their clone state separately, so that they don't
+	// overwrite the state of the primary copy.
+	_, replica := s.replicaPrimary(ctx, repo)
+	setCloneStatus := s.DB.GitserverRepos().SetCloneStatus
+	if replica {
+		setCloneStatus = s.DB.GitserverRepos().SetReplicaCloneStatus
+	}
+
 	// It may already be cloned
 	if !repoCloned(dir) {
-		if err := s.DB.GitserverRepos().SetCloneStatus(ctx, repo, types.CloneStatusCloning, s.Hostname); err != nil {
+		if err := setCloneStatus(ctx, repo, types.CloneStatusCloning, s.Hostname); err != nil {
 			s.Logger.Error("Setting clone status in DB", log.Error(err))
 		}
 	}
 	defer func() {
 		// Use a background context to ensure we still update the DB even if we time out
-		if err := s.DB.GitserverRepos().SetCloneStatus(context.Background(), repo, cloneStatus(repoCloned(dir), false), s.Hostname); err != nil {
+		if err := setCloneStatus(context.Background(), repo, cloneStatus(repoCloned(dir), false), s.Hostname); err != nil {
 			s.Logger.Error("Setting clone status in DB", log.Error(err))
 		}
 	}()
@@ -1495,8 +1582,10 @@ func (s *Server) doClone(
 	}
 
 	// best-effort update the output of the clone
-	if err := s.DB.GitserverRepos().SetLastOutput(context.Background(), repo, output.String()); err != nil {
-		s.Logger.Error("Setting last output in DB", log.Error(err))
+	if !replica {
+		if err := s.DB.GitserverRepos().SetLastOutput(context.Background(), repo, output.String()); err != nil {
+			s.Logger.Error("Setting last output in DB", log.Error(err))
+		}
 	}
 
 	if cloneErr != nil {
@@ -1509,7 +1598,14 @@ func (s *Server) doClone(
 		testRepoCorrupter(ctx, common.GitDir(tmpPath))
 	}
 
-	if err := postRepoFetchActions(ctx, logger, s.DB, s.Hostname, s.RecordingCommandFactory, s.ReposDir, repo, common.GitDir(tmpPath), remoteURL, syncer); err != nil {
+	originURL := remoteURL
+	if replica {
+		if originURL, err = s.originURL(ctx, repo); err != nil {
+			return err
+		}
+	}
+
+	if err := postRepoFetchActions(ctx, logger, s.DB, s.Hostname, replica, s.RecordingCommandFactory, s.ReposDir, repo, common.GitDir(tmpPath), remoteURL, originURL, syncer); err != nil {
 		return err
 	}
 
@@ -1600,11 +1696,13 @@ func postRepoFetchActions(
 	logger log.Logger,
 	db database.DB,
 	shardID string,
+	replica bool,
 	rcf *wrexec.RecordingCommandFactory,
 	reposDir string,
 	repo api.RepoName,
 	dir common.GitDir,
 	remoteURL *vcs.URL,
+	originURL *vcs.URL,
 	syncer vcssyncer.VCSSyncer,
 ) (errs error) {
 	// Note: We use a multi error in this function to try to make as many of the
@@ -1616,6 +1714,17 @@ func postRepoFetchActions(
 		errs = errors.Append(errs, errors.Wrapf(err, "failed to ensure HEAD exists for repo %q", repo))
 	}
 
+	if err := vcssyncer.PrefetchSparsePaths(ctx, logger, rcf, repo, dir, remoteURL); err != nil {
+		errs = errors.Append(errs, errors.Wrapf(err, "failed to prefetch sparse paths for repo %q", repo))
+	}
+
+	// The primary gitserver doesn't serve Git LFS objects, so secondary copies
+	// fetch them from the code host. originURL is the same as remoteURL for
+	// primary copies.
+	if err := vcssyncer.FetchLFSObjects(ctx, logger, httpcli.UncachedExternalDoer, dir, originURL); err != nil {
+		errs = errors.Append(errs, errors.Wrapf(err, "failed to fetch Git LFS objects for repo %q", repo))
+	}
+
 	if err := git.RemoveBadRefs(ctx, dir); err != nil {
 		errs = errors.Append(errs, errors.Wrapf(err, "failed to remove bad refs for repo %q", repo))
 	}
@@ -1637,6 +1746,15 @@ func postRepoFetchActions(
 		errs = errors.Append(errs, errors.Wrap(err, "failed to update last changed time"))
 	}
 
+	// Secondary copies only record when they were last fetched from the
+	// primary. The fetch state and size of the repo are tracked by the primary.
+	if replica {
+		if err := setReplicaLastFetched(ctx, db, shardID, dir, repo); err != nil {
+			errs = errors.Append(errs, errors.Wrap(err, "failed setting replica last fetch in DB"))
+		}
+		return errs
+	}
+
 	// Successfully updated, best-effort updating of db fetch state based on
 	// disk state.
 	if err := setLastFetched(ctx, db, shardID, dir, repo); err != nil {
@@ -1879,7 +1997,7 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 		return errors.Wrap(err, "failed to determine Git remote URL")
 	}
 
-	syncer, err := s.GetVCSSyncer(ctx, repo)
+	syncer, err := s.getVCSSyncer(ctx, repo)
 	if err != nil {
 		return errors.Wrap(err, "get VCS syncer")
 	}
@@ -1896,8 +2014,11 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 	// with what clone does.
 	redactedOutput := urlredactor.New(remoteURL).Redact(string(output))
 	// best-effort update the output of the fetch
-	if err := s.DB.GitserverRepos().SetLastOutput(context.Background(), repo, redactedOutput); err != nil {
-		s.Logger.Warn("Setting last output in DB", log.Error(err))
+	_, replica := s.replicaPrimary(ctx, repo)
+	if !replica {
+		if err := s.DB.GitserverRepos().SetLastOutput(context.Background(), repo, redactedOutput); err != nil {
+			s.Logger.Warn("Setting last output in DB", log.Error(err))
+		}
 	}
 
 	if err != nil {
@@ -1908,7 +2029,14 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 		}
 	}
 
-	return postRepoFetchActions(ctx, logger, s.DB, s.Hostname, s.RecordingCommandFactory, s.ReposDir, repo, dir, remoteURL, syncer)
+	originURL := remoteURL
+	if replica {
+		if originURL, err = s.originURL(ctx, repo); err != nil {
+			return err
+		}
+	}
+
+	return postRepoFetchActions(ctx, logger, s.DB, s.Hostname, replica, s.RecordingCommandFactory, s.ReposDir, repo, dir, remoteURL, originURL, syncer)
 }
 
 // setHEAD configures git repo defaults (such as what HEAD is) which are
@@ -1,25 +1,36 @@
 package internal
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"path"
+	"strconv"
 	"strings"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/go-git/go-git/v5/plumbing/format/config"
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/accesslog"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/gitserverfs"
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/cloneurls"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
 	"github.com/sourcegraph/sourcegraph/internal/grpc/streamio"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -135,6 +146,13 @@ func (gs *GRPCServer) Exec(req *proto.ExecRequest, ss proto.GitserverService_Exe
 		log.Strings("args", args),
 	)
 
+	// 🚨 SECURITY: Commands with a dedicated RPC must go through that RPC, which
+	// constructs the arguments on the server side.
+	if gitdomain.HasDedicatedRPC(internalReq.Args, gs.Server.BlockExecOfNewTypedRPCCommands) {
+		blockedCommandExecutedCounter.Inc()
+		return status.Errorf(codes.InvalidArgument, "git %s must be invoked through its dedicated RPC", cmd)
+	}
+
 	// TODO(mucles): set user agent from all grpc clients
 	return gs.doExec(ss.Context(), gs.Server.Logger, &internalReq, "unknown-grpc-client", w)
 }
@@ -187,49 +205,110 @@ func (gs *GRPCServer) Archive(req *proto.ArchiveRequest, ss proto.GitserverServi
 func (gs *GRPCServer) doExec(ctx context.Context, logger log.Logger, req *protocol.ExecRequest, userAgent string, w io.Writer) error {
 	execStatus, err := gs.Server.exec(ctx, logger, req, userAgent, w)
 	if err != nil {
-		if v := (&NotFoundError{}); errors.As(err, &v) {
-			s, err := status.New(codes.NotFound, "repo not found").WithDetails(&proto.NotFoundPayload{
-				Repo:            string(req.Repo),
-				CloneInProgress: v.Payload.CloneInProgress,
-				CloneProgress:   v.Payload.CloneProgress,
-			})
-			if err != nil {
-				gs.Server.Logger.Error("failed to marshal status", log.Error(err))
-				return err
-			}
-			return s.Err()
+		return gs.convertExecError(ctx, req.Repo, err)
+	}
 
-		} else if errors.Is(err, ErrInvalidCommand) {
-			return status.New(codes.InvalidArgument, "invalid command").Err()
-		} else if ctxErr := ctx.Err(); ctxErr != nil {
-			return status.FromContextError(ctxErr).Err()
-		}
+	if execStatus.ExitStatus != 0 || execStatus.Err != nil {
+		return gs.convertExecStatus(ctx, execStatus)
+	}
+	return nil
 
-		return err
+}
+
+// runGitCommand executes a git command on behalf of one of the typed RPCs and
+// streams its output to w. If the command fails, mapStderr is given the
+// opportunity to translate the stderr output of git into a structured error
+// (see newRevisionNotFoundError and newFileNotFoundError). If it returns nil,
+// the generic ExecStatusPayload error is returned instead.
+func (gs *GRPCServer) runGitCommand(ctx context.Context, req *protocol.ExecRequest, w io.Writer, mapStderr func(stderr string) error) error {
+	// TODO(mucles): set user agent from all grpc clients
+	execStatus, err := gs.Server.exec(ctx, gs.Server.Logger, req, "unknown-grpc-client", w)
+	if err != nil {
+		return gs.convertExecError(ctx, req.Repo, err)
 	}
 
 	if execStatus.ExitStatus != 0 || execStatus.Err != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			return status.FromContextError(ctxErr).Err()
 		}
-
-		gRPCStatus := codes.Unknown
-		if strings.Contains(execStatus.Err.Error(), "signal: killed") {
-			gRPCStatus = codes.Aborted
+		if mapStderr != nil {
+			if err := mapStderr(execStatus.Stderr); err != nil {
+				return err
+			}
 		}
+		return gs.convertExecStatus(ctx, execStatus)
+	}
+	return nil
+}
 
-		s, err := status.New(gRPCStatus, execStatus.Err.Error()).WithDetails(&proto.ExecStatusPayload{
-			StatusCode: int32(execStatus.ExitStatus),
-			Stderr:     execStatus.Stderr,
+// streamGitCommand runs the git command described by req like runGitCommand,
+// but passes its output to parse while the command is still running.
+func (gs *GRPCServer) streamGitCommand(ctx context.Context, req *protocol.ExecRequest, mapStderr func(stderr string) error, parse func(r io.Reader) error) error {
+	pr, pw := io.Pipe()
+	cmdErrC := make(chan error, 1)
+	go func() {
+		err := gs.runGitCommand(ctx, req, pw, mapStderr)
+		pw.CloseWithError(err)
+		cmdErrC <- err
+	}()
+
+	parseErr := parse(pr)
+	// Unblock the command if parse returned before reading all of its output.
+	pr.CloseWithError(io.ErrClosedPipe)
+	cmdErr := <-cmdErrC
+
+	// A failing command also fails parse, in which case the error of the
+	// command is the more meaningful one.
+	if cmdErr != nil && (parseErr == nil || errors.Is(parseErr, cmdErr)) {
+		return cmdErr
+	}
+	return parseErr
+}
+
+// convertExecError converts an error returned by Server.exec into a gRPC status error.
+func (gs *GRPCServer) convertExecError(ctx context.Context, repo api.RepoName, err error) error {
+	if v := (&NotFoundError{}); errors.As(err, &v) {
+		s, err := status.New(codes.NotFound, "repo not found").WithDetails(&proto.NotFoundPayload{
+			Repo:            string(repo),
+			CloneInProgress: v.Payload.CloneInProgress,
+			CloneProgress:   v.Payload.CloneProgress,
 		})
 		if err != nil {
 			gs.Server.Logger.Error("failed to marshal status", log.Error(err))
 			return err
 		}
 		return s.Err()
+
+	} else if errors.Is(err, ErrInvalidCommand) {
+		return status.New(codes.InvalidArgument, "invalid command").Err()
+	} else if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
 	}
-	return nil
 
+	return err
+}
+
+// convertExecStatus converts the status of a failed git command into a gRPC
+// status error carrying an ExecStatusPayload.
+func (gs *GRPCServer) convertExecStatus(ctx context.Context, execStatus execStatus) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+
+	gRPCStatus := codes.Unknown
+	if strings.Contains(execStatus.Err.Error(), "signal: killed") {
+		gRPCStatus = codes.Aborted
+	}
+
+	s, err := status.New(gRPCStatus, execStatus.Err.Error()).WithDetails(&proto.ExecStatusPayload{
+		StatusCode: int32(execStatus.ExitStatus),
+		Stderr:     execStatus.Stderr,
+	})
+	if err != nil {
+		gs.Server.Logger.Error("failed to marshal status", log.Error(err))
+		return err
+	}
+	return s.Err()
 }
 
 func (gs *GRPCServer) GetObject(ctx context.Context, req *proto.GetObjectRequest) (*proto.GetObjectResponse, error) {
@@ -615,6 +694,832 @@ func (gs *GRPCServer) PerforceGetChangelist(ctx context.Context, req *proto.Perf
 	}, nil
 }
 
+func (gs *GRPCServer) MergeBase(ctx context.Context, req *proto.MergeBaseRequest) (*proto.MergeBaseResponse, error) {
+	// Log which actor is accessing the repo.
+	accesslog.Record(ctx, req.GetRepo(),
+		log.String("base", req.GetBase()),
+		log.String("head", req.GetHead()),
+	)
+
+	if req.GetRepo() == "" || req.GetBase() == "" || req.GetHead() == "" {
+		return nil, status.Error(codes.InvalidArgument, "repo, base and head must be specified")
+	}
+
+	execReq := &protocol.ExecRequest{
+		Repo: api.RepoName(req.GetRepo()),
+		Args: []string{"merge-base", "--", req.GetBase(), req.GetHead()},
+	}
+
+	var buf bytes.Buffer
+	err := gs.runGitCommand(ctx, execReq, &buf, func(stderr string) error {
+		if spec, ok := revisionNotFoundSpec(stderr); ok {
+			return newRevisionNotFoundError(req.GetRepo(), spec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.MergeBaseResponse{
+		MergeBaseCommitSha: strings.TrimSpace(buf.String()),
+	}, nil
+}
+
+func (gs *GRPCServer) Blame(req *proto.BlameRequest, ss proto.GitserverService_BlameServer) error {
+	// Log which actor is accessing the repo.
+	accesslog.Record(ss.Context(), req.GetRepo(),
+		log.String("commit", req.GetCommit()),
+		log.String("path", req.GetPath()),
+	)
+
+	if req.GetRepo() == "" || req.GetPath() == "" {
+		return status.Error(codes.InvalidArgument, "repo and path must be specified")
+	}
+	commit := req.GetCommit()
+	if commit == "" {
+		commit = "HEAD"
+	}
+	if err := git.CheckSpecArgSafety(commit); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	args := []string{"blame", "--porcelain", "--incremental"}
+	if req.GetIgnoreWhitespace() {
+		args = append(args, "-w")
+	}
+	if r := req.GetRange(); r != nil {
+		startLine := r.GetStartLine()
+		if startLine == 0 {
+			startLine = 1
+		}
+		// An empty end of the range blames the rest of the file.
+		var endLine string
+		if r.GetEndLine() != 0 {
+			endLine = strconv.FormatUint(uint64(r.GetEndLine()), 10)
+		}
+		args = append(args, fmt.Sprintf("-L%d,%s", startLine, endLine))
+	}
+	args = append(args, commit, "--", req.GetPath())
+
+	execReq := &protocol.ExecRequest{
+		Repo: api.RepoName(req.GetRepo()),
+		Args: args,
+	}
+
+	return gs.streamGitCommand(ss.Context(), execReq, func(stderr string) error {
+		if spec, ok := revisionNotFoundSpec(stderr); ok {
+			return newRevisionNotFoundError(req.GetRepo(), spec)
+		}
+		if strings.Contains(stderr, "no such path") {
+			return newFileNotFoundError(req.GetRepo(), commit, req.GetPath())
+		}
+		return nil
+	}, func(r io.Reader) error {
+		hr := gitdomain.NewBlameHunkReader(r)
+		for {
+			h, err := hr.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := ss.Send(&proto.BlameResponse{Hunk: h.ToProto()}); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func (gs *GRPCServer) ReadDir(req *proto.ReadDirRequest, ss proto.GitserverService_ReadDirServer) error {
+	// Log which actor is accessing the repo.
+	accesslog.Record(ss.Context(), req.GetRepo(),
+		log.String("commit", req.GetCommitSha()),
+		log.String("path", req.GetPath()),
+		log.Bool("recursive", req.GetRecursive()),
+	)
+
+	if req.GetRepo() == "" {
+		return status.Error(codes.InvalidArgument, "repo must be specified")
+	}
+	if err := gitdomain.EnsureAbsoluteCommit(api.CommitID(req.GetCommitSha())); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := git.CheckSpecArgSafety(req.GetPath()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	args := []string{
+		"ls-tree",
+		"--long", // show size
+		"--full-name",
+		"-z",
+		req.GetCommitSha(),
+	}
+	if req.GetRecursive() {
+		args = append(args, "-r", "-t")
+	}
+	if req.GetPath() != "" {
+		args = append(args, "--", req.GetPath())
+	}
+
+	execReq := &protocol.ExecRequest{
+		Repo: api.RepoName(req.GetRepo()),
+		Args: args,
+	}
+
+	return gs.streamGitCommand(ss.Context(), execReq, func(stderr string) error {
+		if strings.Contains(stderr, "exists on disk, but not in") {
+			return newFileNotFoundError(req.GetRepo(), req.GetCommitSha(), req.GetPath())
+		}
+		if _, ok := revisionNotFoundSpec(stderr); ok || strings.Contains(stderr, "not a tree object") {
+			return newRevisionNotFoundError(req.GetRepo(), req.GetCommitSha())
+		}
+		return nil
+	}, func(r io.Reader) error {
+		var submodules *config.Config
+		submoduleRepos := map[string]string{}
+
+		sc := bufio.NewScanner(r)
+		sc.Buffer(nil, maxTreeEntrySize)
+		sc.Split(scanNullTerminated)
+
+		var entries []*proto.TreeEntry
+		for sc.Scan() {
+			entry, err := parseTreeEntry(sc.Bytes())
+			if err != nil {
+				return err
+			}
+
+			if entry.Type == proto.GitObject_OBJECT_TYPE_COMMIT {
+				if submodules == nil {
+					submodules, err = gs.readGitmodules(ss.Context(), req.GetRepo(), req.GetCommitSha())
+					if err != nil {
+						return err
+					}
+				}
+				subsection := submodules.Section("submodule").Subsection(string(entry.Path))
+				url := subsection.Option("url")
+				submoduleRepo, ok := submoduleRepos[url]
+				if !ok {
+					submoduleRepo = string(gs.submoduleRepo(ss.Context(), req.GetRepo(), url))
+					submoduleRepos[url] = submoduleRepo
+				}
+				entry.Submodule = &proto.Submodule{
+					Path: []byte(subsection.Option("path")),
+					Url:  url,
+					Repo: submoduleRepo,
+				}
+			}
+
+			entries = append(entries, entry)
+			if len(entries) >= readDirChunkSize {
+				if err := ss.Send(&proto.ReadDirResponse{Entries: entries}); err != nil {
+					return err
+				}
+				entries = nil
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			return ss.Send(&proto.ReadDirResponse{Entries: entries})
+		}
+		return nil
+	})
+}
+
+// readDirChunkSize is the number of tree entries sent in a single
+// ReadDirResponse message.
+const readDirChunkSize = 1000
+
+// maxTreeEntrySize is the maximum size of a single line of `git ls-tree`
+// output, which is dominated by the length of the path.
+const maxTreeEntrySize = 64 * 1024
+
+// parseTreeEntry parses an entry of the output of
+// `git ls-tree --long --full-name -z`, which is of the form
+// "<mode> <type> <oid> <size>\t<path>".
+func parseTreeEntry(line []byte) (*proto.TreeEntry, error) {
+	info, path, ok := bytes.Cut(line, []byte{'\t'})
+	if !ok {
+		return nil, errors.Errorf("invalid `git ls-tree` output: %q", line)
+	}
+	fields := strings.Fields(string(info))
+	if len(fields) != 4 {
+		return nil, errors.Errorf("invalid `git ls-tree` output: %q", line)
+	}
+
+	mode, err := strconv.ParseUint(fields[0], 8, 32)
+	if err != nil {
+		return nil, errors.Errorf("invalid `git ls-tree` mode output: %q", fields[0])
+	}
+
+	var typ proto.GitObject_ObjectType
+	switch fields[1] {
+	case "blob":
+		typ = proto.GitObject_OBJECT_TYPE_BLOB
+	case "tree":
+		typ = proto.GitObject_OBJECT_TYPE_TREE
+	case "commit":
+		typ = proto.GitObject_OBJECT_TYPE_COMMIT
+	default:
+		return nil, errors.Errorf("invalid `git ls-tree` type output: %q", fields[1])
+	}
+
+	oid := fields[2]
+	if !gitdomain.IsAbsoluteRevision(oid) {
+		return nil, errors.Errorf("invalid `git ls-tree` SHA output: %q", oid)
+	}
+
+	var size int64
+	if fields[3] != "-" {
+		// Size of "-" indicates a dir or submodule.
+		size, err = strconv.ParseInt(fields[3], 10, 64)
+		if err != nil || size < 0 {
+			return nil, errors.Errorf("invalid `git ls-tree` size output: %q (error: %s)", fields[3], err)
+		}
+	}
+
+	return &proto.TreeEntry{
+		Path: bytes.Clone(path),
+		Mode: uint32(mode),
+		Type: typ,
+		Oid:  oid,
+		Size: size,
+	}, nil
+}
+
+// readGitmodules returns the .gitmodules file of commit. A missing or
+// unreadable .gitmodules file results in an empty configuration.
+func (gs *GRPCServer) readGitmodules(ctx context.Context, repo, commit string) (*config.Config, error) {
+	execReq := &protocol.ExecRequest{
+		Repo: api.RepoName(repo),
+		Args: []string{"show", commit + ":.gitmodules"},
+	}
+
+	var cfg config.Config
+	var buf bytes.Buffer
+	if err := gs.runGitCommand(ctx, execReq, &buf, nil); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		return &cfg, nil
+	}
+	if err := config.NewDecoder(&buf).Decode(&cfg); err != nil {
+		return nil, errors.Errorf("error parsing .gitmodules: %s", err)
+	}
+	return &cfg, nil
+}
+
+// submoduleRepo returns the name of the repository the clone URL of a
+// submodule of repo maps to, or the empty string if that repository isn't
+// known to this instance.
+func (gs *GRPCServer) submoduleRepo(ctx context.Context, repo, cloneURL string) api.RepoName {
+	if cloneURL == "" {
+		return ""
+	}
+	if strings.HasPrefix(cloneURL, "../") {
+		cloneURL = path.Join(repo, cloneURL)
+	}
+
+	// Whether the current user may see the repository is checked by the
+	// callers, so look it up as an internal actor.
+	ctx = actor.WithInternalActor(ctx)
+	logger := gs.Server.Logger.With(log.String("repo", repo), log.String("cloneURL", cloneURL))
+
+	name, err := cloneurls.RepoSourceCloneURLToRepoName(ctx, gs.Server.DB, cloneURL)
+	if err != nil {
+		logger.Warn("failed to map submodule clone URL to a repository name", log.Error(err))
+		return ""
+	}
+	if name == "" {
+		return ""
+	}
+	if _, err := gs.Server.DB.Repos().GetByName(ctx, name); err != nil {
+		if !errcode.IsNotFound(err) {
+			logger.Warn("failed to look up submodule repository", log.Error(err))
+		}
+		return ""
+	}
+	return name
+}
+
+// scanNullTerminated is a bufio.SplitFunc for null-terminated records.
+func scanNullTerminated(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// lsFilesChunkSize is the approximate number of bytes of file paths sent in a
+// single LsFilesResponse message.
+const lsFilesChunkSize = 1024 * 1024 // 1 MiB
+
+func (gs *GRPCServer) LsFiles(req *proto.LsFilesRequest, ss proto.GitserverService_LsFilesServer) error {
+	// Log which actor is accessing the repo.
+	accesslog.Record(ss.Context(), req.GetRepo(),
+		log.String("commit", req.GetCommitSha()),
+		log.Strings("pathspecs", req.GetPathspecs()),
+	)
+
+	if req.GetRepo() == "" || req.GetCommitSha() == "" {
+		return status.Error(codes.InvalidArgument, "repo and commit must be specified")
+	}
+	if err := git.CheckSpecArgSafety(req.GetCommitSha()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	args := []string{
+		"ls-files",
+		"-z",
+		"--with-tree",
+		req.GetCommitSha(),
+	}
+	if len(req.GetPathspecs()) > 0 {
+		args = append(args, "--")
+		args = append(args, req.GetPathspecs()...)
+	}
+
+	execReq := &protocol.ExecRequest{
+		Repo: api.RepoName(req.GetRepo()),
+		Args: args,
+	}
+
+	var buf bytes.Buffer
+	err := gs.runGitCommand(ss.Context(), execReq, &buf, func(stderr string) error {
+		if spec, ok := revisionNotFoundSpec(stderr); ok {
+			return newRevisionNotFoundError(req.GetRepo(), spec)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var (
+		files []string
+		size  int
+	)
+	for _, file := range strings.Split(buf.String(), "\x00") {
+		if file == "" {
+			continue
+		}
+		files = append(files, file)
+		size += len(file)
+		if size >= lsFilesChunkSize {
+			if err := ss.Send(&proto.LsFilesResponse{Files: files}); err != nil {
+				return err
+			}
+			files, size = nil, 0
+		}
+	}
+	if len(files) > 0 {
+		return ss.Send(&proto.LsFilesResponse{Files: files})
+	}
+	return nil
+}
+
+func (gs *GRPCServer) Commits(req *proto.CommitsRequest, ss proto.GitserverService_CommitsServer) error {
+	// Log which actor is accessing the repo.
+	accesslog.Record(ss.Context(), req.GetRepo(),
+		log.String("range", req.GetRange()),
+		log.String("path", string(req.GetPath())),
+	)
+
+	if req.GetRepo() == "" {
+		return status.Error(codes.InvalidArgument, "repo must be specified")
+	}
+	if err := git.CheckSpecArgSafety(req.GetRange()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	execReq := &protocol.ExecRequest{
+		Repo: api.RepoName(req.GetRepo()),
+		Args: commitLogArgs(req),
+	}
+	if !req.GetNoEnsureRevision() {
+		execReq.EnsureRevision = req.GetRange()
+	}
+
+	return gs.streamGitCommand(ss.Context(), execReq, func(stderr string) error {
+		if _, ok := revisionNotFoundSpec(stderr); ok {
+			return newRevisionNotFoundError(req.GetRepo(), req.GetRange())
+		}
+		return nil
+	}, func(r io.Reader) error {
+		var commits []*proto.GitCommit
+		err := gitdomain.ParseCommitLog(r, func(commit *gitdomain.Commit, modifiedFiles []string) error {
+			c := commit.ToProto()
+			if req.GetNameOnly() {
+				c.ModifiedFiles = make([][]byte, 0, len(modifiedFiles))
+				for _, f := range modifiedFiles {
+					c.ModifiedFiles = append(c.ModifiedFiles, []byte(f))
+				}
+			}
+			commits = append(commits, c)
+			if len(commits) >= commitsChunkSize {
+				if err := ss.Send(&proto.CommitsResponse{Commits: commits}); err != nil {
+					return err
+				}
+				commits = nil
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(commits) > 0 {
+			return ss.Send(&proto.CommitsResponse{Commits: commits})
+		}
+		return nil
+	})
+}
+
+// commitsChunkSize is the number of commits sent in a single CommitsResponse
+// message.
+const commitsChunkSize = 100
+
+// commitLogArgs returns the arguments for the `git log` invocation that
+// serves the given Commits request. Its output is parsed with
+// gitdomain.ParseCommitLog.
+func commitLogArgs(req *proto.CommitsRequest) []string {
+	args := []string{"log", gitdomain.CommitLogFormat}
+	if req.GetMaxCommits() != 0 {
+		args = append(args, "-n", strconv.FormatUint(uint64(req.GetMaxCommits()), 10))
+	}
+	if req.GetSkip() != 0 {
+		args = append(args, "--skip="+strconv.FormatUint(uint64(req.GetSkip()), 10))
+	}
+	if len(req.GetAuthor()) != 0 {
+		args = append(args, "--fixed-strings", "--author="+string(req.GetAuthor()))
+	}
+	if req.GetAfter() != "" {
+		args = append(args, "--after="+req.GetAfter())
+	}
+	if req.GetBefore() != "" {
+		args = append(args, "--before="+req.GetBefore())
+	}
+	if req.GetReverse() {
+		args = append(args, "--reverse")
+	}
+	if req.GetDateOrder() {
+		args = append(args, "--date-order")
+	}
+	if req.GetFirstParent() {
+		args = append(args, "--first-parent")
+	}
+	if len(req.GetMessageQuery()) != 0 {
+		args = append(args, "--fixed-strings", "--regexp-ignore-case", "--grep="+string(req.GetMessageQuery()))
+	}
+	if req.GetRange() != "" {
+		args = append(args, req.GetRange())
+	}
+	if req.GetNameOnly() {
+		args = append(args, "--name-only")
+	}
+	if req.GetFollow() {
+		args = append(args, "--follow")
+	}
+	if len(req.GetPath()) != 0 {
+		args = append(args, "--", string(req.GetPath()))
+	}
+	return args
+}
+
+func (gs *GRPCServer) DiffSymbols(req *proto.DiffSymbolsRequest, ss proto.GitserverService_DiffSymbolsServer) error {
+	// Log which actor is accessing the repo.
+	accesslog.Record(ss.Context(), req.GetRepo(),
+		log.String("commitA", req.GetCommitA()),
+		log.String("commitB", req.GetCommitB()),
+	)
+
+	if req.GetRepo() == "" || req.GetCommitA() == "" || req.GetCommitB() == "" {
+		return status.Error(codes.InvalidArgument, "repo, commit_a and commit_b must be specified")
+	}
+	for _, commit := range []string{req.GetCommitA(), req.GetCommitB()} {
+		if err := git.CheckSpecArgSafety(commit); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	execReq := &protocol.ExecRequest{
+		Repo: api.RepoName(req.GetRepo()),
+		Args: []string{"diff", "-z", "--name-status", "--no-renames", req.GetCommitA(), req.GetCommitB()},
+	}
+
+	var buf bytes.Buffer
+	err := gs.runGitCommand(ss.Context(), execReq, &buf, func(stderr string) error {
+		if spec, ok := revisionNotFoundSpec(stderr); ok {
+			return newRevisionNotFoundError(req.GetRepo(), spec)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	files, err := parseDiffNameStatus(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	for len(files) > 0 {
+		n := min(len(files), diffSymbolsChunkSize)
+		if err := ss.Send(&proto.DiffSymbolsResponse{Files: files[:n]}); err != nil {
+			return err
+		}
+		files = files[n:]
+	}
+	return nil
+}
+
+// diffSymbolsChunkSize is the number of changed files sent in a single
+// DiffSymbolsResponse message.
+const diffSymbolsChunkSize = 1000
+
+// parseDiffNameStatus parses the output of
+// `git diff -z --name-status --no-renames`, which is a sequence of
+// null-terminated status and path pairs. Statuses other than added, modified
+// and deleted are skipped.
+func parseDiffNameStatus(out []byte) ([]*proto.ChangedFile, error) {
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	fields := bytes.Split(bytes.TrimRight(out, "\x00"), []byte{0})
+	if len(fields)%2 != 0 {
+		return nil, errors.New("uneven pairs")
+	}
+
+	files := make([]*proto.ChangedFile, 0, len(fields)/2)
+	for i := 0; i < len(fields); i += 2 {
+		var status proto.ChangedFile_Status
+		switch fields[i][0] {
+		case 'A':
+			status = proto.ChangedFile_STATUS_ADDED
+		case 'M':
+			status = proto.ChangedFile_STATUS_MODIFIED
+		case 'D':
+			status = proto.ChangedFile_STATUS_DELETED
+		default:
+			continue
+		}
+		files = append(files, &proto.ChangedFile{
+			Path:   fields[i+1],
+			Status: status,
+		})
+	}
+	return files, nil
+}
+
+func (gs *GRPCServer) ContributorCounts(ctx context.Context, req *proto.ContributorCountsRequest) (*proto.ContributorCountsResponse, error) {
+	// Log which actor is accessing the repo.
+	accesslog.Record(ctx, req.GetRepo(),
+		log.String("range", req.GetRange()),
+		log.String("after", req.GetAfter()),
+		log.String("path", req.GetPath()),
+	)
+
+	if req.GetRepo() == "" {
+		return nil, status.Error(codes.InvalidArgument, "repo must be specified")
+	}
+
+	revRange := req.GetRange()
+	if revRange == "" {
+		revRange = "HEAD"
+	}
+	if err := git.CheckSpecArgSafety(revRange); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// We split the individual args for the shortlog command instead of -sne for easier arg checking in the allowlist.
+	args := []string{"shortlog", "-s", "-n", "-e", "--no-merges"}
+	if req.GetAfter() != "" {
+		args = append(args, "--after="+req.GetAfter())
+	}
+	args = append(args, revRange, "--")
+	if req.GetPath() != "" {
+		args = append(args, req.GetPath())
+	}
+
+	execReq := &protocol.ExecRequest{
+		Repo: api.RepoName(req.GetRepo()),
+		Args: args,
+	}
+
+	var buf bytes.Buffer
+	err := gs.runGitCommand(ctx, execReq, &buf, func(stderr string) error {
+		if _, ok := revisionNotFoundSpec(stderr); ok {
+			return newRevisionNotFoundError(req.GetRepo(), revRange)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := gitdomain.ParseShortLog(buf.Bytes())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &proto.ContributorCountsResponse{
+		Counts: make([]*proto.ContributorCount, 0, len(counts)),
+	}
+	for _, c := range counts {
+		resp.Counts = append(resp.Counts, c.ToProto())
+	}
+	return resp, nil
+}
+
+// getBlobsChunkSize is the maximum number of bytes of blob contents sent in a
+// single GetBlobsResponse message.
+const getBlobsChunkSize = 1024 * 1024 // 1 MiB
+
+func (gs *GRPCServer) GetBlobs(req *proto.GetBlobsRequest, ss proto.GitserverService_GetBlobsServer) error {
+	// Log which actor is accessing the repo.
+	accesslog.Record(ss.Context(), req.GetRepo(),
+		log.Int("oids", len(req.GetOids())),
+	)
+
+	if req.GetRepo() == "" {
+		return status.Error(codes.InvalidArgument, "repo must be specified")
+	}
+	if len(req.GetOids()) == 0 {
+		return nil
+	}
+	for _, oid := range req.GetOids() {
+		if !gitdomain.IsAbsoluteRevision(oid) {
+			return status.Errorf(codes.InvalidArgument, "invalid object ID %q", oid)
+		}
+	}
+
+	execReq := &protocol.ExecRequest{
+		Repo:  api.RepoName(req.GetRepo()),
+		Args:  []string{"cat-file", "--batch"},
+		Stdin: []byte(strings.Join(req.GetOids(), "\n") + "\n"),
+	}
+
+	ctx, cancel := context.WithCancel(ss.Context())
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := gs.runGitCommand(ctx, execReq, pw, nil)
+		pw.CloseWithError(err)
+		done <- err
+	}()
+
+	sendErr := gs.sendBlobs(req.GetRepo(), gitdomain.NewCatFileBatchReader(pr), ss)
+	// Unblock git if we stopped reading its output early.
+	pr.CloseWithError(errors.New("GetBlobs: stopped reading blobs"))
+	cancel()
+	runErr := <-done
+
+	// If git failed, reading its output fails with the same error.
+	if runErr != nil && (sendErr == nil || errors.Is(sendErr, runErr)) {
+		return runErr
+	}
+	return sendErr
+}
+
+// sendBlobs streams the blobs read by r to the client. Like files read with
+// git show, Git LFS pointers are replaced by the contents of the objects
+// they point to if those were fetched.
+func (gs *GRPCServer) sendBlobs(repo string, r *gitdomain.CatFileBatchReader, ss proto.GitserverService_GetBlobsServer) error {
+	dir := gitserverfs.RepoDirFromName(gs.Server.ReposDir, api.RepoName(repo))
+	hasLFSObjects := git.HasLFSObjects(dir)
+
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if h.Missing {
+			return newRevisionNotFoundError(repo, h.OID)
+		}
+		if h.Type != gitdomain.ObjectTypeBlob {
+			return status.Errorf(codes.InvalidArgument, "object %s is a %s, not a blob", h.OID, h.Type)
+		}
+
+		if !hasLFSObjects || h.Size >= git.LFSPointerMaxSize {
+			if err := sendBlob(ss, h.OID, h.Size, r); err != nil {
+				return err
+			}
+			continue
+		}
+
+		blob := make([]byte, h.Size)
+		if _, err := io.ReadFull(r, blob); err != nil {
+			return err
+		}
+		if err := sendMaybeLFSBlob(ss, dir, h.OID, blob); err != nil {
+			return err
+		}
+	}
+}
+
+// sendMaybeLFSBlob sends the contents of the Git LFS object blob points to,
+// or blob itself if it isn't a pointer or the object wasn't fetched.
+func sendMaybeLFSBlob(ss proto.GitserverService_GetBlobsServer, dir common.GitDir, oid string, blob []byte) error {
+	if p, ok := git.ParseLFSPointer(blob); ok {
+		if f, err := git.OpenLFSObject(dir, p); err == nil {
+			defer f.Close()
+			return sendBlob(ss, oid, p.Size, f)
+		}
+	}
+	return sendBlob(ss, oid, int64(len(blob)), bytes.NewReader(blob))
+}
+
+// sendBlob sends size bytes read from r as the contents of the blob oid, in
+// chunks of at most getBlobsChunkSize bytes.
+func sendBlob(ss proto.GitserverService_GetBlobsServer, oid string, size int64, r io.Reader) error {
+	resp := &proto.GetBlobsResponse{Oid: oid, Size: size}
+	remaining := size
+	for {
+		// Empty blobs are sent as a single message without data.
+		data := make([]byte, min(remaining, getBlobsChunkSize))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return err
+		}
+		remaining -= int64(len(data))
+		resp.Data = data
+		if err := ss.Send(resp); err != nil {
+			return err
+		}
+		if remaining == 0 {
+			return nil
+		}
+		resp = &proto.GetBlobsResponse{}
+	}
+}
+
+// revisionNotFoundPatterns match the messages git prints to stderr when it is
+// asked about a revision that doesn't exist in the repository. The first
+// submatch is the revision as it was given to git.
+var revisionNotFoundPatterns = []*lazyregexp.Regexp{
+	lazyregexp.New(`fatal: bad revision '([^']*)'`),
+	lazyregexp.New(`fatal: ambiguous argument '([^']*)': unknown revision`),
+	lazyregexp.New(`fatal: Not a valid object name (\S+)`),
+	lazyregexp.New(`fatal: Not a valid commit name (\S+)`),
+	lazyregexp.New(`fatal: bad object (\S+)`),
+	lazyregexp.New(`fatal: tree-ish (\S+) not found`),
+	lazyregexp.New(`fatal: bad tree-ish (\S+)`),
+}
+
+// revisionNotFoundSpec reports whether the given git stderr output indicates
+// that a revision doesn't exist, and if so, which one.
+func revisionNotFoundSpec(stderr string) (string, bool) {
+	for _, p := range revisionNotFoundPatterns {
+		if m := p.FindStringSubmatch(stderr); m != nil {
+			return m[1], true
+		}
+	}
+	return "", false
+}
+
+// newRevisionNotFoundError returns a NotFound status error carrying a
+// RevisionNotFoundPayload, which the client translates into a
+// gitdomain.RevisionNotFoundError.
+func newRevisionNotFoundError(repo, spec string) error {
+	s, err := status.New(codes.NotFound, "revision not found").WithDetails(&proto.RevisionNotFoundPayload{
+		Repo: repo,
+		Spec: spec,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Err()
+}
+
+// newFileNotFoundError returns a NotFound status error carrying a
+// FileNotFoundPayload, which the client translates into an error matching
+// os.ErrNotExist.
+func newFileNotFoundError(repo, commit, path string) error {
+	s, err := status.New(codes.NotFound, "file not found").WithDetails(&proto.FileNotFoundPayload{
+		Repo:   repo,
+		Commit: commit,
+		Path:   path,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Err()
+}
+
 func byteSlicesToStrings(in [][]byte) []string {
 	res := make([]string, len(in))
 	for i, b := range in {
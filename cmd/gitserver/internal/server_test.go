@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"archive/tar"
 	"bytes"
 	"container/list"
 	"context"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/executil"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/gitserverfs"
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/perforce"
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/vcssyncer"
@@ -261,6 +263,57 @@ func TestExecRequest(t *testing.T) {
 	}
 }
 
+func TestExec_PartialClone(t *testing.T) {
+	reposDir := t.TempDir()
+	repo := api.RepoName("example.com/monorepo")
+	remote := makePartialClone(t, reposDir, repo, `mkdir docs
+echo docs > docs/README
+echo 'package main' > main.go
+git add .
+git commit -m init
+`)
+
+	ctx := context.Background()
+	s := makeTestServer(ctx, t, reposDir, "", nil)
+	var remoteURLLookups int
+	s.GetRemoteURLFunc = func(context.Context, api.RepoName) (string, error) {
+		remoteURLLookups++
+		return remote, nil
+	}
+
+	execute := func(args ...string) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		status, err := s.exec(ctx, logtest.Scoped(t), &protocol.ExecRequest{Repo: repo, Args: args}, "test", &buf)
+		require.NoError(t, err)
+		require.NoError(t, status.Err, status.Stderr)
+		require.Equal(t, 0, status.ExitStatus, status.Stderr)
+		return buf.Bytes()
+	}
+
+	// Reading files and creating archives fetch the missing blobs.
+	assert.Equal(t, "package main\n", string(execute("show", "HEAD:main.go")))
+
+	files := map[string]string{}
+	tr := tar.NewReader(bytes.NewReader(execute("archive", "--format=tar", "HEAD")))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if hdr.Typeflag == tar.TypeReg {
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			files[hdr.Name] = string(content)
+		}
+	}
+	assert.Equal(t, map[string]string{"docs/README": "docs\n", "main.go": "package main\n"}, files)
+
+	// The remote URL is looked up once and reused by later commands.
+	assert.Equal(t, 1, remoteURLLookups)
+}
+
 func staticGetRemoteURL(remote string) func(context.Context, api.RepoName) (string, error) {
 	return func(context.Context, api.RepoName) (string, error) {
 		return remote, nil
@@ -329,6 +382,41 @@ func makeTestServer(ctx context.Context, t *testing.T, repoDir, remote string, d
 	return s
 }
 
+// makePartialClone runs script in a new repository and clones it into
+// reposDir as a blobless partial clone of repo. Like the partial clones
+// created by gitserver, the clone doesn't store the URL of its remote, which
+// is returned.
+func makePartialClone(t *testing.T, reposDir string, repo api.RepoName, script string) string {
+	t.Helper()
+
+	remote := t.TempDir()
+	script = `git init --initial-branch=main
+git config uploadpack.allowFilter true
+git config uploadpack.allowAnySHA1InWant true
+git config user.name a
+git config user.email a@a.com
+` + script
+	cmd := exec.Command("/bin/sh", "-euxc", script)
+	cmd.Dir = remote
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("out=%s, err=%s", out, err)
+	}
+
+	dir := gitserverfs.RepoDirFromName(reposDir, repo)
+	cmd = exec.Command("git", "clone", "--bare", "--filter=blob:none", "file://"+remote, dir.Path())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("out=%s, err=%s", out, err)
+	}
+	cmd = exec.Command("git", "config", "--unset", "remote."+git.PartialCloneRemote+".url")
+	cmd.Dir = dir.Path()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("out=%s, err=%s", out, err)
+	}
+	require.True(t, git.IsPartialClone(dir))
+
+	return "file://" + remote
+}
+
 func TestCloneRepo(t *testing.T) {
 	logger := logtest.Scoped(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -239,6 +239,18 @@ func hostnameMatch(shardID, addr string) bool {
 	return next == '.' || next == ':'
 }
 
+// shardIDsForAddr returns all shard IDs that hostnameMatch matches with the
+// given address, starting with the longest.
+func shardIDsForAddr(addr string) []string {
+	shardIDs := []string{addr}
+	for i := len(addr) - 1; i > 0; i-- {
+		if addr[i] == '.' || addr[i] == ':' {
+			shardIDs = append(shardIDs, addr[:i])
+		}
+	}
+	return shardIDs
+}
+
 // Send 1 in 16 events to honeycomb. This is hardcoded since we only use this
 // for Sourcegraph.com.
 //
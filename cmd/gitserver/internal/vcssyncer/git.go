@@ -24,12 +24,27 @@ import (
 type gitRepoSyncer struct {
 	logger                  log.Logger
 	recordingCommandFactory *wrexec.RecordingCommandFactory
+	// partialCloneURL is matched against the partial clone mappings when
+	// cloning instead of the remote URL if it is set.
+	partialCloneURL *vcs.URL
 }
 
 func NewGitRepoSyncer(logger log.Logger, r *wrexec.RecordingCommandFactory) *gitRepoSyncer {
 	return &gitRepoSyncer{logger: logger.Scoped("GitRepoSyncer"), recordingCommandFactory: r}
 }
 
+// NewReplicaGitRepoSyncer returns a syncer for a secondary copy of a repo,
+// which is fetched from the primary gitserver instead of the code host.
+// originURL is the URL of the repo on the code host. If it matches a partial
+// clone mapping, the secondary copy is cloned with the same filter as the
+// primary copy, so that the primary doesn't have to fetch all the objects it
+// filtered out from the code host.
+func NewReplicaGitRepoSyncer(logger log.Logger, r *wrexec.RecordingCommandFactory, originURL *vcs.URL) *gitRepoSyncer {
+	s := NewGitRepoSyncer(logger, r)
+	s.partialCloneURL = originURL
+	return s
+}
+
 func (s *gitRepoSyncer) Type() string {
 	return "git"
 }
@@ -85,7 +100,11 @@ func (s *gitRepoSyncer) Clone(ctx context.Context, repo api.RepoName, remoteURL
 
 	// Now we build our fetch command. We don't actually clone, instead we init
 	// a bare repository and fetch all refs from remote once into local refs.
-	cmd, _ := s.fetchCommand(ctx, remoteURL)
+	partialCloneURL := remoteURL
+	if s.partialCloneURL != nil {
+		partialCloneURL = s.partialCloneURL
+	}
+	cmd, _ := s.fetchCommand(ctx, remoteURL, partialCloneOptionsFor(partialCloneMappings(), partialCloneURL))
 	cmd.Dir = tmpPath
 	if cmd.Env == nil {
 		cmd.Env = os.Environ()
@@ -110,7 +129,14 @@ func (s *gitRepoSyncer) Clone(ctx context.Context, repo api.RepoName, remoteURL
 
 // Fetch tries to fetch updates of a Git repository.
 func (s *gitRepoSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, repoName api.RepoName, dir common.GitDir, _ string) ([]byte, error) {
-	cmd, configRemoteOpts := s.fetchCommand(ctx, remoteURL)
+	var partial *partialCloneOptions
+	if git.IsPartialClone(dir) {
+		// Keep fetching with the filter the repository was cloned with, even if
+		// it no longer matches a partial clone mapping. Converting it into a
+		// full clone requires a reclone.
+		partial = &partialCloneOptions{}
+	}
+	cmd, configRemoteOpts := s.fetchCommand(ctx, remoteURL, partial)
 	dir.Set(cmd)
 	r := urlredactor.New(remoteURL)
 	output, err := executil.RunRemoteGitCommand(ctx, s.recordingCommandFactory.WrapWithRepoName(ctx, log.NoOp(), repoName, cmd).WithRedactorFunc(r.Redact), configRemoteOpts)
@@ -125,16 +151,28 @@ func (s *gitRepoSyncer) RemoteShowCommand(ctx context.Context, remoteURL *vcs.UR
 	return exec.CommandContext(ctx, "git", "remote", "show", remoteURL.String()), nil
 }
 
-func (s *gitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (cmd *exec.Cmd, configRemoteOpts bool) {
-	configRemoteOpts = true
+// fetchCommand returns the command used to fetch all refs of the remote. If
+// partial is non-nil, the command fetches from the promisor remote of a partial
+// clone instead of the remote URL.
+func (s *gitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL, partial *partialCloneOptions) (cmd *exec.Cmd, configRemoteOpts bool) {
 	if customCmd := customFetchCmd(ctx, remoteURL); customCmd != nil {
-		cmd = customCmd
-		configRemoteOpts = false
-	} else if useRefspecOverrides() {
-		cmd = refspecOverridesFetchCmd(ctx, remoteURL)
+		return customCmd, false
+	}
+
+	args := []string{"fetch", "--progress", "--prune"}
+	remote := remoteURL.String()
+	if partial != nil {
+		if partial.filter != "" {
+			args = append(args, "--filter="+partial.filter)
+		}
+		remote = git.PartialCloneRemote
+	}
+	args = append(args, remote)
+
+	if useRefspecOverrides() {
+		cmd = refspecOverridesFetchCmd(ctx, args)
 	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch",
-			"--progress", "--prune", remoteURL.String(),
+		cmd = exec.CommandContext(ctx, "git", append(args,
 			// Normal git refs
 			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
 			// GitHub pull requests
@@ -146,9 +184,15 @@ func (s *gitRepoSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) (c
 			// Gerrit changesets
 			"+refs/changes/*:refs/changes/*",
 			// Possibly deprecated refs for sourcegraph zap experiment?
-			"+refs/sourcegraph/*:refs/sourcegraph/*")
+			"+refs/sourcegraph/*:refs/sourcegraph/*")...)
+	}
+
+	if partial != nil {
+		// 🚨 SECURITY: The remote URL is passed through the environment so that
+		// git doesn't store it in the repository's config.
+		cmd.Env = append(os.Environ(), git.PromisorRemoteEnv(remoteURL.String())...)
 	}
-	return cmd, configRemoteOpts
+	return cmd, true
 }
 
 func isAlwaysCloningTestRemoteURL(remoteURL *vcs.URL) bool {
@@ -0,0 +1,45 @@
+package vcssyncer
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackHexPackage(t *testing.T) {
+	contents := createTgz(t, []fileInfo{
+		{path: "mix.exs", contents: []byte("defmodule Phoenix.MixProject do end")},
+		{path: "lib/phoenix.ex", contents: []byte("defmodule Phoenix do end")},
+		{path: "../escape.ex", contents: []byte("filter me")},
+	})
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range []fileInfo{
+		{path: "VERSION", contents: []byte("3")},
+		{path: "CHECKSUM", contents: []byte("0123456789ABCDEF")},
+		{path: "metadata.config", contents: []byte(`{<<"name">>,<<"phoenix">>}.`)},
+		{path: "contents.tar.gz", contents: contents},
+	} {
+		require.NoError(t, addFileToTarball(t, tw, f))
+	}
+	require.NoError(t, tw.Close())
+
+	tmp := t.TempDir()
+	require.NoError(t, unpackHexPackage(&buf, tmp))
+
+	assert.Equal(t, []string{
+		"hex_metadata.config",
+		"lib/phoenix.ex",
+		"mix.exs",
+	}, listFiles(t, tmp))
+
+	metadata, err := os.ReadFile(filepath.Join(tmp, "hex_metadata.config"))
+	require.NoError(t, err)
+	assert.Equal(t, `{<<"name">>,<<"phoenix">>}.`, string(metadata))
+}
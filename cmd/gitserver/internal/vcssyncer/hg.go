@@ -0,0 +1,168 @@
+package vcssyncer
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/executil"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/urlredactor"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/wrexec"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// hgRemoteName is the name of the remote that git-remote-hg is invoked with.
+// The helper keeps its Mercurial clone and the marks of all converted
+// changesets in $GIT_DIR/hg/<remote name>. The remote URL is never written to
+// the Git config, so a fixed name keeps that state in place when the
+// credentials embedded in the URL change.
+const hgRemoteName = "origin"
+
+// hgSyncer is a syncer for Mercurial repositories. Changesets are converted
+// into Git commits with the git-remote-hg remote helper.
+//
+// The conversion is incremental: changesets that were converted before are
+// looked up in the marks kept in the Git directory instead of being converted
+// again, so existing commit SHAs never change on fetch. A fresh clone converts
+// the whole history again. Commits are built only from the contents of the
+// changesets and the settings pinned in fetchCommand, so it produces the same
+// commit SHAs as long as the git-remote-hg version stays the same, which
+// TestHgSyncer_CloneAndFetch checks. The helper records the Mercurial changeset
+// ID of every commit as a note in refs/notes/hg, which is what
+// gitserver.Client.HgChangesetCommit looks up.
+type hgSyncer struct {
+	logger                  log.Logger
+	recordingCommandFactory *wrexec.RecordingCommandFactory
+}
+
+func NewHgSyncer(logger log.Logger, r *wrexec.RecordingCommandFactory) VCSSyncer {
+	return &hgSyncer{logger: logger.Scoped("HgSyncer"), recordingCommandFactory: r}
+}
+
+func (s *hgSyncer) Type() string {
+	return "hg"
+}
+
+// IsCloneable checks to see if the Mercurial remote URL is cloneable.
+func (s *hgSyncer) IsCloneable(ctx context.Context, repoName api.RepoName, remoteURL *vcs.URL) error {
+	args := []string{
+		"identify", "--id", "--noninteractive",
+		// Fail instead of asking to add the host key to known_hosts, and avoid
+		// hanging indefinitely if the server is unreachable.
+		"--config", "ui.ssh=ssh -o BatchMode=yes -o ConnectTimeout=30",
+		remoteURL.String(),
+	}
+	ctx, cancel := context.WithTimeout(ctx, executil.ShortGitCommandTimeout(args))
+	defer cancel()
+
+	r := urlredactor.New(remoteURL)
+	cmd := exec.CommandContext(ctx, "hg", args...)
+	cmd.Env = hgCommandEnv()
+	out, err := executil.RunCommandCombinedOutput(ctx, s.recordingCommandFactory.WrapWithRepoName(ctx, log.NoOp(), repoName, cmd).WithRedactorFunc(r.Redact))
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = &common.GitCommandError{Err: err, Output: r.Redact(string(out))}
+		}
+		return err
+	}
+	return nil
+}
+
+// Clone converts a Mercurial repository into a Git repository in tmpPath,
+// reporting redacted progress logs via the progressWriter.
+func (s *hgSyncer) Clone(ctx context.Context, repo api.RepoName, remoteURL *vcs.URL, _ common.GitDir, tmpPath string, progressWriter io.Writer) (err error) {
+	// First, make sure the tmpPath exists.
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+
+	tryWrite(s.logger, progressWriter, "Creating bare repo\n")
+	if err := git.MakeBareRepo(ctx, tmpPath); err != nil {
+		return &common.GitCommandError{Err: err}
+	}
+	tryWrite(s.logger, progressWriter, "Created bare repo at %s\n", tmpPath)
+
+	// The clone is a regular fetch into the empty repository. The Mercurial
+	// clone and marks created by git-remote-hg end up in tmpPath and move along
+	// with it, so later fetches only convert new changesets.
+	cmd := s.fetchCommand(ctx, remoteURL)
+	common.GitDir(tmpPath).Set(cmd)
+
+	tryWrite(s.logger, progressWriter, "Converting Mercurial repository using git-remote-hg\n")
+	redactor := urlredactor.New(remoteURL)
+	wrCmd := s.recordingCommandFactory.WrapWithRepoName(ctx, s.logger, repo, cmd).WithRedactorFunc(redactor.Redact)
+	// Note: Using RunCommandWriteOutput here does NOT store the output of the
+	// command as the command output of the wrexec command, because the pipes are
+	// already used.
+	exitCode, err := executil.RunCommandWriteOutput(ctx, wrCmd, progressWriter, redactor.Redact)
+	if err != nil {
+		return errors.Wrapf(err, "failed to run hg->git conversion: exit code %d", exitCode)
+	}
+
+	return nil
+}
+
+// Fetch converts new changesets of a Mercurial repository into the existing
+// Git repository.
+func (s *hgSyncer) Fetch(ctx context.Context, remoteURL *vcs.URL, repoName api.RepoName, dir common.GitDir, _ string) ([]byte, error) {
+	cmd := s.fetchCommand(ctx, remoteURL)
+	dir.Set(cmd)
+	r := urlredactor.New(remoteURL)
+	output, err := executil.RunCommandCombinedOutput(ctx, s.recordingCommandFactory.WrapWithRepoName(ctx, log.NoOp(), repoName, cmd).WithRedactorFunc(r.Redact))
+	if err != nil {
+		return nil, &common.GitCommandError{Err: err, Output: r.Redact(string(output))}
+	}
+	return output, nil
+}
+
+// RemoteShowCommand returns the command to be executed for showing the Git
+// remote of a converted Mercurial repository.
+func (s *hgSyncer) RemoteShowCommand(ctx context.Context, _ *vcs.URL) (cmd *exec.Cmd, err error) {
+	// git-remote-hg converts the default branch to master, which is also the
+	// HEAD of the local repository.
+	return exec.CommandContext(ctx, "git", "remote", "show", "./"), nil
+}
+
+func (s *hgSyncer) fetchCommand(ctx context.Context, remoteURL *vcs.URL) *exec.Cmd {
+	args := []string{
+		// Configure the remote for this invocation only, so that credentials
+		// in the URL are not persisted in the Git config.
+		"-c", "remote." + hgRemoteName + ".url=hg::" + remoteURL.String(),
+		// These settings change how commits are converted, and so the
+		// resulting commit SHAs. They are pinned so that a change in the
+		// global Git config cannot rewrite the history of existing repos.
+		"-c", "remote-hg.track-branches=true",
+		"-c", "remote-hg.hg-git-compat=false",
+		"fetch", "--progress", "--prune", hgRemoteName,
+		// Bookmarks and named branches (as branches/<name>) are converted
+		// to branches, Mercurial tags to Git tags.
+		"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = hgCommandEnv()
+	return cmd
+}
+
+// hgCommandEnv returns the environment for commands that run Mercurial.
+func hgCommandEnv() []string {
+	return append(os.Environ(),
+		// Disable user configuration that changes the output of Mercurial,
+		// such as aliases or localization.
+		"HGPLAIN=1",
+		"HGENCODING=utf-8",
+		// Disable password prompts in git, the remote helper runs
+		// non-interactively.
+		"GIT_ASKPASS=true",
+	)
+}
@@ -0,0 +1,384 @@
+package vcssyncer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// defaultLFSMaxObjectSize is the maximum size of the Git LFS objects that are
+// fetched for repositories that don't configure one explicitly.
+const defaultLFSMaxObjectSize = 10 * 1024 * 1024 // 10 MiB
+
+// lfsBatchSize is the maximum number of objects requested from the Git LFS
+// batch API at once.
+const lfsBatchSize = 100
+
+var lfsMappings = conf.Cached(func() []*schema.GitLFSMapping {
+	return conf.ExperimentalFeatures().GitLFS
+})
+
+// lfsMaxObjectSizeFor returns the maximum object size of the first mapping
+// that matches remoteURL, or false if Git LFS objects of the repository
+// shouldn't be fetched.
+func lfsMaxObjectSizeFor(mappings []*schema.GitLFSMapping, remoteURL *vcs.URL) (int64, bool) {
+	for _, mapping := range mappings {
+		if !matchesDomainPath(mapping.DomainPath, remoteURL) {
+			continue
+		}
+		if mapping.MaxObjectSize > 0 {
+			return int64(mapping.MaxObjectSize), true
+		}
+		return defaultLFSMaxObjectSize, true
+	}
+	return 0, false
+}
+
+// FetchLFSObjects fetches the Git LFS objects referenced by the commit HEAD
+// points to from the code host, and removes all other objects that were
+// fetched before. Objects larger than the configured maximum size are
+// skipped. If Git LFS isn't enabled for the repository, all previously
+// fetched objects are removed.
+//
+// HEAD must be set before calling this function.
+func FetchLFSObjects(ctx context.Context, logger log.Logger, cli httpcli.Doer, dir common.GitDir, remoteURL *vcs.URL) error {
+	maxSize, ok := lfsMaxObjectSizeFor(lfsMappings(), remoteURL)
+	if !ok {
+		return os.RemoveAll(dir.Path("lfs"))
+	}
+	if remoteURL.Scheme != "http" && remoteURL.Scheme != "https" {
+		// The batch API can only be reached over SSH with git-lfs-authenticate,
+		// which isn't supported.
+		logger.Debug("skipping Git LFS objects of repository not cloned over HTTP(S)")
+		return nil
+	}
+
+	pointers, err := lfsPointersAtHEAD(ctx, dir)
+	if err != nil {
+		return errors.Wrap(err, "listing Git LFS pointers")
+	}
+
+	keep := make(map[string]struct{}, len(pointers))
+	var missing []git.LFSPointer
+	for _, p := range pointers {
+		if p.Size > maxSize {
+			continue
+		}
+		keep[p.OID] = struct{}{}
+		if f, err := git.OpenLFSObject(dir, p); err == nil {
+			f.Close()
+			continue
+		}
+		missing = append(missing, p)
+	}
+
+	var errs error
+	for len(missing) > 0 {
+		batch := missing[:min(len(missing), lfsBatchSize)]
+		missing = missing[len(batch):]
+		if err := fetchLFSBatch(ctx, cli, dir, remoteURL, batch); err != nil {
+			errs = errors.Append(errs, err)
+		}
+	}
+
+	if err := pruneLFSObjects(dir, keep); err != nil {
+		errs = errors.Append(errs, errors.Wrap(err, "pruning Git LFS objects"))
+	}
+
+	// The attributes file marks the repository as having Git LFS objects, so it
+	// is written last.
+	if err := os.MkdirAll(dir.Path("lfs"), 0o755); err != nil {
+		errs = errors.Append(errs, err)
+	} else if err := os.WriteFile(git.LFSAttributesPath(dir), []byte("* filter=lfs\n"), 0o600); err != nil {
+		errs = errors.Append(errs, errors.Wrap(err, "writing Git LFS attributes"))
+	}
+
+	return errs
+}
+
+// lfsPointersAtHEAD returns the Git LFS pointers of all files at HEAD.
+func lfsPointersAtHEAD(ctx context.Context, dir common.GitDir) ([]git.LFSPointer, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-tree", "-r", "-z", "--long", "HEAD")
+	dir.Set(cmd)
+	// Partial clones may lack blobs, which are fetched in full from the
+	// code host later if they are ever read.
+	cmd.Env = append(os.Environ(), "GIT_NO_LAZY_FETCH=1")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	oids := parseLsTreeSmallBlobs(out, git.LFSPointerMaxSize)
+	if len(oids) == 0 {
+		return nil, nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	dir.Set(cmd)
+	cmd.Env = append(os.Environ(), "GIT_NO_LAZY_FETCH=1")
+	cmd.Stdin = strings.NewReader(strings.Join(oids, "\n") + "\n")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var pointers []git.LFSPointer
+	r := gitdomain.NewCatFileBatchReader(stdout)
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = cmd.Wait()
+			return nil, err
+		}
+		if h.Missing {
+			continue
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			_ = cmd.Wait()
+			return nil, err
+		}
+		if p, ok := git.ParseLFSPointer(b); ok {
+			pointers = append(pointers, p)
+		}
+	}
+	return pointers, cmd.Wait()
+}
+
+// parseLsTreeSmallBlobs returns the distinct object IDs of all blobs smaller
+// than maxSize in the NUL terminated output of git ls-tree -z --long.
+func parseLsTreeSmallBlobs(out []byte, maxSize int64) []string {
+	var oids []string
+	seen := make(map[string]struct{})
+	for _, entry := range bytes.Split(out, []byte{0}) {
+		// Each entry has the form "<mode> SP <type> SP <object> SP+ <size> TAB <path>".
+		info, _, ok := bytes.Cut(entry, []byte{'\t'})
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(string(info))
+		if len(fields) != 4 || fields[1] != "blob" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil || size >= maxSize {
+			continue
+		}
+		if _, ok := seen[fields[2]]; ok {
+			continue
+		}
+		seen[fields[2]] = struct{}{}
+		oids = append(oids, fields[2])
+	}
+	return oids
+}
+
+// lfsEndpoint returns the URL of the Git LFS server of the repository at
+// remoteURL, without credentials.
+// https://github.com/git-lfs/git-lfs/blob/main/docs/api/server-discovery.md
+func lfsEndpoint(remoteURL *vcs.URL) string {
+	u := remoteURL.URL
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	if !strings.HasSuffix(u.Path, ".git") {
+		u.Path += ".git"
+	}
+	u.Path += "/info/lfs"
+	u.RawPath = ""
+	return u.String()
+}
+
+type lfsObject struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type lfsBatchRequest struct {
+	Operation string      `json:"operation"`
+	Transfers []string    `json:"transfers"`
+	Objects   []lfsObject `json:"objects"`
+	HashAlgo  string      `json:"hash_algo"`
+}
+
+type lfsBatchResponse struct {
+	Objects []struct {
+		lfsObject
+		Actions struct {
+			Download *struct {
+				Href   string            `json:"href"`
+				Header map[string]string `json:"header"`
+			} `json:"download"`
+		} `json:"actions"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"objects"`
+}
+
+const lfsMediaType = "application/vnd.git-lfs+json"
+
+// fetchLFSBatch downloads the given objects using the Git LFS batch API.
+// https://github.com/git-lfs/git-lfs/blob/main/docs/api/batch.md
+func fetchLFSBatch(ctx context.Context, cli httpcli.Doer, dir common.GitDir, remoteURL *vcs.URL, pointers []git.LFSPointer) error {
+	batchReq := lfsBatchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		HashAlgo:  "sha256",
+	}
+	for _, p := range pointers {
+		batchReq.Objects = append(batchReq.Objects, lfsObject{OID: p.OID, Size: p.Size})
+	}
+	body, err := json.Marshal(batchReq)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lfsEndpoint(remoteURL)+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	// 🚨 SECURITY: The credentials of the remote URL are only sent to the LFS
+	// server of the code host, never to the download URLs it returns, which
+	// carry their own authorization headers.
+	if user := remoteURL.User; user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+	}
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "requesting Git LFS batch")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d from Git LFS batch API", resp.StatusCode)
+	}
+	var batchResp lfsBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return errors.Wrap(err, "decoding Git LFS batch response")
+	}
+
+	requested := make(map[string]git.LFSPointer, len(pointers))
+	for _, p := range pointers {
+		requested[p.OID] = p
+	}
+
+	var errs error
+	for _, obj := range batchResp.Objects {
+		// 🚨 SECURITY: The OID is used as a file name, so only objects that were
+		// requested are accepted.
+		p, ok := requested[obj.OID]
+		if !ok {
+			continue
+		}
+		if obj.Error != nil {
+			errs = errors.Append(errs, errors.Errorf("Git LFS object %s: %s (%d)", obj.OID, obj.Error.Message, obj.Error.Code))
+			continue
+		}
+		if obj.Actions.Download == nil {
+			errs = errors.Append(errs, errors.Errorf("Git LFS object %s: no download action", obj.OID))
+			continue
+		}
+		if err := downloadLFSObject(ctx, cli, dir, p, obj.Actions.Download.Href, obj.Actions.Download.Header); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "Git LFS object %s", obj.OID))
+		}
+	}
+	return errs
+}
+
+// downloadLFSObject downloads the contents of the object p points to from
+// href, verifies them and moves them into place.
+func downloadLFSObject(ctx context.Context, cli httpcli.Doer, dir common.GitDir, p git.LFSPointer, href string, header map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, href, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	tmpDir := dir.Path("lfs", "tmp")
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(tmpDir, p.OID)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(resp.Body, p.Size+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n != p.Size {
+		return errors.Errorf("expected %d bytes, got %d", p.Size, n)
+	}
+	if oid := hex.EncodeToString(h.Sum(nil)); oid != p.OID {
+		return errors.Errorf("contents have unexpected hash %s", oid)
+	}
+
+	path := git.LFSObjectPath(dir, p.OID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// pruneLFSObjects removes all fetched Git LFS objects that aren't in keep.
+func pruneLFSObjects(dir common.GitDir, keep map[string]struct{}) error {
+	err := filepath.WalkDir(dir.Path("lfs", "objects"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if _, ok := keep[d.Name()]; ok {
+			return nil
+		}
+		return os.Remove(path)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,134 @@
+package vcssyncer
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/gitserverfs"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/nuget"
+	"github.com/sourcegraph/sourcegraph/internal/unpack"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func NewNuGetPackagesSyncer(
+	connection *schema.NuGetPackagesConnection,
+	svc *dependencies.Service,
+	client *nuget.Client,
+	reposDir string,
+) VCSSyncer {
+	return &vcsPackagesSyncer{
+		logger:      log.Scoped("NuGetPackagesSyncer"),
+		typ:         "nuget_packages",
+		scheme:      dependencies.NuGetPackagesScheme,
+		placeholder: reposource.NewNuGetVersionedPackage("Sourcegraph.Placeholder", "0.0.0"),
+		svc:         svc,
+		configDeps:  connection.Dependencies,
+		reposDir:    reposDir,
+		source:      &nugetDependencySource{client: client, reposDir: reposDir},
+	}
+}
+
+type nugetDependencySource struct {
+	client   *nuget.Client
+	reposDir string
+}
+
+func (nugetDependencySource) ParseVersionedPackageFromNameAndVersion(name reposource.PackageName, version string) (reposource.VersionedPackage, error) {
+	return reposource.NewNuGetVersionedPackage(name, version), nil
+}
+
+func (nugetDependencySource) ParseVersionedPackageFromConfiguration(dep string) (reposource.VersionedPackage, error) {
+	return reposource.ParseNuGetVersionedPackage(dep), nil
+}
+
+func (nugetDependencySource) ParsePackageFromName(name reposource.PackageName) (reposource.Package, error) {
+	return reposource.ParseNuGetPackageFromName(name), nil
+}
+
+func (nugetDependencySource) ParsePackageFromRepoName(repoName api.RepoName) (reposource.Package, error) {
+	return reposource.ParseNuGetPackageFromRepoName(repoName)
+}
+
+func (s *nugetDependencySource) Download(ctx context.Context, dir string, dep reposource.VersionedPackage) error {
+	pkgContents, err := s.client.GetPackageContents(ctx, dep)
+	if err != nil {
+		return errors.Wrapf(err, "error downloading NuGet package %q", dep.VersionedPackageSyntax())
+	}
+	defer pkgContents.Close()
+
+	if err = unpackNuGetPackage(pkgContents, s.reposDir, dir); err != nil {
+		return errors.Wrapf(err, "failed to unzip NuGet package %q", dep.VersionedPackageSyntax())
+	}
+
+	return nil
+}
+
+// unpackNuGetPackage unpacks the given .nupkg archive into workDir. The files
+// that the NuGet client adds to every package for the Open Packaging
+// Conventions, the package signature and compiled binaries are skipped.
+func unpackNuGetPackage(pkg io.Reader, reposDir, workDir string) error {
+	opts := unpack.Opts{
+		SkipInvalid:    true,
+		SkipDuplicates: true,
+		Filter: func(filePath string, file fs.FileInfo) bool {
+			if isNuGetPackagingFile(filePath) {
+				return false
+			}
+
+			size := file.Size()
+
+			const sizeLimit = 15 * 1024 * 1024
+			if size >= sizeLimit {
+				return false
+			}
+
+			malicious := isPotentiallyMaliciousFilepathInArchive(filePath, workDir)
+			return !malicious
+		},
+	}
+
+	// We cannot unzip in a streaming fashion, so we write the zip file to
+	// a temporary file. Otherwise, we would need to load the entire zip into
+	// memory, which isn't great for multi-megabyte+ files.
+
+	// Create a tmpdir that gitserver manages.
+	tmpdir, err := gitserverfs.TempDir(reposDir, "nuget-packages")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Write the whole package to a temporary file.
+	zip, zipLen, err := writeZipToTemp(tmpdir, pkg)
+	if err != nil {
+		return err
+	}
+	defer zip.Close()
+
+	return unpack.Zip(zip, zipLen, workDir, opts)
+}
+
+func isNuGetPackagingFile(filePath string) bool {
+	if strings.HasPrefix(filePath, "_rels/") || strings.HasPrefix(filePath, "package/") {
+		return true
+	}
+	switch filePath {
+	case "[Content_Types].xml", ".signature.p7s":
+		return true
+	}
+	switch strings.ToLower(path.Ext(filePath)) {
+	case ".dll", ".exe", ".pdb", ".so", ".dylib", ".a", ".nupkg":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package vcssyncer
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackNuGetPackage(t *testing.T) {
+	pkg := createZip(t, []fileInfo{
+		{path: "Newtonsoft.Json.nuspec", contents: []byte("<package/>")},
+		{path: "README.md", contents: []byte("readme")},
+		{path: "contentFiles/cs/any/Helpers.cs", contents: []byte("class Helpers {}")},
+		{path: "lib/net6.0/Newtonsoft.Json.dll", contents: []byte("binary")},
+		{path: "lib/net6.0/Newtonsoft.Json.xml", contents: []byte("<doc/>")},
+		{path: "_rels/.rels", contents: []byte("<Relationships/>")},
+		{path: "package/services/metadata/core-properties/1.psmdcp", contents: []byte("<coreProperties/>")},
+		{path: "[Content_Types].xml", contents: []byte("<Types/>")},
+		{path: ".signature.p7s", contents: []byte("signature")},
+	})
+
+	tmp := t.TempDir()
+	require.NoError(t, unpackNuGetPackage(bytes.NewReader(pkg), t.TempDir(), tmp))
+
+	assert.Equal(t, []string{
+		"Newtonsoft.Json.nuspec",
+		"README.md",
+		"contentFiles/cs/any/Helpers.cs",
+		"lib/net6.0/Newtonsoft.Json.xml",
+	}, listFiles(t, tmp))
+}
+
+func createZip(t *testing.T, fileInfos []fileInfo) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range fileInfos {
+		fw, err := zw.Create(f.path)
+		require.NoError(t, err)
+		_, err = fw.Write(f.contents)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+// listFiles returns the sorted paths of all files in dir, relative to dir.
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	require.NoError(t, err)
+
+	sort.Strings(files)
+	return files
+}
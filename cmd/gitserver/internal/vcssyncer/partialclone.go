@@ -0,0 +1,141 @@
+package vcssyncer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/executil"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/urlredactor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/wrexec"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// defaultPartialCloneFilter is the object filter used for partial clones that
+// don't configure one explicitly. It omits all blobs, which are then fetched
+// lazily when they are read for the first time.
+const defaultPartialCloneFilter = "blob:none"
+
+// TestPartialCloneMappings is a test fixture that overrides the partial clone
+// mappings of the site configuration when it is set.
+var TestPartialCloneMappings []*schema.PartialCloneMapping
+
+var siteConfigPartialCloneMappings = conf.Cached(func() []*schema.PartialCloneMapping {
+	return conf.ExperimentalFeatures().PartialClones
+})
+
+func partialCloneMappings() []*schema.PartialCloneMapping {
+	if TestPartialCloneMappings != nil {
+		return TestPartialCloneMappings
+	}
+	return siteConfigPartialCloneMappings()
+}
+
+// partialCloneOptions describes how a repository is partially cloned.
+type partialCloneOptions struct {
+	// filter is the object filter passed to git fetch. It is empty for
+	// repositories that are already partial clones, in which case git uses the
+	// filter that was stored in the repository's config when it was cloned.
+	filter string
+	// sparsePaths are the paths whose blobs are fetched eagerly for the
+	// default branch.
+	sparsePaths []string
+}
+
+// partialCloneOptionsFor returns the options of the first mapping that
+// matches remoteURL, or nil if the repository should be cloned in full. A
+// mapping matches if its domainPath is equal to the host and path of the
+// remote URL, or a path prefix of it.
+func partialCloneOptionsFor(mappings []*schema.PartialCloneMapping, remoteURL *vcs.URL) *partialCloneOptions {
+	for _, mapping := range mappings {
+		if !matchesDomainPath(mapping.DomainPath, remoteURL) {
+			continue
+		}
+		filter := mapping.Filter
+		if filter == "" {
+			filter = defaultPartialCloneFilter
+		}
+		return &partialCloneOptions{filter: filter, sparsePaths: mapping.SparsePaths}
+	}
+	return nil
+}
+
+// matchesDomainPath returns true if domainPath is equal to the host and path
+// of remoteURL, or a path prefix of it.
+func matchesDomainPath(domainPath string, remoteURL *vcs.URL) bool {
+	dp := strings.TrimSuffix(path.Join(remoteURL.Host, remoteURL.Path), ".git")
+	prefix := strings.TrimSuffix(domainPath, "/")
+	return prefix != "" && (dp == prefix || strings.HasPrefix(dp, prefix+"/"))
+}
+
+// PrefetchSparsePaths fetches the blobs below the sparse paths configured for
+// a partially cloned repository at the commit HEAD points to, so that reading
+// them doesn't require a round trip to the code host. It is a no-op for
+// repositories that aren't partial clones or have no sparse paths configured.
+//
+// HEAD must be set before calling this function.
+func PrefetchSparsePaths(ctx context.Context, logger log.Logger, rcf *wrexec.RecordingCommandFactory, repo api.RepoName, dir common.GitDir, remoteURL *vcs.URL) error {
+	if !git.IsPartialClone(dir) {
+		return nil
+	}
+	opts := partialCloneOptionsFor(partialCloneMappings(), remoteURL)
+	if opts == nil || len(opts.sparsePaths) == 0 {
+		return nil
+	}
+
+	// ls-tree only reads tree objects, which are never filtered out, so this
+	// doesn't trigger any lazy fetches.
+	cmd := exec.CommandContext(ctx, "git", append([]string{"ls-tree", "-r", "-z", "HEAD", "--"}, opts.sparsePaths...)...)
+	dir.Set(cmd)
+	cmd.Env = append(os.Environ(), "GIT_NO_LAZY_FETCH=1")
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Wrap(err, "listing sparse paths")
+	}
+	oids := parseLsTreeBlobs(out)
+	if len(oids) == 0 {
+		return nil
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "fetch", "--no-tags", "--no-write-fetch-head", "--recurse-submodules=no",
+		"--filter="+defaultPartialCloneFilter, git.PartialCloneRemote, "--stdin")
+	dir.Set(cmd)
+	cmd.Env = append(os.Environ(), git.PromisorRemoteEnv(remoteURL.String())...)
+	cmd.Stdin = strings.NewReader(strings.Join(oids, "\n") + "\n")
+	r := urlredactor.New(remoteURL)
+	output, err := executil.RunRemoteGitCommand(ctx, rcf.WrapWithRepoName(ctx, logger, repo, cmd).WithRedactorFunc(r.Redact), true)
+	if err != nil {
+		return &common.GitCommandError{Err: err, Output: r.Redact(string(output))}
+	}
+	return nil
+}
+
+// parseLsTreeBlobs returns the object IDs of all blobs in the NUL terminated
+// output of git ls-tree -z.
+func parseLsTreeBlobs(out []byte) []string {
+	var oids []string
+	for _, entry := range bytes.Split(out, []byte{0}) {
+		// Each entry has the form "<mode> SP <type> SP <object> TAB <path>".
+		info, _, ok := bytes.Cut(entry, []byte{'\t'})
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(string(info))
+		if len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		oids = append(oids, fields[2])
+	}
+	return oids
+}
@@ -0,0 +1,125 @@
+package vcssyncer
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/common"
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/git"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/wrexec"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestPartialCloneOptionsFor(t *testing.T) {
+	mappings := []*schema.PartialCloneMapping{
+		{DomainPath: "github.com/foo/monorepo", SparsePaths: []string{"docs"}},
+		{DomainPath: "gitlab.com/bar/", Filter: "blob:limit=1m"},
+	}
+
+	for _, tc := range []struct {
+		remoteURL string
+		want      *partialCloneOptions
+	}{
+		{
+			remoteURL: "https://github.com/foo/monorepo.git",
+			want:      &partialCloneOptions{filter: "blob:none", sparsePaths: []string{"docs"}},
+		},
+		{
+			remoteURL: "git@github.com:foo/monorepo",
+			want:      &partialCloneOptions{filter: "blob:none", sparsePaths: []string{"docs"}},
+		},
+		{
+			remoteURL: "https://github.com/foo/monorepo-tools",
+			want:      nil,
+		},
+		{
+			remoteURL: "https://token@gitlab.com/bar/baz",
+			want:      &partialCloneOptions{filter: "blob:limit=1m"},
+		},
+		{
+			remoteURL: "https://gitlab.com/barbaz/qux",
+			want:      nil,
+		},
+	} {
+		t.Run(tc.remoteURL, func(t *testing.T) {
+			remoteURL, err := vcs.ParseURL(tc.remoteURL)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, partialCloneOptionsFor(mappings, remoteURL))
+		})
+	}
+}
+
+func TestFetchCommand_PartialClone(t *testing.T) {
+	s := NewGitRepoSyncer(logtest.Scoped(t), wrexec.NewNoOpRecordingCommandFactory())
+	remoteURL, err := vcs.ParseURL("https://token@github.com/foo/monorepo")
+	require.NoError(t, err)
+
+	cmd, configRemoteOpts := s.fetchCommand(context.Background(), remoteURL, &partialCloneOptions{filter: "blob:none"})
+	assert.True(t, configRemoteOpts)
+	assert.Equal(t, []string{"git", "fetch", "--progress", "--prune", "--filter=blob:none", "origin"}, cmd.Args[:6])
+	assert.Contains(t, cmd.Env, "GIT_CONFIG_VALUE_0=https://token@github.com/foo/monorepo")
+
+	// Existing partial clones use the filter stored in their config.
+	cmd, _ = s.fetchCommand(context.Background(), remoteURL, &partialCloneOptions{})
+	assert.Equal(t, []string{"git", "fetch", "--progress", "--prune", "origin"}, cmd.Args[:5])
+
+	cmd, _ = s.fetchCommand(context.Background(), remoteURL, nil)
+	assert.Equal(t, []string{"git", "fetch", "--progress", "--prune", "https://token@github.com/foo/monorepo"}, cmd.Args[:5])
+	assert.Nil(t, cmd.Env)
+}
+
+func TestPrefetchSparsePaths(t *testing.T) {
+	remote := t.TempDir()
+	runGit(t, remote, "init", "--initial-branch=main")
+	runGit(t, remote, "config", "uploadpack.allowFilter", "true")
+	runGit(t, remote, "config", "uploadpack.allowAnySHA1InWant", "true")
+	require.NoError(t, os.MkdirAll(filepath.Join(remote, "docs"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(remote, "docs", "README"), []byte("docs"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(remote, "main.go"), []byte("package main"), 0o644))
+	runGit(t, remote, "add", ".")
+	runGit(t, remote, "-c", "user.name=a", "-c", "user.email=a@a.com", "commit", "-m", "init")
+
+	remoteURL, err := vcs.ParseURL("file://" + remote)
+	require.NoError(t, err)
+
+	dir := common.GitDir(t.TempDir())
+	runGit(t, string(dir), "init", "--bare", "--initial-branch=main")
+	s := NewGitRepoSyncer(logtest.Scoped(t), wrexec.NewNoOpRecordingCommandFactory())
+	cmd, _ := s.fetchCommand(context.Background(), remoteURL, &partialCloneOptions{filter: "blob:none"})
+	dir.Set(cmd)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.True(t, git.IsPartialClone(dir))
+
+	TestPartialCloneMappings = []*schema.PartialCloneMapping{{DomainPath: remote, SparsePaths: []string{"docs"}}}
+	t.Cleanup(func() { TestPartialCloneMappings = nil })
+
+	err = PrefetchSparsePaths(context.Background(), logtest.Scoped(t), wrexec.NewNoOpRecordingCommandFactory(), "repo", dir, remoteURL)
+	require.NoError(t, err)
+
+	// Without lazy fetching, only the blobs below the sparse paths can be read.
+	catFile := func(path string) error {
+		cmd := exec.Command("git", "cat-file", "-p", "HEAD:"+path)
+		dir.Set(cmd)
+		cmd.Env = append(os.Environ(), "GIT_NO_LAZY_FETCH=1")
+		return cmd.Run()
+	}
+	assert.NoError(t, catFile("docs/README"))
+	assert.Error(t, catFile("main.go"))
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
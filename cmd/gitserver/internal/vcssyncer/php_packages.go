@@ -0,0 +1,114 @@
+package vcssyncer
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/gitserver/internal/gitserverfs"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/packagist"
+	"github.com/sourcegraph/sourcegraph/internal/unpack"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func NewPHPPackagesSyncer(
+	connection *schema.PHPPackagesConnection,
+	svc *dependencies.Service,
+	client *packagist.Client,
+	reposDir string,
+) VCSSyncer {
+	return &vcsPackagesSyncer{
+		logger:      log.Scoped("PHPPackagesSyncer"),
+		typ:         "php_packages",
+		scheme:      dependencies.PHPPackagesScheme,
+		placeholder: reposource.NewPHPVersionedPackage("sourcegraph/placeholder", "0.0.0"),
+		svc:         svc,
+		configDeps:  connection.Dependencies,
+		reposDir:    reposDir,
+		source:      &phpDependencySource{client: client, reposDir: reposDir},
+	}
+}
+
+type phpDependencySource struct {
+	client   *packagist.Client
+	reposDir string
+}
+
+func (phpDependencySource) ParseVersionedPackageFromNameAndVersion(name reposource.PackageName, version string) (reposource.VersionedPackage, error) {
+	return reposource.ParsePHPVersionedPackage(string(name) + "@" + version)
+}
+
+func (phpDependencySource) ParseVersionedPackageFromConfiguration(dep string) (reposource.VersionedPackage, error) {
+	return reposource.ParsePHPVersionedPackage(dep)
+}
+
+func (phpDependencySource) ParsePackageFromName(name reposource.PackageName) (reposource.Package, error) {
+	return reposource.ParsePHPPackageFromName(name)
+}
+
+func (phpDependencySource) ParsePackageFromRepoName(repoName api.RepoName) (reposource.Package, error) {
+	return reposource.ParsePHPPackageFromRepoName(repoName)
+}
+
+func (s *phpDependencySource) Download(ctx context.Context, dir string, dep reposource.VersionedPackage) error {
+	pkgContents, err := s.client.GetPackageContents(ctx, dep)
+	if err != nil {
+		return errors.Wrapf(err, "error downloading PHP package %q", dep.VersionedPackageSyntax())
+	}
+	defer pkgContents.Close()
+
+	if err = unpackPHPPackage(pkgContents, s.reposDir, dir); err != nil {
+		return errors.Wrapf(err, "failed to unzip PHP package %q", dep.VersionedPackageSyntax())
+	}
+
+	return nil
+}
+
+// unpackPHPPackage unpacks the given dist zip archive of a PHP package into
+// workDir. Dist archives created by GitHub contain a single directory named
+// after the repository and commit, which is stripped.
+func unpackPHPPackage(pkg io.Reader, reposDir, workDir string) error {
+	opts := unpack.Opts{
+		SkipInvalid:    true,
+		SkipDuplicates: true,
+		Filter: func(path string, file fs.FileInfo) bool {
+			size := file.Size()
+
+			const sizeLimit = 15 * 1024 * 1024
+			if size >= sizeLimit {
+				return false
+			}
+
+			malicious := isPotentiallyMaliciousFilepathInArchive(path, workDir)
+			return !malicious
+		},
+	}
+
+	// Create a tmpdir that gitserver manages.
+	tmpdir, err := gitserverfs.TempDir(reposDir, "php-packages")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpdir)
+
+	// Write the whole package to a temporary file, zip files cannot be
+	// unpacked in a streaming fashion.
+	zip, zipLen, err := writeZipToTemp(tmpdir, pkg)
+	if err != nil {
+		return err
+	}
+	defer zip.Close()
+
+	if err := unpack.Zip(zip, zipLen, workDir, opts); err != nil {
+		return err
+	}
+
+	return stripSingleOutermostDirectory(workDir)
+}
@@ -0,0 +1,26 @@
+package vcssyncer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackPHPPackage(t *testing.T) {
+	pkg := createZip(t, []fileInfo{
+		{path: "Seldaek-monolog-e2392369686d420ca32df3803de28b5d6f76867d/composer.json", contents: []byte("{}")},
+		{path: "Seldaek-monolog-e2392369686d420ca32df3803de28b5d6f76867d/src/Monolog/Logger.php", contents: []byte("<?php")},
+		{path: "Seldaek-monolog-e2392369686d420ca32df3803de28b5d6f76867d/.git/config", contents: []byte("filter me")},
+	})
+
+	tmp := t.TempDir()
+	require.NoError(t, unpackPHPPackage(bytes.NewReader(pkg), t.TempDir(), tmp))
+
+	// The directory GitHub adds to dist archives is stripped.
+	assert.Equal(t, []string{
+		"composer.json",
+		"src/Monolog/Logger.php",
+	}, listFiles(t, tmp))
+}
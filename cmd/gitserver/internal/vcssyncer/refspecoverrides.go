@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/env"
-	"github.com/sourcegraph/sourcegraph/internal/vcs"
 )
 
 // HACK(keegancsmith) workaround to experiment with cloning less in a large
@@ -21,6 +20,6 @@ func useRefspecOverrides() bool {
 
 // HACK(keegancsmith) workaround to experiment with cloning less in a large
 // monorepo. https://github.com/sourcegraph/customer/issues/19
-func refspecOverridesFetchCmd(ctx context.Context, remoteURL *vcs.URL) *exec.Cmd {
-	return exec.CommandContext(ctx, "git", append([]string{"fetch", "--progress", "--prune", remoteURL.String()}, refspecOverrides...)...)
+func refspecOverridesFetchCmd(ctx context.Context, fetchArgs []string) *exec.Cmd {
+	return exec.CommandContext(ctx, "git", append(fetchArgs, refspecOverrides...)...)
 }
@@ -18,7 +18,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/crates"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gomodproxy"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/hex"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/npm"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/nuget"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/packagist"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/pypi"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/rubygems"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
@@ -179,6 +182,47 @@ func NewVCSSyncer(ctx context.Context, opts *NewVCSSyncerOpts) (VCSSyncer, error
 			return nil, err
 		}
 		return NewRubyPackagesSyncer(&c, opts.DepsSvc, cli, opts.ReposDir), nil
+	case extsvc.TypeNuGetPackages:
+		var c schema.NuGetPackagesConnection
+		urn, err := extractOptions(&c)
+		if err != nil {
+			return nil, err
+		}
+		cli, err := nuget.NewClient(urn, c.Repository, httpcli.ExternalClientFactory)
+		if err != nil {
+			return nil, err
+		}
+		return NewNuGetPackagesSyncer(&c, opts.DepsSvc, cli, opts.ReposDir), nil
+	case extsvc.TypePHPPackages:
+		var c schema.PHPPackagesConnection
+		urn, err := extractOptions(&c)
+		if err != nil {
+			return nil, err
+		}
+		cli, err := packagist.NewClient(urn, c.Repository, httpcli.ExternalClientFactory)
+		if err != nil {
+			return nil, err
+		}
+		return NewPHPPackagesSyncer(&c, opts.DepsSvc, cli, opts.ReposDir), nil
+	case extsvc.TypeHexPackages:
+		var c schema.HexPackagesConnection
+		urn, err := extractOptions(&c)
+		if err != nil {
+			return nil, err
+		}
+		cli, err := hex.NewClient(urn, c.Repository, httpcli.ExternalClientFactory)
+		if err != nil {
+			return nil, err
+		}
+		return NewHexPackagesSyncer(&c, opts.DepsSvc, cli, opts.ReposDir), nil
+	case extsvc.TypeOther:
+		var c schema.OtherExternalServiceConnection
+		if _, err := extractOptions(&c); err != nil {
+			return nil, err
+		}
+		if c.Vcs == "hg" {
+			return NewHgSyncer(opts.Logger, opts.RecordingCommandFactory), nil
+		}
 	}
 
 	return NewGitRepoSyncer(opts.Logger, opts.RecordingCommandFactory), nil
@@ -67,3 +67,48 @@ func TestGetVCSSyncer(t *testing.T) {
 
 	require.Equal(t, "perforce", s.Type())
 }
+
+func TestGetVCSSyncer_Other(t *testing.T) {
+	for config, want := range map[string]string{
+		`{"repos": ["repo"]}`:               "git",
+		`{"repos": ["repo"], "vcs": "git"}`: "git",
+		`{"repos": ["repo"], "vcs": "hg"}`:  "hg",
+	} {
+		t.Run(config, func(t *testing.T) {
+			extsvcStore := dbmocks.NewMockExternalServiceStore()
+			repoStore := dbmocks.NewMockRepoStore()
+
+			repoStore.GetByNameFunc.SetDefaultHook(func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+				return &types.Repo{
+					ExternalRepo: api.ExternalRepoSpec{
+						ServiceType: extsvc.TypeOther,
+					},
+					Sources: map[string]*types.SourceInfo{
+						"a": {
+							ID:       "abc",
+							CloneURL: "https://hg.example.com/repo",
+						},
+					},
+				}, nil
+			})
+
+			extsvcStore.GetByIDFunc.SetDefaultHook(func(ctx context.Context, i int64) (*types.ExternalService, error) {
+				return &types.ExternalService{
+					ID:          1,
+					Kind:        extsvc.KindOther,
+					DisplayName: "test",
+					Config:      extsvc.NewUnencryptedConfig(config),
+				}, nil
+			})
+
+			s, err := NewVCSSyncer(context.Background(), &NewVCSSyncerOpts{
+				ExternalServiceStore: extsvcStore,
+				RepoStore:            repoStore,
+				Repo:                 api.RepoName("hg.example.com/repo"),
+				Logger:               logtest.Scoped(t),
+			})
+			require.NoError(t, err)
+			require.Equal(t, want, s.Type())
+		})
+	}
+}
@@ -36,9 +36,14 @@ type Config struct {
 	SyncRepoStateUpdatePerSecond   int
 	BatchLogGlobalConcurrencyLimit int
 
+	SyncReplicasInterval  time.Duration
+	SyncReplicasBatchSize int
+
 	JanitorReposDesiredPercentFree        int
 	JanitorInterval                       time.Duration
 	JanitorDisableDeleteReposOnWrongShard bool
+
+	BlockExecOfNewTypedRPCCommands bool
 }
 
 func (c *Config) Load() {
@@ -75,6 +80,9 @@ func (c *Config) Load() {
 	c.SyncRepoStateUpdatePerSecond = c.GetInt("SRC_REPOS_SYNC_STATE_UPSERT_PER_SEC", "500", "The number of updated rows allowed per second across all gitserver instances")
 	c.BatchLogGlobalConcurrencyLimit = c.GetInt("SRC_BATCH_LOG_GLOBAL_CONCURRENCY_LIMIT", "256", "The maximum number of in-flight Git commands from all /batch-log requests combined")
 
+	c.SyncReplicasInterval = c.GetInterval("SRC_REPOS_SYNC_REPLICAS_INTERVAL", "1m", "Interval between fetches of outdated repository replicas from their primary gitserver")
+	c.SyncReplicasBatchSize = c.GetInt("SRC_REPOS_SYNC_REPLICAS_BATCH_SIZE", "100", "Number of outdated repository replicas to fetch per interval")
+
 	// Align these variables with the 'disk_space_remaining' alerts in monitoring
 	c.JanitorReposDesiredPercentFree = c.GetInt("SRC_REPOS_DESIRED_PERCENT_FREE", "10", "Target percentage of free space on disk.")
 	if c.JanitorReposDesiredPercentFree < 0 {
@@ -86,4 +94,8 @@ func (c *Config) Load() {
 
 	c.JanitorInterval = c.GetInterval("SRC_REPOS_JANITOR_INTERVAL", "1m", "Interval between cleanup runs")
 	c.JanitorDisableDeleteReposOnWrongShard = c.GetBool("SRC_REPOS_JANITOR_DISABLE_DELETE_REPOS_ON_WRONG_SHARD", "false", "Disable deleting repos on wrong shard")
+
+	// Off by default so that clients from the previous version, which still
+	// send these commands over Exec, keep working during a rolling upgrade.
+	c.BlockExecOfNewTypedRPCCommands = c.GetBool("SRC_GITSERVER_BLOCK_EXEC_OF_TYPED_RPC_COMMANDS", "false", "Reject the git log, ls-tree and diff invocations served by the Commits, ReadDir and DiffSymbols RPCs when they are sent over Exec.")
 }
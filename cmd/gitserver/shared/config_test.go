@@ -34,6 +34,12 @@ func TestConfigDefaults(t *testing.T) {
 	if have, want := config.BatchLogGlobalConcurrencyLimit, 256; have != want {
 		t.Errorf("invalid value for BatchLogGlobalConcurrencyLimit: have=%d want=%d", have, want)
 	}
+	if have, want := config.SyncReplicasInterval, time.Minute; have != want {
+		t.Errorf("invalid value for SyncReplicasInterval: have=%s want=%s", have, want)
+	}
+	if have, want := config.SyncReplicasBatchSize, 100; have != want {
+		t.Errorf("invalid value for SyncReplicasBatchSize: have=%d want=%d", have, want)
+	}
 	if have, want := config.JanitorReposDesiredPercentFree, 10; have != want {
 		t.Errorf("invalid value for JanitorReposDesiredPercentFree: have=%d want=%d", have, want)
 	}
@@ -43,6 +49,9 @@ func TestConfigDefaults(t *testing.T) {
 	if have, want := config.JanitorDisableDeleteReposOnWrongShard, false; have != want {
 		t.Errorf("invalid value for JanitorDisableDeleteReposOnWrongShard: have=%t want=%t", have, want)
 	}
+	if have, want := config.BlockExecOfNewTypedRPCCommands, false; have != want {
+		t.Errorf("invalid value for BlockExecOfNewTypedRPCCommands: have=%t want=%t", have, want)
+	}
 }
 
 func TestConfig_PercentFree(t *testing.T) {
@@ -107,13 +107,14 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 				Logger:                  logger,
 			})
 		},
-		Hostname:                config.ExternalAddress,
-		DB:                      db,
-		CloneQueue:              cloneQueue,
-		GlobalBatchLogSemaphore: semaphore.NewWeighted(int64(config.BatchLogGlobalConcurrencyLimit)),
-		Perforce:                perforce.NewService(ctx, observationCtx, logger, db, list.New()),
-		RecordingCommandFactory: recordingCommandFactory,
-		Locker:                  locker,
+		Hostname:                       config.ExternalAddress,
+		DB:                             db,
+		CloneQueue:                     cloneQueue,
+		GlobalBatchLogSemaphore:        semaphore.NewWeighted(int64(config.BatchLogGlobalConcurrencyLimit)),
+		Perforce:                       perforce.NewService(ctx, observationCtx, logger, db, list.New()),
+		RecordingCommandFactory:        recordingCommandFactory,
+		Locker:                         locker,
+		BlockExecOfNewTypedRPCCommands: config.BlockExecOfNewTypedRPCCommands,
 		RPSLimiter: ratelimit.NewInstrumentedLimiter(
 			ratelimit.GitRPSLimiterBucketName,
 			ratelimit.NewGlobalRateLimiter(logger, ratelimit.GitRPSLimiterBucketName),
@@ -163,6 +164,7 @@ func Main(ctx context.Context, observationCtx *observation.Context, ready servic
 			config.SyncRepoStateBatchSize,
 			config.SyncRepoStateUpdatePerSecond,
 		),
+		gitserver.NewReplicaSyncer(ctx, config.SyncReplicasInterval, config.SyncReplicasBatchSize),
 	}
 
 	if runtime.GOOS == "windows" {
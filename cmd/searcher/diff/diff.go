@@ -1,39 +1,28 @@
 package diff
 
 import (
-	"bytes"
 	"sort"
 
-	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 )
 
-// ParseGitDiffNameStatus returns the paths changedA and changedB for commits
-// A and B respectively. It expects to be parsing the output of the command
-// git diff -z --name-status --no-renames A B.
-func ParseGitDiffNameStatus(out []byte) (changedA, changedB []string, err error) {
-	if len(out) == 0 {
-		return nil, nil, nil
-	}
-
-	slices := bytes.Split(bytes.TrimRight(out, "\x00"), []byte{0})
-	if len(slices)%2 != 0 {
-		return nil, nil, errors.New("uneven pairs")
-	}
-
-	for i := 0; i < len(slices); i += 2 {
-		path := string(slices[i+1])
-		switch slices[i][0] {
-		case 'D': // no longer appears in B
-			changedA = append(changedA, path)
-		case 'M':
-			changedA = append(changedA, path)
-			changedB = append(changedB, path)
-		case 'A': // doesn't exist in A
-			changedB = append(changedB, path)
+// ChangedPaths returns the paths changedA and changedB for commits A and B
+// respectively, given the files changed between A and B as returned by
+// gitserver's DiffSymbols.
+func ChangedPaths(pathStatuses []gitdomain.PathStatus) (changedA, changedB []string) {
+	for _, p := range pathStatuses {
+		switch p.Status {
+		case gitdomain.DeletedAMD: // no longer appears in B
+			changedA = append(changedA, p.Path)
+		case gitdomain.ModifiedAMD:
+			changedA = append(changedA, p.Path)
+			changedB = append(changedB, p.Path)
+		case gitdomain.AddedAMD: // doesn't exist in A
+			changedB = append(changedB, p.Path)
 		}
 	}
 	sort.Strings(changedA)
 	sort.Strings(changedB)
 
-	return changedA, changedB, nil
+	return changedA, changedB
 }
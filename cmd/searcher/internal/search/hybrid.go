@@ -105,7 +105,7 @@ func (s *Service) hybrid(ctx context.Context, rootLogger log.Logger, p *protocol
 		// TODO if our store was more flexible we could cache just based on
 		// indexed and p.Commit and avoid the need of running diff for each
 		// search.
-		out, err := s.GitDiffSymbols(ctx, p.Repo, indexed, p.Commit)
+		changes, err := s.GitDiffSymbols(ctx, p.Repo, indexed, p.Commit)
 		if errcode.IsNotFound(err) {
 			recordHybridFinalState("git-diff-not-found")
 			logger.Debug("not doing hybrid search due to likely missing indexed commit on gitserver", log.Error(err))
@@ -115,14 +115,7 @@ func (s *Service) hybrid(ctx context.Context, rootLogger log.Logger, p *protocol
 			return nil, false, errors.Wrapf(err, "failed to find changed files in %s between %s and %s", p.Repo, indexed, p.Commit)
 		}
 
-		indexedIgnore, unindexedSearch, err := diff.ParseGitDiffNameStatus(out)
-		if err != nil {
-			logger.Debug("parseGitDiffNameStatus failed",
-				log.Binary("out", out),
-				log.Error(err))
-			recordHybridFinalState("git-diff-parse-error")
-			return nil, false, errors.Wrapf(err, "failed to parse git diff output of changed files in %s between %s and %s", p.Repo, indexed, p.Commit)
-		}
+		indexedIgnore, unindexedSearch := diff.ChangedPaths(changes)
 
 		totalLenIndexedIgnore := totalStringsLen(indexedIgnore)
 		totalLenUnindexedSearch := totalStringsLen(unindexedSearch)
@@ -26,6 +26,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/internal/search"
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/types"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -80,12 +82,11 @@ Hello world example in go`, typeFile},
 		delete(files, unchanged)
 	}
 
-	gitDiffOutput := strings.Join([]string{
-		"M", "changed.go",
-		"A", "added.md",
-		"D", "removed.md",
-		"", // trailing null
-	}, "\x00")
+	gitDiffOutput := []gitdomain.PathStatus{
+		{Path: "changed.go", Status: gitdomain.ModifiedAMD},
+		{Path: "added.md", Status: gitdomain.AddedAMD},
+		{Path: "removed.md", Status: gitdomain.DeletedAMD},
+	}
 
 	s := newStore(t, files)
 
@@ -114,14 +115,14 @@ Hello world example in go`, typeFile},
 
 	// we expect one command against git, lets just fake it.
 	service := &search.Service{
-		GitDiffSymbols: func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]byte, error) {
+		GitDiffSymbols: func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]gitdomain.PathStatus, error) {
 			if commitA != "indexedfdeadbeefdeadbeefdeadbeefdeadbeef" {
 				return nil, errors.Errorf("expected first commit to be indexed, got: %s", commitA)
 			}
 			if commitB != "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef" {
 				return nil, errors.Errorf("expected first commit to be unindexed, got: %s", commitB)
 			}
-			return []byte(gitDiffOutput), nil
+			return gitDiffOutput, nil
 		},
 		MaxTotalPathsLength: 100_000,
 
@@ -273,6 +274,40 @@ unchanged.md
 			}
 		})
 	}
+
+	// Indexed and unindexed matches are merged by document rank.
+	t.Run("document-ranks", func(t *testing.T) {
+		service.DocumentRanks = func(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error) {
+			return types.RepoPathRanks{Paths: map[string]float64{
+				"unchanged.md": 2,
+				"changed.go":   1,
+			}}, nil
+		}
+		t.Cleanup(func() { service.DocumentRanks = nil })
+
+		req := protocol.Request{
+			Repo:         "foo",
+			RepoID:       123,
+			URL:          "u",
+			Commit:       "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			PatternInfo:  protocol.PatternInfo{Pattern: "world"},
+			FetchTimeout: fetchTimeoutForCI(t),
+		}
+
+		m, err := doSearch(ts.URL, &req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, fm := range m {
+			got = append(got, fm.Path)
+		}
+		want := []string{"unchanged.md", "changed.go", "added.md"}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Fatalf("mismatch (-want, +got):\n%s", d)
+		}
+	})
 }
 
 func newZoekt(t *testing.T, repo *zoekt.Repository, files map[string]struct {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"regexp/syntax"
+	"sort"
 	"strings"
 
 	"github.com/grafana/regexp"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/search/casetransform"
+	searchquery "github.com/sourcegraph/sourcegraph/internal/search/query"
 )
 
 type matcher interface {
@@ -30,24 +32,46 @@ type matcher interface {
 
 // compilePattern returns a matcher for matching the pattern info
 func compilePattern(p *protocol.PatternInfo) (matcher, error) {
+	if p.Pattern == "" {
+		return &allMatcher{}, nil
+	}
+
+	rm, err := compileRegexMatcher(p.Pattern, p.IsRegExp, p)
+	if err != nil {
+		return nil, err
+	}
+	if p.NearPattern == "" {
+		return rm, nil
+	}
+
+	near, err := compileRegexMatcher(p.NearPattern, true, p)
+	if err != nil {
+		return nil, err
+	}
+	return &nearMatcher{left: rm, right: near, distance: p.NearDistance}, nil
+}
+
+// compileRegexMatcher returns a matcher for pattern, using the flags of p.
+func compileRegexMatcher(pattern string, isRegExp bool, p *protocol.PatternInfo) (*regexMatcher, error) {
 	var (
 		re               *regexp.Regexp
 		literalSubstring []byte
 	)
 
-	if p.Pattern == "" {
-		return &allMatcher{}, nil
-	}
-
-	expr := p.Pattern
-	if !p.IsRegExp {
+	expr := pattern
+	if !isRegExp {
 		expr = regexp.QuoteMeta(expr)
 	}
 
-	if p.IsRegExp {
+	if isRegExp {
 		// We don't do the search line by line, therefore we want the
 		// regex engine to consider newlines for anchors (^$).
 		expr = "(?m:" + expr + ")"
+
+		if p.IsMultiline {
+			// Let "." match newlines, so a match can span multiple lines.
+			expr = "(?s:" + expr + ")"
+		}
 	}
 
 	// Transforms on the parsed regex
@@ -237,3 +261,116 @@ func (rm *regexMatcher) negateIfNeeded(q zoektquery.Q) zoektquery.Q {
 	}
 	return q
 }
+
+// nearMatcher matches where left and right match at most distance lines
+// apart, in either order. It implements NEAR/n expressions. Each match spans
+// from the first term to the last, so that terms close to each other are part
+// of the same match.
+type nearMatcher struct {
+	left, right *regexMatcher
+	distance    int
+}
+
+func (nm *nearMatcher) String() string {
+	return fmt.Sprintf("near/%d: %s %s", nm.distance, nm.left, nm.right)
+}
+
+func (nm *nearMatcher) MatchesString(s string) bool {
+	return nm.left.MatchesString(s) && nm.right.MatchesString(s)
+}
+
+func (nm *nearMatcher) MatchesFile(fileBuf []byte, limit int) (bool, [][]int) {
+	matches := nm.matchesFile(fileBuf, limit)
+	return len(matches) > 0, matches
+}
+
+func (nm *nearMatcher) matchesFile(fileBuf []byte, limit int) [][]int {
+	// Unlike regexMatcher we need all matches of both terms, the limit
+	// applies to the matches of the terms near each other.
+	left := findAllIndex(nm.left, fileBuf)
+	if len(left) == 0 {
+		return nil
+	}
+	right := findAllIndex(nm.right, fileBuf)
+	if len(right) == 0 {
+		return nil
+	}
+
+	var newlines []int
+	for i, c := range fileBuf {
+		if c == '\n' {
+			newlines = append(newlines, i)
+		}
+	}
+	// line returns the 0-based line of offset.
+	line := func(offset int) int {
+		return sort.SearchInts(newlines, offset)
+	}
+	isNear := func(a, b []int) bool {
+		if a[0] > b[0] {
+			a, b = b, a
+		}
+		end := a[1] - 1
+		if end < a[0] {
+			end = a[0]
+		}
+		return line(b[0])-line(end) <= nm.distance
+	}
+
+	// Pair each match with the closest matches of the other term, in both
+	// directions, so that every match that is near a match of the other term
+	// is part of a span.
+	var spans [][]int
+	pair := func(xs, ys [][]int) {
+		for _, x := range xs {
+			i := sort.Search(len(ys), func(i int) bool { return ys[i][0] >= x[0] })
+			for _, j := range []int{i - 1, i} {
+				if j < 0 || j >= len(ys) || !isNear(x, ys[j]) {
+					continue
+				}
+				spans = append(spans, []int{min(x[0], ys[j][0]), max(x[1], ys[j][1])})
+			}
+		}
+	}
+	pair(left, right)
+	pair(right, left)
+	if len(spans) == 0 {
+		return nil
+	}
+
+	// Merge overlapping spans, so that every term is part of one match only.
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	matches := spans[:1]
+	for _, s := range spans[1:] {
+		last := matches[len(matches)-1]
+		if s[0] < last[1] {
+			last[1] = max(last[1], s[1])
+			continue
+		}
+		// find limit+1 matches so we know whether we hit the limit
+		if len(matches) > limit {
+			break
+		}
+		matches = append(matches, s)
+	}
+	return matches
+}
+
+// findAllIndex returns all matches of rm in fileBuf.
+func findAllIndex(rm *regexMatcher, fileBuf []byte) [][]int {
+	if !bytes.Contains(fileBuf, rm.literalSubstring) {
+		return nil
+	}
+	return rm.re.FindAllIndex(fileBuf, -1)
+}
+
+func (nm *nearMatcher) ToZoektQuery(matchContent bool, matchPath bool) (zoektquery.Q, error) {
+	// Zoekt can't evaluate NEAR/n natively, so we search for a regular
+	// expression that matches the same spans.
+	re, err := regexp.Compile(searchquery.NearRegexp(nm.left.re.String(), nm.right.re.String(), nm.distance))
+	if err != nil {
+		return nil, err
+	}
+	rm := &regexMatcher{re: re, ignoreCase: nm.left.ignoreCase}
+	return rm.ToZoektQuery(matchContent, matchPath)
+}
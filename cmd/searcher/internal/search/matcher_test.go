@@ -4,7 +4,10 @@ import (
 	"regexp/syntax" //nolint:depguard // using the grafana fork of regexp clashes with zoekt, which uses the std regexp/syntax.
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 )
 
 func TestLongestLiteral(t *testing.T) {
@@ -46,3 +49,69 @@ func TestLongestLiteral(t *testing.T) {
 		}
 	}
 }
+
+func TestNearMatcher(t *testing.T) {
+	file := []byte("a foo\nb\nc bar\nd\ne\nf\ng foo bar\n")
+
+	cases := []struct {
+		name    string
+		pattern protocol.PatternInfo
+		limit   int
+		want    []string
+	}{{
+		name:    "within distance",
+		pattern: protocol.PatternInfo{Pattern: "foo", NearPattern: "bar", NearDistance: 2},
+		limit:   10,
+		want:    []string{"foo\nb\nc bar", "foo bar"},
+	}, {
+		name:    "either order",
+		pattern: protocol.PatternInfo{Pattern: "bar", NearPattern: "foo", NearDistance: 2},
+		limit:   10,
+		want:    []string{"foo\nb\nc bar", "foo bar"},
+	}, {
+		name:    "same line only",
+		pattern: protocol.PatternInfo{Pattern: "foo", NearPattern: "bar", NearDistance: 0},
+		limit:   10,
+		want:    []string{"foo bar"},
+	}, {
+		name:    "overlapping pairs are merged",
+		pattern: protocol.PatternInfo{Pattern: "foo", NearPattern: "bar", NearDistance: 4},
+		limit:   10,
+		want:    []string{"foo\nb\nc bar\nd\ne\nf\ng foo bar"},
+	}, {
+		name:    "regexp right-hand side",
+		pattern: protocol.PatternInfo{Pattern: "FOO", NearPattern: "^c", NearDistance: 2},
+		limit:   10,
+		want:    []string{"foo\nb\nc"},
+	}, {
+		name:    "limit",
+		pattern: protocol.PatternInfo{Pattern: "foo", NearPattern: "bar", NearDistance: 2},
+		limit:   0,
+		want:    []string{"foo\nb\nc bar"},
+	}, {
+		name:    "no match",
+		pattern: protocol.PatternInfo{Pattern: "foo", NearPattern: "baz", NearDistance: 2},
+		limit:   10,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.pattern.IsRegExp = true
+			m, err := compilePattern(&tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			match, locs := m.MatchesFile(file, tc.limit)
+			var got []string
+			for _, loc := range locs {
+				got = append(got, string(file[loc[0]:loc[1]]))
+			}
+			if match != (len(tc.want) > 0) {
+				t.Errorf("got match %t, want %t", match, len(tc.want) > 0)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected matches (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+)
+
+// rankWindowSize is the number of file matches rankedSender buffers before
+// it sorts and sends them.
+const rankWindowSize = 100
+
+// withDocumentRanks wraps sender so that matches are ordered by the document
+// ranks of p.Repo. The returned flush function must be called once searching
+// is done; it sends the remaining buffered matches to sender.
+//
+// Documents which define symbols according to precise SCIP data rank above
+// documents which only reference them, and among those documents defining
+// symbols with many precise references rank highest. Documents without a rank
+// come after ranked ones, and ties are broken by path so results are stable
+// across requests.
+//
+// Matches are sorted in windows of rankWindowSize files, which are passed to
+// sender as soon as they are full. This bounds the memory used for ranking and
+// keeps results streaming, at the cost of only ordering matches within a
+// window. sender applies the limit of the request to the sorted windows, so
+// within a window the limit keeps the highest ranked matches rather than the
+// first ones found, and the search stops once a window hits the limit.
+//
+// If the repository has no ranks, sender is returned as is and matches are
+// streamed as they are found.
+func (s *Service) withDocumentRanks(ctx context.Context, p *protocol.Request, sender matchSender) (matchSender, func()) {
+	noop := func() {}
+	if s.DocumentRanks == nil {
+		return sender, noop
+	}
+
+	ranks, err := s.DocumentRanks(ctx, p.Repo)
+	if err != nil {
+		// Ranking is best effort, we still want to return results.
+		s.Log.Warn("failed to fetch document ranks", log.String("repo", string(p.Repo)), log.Error(err))
+		return sender, noop
+	}
+	if len(ranks.Paths) == 0 && len(ranks.DefinitionPaths) == 0 {
+		return sender, noop
+	}
+
+	definitions := make(map[string]struct{}, len(ranks.DefinitionPaths))
+	for _, path := range ranks.DefinitionPaths {
+		definitions[path] = struct{}{}
+	}
+	r := &rankedSender{ranks: ranks.Paths, definitions: definitions, sender: sender}
+	return r, r.flush
+}
+
+// rankedSender buffers up to rankWindowSize matches at a time to sort them by
+// document rank.
+type rankedSender struct {
+	ranks       map[string]float64
+	definitions map[string]struct{}
+	sender      matchSender
+
+	// sendMu is held while a window is sorted and sent, so that a window
+	// reaches sender in full before the next one.
+	sendMu  sync.Mutex
+	mu      sync.Mutex
+	matches []protocol.FileMatch
+	count   int
+}
+
+func (r *rankedSender) Send(match protocol.FileMatch) {
+	r.mu.Lock()
+	r.matches = append(r.matches, match)
+	r.count += match.MatchCount()
+	full := len(r.matches) >= rankWindowSize
+	r.mu.Unlock()
+
+	if full {
+		r.flush()
+	}
+}
+
+// SentCount returns the number of matches sent, including buffered ones.
+func (r *rankedSender) SentCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Remaining returns the remaining matches of the underlying sender, which
+// limits the matches of a single file. Buffered matches don't count against
+// it, any of them may be dropped when their window is sorted.
+func (r *rankedSender) Remaining() int {
+	return r.sender.Remaining()
+}
+
+func (r *rankedSender) LimitHit() bool {
+	return r.sender.LimitHit()
+}
+
+func (r *rankedSender) SetLimitHit() {
+	r.sender.SetLimitHit()
+}
+
+// flush sorts the buffered matches and sends them to the underlying sender.
+func (r *rankedSender) flush() {
+	r.sendMu.Lock()
+	defer r.sendMu.Unlock()
+
+	r.mu.Lock()
+	matches := r.matches
+	r.matches = make([]protocol.FileMatch, 0, rankWindowSize)
+	r.mu.Unlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		_, di := r.definitions[matches[i].Path]
+		_, dj := r.definitions[matches[j].Path]
+		if di != dj {
+			return di
+		}
+		ri, rj := r.ranks[matches[i].Path], r.ranks[matches[j].Path]
+		if ri != rj {
+			return ri > rj
+		}
+		return matches[i].Path < matches[j].Path
+	})
+
+	for _, m := range matches {
+		r.sender.Send(m)
+	}
+}
@@ -0,0 +1,141 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestWithDocumentRanks(t *testing.T) {
+	inputs := []protocol.FileMatch{
+		{Path: "c.go"},
+		{Path: "unranked_b.go"},
+		{Path: "a.go"},
+		{Path: "unranked_a.go"},
+		{Path: "b.go"},
+	}
+
+	runWithLimit := func(t *testing.T, s *Service, limit int) ([]string, bool) {
+		t.Helper()
+
+		var got []string
+		ctx, cancel, stream := newLimitedStream(context.Background(), limit, func(m protocol.FileMatch) {
+			got = append(got, m.Path)
+		})
+		defer cancel()
+		sender, flush := s.withDocumentRanks(ctx, &protocol.Request{Repo: "foo"}, stream)
+		for _, m := range inputs {
+			sender.Send(m)
+		}
+		flush()
+		return got, stream.LimitHit()
+	}
+
+	run := func(t *testing.T, s *Service) []string {
+		t.Helper()
+		got, _ := runWithLimit(t, s, len(inputs))
+		return got
+	}
+
+	ranked := func(t *testing.T) *Service {
+		return &Service{
+			Log: logtest.Scoped(t),
+			DocumentRanks: func(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error) {
+				require.Equal(t, api.RepoName("foo"), repo)
+				return types.RepoPathRanks{Paths: map[string]float64{
+					"a.go": 1,
+					"b.go": 3,
+					"c.go": 1,
+				}}, nil
+			},
+		}
+	}
+
+	t.Run("ranked", func(t *testing.T) {
+		require.Equal(t, []string{"b.go", "a.go", "c.go", "unranked_a.go", "unranked_b.go"}, run(t, ranked(t)))
+	})
+
+	t.Run("definitions above references", func(t *testing.T) {
+		s := &Service{
+			Log: logtest.Scoped(t),
+			DocumentRanks: func(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error) {
+				return types.RepoPathRanks{
+					Paths: map[string]float64{
+						"a.go": 1,
+						"b.go": 3,
+						"c.go": 1,
+					},
+					DefinitionPaths: []string{"a.go", "unranked_b.go"},
+				}, nil
+			},
+		}
+		require.Equal(t, []string{"a.go", "unranked_b.go", "b.go", "c.go", "unranked_a.go"}, run(t, s))
+	})
+
+	t.Run("limit applies after ranking", func(t *testing.T) {
+		// b.go is sent last, but ranks highest so it is kept.
+		got, limitHit := runWithLimit(t, ranked(t), 2)
+		require.Equal(t, []string{"b.go", "a.go"}, got)
+		require.True(t, limitHit)
+	})
+
+	t.Run("windows are sent once full", func(t *testing.T) {
+		var got []string
+		ctx, cancel, stream := newLimitedStream(context.Background(), 2*rankWindowSize, func(m protocol.FileMatch) {
+			got = append(got, m.Path)
+		})
+		defer cancel()
+		sender, flush := ranked(t).withDocumentRanks(ctx, &protocol.Request{Repo: "foo"}, stream)
+
+		for i := 0; i < rankWindowSize-1; i++ {
+			sender.Send(protocol.FileMatch{Path: fmt.Sprintf("unranked_%03d.go", i)})
+		}
+		require.Empty(t, got)
+
+		// Filling the window sends it, ranked, without waiting for flush.
+		sender.Send(protocol.FileMatch{Path: "b.go"})
+		require.Len(t, got, rankWindowSize)
+		require.Equal(t, "b.go", got[0])
+
+		// Later matches are ranked within their own window.
+		sender.Send(protocol.FileMatch{Path: "unranked_z.go"})
+		sender.Send(protocol.FileMatch{Path: "a.go"})
+		require.Len(t, got, rankWindowSize)
+		flush()
+		require.Equal(t, []string{"a.go", "unranked_z.go"}, got[rankWindowSize:])
+	})
+
+	unordered := []string{"c.go", "unranked_b.go", "a.go", "unranked_a.go", "b.go"}
+
+	t.Run("no ranker", func(t *testing.T) {
+		require.Equal(t, unordered, run(t, &Service{Log: logtest.Scoped(t)}))
+	})
+
+	t.Run("no ranks", func(t *testing.T) {
+		s := &Service{
+			Log: logtest.Scoped(t),
+			DocumentRanks: func(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error) {
+				return types.RepoPathRanks{}, nil
+			},
+		}
+		require.Equal(t, unordered, run(t, s))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		s := &Service{
+			Log: logtest.Scoped(t),
+			DocumentRanks: func(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error) {
+				return types.RepoPathRanks{}, errors.New("boom")
+			},
+		}
+		require.Equal(t, unordered, run(t, s))
+	})
+}
@@ -29,7 +29,9 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/types"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
 	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
@@ -50,16 +52,18 @@ type Service struct {
 
 	Indexed zoekt.Streamer
 
-	// GitDiffSymbols returns the stdout of running "git diff -z --name-status
-	// --no-renames commitA commitB" against repo.
-	//
-	// TODO Git client should be exposing a better API here.
-	GitDiffSymbols func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]byte, error)
+	// GitDiffSymbols returns the files that were added, modified or deleted
+	// between commitA and commitB in repo.
+	GitDiffSymbols func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]gitdomain.PathStatus, error)
 
 	// MaxTotalPathsLength is the maximum sum of lengths of all paths in a
 	// single call to git archive. This mainly needs to be less than ARG_MAX
 	// for the exec.Command on gitserver.
 	MaxTotalPathsLength int
+
+	// DocumentRanks returns the document ranks of repo used to order
+	// matches. If nil, matches are streamed in the order they are found.
+	DocumentRanks func(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error)
 }
 
 // ServeHTTP handles HTTP based search requests
@@ -120,7 +124,9 @@ func (s *Service) streamSearch(ctx context.Context, w http.ResponseWriter, p pro
 	ctx, cancel, stream := newLimitedStream(ctx, p.Limit, onMatches)
 	defer cancel()
 
-	err = s.search(ctx, &p, stream)
+	sender, flushRanked := s.withDocumentRanks(ctx, &p, stream)
+	err = s.search(ctx, &p, sender)
+	flushRanked()
 	doneEvent := searcher.EventDone{
 		LimitHit: stream.LimitHit(),
 	}
@@ -36,7 +36,9 @@ func (s *Server) Search(req *proto.SearchRequest, stream proto.SearcherService_S
 	ctx, cancel, matchStream := newLimitedStream(stream.Context(), int(req.PatternInfo.Limit), onMatches)
 	defer cancel()
 
-	err := s.Service.search(ctx, &unmarshaledReq, matchStream)
+	sender, flushRanked := s.Service.withDocumentRanks(ctx, &unmarshaledReq, matchStream)
+	err := s.Service.search(ctx, &unmarshaledReq, sender)
+	flushRanked()
 	if err != nil {
 		return convertToGRPCError(ctx, err)
 	}
@@ -481,6 +481,94 @@ func TestRegexSearch(t *testing.T) {
 	}
 }
 
+// multilineFile is searched by the multiline tests. The same cases are run
+// against zoekt in Test_zoektSearchMultiline, so that both backends agree on
+// the ranges of matches which span multiple lines.
+const multilineFile = "package main\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n\n// end\n"
+
+var multilineCases = []struct {
+	name      string
+	pattern   string
+	multiline bool
+	want      []protocol.Range
+}{{
+	name:    "dot does not match newline by default",
+	pattern: `main\(\) \{.*\}`,
+}, {
+	name:      "dot matches newline in multiline mode",
+	pattern:   `main\(\) \{.*\}`,
+	multiline: true,
+	want: []protocol.Range{{
+		Start: protocol.Location{Offset: 19, Line: 2, Column: 5},
+		End:   protocol.Location{Offset: 51, Line: 4, Column: 1},
+	}},
+}, {
+	name:    "explicit newlines",
+	pattern: `\{\n.*\n\}\n\n//`,
+	want: []protocol.Range{{
+		Start: protocol.Location{Offset: 26, Line: 2, Column: 12},
+		End:   protocol.Location{Offset: 55, Line: 6, Column: 2},
+	}},
+}, {
+	name:    "match ends at the start of a line",
+	pattern: `hello.*\n`,
+	want: []protocol.Range{{
+		Start: protocol.Location{Offset: 42, Line: 3, Column: 14},
+		End:   protocol.Location{Offset: 50, Line: 4, Column: 0},
+	}},
+}, {
+	name:      "anchors match at line boundaries in multiline mode",
+	pattern:   `^func.*end$`,
+	multiline: true,
+	want: []protocol.Range{{
+		Start: protocol.Location{Offset: 14, Line: 2, Column: 0},
+		End:   protocol.Location{Offset: 59, Line: 6, Column: 6},
+	}},
+}}
+
+func TestRegexSearchMultiline(t *testing.T) {
+	zipData, err := createZip(map[string]string{"main.go": multilineFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, err := mockZipFile(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range multilineCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &protocol.PatternInfo{
+				Pattern:               tc.pattern,
+				IsRegExp:              true,
+				IsMultiline:           tc.multiline,
+				PatternMatchesContent: true,
+			}
+			m, err := compilePattern(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pm, err := compilePathPatterns(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fileMatches, _, err := regexSearchBatch(context.Background(), m, pm, zf, 10, true, false, false, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []protocol.Range
+			for _, fm := range fileMatches {
+				for _, cm := range fm.ChunkMatches {
+					got = append(got, cm.Ranges...)
+				}
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func Test_locsToRanges(t *testing.T) {
 	cases := []struct {
 		buf    string
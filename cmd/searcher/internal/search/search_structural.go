@@ -515,6 +515,18 @@ func structuralSearch(
 		NumWorkers:    numWorkers,
 	}
 
+	if args.UseNative() {
+		tr.AddEvent("using native structural matcher")
+		stats, err := runNativeStructuralSearch(ctx, args, contextLines, sender)
+		if len(stats.SkippedFiles) > 0 {
+			// The results are incomplete, which is reported like a limit
+			// that was hit.
+			tr.AddEvent("skipped files", attribute.Int("count", len(stats.SkippedFiles)))
+			sender.SetLimitHit()
+		}
+		return err
+	}
+
 	switch combyInput := inputType.(type) {
 	case comby.Tar:
 		return runCombyAgainstTar(ctx, logger, args, combyInput, contextLines, sender)
@@ -680,6 +692,45 @@ func runCombyAgainstZip(
 	return nil
 }
 
+// runNativeStructuralSearch runs the search with the in-process matcher of
+// package comby, for hosts where the comby executable is not installed. It
+// sends the same file matches as runCombyAgainstTar and runCombyAgainstZip.
+// Files which are too expensive to match are listed in the returned stats.
+func runNativeStructuralSearch(
+	ctx context.Context,
+	args comby.Args,
+	contextLines int32,
+	sender matchSender,
+) (comby.NativeStats, error) {
+	var zipReader *zip.ReadCloser
+	switch input := args.Input.(type) {
+	case comby.Tar:
+	case comby.ZipPath:
+		var err error
+		zipReader, err = zip.OpenReader(string(input))
+		if err != nil {
+			return comby.NativeStats{}, err
+		}
+		defer zipReader.Close()
+	default:
+		return comby.NativeStats{}, errors.New("comby input must be either -tar or -zip for structural search")
+	}
+
+	return comby.RunNative(ctx, args, func(r comby.Result) error {
+		switch m := r.(type) {
+		case *comby.FileMatchWithChunks:
+			sender.Send(combyChunkMatchesToFileMatch(m))
+		case *comby.FileMatch:
+			fm, err := toFileMatch(&zipReader.Reader, m, contextLines)
+			if err != nil {
+				return errors.Wrap(err, "toFileMatch")
+			}
+			sender.Send(fm)
+		}
+		return nil
+	})
+}
+
 // killAndWait is a helper to kill a started cmd and release its resources.
 // This is used when returning from a function after calling Start but before
 // calling Wait. This can be called in a goroutine.
@@ -725,17 +725,56 @@ func bar() {
 	})
 }
 
+func TestNativeStructuralSearch(t *testing.T) {
+	input := map[string]string{
+		"main.go": "// foo(comment)\nfunc foo(success) {} func bar(fail) {}",
+	}
+
+	zipData, err := createZip(input)
+	require.NoError(t, err)
+	zf := tempZipFileOnDisk(t, zipData)
+
+	ctx, cancel, sender := newLimitedStreamCollector(context.Background(), 1000000000)
+	defer cancel()
+	stats, err := runNativeStructuralSearch(ctx, comby.Args{
+		Input:         comby.ZipPath(zf),
+		Matcher:       ".go",
+		MatchTemplate: "func :[[fn]](:[args])",
+		Rule:          `where :[args] == "success"`,
+		ResultKind:    comby.MatchOnly,
+		Engine:        comby.EngineNative,
+	}, 0, sender)
+	require.NoError(t, err)
+
+	want := []protocol.FileMatch{{
+		Path: "main.go",
+		ChunkMatches: []protocol.ChunkMatch{{
+			Content:      "func foo(success) {} func bar(fail) {}",
+			ContentStart: protocol.Location{Offset: 16, Line: 1, Column: 0},
+			Ranges: []protocol.Range{{
+				Start: protocol.Location{Offset: 16, Line: 1, Column: 0},
+				End:   protocol.Location{Offset: 33, Line: 1, Column: 17},
+			}},
+		}},
+	}}
+	require.Equal(t, want, sender.collected)
+	require.Empty(t, stats.SkippedFiles)
+}
+
+// maybeSkipComby skips structural search tests on hosts where the comby
+// executable does not work. If comby is not installed, the tests run with the
+// native matcher.
 func maybeSkipComby(t *testing.T) {
 	t.Helper()
 	if os.Getenv("CI") != "" {
 		return
 	}
+	if _, err := exec.LookPath("comby"); err != nil {
+		return
+	}
 	if runtime.GOOS == "darwin" && runtime.GOARCH == "arm64" {
 		t.Skip("Skipping due to limitations in comby and M1")
 	}
-	if _, err := exec.LookPath("comby"); err != nil {
-		t.Skipf("skipping comby test when not on CI: %v", err)
-	}
 }
 
 func Test_addContext(t *testing.T) {
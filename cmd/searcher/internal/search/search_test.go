@@ -263,6 +263,18 @@ import "fmt"
 
 func main() {
 `),
+	}, {
+		// import NEAR/2 package
+		arg: protocol.PatternInfo{Pattern: "import", NearPattern: "package", NearDistance: 2, IsRegExp: true, PatternMatchesContent: true},
+		want: autogold.Expect(`main.go:1:3:
+package main
+
+import "fmt"
+`),
+	}, {
+		// fmt NEAR/1 package doesn't match, the terms are two lines apart.
+		arg:  protocol.PatternInfo{Pattern: "fmt", NearPattern: "package", NearDistance: 1, IsRegExp: true, PatternMatchesContent: true},
+		want: autogold.Expect(""),
 	}, {
 		arg: protocol.PatternInfo{Pattern: "\n", IsCaseSensitive: false, IsRegExp: true, PatternMatchesPath: true, PatternMatchesContent: true},
 		want: autogold.Expect(`README.md:1:3:
@@ -14,6 +14,9 @@ type matchSender interface {
 	SentCount() int
 	Remaining() int
 	LimitHit() bool
+	// SetLimitHit marks the results as incomplete without sending a match,
+	// for example when files couldn't be searched.
+	SetLimitHit()
 }
 
 type limitedStream struct {
@@ -87,6 +90,10 @@ func (m *limitedStream) LimitHit() bool {
 	return m.limitHit.Load()
 }
 
+func (m *limitedStream) SetLimitHit() {
+	m.limitHit.Store(true)
+}
+
 type limitedStreamCollector struct {
 	collected []protocol.FileMatch
 	mux       sync.Mutex
@@ -1,6 +1,7 @@
 package search
 
 import (
+	"bytes"
 	"context"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/sourcegraph/zoekt/query"
 	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/comby"
 	"github.com/sourcegraph/sourcegraph/internal/search"
@@ -62,3 +64,60 @@ func Test_zoektSearch(t *testing.T) {
 	)
 	require.Error(t, err)
 }
+
+// Test_zoektSearchMultiline runs multilineCases against an in-memory zoekt
+// index, using the same query as hybrid search. The ranges must match the
+// ones reported by TestRegexSearchMultiline.
+func Test_zoektSearchMultiline(t *testing.T) {
+	b, err := zoekt.NewIndexBuilder(&zoekt.Repository{
+		Name:     "foo",
+		ID:       1,
+		Branches: []zoekt.RepositoryBranch{{Name: "HEAD", Version: "deadbeef"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, b.Add(zoekt.Document{
+		Name:     "main.go",
+		Content:  []byte(multilineFile),
+		Branches: []string{"HEAD"},
+	}))
+	var buf bytes.Buffer
+	require.NoError(t, b.Write(&buf))
+	searcher, err := zoekt.NewSearcher(&memIndexFile{data: buf.Bytes()})
+	require.NoError(t, err)
+	t.Cleanup(searcher.Close)
+
+	for _, tc := range multilineCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := zoektCompile(&protocol.PatternInfo{
+				Pattern:               tc.pattern,
+				IsRegExp:              true,
+				IsMultiline:           tc.multiline,
+				PatternMatchesContent: true,
+			})
+			require.NoError(t, err)
+
+			res, err := searcher.Search(context.Background(), q, &zoekt.SearchOptions{ChunkMatches: true})
+			require.NoError(t, err)
+
+			var got []protocol.Range
+			for _, fm := range res.Files {
+				for _, cm := range zoektChunkMatches(fm.ChunkMatches) {
+					got = append(got, cm.Ranges...)
+				}
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// memIndexFile is a zoekt.IndexFile backed by a byte slice.
+type memIndexFile struct {
+	data []byte
+}
+
+func (f *memIndexFile) Name() string { return "memIndexFile" }
+func (f *memIndexFile) Close()       {}
+func (f *memIndexFile) Read(off, sz uint32) ([]byte, error) {
+	return f.data[off : off+sz], nil
+}
+func (f *memIndexFile) Size() (uint32, error) { return uint32(len(f.data)), nil }
@@ -70,6 +70,12 @@ type PatternInfo struct {
 	// IsRegExp if true will treat the Pattern as a regular expression.
 	IsRegExp bool
 
+	// NearPattern, if set, is the right-hand side of a NEAR/n expression. It
+	// is always a regular expression. Pattern then only matches where
+	// NearPattern matches at most NearDistance lines away.
+	NearPattern  string
+	NearDistance int
+
 	// IsStructuralPat if true will treat the pattern as a Comby structural search pattern.
 	IsStructuralPat bool
 
@@ -77,6 +83,11 @@ type PatternInfo struct {
 	// when finding matches.
 	IsCaseSensitive bool
 
+	// IsMultiline if true will let "." in the pattern match newlines, so
+	// that a regular expression can match across lines. It only applies
+	// when IsRegExp is true.
+	IsMultiline bool
+
 	// ExcludePattern is a pattern that may not match the returned files' paths.
 	// eg '**/node_modules'
 	ExcludePattern string
@@ -128,6 +139,9 @@ func (p *PatternInfo) String() string {
 	if p.IsRegExp {
 		args = append(args, "re")
 	}
+	if p.NearPattern != "" {
+		args = append(args, fmt.Sprintf("near/%d:%q", p.NearDistance, p.NearPattern))
+	}
 	if p.IsStructuralPat {
 		if p.CombyRule != "" {
 			args = append(args, fmt.Sprintf("comby:%s", p.CombyRule))
@@ -138,6 +152,9 @@ func (p *PatternInfo) String() string {
 	if p.IsCaseSensitive {
 		args = append(args, "case")
 	}
+	if p.IsMultiline {
+		args = append(args, "multiline")
+	}
 	if !p.PatternMatchesContent {
 		args = append(args, "nocontent")
 	}
@@ -180,8 +197,11 @@ func (r *Request) ToProto() *proto.SearchRequest {
 			Pattern:                      r.PatternInfo.Pattern,
 			IsNegated:                    r.PatternInfo.IsNegated,
 			IsRegexp:                     r.PatternInfo.IsRegExp,
+			NearPattern:                  r.PatternInfo.NearPattern,
+			NearDistance:                 int64(r.PatternInfo.NearDistance),
 			IsStructural:                 r.PatternInfo.IsStructuralPat,
 			IsCaseSensitive:              r.PatternInfo.IsCaseSensitive,
+			IsMultiline:                  r.PatternInfo.IsMultiline,
 			ExcludePattern:               r.PatternInfo.ExcludePattern,
 			IncludePatterns:              r.PatternInfo.IncludePatterns,
 			PathPatternsAreCaseSensitive: r.PatternInfo.PathPatternsAreCaseSensitive,
@@ -208,8 +228,11 @@ func (r *Request) FromProto(req *proto.SearchRequest) {
 			Pattern:                      req.PatternInfo.Pattern,
 			IsNegated:                    req.PatternInfo.IsNegated,
 			IsRegExp:                     req.PatternInfo.IsRegexp,
+			NearPattern:                  req.PatternInfo.NearPattern,
+			NearDistance:                 int(req.PatternInfo.NearDistance),
 			IsStructuralPat:              req.PatternInfo.IsStructural,
 			IsCaseSensitive:              req.PatternInfo.IsCaseSensitive,
+			IsMultiline:                  req.PatternInfo.IsMultiline,
 			ExcludePattern:               req.PatternInfo.ExcludePattern,
 			IncludePatterns:              req.PatternInfo.IncludePatterns,
 			PathPatternsAreCaseSensitive: req.PatternInfo.PathPatternsAreCaseSensitive,
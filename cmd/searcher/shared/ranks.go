@@ -0,0 +1,58 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/api/internalapi"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+type documentRanksCacheEntry struct {
+	ranks     types.RepoPathRanks
+	fetchedAt time.Time
+}
+
+// documentRanks returns the document ranks of repositories, as computed by
+// code intelligence ranking. Ranks are cached since they change at most
+// once per ranking job.
+type documentRanks struct {
+	cache *lru.Cache[api.RepoName, documentRanksCacheEntry]
+	fetch func(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error)
+	ttl   time.Duration
+}
+
+func newDocumentRanks() (*documentRanks, error) {
+	cache, err := lru.New[api.RepoName, documentRanksCacheEntry](documentRanksCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &documentRanks{
+		cache: cache,
+		fetch: internalapi.Client.DocumentRanks,
+		ttl:   documentRanksCacheTTL,
+	}, nil
+}
+
+// Get returns the document ranks of repo. Empty ranks are returned if
+// document ranking is disabled.
+func (d *documentRanks) Get(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error) {
+	if !conf.CodeIntelRankingDocumentReferenceCountsEnabled() {
+		return types.RepoPathRanks{}, nil
+	}
+
+	if e, ok := d.cache.Get(repo); ok && time.Since(e.fetchedAt) < d.ttl {
+		return e.ranks, nil
+	}
+
+	ranks, err := d.fetch(ctx, repo)
+	if err != nil {
+		return types.RepoPathRanks{}, err
+	}
+	d.cache.Add(repo, documentRanksCacheEntry{ranks: ranks, fetchedAt: time.Now()})
+	return ranks, nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/blobcache"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	internalgrpc "github.com/sourcegraph/sourcegraph/internal/grpc"
@@ -47,6 +48,15 @@ var (
 	backgroundTimeout = env.MustGetDuration("PROCESSING_TIMEOUT", 2*time.Hour, "maximum time to spend processing a repository")
 
 	maxTotalPathsLengthRaw = env.Get("MAX_TOTAL_PATHS_LENGTH", "100000", "maximum sum of lengths of all paths in a single call to git archive")
+
+	documentRanksCacheSize = env.MustGetInt("SEARCHER_DOCUMENT_RANKS_CACHE_SIZE", 1000, "number of repositories to cache document ranks for")
+	documentRanksCacheTTL  = env.MustGetDuration("SEARCHER_DOCUMENT_RANKS_CACHE_TTL", 10*time.Minute, "how long to cache the document ranks of a repository")
+
+	// Archives assembled from cached blobs match the ones created by
+	// gitserver with git archive --worktree-attributes, which ignores the
+	// .gitattributes files of the repository: export-ignore files are
+	// included and export-subst placeholders are not expanded.
+	blobCacheSizeMB = env.MustGetInt("SEARCHER_BLOB_CACHE_SIZE_MB", 0, "maximum size of the on disk cache of git blobs in megabytes. If greater than zero, archives are assembled from cached blobs instead of being created by gitserver")
 )
 
 const port = "3181"
@@ -123,23 +133,43 @@ func Start(ctx context.Context, observationCtx *observation.Context, ready servi
 		return errors.Wrap(err, "failed to setup TMPDIR")
 	}
 
+	ranks, err := newDocumentRanks()
+	if err != nil {
+		return errors.Wrap(err, "failed to create document ranks cache")
+	}
+
 	// Explicitly don't scope Store logger under the parent logger
 	storeObservationCtx := observation.NewContext(log.Scoped("Store"))
 
 	git := gitserver.NewClient("searcher")
 
+	fetchTar := func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
+		// We pass in a nil sub-repo permissions checker and an internal actor here since
+		// searcher needs access to all data in the archive.
+		ctx = actor.WithInternalActor(ctx)
+		return git.ArchiveReader(ctx, repo, gitserver.ArchiveOptions{
+			Treeish: string(commit),
+			Format:  gitserver.ArchiveFormatTar,
+		})
+	}
+
+	var routines []goroutine.BackgroundRoutine
+	if blobCacheSizeMB > 0 {
+		// Consecutive commits of a repo share most of their blobs, so only the
+		// blobs that changed since a commit that was searched before are
+		// transferred from gitserver.
+		blobs := blobcache.New(observationCtx, git, filepath.Join(cacheDir, "searcher-blobs"), int64(blobCacheSizeMB)*1000*1000)
+		routines = append(routines, blobs.NewEvicter(ctx, 10*time.Second))
+		fetchTar = func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
+			ctx = actor.WithInternalActor(ctx)
+			return blobs.TarReader(ctx, repo, commit)
+		}
+	}
+
 	sService := &search.Service{
 		Store: &search.Store{
 			GitserverClient: git,
-			FetchTar: func(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
-				// We pass in a nil sub-repo permissions checker and an internal actor here since
-				// searcher needs access to all data in the archive.
-				ctx = actor.WithInternalActor(ctx)
-				return git.ArchiveReader(ctx, repo, gitserver.ArchiveOptions{
-					Treeish: string(commit),
-					Format:  gitserver.ArchiveFormatTar,
-				})
-			},
+			FetchTar:        fetchTar,
 			FetchTarPaths: func(ctx context.Context, repo api.RepoName, commit api.CommitID, paths []string) (io.ReadCloser, error) {
 				pathspecs := make([]gitdomain.Pathspec, len(paths))
 				for i, p := range paths {
@@ -164,13 +194,15 @@ func Start(ctx context.Context, observationCtx *observation.Context, ready servi
 
 		Indexed: sharedsearch.Indexed(),
 
-		GitDiffSymbols: func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]byte, error) {
+		GitDiffSymbols: func(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]gitdomain.PathStatus, error) {
 			// As this is an internal service call, we need an internal actor.
 			ctx = actor.WithInternalActor(ctx)
 			return git.DiffSymbols(ctx, repo, commitA, commitB)
 		},
 		MaxTotalPathsLength: maxTotalPathsLength,
 
+		DocumentRanks: ranks.Get,
+
 		Log: logger,
 	}
 	sService.Store.Start()
@@ -223,6 +255,13 @@ func Start(ctx context.Context, observationCtx *observation.Context, ready servi
 		return shutdownOnSignal(ctx, server)
 	})
 
+	if len(routines) > 0 {
+		g.Go(func() error {
+			goroutine.MonitorBackgroundRoutines(ctx, routines...)
+			return nil
+		})
+	}
+
 	return g.Wait()
 }
 
@@ -6,6 +6,12 @@ git version
 
 git-lfs version
 
+hg --version
+
+test -x /usr/bin/git-remote-hg
+
+python3 -c "import mercurial"
+
 postgres --version
 
 bash --version
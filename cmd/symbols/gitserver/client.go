@@ -1,7 +1,6 @@
 package gitserver
 
 import (
-	"bytes"
 	"context"
 	"io"
 
@@ -86,14 +85,12 @@ func (c *gitserverClient) GitDiff(ctx context.Context, repo api.RepoName, commit
 	}})
 	defer endObservation(1, observation.Args{})
 
-	output, err := c.innerClient.DiffSymbols(ctx, repo, commitA, commitB)
-
-	changes, err := parseGitDiffOutput(output)
+	pathStatuses, err := c.innerClient.DiffSymbols(ctx, repo, commitA, commitB)
 	if err != nil {
-		return Changes{}, errors.Wrap(err, "failed to parse git diff output")
+		return Changes{}, err
 	}
 
-	return changes, nil
+	return changesFromPathStatuses(pathStatuses), nil
 }
 
 func (c *gitserverClient) ReadFile(ctx context.Context, repoCommitPath types.RepoCommitPath) ([]byte, error) {
@@ -112,30 +109,19 @@ func (c *gitserverClient) RevList(ctx context.Context, repo string, commit strin
 	return c.innerClient.RevList(ctx, repo, commit, onCommit)
 }
 
-var NUL = []byte{0}
-
-// parseGitDiffOutput parses the output of a git diff command, which consists
-// of a repeated sequence of `<status> NUL <path> NUL` where NUL is the 0 byte.
-func parseGitDiffOutput(output []byte) (changes Changes, _ error) {
-	if len(output) == 0 {
-		return Changes{}, nil
-	}
-
-	slices := bytes.Split(bytes.TrimRight(output, string(NUL)), NUL)
-	if len(slices)%2 != 0 {
-		return changes, errors.Newf("uneven pairs")
-	}
-
-	for i := 0; i < len(slices); i += 2 {
-		switch slices[i][0] {
-		case 'A':
-			changes.Added = append(changes.Added, string(slices[i+1]))
-		case 'M':
-			changes.Modified = append(changes.Modified, string(slices[i+1]))
-		case 'D':
-			changes.Deleted = append(changes.Deleted, string(slices[i+1]))
+// changesFromPathStatuses groups the paths changed between two commits by
+// their status.
+func changesFromPathStatuses(pathStatuses []gitdomain.PathStatus) (changes Changes) {
+	for _, p := range pathStatuses {
+		switch p.Status {
+		case gitdomain.AddedAMD:
+			changes.Added = append(changes.Added, p.Path)
+		case gitdomain.ModifiedAMD:
+			changes.Modified = append(changes.Modified, p.Path)
+		case gitdomain.DeletedAMD:
+			changes.Deleted = append(changes.Deleted, p.Path)
 		}
 	}
 
-	return changes, nil
+	return changes
 }
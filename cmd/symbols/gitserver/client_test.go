@@ -4,26 +4,27 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 )
 
-func TestParseGitDiffOutput(t *testing.T) {
+func TestChangesFromPathStatuses(t *testing.T) {
 	testCases := []struct {
-		output          []byte
+		pathStatuses    []gitdomain.PathStatus
 		expectedChanges Changes
-		shouldError     bool
 	}{
 		{
-			output: combineBytes(
-				[]byte("A"), NUL, []byte("added1.json"), NUL,
-				[]byte("M"), NUL, []byte("modified1.json"), NUL,
-				[]byte("D"), NUL, []byte("deleted1.json"), NUL,
-				[]byte("A"), NUL, []byte("added2.json"), NUL,
-				[]byte("M"), NUL, []byte("modified2.json"), NUL,
-				[]byte("D"), NUL, []byte("deleted2.json"), NUL,
-				[]byte("A"), NUL, []byte("added3.json"), NUL,
-				[]byte("M"), NUL, []byte("modified3.json"), NUL,
-				[]byte("D"), NUL, []byte("deleted3.json"), NUL,
-			),
+			pathStatuses: []gitdomain.PathStatus{
+				{Path: "added1.json", Status: gitdomain.AddedAMD},
+				{Path: "modified1.json", Status: gitdomain.ModifiedAMD},
+				{Path: "deleted1.json", Status: gitdomain.DeletedAMD},
+				{Path: "added2.json", Status: gitdomain.AddedAMD},
+				{Path: "modified2.json", Status: gitdomain.ModifiedAMD},
+				{Path: "deleted2.json", Status: gitdomain.DeletedAMD},
+				{Path: "added3.json", Status: gitdomain.AddedAMD},
+				{Path: "modified3.json", Status: gitdomain.ModifiedAMD},
+				{Path: "deleted3.json", Status: gitdomain.DeletedAMD},
+			},
 			expectedChanges: Changes{
 				Added:    []string{"added1.json", "added2.json", "added3.json"},
 				Modified: []string{"modified1.json", "modified2.json", "modified3.json"},
@@ -31,38 +32,14 @@ func TestParseGitDiffOutput(t *testing.T) {
 			},
 		},
 		{
-			output: combineBytes(
-				[]byte("A"), NUL, []byte("added1.json"), NUL,
-				[]byte("M"), NUL, []byte("modified1.json"), NUL,
-				[]byte("D"), NUL,
-			),
-			shouldError: true,
-		},
-		{
-			output: []byte{},
+			pathStatuses: nil,
 		},
 	}
 
 	for _, testCase := range testCases {
-		changes, err := parseGitDiffOutput(testCase.output)
-		if err != nil {
-			if !testCase.shouldError {
-				t.Fatalf("unexpected error parsing git diff output: %s", err)
-			}
-		} else if testCase.shouldError {
-			t.Fatalf("expected error, got none")
-		}
-
+		changes := changesFromPathStatuses(testCase.pathStatuses)
 		if diff := cmp.Diff(testCase.expectedChanges, changes); diff != "" {
 			t.Errorf("unexpected changes (-want +got):\n%s", diff)
 		}
 	}
 }
-
-func combineBytes(bss ...[]byte) (combined []byte) {
-	for _, bs := range bss {
-		combined = append(combined, bs...)
-	}
-
-	return combined
-}
@@ -20,6 +20,18 @@ class C {
 		int x = 5;
 	}
 }
+`
+
+	javaBlock := `
+class D {
+	void m() {
+		/**
+		 * comment line 1
+		 * comment line 2
+		 */
+		int x = 5;
+	}
+}
 `
 
 	golang := `
@@ -52,6 +64,7 @@ namespace Foo {
 		want     string
 	}{
 		{"test.java", java, "comment line 1\ncomment line 2\n"},
+		{"block.java", javaBlock, "comment line 1\ncomment line 2\n"},
 		{"test.go", golang, "comment line 1\ncomment line 2\n"},
 		{"test.cs", csharp, "comment line 1\ncomment line 2\n"},
 	}
@@ -0,0 +1,291 @@
+package squirrel
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grafana/regexp"
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/bash"
+	"github.com/smacker/go-tree-sitter/c"
+	"github.com/smacker/go-tree-sitter/cpp"
+	"github.com/smacker/go-tree-sitter/csharp"
+	"github.com/smacker/go-tree-sitter/elixir"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/java"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/kotlin"
+	"github.com/smacker/go-tree-sitter/php"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/ruby"
+	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/scala"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// Mapping from grammar name to the tree-sitter grammars that languages
+// configured in site configuration can be parsed with. Keep in sync with the
+// enum of codeIntelSearchBased.treeSitterLanguages in site.schema.json.
+var grammars = map[string]*sitter.Language{
+	"bash":       bash.GetLanguage(),
+	"c":          c.GetLanguage(),
+	"cpp":        cpp.GetLanguage(),
+	"csharp":     csharp.GetLanguage(),
+	"elixir":     elixir.GetLanguage(),
+	"go":         golang.GetLanguage(),
+	"java":       java.GetLanguage(),
+	"javascript": javascript.GetLanguage(),
+	"kotlin":     kotlin.GetLanguage(),
+	"php":        php.GetLanguage(),
+	"python":     python.GetLanguage(),
+	"ruby":       ruby.GetLanguage(),
+	"rust":       rust.GetLanguage(),
+	"scala":      scala.GetLanguage(),
+	"typescript": tsx.GetLanguage(),
+}
+
+// customLanguages are languages configured in site configuration.
+type customLanguages struct {
+	// Mapping from file extension to language name.
+	extToLang map[string]string
+	// Mapping from language name to language specification.
+	langToLangSpec map[string]LangSpec
+}
+
+// siteCustomLanguages returns the languages configured in site
+// configuration. Invalid languages are skipped, the site config validator
+// reports them to admins.
+var siteCustomLanguages = conf.Cached(func() customLanguages {
+	langs, _ := newCustomLanguages(conf.Get().CodeIntelSearchBasedTreeSitterLanguages)
+	return langs
+})
+
+func init() {
+	conf.ContributeValidator(func(c conftypes.SiteConfigQuerier) (problems conf.Problems) {
+		_, errs := newCustomLanguages(c.SiteConfig().CodeIntelSearchBasedTreeSitterLanguages)
+		for _, err := range errs {
+			problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("codeIntelSearchBased.treeSitterLanguages: %s", err)))
+		}
+		return problems
+	})
+}
+
+// localsCaptureReplacer renames the captures used by tree-sitter locals
+// queries to the ones squirrel uses.
+var localsCaptureReplacer = strings.NewReplacer(
+	"@local.scope", "@scope",
+	"@local.definition", "@definition",
+)
+
+// newCustomLanguages builds the language specifications of the given
+// languages. It returns the valid languages along with an error describing
+// each invalid one.
+func newCustomLanguages(configs []*schema.TreeSitterLanguage) (customLanguages, []error) {
+	langs := customLanguages{
+		extToLang:      map[string]string{},
+		langToLangSpec: map[string]LangSpec{},
+	}
+
+	var errs []error
+	for _, config := range configs {
+		spec, exts, err := newCustomLangSpec(config)
+		if err == nil {
+			if _, ok := langs.langToLangSpec[config.Name]; ok {
+				err = errors.New("defined more than once")
+			}
+		}
+		if err == nil {
+			for _, ext := range exts {
+				if other, ok := langs.extToLang[ext]; ok {
+					err = errors.Newf("file extension %q is also used by %s", ext, other)
+					break
+				}
+			}
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "language %q", config.Name))
+			continue
+		}
+
+		langs.langToLangSpec[config.Name] = spec
+		for _, ext := range exts {
+			langs.extToLang[ext] = config.Name
+		}
+	}
+
+	return langs, errs
+}
+
+// newCustomLangSpec validates config and returns the specification and file
+// extensions of the language.
+func newCustomLangSpec(config *schema.TreeSitterLanguage) (LangSpec, []string, error) {
+	if _, ok := langToLangSpec[config.Name]; ok {
+		return LangSpec{}, nil, errors.New("the language has built-in support")
+	}
+
+	language, ok := grammars[config.Grammar]
+	if !ok {
+		names := make([]string, 0, len(grammars))
+		for name := range grammars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return LangSpec{}, nil, errors.Newf("unknown grammar %q, expected one of %s", config.Grammar, strings.Join(names, ", "))
+	}
+
+	exts := config.Extensions
+	if len(exts) == 0 {
+		exts = langToExts[config.Name]
+	}
+	if len(exts) == 0 {
+		return LangSpec{}, nil, errors.New("no file extensions are known for the language, set extensions")
+	}
+	for _, ext := range exts {
+		if lang, ok := extToLang[ext]; ok {
+			if _, ok := langToLangSpec[lang]; ok {
+				return LangSpec{}, nil, errors.Newf("file extension %q is used by %s, which has built-in support", ext, lang)
+			}
+		}
+	}
+
+	localsQuery := localsCaptureReplacer.Replace(config.LocalsQuery)
+	if err := validateQuery(localsQuery, language); err != nil {
+		return LangSpec{}, nil, errors.Wrap(err, "invalid localsQuery")
+	}
+	if err := validateQuery(config.TagsQuery, language); err != nil {
+		return LangSpec{}, nil, errors.Wrap(err, "invalid tagsQuery")
+	}
+
+	nodeTypes := config.CommentNodeTypes
+	if len(nodeTypes) == 0 {
+		nodeTypes = []string{"comment"}
+	}
+	var stripRegex *regexp.Regexp
+	if config.CommentStripPattern != "" {
+		var err error
+		if stripRegex, err = regexp.Compile(config.CommentStripPattern); err != nil {
+			return LangSpec{}, nil, errors.Wrap(err, "invalid commentStripPattern")
+		}
+	}
+
+	return LangSpec{
+		name:     config.Name,
+		language: language,
+		commentStyle: CommentStyle{
+			nodeTypes:     nodeTypes,
+			stripRegex:    stripRegex,
+			codeFenceName: config.Name,
+		},
+		localsQuery: localsQuery,
+		tagsQuery:   config.TagsQuery,
+		custom:      true,
+	}, exts, nil
+}
+
+func validateQuery(query string, language *sitter.Language) error {
+	if query == "" {
+		return nil
+	}
+	sitterQuery, err := sitter.NewQuery([]byte(query), language)
+	if err != nil {
+		return err
+	}
+	sitterQuery.Close()
+	return nil
+}
+
+// getDefCustom finds the definition of an identifier in a language configured
+// in site configuration. It looks for the nearest enclosing scope defining the
+// name, then for a top-level definition in the file, and finally asks the
+// symbols service for a definition elsewhere in the repository.
+func (s *SquirrelService) getDefCustom(ctx context.Context, node Node) (ret *Node, err error) {
+	defer s.onCall(node, String(node.Type()), lazyNodeStringer(&ret))()
+
+	if !strings.Contains(node.Type(), "identifier") {
+		return nil, nil
+	}
+	name := node.Content(node.Contents)
+	root := swapNode(node, getRoot(node.Node))
+
+	// Collect scopes
+	scopes := map[NodeId]struct{}{}
+	forEachCapture(root.LangSpec.localsQuery, root, func(nameToNode map[string]Node) {
+		if scope, ok := nameToNode["scope"]; ok {
+			scopes[nodeId(scope.Node)] = struct{}{}
+		}
+	})
+
+	// Collect the first definition of name in each scope
+	defs := map[NodeId]*Node{}
+	forEachCapture(root.LangSpec.localsQuery, root, func(nameToNode map[string]Node) {
+		for captureName, def := range nameToNode {
+			if !strings.HasPrefix(captureName, "definition") || def.Content(def.Contents) != name {
+				continue
+			}
+			for cur := def.Node; cur != nil; cur = cur.Parent() {
+				if _, ok := scopes[nodeId(cur)]; ok {
+					if _, ok := defs[nodeId(cur)]; !ok {
+						def := def
+						defs[nodeId(cur)] = &def
+					}
+					break
+				}
+			}
+		}
+	})
+
+	// Walk up the scopes enclosing the identifier
+	for cur := node.Node; cur != nil; cur = cur.Parent() {
+		if def, ok := defs[nodeId(cur)]; ok {
+			return def, nil
+		}
+	}
+
+	// Look for a top-level definition in the file
+	if def := tagNamed(name, root); def != nil {
+		return def, nil
+	}
+
+	if s.symbolSearch == nil {
+		return nil, nil
+	}
+	include := []string{regexp.QuoteMeta(filepath.Ext(node.RepoCommitPath.Path)) + "$"}
+	return s.symbolSearchOne(ctx, node.RepoCommitPath.Repo, node.RepoCommitPath.Commit, include, name)
+}
+
+// tagNamed returns the top-level definition of name captured with @name or
+// @symbol by the tags query of the language, or nil if there is none.
+func tagNamed(name string, root Node) *Node {
+	if root.LangSpec.tagsQuery == "" {
+		return nil
+	}
+
+	var found *Node
+	withQuery(root.LangSpec.tagsQuery, root, func(sitterQuery *sitter.Query, cursor *sitter.QueryCursor) {
+		for found == nil {
+			match, ok := cursor.NextMatch()
+			if !ok {
+				return
+			}
+			for _, capture := range match.Captures {
+				captureName := sitterQuery.CaptureNameForId(capture.Index)
+				if captureName != "name" && captureName != "symbol" {
+					continue
+				}
+				if capture.Node.Content(root.Contents) == name {
+					found = swapNodePtr(root, capture.Node)
+					break
+				}
+			}
+		}
+	})
+	return found
+}
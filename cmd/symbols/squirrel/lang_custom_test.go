@@ -0,0 +1,150 @@
+package squirrel
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var scalaLanguage = &schema.TreeSitterLanguage{
+	Name:    "scala",
+	Grammar: "scala",
+	LocalsQuery: `
+(function_definition) @local.scope
+(block)               @local.scope
+
+(parameter      name:    (identifier) @local.definition)
+(val_definition pattern: (identifier) @local.definition)
+(var_definition pattern: (identifier) @local.definition)
+`,
+	TagsQuery: `
+(compilation_unit (class_definition  name: (identifier) @name)) @definition.class
+(compilation_unit (object_definition name: (identifier) @name)) @definition.object
+(compilation_unit (trait_definition  name: (identifier) @name)) @definition.interface
+`,
+}
+
+func TestNewCustomLanguages(t *testing.T) {
+	langs, errs := newCustomLanguages([]*schema.TreeSitterLanguage{
+		scalaLanguage,
+		{Name: "java", Grammar: "java", LocalsQuery: "(block) @scope"},
+		{Name: "kotlin", Grammar: "kotlin", LocalsQuery: "(function_body) @scope"},
+		{Name: "cobol", Grammar: "cobol", Extensions: []string{"cbl"}, LocalsQuery: "(block) @scope"},
+		{Name: "elixir", Grammar: "ruby", LocalsQuery: "(not_a_node) @scope"},
+		{Name: "sbt", Grammar: "scala", Extensions: []string{"sbt"}, LocalsQuery: "(block) @scope"},
+		{Name: "mystery", Grammar: "python", LocalsQuery: "(block) @scope"},
+		{Name: "gosh", Grammar: "go", Extensions: []string{"go"}, LocalsQuery: "(block) @scope"},
+	})
+
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`language "java": the language has built-in support`,
+		`language "cobol": unknown grammar "cobol", expected one of bash, c, cpp, csharp, elixir, go, java, javascript, kotlin, php, python, ruby, rust, scala, typescript`,
+		`language "elixir": invalid localsQuery: `,
+		`language "sbt": file extension "sbt" is also used by scala`,
+		`language "mystery": no file extensions are known for the language, set extensions`,
+		`language "gosh": file extension "go" is used by go, which has built-in support`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got errors %q, want %d errors", got, len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(got[i], want[i]) {
+			t.Errorf("error %d: got %q, want prefix %q", i, got[i], want[i])
+		}
+	}
+
+	if diff := cmp.Diff(map[string]string{
+		"kt":    "kotlin",
+		"ktm":   "kotlin",
+		"kts":   "kotlin",
+		"sbt":   "scala",
+		"sc":    "scala",
+		"scala": "scala",
+	}, langs.extToLang); diff != "" {
+		t.Errorf("unexpected extensions (-want +got):\n%s", diff)
+	}
+	if spec := langs.langToLangSpec["scala"]; !spec.custom || strings.Contains(spec.localsQuery, "@local.") {
+		t.Errorf("unexpected scala spec: %+v", spec)
+	}
+}
+
+func TestCustomLanguageDefinition(t *testing.T) {
+	langs, errs := newCustomLanguages([]*schema.TreeSitterLanguage{scalaLanguage})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	old := siteCustomLanguages
+	siteCustomLanguages = func() customLanguages { return langs }
+	t.Cleanup(func() { siteCustomLanguages = old })
+
+	contents := `class Greeter {
+  def greet(name: String): String = {
+    val greeting = "Hello, " + name
+    greeting
+  }
+}
+
+object Main {
+  def main(args: Array[String]): Unit = {
+    val greeter = new Greeter
+    println(greeter.greet("world"))
+  }
+}
+`
+	readFile := func(ctx context.Context, path types.RepoCommitPath) ([]byte, error) {
+		return []byte(contents), nil
+	}
+	path := types.RepoCommitPath{Repo: "foo", Commit: "abc", Path: "Main.scala"}
+
+	cases := []struct {
+		name      string
+		ref       types.Range
+		wantDef   *types.Range
+		wantIdent string
+	}{
+		{name: "parameter", ref: types.Range{Row: 2, Column: 31}, wantDef: &types.Range{Row: 1, Column: 12, Length: 4}, wantIdent: "name"},
+		{name: "local", ref: types.Range{Row: 3, Column: 4}, wantDef: &types.Range{Row: 2, Column: 8, Length: 8}, wantIdent: "greeting"},
+		{name: "top-level", ref: types.Range{Row: 9, Column: 22}, wantDef: &types.Range{Row: 0, Column: 6, Length: 7}, wantIdent: "Greeter"},
+		{name: "unknown", ref: types.Range{Row: 10, Column: 4}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			squirrel := New(readFile, nil)
+			defer squirrel.Close()
+
+			info, err := squirrel.SymbolInfo(context.Background(), types.RepoCommitPathPoint{
+				RepoCommitPath: path,
+				Point:          types.Point{Row: tc.ref.Row, Column: tc.ref.Column},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.wantDef == nil {
+				if info != nil {
+					t.Fatalf("expected no definition, got %+v", info.Definition)
+				}
+				return
+			}
+			if info == nil {
+				t.Fatal("expected a definition")
+			}
+			if diff := cmp.Diff(tc.wantDef, info.Definition.Range); diff != "" {
+				t.Fatalf("unexpected definition (-want +got):\n%s", diff)
+			}
+			line := strings.Split(contents, "\n")[tc.wantDef.Row]
+			if got := line[tc.wantDef.Column : tc.wantDef.Column+tc.wantDef.Length]; got != tc.wantIdent {
+				t.Fatalf("definition is %q, want %q", got, tc.wantIdent)
+			}
+		})
+	}
+}
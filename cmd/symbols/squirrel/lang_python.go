@@ -84,14 +84,18 @@ func (s *SquirrelService) getDefPython(ctx context.Context, node Node) (ret *Nod
 				continue
 
 			case "except_clause":
-				if cur.NamedChildCount() < 3 {
+				//        vvvvvvvvvvvvvv as_pattern
+				//                     v as_pattern_target
+				// except Exception as e:
+				pattern := cur.NamedChild(0)
+				if pattern == nil || pattern.Type() != "as_pattern" {
 					continue
 				}
-				//        vvvvvvvvv identifier
-				//                     v identifier
-				//                      v block
-				// except Exception as e:
-				exceptIdent := cur.NamedChild(1)
+				alias := pattern.ChildByFieldName("alias")
+				if alias == nil || alias.NamedChildCount() == 0 {
+					continue
+				}
+				exceptIdent := alias.NamedChild(0)
 				if exceptIdent == nil || exceptIdent.Type() != "identifier" {
 					continue
 				}
@@ -53,6 +53,11 @@ type LangSpec struct {
 	// localsQuery is a tree-sitter localsQuery that finds scopes and defs.
 	localsQuery          string
 	topLevelSymbolsQuery string
+	// tagsQuery is a tree-sitter query that finds the names of top-level
+	// definitions. It is only set for custom languages.
+	tagsQuery string
+	// custom is true for languages configured in site configuration.
+	custom bool
 }
 
 // CommentStyle contains info about comments in a language.
@@ -73,7 +78,7 @@ var langToLangSpec = map[string]LangSpec{
 		name:     "java",
 		language: java.GetLanguage(),
 		commentStyle: CommentStyle{
-			nodeTypes:     []string{"comment"},
+			nodeTypes:     []string{"line_comment", "block_comment"},
 			stripRegex:    javaStyleStripRegex,
 			ignoreRegex:   javaStyleIgnoreRegex,
 			codeFenceName: "java",
@@ -302,7 +307,7 @@ var pythonLocalsQuery = `
 (typed_parameter               (identifier) @definition)                                   ; def f(x: bool): ...
 (default_parameter       name: (identifier) @definition)                                   ; def f(x = False): ...
 (typed_default_parameter name: (identifier) @definition)                                   ; def f(x: bool = False): ...
-(except_clause                 (identifier) (identifier) @definition)                      ; except Exception as e: ...
+(except_clause (as_pattern alias: (as_pattern_target (identifier) @definition)))           ; except Exception as e: ...
 (expression_statement          (assignment left: (identifier) @definition))                ; x = ...
 (expression_statement          (assignment left: (pattern_list (identifier) @definition))) ; x, y = ...
 (for_statement           left: (identifier) @definition)                                   ; for x in ...: ...
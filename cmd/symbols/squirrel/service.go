@@ -152,6 +152,9 @@ func (s *SquirrelService) getDef(ctx context.Context, node Node) (*Node, error)
 	// case "cpp":
 	// case "ruby":
 	default:
+		if node.LangSpec.custom {
+			return s.getDefCustom(ctx, node)
+		}
 		// Language not implemented yet
 		return nil, nil
 	}
@@ -230,7 +230,12 @@ func (s *SquirrelService) parse(ctx context.Context, repoCommitPath types.RepoCo
 		ext = strings.TrimPrefix(filepath.Ext(repoCommitPath.Path), ".")
 	}
 
-	langName, ok := extToLang[ext]
+	custom := siteCustomLanguages()
+
+	langName, ok := custom.extToLang[ext]
+	if !ok {
+		langName, ok = extToLang[ext]
+	}
 	if !ok {
 		// It is not uncommon to have files with upper-case extensions
 		// like .C, .H, .CPP etc., especially for code developed on
@@ -241,14 +246,18 @@ func (s *SquirrelService) parse(ctx context.Context, repoCommitPath types.RepoCo
 		// but that would be incorrect as we want to distinguish files
 		// named 'build' (a common name for shell scripts) vs BUILD
 		//// (file extension for Bazel).
-		if langName, ok = extToLang[strings.ToLower(ext)]; !ok {
-			return nil, UnrecognizedFileExtensionError
+		if langName, ok = custom.extToLang[strings.ToLower(ext)]; !ok {
+			if langName, ok = extToLang[strings.ToLower(ext)]; !ok {
+				return nil, UnrecognizedFileExtensionError
+			}
 		}
 	}
 
 	langSpec, ok := langToLangSpec[langName]
 	if !ok {
-		return nil, UnsupportedLanguageError
+		if langSpec, ok = custom.langToLangSpec[langName]; !ok {
+			return nil, UnsupportedLanguageError
+		}
 	}
 
 	s.parser.SetLanguage(langSpec.language)
@@ -8,6 +8,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/diff"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/api/internalapi"
 	codeintelContext "github.com/sourcegraph/sourcegraph/internal/codeintel/context"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
@@ -161,7 +162,7 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, record *bgrepo.
 		}
 	}
 
-	ranks, err := getDocumentRanks(ctx, string(repo.Name))
+	ranks, err := internalapi.Client.DocumentRanks(ctx, repo.Name)
 	if err != nil {
 		return err
 	}
@@ -264,15 +265,12 @@ func (r *revisionFetcher) Diff(ctx context.Context, oldCommit api.CommitID) (
 	err error,
 ) {
 	ctx = actor.WithInternalActor(ctx)
-	b, err := r.gitserver.DiffSymbols(ctx, r.repo, oldCommit, r.revision)
+	changes, err := r.gitserver.DiffSymbols(ctx, r.repo, oldCommit, r.revision)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	toRemove, changedNew, err := diff.ParseGitDiffNameStatus(b)
-	if err != nil {
-		return nil, nil, err
-	}
+	toRemove, changedNew := diff.ChangedPaths(changes)
 
 	// toRemove only contains file names, but we also need the file sizes. We could
 	// ask gitserver for the file size of each file, however my intuition tells me
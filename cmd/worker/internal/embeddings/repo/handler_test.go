@@ -15,20 +15,21 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/embeddings/embed"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 )
 
 func TestDiff(t *testing.T) {
 	ctx := context.Background()
 
 	diffSymbolsFunc := &gitserver.ClientDiffSymbolsFunc{}
-	diffSymbolsFunc.SetDefaultHook(func(ctx context.Context, name api.RepoName, id api.CommitID, id2 api.CommitID) ([]byte, error) {
-		// This is a fake diff output that contains a modified, added and deleted file.
-		// The output assumes a specific order of "old commit" and "new commit" in
-		// the call to git diff.
-		//
-		// 		git diff -z --name-status --no-renames <old commit> <new commit>
-		//
-		return []byte("M\x00modifiedFile\x00A\x00addedFile\x00D\x00deletedFile\x00"), nil
+	diffSymbolsFunc.SetDefaultHook(func(ctx context.Context, name api.RepoName, id api.CommitID, id2 api.CommitID) ([]gitdomain.PathStatus, error) {
+		// This is a fake diff that contains a modified, added and deleted file,
+		// going from the "old commit" to the "new commit".
+		return []gitdomain.PathStatus{
+			{Path: "modifiedFile", Status: gitdomain.ModifiedAMD},
+			{Path: "addedFile", Status: gitdomain.AddedAMD},
+			{Path: "deletedFile", Status: gitdomain.DeletedAMD},
+		}, nil
 	})
 
 	readDirFunc := &gitserver.ClientReadDirFunc{}
@@ -69,9 +69,23 @@ func (h *exhaustiveSearchHandler) Handle(ctx context.Context, logger log.Logger,
 	}
 	defer func() { err = tx.Done(err) }()
 
+	// When a search job is retried, repo jobs created by the previous run
+	// already exist. We only create jobs for newly matching repositories.
+	existing, err := tx.ListRepoRevSpecs(ctx, record.ID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[types.RepositoryRevSpecs]bool, len(existing))
+	for _, repoRevSpec := range existing {
+		seen[repoRevSpec] = true
+	}
+
 	it := q.RepositoryRevSpecs(ctx)
 	for it.Next() {
 		repoRevSpec := it.Current()
+		if seen[repoRevSpec] {
+			continue
+		}
 		_, err := tx.CreateExhaustiveSearchRepoJob(ctx, types.ExhaustiveSearchRepoJob{
 			RepoID:      repoRevSpec.Repository,
 			RefSpec:     repoRevSpec.RevisionSpecifiers.String(),
@@ -52,7 +52,7 @@ type exhaustiveSearchRepoHandler struct {
 
 var _ workerutil.Handler[*types.ExhaustiveSearchRepoJob] = &exhaustiveSearchRepoHandler{}
 
-func (h *exhaustiveSearchRepoHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoJob) error {
+func (h *exhaustiveSearchRepoHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoJob) (err error) {
 	repoRevSpec := types.RepositoryRevSpecs{
 		Repository:         record.RepoID,
 		RevisionSpecifiers: types.RevisionSpecifiers(record.RefSpec),
@@ -82,7 +82,21 @@ func (h *exhaustiveSearchRepoHandler) Handle(ctx context.Context, logger log.Log
 	}
 	defer func() { err = tx.Done(err) }()
 
+	// When a search job is retried, revision jobs created by the previous run
+	// already exist. We only create jobs for newly resolved revisions.
+	existing, err := tx.ListRevisions(ctx, record.ID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, rev := range existing {
+		seen[rev] = true
+	}
+
 	for _, repoRev := range repoRevisions {
+		if seen[repoRev.Revision] {
+			continue
+		}
 		_, err := tx.CreateExhaustiveSearchRepoRevisionJob(ctx, types.ExhaustiveSearchRepoRevisionJob{
 			SearchRepoJobID: record.ID,
 			Revision:        repoRev.Revision,
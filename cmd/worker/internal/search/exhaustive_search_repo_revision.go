@@ -60,7 +60,7 @@ type exhaustiveSearchRepoRevHandler struct {
 var _ workerutil.Handler[*types.ExhaustiveSearchRepoRevisionJob] = &exhaustiveSearchRepoRevHandler{}
 
 func (h *exhaustiveSearchRepoRevHandler) Handle(ctx context.Context, logger log.Logger, record *types.ExhaustiveSearchRepoRevisionJob) error {
-	jobID, query, repoRev, initiatorID, err := h.store.GetQueryRepoRev(ctx, record)
+	jobID, query, format, repoRev, initiatorID, err := h.store.GetQueryRepoRev(ctx, record)
 	if err != nil {
 		return err
 	}
@@ -72,14 +72,50 @@ func (h *exhaustiveSearchRepoRevHandler) Handle(ctx context.Context, logger log.
 		return err
 	}
 
-	csvWriter := service.NewBlobstoreCSVWriter(ctx, h.uploadStore, fmt.Sprintf("%d-%d", jobID, record.ID))
+	commitID, err := q.ResolveCommit(ctx, repoRev)
+	if err != nil {
+		return err
+	}
+
+	// The job was retried and the revision still points to the commit we
+	// searched last time. The results in the upload store are up to date.
+	if record.CommitID != "" && record.CommitID == commitID {
+		return nil
+	}
 
-	err = q.Search(ctx, repoRev, csvWriter)
-	if closeErr := csvWriter.Close(); closeErr != nil {
+	// Forget the old commit before we touch the stored results. Otherwise a
+	// failure below could leave us reusing partial results on the next retry.
+	if record.CommitID != "" {
+		if err := h.store.SetRepoRevisionJobCommitID(ctx, record.ID, ""); err != nil {
+			return err
+		}
+	}
+
+	prefix := fmt.Sprintf("%d-%d", jobID, record.ID)
+
+	// Remove results of an earlier run, so that they don't get mixed up with
+	// the results of this run.
+	if err := service.DeleteBlobstoreShards(ctx, h.uploadStore, prefix); err != nil {
+		return err
+	}
+
+	// Pin the search to the commit we resolved, so that the results match
+	// the commit we record below.
+	if commitID != "" {
+		repoRev.Revision = string(commitID)
+	}
+
+	resultsWriter := service.NewBlobstoreWriter(ctx, h.uploadStore, prefix, format)
+
+	err = q.Search(ctx, repoRev, resultsWriter)
+	if closeErr := resultsWriter.Close(); closeErr != nil {
 		err = errors.Append(err, closeErr)
 	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	return h.store.SetRepoRevisionJobCommitID(ctx, record.ID, commitID)
 }
 
 func newExhaustiveSearchRepoRevisionWorkerResetter(
@@ -0,0 +1,39 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/service"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// newExhaustiveSearchScheduler creates a background routine that starts the
+// runs of scheduled search jobs and diffs the results of finished runs.
+func newExhaustiveSearchScheduler(
+	ctx context.Context,
+	observationCtx *observation.Context,
+	svc *service.Service,
+	config config,
+) goroutine.BackgroundRoutine {
+	operation := observationCtx.Operation(observation.Op{
+		Name: "exhaustive_search_scheduler.Run",
+	})
+
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			// Diff first, a job isn't run again until its last run was
+			// diffed.
+			_, diffErr := svc.DiffFinishedScheduledRuns(ctx)
+			_, runErr := svc.RunDueScheduledSearchJobs(ctx, time.Now())
+			return errors.Append(diffErr, runErr)
+		}),
+		goroutine.WithName("exhaustive_search_scheduler"),
+		goroutine.WithDescription("runs scheduled search jobs and diffs their results"),
+		goroutine.WithInterval(config.SchedulerInterval),
+		goroutine.WithOperation(operation),
+	)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/iterator"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -61,7 +62,7 @@ func TestExhaustiveSearch(t *testing.T) {
 	query := "1@rev1 1@rev2 2@rev3"
 
 	// Create a job
-	job, err := svc.CreateSearchJob(userCtx, query)
+	job, err := svc.CreateSearchJob(userCtx, query, types.OutputFormatCSV)
 	require.NoError(err)
 
 	// Do some assertions on the job before it runs
@@ -91,6 +92,8 @@ func TestExhaustiveSearch(t *testing.T) {
 		workerDB: db,
 		config: config{
 			WorkerInterval: 10 * time.Millisecond,
+			// The test runs scheduled jobs by calling the service directly.
+			SchedulerInterval: time.Hour,
 		},
 	}
 
@@ -149,6 +152,87 @@ func TestExhaustiveSearch(t *testing.T) {
 		}, stats)
 	}
 
+	// Retrying the job for changed repositories queues all jobs again. The
+	// fake searcher resolves every revision to the same commit, so the
+	// stored results are reused and nothing is uploaded again.
+	{
+		uploads := len(mockUploadStore.UploadFunc.History())
+
+		job2, err := svc.RetrySearchJob(userCtx, job.ID, types.RetryModeChanged)
+		require.NoError(err)
+		require.Equal(types.JobStateQueued, job2.State)
+
+		require.Eventually(func() bool {
+			return !searchJob.hasWork(workerCtx)
+		}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+		require.Equal(uploads, len(mockUploadStore.UploadFunc.History()))
+		require.Equal(3, len(bucket))
+
+		stats, err := svc.GetAggregateRepoRevState(userCtx, job.ID)
+		require.NoError(err)
+		require.Equal(&types.RepoRevJobStats{
+			Total:      6,
+			Completed:  6,
+			Failed:     0,
+			InProgress: 0,
+		}, stats)
+
+		// Nothing failed, so retrying failed jobs doesn't queue anything.
+		job3, err := svc.RetrySearchJob(userCtx, job.ID, types.RetryModeFailed)
+		require.NoError(err)
+		require.Equal(types.JobStateCompleted, job3.AggState)
+
+		userBadCtx := actor.WithActor(context.Background(), actor.FromUser(userBadID))
+		_, err = svc.RetrySearchJob(userBadCtx, job.ID, types.RetryModeChanged)
+		require.ErrorIs(err, auth.ErrMustBeSiteAdminOrSameUser)
+	}
+
+	// Scheduled runs copy the results of the last run and diff them against
+	// the results of the new run once it finished.
+	{
+		job2, err := svc.SetSearchJobSchedule(userCtx, job.ID, "@daily")
+		require.NoError(err)
+		require.Equal("@daily", job2.Schedule)
+		require.False(job2.NextRunAt.IsZero())
+
+		started, err := svc.RunDueScheduledSearchJobs(workerCtx, job2.NextRunAt)
+		require.NoError(err)
+		require.Equal(1, started)
+
+		require.Eventually(func() bool {
+			return !searchJob.hasWork(workerCtx)
+		}, tTimeout(t, 10*time.Second), 10*time.Millisecond)
+
+		// The run isn't started again until it was diffed.
+		started, err = svc.RunDueScheduledSearchJobs(workerCtx, job2.NextRunAt.Add(48*time.Hour))
+		require.NoError(err)
+		require.Equal(0, started)
+
+		diffed, err := svc.DiffFinishedScheduledRuns(workerCtx)
+		require.NoError(err)
+		require.Equal(1, diffed)
+
+		job3, err := svc.GetSearchJob(userCtx, job.ID)
+		require.NoError(err)
+		require.False(job3.DiffPending)
+		require.Equal(int32(0), job3.Diff.Added)
+		require.Equal(int32(0), job3.Diff.Removed)
+		require.False(job3.Diff.ComputedAt.IsZero())
+		require.True(job3.NextRunAt.After(job2.NextRunAt))
+
+		// Nothing changed, so the diff only has a header.
+		writerTo, _, err := svc.GetSearchJobDiffWriterTo(userCtx, job.ID)
+		require.NoError(err)
+		var buf bytes.Buffer
+		_, err = writerTo.WriteTo(&buf)
+		require.NoError(err)
+		require.Equal("change,repo,revspec,revision\n", buf.String())
+
+		_, err = svc.SetSearchJobSchedule(userCtx, job.ID, "")
+		require.NoError(err)
+	}
+
 	// Assert that we can write the job logs to a writer and that the number of
 	// lines and columns matches our expectation.
 	{
@@ -187,7 +271,9 @@ func TestExhaustiveSearch(t *testing.T) {
 
 	// Delete should remove the job from the database and the uploadstore.
 	{
-		require.Equal(3, len(bucket))
+		// 3 results + the copy of the results of the last scheduled run + the
+		// diff
+		require.Equal(5, len(bucket))
 		err = svc.DeleteSearchJob(userCtx, job.ID)
 		require.NoError(err)
 		require.Equal(0, len(bucket))
@@ -259,11 +345,23 @@ func newMockUploadStore(t *testing.T) (*mocks.MockStore, map[string]string) {
 		return nil
 	})
 
+	mockStore.GetFunc.SetDefaultHook(func(ctx context.Context, key string) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		b, ok := bucket[key]
+		if !ok {
+			return nil, errors.Errorf("key %q not found", key)
+		}
+		return io.NopCloser(strings.NewReader(b)), nil
+	})
+
 	mockStore.ListFunc.SetDefaultHook(func(ctx context.Context, prefix string) (*iterator.Iterator[string], error) {
 		var keys []string
 		mu.Lock()
 		for k := range bucket {
-			keys = append(keys, k)
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
 		}
 		mu.Unlock()
 		return iterator.From(keys), nil
@@ -24,6 +24,10 @@ import (
 type config struct {
 	// WorkerInterval sets WorkerOptions.Interval for every worker
 	WorkerInterval time.Duration
+
+	// SchedulerInterval is how often we check for scheduled search jobs
+	// which are due.
+	SchedulerInterval time.Duration
 }
 
 type searchJob struct {
@@ -44,7 +48,8 @@ type searchJob struct {
 func NewSearchJob() job.Job {
 	return &searchJob{
 		config: config{
-			WorkerInterval: 1 * time.Second,
+			WorkerInterval:    1 * time.Second,
+			SchedulerInterval: 1 * time.Minute,
 		},
 	}
 }
@@ -92,6 +97,7 @@ func (j *searchJob) newSearchJobRoutines(
 		newSearcher := newSearcherFactory(observationCtx, db)
 
 		exhaustiveSearchStore := store.New(db, observationCtx)
+		svc := service.New(observationCtx, exhaustiveSearchStore, uploadStore, newSearcher)
 
 		searchWorkerStore := store.NewExhaustiveSearchJobWorkerStore(observationCtx, db.Handle())
 		repoWorkerStore := store.NewRepoSearchJobWorkerStore(observationCtx, db.Handle())
@@ -112,6 +118,7 @@ func (j *searchJob) newSearchJobRoutines(
 			newExhaustiveSearchWorker(workCtx, observationCtx, searchWorkerStore, exhaustiveSearchStore, newSearcher, j.config),
 			newExhaustiveSearchRepoWorker(workCtx, observationCtx, repoWorkerStore, exhaustiveSearchStore, newSearcher, j.config),
 			newExhaustiveSearchRepoRevisionWorker(workCtx, observationCtx, revWorkerStore, exhaustiveSearchStore, newSearcher, uploadStore, j.config),
+			newExhaustiveSearchScheduler(workCtx, observationCtx, svc, j.config),
 
 			// resetters
 			newExhaustiveSearchWorkerResetter(observationCtx, searchWorkerStore),
@@ -19,7 +19,9 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	proto "github.com/sourcegraph/sourcegraph/internal/api/internalapi/v1"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/grpc/defaults"
@@ -94,6 +96,38 @@ func (c *internalClient) Configuration(ctx context.Context) (conftypes.RawUnifie
 	return cfg, err
 }
 
+// DocumentRanks returns the document ranks of repo, as computed by code
+// intelligence ranking.
+func (c *internalClient) DocumentRanks(ctx context.Context, repo api.RepoName) (types.RepoPathRanks, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return types.RepoPathRanks{}, err
+	}
+	u = u.ResolveReference(&url.URL{
+		Path: "/.internal/ranks/" + strings.Trim(string(repo), "/") + "/documents",
+	})
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return types.RepoPathRanks{}, err
+	}
+
+	resp, err := httpcli.InternalDoer.Do(req)
+	if err != nil {
+		return types.RepoPathRanks{}, err
+	}
+	defer resp.Body.Close()
+	if err := checkAPIResponse(resp); err != nil {
+		return types.RepoPathRanks{}, err
+	}
+
+	var ranks types.RepoPathRanks
+	if err := json.NewDecoder(resp.Body).Decode(&ranks); err != nil {
+		return types.RepoPathRanks{}, err
+	}
+	return ranks, nil
+}
+
 // postInternal sends an HTTP post request to the internal route.
 func (c *internalClient) postInternal(ctx context.Context, route string, reqBody, respBody any) error {
 	return c.meteredPost(ctx, "/.internal/"+route, reqBody, respBody)
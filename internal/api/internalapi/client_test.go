@@ -1,10 +1,15 @@
 package internalapi
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/types"
 )
 
 func TestParseAddress(t *testing.T) {
@@ -292,3 +297,32 @@ func TestMustParseInternalURL(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentRanks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.internal/ranks/github.com/foo/bar/documents" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"mean_reference_count":1.5,"paths":{"a.go":2},"definition_paths":["a.go"]}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := &internalClient{URL: srv.URL}
+
+	ranks, err := c.DocumentRanks(context.Background(), "github.com/foo/bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := types.RepoPathRanks{
+		MeanRank:        1.5,
+		Paths:           map[string]float64{"a.go": 2},
+		DefinitionPaths: []string{"a.go"},
+	}
+	if diff := cmp.Diff(want, ranks); diff != "" {
+		t.Errorf("unexpected ranks (-want +got):\n%s", diff)
+	}
+
+	if _, err := c.DocumentRanks(context.Background(), "github.com/foo/missing"); err == nil {
+		t.Error("expected an error for a missing repository")
+	}
+}
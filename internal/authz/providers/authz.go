@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/authz/providers/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/authz/providers/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/authz/providers/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/authz/providers/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/authz/providers/github"
 	"github.com/sourcegraph/sourcegraph/internal/authz/providers/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/authz/providers/perforce"
@@ -63,6 +64,7 @@ func ProvidersFromConfig(
 			extsvc.VariantBitbucketCloud.AsKind(),
 			extsvc.VariantBitbucketServer.AsKind(),
 			extsvc.VariantGerrit.AsKind(),
+			extsvc.KindGitea,
 			extsvc.VariantGitHub.AsKind(),
 			extsvc.VariantGitLab.AsKind(),
 			extsvc.VariantPerforce.AsKind(),
@@ -79,6 +81,7 @@ func ProvidersFromConfig(
 		perforceConns        []*types.PerforceConnection
 		bitbucketCloudConns  []*types.BitbucketCloudConnection
 		gerritConns          []*types.GerritConnection
+		giteaConns           []*types.GiteaConnection
 		azuredevopsConns     []*types.AzureDevOpsConnection
 	)
 	for {
@@ -124,6 +127,11 @@ func ProvidersFromConfig(
 					URN:              svc.URN(),
 					GerritConnection: c,
 				})
+			case *schema.GiteaConnection:
+				giteaConns = append(giteaConns, &types.GiteaConnection{
+					URN:             svc.URN(),
+					GiteaConnection: c,
+				})
 			case *schema.GitHubConnection:
 				gitHubConns = append(gitHubConns,
 					&github.ExternalConnection{
@@ -167,6 +175,7 @@ func ProvidersFromConfig(
 	initResult.Append(perforce.NewAuthzProviders(perforceConns))
 	initResult.Append(bitbucketcloud.NewAuthzProviders(db, bitbucketCloudConns, cfg.SiteConfig().AuthProviders))
 	initResult.Append(gerrit.NewAuthzProviders(gerritConns, cfg.SiteConfig().AuthProviders))
+	initResult.Append(gitea.NewAuthzProviders(giteaConns))
 	initResult.Append(azuredevops.NewAuthzProviders(db, azuredevopsConns, httpcli.ExternalClient))
 
 	return allowAccessByDefault, initResult.Providers, initResult.Problems, initResult.Warnings, initResult.InvalidConnections
@@ -0,0 +1,149 @@
+// Package gitea contains an authorization provider for Gitea and Forgejo.
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Provider is an implementation of AuthzProvider that provides repository
+// permissions as determined from a Gitea instance. It assumes usernames of
+// Sourcegraph accounts match 1-1 with logins of Gitea users, and requires the
+// token of the connection to belong to a site administrator so that it can make
+// requests on behalf of users.
+type Provider struct {
+	urn      string
+	client   gitea.Client
+	codeHost *extsvc.CodeHost
+}
+
+var _ authz.Provider = (*Provider)(nil)
+
+func NewProvider(conn *types.GiteaConnection) (*Provider, error) {
+	baseURL, err := url.Parse(conn.Url)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := gitea.NewClient(conn.URN, baseURL, conn.Token, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newProvider(conn.URN, cli), nil
+}
+
+func newProvider(urn string, cli gitea.Client) *Provider {
+	return &Provider{
+		urn:      urn,
+		client:   cli,
+		codeHost: extsvc.NewCodeHost(cli.GetURL(), extsvc.TypeGitea),
+	}
+}
+
+// FetchAccount looks up the Gitea user with the same username as the given
+// user. It returns nil if there is no such user.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, _ []*extsvc.Account, _ []string) (*extsvc.Account, error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	giteaUser, err := p.client.GetUser(ctx, user.Username)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	accountData, err := json.Marshal(giteaUser)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extsvc.Account{
+		UserID: user.ID,
+		AccountSpec: extsvc.AccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   strconv.FormatInt(giteaUser.ID, 10),
+		},
+		AccountData: extsvc.AccountData{
+			Data: extsvc.NewUnencryptedData(accountData),
+		},
+	}, nil
+}
+
+// FetchUserPerms returns the IDs of the private and internal repositories the
+// given account can read, by listing repositories on behalf of the user.
+//
+// This method may return partial but valid results in case of error, and it is
+// up to callers to decide whether to discard.
+func (p *Provider) FetchUserPerms(ctx context.Context, account *extsvc.Account, opts authz.FetchPermsOptions) (*authz.ExternalUserPermissions, error) {
+	switch {
+	case account == nil:
+		return nil, errors.New("no account provided")
+	case account.Data == nil:
+		return nil, errors.New("no account data provided")
+	case !extsvc.IsHostOfAccount(p.codeHost, account):
+		return nil, errors.Errorf("not a code host of the account: want %q but have %q",
+			p.codeHost.ServiceID, account.AccountSpec.ServiceID)
+	}
+
+	user, err := encryption.DecryptJSON[gitea.User](ctx, account.Data)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshaling account data")
+	}
+
+	extIDs := []extsvc.RepoID{}
+	it := p.client.WithSudo(user.Login).ListRepos(ctx, gitea.ListReposArgs{})
+	for it.Next() {
+		repo := it.Current()
+		// Public repositories are visible to everyone and don't need permissions.
+		if repo.Private || repo.Internal {
+			extIDs = append(extIDs, extsvc.RepoID(strconv.FormatInt(repo.ID, 10)))
+		}
+	}
+
+	return &authz.ExternalUserPermissions{
+		Exacts: extIDs,
+	}, it.Err()
+}
+
+func (p *Provider) FetchRepoPerms(ctx context.Context, repo *extsvc.Repository, opts authz.FetchPermsOptions) ([]extsvc.AccountID, error) {
+	return nil, &authz.ErrUnimplemented{Feature: "gitea.FetchRepoPerms"}
+}
+
+func (p *Provider) ServiceType() string {
+	return p.codeHost.ServiceType
+}
+
+func (p *Provider) ServiceID() string {
+	return p.codeHost.ServiceID
+}
+
+func (p *Provider) URN() string {
+	return p.urn
+}
+
+// ValidateConnection validates that the token of the connection belongs to a
+// site administrator, which is required to fetch permissions on behalf of
+// users.
+func (p *Provider) ValidateConnection(ctx context.Context) error {
+	user, err := p.client.GetAuthenticatedUser(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to get authenticated user")
+	}
+	if !user.IsAdmin {
+		return errors.Newf("Gitea user %q is not a site administrator, which is required to sync permissions", user.Login)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/authz"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+var update = flag.Bool("update", false, "update testdata")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(m.Run())
+}
+
+func newTestProvider(t *testing.T, name string) *Provider {
+	return newProvider("urn", gitea.NewTestClient(t, name, *update))
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	p := newTestProvider(t, "FetchAccount")
+	ctx := context.Background()
+
+	acct, err := p.FetchAccount(ctx, &types.User{ID: 42, Username: "alice"}, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, acct)
+	assert.Equal(t, int32(42), acct.UserID)
+	assert.Equal(t, extsvc.AccountSpec{
+		ServiceType: "gitea",
+		ServiceID:   "https://gitea.sgdev.org/",
+		AccountID:   "2",
+	}, acct.AccountSpec)
+
+	user, err := encryption.DecryptJSON[gitea.User](ctx, acct.Data)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.Login)
+
+	acct, err = p.FetchAccount(ctx, &types.User{ID: 43, Username: "nobody"}, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, acct)
+}
+
+func TestProvider_FetchUserPerms(t *testing.T) {
+	p := newTestProvider(t, "FetchUserPerms")
+	ctx := context.Background()
+
+	t.Run("wrong code host", func(t *testing.T) {
+		_, err := p.FetchUserPerms(ctx, &extsvc.Account{
+			AccountSpec: extsvc.AccountSpec{ServiceType: "gitea", ServiceID: "https://gitea.example.com/"},
+			AccountData: extsvc.AccountData{Data: extsvc.NewUnencryptedData(json.RawMessage(`{}`))},
+		}, authz.FetchPermsOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not a code host of the account")
+	})
+
+	account := &extsvc.Account{
+		AccountSpec: extsvc.AccountSpec{ServiceType: "gitea", ServiceID: "https://gitea.sgdev.org/", AccountID: "2"},
+		AccountData: extsvc.AccountData{Data: extsvc.NewUnencryptedData(json.RawMessage(`{"id":2,"login":"alice"}`))},
+	}
+	perms, err := p.FetchUserPerms(ctx, account, authz.FetchPermsOptions{})
+	require.NoError(t, err)
+	// Only private and internal repositories are returned.
+	assert.Equal(t, []extsvc.RepoID{"102", "103"}, perms.Exacts)
+}
+
+func TestProvider_ValidateConnection(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("admin", func(t *testing.T) {
+		p := newTestProvider(t, "ValidateConnection/admin")
+		require.NoError(t, p.ValidateConnection(ctx))
+	})
+
+	t.Run("non-admin", func(t *testing.T) {
+		p := newTestProvider(t, "ValidateConnection/non-admin")
+		err := p.ValidateConnection(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `Gitea user "alice" is not a site administrator`)
+	})
+}
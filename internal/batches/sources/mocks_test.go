@@ -10934,6 +10934,9 @@ type MockGitserverClient struct {
 	// HeadFunc is an instance of a mock function object controlling the
 	// behavior of the method Head.
 	HeadFunc *GitserverClientHeadFunc
+	// HgChangesetCommitFunc is an instance of a mock function object
+	// controlling the behavior of the method HgChangesetCommit.
+	HgChangesetCommitFunc *GitserverClientHgChangesetCommitFunc
 	// IsPerforcePathCloneableFunc is an instance of a mock function object
 	// controlling the behavior of the method IsPerforcePathCloneable.
 	IsPerforcePathCloneableFunc *GitserverClientIsPerforcePathCloneableFunc
@@ -10982,6 +10985,9 @@ type MockGitserverClient struct {
 	// PerforceUsersFunc is an instance of a mock function object
 	// controlling the behavior of the method PerforceUsers.
 	PerforceUsersFunc *GitserverClientPerforceUsersFunc
+	// ReadBlobsFunc is an instance of a mock function object controlling
+	// the behavior of the method ReadBlobs.
+	ReadBlobsFunc *GitserverClientReadBlobsFunc
 	// ReadDirFunc is an instance of a mock function object controlling the
 	// behavior of the method ReadDir.
 	ReadDirFunc *GitserverClientReadDirFunc
@@ -11122,7 +11128,7 @@ func NewMockGitserverClient() *MockGitserverClient {
 			},
 		},
 		DiffSymbolsFunc: &GitserverClientDiffSymbolsFunc{
-			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) (r0 []byte, r1 error) {
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) (r0 []gitdomain.PathStatus, r1 error) {
 				return
 			},
 		},
@@ -11166,6 +11172,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return
 			},
 		},
+		HgChangesetCommitFunc: &GitserverClientHgChangesetCommitFunc{
+			defaultHook: func(context.Context, api.RepoName, string) (r0 api.CommitID, r1 error) {
+				return
+			},
+		},
 		IsPerforcePathCloneableFunc: &GitserverClientIsPerforcePathCloneableFunc{
 			defaultHook: func(context.Context, protocol.PerforceConnectionDetails, string) (r0 error) {
 				return
@@ -11246,6 +11257,11 @@ func NewMockGitserverClient() *MockGitserverClient {
 				return
 			},
 		},
+		ReadBlobsFunc: &GitserverClientReadBlobsFunc{
+			defaultHook: func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) (r0 error) {
+				return
+			},
+		},
 		ReadDirFunc: &GitserverClientReadDirFunc{
 			defaultHook: func(context.Context, api.RepoName, api.CommitID, string, bool) (r0 []fs.FileInfo, r1 error) {
 				return
@@ -11419,7 +11435,7 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 			},
 		},
 		DiffSymbolsFunc: &GitserverClientDiffSymbolsFunc{
-			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error) {
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error) {
 				panic("unexpected invocation of MockGitserverClient.DiffSymbols")
 			},
 		},
@@ -11463,6 +11479,11 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 				panic("unexpected invocation of MockGitserverClient.Head")
 			},
 		},
+		HgChangesetCommitFunc: &GitserverClientHgChangesetCommitFunc{
+			defaultHook: func(context.Context, api.RepoName, string) (api.CommitID, error) {
+				panic("unexpected invocation of MockGitserverClient.HgChangesetCommit")
+			},
+		},
 		IsPerforcePathCloneableFunc: &GitserverClientIsPerforcePathCloneableFunc{
 			defaultHook: func(context.Context, protocol.PerforceConnectionDetails, string) error {
 				panic("unexpected invocation of MockGitserverClient.IsPerforcePathCloneable")
@@ -11543,6 +11564,11 @@ func NewStrictMockGitserverClient() *MockGitserverClient {
 				panic("unexpected invocation of MockGitserverClient.PerforceUsers")
 			},
 		},
+		ReadBlobsFunc: &GitserverClientReadBlobsFunc{
+			defaultHook: func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+				panic("unexpected invocation of MockGitserverClient.ReadBlobs")
+			},
+		},
 		ReadDirFunc: &GitserverClientReadDirFunc{
 			defaultHook: func(context.Context, api.RepoName, api.CommitID, string, bool) ([]fs.FileInfo, error) {
 				panic("unexpected invocation of MockGitserverClient.ReadDir")
@@ -11709,6 +11735,9 @@ func NewMockGitserverClientFrom(i gitserver.Client) *MockGitserverClient {
 		HeadFunc: &GitserverClientHeadFunc{
 			defaultHook: i.Head,
 		},
+		HgChangesetCommitFunc: &GitserverClientHgChangesetCommitFunc{
+			defaultHook: i.HgChangesetCommit,
+		},
 		IsPerforcePathCloneableFunc: &GitserverClientIsPerforcePathCloneableFunc{
 			defaultHook: i.IsPerforcePathCloneable,
 		},
@@ -11757,6 +11786,9 @@ func NewMockGitserverClientFrom(i gitserver.Client) *MockGitserverClient {
 		PerforceUsersFunc: &GitserverClientPerforceUsersFunc{
 			defaultHook: i.PerforceUsers,
 		},
+		ReadBlobsFunc: &GitserverClientReadBlobsFunc{
+			defaultHook: i.ReadBlobs,
+		},
 		ReadDirFunc: &GitserverClientReadDirFunc{
 			defaultHook: i.ReadDir,
 		},
@@ -13701,15 +13733,15 @@ func (c GitserverClientDiffPathFuncCall) Results() []interface{} {
 // GitserverClientDiffSymbolsFunc describes the behavior when the
 // DiffSymbols method of the parent MockGitserverClient instance is invoked.
 type GitserverClientDiffSymbolsFunc struct {
-	defaultHook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error)
-	hooks       []func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error)
+	defaultHook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error)
+	hooks       []func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error)
 	history     []GitserverClientDiffSymbolsFuncCall
 	mutex       sync.Mutex
 }
 
 // DiffSymbols delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockGitserverClient) DiffSymbols(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 api.CommitID) ([]byte, error) {
+func (m *MockGitserverClient) DiffSymbols(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 api.CommitID) ([]gitdomain.PathStatus, error) {
 	r0, r1 := m.DiffSymbolsFunc.nextHook()(v0, v1, v2, v3)
 	m.DiffSymbolsFunc.appendCall(GitserverClientDiffSymbolsFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
@@ -13718,7 +13750,7 @@ func (m *MockGitserverClient) DiffSymbols(v0 context.Context, v1 api.RepoName, v
 // SetDefaultHook sets function that is called when the DiffSymbols method
 // of the parent MockGitserverClient instance is invoked and the hook queue
 // is empty.
-func (f *GitserverClientDiffSymbolsFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error)) {
+func (f *GitserverClientDiffSymbolsFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error)) {
 	f.defaultHook = hook
 }
 
@@ -13726,7 +13758,7 @@ func (f *GitserverClientDiffSymbolsFunc) SetDefaultHook(hook func(context.Contex
 // DiffSymbols method of the parent MockGitserverClient instance invokes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *GitserverClientDiffSymbolsFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error)) {
+func (f *GitserverClientDiffSymbolsFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -13734,20 +13766,20 @@ func (f *GitserverClientDiffSymbolsFunc) PushHook(hook func(context.Context, api
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *GitserverClientDiffSymbolsFunc) SetDefaultReturn(r0 []byte, r1 error) {
-	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error) {
+func (f *GitserverClientDiffSymbolsFunc) SetDefaultReturn(r0 []gitdomain.PathStatus, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *GitserverClientDiffSymbolsFunc) PushReturn(r0 []byte, r1 error) {
-	f.PushHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error) {
+func (f *GitserverClientDiffSymbolsFunc) PushReturn(r0 []gitdomain.PathStatus, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error) {
 		return r0, r1
 	})
 }
 
-func (f *GitserverClientDiffSymbolsFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error) {
+func (f *GitserverClientDiffSymbolsFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -13794,7 +13826,7 @@ type GitserverClientDiffSymbolsFuncCall struct {
 	Arg3 api.CommitID
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []byte
+	Result0 []gitdomain.PathStatus
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -14718,6 +14750,117 @@ func (c GitserverClientHeadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// GitserverClientHgChangesetCommitFunc describes the behavior when the
+// HgChangesetCommit method of the parent MockGitserverClient instance is invoked.
+type GitserverClientHgChangesetCommitFunc struct {
+	defaultHook func(context.Context, api.RepoName, string) (api.CommitID, error)
+	hooks       []func(context.Context, api.RepoName, string) (api.CommitID, error)
+	history     []GitserverClientHgChangesetCommitFuncCall
+	mutex       sync.Mutex
+}
+
+// HgChangesetCommit delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverClient) HgChangesetCommit(v0 context.Context, v1 api.RepoName, v2 string) (api.CommitID, error) {
+	r0, r1 := m.HgChangesetCommitFunc.nextHook()(v0, v1, v2)
+	m.HgChangesetCommitFunc.appendCall(GitserverClientHgChangesetCommitFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the HgChangesetCommit
+// method of the parent MockGitserverClient instance is invoked and the hook queue is
+// empty.
+func (f *GitserverClientHgChangesetCommitFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string) (api.CommitID, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// HgChangesetCommit method of the parent MockGitserverClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *GitserverClientHgChangesetCommitFunc) PushHook(hook func(context.Context, api.RepoName, string) (api.CommitID, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientHgChangesetCommitFunc) SetDefaultReturn(r0 api.CommitID, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string) (api.CommitID, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientHgChangesetCommitFunc) PushReturn(r0 api.CommitID, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, string) (api.CommitID, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverClientHgChangesetCommitFunc) nextHook() func(context.Context, api.RepoName, string) (api.CommitID, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientHgChangesetCommitFunc) appendCall(r0 GitserverClientHgChangesetCommitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientHgChangesetCommitFuncCall objects
+// describing the invocations of this function.
+func (f *GitserverClientHgChangesetCommitFunc) History() []GitserverClientHgChangesetCommitFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientHgChangesetCommitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientHgChangesetCommitFuncCall is an object that describes an invocation
+// of method HgChangesetCommit on an instance of MockGitserverClient.
+type GitserverClientHgChangesetCommitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 api.CommitID
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientHgChangesetCommitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientHgChangesetCommitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverClientIsPerforcePathCloneableFunc describes the behavior when
 // the IsPerforcePathCloneable method of the parent MockGitserverClient
 // instance is invoked.
@@ -16535,6 +16678,116 @@ func (c GitserverClientPerforceUsersFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverClientReadBlobsFunc describes the behavior when the ReadBlobs method of
+// the parent MockGitserverClient instance is invoked.
+type GitserverClientReadBlobsFunc struct {
+	defaultHook func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error
+	hooks       []func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error
+	history     []GitserverClientReadBlobsFuncCall
+	mutex       sync.Mutex
+}
+
+// ReadBlobs delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockGitserverClient) ReadBlobs(v0 context.Context, v1 api.RepoName, v2 []gitdomain.OID, v3 func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+	r0 := m.ReadBlobsFunc.nextHook()(v0, v1, v2, v3)
+	m.ReadBlobsFunc.appendCall(GitserverClientReadBlobsFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the ReadBlobs method of
+// the parent MockGitserverClient instance is invoked and the hook queue is empty.
+func (f *GitserverClientReadBlobsFunc) SetDefaultHook(hook func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ReadBlobs method of the parent MockGitserverClient instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *GitserverClientReadBlobsFunc) PushHook(hook func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverClientReadBlobsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverClientReadBlobsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+		return r0
+	})
+}
+
+func (f *GitserverClientReadBlobsFunc) nextHook() func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverClientReadBlobsFunc) appendCall(r0 GitserverClientReadBlobsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverClientReadBlobsFuncCall objects describing
+// the invocations of this function.
+func (f *GitserverClientReadBlobsFunc) History() []GitserverClientReadBlobsFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverClientReadBlobsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverClientReadBlobsFuncCall is an object that describes an invocation of
+// method ReadBlobs on an instance of MockGitserverClient.
+type GitserverClientReadBlobsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []gitdomain.OID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 func(oid gitdomain.OID, size int64, r io.Reader) error
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverClientReadBlobsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverClientReadBlobsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // GitserverClientReadDirFunc describes the behavior when the ReadDir method
 // of the parent MockGitserverClient instance is invoked.
 type GitserverClientReadDirFunc struct {
@@ -459,7 +459,8 @@ func loadExternalService(ctx context.Context, s database.ExternalServiceStore, o
 			*schema.BitbucketCloudConnection,
 			*schema.AzureDevOpsConnection,
 			*schema.GerritConnection,
-			*schema.PerforceConnection:
+			*schema.PerforceConnection,
+			*schema.GiteaConnection:
 			return e, nil
 		}
 	}
@@ -485,6 +486,8 @@ func buildChangesetSource(ctx context.Context, tx SourcerStore, cf *httpcli.Fact
 		return NewGerritSource(ctx, externalService, cf)
 	case extsvc.KindPerforce:
 		return NewPerforceSource(ctx, gitserver.NewClient("batches.perforcesource"), externalService, cf)
+	case extsvc.KindGitea:
+		return NewGiteaSource(ctx, externalService, cf)
 	default:
 		return nil, errors.Errorf("unsupported external service type %q", extsvc.KindToType(externalService.Kind))
 	}
@@ -534,6 +537,9 @@ func setOAuthTokenAuth(u *vcs.URL, extSvcType, token string) error {
 	case extsvc.TypeBitbucketServer:
 		return errors.New("require username/token to push commits to BitbucketServer")
 
+	case extsvc.TypeGitea:
+		u.User = url.User(token)
+
 	default:
 		panic(fmt.Sprintf("setOAuthTokenAuth: invalid external service type %q", extSvcType))
 	}
@@ -546,7 +552,7 @@ func setBasicAuth(u *vcs.URL, extSvcType, username, password string) error {
 	switch extSvcType {
 	case extsvc.TypeGitHub, extsvc.TypeGitLab:
 		return errors.New("need token to push commits to " + extSvcType)
-	case extsvc.TypeBitbucketServer, extsvc.TypeBitbucketCloud, extsvc.TypeAzureDevOps, extsvc.TypeGerrit:
+	case extsvc.TypeBitbucketServer, extsvc.TypeBitbucketCloud, extsvc.TypeAzureDevOps, extsvc.TypeGerrit, extsvc.TypeGitea:
 		u.User = url.UserPassword(username, password)
 
 	default:
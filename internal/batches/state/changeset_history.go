@@ -8,6 +8,7 @@ import (
 
 	adobatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/azuredevops"
 	gerritbatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/gerrit"
+	giteabatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/gitea"
 
 	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
@@ -213,7 +214,10 @@ func computeHistory(ch *btypes.Changeset, ce ChangesetEvents) (changesetHistory,
 			btypes.ChangesetEventKindGitLabApproved,
 			btypes.ChangesetEventKindBitbucketCloudApproved,
 			btypes.ChangesetEventKindBitbucketCloudPullRequestApproved,
-			btypes.ChangesetEventKindAzureDevOpsPullRequestApproved:
+			btypes.ChangesetEventKindAzureDevOpsPullRequestApproved,
+			btypes.ChangesetEventKindGiteaApproved,
+			btypes.ChangesetEventKindGiteaChangesRequested,
+			btypes.ChangesetEventKindGiteaReviewDismissed:
 			s, err := e.ReviewState()
 			if err != nil {
 				return nil, err
@@ -347,6 +351,10 @@ func initialExternalState(ch *btypes.Changeset, ce ChangesetEvents) btypes.Chang
 		if m.Change.WorkInProgress {
 			open = false
 		}
+	case *giteabatches.AnnotatedPullRequest:
+		if m.IsWorkInProgress() {
+			open = false
+		}
 	default:
 		return btypes.ChangesetExternalStateOpen
 	}
@@ -10,9 +10,11 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/batches/sources/azuredevops"
 	gerritbatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/gerrit"
+	giteabatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	adobatches "github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/perforce"
 
 	"github.com/sourcegraph/go-diff/diff"
@@ -123,6 +125,8 @@ func computeCheckState(c *btypes.Changeset, events ChangesetEvents) btypes.Chang
 		return computeAzureDevOpsBuildState(m)
 	case *gerritbatches.AnnotatedChange:
 		return computeGerritBuildState(m)
+	case *giteabatches.AnnotatedPullRequest:
+		return computeGiteaBuildState(m)
 	case *perforce.ChangelistState:
 		// Perforce doesn't have builds built-in, its better to be explicit by still
 		// including this case for clarity.
@@ -331,6 +335,36 @@ func computeGerritBuildState(ac *gerritbatches.AnnotatedChange) btypes.Changeset
 	return combineCheckStates(states)
 }
 
+func computeGiteaBuildState(apr *giteabatches.AnnotatedPullRequest) btypes.ChangesetCheckState {
+	// Gitea keeps every status reported for a commit, so only the most recent
+	// status of each context is taken into account.
+	latest := make(map[string]*gitea.CommitStatus)
+	for _, status := range apr.Statuses {
+		if prev, ok := latest[status.Context]; !ok || prev.ID < status.ID {
+			latest[status.Context] = status
+		}
+	}
+
+	states := make([]btypes.ChangesetCheckState, 0, len(latest))
+	for _, status := range latest {
+		states = append(states, parseGiteaBuildState(status.State))
+	}
+	return combineCheckStates(states)
+}
+
+func parseGiteaBuildState(s gitea.CommitStatusState) btypes.ChangesetCheckState {
+	switch s {
+	case gitea.CommitStatusError, gitea.CommitStatusFailure:
+		return btypes.ChangesetCheckStateFailed
+	case gitea.CommitStatusPending:
+		return btypes.ChangesetCheckStatePending
+	case gitea.CommitStatusSuccess, gitea.CommitStatusWarning:
+		return btypes.ChangesetCheckStatePassed
+	default:
+		return btypes.ChangesetCheckStateUnknown
+	}
+}
+
 func parseGerritBuildState(s string) btypes.ChangesetCheckState {
 	switch s {
 	case "-2", "-1":
@@ -638,6 +672,21 @@ func computeSingleChangesetExternalState(c *btypes.Changeset) (s btypes.Changese
 		default:
 			return "", errors.Errorf("unknown Gerrit Change state: %s", m.Change.Status)
 		}
+	case *giteabatches.AnnotatedPullRequest:
+		switch {
+		case m.Merged:
+			s = btypes.ChangesetExternalStateMerged
+		case m.State == gitea.PullRequestStateClosed:
+			s = btypes.ChangesetExternalStateClosed
+		case m.State == gitea.PullRequestStateOpen:
+			if m.IsWorkInProgress() {
+				s = btypes.ChangesetExternalStateDraft
+			} else {
+				s = btypes.ChangesetExternalStateOpen
+			}
+		default:
+			return "", errors.Errorf("unknown Gitea pull request state: %s", m.State)
+		}
 	case *perforce.Changelist:
 		switch m.State {
 		case perforce.ChangelistStateClosed:
@@ -757,6 +806,31 @@ func computeSingleChangesetReviewState(c *btypes.Changeset) (s btypes.ChangesetR
 			}
 
 		}
+	case *giteabatches.AnnotatedPullRequest:
+		// Only the most recent review of each reviewer counts, and comments
+		// neither approve nor request changes.
+		latest := make(map[int64]*gitea.Review)
+		for _, review := range m.Reviews {
+			if review.User == nil || review.Dismissed {
+				continue
+			}
+			switch review.State {
+			case gitea.ReviewStateApproved, gitea.ReviewStateRequestChanges:
+				if prev, ok := latest[review.User.ID]; !ok || prev.ID < review.ID {
+					latest[review.User.ID] = review
+				}
+			}
+		}
+		if len(latest) == 0 {
+			states[btypes.ChangesetReviewStatePending] = true
+		}
+		for _, review := range latest {
+			if review.State == gitea.ReviewStateApproved {
+				states[btypes.ChangesetReviewStateApproved] = true
+			} else {
+				states[btypes.ChangesetReviewStateChangesRequested] = true
+			}
+		}
 	case *perforce.Changelist:
 		states[btypes.ChangesetReviewStatePending] = true
 	default:
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	azuredevops2 "github.com/sourcegraph/sourcegraph/internal/batches/sources/azuredevops"
+	giteabatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/perforce"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -17,6 +18,7 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
@@ -396,6 +398,82 @@ func TestComputeAzureDevOpsBuildStatus(t *testing.T) {
 	}
 }
 
+func TestComputeGiteaBuildStatus(t *testing.T) {
+	t.Parallel()
+
+	pr := &giteabatches.AnnotatedPullRequest{
+		PullRequest: &gitea.PullRequest{},
+	}
+
+	tests := []struct {
+		name     string
+		statuses []*gitea.CommitStatus
+		want     btypes.ChangesetCheckState
+	}{
+		{
+			name:     "empty slice",
+			statuses: []*gitea.CommitStatus{},
+			want:     btypes.ChangesetCheckStateUnknown,
+		},
+		{
+			name: "single success",
+			statuses: []*gitea.CommitStatus{
+				{ID: 1, Context: "ci", State: gitea.CommitStatusSuccess},
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		{
+			name: "single warning",
+			statuses: []*gitea.CommitStatus{
+				{ID: 1, Context: "ci", State: gitea.CommitStatusWarning},
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+		{
+			name: "single pending",
+			statuses: []*gitea.CommitStatus{
+				{ID: 1, Context: "ci", State: gitea.CommitStatusPending},
+			},
+			want: btypes.ChangesetCheckStatePending,
+		},
+		{
+			name: "single error",
+			statuses: []*gitea.CommitStatus{
+				{ID: 1, Context: "ci", State: gitea.CommitStatusError},
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+		{
+			name: "failed + success",
+			statuses: []*gitea.CommitStatus{
+				{ID: 1, Context: "ci", State: gitea.CommitStatusFailure},
+				{ID: 2, Context: "lint", State: gitea.CommitStatusSuccess},
+			},
+			want: btypes.ChangesetCheckStateFailed,
+		},
+		{
+			name: "latest status per context wins",
+			statuses: []*gitea.CommitStatus{
+				{ID: 3, Context: "ci", State: gitea.CommitStatusSuccess},
+				{ID: 1, Context: "ci", State: gitea.CommitStatusFailure},
+				{ID: 2, Context: "lint", State: gitea.CommitStatusPending},
+				{ID: 4, Context: "lint", State: gitea.CommitStatusSuccess},
+			},
+			want: btypes.ChangesetCheckStatePassed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pr.Statuses = tc.statuses
+			have := computeGiteaBuildState(pr)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf(diff)
+			}
+		})
+	}
+}
+
 func TestComputeGitLabCheckState(t *testing.T) {
 	t.Parallel()
 
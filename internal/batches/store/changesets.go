@@ -24,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/batches/search"
 	bbcs "github.com/sourcegraph/sourcegraph/internal/batches/sources/bitbucketcloud"
+	giteabatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/gitea"
 	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -32,6 +33,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -1526,6 +1528,11 @@ func ScanChangeset(t *btypes.Changeset, s dbutil.Scanner) error {
 		m := new(gerritbatches.AnnotatedChange)
 		m.Change = &gerrit.Change{}
 		t.Metadata = m
+	case extsvc.TypeGitea:
+		m := new(giteabatches.AnnotatedPullRequest)
+		// Ensure the inner PR is initialized, it should never be nil.
+		m.PullRequest = &gitea.PullRequest{}
+		t.Metadata = m
 	case extsvc.TypePerforce:
 		t.Metadata = new(perforce.Changelist)
 	case extsvc.TypeGerrit:
@@ -15,11 +15,13 @@ import (
 	adobatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/azuredevops"
 	bbcs "github.com/sourcegraph/sourcegraph/internal/batches/sources/bitbucketcloud"
 	gerritbatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/gerrit"
+	giteabatches "github.com/sourcegraph/sourcegraph/internal/batches/sources/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/azuredevops"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
@@ -468,6 +470,20 @@ func (c *Changeset) SetMetadata(meta any) error {
 		c.ExternalServiceType = extsvc.TypeGerrit
 		c.ExternalBranch = gitdomain.EnsureRefPrefix(pr.Change.Branch)
 		c.ExternalUpdatedAt = pr.Change.Updated
+	case *giteabatches.AnnotatedPullRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(pr.Number, 10)
+		c.ExternalServiceType = extsvc.TypeGitea
+		c.ExternalBranch = gitdomain.EnsureRefPrefix(pr.Head.Ref)
+		c.ExternalUpdatedAt = pr.UpdatedAt
+
+		if pr.Head.RepoID != pr.Base.RepoID && pr.Head.Repository != nil {
+			c.ExternalForkNamespace = pr.Head.Repository.Namespace()
+			c.ExternalForkName = pr.Head.Repository.Name
+		} else {
+			c.ExternalForkNamespace = ""
+			c.ExternalForkName = ""
+		}
 	case *perforce.Changelist:
 		c.Metadata = pr
 		c.ExternalID = pr.ID
@@ -508,6 +524,8 @@ func (c *Changeset) Title() (string, error) {
 		// Remove extra quotes added by the commit message
 		title = strings.TrimPrefix(strings.TrimSuffix(title, "\""), "\"")
 		return title, nil
+	case *giteabatches.AnnotatedPullRequest:
+		return m.Title, nil
 	case *perforce.Changelist:
 		return m.Title, nil
 	default:
@@ -535,6 +553,11 @@ func (c *Changeset) AuthorName() (string, error) {
 		return m.CreatedBy.UniqueName, nil
 	case *gerritbatches.AnnotatedChange:
 		return m.Change.Owner.Name, nil
+	case *giteabatches.AnnotatedPullRequest:
+		if m.User == nil {
+			return "", nil
+		}
+		return m.User.Login, nil
 	case *perforce.Changelist:
 		return m.Author, nil
 	default:
@@ -570,6 +593,13 @@ func (c *Changeset) AuthorEmail() (string, error) {
 		return m.CreatedBy.UniqueName, nil
 	case *gerritbatches.AnnotatedChange:
 		return m.Change.Owner.Email, nil
+	case *giteabatches.AnnotatedPullRequest:
+		// Gitea only returns the e-mail to site administrators and the user
+		// themselves, so this is frequently empty.
+		if m.User == nil {
+			return "", nil
+		}
+		return m.User.Email, nil
 	case *perforce.Changelist:
 		return "", nil
 	default:
@@ -594,6 +624,8 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 		return m.CreationDate
 	case *gerritbatches.AnnotatedChange:
 		return m.Change.Created
+	case *giteabatches.AnnotatedPullRequest:
+		return m.CreatedAt
 	case *perforce.Changelist:
 		return m.CreationDate
 	default:
@@ -617,6 +649,8 @@ func (c *Changeset) Body() (string, error) {
 	case *gerritbatches.AnnotatedChange:
 		// Gerrit doesn't really differentiate between title/description.
 		return m.Change.Subject, nil
+	case *giteabatches.AnnotatedPullRequest:
+		return m.Body, nil
 	case *perforce.Changelist:
 		return "", nil
 	default:
@@ -685,6 +719,8 @@ func (c *Changeset) URL() (s string, err error) {
 		return returnURL.String(), nil
 	case *gerritbatches.AnnotatedChange:
 		return m.CodeHostURL.JoinPath("c", url.PathEscape(m.Change.Project), "+", url.PathEscape(strconv.Itoa(m.Change.ChangeNumber))).String(), nil
+	case *giteabatches.AnnotatedPullRequest:
+		return m.HTMLURL, nil
 	case *perforce.Changelist:
 		return "", nil
 	default:
@@ -905,6 +941,35 @@ func (c *Changeset) Events() (events []*ChangesetEvent, err error) {
 				Metadata:    reviewer,
 			})
 		}
+	case *giteabatches.AnnotatedPullRequest:
+		// There are two types of event that we create from an annotated pull
+		// request: review events, based on the reviews of the pull request,
+		// and check events, based on the commit statuses of its head commit.
+		var kind ChangesetEventKind
+
+		for _, review := range m.Reviews {
+			if kind, err = ChangesetEventKindFor(review); err != nil {
+				return
+			}
+			appendEvent(&ChangesetEvent{
+				ChangesetID: c.ID,
+				Key:         review.Key(),
+				Kind:        kind,
+				Metadata:    review,
+			})
+		}
+
+		for _, status := range m.Statuses {
+			if kind, err = ChangesetEventKindFor(status); err != nil {
+				return
+			}
+			appendEvent(&ChangesetEvent{
+				ChangesetID: c.ID,
+				Key:         status.Key(),
+				Kind:        kind,
+				Metadata:    status,
+			})
+		}
 	case *perforce.Changelist:
 		// We don't have any events we care about right now
 		break
@@ -926,6 +991,8 @@ func (c *Changeset) HeadRefOid() (string, error) {
 		return m.DiffRefs.HeadSHA, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Source.Commit.Hash, nil
+	case *giteabatches.AnnotatedPullRequest:
+		return m.Head.SHA, nil
 	case *adobatches.AnnotatedPullRequest:
 		return "", nil
 	case *gerritbatches.AnnotatedChange:
@@ -949,6 +1016,8 @@ func (c *Changeset) HeadRef() (string, error) {
 		return "refs/heads/" + m.SourceBranch, nil
 	case *bbcs.AnnotatedPullRequest:
 		return "refs/heads/" + m.Source.Branch.Name, nil
+	case *giteabatches.AnnotatedPullRequest:
+		return "refs/heads/" + m.Head.Ref, nil
 	case *adobatches.AnnotatedPullRequest:
 		return m.SourceRefName, nil
 	case *gerritbatches.AnnotatedChange:
@@ -973,6 +1042,8 @@ func (c *Changeset) BaseRefOid() (string, error) {
 		return m.DiffRefs.BaseSHA, nil
 	case *bbcs.AnnotatedPullRequest:
 		return m.Destination.Commit.Hash, nil
+	case *giteabatches.AnnotatedPullRequest:
+		return m.Base.SHA, nil
 	case *adobatches.AnnotatedPullRequest:
 		return "", nil
 	case *gerritbatches.AnnotatedChange:
@@ -996,6 +1067,8 @@ func (c *Changeset) BaseRef() (string, error) {
 		return "refs/heads/" + m.TargetBranch, nil
 	case *bbcs.AnnotatedPullRequest:
 		return "refs/heads/" + m.Destination.Branch.Name, nil
+	case *giteabatches.AnnotatedPullRequest:
+		return "refs/heads/" + m.Base.Ref, nil
 	case *adobatches.AnnotatedPullRequest:
 		return m.TargetRefName, nil
 	case *gerritbatches.AnnotatedChange:
@@ -1119,6 +1192,16 @@ func (c *Changeset) Labels() []ChangesetLabel {
 			labels[i] = ChangesetLabel{Name: l, Color: "000000"}
 		}
 		return labels
+	case *giteabatches.AnnotatedPullRequest:
+		labels := make([]ChangesetLabel, len(m.Labels))
+		for i, l := range m.Labels {
+			labels[i] = ChangesetLabel{
+				Name:        l.Name,
+				Color:       l.Color,
+				Description: l.Description,
+			}
+		}
+		return labels
 	default:
 		return []ChangesetLabel{}
 	}
@@ -1397,6 +1480,20 @@ func ChangesetEventKindFor(e any) (ChangesetEventKind, error) {
 				}
 			}
 		}
+	case *gitea.Review:
+		if e.Dismissed {
+			return ChangesetEventKindGiteaReviewDismissed, nil
+		}
+		switch e.State {
+		case gitea.ReviewStateApproved:
+			return ChangesetEventKindGiteaApproved, nil
+		case gitea.ReviewStateRequestChanges:
+			return ChangesetEventKindGiteaChangesRequested, nil
+		default:
+			return ChangesetEventKindGiteaReviewed, nil
+		}
+	case *gitea.CommitStatus:
+		return ChangesetEventKindGiteaCommitStatus, nil
 	}
 
 	return ChangesetEventKindInvalid, errors.Errorf("changeset eventkindfor unknown changeset event kind for %T", e)
@@ -1546,6 +1643,16 @@ func NewChangesetEventMetadata(k ChangesetEventKind) (any, error) {
 			ChangesetEventKindGerritChangeBuildSucceeded:
 			return new(gerrit.Reviewer), nil
 		}
+	case strings.HasPrefix(string(k), "gitea"):
+		switch k {
+		case ChangesetEventKindGiteaApproved,
+			ChangesetEventKindGiteaChangesRequested,
+			ChangesetEventKindGiteaReviewed,
+			ChangesetEventKindGiteaReviewDismissed:
+			return new(gitea.Review), nil
+		case ChangesetEventKindGiteaCommitStatus:
+			return new(gitea.CommitStatus), nil
+		}
 	}
 	return nil, errors.Errorf("changeset event metadata unknown changeset event kind %q", k)
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	gitlabwebhooks "github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab/webhooks"
@@ -125,6 +126,12 @@ const (
 	ChangesetEventKindGerritChangeBuildFailed             ChangesetEventKind = "gerrit:change:build_failed"
 	ChangesetEventKindGerritChangeBuildPending            ChangesetEventKind = "gerrit:change:build_pending"
 
+	ChangesetEventKindGiteaApproved         ChangesetEventKind = "gitea:approved"
+	ChangesetEventKindGiteaChangesRequested ChangesetEventKind = "gitea:changes_requested"
+	ChangesetEventKindGiteaReviewed         ChangesetEventKind = "gitea:reviewed"
+	ChangesetEventKindGiteaReviewDismissed  ChangesetEventKind = "gitea:review_dismissed"
+	ChangesetEventKindGiteaCommitStatus     ChangesetEventKind = "gitea:commit_status"
+
 	ChangesetEventKindInvalid ChangesetEventKind = "invalid"
 )
 
@@ -202,6 +209,12 @@ func (e *ChangesetEvent) ReviewAuthor() string {
 		return meta.PullRequest.Reviewers[len(meta.PullRequest.Reviewers)-1].UniqueName
 	case *azuredevops.PullRequestUpdatedEvent:
 		return meta.PullRequest.CreatedBy.UniqueName
+
+	case *gitea.Review:
+		if meta.User == nil {
+			return ""
+		}
+		return meta.User.Login
 	default:
 		return ""
 	}
@@ -214,7 +227,8 @@ func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 		ChangesetEventKindGitLabApproved,
 		ChangesetEventKindBitbucketCloudApproved,
 		ChangesetEventKindBitbucketCloudPullRequestApproved,
-		ChangesetEventKindAzureDevOpsPullRequestApproved:
+		ChangesetEventKindAzureDevOpsPullRequestApproved,
+		ChangesetEventKindGiteaApproved:
 		return ChangesetReviewStateApproved, nil
 
 	// BitbucketServer's "REVIEWED" activity is created when someone clicks
@@ -223,7 +237,8 @@ func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 		ChangesetEventKindBitbucketCloudChangesRequested,
 		ChangesetEventKindBitbucketCloudPullRequestChangesRequestCreated,
 		ChangesetEventKindAzureDevOpsPullRequestWaitingForAuthor,
-		ChangesetEventKindAzureDevOpsPullRequestApprovedWithSuggestions:
+		ChangesetEventKindAzureDevOpsPullRequestApprovedWithSuggestions,
+		ChangesetEventKindGiteaChangesRequested:
 		return ChangesetReviewStateChangesRequested, nil
 
 	case ChangesetEventKindGitHubReviewed:
@@ -246,7 +261,8 @@ func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 		ChangesetEventKindGitLabUnapproved,
 		ChangesetEventKindBitbucketCloudPullRequestUnapproved,
 		ChangesetEventKindBitbucketCloudPullRequestChangesRequestRemoved,
-		ChangesetEventKindAzureDevOpsPullRequestRejected:
+		ChangesetEventKindAzureDevOpsPullRequestRejected,
+		ChangesetEventKindGiteaReviewDismissed:
 		return ChangesetReviewStateDismissed, nil
 	default:
 		return ChangesetReviewStatePending, nil
@@ -370,6 +386,10 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		t = ev.CreatedDate
 	case *azuredevops.PullRequestMergedEvent:
 		t = ev.CreatedDate
+	case *gitea.Review:
+		t = ev.SubmittedAt
+	case *gitea.CommitStatus:
+		t = ev.UpdatedAt
 	}
 
 	return t
@@ -913,6 +933,13 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) error {
 	case *azuredevops.PullRequestRejectedEvent:
 		o := o.Metadata.(*azuredevops.PullRequestRejectedEvent)
 		*e = *o
+
+	case *gitea.Review:
+		o := o.Metadata.(*gitea.Review)
+		*e = *o
+	case *gitea.CommitStatus:
+		o := o.Metadata.(*gitea.CommitStatus)
+		*e = *o
 	default:
 		return errors.Errorf("unknown changeset event metadata %T", e)
 	}
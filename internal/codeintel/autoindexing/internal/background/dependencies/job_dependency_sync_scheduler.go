@@ -70,6 +70,9 @@ var schemeToExternalService = map[string]string{
 	dependencies.PythonPackagesScheme: extsvc.KindPythonPackages,
 	dependencies.RustPackagesScheme:   extsvc.KindRustPackages,
 	dependencies.RubyPackagesScheme:   extsvc.KindRubyPackages,
+	dependencies.NuGetPackagesScheme:  extsvc.KindNuGetPackages,
+	dependencies.PHPPackagesScheme:    extsvc.KindPHPPackages,
+	dependencies.HexPackagesScheme:    extsvc.KindHexPackages,
 }
 
 func (h *dependencySyncSchedulerHandler) Handle(ctx context.Context, logger log.Logger, job dependencySyncingJob) error {
@@ -284,6 +287,8 @@ func (h *dependencySyncSchedulerHandler) shouldIndexDependencies(ctx context.Con
 		upload.Indexer == "lsif-typescript" ||
 		upload.Indexer == "scip-python" ||
 		upload.Indexer == "scip-ruby" ||
+		upload.Indexer == "scip-dotnet" ||
+		upload.Indexer == "scip-php" ||
 		upload.Indexer == "rust-analyzer", nil
 }
 
@@ -19,6 +19,9 @@ func InferRepositoryAndRevision(pkg dependencies.MinimialVersionedPackageRepo) (
 		inferRustRepositoryAndRevision,
 		inferPythonRepositoryAndRevision,
 		inferRubyRepositoryAndRevision,
+		inferNuGetRepositoryAndRevision,
+		inferPHPRepositoryAndRevision,
+		inferHexRepositoryAndRevision,
 	} {
 		if repoName, gitTagOrCommit, ok := fn(pkg); ok {
 			return repoName, gitTagOrCommit, true
@@ -99,3 +102,36 @@ func inferRubyRepositoryAndRevision(pkg dependencies.MinimialVersionedPackageRep
 
 	return rubyPkg.RepoName(), pkg.Version, true
 }
+
+func inferNuGetRepositoryAndRevision(pkg dependencies.MinimialVersionedPackageRepo) (api.RepoName, string, bool) {
+	if pkg.Scheme != dependencies.NuGetPackagesScheme {
+		return "", "", false
+	}
+
+	nugetPkg := reposource.NewNuGetVersionedPackage(pkg.Name, pkg.Version)
+	return nugetPkg.RepoName(), nugetPkg.GitTagFromVersion(), true
+}
+
+func inferPHPRepositoryAndRevision(pkg dependencies.MinimialVersionedPackageRepo) (api.RepoName, string, bool) {
+	if pkg.Scheme != dependencies.PHPPackagesScheme {
+		return "", "", false
+	}
+
+	logger := log.Scoped("inferPHPRepositoryAndRevision")
+	phpPkg, err := reposource.ParsePHPPackageFromName(pkg.Name)
+	if err != nil {
+		logger.Error("invalid PHP package name in database", log.Error(err))
+		return "", "", false
+	}
+	phpPkg.Version = pkg.Version
+	return phpPkg.RepoName(), phpPkg.GitTagFromVersion(), true
+}
+
+func inferHexRepositoryAndRevision(pkg dependencies.MinimialVersionedPackageRepo) (api.RepoName, string, bool) {
+	if pkg.Scheme != dependencies.HexPackagesScheme {
+		return "", "", false
+	}
+
+	hexPkg := reposource.NewHexVersionedPackage(pkg.Name, pkg.Version)
+	return hexPkg.RepoName(), hexPkg.GitTagFromVersion(), true
+}
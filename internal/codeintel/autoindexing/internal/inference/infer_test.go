@@ -58,6 +58,33 @@ func TestInferRepositoryAndRevision(t *testing.T) {
 				repoName: "npm/myscope/mypackage",
 				revision: "v1.0.0",
 			},
+			{
+				pkg: dependencies.MinimialVersionedPackageRepo{
+					Scheme:  "scip-dotnet",
+					Name:    "Newtonsoft.Json",
+					Version: "13.0.3",
+				},
+				repoName: "nuget/Newtonsoft.Json",
+				revision: "v13.0.3",
+			},
+			{
+				pkg: dependencies.MinimialVersionedPackageRepo{
+					Scheme:  "scip-php",
+					Name:    "laravel/framework",
+					Version: "v10.0.0",
+				},
+				repoName: "packagist/laravel/framework",
+				revision: "v10.0.0",
+			},
+			{
+				pkg: dependencies.MinimialVersionedPackageRepo{
+					Scheme:  "hex",
+					Name:    "phoenix",
+					Version: "1.7.10",
+				},
+				repoName: "hex/phoenix",
+				revision: "v1.7.10",
+			},
 		}
 
 		for _, testCase := range testCases {
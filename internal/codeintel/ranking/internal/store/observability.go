@@ -11,6 +11,7 @@ type operations struct {
 	summaries                      *observation.Operation
 	getStarRank                    *observation.Operation
 	getDocumentRanks               *observation.Operation
+	getDefinitionPaths             *observation.Operation
 	getReferenceCountStatistics    *observation.Operation
 	coverageCounts                 *observation.Operation
 	lastUpdatedAt                  *observation.Operation
@@ -58,6 +59,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 		summaries:                      op("Summaries"),
 		getStarRank:                    op("GetStarRank"),
 		getDocumentRanks:               op("GetDocumentRanks"),
+		getDefinitionPaths:             op("GetDefinitionPaths"),
 		getReferenceCountStatistics:    op("GetReferenceCountStatistics"),
 		coverageCounts:                 op("CoverageCounts"),
 		lastUpdatedAt:                  op("LastUpdatedAt"),
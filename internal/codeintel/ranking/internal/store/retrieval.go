@@ -83,6 +83,40 @@ WHERE
 	r.blocked IS NULL
 `
 
+// GetDefinitionPaths returns the paths of the given repository which define
+// symbols according to the precise SCIP indexes exported for the last
+// completed ranking graph.
+func (s *store) GetDefinitionPaths(ctx context.Context, repoName api.RepoName) (_ []string, err error) {
+	ctx, _, endObservation := s.operations.getDefinitionPaths.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	return basestore.ScanStrings(s.db.Query(ctx, sqlf.Sprintf(getDefinitionPathsQuery, repoName)))
+}
+
+const getDefinitionPathsQuery = `
+WITH
+last_completed_progress AS (
+	-- Definitions are exported under the root of the derivative graph key
+	SELECT split_part(crp.graph_key, '.', 1) AS graph_key
+	FROM codeintel_ranking_progress crp
+	WHERE crp.reducer_completed_at IS NOT NULL
+	ORDER BY crp.reducer_completed_at DESC
+	LIMIT 1
+)
+SELECT DISTINCT rd.document_path
+FROM codeintel_ranking_definitions rd
+JOIN codeintel_ranking_exports cre ON cre.id = rd.exported_upload_id
+JOIN lsif_uploads u ON u.id = cre.upload_id
+JOIN repo r ON r.id = u.repository_id
+WHERE
+	rd.graph_key IN (SELECT graph_key FROM last_completed_progress) AND
+	cre.deleted_at IS NULL AND
+	r.name = %s AND
+	r.deleted_at IS NULL AND
+	r.blocked IS NULL
+ORDER BY rd.document_path
+`
+
 func (s *store) GetReferenceCountStatistics(ctx context.Context) (logmean float64, err error) {
 	ctx, _, endObservation := s.operations.getReferenceCountStatistics.With(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
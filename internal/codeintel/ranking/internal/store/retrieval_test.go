@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	rankingshared "github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/internal/shared"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/ranking/shared"
+	uploadsshared "github.com/sourcegraph/sourcegraph/internal/codeintel/uploads/shared"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
@@ -122,6 +123,62 @@ func TestDocumentRanks(t *testing.T) {
 	}
 }
 
+func TestGetDefinitionPaths(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	ctx := context.Background()
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	store := New(&observation.TestContext, db)
+
+	key := rankingshared.NewDerivativeGraphKey(mockRankingGraphKey, "123")
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO codeintel_ranking_progress(graph_key, max_export_id, mappers_started_at, reducer_completed_at)
+		VALUES ($1, 1000, NOW(), NOW())
+	`,
+		key,
+	); err != nil {
+		t.Fatalf("failed to insert metadata: %s", err)
+	}
+
+	insertUploads(t, db,
+		uploadsshared.Upload{ID: 1, RepositoryID: 50, RepositoryName: "foo"},
+		uploadsshared.Upload{ID: 2, RepositoryID: 51, RepositoryName: "bar"},
+		uploadsshared.Upload{ID: 3, RepositoryID: 50, RepositoryName: "foo"},
+	)
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO codeintel_ranking_exports (id, upload_id, graph_key, upload_key, deleted_at)
+		VALUES
+			(101, 1, $1, md5('key-1'), NULL),
+			(102, 2, $1, md5('key-2'), NULL),
+			(103, 3, $1, md5('key-3'), NOW())
+	`,
+		mockRankingGraphKey,
+	); err != nil {
+		t.Fatalf("unexpected error inserting exported upload record: %s", err)
+	}
+
+	definitions := make(chan shared.RankingDefinitions, 4)
+	definitions <- shared.RankingDefinitions{ExportedUploadID: 101, SymbolChecksum: hash("foo"), DocumentPath: "foo.go"}
+	definitions <- shared.RankingDefinitions{ExportedUploadID: 101, SymbolChecksum: hash("bar"), DocumentPath: "foo.go"}
+	definitions <- shared.RankingDefinitions{ExportedUploadID: 102, SymbolChecksum: hash("baz"), DocumentPath: "bar.go"}
+	definitions <- shared.RankingDefinitions{ExportedUploadID: 103, SymbolChecksum: hash("old"), DocumentPath: "deleted.go"}
+	close(definitions)
+	if err := store.InsertDefinitionsForRanking(ctx, mockRankingGraphKey, definitions); err != nil {
+		t.Fatalf("unexpected error inserting definitions: %s", err)
+	}
+
+	paths, err := store.GetDefinitionPaths(ctx, api.RepoName("foo"))
+	if err != nil {
+		t.Fatalf("unexpected error getting definition paths: %s", err)
+	}
+	if diff := cmp.Diff([]string{"foo.go"}, paths); diff != "" {
+		t.Errorf("unexpected paths (-want +got):\n%s", diff)
+	}
+}
+
 func TestGetReferenceCountStatistics(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -39,6 +39,9 @@ type MockStore struct {
 	// DerivativeGraphKeyFunc is an instance of a mock function object
 	// controlling the behavior of the method DerivativeGraphKey.
 	DerivativeGraphKeyFunc *StoreDerivativeGraphKeyFunc
+	// GetDefinitionPathsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDefinitionPaths.
+	GetDefinitionPathsFunc *StoreGetDefinitionPathsFunc
 	// GetDocumentRanksFunc is an instance of a mock function object
 	// controlling the behavior of the method GetDocumentRanks.
 	GetDocumentRanksFunc *StoreGetDocumentRanksFunc
@@ -138,6 +141,11 @@ func NewMockStore() *MockStore {
 				return
 			},
 		},
+		GetDefinitionPathsFunc: &StoreGetDefinitionPathsFunc{
+			defaultHook: func(context.Context, api.RepoName) (r0 []string, r1 error) {
+				return
+			},
+		},
 		GetDocumentRanksFunc: &StoreGetDocumentRanksFunc{
 			defaultHook: func(context.Context, api.RepoName) (r0 map[string]float64, r1 bool, r2 error) {
 				return
@@ -270,6 +278,11 @@ func NewStrictMockStore() *MockStore {
 				panic("unexpected invocation of MockStore.DerivativeGraphKey")
 			},
 		},
+		GetDefinitionPathsFunc: &StoreGetDefinitionPathsFunc{
+			defaultHook: func(context.Context, api.RepoName) ([]string, error) {
+				panic("unexpected invocation of MockStore.GetDefinitionPaths")
+			},
+		},
 		GetDocumentRanksFunc: &StoreGetDocumentRanksFunc{
 			defaultHook: func(context.Context, api.RepoName) (map[string]float64, bool, error) {
 				panic("unexpected invocation of MockStore.GetDocumentRanks")
@@ -392,6 +405,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		DerivativeGraphKeyFunc: &StoreDerivativeGraphKeyFunc{
 			defaultHook: i.DerivativeGraphKey,
 		},
+		GetDefinitionPathsFunc: &StoreGetDefinitionPathsFunc{
+			defaultHook: i.GetDefinitionPaths,
+		},
 		GetDocumentRanksFunc: &StoreGetDocumentRanksFunc{
 			defaultHook: i.GetDocumentRanks,
 		},
@@ -986,6 +1002,114 @@ func (c StoreDerivativeGraphKeyFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
 
+// StoreGetDefinitionPathsFunc describes the behavior when the
+// GetDefinitionPaths method of the parent MockStore instance is invoked.
+type StoreGetDefinitionPathsFunc struct {
+	defaultHook func(context.Context, api.RepoName) ([]string, error)
+	hooks       []func(context.Context, api.RepoName) ([]string, error)
+	history     []StoreGetDefinitionPathsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDefinitionPaths delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) GetDefinitionPaths(v0 context.Context, v1 api.RepoName) ([]string, error) {
+	r0, r1 := m.GetDefinitionPathsFunc.nextHook()(v0, v1)
+	m.GetDefinitionPathsFunc.appendCall(StoreGetDefinitionPathsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetDefinitionPaths
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreGetDefinitionPathsFunc) SetDefaultHook(hook func(context.Context, api.RepoName) ([]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDefinitionPaths method of the parent MockStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreGetDefinitionPathsFunc) PushHook(hook func(context.Context, api.RepoName) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *StoreGetDefinitionPathsFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *StoreGetDefinitionPathsFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreGetDefinitionPathsFunc) nextHook() func(context.Context, api.RepoName) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreGetDefinitionPathsFunc) appendCall(r0 StoreGetDefinitionPathsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreGetDefinitionPathsFuncCall objects
+// describing the invocations of this function.
+func (f *StoreGetDefinitionPathsFunc) History() []StoreGetDefinitionPathsFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreGetDefinitionPathsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreGetDefinitionPathsFuncCall is an object that describes an invocation
+// of method GetDefinitionPaths on an instance of MockStore.
+type StoreGetDefinitionPathsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetDefinitionPathsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetDefinitionPathsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreGetDocumentRanksFunc describes the behavior when the
 // GetDocumentRanks method of the parent MockStore instance is invoked.
 type StoreGetDocumentRanksFunc struct {
@@ -88,7 +88,8 @@ func squashRange(j float64) float64 {
 	return j / (1 + j)
 }
 
-// GetDocumentRank returns a map from paths within the given repo to their reference count.
+// GetDocumentRank returns a map from paths within the given repo to their reference count,
+// along with the paths which define symbols according to precise SCIP data.
 func (s *Service) GetDocumentRanks(ctx context.Context, repoName api.RepoName) (_ types.RepoPathRanks, err error) {
 	_, _, endObservation := s.operations.getDocumentRanks.With(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
@@ -115,9 +116,15 @@ func (s *Service) GetDocumentRanks(ctx context.Context, repoName api.RepoName) (
 		}
 	}
 
+	definitionPaths, err := s.store.GetDefinitionPaths(ctx, repoName)
+	if err != nil {
+		return types.RepoPathRanks{}, err
+	}
+
 	return types.RepoPathRanks{
-		MeanRank: logmean,
-		Paths:    paths,
+		MeanRank:        logmean,
+		Paths:           paths,
+		DefinitionPaths: definitionPaths,
 	}, nil
 }
 
@@ -5,6 +5,9 @@ import (
 	"math"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/schema"
@@ -62,6 +65,30 @@ func TestGetRepoRankWithUserBoostedScores(t *testing.T) {
 	}
 }
 
+func TestGetDocumentRanks(t *testing.T) {
+	ctx := context.Background()
+	mockStore := NewMockStore()
+	svc := newService(&observation.TestContext, mockStore, nil, conf.DefaultClient())
+
+	mockStore.GetDocumentRanksFunc.SetDefaultReturn(map[string]float64{"a.go": 4, "b.go": 0}, true, nil)
+	mockStore.GetReferenceCountStatisticsFunc.SetDefaultReturn(1.5, nil)
+	mockStore.GetDefinitionPathsFunc.SetDefaultReturn([]string{"a.go"}, nil)
+
+	ranks, err := svc.GetDocumentRanks(ctx, "foo")
+	if err != nil {
+		t.Fatalf("unexpected error getting document ranks: %s", err)
+	}
+
+	expected := types.RepoPathRanks{
+		MeanRank:        1.5,
+		Paths:           map[string]float64{"a.go": 2, "b.go": 0},
+		DefinitionPaths: []string{"a.go"},
+	}
+	if diff := cmp.Diff(expected, ranks); diff != "" {
+		t.Errorf("unexpected ranks (-want +got):\n%s", diff)
+	}
+}
+
 const epsilon = 0.00000001
 
 func cmpFloat(x, y float64) bool {
@@ -10,4 +10,9 @@ type RepoPathRanks struct {
 	// equal log_2({number of references to file} + 1), where references are considered
 	// over all repositories.
 	Paths map[string]float64 `json:"paths"`
+
+	// DefinitionPaths are the paths which define symbols according to precise
+	// code intelligence data. Other paths only reference symbols or weren't
+	// indexed precisely.
+	DefinitionPaths []string `json:"definition_paths,omitempty"`
 }
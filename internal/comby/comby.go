@@ -102,6 +102,17 @@ func toOutput(b []byte) (Result, error) {
 }
 
 func Run(ctx context.Context, logger log.Logger, args Args, unmarshal unmarshaller) (results []Result, err error) {
+	if args.UseNative() {
+		_, err := RunNative(ctx, args, func(r Result) error {
+			results = append(results, r)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return results, nil
+	}
+
 	cmd, stdin, stdout, stderr, err := SetupCmdWithPipes(ctx, args)
 	if err != nil {
 		return nil, err
@@ -10,6 +10,10 @@ import (
 
 // Comby is not supported on Windows
 
+func Exists() bool {
+	return false
+}
+
 func Outputs(ctx context.Context, args Args) (string, error) {
 	return "", errors.New("Comby is not supported on Windows")
 }
@@ -0,0 +1,24 @@
+package comby
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+var defaultEngine = Engine(env.Get("SRC_STRUCTURAL_SEARCH_ENGINE", string(EngineAuto), "Structural search matcher: comby, native or auto (comby if it is installed, native otherwise)."))
+
+// UseNative returns true if args should be run with the native matcher
+// instead of the comby executable.
+func (args Args) UseNative() bool {
+	engine := args.Engine
+	if engine == "" {
+		engine = defaultEngine
+	}
+	switch engine {
+	case EngineComby:
+		return false
+	case EngineNative:
+		return true
+	default:
+		return !Exists()
+	}
+}
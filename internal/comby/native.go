@@ -0,0 +1,548 @@
+package comby
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxNativeMatchSteps bounds the work the native matcher does per start
+// offset, so that templates with many adjacent holes can't run for too long.
+const maxNativeMatchSteps = 1_000_000
+
+// maxNativeMatchDuration bounds the time the native matcher spends on a
+// single file. Large files can exceed it even if the work per start offset is
+// small.
+const maxNativeMatchDuration = 10 * time.Second
+
+var errTooManySteps = errors.New("the structural pattern is too expensive to match, try making it more specific")
+
+type termKind int
+
+const (
+	termLiteral termKind = iota
+	// termSpace matches whitespace. Whitespace in a template matches any
+	// amount of whitespace in the source.
+	termSpace
+	termHole
+)
+
+type holeKind int
+
+const (
+	// holeAnything is :[x] or ..., which lazily matches balanced text.
+	holeAnything holeKind = iota
+	// holeAlphanum is :[[x]], which matches a word.
+	holeAlphanum
+	// holePunctuation is :[x.], which matches text without whitespace.
+	holePunctuation
+	// holeLine is :[x\n], which matches the rest of a line.
+	holeLine
+	// holeSpace is :[ x], which matches whitespace except newlines.
+	holeSpace
+	// holeRegexp is :[x~re], which matches a regular expression.
+	holeRegexp
+)
+
+type term struct {
+	kind termKind
+
+	// literal is the text of a termLiteral.
+	literal string
+
+	// optional is set for termSpace between punctuation, like in `f( x )`,
+	// where the source does not need to contain whitespace.
+	optional bool
+
+	hole holeKind
+	// name is the name of a hole. Holes with the same name must match the
+	// same text, except for the empty name and "_".
+	name string
+	re   *regexp.Regexp
+}
+
+var (
+	holeAlphanumPattern    = lazyregexp.New(`^:\[\[(\w+)\]\]$`)
+	holeRegexpPattern      = lazyregexp.New(`^:\[(\w*)~(.*)\]$`)
+	holeAnythingPattern    = lazyregexp.New(`^:\[(\w+)\]$`)
+	holePunctuationPattern = lazyregexp.New(`^:\[(\w+)\.\]$`)
+	holeLinePattern        = lazyregexp.New(`^:\[(\w+)\\n\]$`)
+	holeSpacePattern       = lazyregexp.New(`^:\[ +(\w*)\]$`)
+)
+
+// parseNativeTemplate parses a match template into terms.
+func parseNativeTemplate(template string) ([]term, error) {
+	var terms []term
+	for _, t := range parseTemplate([]byte(template)) {
+		switch t := t.(type) {
+		case Literal:
+			terms = appendLiteralTerms(terms, string(t))
+		case Hole:
+			h, err := parseHole(string(t))
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, h)
+		}
+	}
+
+	// Leading and trailing whitespace in the template is not significant.
+	for len(terms) > 0 && terms[0].kind == termSpace {
+		terms = terms[1:]
+	}
+	for len(terms) > 0 && terms[len(terms)-1].kind == termSpace {
+		terms = terms[:len(terms)-1]
+	}
+
+	for i := range terms {
+		if terms[i].kind != termSpace {
+			continue
+		}
+		terms[i].optional = isPunctuationTerm(terms[i-1], true) || isPunctuationTerm(terms[i+1], false)
+	}
+	return terms, nil
+}
+
+// appendLiteralTerms splits a literal part of a template into literal,
+// whitespace and ... terms.
+func appendLiteralTerms(terms []term, s string) []term {
+	for len(s) > 0 {
+		if strings.HasPrefix(s, "...") {
+			terms = append(terms, term{kind: termHole, hole: holeAnything})
+			s = s[3:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		if unicode.IsSpace(r) {
+			end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+			if end < 0 {
+				end = len(s)
+			}
+			terms = append(terms, term{kind: termSpace})
+			s = s[end:]
+			continue
+		}
+		end := size
+		for end < len(s) && !strings.HasPrefix(s[end:], "...") {
+			r, size := utf8.DecodeRuneInString(s[end:])
+			if unicode.IsSpace(r) {
+				break
+			}
+			end += size
+		}
+		terms = append(terms, term{kind: termLiteral, literal: s[:end]})
+		s = s[end:]
+	}
+	return terms
+}
+
+func parseHole(s string) (term, error) {
+	h := term{kind: termHole}
+	if m := holeAlphanumPattern.FindStringSubmatch(s); m != nil {
+		h.hole, h.name = holeAlphanum, m[1]
+	} else if m := holeRegexpPattern.FindStringSubmatch(s); m != nil {
+		re, err := regexp.Compile(`\A(?:` + m[2] + `)`)
+		if err != nil {
+			return term{}, errors.Wrapf(err, "invalid regular expression in hole %s", s)
+		}
+		h.hole, h.name, h.re = holeRegexp, m[1], re
+	} else if m := holeAnythingPattern.FindStringSubmatch(s); m != nil {
+		h.hole, h.name = holeAnything, m[1]
+	} else if m := holePunctuationPattern.FindStringSubmatch(s); m != nil {
+		h.hole, h.name = holePunctuation, m[1]
+	} else if m := holeLinePattern.FindStringSubmatch(s); m != nil {
+		h.hole, h.name = holeLine, m[1]
+	} else if m := holeSpacePattern.FindStringSubmatch(s); m != nil {
+		h.hole, h.name = holeSpace, m[1]
+	} else {
+		return term{}, errors.Errorf("invalid hole %s", s)
+	}
+	return h, nil
+}
+
+// isPunctuationTerm returns true if t ends (or starts, if end is false) with
+// a character which is not part of a word.
+func isPunctuationTerm(t term, end bool) bool {
+	if t.kind != termLiteral {
+		return false
+	}
+	var r rune
+	if end {
+		r, _ = utf8.DecodeLastRuneInString(t.literal)
+	} else {
+		r, _ = utf8.DecodeRuneInString(t.literal)
+	}
+	return !isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isOpening(c byte) bool { return c == '(' || c == '[' || c == '{' }
+func isClosing(c byte) bool { return c == ')' || c == ']' || c == '}' }
+
+// source is a file prepared for matching.
+type source struct {
+	buf []byte
+
+	// skipTo is the end of the comment or string starting at an offset, or
+	// 0 if none starts there.
+	skipTo []int32
+	// closing is the offset of the closing delimiter for an opening
+	// delimiter, or -1 if it is not balanced.
+	closing []int32
+	// inert is true for offsets inside comments and strings.
+	inert []bool
+
+	lineStarts []int
+}
+
+func newSource(buf []byte, syntax *nativeSyntax) *source {
+	s := &source{
+		buf:        buf,
+		skipTo:     make([]int32, len(buf)),
+		closing:    make([]int32, len(buf)),
+		inert:      make([]bool, len(buf)),
+		lineStarts: []int{0},
+	}
+	var open []int
+	for i := 0; i < len(buf); {
+		if end, ok := syntax.skip(buf, i); ok {
+			s.skipTo[i] = int32(end)
+			for j := i + 1; j < end; j++ {
+				s.inert[j] = true
+			}
+			i = end
+			continue
+		}
+		c := buf[i]
+		switch {
+		case isOpening(c):
+			s.closing[i] = -1
+			open = append(open, i)
+		case isClosing(c):
+			if len(open) > 0 && delimitersMatch(buf[open[len(open)-1]], c) {
+				s.closing[open[len(open)-1]] = int32(i)
+				open = open[:len(open)-1]
+			}
+		}
+		i++
+	}
+	for i, c := range buf {
+		if c == '\n' {
+			s.lineStarts = append(s.lineStarts, i+1)
+		}
+	}
+	return s
+}
+
+func delimitersMatch(open, close byte) bool {
+	switch open {
+	case '(':
+		return close == ')'
+	case '[':
+		return close == ']'
+	case '{':
+		return close == '}'
+	}
+	return false
+}
+
+// location converts an offset to a Location with 1-based lines and columns,
+// like comby reports them.
+func (s *source) location(offset int) Location {
+	line := 0
+	lo, hi := 0, len(s.lineStarts)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if s.lineStarts[mid] <= offset {
+			line = mid
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return Location{
+		Offset: offset,
+		Line:   line + 1,
+		Column: utf8.RuneCount(s.buf[s.lineStarts[line]:offset]) + 1,
+	}
+}
+
+// next returns the end of the balanced unit starting at i: a comment, a
+// string, a delimited group or a single rune. It returns false if no unit
+// starts at i, because i is at a closing or unbalanced delimiter.
+func (s *source) next(i int) (int, bool) {
+	if end := s.skipTo[i]; end > 0 {
+		return int(end), true
+	}
+	c := s.buf[i]
+	if isOpening(c) {
+		if end := s.closing[i]; end >= 0 {
+			return int(end) + 1, true
+		}
+		return 0, false
+	}
+	if isClosing(c) {
+		return 0, false
+	}
+	_, size := utf8.DecodeRune(s.buf[i:])
+	return i + size, true
+}
+
+type binding struct {
+	name  string
+	value string
+}
+
+// nativeMatch is a match of a template in a source.
+type nativeMatch struct {
+	start, end int
+	env        []binding
+}
+
+type matcher struct {
+	terms []term
+	rule  *nativeRule
+	src   *source
+	env   []binding
+
+	// steps is the number of steps taken for the current start offset, and
+	// totalSteps the number of steps taken for the source.
+	steps      int
+	totalSteps int
+	deadline   time.Time
+}
+
+// matches returns the non-overlapping matches of the template in src. It
+// returns errTooManySteps if matching at a single start offset takes more than
+// maxNativeMatchSteps steps, or matching the source takes longer than
+// maxNativeMatchDuration.
+func (m *matcher) matches() ([]nativeMatch, error) {
+	if len(m.terms) == 0 {
+		// comby matches every file with an empty template.
+		return []nativeMatch{{}}, nil
+	}
+	m.deadline = time.Now().Add(maxNativeMatchDuration)
+
+	var matches []nativeMatch
+	for start := 0; start < len(m.src.buf); {
+		if m.src.inert[start] {
+			start++
+			continue
+		}
+		m.env = m.env[:0]
+		m.steps = 0
+		end, ok, err := m.match(0, start)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			_, size := utf8.DecodeRune(m.src.buf[start:])
+			start += size
+			continue
+		}
+		matches = append(matches, nativeMatch{
+			start: start,
+			end:   end,
+			env:   append([]binding(nil), m.env...),
+		})
+		if end > start {
+			start = end
+		} else {
+			_, size := utf8.DecodeRune(m.src.buf[start:])
+			start += size
+		}
+	}
+	return matches, nil
+}
+
+// match matches the terms starting at index k at offset pos of the source. It
+// returns the end of the match.
+func (m *matcher) match(k, pos int) (int, bool, error) {
+	m.steps++
+	if m.steps > maxNativeMatchSteps {
+		return 0, false, errTooManySteps
+	}
+	// Checking the clock is expensive compared to a step.
+	m.totalSteps++
+	if m.totalSteps%4096 == 0 && time.Now().After(m.deadline) {
+		return 0, false, errTooManySteps
+	}
+
+	if k == len(m.terms) {
+		if m.rule != nil && !m.rule.eval(m.env) {
+			return 0, false, nil
+		}
+		return pos, true, nil
+	}
+
+	buf := m.src.buf
+	t := m.terms[k]
+	switch t.kind {
+	case termLiteral:
+		if end := pos + len(t.literal); end > len(buf) || string(buf[pos:end]) != t.literal {
+			return 0, false, nil
+		}
+		return m.match(k+1, pos+len(t.literal))
+
+	case termSpace:
+		end := pos
+		for end < len(buf) && isSpace(buf[end]) {
+			end++
+		}
+		if end == pos && !t.optional {
+			return 0, false, nil
+		}
+		return m.match(k+1, end)
+	}
+
+	// try binds the hole to buf[pos:end] and matches the remaining terms.
+	try := func(end int) (int, bool, error) {
+		value := string(buf[pos:end])
+		n := len(m.env)
+		if t.name != "" && t.name != "_" {
+			if bound, ok := lookup(m.env, t.name); ok {
+				if bound != value {
+					return 0, false, nil
+				}
+			} else {
+				m.env = append(m.env, binding{name: t.name, value: value})
+			}
+		}
+		matchEnd, ok, err := m.match(k+1, end)
+		if !ok {
+			m.env = m.env[:n]
+		}
+		return matchEnd, ok, err
+	}
+
+	switch t.hole {
+	case holeAlphanum:
+		end := pos
+		for end < len(buf) {
+			r, size := utf8.DecodeRune(buf[end:])
+			if !isWordRune(r) {
+				break
+			}
+			end += size
+		}
+		if end == pos {
+			return 0, false, nil
+		}
+		return try(end)
+
+	case holeSpace:
+		end := pos
+		for end < len(buf) && (buf[end] == ' ' || buf[end] == '\t') {
+			end++
+		}
+		if end == pos {
+			return 0, false, nil
+		}
+		return try(end)
+
+	case holeLine:
+		end := len(buf)
+		if i := bytes.IndexByte(buf[pos:], '\n'); i >= 0 {
+			end = pos + i + 1
+		}
+		return try(end)
+
+	case holeRegexp:
+		loc := t.re.FindIndex(buf[pos:])
+		if loc == nil {
+			return 0, false, nil
+		}
+		return try(pos + loc[1])
+
+	case holePunctuation:
+		for end := pos; end < len(buf); {
+			c := buf[end]
+			if isSpace(c) || isOpening(c) || isClosing(c) {
+				break
+			}
+			_, size := utf8.DecodeRune(buf[end:])
+			end += size
+			if matchEnd, ok, err := try(end); ok || err != nil {
+				return matchEnd, ok, err
+			}
+		}
+		return 0, false, nil
+	}
+
+	// holeAnything matches balanced text. If the hole is the last term of
+	// the template it matches to the end of the line or group, otherwise it
+	// matches as little as possible.
+	if k == len(m.terms)-1 {
+		end := pos
+		for end < len(buf) && buf[end] != '\n' {
+			next, ok := m.src.next(end)
+			if !ok {
+				break
+			}
+			end = next
+		}
+		return try(end)
+	}
+	for end := pos; ; {
+		if matchEnd, ok, err := try(end); ok || err != nil {
+			return matchEnd, ok, err
+		}
+		if end == len(buf) {
+			return 0, false, nil
+		}
+		next, ok := m.src.next(end)
+		if !ok {
+			return 0, false, nil
+		}
+		end = next
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func lookup(env []binding, name string) (string, bool) {
+	for _, b := range env {
+		if b.name == name {
+			return b.value, true
+		}
+	}
+	return "", false
+}
+
+// substitute replaces the holes in a rewrite template with the text they
+// matched. Holes which did not match anything are kept as they are.
+func substitute(rewrite []Term, env []binding) string {
+	var b strings.Builder
+	for _, t := range rewrite {
+		if h, ok := t.(Hole); ok {
+			if name, ok := holeName(string(h)); ok {
+				if value, ok := lookup(env, name); ok {
+					b.WriteString(value)
+					continue
+				}
+			}
+		}
+		b.WriteString(t.String())
+	}
+	return b.String()
+}
+
+func holeName(s string) (string, bool) {
+	h, err := parseHole(s)
+	if err != nil {
+		return "", false
+	}
+	return h.name, true
+}
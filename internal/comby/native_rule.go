@@ -0,0 +1,113 @@
+package comby
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// nativeRule is the subset of comby rules the native matcher supports: a
+// comma-separated list of comparisons of holes and strings, like
+//
+//	where :[x] == "foo", :[y] != :[x]
+type nativeRule struct {
+	comparisons []comparison
+}
+
+type comparison struct {
+	left, right operand
+	equal       bool
+}
+
+// operand is either a hole or a string.
+type operand struct {
+	hole  string
+	value string
+}
+
+func parseNativeRule(rule string) (*nativeRule, error) {
+	rule = strings.TrimSpace(rule)
+	if rule == "" {
+		return nil, nil
+	}
+	rest, ok := strings.CutPrefix(rule, "where")
+	if !ok {
+		return nil, errors.Errorf("unsupported rule %q: rules must start with 'where'", rule)
+	}
+
+	r := &nativeRule{}
+	for {
+		var c comparison
+		var err error
+		if c.left, rest, err = parseOperand(rest); err != nil {
+			return nil, errors.Wrapf(err, "unsupported rule %q", rule)
+		}
+		rest = strings.TrimSpace(rest)
+		switch {
+		case strings.HasPrefix(rest, "=="):
+			c.equal = true
+		case strings.HasPrefix(rest, "!="):
+		default:
+			return nil, errors.Errorf("unsupported rule %q: only == and != are supported", rule)
+		}
+		if c.right, rest, err = parseOperand(rest[2:]); err != nil {
+			return nil, errors.Wrapf(err, "unsupported rule %q", rule)
+		}
+		r.comparisons = append(r.comparisons, c)
+
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return r, nil
+		}
+		if rest, ok = strings.CutPrefix(rest, ","); !ok {
+			return nil, errors.Errorf("unsupported rule %q: expected ',' before %q", rule, rest)
+		}
+	}
+}
+
+func parseOperand(s string) (operand, string, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, ":[["):
+		end := strings.Index(s, "]]")
+		if end < 0 {
+			return operand{}, "", errors.New("unterminated hole")
+		}
+		return operand{hole: s[3:end]}, s[end+2:], nil
+	case strings.HasPrefix(s, ":["):
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return operand{}, "", errors.New("unterminated hole")
+		}
+		return operand{hole: s[2:end]}, s[end+1:], nil
+	case strings.HasPrefix(s, `"`):
+		prefix, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return operand{}, "", errors.Wrap(err, "invalid string")
+		}
+		value, err := strconv.Unquote(prefix)
+		if err != nil {
+			return operand{}, "", errors.Wrap(err, "invalid string")
+		}
+		return operand{value: value}, s[len(prefix):], nil
+	}
+	return operand{}, "", errors.Errorf("expected a hole or a string at %q", s)
+}
+
+func (r *nativeRule) eval(env []binding) bool {
+	for _, c := range r.comparisons {
+		if (c.left.eval(env) == c.right.eval(env)) != c.equal {
+			return false
+		}
+	}
+	return true
+}
+
+func (o operand) eval(env []binding) string {
+	if o.hole == "" {
+		return o.value
+	}
+	value, _ := lookup(env, o.hole)
+	return value
+}
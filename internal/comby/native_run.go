@@ -0,0 +1,250 @@
+package comby
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// RunNative runs args with the in-process matcher instead of the comby
+// executable, and calls onResult with each result as soon as a file is
+// matched. The results have the same types as the results of Run.
+//
+// The native matcher supports the holes of comby templates and balances
+// delimiters, comments and strings for the language of args.Matcher. Rules
+// are limited to comparing holes and strings, and Diff results are not
+// supported.
+//
+// Files of a zip, directory or tar input which are too expensive to match are
+// skipped and listed in the returned stats instead of failing the run.
+func RunNative(ctx context.Context, args Args, onResult func(Result) error) (NativeStats, error) {
+	r, err := newNativeRunner(args, onResult)
+	if err != nil {
+		return NativeStats{}, err
+	}
+	err = r.run(ctx)
+	return r.stats, err
+}
+
+// NativeStats describes the files RunNative didn't match.
+type NativeStats struct {
+	// SkippedFiles are the files which were skipped because matching the
+	// template against them was too expensive.
+	SkippedFiles []string
+}
+
+func newNativeRunner(args Args, onResult func(Result) error) (*nativeRunner, error) {
+	if args.ResultKind == Diff {
+		return nil, errors.New("the native structural matcher does not support diffs")
+	}
+	terms, err := parseNativeTemplate(args.MatchTemplate)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := parseNativeRule(args.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nativeRunner{
+		args:     args,
+		terms:    terms,
+		rule:     rule,
+		rewrite:  parseTemplate([]byte(args.RewriteTemplate)),
+		onResult: onResult,
+	}, nil
+}
+
+func (r *nativeRunner) run(ctx context.Context) error {
+	switch input := r.args.Input.(type) {
+	case FileContent:
+		// comby returns a replacement for stdin even if nothing matched.
+		return r.file("", input, true)
+
+	case ZipPath:
+		zr, err := zip.OpenReader(string(input))
+		if err != nil {
+			return errors.Wrap(err, "open zip")
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if f.FileInfo().IsDir() || !r.include(f.Name) {
+				continue
+			}
+			content, err := readZipFile(f)
+			if err != nil {
+				return err
+			}
+			if err := r.file(f.Name, content, false); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case DirPath:
+		return filepath.WalkDir(string(input), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if d.IsDir() || !r.include(path) {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return r.file(path, content, false)
+		})
+
+	case Tar:
+		for event := range input.TarInputEventC {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !r.include(event.Header.Name) {
+				continue
+			}
+			if err := r.file(event.Header.Name, event.Content, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.Errorf("unsupported input %T", r.args.Input)
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, errors.Wrapf(err, "open %s", f.Name)
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+type nativeRunner struct {
+	args     Args
+	terms    []term
+	rule     *nativeRule
+	rewrite  []Term
+	onResult func(Result) error
+	stats    NativeStats
+}
+
+// include returns true if path matches args.FilePatterns, which comby
+// interprets as suffixes.
+func (r *nativeRunner) include(path string) bool {
+	if len(r.args.FilePatterns) == 0 {
+		return true
+	}
+	for _, suffix := range r.args.FilePatterns {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *nativeRunner) file(uri string, content []byte, always bool) error {
+	src := newSource(content, syntaxFor(r.args.Matcher, uri))
+	m := &matcher{terms: r.terms, rule: r.rule, src: src}
+	matches, err := m.matches()
+	// A single file passed as content can't be skipped, there is nothing
+	// else to search.
+	if errors.Is(err, errTooManySteps) && !always {
+		r.stats.SkippedFiles = append(r.stats.SkippedFiles, uri)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, uri)
+	}
+	if len(matches) == 0 && !always {
+		return nil
+	}
+
+	switch r.args.ResultKind {
+	case MatchOnly:
+		if _, ok := r.args.Input.(Tar); ok {
+			return r.onResult(&FileMatchWithChunks{URI: uri, ChunkMatches: chunkMatches(src, matches)})
+		}
+		fm := &FileMatch{URI: uri, Matches: make([]Match, 0, len(matches))}
+		for _, match := range matches {
+			fm.Matches = append(fm.Matches, Match{
+				Range:   Range{Start: src.location(match.start), End: src.location(match.end)},
+				Matched: string(content[match.start:match.end]),
+			})
+		}
+		return r.onResult(fm)
+
+	case Replacement:
+		var b strings.Builder
+		last := 0
+		for _, match := range matches {
+			b.Write(content[last:match.start])
+			b.WriteString(substitute(r.rewrite, match.env))
+			last = match.end
+		}
+		b.Write(content[last:])
+		return r.onResult(&FileReplacement{URI: uri, Content: b.String()})
+
+	case NewlineSeparatedOutput:
+		for _, match := range matches {
+			if err := r.onResult(&Output{Value: []byte(substitute(r.rewrite, match.env))}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.Errorf("unsupported result kind %d", r.args.ResultKind)
+}
+
+// chunkMatches groups matches into chunks of the lines they span, like
+// comby's -chunk-matches 0.
+func chunkMatches(src *source, matches []nativeMatch) []ChunkMatch {
+	var chunks []ChunkMatch
+	chunkEnd := -1
+	for _, match := range matches {
+		start, end := src.location(match.start), src.location(match.end)
+		r := Range{Start: start, End: end}
+		if len(chunks) > 0 && match.start <= chunkEnd {
+			last := &chunks[len(chunks)-1]
+			last.Ranges = append(last.Ranges, r)
+			if newEnd := lineEnd(src.buf, match.end); newEnd > chunkEnd {
+				last.Content = string(src.buf[last.Start.Offset:newEnd])
+				chunkEnd = newEnd
+			}
+			continue
+		}
+
+		lineStart := src.lineStarts[start.Line-1]
+		chunkEnd = lineEnd(src.buf, match.end)
+		chunks = append(chunks, ChunkMatch{
+			Content: string(src.buf[lineStart:chunkEnd]),
+			Start:   src.location(lineStart),
+			Ranges:  []Range{r},
+		})
+	}
+	return chunks
+}
+
+// lineEnd returns the offset of the end of the line containing offset,
+// excluding the newline.
+func lineEnd(buf []byte, offset int) int {
+	if i := bytes.IndexByte(buf[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(buf)
+}
@@ -0,0 +1,154 @@
+package comby
+
+import (
+	"bytes"
+	"path/filepath"
+)
+
+// nativeSyntax describes the comments and string literals of a language. The
+// native matcher does not match templates inside comments and strings, and
+// ignores delimiters in them when balancing holes.
+type nativeSyntax struct {
+	lineComments  []string
+	blockComments [][2]string
+	strings       []stringSyntax
+}
+
+type stringSyntax struct {
+	quote byte
+	// multiline strings may contain newlines, other strings end at the end
+	// of the line. An unterminated string is not treated as a string.
+	multiline bool
+	// raw strings do not have backslash escapes.
+	raw bool
+}
+
+var (
+	doubleQuoted = stringSyntax{quote: '"'}
+	singleQuoted = stringSyntax{quote: '\''}
+	backQuoted   = stringSyntax{quote: '`', multiline: true}
+
+	cStyleComments = nativeSyntax{
+		lineComments:  []string{"//"},
+		blockComments: [][2]string{{"/*", "*/"}},
+	}
+
+	genericSyntax = &nativeSyntax{strings: []stringSyntax{doubleQuoted}}
+	cSyntax       = cStyleComments.withStrings(doubleQuoted, singleQuoted)
+	goSyntax      = cStyleComments.withStrings(doubleQuoted, singleQuoted, stringSyntax{quote: '`', multiline: true, raw: true})
+	jsSyntax      = cStyleComments.withStrings(doubleQuoted, singleQuoted, backQuoted)
+	// Rust uses single quotes for lifetimes too, so they are not strings.
+	rustSyntax   = cStyleComments.withStrings(doubleQuoted)
+	hashSyntax   = &nativeSyntax{lineComments: []string{"#"}, strings: []stringSyntax{doubleQuoted, singleQuoted}}
+	sqlSyntax    = &nativeSyntax{lineComments: []string{"--"}, blockComments: [][2]string{{"/*", "*/"}}, strings: []stringSyntax{singleQuoted, doubleQuoted}}
+	haskSyntax   = &nativeSyntax{lineComments: []string{"--"}, blockComments: [][2]string{{"{-", "-}"}}, strings: []stringSyntax{doubleQuoted}}
+	mlSyntax     = &nativeSyntax{blockComments: [][2]string{{"(*", "*)"}}, strings: []stringSyntax{doubleQuoted}}
+	fsharpSyntax = &nativeSyntax{lineComments: []string{"//"}, blockComments: [][2]string{{"(*", "*)"}}, strings: []stringSyntax{doubleQuoted}}
+	pascalSyntax = &nativeSyntax{lineComments: []string{"//"}, blockComments: [][2]string{{"(*", "*)"}}, strings: []stringSyntax{singleQuoted}}
+	lispSyntax   = &nativeSyntax{lineComments: []string{";"}, strings: []stringSyntax{doubleQuoted}}
+	erlangSyntax = &nativeSyntax{lineComments: []string{"%"}, strings: []stringSyntax{doubleQuoted}}
+	texSyntax    = &nativeSyntax{lineComments: []string{"%"}}
+	markupSyntax = &nativeSyntax{blockComments: [][2]string{{"<!--", "-->"}}, strings: []stringSyntax{doubleQuoted, singleQuoted}}
+	cssSyntax    = &nativeSyntax{blockComments: [][2]string{{"/*", "*/"}}, strings: []stringSyntax{doubleQuoted, singleQuoted}}
+	jsonSyntax   = &nativeSyntax{strings: []stringSyntax{doubleQuoted}}
+	fortSyntax   = &nativeSyntax{lineComments: []string{"!"}, strings: []stringSyntax{doubleQuoted, singleQuoted}}
+)
+
+func (s nativeSyntax) withStrings(strings ...stringSyntax) *nativeSyntax {
+	s.strings = strings
+	return &s
+}
+
+// nativeSyntaxes maps the matchers accepted by comby to a syntax. Matchers
+// which are not in this map use genericSyntax.
+var nativeSyntaxes = map[string]*nativeSyntax{
+	".c":     cSyntax,
+	".cs":    cSyntax,
+	".dart":  cSyntax,
+	".java":  cSyntax,
+	".kt":    cSyntax,
+	".php":   cSyntax,
+	".scala": cSyntax,
+	".swift": cSyntax,
+	".re":    cSyntax,
+	".go":    goSyntax,
+	".js":    jsSyntax,
+	".ts":    jsSyntax,
+	".rs":    rustSyntax,
+	".ex":    hashSyntax,
+	".jl":    hashSyntax,
+	".nim":   hashSyntax,
+	".py":    hashSyntax,
+	".rb":    hashSyntax,
+	".sh":    hashSyntax,
+	".sql":   sqlSyntax,
+	".elm":   haskSyntax,
+	".hs":    haskSyntax,
+	".ml":    mlSyntax,
+	".fsx":   fsharpSyntax,
+	".pas":   pascalSyntax,
+	".clj":   lispSyntax,
+	".lisp":  lispSyntax,
+	".erl":   erlangSyntax,
+	".tex":   texSyntax,
+	".html":  markupSyntax,
+	".xml":   markupSyntax,
+	".css":   cssSyntax,
+	".json":  jsonSyntax,
+	".f":     fortSyntax,
+}
+
+// syntaxFor returns the syntax for a matcher like ".go". If matcher is empty,
+// the syntax is inferred from the extension of path like comby does.
+func syntaxFor(matcher, path string) *nativeSyntax {
+	if matcher == "" {
+		matcher = filepath.Ext(path)
+	}
+	if s, ok := nativeSyntaxes[matcher]; ok {
+		return s
+	}
+	return genericSyntax
+}
+
+// skip returns the end of the comment or string literal starting at offset i
+// of buf, if any.
+func (s *nativeSyntax) skip(buf []byte, i int) (int, bool) {
+	rest := buf[i:]
+	for _, c := range s.blockComments {
+		if bytes.HasPrefix(rest, []byte(c[0])) {
+			if end := bytes.Index(rest[len(c[0]):], []byte(c[1])); end >= 0 {
+				return i + len(c[0]) + end + len(c[1]), true
+			}
+			return len(buf), true
+		}
+	}
+	for _, c := range s.lineComments {
+		if bytes.HasPrefix(rest, []byte(c)) {
+			if end := bytes.IndexByte(rest, '\n'); end >= 0 {
+				return i + end, true
+			}
+			return len(buf), true
+		}
+	}
+	for _, str := range s.strings {
+		if rest[0] != str.quote {
+			continue
+		}
+		for j := 1; j < len(rest); j++ {
+			switch rest[j] {
+			case '\\':
+				if !str.raw {
+					j++
+				}
+			case '\n':
+				if !str.multiline {
+					return 0, false
+				}
+			case str.quote:
+				return i + j + 1, true
+			}
+		}
+		return 0, false
+	}
+	return 0, false
+}
@@ -0,0 +1,275 @@
+package comby
+
+import (
+	"archive/tar"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+)
+
+func TestRunNative(t *testing.T) {
+	run := func(args Args) []Result {
+		t.Helper()
+		var results []Result
+		_, err := RunNative(context.Background(), args, func(r Result) error {
+			results = append(results, r)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+
+	// matches returns the matched text of template in content, separated
+	// by "|".
+	matches := func(template, matcher, content string) string {
+		t.Helper()
+		var matched []string
+		for _, r := range run(Args{Input: FileContent(content), MatchTemplate: template, Matcher: matcher}) {
+			for _, m := range r.(*FileMatch).Matches {
+				matched = append(matched, m.Matched)
+			}
+		}
+		return strings.Join(matched, "|")
+	}
+
+	// outputs returns the rewrite template for each match of template in
+	// content, separated by "|".
+	outputs := func(template, rewrite, content string) string {
+		t.Helper()
+		var values []string
+		for _, r := range run(Args{Input: FileContent(content), MatchTemplate: template, RewriteTemplate: rewrite, Matcher: ".go", ResultKind: NewlineSeparatedOutput}) {
+			values = append(values, string(r.(*Output).Value))
+		}
+		return strings.Join(values, "|")
+	}
+
+	t.Run("holes", func(t *testing.T) {
+		autogold.Expect("foo(a, b(c, d))").Equal(t, matches("foo(:[args])", ".go", "x := foo(a, b(c, d)) + 1"))
+		autogold.Expect("foo(a, b(c, d))").Equal(t, matches("foo(...)", ".go", "x := foo(a, b(c, d)) + 1"))
+		autogold.Expect("a / b(c, d), e").Equal(t, outputs("foo(:[x], :[y])", ":[x] / :[y]", "foo(a, b(c, d), e)"))
+		autogold.Expect("foo|bar").Equal(t, outputs("func :[[name]]", ":[name]", "func foo() {}\nfunc bar() {}"))
+		autogold.Expect("x.y.z").Equal(t, outputs("= :[x.];", ":[x]", "a = x.y.z;"))
+		autogold.Expect("12").Equal(t, outputs(`:[n~\d+]`, ":[n]", "a12b"))
+		autogold.Expect("x + 1").Equal(t, outputs("return :[x]", ":[x]", "return x + 1\n}"))
+	})
+
+	t.Run("whitespace", func(t *testing.T) {
+		autogold.Expect("if   x  {").Equal(t, matches("if x {", ".go", "if   x  {"))
+		autogold.Expect("foo(a)").Equal(t, matches("foo( :[x] )", ".go", "foo(a)"))
+		autogold.Expect("").Equal(t, matches("if x {", ".go", "ifx {"))
+	})
+
+	t.Run("balanced delimiters", func(t *testing.T) {
+		autogold.Expect("{ a { b } }").Equal(t, matches("{:[body]}", ".go", "{ a { b } }"))
+		autogold.Expect("(a)").Equal(t, matches("(:[x])", ".go", "(a)(b"))
+		autogold.Expect(`foo(")")`).Equal(t, matches("foo(:[x])", ".go", `foo(")")`))
+	})
+
+	t.Run("comments and strings", func(t *testing.T) {
+		content := "// foo(comment)\nx := \"foo(string)\"\n/* foo(block) */ foo(code)"
+		autogold.Expect("foo(code)").Equal(t, matches("foo(:[x])", ".go", content))
+		autogold.Expect("foo(comment)|foo(string)|foo(block)|foo(code)").Equal(t, matches("foo(:[x])", ".generic", strings.ReplaceAll(content, `"`, "")))
+		autogold.Expect("foo(code)").Equal(t, matches("foo(:[x])", ".py", "# foo(comment)\nfoo(code)"))
+	})
+
+	t.Run("repeated holes", func(t *testing.T) {
+		autogold.Expect("a == a").Equal(t, matches(":[[x]] == :[[x]]", ".go", "a == b; a == a"))
+	})
+
+	t.Run("rules", func(t *testing.T) {
+		test := func(rule string) string {
+			var matched []string
+			for _, r := range run(Args{Input: FileContent("f(a) f(b) f(c)"), MatchTemplate: "f(:[x])", Rule: rule}) {
+				for _, m := range r.(*FileMatch).Matches {
+					matched = append(matched, m.Matched)
+				}
+			}
+			return strings.Join(matched, "|")
+		}
+		autogold.Expect("f(b)").Equal(t, test(`where :[x] == "b"`))
+		autogold.Expect("f(a)|f(c)").Equal(t, test(`where :[x] != "b"`))
+		autogold.Expect("f(c)").Equal(t, test(`where :[x] != "a", "b" != :[x]`))
+	})
+
+	t.Run("replacements", func(t *testing.T) {
+		results := run(Args{
+			Input:           FileContent("foo(bar, baz) + foo(1, 2)"),
+			MatchTemplate:   "foo(:[x], :[y])",
+			RewriteTemplate: "foo(:[y], :[x])",
+			ResultKind:      Replacement,
+		})
+		autogold.Expect("foo(baz, bar) + foo(2, 1)").Equal(t, results[0].(*FileReplacement).Content)
+	})
+
+	t.Run("outputs", func(t *testing.T) {
+		var outputs []string
+		for _, r := range run(Args{
+			Input:           FileContent("train(intercity, regional). train(lightrail, commuter)"),
+			MatchTemplate:   "train(:[x], :[y])",
+			RewriteTemplate: ":[y]->:[x]",
+			ResultKind:      NewlineSeparatedOutput,
+		}) {
+			outputs = append(outputs, string(r.(*Output).Value))
+		}
+		autogold.Expect([]string{"regional->intercity", "commuter->lightrail"}).Equal(t, outputs)
+	})
+
+	t.Run("tar chunks", func(t *testing.T) {
+		c := make(chan TarInputEvent, 2)
+		c <- TarInputEvent{Header: tar.Header{Name: "a.go"}, Content: []byte("x\nf(a) f(b)\nf(\nc)\n")}
+		c <- TarInputEvent{Header: tar.Header{Name: "b.txt"}, Content: []byte("f(d)")}
+		close(c)
+		results := run(Args{Input: Tar{TarInputEventC: c}, MatchTemplate: "f(:[x])", FilePatterns: []string{".go"}})
+		autogold.Expect([]Result{&FileMatchWithChunks{
+			URI: "a.go",
+			ChunkMatches: []ChunkMatch{
+				{
+					Content: "f(a) f(b)",
+					Start: Location{
+						Offset: 2,
+						Line:   2,
+						Column: 1,
+					},
+					Ranges: []Range{
+						{
+							Start: Location{
+								Offset: 2,
+								Line:   2,
+								Column: 1,
+							},
+							End: Location{
+								Offset: 6,
+								Line:   2,
+								Column: 5,
+							},
+						},
+						{
+							Start: Location{
+								Offset: 7,
+								Line:   2,
+								Column: 6,
+							},
+							End: Location{
+								Offset: 11,
+								Line:   2,
+								Column: 10,
+							},
+						},
+					},
+				},
+				{
+					Content: "f(\nc)",
+					Start: Location{
+						Offset: 12,
+						Line:   3,
+						Column: 1,
+					},
+					Ranges: []Range{{
+						Start: Location{
+							Offset: 12,
+							Line:   3,
+							Column: 1,
+						},
+						End: Location{
+							Offset: 17,
+							Line:   4,
+							Column: 3,
+						},
+					}},
+				},
+			},
+		}}).Equal(t, results)
+	})
+}
+
+func TestRunNative_Errors(t *testing.T) {
+	test := func(args Args) string {
+		_, err := RunNative(context.Background(), args, func(Result) error { return nil })
+		if err == nil {
+			return "<nil>"
+		}
+		return err.Error()
+	}
+
+	autogold.Expect("invalid hole :[x y]").Equal(t, test(Args{Input: FileContent("x"), MatchTemplate: ":[x y]"}))
+	autogold.Expect(`unsupported rule "where match :[x] { }": expected a hole or a string at "match :[x] { }"`).Equal(t, test(Args{Input: FileContent("x"), MatchTemplate: ":[x]", Rule: "where match :[x] { }"}))
+	autogold.Expect("the native structural matcher does not support diffs").Equal(t, test(Args{Input: FileContent("x"), MatchTemplate: "x", ResultKind: Diff}))
+	autogold.Expect("the structural pattern is too expensive to match, try making it more specific").Equal(t, test(Args{
+		Input:         FileContent(strings.Repeat("a ", 2000)),
+		MatchTemplate: ":[a] :[b] :[c] b",
+	}))
+}
+
+func TestRunNative_LargeFiles(t *testing.T) {
+	// The budget applies to each start offset, so large files can be matched
+	// with templates which are cheap to match at each offset.
+	for _, tc := range []struct {
+		template string
+		line     string
+		size     int
+	}{
+		{template: "foo(:[b])", line: "\tfoo(x, bar(y))\n", size: 1_200_000},
+		{template: ":[a] == nil", line: "\tif err == nil {\n", size: 15_000},
+	} {
+		t.Run(tc.template, func(t *testing.T) {
+			n := tc.size / len(tc.line)
+			content := "func f() {\n" + strings.Repeat(tc.line, n) + "}\n"
+
+			var matches int
+			stats, err := RunNative(context.Background(), Args{
+				Input:         FileContent(content),
+				MatchTemplate: tc.template,
+				Matcher:       ".go",
+			}, func(r Result) error {
+				matches += len(r.(*FileMatch).Matches)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if matches != n {
+				t.Errorf("got %d matches, want %d", matches, n)
+			}
+			if len(stats.SkippedFiles) > 0 {
+				t.Errorf("unexpected skipped files %v", stats.SkippedFiles)
+			}
+		})
+	}
+}
+
+func TestRunNative_SkipsExpensiveFiles(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"cheap.txt":     "a a a b",
+		"expensive.txt": strings.Repeat("a ", 2000),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var matched []string
+	stats, err := RunNative(context.Background(), Args{
+		Input:         DirPath(dir),
+		MatchTemplate: ":[a] :[b] :[c] b",
+	}, func(r Result) error {
+		matched = append(matched, filepath.Base(r.(*FileMatch).URI))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cheap.txt"}; !reflect.DeepEqual(matched, want) {
+		t.Errorf("got matches in %v, want %v", matched, want)
+	}
+	if want := []string{filepath.Join(dir, "expensive.txt")}; !reflect.DeepEqual(stats.SkippedFiles, want) {
+		t.Errorf("got skipped files %v, want %v", stats.SkippedFiles, want)
+	}
+}
@@ -61,8 +61,25 @@ type Args struct {
 
 	// NumWorkers is the number of worker processes to fork in parallel
 	NumWorkers int
+
+	// Engine selects the matcher which runs the search. The zero value uses
+	// the engine configured with SRC_STRUCTURAL_SEARCH_ENGINE.
+	Engine Engine
 }
 
+// Engine is the implementation of structural matching.
+type Engine string
+
+const (
+	// EngineAuto uses the comby executable if it is installed, and the native
+	// matcher otherwise.
+	EngineAuto Engine = "auto"
+	// EngineComby runs the comby executable.
+	EngineComby Engine = "comby"
+	// EngineNative runs the in-process matcher, see RunNative.
+	EngineNative Engine = "native"
+)
+
 // Location is the location in a file
 type Location struct {
 	Offset int `json:"offset"`
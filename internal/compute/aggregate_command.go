@@ -0,0 +1,118 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+)
+
+// Aggregate counts the matches of a query grouped by By, which is one of
+// the aggregateBy* values or a capture group like $1 of SearchPattern.
+type Aggregate struct {
+	By            string
+	SearchPattern MatchPattern
+}
+
+const (
+	aggregateByRepo   = "repo"
+	aggregateByPath   = "path"
+	aggregateByAuthor = "author"
+	aggregateByDate   = "date"
+)
+
+func (c *Aggregate) ToSearchPattern() string {
+	if c.SearchPattern == nil {
+		return ""
+	}
+	// Delimit the pattern so that the search parses it as a single regexp,
+	// even if it contains spaces or quotes.
+	return query.Delimit(c.SearchPattern.String(), '/')
+}
+
+func (c *Aggregate) String() string {
+	if c.SearchPattern == nil {
+		return fmt.Sprintf("Aggregate: count by %s", c.By)
+	}
+	return fmt.Sprintf("Aggregate: count by %s where (%s)", c.By, c.SearchPattern.String())
+}
+
+// group returns the group of the match r, or an empty string if r does not
+// belong to a group.
+func (c *Aggregate) group(r result.Match) string {
+	switch c.By {
+	case aggregateByRepo:
+		return string(r.RepoName().Name)
+	case aggregateByPath:
+		switch m := r.(type) {
+		case *result.FileMatch:
+			return m.Path
+		case *result.CommitDiffMatch:
+			return m.Path()
+		}
+	case aggregateByAuthor:
+		switch m := r.(type) {
+		case *result.CommitMatch:
+			return m.Commit.Author.Name
+		case *result.CommitDiffMatch:
+			return m.Commit.Author.Name
+		}
+	case aggregateByDate:
+		switch m := r.(type) {
+		case *result.CommitMatch:
+			return m.Commit.Author.Date.UTC().Format("2006-01-02")
+		case *result.CommitDiffMatch:
+			return m.Commit.Author.Date.UTC().Format("2006-01-02")
+		}
+	}
+	return ""
+}
+
+// captureGroups counts the values of the capture group c.By in the content
+// of r.
+func (c *Aggregate) captureGroups(r result.Match) map[string]int {
+	re := c.SearchPattern.(*Regexp).Value
+	counts := map[string]int{}
+	for _, content := range resultChunks(r, "", false) {
+		for _, submatches := range re.FindAllStringSubmatchIndex(content, -1) {
+			value := string(re.ExpandString(nil, c.By, content, submatches))
+			if value == "" {
+				continue
+			}
+			if len(value) > maxGroupLength {
+				value = value[:maxGroupLength]
+			}
+			counts[value]++
+		}
+	}
+	return counts
+}
+
+// maxGroupLength truncates long capture group values, which are unlikely to
+// be useful groups.
+const maxGroupLength = 100
+
+func (c *Aggregate) Run(_ context.Context, _ gitserver.Client, r result.Match) (Result, error) {
+	var counts map[string]int
+	if isCaptureGroup(c.By) {
+		counts = c.captureGroups(r)
+	} else if group := c.group(r); group != "" {
+		counts = map[string]int{group: r.ResultCount()}
+	}
+	if len(counts) == 0 {
+		return nil, nil
+	}
+
+	a := &Aggregation{Kind: "aggregate", Groups: make([]Group, 0, len(counts))}
+	for value, count := range counts {
+		a.Groups = append(a.Groups, Group{Value: value, Count: count})
+	}
+	a.sort()
+	return a, nil
+}
+
+func isCaptureGroup(by string) bool {
+	return len(by) > 1 && by[0] == '$'
+}
@@ -0,0 +1,74 @@
+package compute
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/regexp"
+	"github.com/hexops/autogold/v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestAggregate(t *testing.T) {
+	test := func(cmd *Aggregate, m result.Match) []Group {
+		r, err := cmd.Run(context.Background(), gitserver.NewMockClient(), m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r == nil {
+			return nil
+		}
+		return r.(*Aggregation).Groups
+	}
+
+	importPattern := &Regexp{Value: regexp.MustCompile(`import "(\S+)"`)}
+	autogold.Expect([]Group{{Value: "fmt", Count: 2}, {Value: "os", Count: 1}}).
+		Equal(t, test(&Aggregate{By: "$1", SearchPattern: importPattern}, fileMatch(`import "fmt"`, `import "os"`, `import "fmt"`)))
+
+	autogold.Expect([]Group{{Value: "my/awesome/repo", Count: 3}}).
+		Equal(t, test(&Aggregate{By: "repo"}, fileMatch("a", "b", "c")))
+
+	autogold.Expect([]Group{{Value: "my/awesome/path.ml", Count: 1}}).
+		Equal(t, test(&Aggregate{By: "path"}, fileMatch("a")))
+
+	commit := &result.CommitMatch{
+		Repo: types.MinimalRepo{Name: "my/awesome/repo"},
+		Commit: gitdomain.Commit{
+			Author: gitdomain.Signature{Name: "bob", Date: time.Date(2023, 12, 24, 23, 0, 0, 0, time.UTC)},
+		},
+	}
+	autogold.Expect([]Group{{Value: "bob", Count: 1}}).Equal(t, test(&Aggregate{By: "author"}, commit))
+	autogold.Expect([]Group{{Value: "2023-12-24", Count: 1}}).Equal(t, test(&Aggregate{By: "date"}, commit))
+
+	// File matches have no author.
+	autogold.Expect([]Group(nil)).Equal(t, test(&Aggregate{By: "author"}, fileMatch("a")))
+}
+
+func TestAggregator(t *testing.T) {
+	a := NewAggregator()
+	a.Add(&Aggregation{Groups: []Group{{Value: "a", Count: 1}, {Value: "b", Count: 2}}})
+	a.Add(&Text{Value: "ignored"})
+	a.Add(&Aggregation{Groups: []Group{{Value: "a", Count: 3}, {Value: "c", Count: 1}}})
+	autogold.Expect(true).Equal(t, a.Dirty())
+
+	autogold.Expect(&Aggregation{
+		Kind: "aggregate",
+		Groups: []Group{
+			{
+				Value: "a",
+				Count: 4,
+			},
+			{
+				Value: "b",
+				Count: 2,
+			},
+		},
+		OtherCount: 1,
+	}).Equal(t, a.Totals(2))
+	autogold.Expect(false).Equal(t, a.Dirty())
+}
@@ -0,0 +1,73 @@
+package compute
+
+import "sort"
+
+// Aggregation is a list of groups with the number of matches in each group,
+// ordered by descending count.
+type Aggregation struct {
+	Kind   string  `json:"kind"`
+	Groups []Group `json:"groups"`
+	// OtherCount is the number of matches in groups left out of Groups.
+	OtherCount int `json:"otherCount,omitempty"`
+}
+
+type Group struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+func (a *Aggregation) sort() {
+	sort.Slice(a.Groups, func(i, j int) bool {
+		if a.Groups[i].Count != a.Groups[j].Count {
+			return a.Groups[i].Count > a.Groups[j].Count
+		}
+		return a.Groups[i].Value < a.Groups[j].Value
+	})
+}
+
+// Aggregator sums the aggregations of an aggregate command over all matches
+// of a query.
+type Aggregator struct {
+	counts map[string]int
+	dirty  bool
+}
+
+func NewAggregator() *Aggregator {
+	return &Aggregator{counts: map[string]int{}}
+}
+
+// Add adds the counts of a to the totals. Results which are not
+// aggregations are ignored.
+func (a *Aggregator) Add(r Result) {
+	aggregation, ok := r.(*Aggregation)
+	if !ok {
+		return
+	}
+	for _, g := range aggregation.Groups {
+		a.counts[g.Value] += g.Count
+	}
+	a.dirty = true
+}
+
+// Dirty returns true if counts were added since the last call to Totals.
+func (a *Aggregator) Dirty() bool {
+	return a.dirty
+}
+
+// Totals returns the current totals of at most limit groups with the
+// highest counts. The counts of other groups are summed in OtherCount.
+func (a *Aggregator) Totals(limit int) *Aggregation {
+	a.dirty = false
+	totals := &Aggregation{Kind: "aggregate", Groups: make([]Group, 0, len(a.counts))}
+	for value, count := range a.counts {
+		totals.Groups = append(totals.Groups, Group{Value: value, Count: count})
+	}
+	totals.sort()
+	if len(totals.Groups) > limit {
+		for _, g := range totals.Groups[limit:] {
+			totals.OtherCount += g.Count
+		}
+		totals.Groups = totals.Groups[:limit]
+	}
+	return totals
+}
@@ -22,8 +22,10 @@ var (
 	_ Command = (*MatchOnly)(nil)
 	_ Command = (*Replace)(nil)
 	_ Command = (*Output)(nil)
+	_ Command = (*Aggregate)(nil)
 )
 
 func (MatchOnly) command() {}
 func (Replace) command()   {}
 func (Output) command()    {}
+func (Aggregate) command() {}
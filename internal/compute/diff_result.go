@@ -0,0 +1,31 @@
+package compute
+
+import (
+	"fmt"
+
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+)
+
+// FileDiff is a unified diff of the changes a command makes to a file.
+type FileDiff struct {
+	Value        string `json:"value"`
+	Kind         string `json:"kind"`
+	RepositoryID int32  `json:"repositoryID"`
+	Repository   string `json:"repository"`
+	Commit       string `json:"commit"`
+	Path         string `json:"path"`
+}
+
+// unifiedDiff returns a diff of the change from before to after that can be
+// applied with git apply, or an empty string if there is no change. A last
+// line without a trailing newline is followed by the "\ No newline at end of
+// file" marker so that applying the diff preserves it.
+func unifiedDiff(path, before, after string) string {
+	if before == after {
+		return ""
+	}
+	edits := myers.ComputeEdits("", before, after)
+	unified := gotextdiff.ToUnified("a/"+path, "b/"+path, before, edits)
+	return fmt.Sprintf("diff --git a/%s b/%s\n%s", path, path, unified)
+}
@@ -0,0 +1,49 @@
+package compute
+
+import (
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("no change", func(t *testing.T) {
+		autogold.Expect("").Equal(t, unifiedDiff("a.go", "x\n", "x\n"))
+	})
+
+	t.Run("missing newline on both sides", func(t *testing.T) {
+		autogold.Expect(`diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -1,2 +1,2 @@
+ x
+-colarado
+\ No newline at end of file
++colorado
+\ No newline at end of file
+`).Equal(t, unifiedDiff("a.go", "x\ncolarado", "x\ncolorado"))
+	})
+
+	t.Run("newline removed", func(t *testing.T) {
+		autogold.Expect(`diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -1 +1 @@
+-colarado
++colorado
+\ No newline at end of file
+`).Equal(t, unifiedDiff("a.go", "colarado\n", "colorado"))
+	})
+
+	t.Run("newline added", func(t *testing.T) {
+		autogold.Expect(`diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -1 +1,2 @@
+-colarado
+\ No newline at end of file
++colorado
++x
+`).Equal(t, unifiedDiff("a.go", "colarado", "colorado\nx\n"))
+	})
+}
@@ -103,6 +103,14 @@ func resultChunks(r result.Match, kind string, onlyPath bool) []string {
 		return []string{content}
 	case *result.OwnerMatch:
 		return []string{m.ResolvedOwner.Identifier()}
+	case *result.DirectoryMatch:
+		return []string{m.Path}
+	case *result.CompareMatch:
+		lines := make([]string, 0, len(m.Lines))
+		for _, l := range m.Lines {
+			lines = append(lines, l.Preview)
+		}
+		return lines
 	default:
 		panic("unsupported result kind in compute output command")
 	}
@@ -3,14 +3,12 @@ package compute
 import (
 	"context"
 	"encoding/json"
-	"os"
 	"testing"
 
 	"github.com/grafana/regexp"
 	"github.com/hexops/autogold/v2"
 
 	"github.com/sourcegraph/log/logtest"
-	"github.com/sourcegraph/sourcegraph/internal/comby"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
@@ -33,11 +31,6 @@ func Test_output(t *testing.T) {
 			Separator:     "~",
 		}))
 
-	// If we are not on CI skip the test if comby is not installed.
-	if os.Getenv("CI") == "" && !comby.Exists() {
-		t.Skip("comby is not installed on the PATH. Try running 'bash <(curl -sL get.comby.dev)'.")
-	}
-
 	autogold.Expect(`train(regional, intercity)
 train(commuter, lightrail)`).
 		Equal(t, test("Im a train. train(intercity, regional). choo choo. train(lightrail, commuter)", &Output{
@@ -111,11 +104,6 @@ func TestRun(t *testing.T) {
 	autogold.Expect("test\nstring\n").
 		Equal(t, test(`content:output((\b\w+\b) -> $1)`, fileMatch("test", "string")))
 
-	// If we are not on CI skip the test if comby is not installed.
-	if os.Getenv("CI") == "" && !comby.Exists() {
-		t.Skip("comby is not installed on the PATH. Try running 'bash <(curl -sL get.comby.dev)'.")
-	}
-
 	autogold.Expect(">bar<").
 		Equal(t, test(`content:output.structural(foo(:[arg]) -> >:[arg]<)`, fileMatch("foo(bar)")))
 
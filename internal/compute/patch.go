@@ -0,0 +1,117 @@
+package compute
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Patch bundles the diffs of a diff replace into a single patch. Diffs of
+// the same repository are grouped together, and each group starts with a
+// comment naming the repository and commit it applies to, which git apply
+// ignores.
+func Patch(diffs []*FileDiff) string {
+	var b strings.Builder
+	for _, repo := range groupByRepository(diffs) {
+		fmt.Fprintf(&b, "# Repository: %s\n# Commit: %s\n", repo.name, repo.commit)
+		for _, d := range repo.diffs {
+			b.WriteString(d.Value)
+		}
+	}
+	return b.String()
+}
+
+// patchPath is where batch spec steps write the patch of a repository before
+// applying it.
+const patchPath = "/tmp/compute.patch"
+
+type batchSpec struct {
+	Name              string            `yaml:"name"`
+	Description       string            `yaml:"description,omitempty"`
+	On                []batchSpecOn     `yaml:"on"`
+	Steps             []batchSpecStep   `yaml:"steps"`
+	ChangesetTemplate changesetTemplate `yaml:"changesetTemplate"`
+}
+
+type batchSpecOn struct {
+	Repository string `yaml:"repository"`
+}
+
+type batchSpecStep struct {
+	Run       string            `yaml:"run"`
+	Container string            `yaml:"container"`
+	If        string            `yaml:"if"`
+	Files     map[string]string `yaml:"files"`
+}
+
+type changesetTemplate struct {
+	Title  string `yaml:"title"`
+	Body   string `yaml:"body"`
+	Branch string `yaml:"branch"`
+	Commit struct {
+		Message string `yaml:"message"`
+	} `yaml:"commit"`
+}
+
+// BatchSpec returns a batch spec that applies the diffs of a diff replace
+// for query. Each repository gets a step that applies its patch, so the
+// batch change creates one changeset per repository with diffs.
+func BatchSpec(query string, diffs []*FileDiff) (string, error) {
+	spec := batchSpec{
+		Name:        "compute-replace",
+		Description: fmt.Sprintf("Changes from the compute query %q", query),
+	}
+	for _, repo := range groupByRepository(diffs) {
+		var patch strings.Builder
+		for _, d := range repo.diffs {
+			patch.WriteString(d.Value)
+		}
+		spec.On = append(spec.On, batchSpecOn{Repository: repo.name})
+		spec.Steps = append(spec.Steps, batchSpecStep{
+			Run:       "git apply " + patchPath,
+			Container: "alpine/git",
+			If:        fmt.Sprintf("${{ eq repository.name %q }}", repo.name),
+			Files:     map[string]string{patchPath: patch.String()},
+		})
+	}
+	spec.ChangesetTemplate.Title = "Apply compute replace"
+	spec.ChangesetTemplate.Body = fmt.Sprintf("This changeset applies the changes of the compute query `%s`.", query)
+	spec.ChangesetTemplate.Branch = "compute-replace"
+	spec.ChangesetTemplate.Commit.Message = "Apply compute replace"
+
+	out, err := yaml.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+type repositoryDiffs struct {
+	name   string
+	commit string
+	diffs  []*FileDiff
+}
+
+// groupByRepository groups diffs by repository, in the order repositories
+// first appear, and sorts the diffs of each repository by path.
+func groupByRepository(diffs []*FileDiff) []*repositoryDiffs {
+	var repos []*repositoryDiffs
+	byName := map[string]*repositoryDiffs{}
+	for _, d := range diffs {
+		repo, ok := byName[d.Repository]
+		if !ok {
+			repo = &repositoryDiffs{name: d.Repository, commit: d.Commit}
+			byName[d.Repository] = repo
+			repos = append(repos, repo)
+		}
+		repo.diffs = append(repo.diffs, d)
+	}
+	for _, repo := range repos {
+		sort.SliceStable(repo.diffs, func(i, j int) bool {
+			return repo.diffs[i].Path < repo.diffs[j].Path
+		})
+	}
+	return repos
+}
@@ -0,0 +1,73 @@
+package compute
+
+import (
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+
+	"github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func testDiffs() []*FileDiff {
+	diff := func(repo, path, before, after string) *FileDiff {
+		return &FileDiff{
+			Value:      unifiedDiff(path, before, after),
+			Kind:       "diff",
+			Repository: repo,
+			Commit:     "deadbeef",
+			Path:       path,
+		}
+	}
+	return []*FileDiff{
+		diff("github.com/a/a", "b.go", "colarado\n", "colorado\n"),
+		diff("github.com/b/b", "c.go", "colarado", "colorado"),
+		diff("github.com/a/a", "a.go", "x\ncolarado\n", "x\ncolorado\n"),
+	}
+}
+
+func TestPatch(t *testing.T) {
+	autogold.Expect(`# Repository: github.com/a/a
+# Commit: deadbeef
+diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -1,2 +1,2 @@
+ x
+-colarado
++colorado
+diff --git a/b.go b/b.go
+--- a/b.go
++++ b/b.go
+@@ -1 +1 @@
+-colarado
++colorado
+# Repository: github.com/b/b
+# Commit: deadbeef
+diff --git a/c.go b/c.go
+--- a/c.go
++++ b/c.go
+@@ -1 +1 @@
+-colarado
+\ No newline at end of file
++colorado
+\ No newline at end of file
+`).Equal(t, Patch(testDiffs()))
+}
+
+func TestBatchSpec(t *testing.T) {
+	spec, err := BatchSpec("content:replace.diff(colarado -> colorado)", testDiffs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	autogold.ExpectFile(t, autogold.Raw(spec))
+
+	parsed, err := batches.ParseBatchSpec([]byte(spec))
+	if err != nil {
+		t.Fatalf("batch spec does not validate: %s", err)
+	}
+	var repos []string
+	for _, on := range parsed.On {
+		repos = append(repos, on.Repository)
+	}
+	autogold.Expect([]string{"github.com/a/a", "github.com/b/b"}).Equal(t, repos)
+}
@@ -2,6 +2,7 @@ package compute
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/grafana/regexp"
 
@@ -25,11 +26,14 @@ func (q Query) String() string {
 }
 
 func (q Query) ToSearchQuery() (string, error) {
-	pattern := q.Command.ToSearchPattern()
+	operands := q.Parameters
+	if pattern := q.Command.ToSearchPattern(); pattern != "" {
+		operands = append(operands, query.Pattern{Value: pattern})
+	}
 	expression := []query.Node{
 		query.Operator{
 			Kind:     query.And,
-			Operands: append(q.Parameters, query.Pattern{Value: pattern}),
+			Operands: operands,
 		},
 	}
 	return query.StringHuman(expression), nil
@@ -97,13 +101,17 @@ func toRegexpPattern(value string) (MatchPattern, error) {
 
 var ComputePredicateRegistry = query.PredicateRegistry{
 	query.FieldContent: {
-		"replace":            func() query.Predicate { return query.EmptyPredicate{} },
-		"replace.regexp":     func() query.Predicate { return query.EmptyPredicate{} },
-		"replace.structural": func() query.Predicate { return query.EmptyPredicate{} },
-		"output":             func() query.Predicate { return query.EmptyPredicate{} },
-		"output.regexp":      func() query.Predicate { return query.EmptyPredicate{} },
-		"output.structural":  func() query.Predicate { return query.EmptyPredicate{} },
-		"output.extra":       func() query.Predicate { return query.EmptyPredicate{} },
+		"replace":                 func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.regexp":          func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.structural":      func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.diff":            func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.regexp.diff":     func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.structural.diff": func() query.Predicate { return query.EmptyPredicate{} },
+		"output":                  func() query.Predicate { return query.EmptyPredicate{} },
+		"output.regexp":           func() query.Predicate { return query.EmptyPredicate{} },
+		"output.structural":       func() query.Predicate { return query.EmptyPredicate{} },
+		"output.extra":            func() query.Predicate { return query.EmptyPredicate{} },
+		"aggregate":               func() query.Predicate { return query.EmptyPredicate{} },
 	},
 }
 
@@ -145,6 +153,10 @@ func parseReplace(q *query.Basic) (Command, bool, error) {
 		return nil, false, err
 	}
 
+	// The .diff variants return a unified diff of each file instead of the
+	// replaced content.
+	name, diff := strings.CutSuffix(name, ".diff")
+
 	var matchPattern MatchPattern
 	switch name {
 	case "replace", "replace.regexp":
@@ -164,6 +176,7 @@ func parseReplace(q *query.Basic) (Command, bool, error) {
 	return &Replace{
 		SearchPattern:  matchPattern,
 		ReplacePattern: right,
+		Diff:           diff,
 	}, true, nil
 }
 
@@ -220,6 +233,50 @@ func parseOutput(q *query.Basic) (Command, bool, error) {
 	}, true, nil
 }
 
+var aggregateSyntax = lazyregexp.New(`^count\s+by\s+(\S+)(?:\s+where\s+(.+))?$`)
+
+func parseAggregate(q *query.Basic) (Command, bool, error) {
+	pattern, err := extractPattern(q)
+	if err != nil {
+		return nil, false, err
+	}
+
+	name, args, ok := parseContentPredicate(pattern)
+	if !ok || name != "aggregate" {
+		return nil, false, nil
+	}
+	parts := aggregateSyntax.FindStringSubmatch(strings.TrimSpace(args))
+	if parts == nil {
+		return nil, false, errors.New("invalid aggregate command, expected `count by <group>` optionally followed by `where <regexp>`")
+	}
+	by, where := parts[1], parts[2]
+
+	switch by {
+	case aggregateByRepo, aggregateByPath, aggregateByAuthor, aggregateByDate:
+	default:
+		if !isCaptureGroup(by) {
+			return nil, false, errors.Errorf("invalid aggregate command, cannot count by %q: expected repo, path, author, date or a capture group like $1", by)
+		}
+		if where == "" {
+			return nil, false, errors.Errorf("invalid aggregate command, counting by capture group %s needs a `where <regexp>` pattern", by)
+		}
+	}
+
+	var matchPattern MatchPattern
+	if where != "" {
+		// The pattern may be delimited by slashes, like /regexp/.
+		if len(where) > 1 && strings.HasPrefix(where, "/") && strings.HasSuffix(where, "/") {
+			where = where[1 : len(where)-1]
+		}
+		matchPattern, err = toRegexpPattern(where)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "aggregate command")
+		}
+	}
+
+	return &Aggregate{By: by, SearchPattern: matchPattern}, true, nil
+}
+
 func parseMatchOnly(q *query.Basic) (Command, bool, error) {
 	pattern, err := extractPattern(q)
 	if err != nil {
@@ -261,6 +318,7 @@ func first(parsers ...commandParser) commandParser {
 }
 
 var parseCommand = first(
+	parseAggregate,
 	parseReplace,
 	parseOutput,
 	parseMatchOnly,
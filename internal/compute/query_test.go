@@ -41,6 +41,27 @@ func TestParse(t *testing.T) {
 
 	autogold.Expect("Command: `Replace in place: () -> (b)`").
 		Equal(t, test("content:replace(->b)"))
+
+	autogold.Expect("Command: `Replace as diff: (a) -> (b)`").
+		Equal(t, test("content:replace.diff(a -> b)"))
+
+	autogold.Expect("Command: `Replace as diff: (foo(:[x])) -> (bar(:[x]))`").
+		Equal(t, test("content:replace.structural.diff(foo(:[x]) -> bar(:[x]))"))
+
+	autogold.Expect("Command: `Aggregate: count by $1 where (import \"(\\S+)\")`, Parameters: `repo:x`").
+		Equal(t, test(`content:aggregate(count by $1 where /import "(\S+)"/) repo:x`))
+
+	autogold.Expect("Command: `Aggregate: count by author`, Parameters: `type:commit`").
+		Equal(t, test("content:aggregate(count by author) type:commit"))
+
+	autogold.Expect("invalid aggregate command, counting by capture group $1 needs a `where <regexp>` pattern").
+		Equal(t, test("content:aggregate(count by $1)"))
+
+	autogold.Expect(`invalid aggregate command, cannot count by "lang": expected repo, path, author, date or a capture group like $1`).
+		Equal(t, test("content:aggregate(count by lang where foo)"))
+
+	autogold.Expect("invalid aggregate command, expected `count by <group>` optionally followed by `where <regexp>`").
+		Equal(t, test("content:aggregate(sum of $1)"))
 }
 
 func TestToSearchQuery(t *testing.T) {
@@ -61,4 +82,10 @@ func TestToSearchQuery(t *testing.T) {
 
 	autogold.Expect("((repo:foo file:bar lang:go OR repo:foo file:bar lang:text) AND colarado)").
 		Equal(t, test("content:replace(colarado -> colorodo) repo:foo file:bar (lang:go or lang:text)"))
+
+	autogold.Expect(`repo:foo /import "(\\S+)"/`).
+		Equal(t, test(`content:aggregate(count by $1 where /import "(\S+)"/) repo:foo`))
+
+	autogold.Expect("type:commit").
+		Equal(t, test("content:aggregate(count by author) type:commit"))
 }
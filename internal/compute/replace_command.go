@@ -15,6 +15,9 @@ import (
 type Replace struct {
 	SearchPattern  MatchPattern
 	ReplacePattern string
+	// Diff makes Run return a unified diff of the file instead of the
+	// replaced file content.
+	Diff bool
 }
 
 func (c *Replace) ToSearchPattern() string {
@@ -22,6 +25,9 @@ func (c *Replace) ToSearchPattern() string {
 }
 
 func (c *Replace) String() string {
+	if c.Diff {
+		return fmt.Sprintf("Replace as diff: (%s) -> (%s)", c.SearchPattern.String(), c.ReplacePattern)
+	}
 	return fmt.Sprintf("Replace in place: (%s) -> (%s)", c.SearchPattern.String(), c.ReplacePattern)
 }
 
@@ -57,7 +63,22 @@ func (c *Replace) Run(ctx context.Context, gitserverClient gitserver.Client, r r
 		if err != nil {
 			return nil, err
 		}
-		return replace(ctx, content, c.SearchPattern, c.ReplacePattern)
+		replaced, err := replace(ctx, content, c.SearchPattern, c.ReplacePattern)
+		if err != nil || !c.Diff {
+			return replaced, err
+		}
+		diff := unifiedDiff(m.Path, string(content), replaced.Value)
+		if diff == "" {
+			return nil, nil
+		}
+		return &FileDiff{
+			Value:        diff,
+			Kind:         "diff",
+			RepositoryID: int32(m.Repo.ID),
+			Repository:   string(m.Repo.Name),
+			Commit:       string(m.CommitID),
+			Path:         m.Path,
+		}, nil
 	}
 	return nil, nil
 }
@@ -2,13 +2,14 @@ package compute
 
 import (
 	"context"
-	"os"
 	"testing"
 
 	"github.com/grafana/regexp"
 	"github.com/hexops/autogold/v2"
 
-	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 func Test_replace(t *testing.T) {
@@ -26,14 +27,55 @@ func Test_replace(t *testing.T) {
 			ReplacePattern: "a bit more $1",
 		}))
 
-	// If we are not on CI skip the test if comby is not installed.
-	if os.Getenv("CI") == "" && !comby.Exists() {
-		t.Skip("comby is not installed on the PATH. Try running 'bash <(curl -sL get.comby.dev)'.")
-	}
-
 	autogold.Expect("foo(baz, bar)").
 		Equal(t, test("foo(bar, baz)", &Replace{
 			SearchPattern:  &Comby{Value: `foo(:[x], :[y])`},
 			ReplacePattern: "foo(:[y], :[x])",
 		}))
 }
+
+func TestReplace_Diff(t *testing.T) {
+	gitserverClient := gitserver.NewMockClient()
+	gitserverClient.ReadFileFunc.SetDefaultReturn([]byte("package main\n\nfunc main() {\n\tfmt.Println(colarado)\n}\n"), nil)
+
+	test := func(pattern string) Result {
+		cmd := &Replace{
+			SearchPattern:  &Regexp{Value: regexp.MustCompile(pattern)},
+			ReplacePattern: "colorado",
+			Diff:           true,
+		}
+		r, err := cmd.Run(context.Background(), gitserverClient, &result.FileMatch{
+			File: result.File{
+				Repo:     types.MinimalRepo{ID: 1, Name: "github.com/sourcegraph/sourcegraph"},
+				CommitID: "deadbeef",
+				Path:     "main.go",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	autogold.Expect(&FileDiff{
+		Value: `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,5 +1,5 @@
+ package main
+ 
+ func main() {
+-	fmt.Println(colarado)
++	fmt.Println(colorado)
+ }
+`,
+		Kind:         "diff",
+		RepositoryID: 1,
+		Repository:   "github.com/sourcegraph/sourcegraph",
+		Commit:       "deadbeef",
+		Path:         "main.go",
+	}).Equal(t, test("colarado"))
+
+	// Files without changes have no diff.
+	autogold.Expect(nil).Equal(t, test("nothing"))
+}
@@ -310,6 +310,22 @@ func NewMetaEnvironment(r searchresult.Match, content string) *MetaEnvironment {
 			Owner:   m.ResolvedOwner.Identifier(),
 			Content: content,
 		}
+	case *searchresult.DirectoryMatch:
+		return &MetaEnvironment{
+			Repo:    string(m.Repo.Name),
+			Commit:  string(m.CommitID),
+			Path:    m.Path,
+			Content: content,
+		}
+	case *searchresult.CompareMatch:
+		lang, _ := enry.GetLanguageByExtension(m.Path)
+		return &MetaEnvironment{
+			Repo:    string(m.Repo.Name),
+			Commit:  string(m.HeadCommit),
+			Path:    m.Path,
+			Lang:    lang,
+			Content: content,
+		}
 	}
 	return &MetaEnvironment{}
 }
@@ -0,0 +1,37 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type Gitea struct {
+	*schema.GiteaConnection
+}
+
+var _ RepoSource = Gitea{}
+
+func (c Gitea) CloneURLToRepoName(cloneURL string) (repoName api.RepoName, err error) {
+	parsedCloneURL, baseURL, match, err := parseURLs(cloneURL, c.Url)
+	if err != nil {
+		return "", err
+	}
+	if !match {
+		return "", nil
+	}
+	return GiteaRepoName(c.RepositoryPathPattern, baseURL.Hostname(), strings.TrimPrefix(strings.TrimSuffix(parsedCloneURL.Path, ".git"), "/")), nil
+}
+
+// GiteaRepoName returns the Sourcegraph name of a Gitea repository.
+func GiteaRepoName(repositoryPathPattern, host, nameWithOwner string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{nameWithOwner}"
+	}
+
+	return api.RepoName(strings.NewReplacer(
+		"{host}", host,
+		"{nameWithOwner}", nameWithOwner,
+	).Replace(repositoryPathPattern))
+}
@@ -0,0 +1,39 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParseNuGetVersionedPackage(t *testing.T) {
+	for _, tc := range []struct {
+		dependency string
+		name       PackageName
+		version    string
+	}{
+		{dependency: "newtonsoft.json@13.0.3", name: "newtonsoft.json", version: "13.0.3"},
+		{dependency: "Newtonsoft.Json@13.0.3", name: "newtonsoft.json", version: "13.0.3"},
+		{dependency: "Serilog", name: "serilog"},
+		{dependency: "Microsoft.Extensions.Logging@8.0.0-rc.1", name: "microsoft.extensions.logging", version: "8.0.0-rc.1"},
+	} {
+		t.Run(tc.dependency, func(t *testing.T) {
+			dep := ParseNuGetVersionedPackage(tc.dependency)
+			assert.Equal(t, tc.name, dep.Name)
+			assert.Equal(t, tc.version, dep.Version)
+		})
+	}
+}
+
+func TestParseNuGetPackageFromRepoName(t *testing.T) {
+	dep, err := ParseNuGetPackageFromRepoName("nuget/Newtonsoft.Json")
+	require.NoError(t, err)
+	assert.Equal(t, PackageName("newtonsoft.json"), dep.Name)
+	assert.Equal(t, api.RepoName("nuget/newtonsoft.json"), dep.RepoName())
+
+	_, err = ParseNuGetPackageFromRepoName("npm/newtonsoft.json")
+	require.Error(t, err)
+}
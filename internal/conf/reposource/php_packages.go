@@ -0,0 +1,98 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const phpPackagesPrefix = "packagist/"
+
+// phpPackageNameRegex matches Composer package names, which are always in a
+// 'vendor/package' format. See https://getcomposer.org/doc/04-schema.md#name.
+var phpPackageNameRegex = lazyregexp.New(`^[a-z0-9]([_.-]?[a-z0-9]+)*/[a-z0-9](([_.]|-{1,2})?[a-z0-9]+)*$`)
+
+type PHPVersionedPackage struct {
+	Name    PackageName
+	Version string
+}
+
+func NewPHPVersionedPackage(name PackageName, version string) *PHPVersionedPackage {
+	return &PHPVersionedPackage{
+		Name:    name,
+		Version: version,
+	}
+}
+
+// ParsePHPVersionedPackage parses a string in a '<vendor>/<package>(@<version>)?' format
+// into a PHPVersionedPackage. Composer package names are case-insensitive, so the name
+// is lowercased.
+func ParsePHPVersionedPackage(dependency string) (*PHPVersionedPackage, error) {
+	var dep PHPVersionedPackage
+	if i := strings.LastIndex(dependency, "@"); i == -1 {
+		dep.Name = PackageName(strings.ToLower(strings.TrimSpace(dependency)))
+	} else {
+		dep.Name = PackageName(strings.ToLower(strings.TrimSpace(dependency[:i])))
+		dep.Version = strings.TrimSpace(dependency[i+1:])
+	}
+	if !phpPackageNameRegex.MatchString(string(dep.Name)) {
+		return nil, errors.Newf("invalid PHP package name %q, expected a '<vendor>/<package>' name", dep.Name)
+	}
+	return &dep, nil
+}
+
+func ParsePHPPackageFromName(name PackageName) (*PHPVersionedPackage, error) {
+	return ParsePHPVersionedPackage(string(name))
+}
+
+// ParsePHPPackageFromRepoName is a convenience function to parse a repo name in a
+// 'packagist/<vendor>/<package>(@<version>)?' format into a PHPVersionedPackage.
+func ParsePHPPackageFromRepoName(name api.RepoName) (*PHPVersionedPackage, error) {
+	dependency := strings.TrimPrefix(string(name), phpPackagesPrefix)
+	if len(dependency) == len(name) {
+		return nil, errors.Newf("invalid PHP dependency repo name, missing %s prefix '%s'", phpPackagesPrefix, name)
+	}
+	return ParsePHPVersionedPackage(dependency)
+}
+
+func (p *PHPVersionedPackage) Scheme() string {
+	return "scip-php"
+}
+
+func (p *PHPVersionedPackage) PackageSyntax() PackageName {
+	return p.Name
+}
+
+func (p *PHPVersionedPackage) VersionedPackageSyntax() string {
+	if p.Version == "" {
+		return string(p.Name)
+	}
+	return string(p.Name) + "@" + p.Version
+}
+
+func (p *PHPVersionedPackage) PackageVersion() string {
+	return p.Version
+}
+
+func (p *PHPVersionedPackage) Description() string { return "" }
+
+func (p *PHPVersionedPackage) RepoName() api.RepoName {
+	return api.RepoName(phpPackagesPrefix + p.Name)
+}
+
+func (p *PHPVersionedPackage) GitTagFromVersion() string {
+	version := strings.TrimPrefix(p.Version, "v")
+	return "v" + version
+}
+
+func (p *PHPVersionedPackage) Less(other VersionedPackage) bool {
+	o := other.(*PHPVersionedPackage)
+
+	if p.Name == o.Name {
+		return versionGreaterThan(p.Version, o.Version)
+	}
+
+	return p.Name > o.Name
+}
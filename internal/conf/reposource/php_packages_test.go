@@ -0,0 +1,48 @@
+package reposource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestParsePHPVersionedPackage(t *testing.T) {
+	for _, tc := range []struct {
+		dependency string
+		name       PackageName
+		version    string
+		wantErr    bool
+	}{
+		{dependency: "monolog/monolog@3.5.0", name: "monolog/monolog", version: "3.5.0"},
+		{dependency: "symfony/http-foundation", name: "symfony/http-foundation"},
+		{dependency: "Laravel/Framework@v10.0.0", name: "laravel/framework", version: "v10.0.0"},
+		{dependency: "doctrine/doctrine-bundle@2.11.1", name: "doctrine/doctrine-bundle", version: "2.11.1"},
+		{dependency: "monolog@3.5.0", wantErr: true},
+		{dependency: "monolog/monolog/extra@1.0.0", wantErr: true},
+		{dependency: "/monolog@1.0.0", wantErr: true},
+	} {
+		t.Run(tc.dependency, func(t *testing.T) {
+			dep, err := ParsePHPVersionedPackage(tc.dependency)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.name, dep.Name)
+			assert.Equal(t, tc.version, dep.Version)
+		})
+	}
+}
+
+func TestParsePHPPackageFromRepoName(t *testing.T) {
+	dep, err := ParsePHPPackageFromRepoName("packagist/monolog/monolog")
+	require.NoError(t, err)
+	assert.Equal(t, PackageName("monolog/monolog"), dep.Name)
+	assert.Equal(t, api.RepoName("packagist/monolog/monolog"), dep.RepoName())
+
+	_, err = ParsePHPPackageFromRepoName("npm/monolog")
+	require.Error(t, err)
+}
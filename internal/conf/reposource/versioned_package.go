@@ -25,4 +25,8 @@ var (
 	_ VersionedPackage = (*GoVersionedPackage)(nil)
 	_ VersionedPackage = (*PythonVersionedPackage)(nil)
 	_ VersionedPackage = (*RustVersionedPackage)(nil)
+	_ VersionedPackage = (*RubyVersionedPackage)(nil)
+	_ VersionedPackage = (*NuGetVersionedPackage)(nil)
+	_ VersionedPackage = (*PHPVersionedPackage)(nil)
+	_ VersionedPackage = (*HexVersionedPackage)(nil)
 )
@@ -37484,9 +37484,15 @@ type MockGitserverRepoStore struct {
 	// ListPurgeableReposFunc is an instance of a mock function object
 	// controlling the behavior of the method ListPurgeableRepos.
 	ListPurgeableReposFunc *GitserverRepoStoreListPurgeableReposFunc
+	// ListReplicasFunc is an instance of a mock function object controlling
+	// the behavior of the method ListReplicas.
+	ListReplicasFunc *GitserverRepoStoreListReplicasFunc
 	// ListReposWithLastErrorFunc is an instance of a mock function object
 	// controlling the behavior of the method ListReposWithLastError.
 	ListReposWithLastErrorFunc *GitserverRepoStoreListReposWithLastErrorFunc
+	// ListStaleReplicasFunc is an instance of a mock function object
+	// controlling the behavior of the method ListStaleReplicas.
+	ListStaleReplicasFunc *GitserverRepoStoreListStaleReplicasFunc
 	// LogCorruptionFunc is an instance of a mock function object
 	// controlling the behavior of the method LogCorruption.
 	LogCorruptionFunc *GitserverRepoStoreLogCorruptionFunc
@@ -37505,6 +37511,15 @@ type MockGitserverRepoStore struct {
 	// SetLastOutputFunc is an instance of a mock function object
 	// controlling the behavior of the method SetLastOutput.
 	SetLastOutputFunc *GitserverRepoStoreSetLastOutputFunc
+	// SetReplicaCloneStatusFunc is an instance of a mock function object
+	// controlling the behavior of the method SetReplicaCloneStatus.
+	SetReplicaCloneStatusFunc *GitserverRepoStoreSetReplicaCloneStatusFunc
+	// SetReplicaLastErrorFunc is an instance of a mock function object
+	// controlling the behavior of the method SetReplicaLastError.
+	SetReplicaLastErrorFunc *GitserverRepoStoreSetReplicaLastErrorFunc
+	// SetReplicaLastFetchedFunc is an instance of a mock function object
+	// controlling the behavior of the method SetReplicaLastFetched.
+	SetReplicaLastFetchedFunc *GitserverRepoStoreSetReplicaLastFetchedFunc
 	// SetRepoSizeFunc is an instance of a mock function object controlling
 	// the behavior of the method SetRepoSize.
 	SetRepoSizeFunc *GitserverRepoStoreSetRepoSizeFunc
@@ -37568,11 +37583,21 @@ func NewMockGitserverRepoStore() *MockGitserverRepoStore {
 				return
 			},
 		},
+		ListReplicasFunc: &GitserverRepoStoreListReplicasFunc{
+			defaultHook: func(context.Context, api.RepoID) (r0 []*types.GitserverRepoReplica, r1 error) {
+				return
+			},
+		},
 		ListReposWithLastErrorFunc: &GitserverRepoStoreListReposWithLastErrorFunc{
 			defaultHook: func(context.Context) (r0 []api.RepoName, r1 error) {
 				return
 			},
 		},
+		ListStaleReplicasFunc: &GitserverRepoStoreListStaleReplicasFunc{
+			defaultHook: func(context.Context, string, []string, int) (r0 []api.RepoName, r1 error) {
+				return
+			},
+		},
 		LogCorruptionFunc: &GitserverRepoStoreLogCorruptionFunc{
 			defaultHook: func(context.Context, api.RepoName, string, string) (r0 error) {
 				return
@@ -37603,6 +37628,21 @@ func NewMockGitserverRepoStore() *MockGitserverRepoStore {
 				return
 			},
 		},
+		SetReplicaCloneStatusFunc: &GitserverRepoStoreSetReplicaCloneStatusFunc{
+			defaultHook: func(context.Context, api.RepoName, types.CloneStatus, string) (r0 error) {
+				return
+			},
+		},
+		SetReplicaLastErrorFunc: &GitserverRepoStoreSetReplicaLastErrorFunc{
+			defaultHook: func(context.Context, api.RepoName, string, string) (r0 error) {
+				return
+			},
+		},
+		SetReplicaLastFetchedFunc: &GitserverRepoStoreSetReplicaLastFetchedFunc{
+			defaultHook: func(context.Context, api.RepoName, time.Time, string) (r0 error) {
+				return
+			},
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: func(context.Context, api.RepoName, int64, string) (r0 error) {
 				return
@@ -37676,11 +37716,21 @@ func NewStrictMockGitserverRepoStore() *MockGitserverRepoStore {
 				panic("unexpected invocation of MockGitserverRepoStore.ListPurgeableRepos")
 			},
 		},
+		ListReplicasFunc: &GitserverRepoStoreListReplicasFunc{
+			defaultHook: func(context.Context, api.RepoID) ([]*types.GitserverRepoReplica, error) {
+				panic("unexpected invocation of MockGitserverRepoStore.ListReplicas")
+			},
+		},
 		ListReposWithLastErrorFunc: &GitserverRepoStoreListReposWithLastErrorFunc{
 			defaultHook: func(context.Context) ([]api.RepoName, error) {
 				panic("unexpected invocation of MockGitserverRepoStore.ListReposWithLastError")
 			},
 		},
+		ListStaleReplicasFunc: &GitserverRepoStoreListStaleReplicasFunc{
+			defaultHook: func(context.Context, string, []string, int) ([]api.RepoName, error) {
+				panic("unexpected invocation of MockGitserverRepoStore.ListStaleReplicas")
+			},
+		},
 		LogCorruptionFunc: &GitserverRepoStoreLogCorruptionFunc{
 			defaultHook: func(context.Context, api.RepoName, string, string) error {
 				panic("unexpected invocation of MockGitserverRepoStore.LogCorruption")
@@ -37711,6 +37761,21 @@ func NewStrictMockGitserverRepoStore() *MockGitserverRepoStore {
 				panic("unexpected invocation of MockGitserverRepoStore.SetLastOutput")
 			},
 		},
+		SetReplicaCloneStatusFunc: &GitserverRepoStoreSetReplicaCloneStatusFunc{
+			defaultHook: func(context.Context, api.RepoName, types.CloneStatus, string) error {
+				panic("unexpected invocation of MockGitserverRepoStore.SetReplicaCloneStatus")
+			},
+		},
+		SetReplicaLastErrorFunc: &GitserverRepoStoreSetReplicaLastErrorFunc{
+			defaultHook: func(context.Context, api.RepoName, string, string) error {
+				panic("unexpected invocation of MockGitserverRepoStore.SetReplicaLastError")
+			},
+		},
+		SetReplicaLastFetchedFunc: &GitserverRepoStoreSetReplicaLastFetchedFunc{
+			defaultHook: func(context.Context, api.RepoName, time.Time, string) error {
+				panic("unexpected invocation of MockGitserverRepoStore.SetReplicaLastFetched")
+			},
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: func(context.Context, api.RepoName, int64, string) error {
 				panic("unexpected invocation of MockGitserverRepoStore.SetRepoSize")
@@ -37768,9 +37833,15 @@ func NewMockGitserverRepoStoreFrom(i database.GitserverRepoStore) *MockGitserver
 		ListPurgeableReposFunc: &GitserverRepoStoreListPurgeableReposFunc{
 			defaultHook: i.ListPurgeableRepos,
 		},
+		ListReplicasFunc: &GitserverRepoStoreListReplicasFunc{
+			defaultHook: i.ListReplicas,
+		},
 		ListReposWithLastErrorFunc: &GitserverRepoStoreListReposWithLastErrorFunc{
 			defaultHook: i.ListReposWithLastError,
 		},
+		ListStaleReplicasFunc: &GitserverRepoStoreListStaleReplicasFunc{
+			defaultHook: i.ListStaleReplicas,
+		},
 		LogCorruptionFunc: &GitserverRepoStoreLogCorruptionFunc{
 			defaultHook: i.LogCorruption,
 		},
@@ -37789,6 +37860,15 @@ func NewMockGitserverRepoStoreFrom(i database.GitserverRepoStore) *MockGitserver
 		SetLastOutputFunc: &GitserverRepoStoreSetLastOutputFunc{
 			defaultHook: i.SetLastOutput,
 		},
+		SetReplicaCloneStatusFunc: &GitserverRepoStoreSetReplicaCloneStatusFunc{
+			defaultHook: i.SetReplicaCloneStatus,
+		},
+		SetReplicaLastErrorFunc: &GitserverRepoStoreSetReplicaLastErrorFunc{
+			defaultHook: i.SetReplicaLastError,
+		},
+		SetReplicaLastFetchedFunc: &GitserverRepoStoreSetReplicaLastFetchedFunc{
+			defaultHook: i.SetReplicaLastFetched,
+		},
 		SetRepoSizeFunc: &GitserverRepoStoreSetRepoSizeFunc{
 			defaultHook: i.SetRepoSize,
 		},
@@ -38688,6 +38768,116 @@ func (c GitserverRepoStoreListPurgeableReposFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverRepoStoreListReplicasFunc describes the behavior when the
+// ListReplicas method of the parent MockGitserverRepoStore instance is
+// invoked.
+type GitserverRepoStoreListReplicasFunc struct {
+	defaultHook func(context.Context, api.RepoID) ([]*types.GitserverRepoReplica, error)
+	hooks       []func(context.Context, api.RepoID) ([]*types.GitserverRepoReplica, error)
+	history     []GitserverRepoStoreListReplicasFuncCall
+	mutex       sync.Mutex
+}
+
+// ListReplicas delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) ListReplicas(v0 context.Context, v1 api.RepoID) ([]*types.GitserverRepoReplica, error) {
+	r0, r1 := m.ListReplicasFunc.nextHook()(v0, v1)
+	m.ListReplicasFunc.appendCall(GitserverRepoStoreListReplicasFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListReplicas method
+// of the parent MockGitserverRepoStore instance is invoked and the hook
+// queue is empty.
+func (f *GitserverRepoStoreListReplicasFunc) SetDefaultHook(hook func(context.Context, api.RepoID) ([]*types.GitserverRepoReplica, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListReplicas method of the parent MockGitserverRepoStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *GitserverRepoStoreListReplicasFunc) PushHook(hook func(context.Context, api.RepoID) ([]*types.GitserverRepoReplica, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreListReplicasFunc) SetDefaultReturn(r0 []*types.GitserverRepoReplica, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoID) ([]*types.GitserverRepoReplica, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreListReplicasFunc) PushReturn(r0 []*types.GitserverRepoReplica, r1 error) {
+	f.PushHook(func(context.Context, api.RepoID) ([]*types.GitserverRepoReplica, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverRepoStoreListReplicasFunc) nextHook() func(context.Context, api.RepoID) ([]*types.GitserverRepoReplica, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreListReplicasFunc) appendCall(r0 GitserverRepoStoreListReplicasFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverRepoStoreListReplicasFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverRepoStoreListReplicasFunc) History() []GitserverRepoStoreListReplicasFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreListReplicasFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreListReplicasFuncCall is an object that describes an
+// invocation of method ListReplicas on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreListReplicasFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*types.GitserverRepoReplica
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreListReplicasFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreListReplicasFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverRepoStoreListReposWithLastErrorFunc describes the behavior when
 // the ListReposWithLastError method of the parent MockGitserverRepoStore
 // instance is invoked.
@@ -38797,6 +38987,123 @@ func (c GitserverRepoStoreListReposWithLastErrorFuncCall) Results() []interface{
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// GitserverRepoStoreListStaleReplicasFunc describes the behavior when the
+// ListStaleReplicas method of the parent MockGitserverRepoStore instance is
+// invoked.
+type GitserverRepoStoreListStaleReplicasFunc struct {
+	defaultHook func(context.Context, string, []string, int) ([]api.RepoName, error)
+	hooks       []func(context.Context, string, []string, int) ([]api.RepoName, error)
+	history     []GitserverRepoStoreListStaleReplicasFuncCall
+	mutex       sync.Mutex
+}
+
+// ListStaleReplicas delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) ListStaleReplicas(v0 context.Context, v1 string, v2 []string, v3 int) ([]api.RepoName, error) {
+	r0, r1 := m.ListStaleReplicasFunc.nextHook()(v0, v1, v2, v3)
+	m.ListStaleReplicasFunc.appendCall(GitserverRepoStoreListStaleReplicasFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListStaleReplicas
+// method of the parent MockGitserverRepoStore instance is invoked and the
+// hook queue is empty.
+func (f *GitserverRepoStoreListStaleReplicasFunc) SetDefaultHook(hook func(context.Context, string, []string, int) ([]api.RepoName, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListStaleReplicas method of the parent MockGitserverRepoStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverRepoStoreListStaleReplicasFunc) PushHook(hook func(context.Context, string, []string, int) ([]api.RepoName, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreListStaleReplicasFunc) SetDefaultReturn(r0 []api.RepoName, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, []string, int) ([]api.RepoName, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreListStaleReplicasFunc) PushReturn(r0 []api.RepoName, r1 error) {
+	f.PushHook(func(context.Context, string, []string, int) ([]api.RepoName, error) {
+		return r0, r1
+	})
+}
+
+func (f *GitserverRepoStoreListStaleReplicasFunc) nextHook() func(context.Context, string, []string, int) ([]api.RepoName, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreListStaleReplicasFunc) appendCall(r0 GitserverRepoStoreListStaleReplicasFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of GitserverRepoStoreListStaleReplicasFuncCall
+// objects describing the invocations of this function.
+func (f *GitserverRepoStoreListStaleReplicasFunc) History() []GitserverRepoStoreListStaleReplicasFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreListStaleReplicasFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreListStaleReplicasFuncCall is an object that describes
+// an invocation of method ListStaleReplicas on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreListStaleReplicasFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []api.RepoName
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreListStaleReplicasFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreListStaleReplicasFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // GitserverRepoStoreLogCorruptionFunc describes the behavior when the
 // LogCorruption method of the parent MockGitserverRepoStore instance is
 // invoked.
@@ -39472,6 +39779,351 @@ func (c GitserverRepoStoreSetLastOutputFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// GitserverRepoStoreSetReplicaCloneStatusFunc describes the behavior when
+// the SetReplicaCloneStatus method of the parent MockGitserverRepoStore
+// instance is invoked.
+type GitserverRepoStoreSetReplicaCloneStatusFunc struct {
+	defaultHook func(context.Context, api.RepoName, types.CloneStatus, string) error
+	hooks       []func(context.Context, api.RepoName, types.CloneStatus, string) error
+	history     []GitserverRepoStoreSetReplicaCloneStatusFuncCall
+	mutex       sync.Mutex
+}
+
+// SetReplicaCloneStatus delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) SetReplicaCloneStatus(v0 context.Context, v1 api.RepoName, v2 types.CloneStatus, v3 string) error {
+	r0 := m.SetReplicaCloneStatusFunc.nextHook()(v0, v1, v2, v3)
+	m.SetReplicaCloneStatusFunc.appendCall(GitserverRepoStoreSetReplicaCloneStatusFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// SetReplicaCloneStatus method of the parent MockGitserverRepoStore
+// instance is invoked and the hook queue is empty.
+func (f *GitserverRepoStoreSetReplicaCloneStatusFunc) SetDefaultHook(hook func(context.Context, api.RepoName, types.CloneStatus, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetReplicaCloneStatus method of the parent MockGitserverRepoStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *GitserverRepoStoreSetReplicaCloneStatusFunc) PushHook(hook func(context.Context, api.RepoName, types.CloneStatus, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreSetReplicaCloneStatusFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, types.CloneStatus, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreSetReplicaCloneStatusFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, types.CloneStatus, string) error {
+		return r0
+	})
+}
+
+func (f *GitserverRepoStoreSetReplicaCloneStatusFunc) nextHook() func(context.Context, api.RepoName, types.CloneStatus, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreSetReplicaCloneStatusFunc) appendCall(r0 GitserverRepoStoreSetReplicaCloneStatusFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// GitserverRepoStoreSetReplicaCloneStatusFuncCall objects describing the
+// invocations of this function.
+func (f *GitserverRepoStoreSetReplicaCloneStatusFunc) History() []GitserverRepoStoreSetReplicaCloneStatusFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreSetReplicaCloneStatusFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreSetReplicaCloneStatusFuncCall is an object that
+// describes an invocation of method SetReplicaCloneStatus on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreSetReplicaCloneStatusFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 types.CloneStatus
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreSetReplicaCloneStatusFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreSetReplicaCloneStatusFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// GitserverRepoStoreSetReplicaLastErrorFunc describes the behavior when the
+// SetReplicaLastError method of the parent MockGitserverRepoStore instance
+// is invoked.
+type GitserverRepoStoreSetReplicaLastErrorFunc struct {
+	defaultHook func(context.Context, api.RepoName, string, string) error
+	hooks       []func(context.Context, api.RepoName, string, string) error
+	history     []GitserverRepoStoreSetReplicaLastErrorFuncCall
+	mutex       sync.Mutex
+}
+
+// SetReplicaLastError delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) SetReplicaLastError(v0 context.Context, v1 api.RepoName, v2 string, v3 string) error {
+	r0 := m.SetReplicaLastErrorFunc.nextHook()(v0, v1, v2, v3)
+	m.SetReplicaLastErrorFunc.appendCall(GitserverRepoStoreSetReplicaLastErrorFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the SetReplicaLastError
+// method of the parent MockGitserverRepoStore instance is invoked and the
+// hook queue is empty.
+func (f *GitserverRepoStoreSetReplicaLastErrorFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetReplicaLastError method of the parent MockGitserverRepoStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *GitserverRepoStoreSetReplicaLastErrorFunc) PushHook(hook func(context.Context, api.RepoName, string, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreSetReplicaLastErrorFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreSetReplicaLastErrorFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, string, string) error {
+		return r0
+	})
+}
+
+func (f *GitserverRepoStoreSetReplicaLastErrorFunc) nextHook() func(context.Context, api.RepoName, string, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreSetReplicaLastErrorFunc) appendCall(r0 GitserverRepoStoreSetReplicaLastErrorFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// GitserverRepoStoreSetReplicaLastErrorFuncCall objects describing the
+// invocations of this function.
+func (f *GitserverRepoStoreSetReplicaLastErrorFunc) History() []GitserverRepoStoreSetReplicaLastErrorFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreSetReplicaLastErrorFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreSetReplicaLastErrorFuncCall is an object that describes
+// an invocation of method SetReplicaLastError on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreSetReplicaLastErrorFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreSetReplicaLastErrorFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreSetReplicaLastErrorFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// GitserverRepoStoreSetReplicaLastFetchedFunc describes the behavior when
+// the SetReplicaLastFetched method of the parent MockGitserverRepoStore
+// instance is invoked.
+type GitserverRepoStoreSetReplicaLastFetchedFunc struct {
+	defaultHook func(context.Context, api.RepoName, time.Time, string) error
+	hooks       []func(context.Context, api.RepoName, time.Time, string) error
+	history     []GitserverRepoStoreSetReplicaLastFetchedFuncCall
+	mutex       sync.Mutex
+}
+
+// SetReplicaLastFetched delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockGitserverRepoStore) SetReplicaLastFetched(v0 context.Context, v1 api.RepoName, v2 time.Time, v3 string) error {
+	r0 := m.SetReplicaLastFetchedFunc.nextHook()(v0, v1, v2, v3)
+	m.SetReplicaLastFetchedFunc.appendCall(GitserverRepoStoreSetReplicaLastFetchedFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// SetReplicaLastFetched method of the parent MockGitserverRepoStore
+// instance is invoked and the hook queue is empty.
+func (f *GitserverRepoStoreSetReplicaLastFetchedFunc) SetDefaultHook(hook func(context.Context, api.RepoName, time.Time, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetReplicaLastFetched method of the parent MockGitserverRepoStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *GitserverRepoStoreSetReplicaLastFetchedFunc) PushHook(hook func(context.Context, api.RepoName, time.Time, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *GitserverRepoStoreSetReplicaLastFetchedFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, time.Time, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *GitserverRepoStoreSetReplicaLastFetchedFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, time.Time, string) error {
+		return r0
+	})
+}
+
+func (f *GitserverRepoStoreSetReplicaLastFetchedFunc) nextHook() func(context.Context, api.RepoName, time.Time, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *GitserverRepoStoreSetReplicaLastFetchedFunc) appendCall(r0 GitserverRepoStoreSetReplicaLastFetchedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// GitserverRepoStoreSetReplicaLastFetchedFuncCall objects describing the
+// invocations of this function.
+func (f *GitserverRepoStoreSetReplicaLastFetchedFunc) History() []GitserverRepoStoreSetReplicaLastFetchedFuncCall {
+	f.mutex.Lock()
+	history := make([]GitserverRepoStoreSetReplicaLastFetchedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// GitserverRepoStoreSetReplicaLastFetchedFuncCall is an object that
+// describes an invocation of method SetReplicaLastFetched on an instance of
+// MockGitserverRepoStore.
+type GitserverRepoStoreSetReplicaLastFetchedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 time.Time
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c GitserverRepoStoreSetReplicaLastFetchedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c GitserverRepoStoreSetReplicaLastFetchedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // GitserverRepoStoreSetRepoSizeFunc describes the behavior when the
 // SetRepoSize method of the parent MockGitserverRepoStore instance is
 // invoked.
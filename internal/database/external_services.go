@@ -214,6 +214,7 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindBitbucketCloud:  {CodeHost: true, JSONSchema: schema.BitbucketCloudSchemaJSON},
 	extsvc.KindBitbucketServer: {CodeHost: true, JSONSchema: schema.BitbucketServerSchemaJSON},
 	extsvc.KindGerrit:          {CodeHost: true, JSONSchema: schema.GerritSchemaJSON},
+	extsvc.KindGitea:           {CodeHost: true, JSONSchema: schema.GiteaSchemaJSON},
 	extsvc.KindGitHub:          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
@@ -227,6 +228,9 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindPythonPackages:  {CodeHost: true, JSONSchema: schema.PythonPackagesSchemaJSON},
 	extsvc.KindRustPackages:    {CodeHost: true, JSONSchema: schema.RustPackagesSchemaJSON},
 	extsvc.KindRubyPackages:    {CodeHost: true, JSONSchema: schema.RubyPackagesSchemaJSON},
+	extsvc.KindNuGetPackages:   {CodeHost: true, JSONSchema: schema.NuGetPackagesSchemaJSON},
+	extsvc.KindPHPPackages:     {CodeHost: true, JSONSchema: schema.PHPPackagesSchemaJSON},
+	extsvc.KindHexPackages:     {CodeHost: true, JSONSchema: schema.HexPackagesSchemaJSON},
 }
 
 // ExternalServiceKind describes a kind of external service.
@@ -75,6 +75,24 @@ type GitserverRepoStore interface {
 	// GetGitserverGitDirSize returns the total size of all git directories of cloned
 	// repos across all gitservers.
 	GetGitserverGitDirSize(ctx context.Context) (sizeBytes int64, err error)
+	// SetReplicaCloneStatus will attempt to update ONLY the clone status of the
+	// copy of a repo on the secondary gitserver shardID. If a matching row does
+	// not yet exist a new one will be created.
+	SetReplicaCloneStatus(ctx context.Context, name api.RepoName, status types.CloneStatus, shardID string) error
+	// SetReplicaLastError will attempt to update ONLY the last error of the copy
+	// of a repo on the secondary gitserver shardID. If a matching row does not
+	// yet exist a new one will be created.
+	SetReplicaLastError(ctx context.Context, name api.RepoName, error, shardID string) error
+	// SetReplicaLastFetched sets the last fetched time of the copy of a repo on
+	// the secondary gitserver shardID and ensures it is marked as cloned.
+	SetReplicaLastFetched(ctx context.Context, name api.RepoName, lastFetched time.Time, shardID string) error
+	// ListReplicas returns the state of all secondary copies of a repo.
+	ListReplicas(ctx context.Context, id api.RepoID) ([]*types.GitserverRepoReplica, error)
+	// ListStaleReplicas returns up to limit repos that are cloned on one of the
+	// primaryShardIDs, and whose copy on the secondary gitserver shardID is
+	// missing or was last fetched before the primary last changed. Repos whose
+	// copy was fetched the longest time ago are returned first.
+	ListStaleReplicas(ctx context.Context, shardID string, primaryShardIDs []string, limit int) ([]api.RepoName, error)
 }
 
 var _ GitserverRepoStore = (*gitserverRepoStore)(nil)
@@ -775,3 +793,122 @@ SET
 	updated_at = NOW()
 WHERE repo_id = (SELECT id FROM repo WHERE name = %s)
 `
+
+func (s *gitserverRepoStore) SetReplicaCloneStatus(ctx context.Context, name api.RepoName, status types.CloneStatus, shardID string) error {
+	err := s.Exec(ctx, sqlf.Sprintf(`
+INSERT INTO gitserver_repo_replicas (repo_id, shard_id, clone_status)
+SELECT id, %s, %s FROM repo WHERE name = %s
+ON CONFLICT (repo_id, shard_id) DO UPDATE
+SET
+	clone_status = EXCLUDED.clone_status,
+	updated_at = NOW()
+WHERE
+	gitserver_repo_replicas.clone_status IS DISTINCT FROM EXCLUDED.clone_status
+`, shardID, status, name))
+	if err != nil {
+		return errors.Wrap(err, "setting replica clone status")
+	}
+
+	return nil
+}
+
+func (s *gitserverRepoStore) SetReplicaLastError(ctx context.Context, name api.RepoName, error, shardID string) error {
+	ns := dbutil.NewNullString(sanitizeToUTF8(error))
+
+	err := s.Exec(ctx, sqlf.Sprintf(`
+INSERT INTO gitserver_repo_replicas (repo_id, shard_id, last_error)
+SELECT id, %s, %s FROM repo WHERE name = %s
+ON CONFLICT (repo_id, shard_id) DO UPDATE
+SET
+	last_error = EXCLUDED.last_error,
+	updated_at = NOW()
+WHERE
+	gitserver_repo_replicas.last_error IS DISTINCT FROM EXCLUDED.last_error
+`, shardID, ns, name))
+	if err != nil {
+		return errors.Wrap(err, "setting replica last error")
+	}
+
+	return nil
+}
+
+func (s *gitserverRepoStore) SetReplicaLastFetched(ctx context.Context, name api.RepoName, lastFetched time.Time, shardID string) error {
+	res, err := s.ExecResult(ctx, sqlf.Sprintf(`
+INSERT INTO gitserver_repo_replicas (repo_id, shard_id, clone_status, last_fetched)
+SELECT id, %s, %s, %s FROM repo WHERE name = %s
+ON CONFLICT (repo_id, shard_id) DO UPDATE
+SET
+	clone_status = EXCLUDED.clone_status,
+	last_fetched = EXCLUDED.last_fetched,
+	updated_at = NOW()
+`, shardID, types.CloneStatusCloned, lastFetched, name))
+	if err != nil {
+		return errors.Wrap(err, "setting replica last fetched")
+	}
+
+	if nrows, err := res.RowsAffected(); err != nil {
+		return errors.Wrap(err, "getting rows affected")
+	} else if nrows != 1 {
+		return errors.New("repo not found")
+	}
+
+	return nil
+}
+
+func (s *gitserverRepoStore) ListReplicas(ctx context.Context, id api.RepoID) ([]*types.GitserverRepoReplica, error) {
+	return scanGitserverRepoReplicas(s.Query(ctx, sqlf.Sprintf(listReplicasQueryFmtstr, id)))
+}
+
+const listReplicasQueryFmtstr = `
+SELECT
+	repo_id,
+	shard_id,
+	clone_status,
+	last_error,
+	last_fetched,
+	updated_at
+FROM gitserver_repo_replicas
+WHERE repo_id = %s
+ORDER BY shard_id
+`
+
+var scanGitserverRepoReplicas = basestore.NewSliceScanner(func(scanner dbutil.Scanner) (*types.GitserverRepoReplica, error) {
+	var r types.GitserverRepoReplica
+	var cloneStatus string
+	if err := scanner.Scan(
+		&r.RepoID,
+		&r.ShardID,
+		&cloneStatus,
+		&dbutil.NullString{S: &r.LastError},
+		&dbutil.NullTime{Time: &r.LastFetched},
+		&r.UpdatedAt,
+	); err != nil {
+		return nil, errors.Wrap(err, "scanning GitserverRepoReplica")
+	}
+	r.CloneStatus = types.ParseCloneStatus(cloneStatus)
+	return &r, nil
+})
+
+func (s *gitserverRepoStore) ListStaleReplicas(ctx context.Context, shardID string, primaryShardIDs []string, limit int) ([]api.RepoName, error) {
+	return scanRepoNames(s.Query(ctx, sqlf.Sprintf(listStaleReplicasQueryFmtstr, shardID, pq.Array(primaryShardIDs), limit)))
+}
+
+const listStaleReplicasQueryFmtstr = `
+SELECT r.name
+FROM gitserver_repos gr
+JOIN repo r ON r.id = gr.repo_id
+LEFT JOIN gitserver_repo_replicas rr ON rr.repo_id = gr.repo_id AND rr.shard_id = %s
+WHERE
+	gr.shard_id = ANY(%s)
+	AND gr.clone_status = 'cloned'
+	AND r.deleted_at IS NULL
+	AND r.blocked IS NULL
+	AND (
+		rr.repo_id IS NULL
+		OR rr.clone_status <> 'cloned'
+		OR rr.last_fetched IS NULL
+		OR rr.last_fetched < gr.last_changed
+	)
+ORDER BY rr.last_fetched ASC NULLS FIRST, gr.repo_id
+LIMIT %s
+`
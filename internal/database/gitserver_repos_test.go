@@ -913,6 +913,87 @@ func TestSetRepoSize(t *testing.T) {
 	}
 }
 
+func TestGitserverRepoReplicas(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	ctx := context.Background()
+
+	repo1, _ := createTestRepo(ctx, t, db, "github.com/sourcegraph/repo1")
+	repo2, _ := createTestRepo(ctx, t, db, "github.com/sourcegraph/repo2")
+	repo3, _ := createTestRepo(ctx, t, db, "github.com/sourcegraph/repo3")
+
+	// repo1 and repo2 are cloned on the primary, repo3 is not.
+	now := time.Now().Truncate(time.Second)
+	for _, repo := range []*types.Repo{repo1, repo2} {
+		if err := db.GitserverRepos().SetLastFetched(ctx, repo.Name, GitserverFetchData{LastFetched: now, LastChanged: now, ShardID: "gitserver-1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.GitserverRepos().SetCloneStatus(ctx, repo3.Name, types.CloneStatusNotCloned, "gitserver-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	listStale := func() []api.RepoName {
+		t.Helper()
+		names, err := db.GitserverRepos().ListStaleReplicas(ctx, "gitserver-2", []string{"gitserver-1"}, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return names
+	}
+
+	if diff := cmp.Diff([]api.RepoName{repo1.Name, repo2.Name}, listStale()); diff != "" {
+		t.Fatalf("unexpected stale replicas (-want +got):\n%s", diff)
+	}
+
+	// Replicas that are fetched after the last change of the primary are up to date.
+	if err := db.GitserverRepos().SetReplicaLastFetched(ctx, repo1.Name, now.Add(time.Minute), "gitserver-2"); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]api.RepoName{repo2.Name}, listStale()); diff != "" {
+		t.Fatalf("unexpected stale replicas (-want +got):\n%s", diff)
+	}
+
+	// Replicas that failed to clone are retried.
+	if err := db.GitserverRepos().SetReplicaCloneStatus(ctx, repo1.Name, types.CloneStatusNotCloned, "gitserver-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.GitserverRepos().SetReplicaLastError(ctx, repo1.Name, "oops\x00", "gitserver-2"); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]api.RepoName{repo2.Name, repo1.Name}, listStale()); diff != "" {
+		t.Fatalf("unexpected stale replicas (-want +got):\n%s", diff)
+	}
+
+	replicas, err := db.GitserverRepos().ListReplicas(ctx, repo1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*types.GitserverRepoReplica{{
+		RepoID:      repo1.ID,
+		ShardID:     "gitserver-2",
+		CloneStatus: types.CloneStatusNotCloned,
+		LastError:   "oops",
+		LastFetched: now.Add(time.Minute),
+	}}
+	if diff := cmp.Diff(want, replicas, cmpopts.IgnoreFields(types.GitserverRepoReplica{}, "UpdatedAt"), cmpopts.EquateApproxTime(time.Second)); diff != "" {
+		t.Fatalf("unexpected replicas (-want +got):\n%s", diff)
+	}
+
+	// Other shards don't see replicas of repos they aren't the primary of.
+	names, err := db.GitserverRepos().ListStaleReplicas(ctx, "gitserver-2", []string{"gitserver-3"}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 0 {
+		t.Fatalf("unexpected stale replicas: %v", names)
+	}
+}
+
 func TestGitserverRepo_Update(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
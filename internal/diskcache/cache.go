@@ -29,6 +29,9 @@ type Store interface {
 	// OpenWithPath will open a file from the local cache with key. If missing, fetcher
 	// will fill the cache first. OpenWithPath also performs single-flighting for fetcher.
 	OpenWithPath(ctx context.Context, key []string, fetcher FetcherWithPath) (file *File, err error)
+	// Contains returns true if key is in the cache. The item may be evicted
+	// at any time, so callers still have to be prepared to fetch it in Open.
+	Contains(key []string) bool
 	// Evict will remove files from store.Dir until it is smaller than
 	// maxCacheSizeBytes. It evicts files with the oldest modification time first.
 	Evict(maxCacheSizeBytes int64) (stats EvictStats, err error)
@@ -197,6 +200,11 @@ func (s *store) OpenWithPath(ctx context.Context, key []string, fetcher FetcherW
 	}
 }
 
+func (s *store) Contains(key []string) bool {
+	_, err := os.Stat(s.path(key))
+	return err == nil
+}
+
 // path returns the path for key.
 func (s *store) path(key []string) string {
 	encoded := append([]string{s.dir}, EncodeKeyComponents(key)...)
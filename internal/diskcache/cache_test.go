@@ -43,10 +43,16 @@ func TestOpen(t *testing.T) {
 	}
 
 	// Cache should be empty
+	if store.Contains([]string{"key"}) {
+		t.Fatal("Expected empty cache to not contain key")
+	}
 	_, usedCache := do()
 	if usedCache {
 		t.Fatal("Expected fetcher to be called on empty cache")
 	}
+	if !store.Contains([]string{"key"}) {
+		t.Fatal("Expected cache to contain key after fetching it")
+	}
 
 	// Redo, now we should use the cache
 	f, usedCache := do()
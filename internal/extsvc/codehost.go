@@ -15,7 +15,8 @@ type CodeHost struct {
 
 func (c *CodeHost) IsPackageHost() bool {
 	switch c.ServiceType {
-	case TypeNpmPackages, TypeJVMPackages, TypeGoModules, TypePythonPackages, TypeRustPackages, TypeRubyPackages:
+	case TypeNpmPackages, TypeJVMPackages, TypeGoModules, TypePythonPackages, TypeRustPackages, TypeRubyPackages,
+		TypeNuGetPackages, TypePHPPackages, TypeHexPackages:
 		return true
 	}
 	return false
@@ -49,6 +50,15 @@ var (
 	RubyURL      = &url.URL{Host: "rubygems"}
 	RubyPackages = NewCodeHost(RubyURL, TypeRubyPackages)
 
+	NuGetURL      = &url.URL{Host: "nuget"}
+	NuGetPackages = NewCodeHost(NuGetURL, TypeNuGetPackages)
+
+	PackagistURL = &url.URL{Host: "packagist"}
+	PHPPackages  = NewCodeHost(PackagistURL, TypePHPPackages)
+
+	HexURL      = &url.URL{Host: "hex"}
+	HexPackages = NewCodeHost(HexURL, TypeHexPackages)
+
 	PublicCodeHosts = []*CodeHost{
 		GitHubDotCom,
 		GitLabDotCom,
@@ -58,6 +68,9 @@ var (
 		PythonPackages,
 		RustPackages,
 		RubyPackages,
+		NuGetPackages,
+		PHPPackages,
+		HexPackages,
 	}
 )
 
@@ -0,0 +1,156 @@
+//nolint:bodyclose // Body is closed in Client.Do, but the response is still returned to provide access to the headers
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+// Client access a Gitea or Forgejo instance via the REST API.
+type client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Gitea.
+	URL *url.URL
+
+	// RateLimit is the self-imposed rate limiter (since Gitea does not have a concept
+	// of rate limiting in HTTP response headers).
+	rateLimit *ratelimit.InstrumentedLimiter
+
+	// Authenticator used to authenticate HTTP requests.
+	auther auth.Authenticator
+
+	// sudo is the login of the user that requests are made on behalf of. It
+	// requires the authenticator to belong to a site administrator.
+	sudo string
+}
+
+type Client interface {
+	GetURL() *url.URL
+	WithAuthenticator(a auth.Authenticator) (Client, error)
+	Authenticator() auth.Authenticator
+	WithSudo(login string) Client
+	GetAuthenticatedUser(ctx context.Context) (*User, error)
+	GetUser(ctx context.Context, login string) (*User, error)
+	ListRepos(ctx context.Context, opts ListReposArgs) *iterator.Iterator[*Repository]
+	GetRepo(ctx context.Context, owner, name string) (*Repository, error)
+	CreateFork(ctx context.Context, owner, name string, input CreateForkInput) (*Repository, error)
+	GetPullRequest(ctx context.Context, owner, name string, index int64) (*PullRequest, error)
+	GetPullRequestByBranches(ctx context.Context, owner, name, base, head string) (*PullRequest, error)
+	CreatePullRequest(ctx context.Context, owner, name string, input CreatePullRequestInput) (*PullRequest, error)
+	EditPullRequest(ctx context.Context, owner, name string, index int64, input EditPullRequestInput) (*PullRequest, error)
+	MergePullRequest(ctx context.Context, owner, name string, index int64, input MergePullRequestInput) error
+	ListPullRequestReviews(ctx context.Context, owner, name string, index int64) ([]*Review, error)
+	ListCommitStatuses(ctx context.Context, owner, name, ref string) ([]*CommitStatus, error)
+	CreateComment(ctx context.Context, owner, name string, index int64, body string) error
+}
+
+// NewClient returns an authenticated Gitea API client with the provided
+// configuration. If a nil httpClient is provided, httpcli.ExternalDoer will be
+// used.
+func NewClient(urn string, baseURL *url.URL, token string, httpClient httpcli.Doer) (Client, error) {
+	if httpClient == nil {
+		httpClient = httpcli.ExternalDoer
+	}
+
+	// API paths are resolved relative to the base URL, which needs a trailing
+	// slash for instances served from a subpath.
+	u := *baseURL
+
+	return &client{
+		httpClient: httpClient,
+		URL:        extsvc.NormalizeBaseURL(&u),
+		rateLimit:  ratelimit.NewInstrumentedLimiter(urn, ratelimit.NewGlobalRateLimiter(log.Scoped("GiteaClient"), urn)),
+		auther:     &auth.OAuthBearerToken{Token: token},
+	}, nil
+}
+
+func (c *client) WithAuthenticator(a auth.Authenticator) (Client, error) {
+	switch a.(type) {
+	case *auth.OAuthBearerToken, *auth.OAuthBearerTokenWithSSH, *auth.BasicAuth, *auth.BasicAuthWithSSH:
+		break
+	default:
+		return nil, errors.Errorf("authenticator type unsupported for Gitea clients: %s", a)
+	}
+
+	return &client{
+		httpClient: c.httpClient,
+		URL:        c.URL,
+		rateLimit:  c.rateLimit,
+		auther:     a,
+	}, nil
+}
+
+func (c *client) Authenticator() auth.Authenticator {
+	return c.auther
+}
+
+// WithSudo returns a copy of the client that makes requests on behalf of the
+// user with the given login.
+func (c *client) WithSudo(login string) Client {
+	cc := *c
+	cc.sudo = login
+	return &cc
+}
+
+func (c *client) GetURL() *url.URL {
+	return c.URL
+}
+
+func (c *client) do(ctx context.Context, req *http.Request, result any) (*http.Response, error) {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req = req.WithContext(ctx)
+	if req.Header.Get("Content-Type") == "" && req.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.sudo != "" {
+		req.Header.Set("Sudo", c.sudo)
+	}
+
+	// Authenticate request with auther
+	if c.auther != nil {
+		if err := c.auther.Authenticate(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.rateLimit.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+	if result == nil || len(bs) == 0 {
+		return resp, nil
+	}
+	return resp, json.Unmarshal(bs, result)
+}
@@ -0,0 +1,120 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+)
+
+func TestClient_do(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gitea/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+		case "/gitea/conflict":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"message":"pull request already exists for these targets"}`))
+		default:
+			fmt.Fprintf(w, `{"authorization":%q,"sudo":%q}`, r.Header.Get("Authorization"), r.Header.Get("Sudo"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	// The instance is served from a subpath without a trailing slash, which
+	// NewClient has to account for.
+	srvURL, err := url.Parse(srv.URL + "/gitea")
+	require.NoError(t, err)
+
+	cli, err := NewClient("urn", srvURL, "secret", httpcli.TestExternalDoer)
+	require.NoError(t, err)
+	cli.(*client).rateLimit = &ratelimit.InstrumentedLimiter{Limiter: rate.NewLimiter(10, 10)}
+
+	type headers struct {
+		Authorization string `json:"authorization"`
+		Sudo          string `json:"sudo"`
+	}
+	get := func(t *testing.T, c Client, path string) (headers, error) {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		var h headers
+		_, err = c.(*client).do(context.Background(), req, &h)
+		return h, err
+	}
+
+	t.Run("authenticates with the token", func(t *testing.T) {
+		h, err := get(t, cli, "anything")
+		require.NoError(t, err)
+		assert.Equal(t, headers{Authorization: "Bearer secret"}, h)
+	})
+
+	t.Run("sudo", func(t *testing.T) {
+		h, err := get(t, cli.WithSudo("alice"), "anything")
+		require.NoError(t, err)
+		assert.Equal(t, headers{Authorization: "Bearer secret", Sudo: "alice"}, h)
+	})
+
+	t.Run("authenticator", func(t *testing.T) {
+		c, err := cli.WithAuthenticator(&auth.BasicAuth{Username: "alice", Password: "hunter2"})
+		require.NoError(t, err)
+		h, err := get(t, c, "anything")
+		require.NoError(t, err)
+		assert.Equal(t, "Basic YWxpY2U6aHVudGVyMg==", h.Authorization)
+
+		_, err = cli.WithAuthenticator(&auth.OAuthClient{})
+		assert.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := get(t, cli, "missing")
+		assert.True(t, errcode.IsNotFound(err))
+		assert.Equal(t, fmt.Sprintf(`Gitea API HTTP error: code=404 url="%s/gitea/missing" body="{\"message\":\"not found\"}"`, srv.URL), err.Error())
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		_, err := get(t, cli, "conflict")
+		assert.True(t, IsConflict(err))
+		assert.False(t, errcode.IsNotFound(err))
+	})
+}
+
+func TestClient_GetAuthenticatedUser(t *testing.T) {
+	cli := NewTestClient(t, "GetAuthenticatedUser", *update)
+
+	user, err := cli.GetAuthenticatedUser(context.Background())
+	require.NoError(t, err)
+
+	testutil.AssertGolden(t, "testdata/golden/GetAuthenticatedUser.json", *update, user)
+}
+
+func TestWorkInProgress(t *testing.T) {
+	for title, want := range map[string]bool{
+		"Fix the thing":       false,
+		"WIP: Fix the thing":  true,
+		"wip: Fix the thing":  true,
+		"[WIP] Fix the thing": true,
+		"Fix the WIP: thing":  false,
+	} {
+		pr := &PullRequest{Title: title}
+		assert.Equal(t, want, pr.IsWorkInProgress(), title)
+	}
+
+	assert.Equal(t, "WIP: Fix the thing", SetWorkInProgress("Fix the thing"))
+	assert.Equal(t, "[WIP] Fix the thing", SetWorkInProgress("[WIP] Fix the thing"))
+	assert.Equal(t, "Fix the thing", UnsetWorkInProgress("WIP: Fix the thing"))
+	assert.Equal(t, "Fix the thing", UnsetWorkInProgress("[wip] Fix the thing"))
+	assert.Equal(t, "Fix the thing", UnsetWorkInProgress("Fix the thing"))
+}
@@ -0,0 +1,18 @@
+package gitea
+
+import (
+	"flag"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+	os.Exit(m.Run())
+}
+
+// The cassettes in testdata/vcr were written by hand from the Gitea API
+// documentation instead of being recorded. Record them with -update against
+// the instance in GITEA_URL (gitea.sgdev.org by default) using a GITEA_TOKEN
+// that can read and open pull requests on sourcegraph/go-diff.
+var update = flag.Bool("update", false, "update testdata")
@@ -0,0 +1,163 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// GetPullRequest returns the pull request with the given index.
+func (c *client) GetPullRequest(ctx context.Context, owner, name string, index int64) (*PullRequest, error) {
+	req, err := http.NewRequest("GET", pullRequestPath(owner, name, index), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if _, err = c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// GetPullRequestByBranches returns the pull request merging head into base.
+func (c *client) GetPullRequestByBranches(ctx context.Context, owner, name, base, head string) (*PullRequest, error) {
+	req, err := http.NewRequest("GET", repoPath(owner, name)+"/pulls/"+url.PathEscape(base)+"/"+url.PathEscape(head), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if _, err = c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// CreatePullRequest creates a pull request. If a pull request for the same
+// branches already exists, an error for which IsConflict returns true is
+// returned.
+func (c *client) CreatePullRequest(ctx context.Context, owner, name string, input CreatePullRequestInput) (*PullRequest, error) {
+	data, err := json.Marshal(&input)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling request")
+	}
+
+	req, err := http.NewRequest("POST", repoPath(owner, name)+"/pulls", bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if _, err = c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// EditPullRequest updates a pull request, which is also used to close and
+// reopen it.
+func (c *client) EditPullRequest(ctx context.Context, owner, name string, index int64, input EditPullRequestInput) (*PullRequest, error) {
+	data, err := json.Marshal(&input)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling request")
+	}
+
+	req, err := http.NewRequest("PATCH", pullRequestPath(owner, name, index), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var pr PullRequest
+	if _, err = c.do(ctx, req, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// MergePullRequest merges a pull request. If the pull request cannot be
+// merged, an error for which IsNotMergeable returns true is returned.
+func (c *client) MergePullRequest(ctx context.Context, owner, name string, index int64, input MergePullRequestInput) error {
+	data, err := json.Marshal(&input)
+	if err != nil {
+		return errors.Wrap(err, "marshalling request")
+	}
+
+	req, err := http.NewRequest("POST", pullRequestPath(owner, name, index)+"/merge", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, req, nil)
+	return err
+}
+
+// ListPullRequestReviews returns all reviews of a pull request, oldest first.
+func (c *client) ListPullRequestReviews(ctx context.Context, owner, name string, index int64) ([]*Review, error) {
+	return listAll[*Review](ctx, c, pullRequestPath(owner, name, index)+"/reviews", nil)
+}
+
+// ListCommitStatuses returns all statuses reported for ref, newest first.
+// Statuses reported again for the same context are included, so callers
+// usually only look at the newest status of each context.
+func (c *client) ListCommitStatuses(ctx context.Context, owner, name, ref string) ([]*CommitStatus, error) {
+	qs := url.Values{"sort": []string{"newest"}}
+	return listAll[*CommitStatus](ctx, c, repoPath(owner, name)+"/commits/"+url.PathEscape(ref)+"/statuses", qs)
+}
+
+// CreateComment posts a comment on a pull request.
+func (c *client) CreateComment(ctx context.Context, owner, name string, index int64, body string) error {
+	data, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return errors.Wrap(err, "marshalling request")
+	}
+
+	// Pull requests are issues in Gitea, and share their comments.
+	req, err := http.NewRequest("POST", repoPath(owner, name)+"/issues/"+strconv.FormatInt(index, 10)+"/comments", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, req, nil)
+	return err
+}
+
+// listAll requests all pages of a list endpoint, until an empty page is
+// returned.
+func listAll[T any](ctx context.Context, c *client, path string, qs url.Values) ([]T, error) {
+	if qs == nil {
+		qs = make(url.Values)
+	}
+	qs.Set("limit", strconv.Itoa(defaultPerPage))
+
+	var all []T
+	for page := 1; ; page++ {
+		qs.Set("page", strconv.Itoa(page))
+		req, err := http.NewRequest("GET", path+"?"+qs.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var items []T
+		if _, err = c.do(ctx, req, &items); err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			return all, nil
+		}
+		all = append(all, items...)
+	}
+}
+
+func pullRequestPath(owner, name string, index int64) string {
+	return repoPath(owner, name) + "/pulls/" + strconv.FormatInt(index, 10)
+}
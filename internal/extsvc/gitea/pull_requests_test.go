@@ -0,0 +1,54 @@
+package gitea
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+)
+
+func TestClient_PullRequests(t *testing.T) {
+	cli := NewTestClient(t, "PullRequests", *update)
+
+	ctx := context.Background()
+
+	pr, err := cli.GetPullRequest(ctx, "sourcegraph", "go-diff", 7)
+	require.NoError(t, err)
+
+	reviews, err := cli.ListPullRequestReviews(ctx, "sourcegraph", "go-diff", 7)
+	require.NoError(t, err)
+
+	statuses, err := cli.ListCommitStatuses(ctx, "sourcegraph", "go-diff", pr.Head.SHA)
+	require.NoError(t, err)
+
+	testutil.AssertGolden(t, "testdata/golden/PullRequests.json", *update, map[string]any{
+		"pullRequest": pr,
+		"reviews":     reviews,
+		"statuses":    statuses,
+	})
+}
+
+func TestClient_CreatePullRequest(t *testing.T) {
+	cli := NewTestClient(t, "CreatePullRequest", *update)
+
+	ctx := context.Background()
+	input := CreatePullRequestInput{
+		Title: "Update README",
+		Body:  "Created by a batch change.",
+		Head:  "batch-update-readme",
+		Base:  "main",
+	}
+
+	pr, err := cli.CreatePullRequest(ctx, "sourcegraph", "go-diff", input)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), pr.Number)
+
+	_, err = cli.CreatePullRequest(ctx, "sourcegraph", "go-diff", input)
+	require.True(t, IsConflict(err), "got %v", err)
+
+	existing, err := cli.GetPullRequestByBranches(ctx, "sourcegraph", "go-diff", "main", "batch-update-readme")
+	require.NoError(t, err)
+	require.Equal(t, pr.Number, existing.Number)
+}
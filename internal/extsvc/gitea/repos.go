@@ -0,0 +1,118 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+// defaultPerPage is the default maximum page size of Gitea, larger pages are
+// truncated by the server.
+const defaultPerPage = 50
+
+// ListReposArgs defines options to be set on ListRepos method calls.
+type ListReposArgs struct {
+	// Org lists the repositories of the organization. If empty, all
+	// repositories visible to the authenticated user are listed.
+	Org string
+	// PerPage is the page size, which defaults to 50.
+	PerPage int
+}
+
+// searchReposResponse is the response of the repository search endpoint.
+type searchReposResponse struct {
+	OK    bool          `json:"ok"`
+	Data  []*Repository `json:"data"`
+	Error string        `json:"error"`
+}
+
+// ListRepos lists repositories page by page. Since Gitea caps the page size,
+// the iterator only stops once an empty page is returned.
+func (c *client) ListRepos(ctx context.Context, opts ListReposArgs) *iterator.Iterator[*Repository] {
+	perPage := opts.PerPage
+	if perPage <= 0 {
+		perPage = defaultPerPage
+	}
+
+	page := 1
+	return iterator.New(func() ([]*Repository, error) {
+		qs := make(url.Values)
+		qs.Set("page", strconv.Itoa(page))
+		qs.Set("limit", strconv.Itoa(perPage))
+
+		var repos []*Repository
+		if opts.Org != "" {
+			u := url.URL{Path: "api/v1/orgs/" + opts.Org + "/repos", RawQuery: qs.Encode()}
+			req, err := http.NewRequest("GET", u.String(), nil)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = c.do(ctx, req, &repos); err != nil {
+				return nil, err
+			}
+		} else {
+			u := url.URL{Path: "api/v1/repos/search", RawQuery: qs.Encode()}
+			req, err := http.NewRequest("GET", u.String(), nil)
+			if err != nil {
+				return nil, err
+			}
+			var resp searchReposResponse
+			if _, err = c.do(ctx, req, &resp); err != nil {
+				return nil, err
+			}
+			if !resp.OK {
+				return nil, errors.Errorf("searching repositories: %s", resp.Error)
+			}
+			repos = resp.Data
+		}
+
+		page++
+		return repos, nil
+	})
+}
+
+// GetRepo returns the repository with the given owner and name.
+func (c *client) GetRepo(ctx context.Context, owner, name string) (*Repository, error) {
+	req, err := http.NewRequest("GET", repoPath(owner, name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var repo Repository
+	if _, err = c.do(ctx, req, &repo); err != nil {
+		return nil, err
+	}
+
+	return &repo, nil
+}
+
+// CreateFork forks the repository with the given owner and name, and returns
+// the fork.
+func (c *client) CreateFork(ctx context.Context, owner, name string, input CreateForkInput) (*Repository, error) {
+	data, err := json.Marshal(&input)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling request")
+	}
+
+	req, err := http.NewRequest("POST", repoPath(owner, name)+"/forks", bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var fork Repository
+	if _, err = c.do(ctx, req, &fork); err != nil {
+		return nil, err
+	}
+
+	return &fork, nil
+}
+
+func repoPath(owner, name string) string {
+	return (&url.URL{Path: "api/v1/repos/" + owner + "/" + name}).String()
+}
@@ -0,0 +1,42 @@
+package gitea
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+func TestClient_ListRepos(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args ListReposArgs
+	}{
+		{name: "ListRepos", args: ListReposArgs{PerPage: 2}},
+		{name: "ListRepos org", args: ListReposArgs{Org: "sourcegraph", PerPage: 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := NewTestClient(t, tc.name, *update)
+
+			repos, err := iterator.Collect(cli.ListRepos(context.Background(), tc.args))
+			require.NoError(t, err)
+
+			testutil.AssertGolden(t, "testdata/golden/"+normalize(tc.name)+".json", *update, repos)
+		})
+	}
+}
+
+func TestClient_GetRepo(t *testing.T) {
+	cli := NewTestClient(t, "GetRepo", *update)
+
+	repo, err := cli.GetRepo(context.Background(), "alice", "go-diff")
+	require.NoError(t, err)
+	require.True(t, repo.Fork)
+	require.Equal(t, "sourcegraph/go-diff", repo.Parent.FullName)
+
+	_, err = cli.GetRepo(context.Background(), "alice", "missing")
+	require.Error(t, err)
+}
@@ -0,0 +1,281 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// User is a Gitea user or organization.
+type User struct {
+	ID        int64  `json:"id"`
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	IsAdmin   bool   `json:"is_admin"`
+}
+
+// Repository is a Gitea repository.
+type Repository struct {
+	ID            int64                  `json:"id"`
+	Owner         *User                  `json:"owner"`
+	Name          string                 `json:"name"`
+	FullName      string                 `json:"full_name"`
+	Description   string                 `json:"description"`
+	Empty         bool                   `json:"empty"`
+	Private       bool                   `json:"private"`
+	Internal      bool                   `json:"internal"`
+	Fork          bool                   `json:"fork"`
+	Parent        *Repository            `json:"parent,omitempty"`
+	Mirror        bool                   `json:"mirror"`
+	Archived      bool                   `json:"archived"`
+	HTMLURL       string                 `json:"html_url"`
+	SSHURL        string                 `json:"ssh_url"`
+	CloneURL      string                 `json:"clone_url"`
+	DefaultBranch string                 `json:"default_branch"`
+	StarsCount    int                    `json:"stars_count"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	Permissions   *RepositoryPermissions `json:"permissions,omitempty"`
+}
+
+// RepositoryPermissions are the permissions the authenticated user has on a
+// repository.
+type RepositoryPermissions struct {
+	Admin bool `json:"admin"`
+	Push  bool `json:"push"`
+	Pull  bool `json:"pull"`
+}
+
+// Namespace returns the owner of the repository, which is either a user or an
+// organization.
+func (r *Repository) Namespace() string {
+	if r.Owner != nil {
+		return r.Owner.Login
+	}
+	namespace, _, _ := strings.Cut(r.FullName, "/")
+	return namespace
+}
+
+// PullRequestState is the state of a pull request. Merged pull requests are
+// closed and have Merged set.
+type PullRequestState string
+
+const (
+	PullRequestStateOpen   PullRequestState = "open"
+	PullRequestStateClosed PullRequestState = "closed"
+)
+
+// PullRequest is a Gitea pull request.
+type PullRequest struct {
+	ID             int64            `json:"id"`
+	Number         int64            `json:"number"`
+	User           *User            `json:"user"`
+	Title          string           `json:"title"`
+	Body           string           `json:"body"`
+	Labels         []*Label         `json:"labels"`
+	State          PullRequestState `json:"state"`
+	HTMLURL        string           `json:"html_url"`
+	Mergeable      bool             `json:"mergeable"`
+	Merged         bool             `json:"merged"`
+	MergedAt       *time.Time       `json:"merged_at"`
+	MergedBy       *User            `json:"merged_by"`
+	MergeCommitSHA *string          `json:"merge_commit_sha"`
+	Base           *PRBranchInfo    `json:"base"`
+	Head           *PRBranchInfo    `json:"head"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	ClosedAt       *time.Time       `json:"closed_at"`
+}
+
+// workInProgressPrefixes are the title prefixes Gitea uses by default to mark
+// pull requests as work in progress, which is how drafts are represented.
+var workInProgressPrefixes = []string{"WIP:", "[WIP]"}
+
+// IsWorkInProgress returns true if the pull request is a draft.
+func (pr *PullRequest) IsWorkInProgress() bool {
+	title := strings.ToUpper(strings.TrimSpace(pr.Title))
+	for _, prefix := range workInProgressPrefixes {
+		if strings.HasPrefix(title, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetWorkInProgress returns title prefixed to mark a pull request as a draft,
+// unless it already is.
+func SetWorkInProgress(title string) string {
+	if (&PullRequest{Title: title}).IsWorkInProgress() {
+		return title
+	}
+	return workInProgressPrefixes[0] + " " + title
+}
+
+// UnsetWorkInProgress returns title without the prefix marking a pull request
+// as a draft.
+func UnsetWorkInProgress(title string) string {
+	trimmed := strings.TrimSpace(title)
+	for _, prefix := range workInProgressPrefixes {
+		if len(trimmed) >= len(prefix) && strings.EqualFold(trimmed[:len(prefix)], prefix) {
+			return strings.TrimSpace(trimmed[len(prefix):])
+		}
+	}
+	return title
+}
+
+// PRBranchInfo is the base or head of a pull request.
+type PRBranchInfo struct {
+	Label      string      `json:"label"`
+	Ref        string      `json:"ref"`
+	SHA        string      `json:"sha"`
+	RepoID     int64       `json:"repo_id"`
+	Repository *Repository `json:"repo"`
+}
+
+// Label is a label of an issue or pull request.
+type Label struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// ReviewState is the state of a pull request review.
+type ReviewState string
+
+const (
+	ReviewStateApproved       ReviewState = "APPROVED"
+	ReviewStatePending        ReviewState = "PENDING"
+	ReviewStateComment        ReviewState = "COMMENT"
+	ReviewStateRequestChanges ReviewState = "REQUEST_CHANGES"
+	ReviewStateRequestReview  ReviewState = "REQUEST_REVIEW"
+)
+
+// Review is a review of a pull request.
+type Review struct {
+	ID          int64       `json:"id"`
+	User        *User       `json:"user"`
+	Body        string      `json:"body"`
+	CommitID    string      `json:"commit_id"`
+	State       ReviewState `json:"state"`
+	Stale       bool        `json:"stale"`
+	Official    bool        `json:"official"`
+	Dismissed   bool        `json:"dismissed"`
+	HTMLURL     string      `json:"html_url"`
+	SubmittedAt time.Time   `json:"submitted_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// Key is a unique key identifying this review in the context of its pull
+// request.
+func (r *Review) Key() string {
+	return strconv.FormatInt(r.ID, 10)
+}
+
+// CommitStatusState is the state of a commit status.
+type CommitStatusState string
+
+const (
+	CommitStatusPending CommitStatusState = "pending"
+	CommitStatusSuccess CommitStatusState = "success"
+	CommitStatusError   CommitStatusState = "error"
+	CommitStatusFailure CommitStatusState = "failure"
+	CommitStatusWarning CommitStatusState = "warning"
+)
+
+// CommitStatus is a status reported for a commit, usually by CI.
+type CommitStatus struct {
+	ID          int64             `json:"id"`
+	State       CommitStatusState `json:"status"`
+	TargetURL   string            `json:"target_url"`
+	Description string            `json:"description"`
+	Context     string            `json:"context"`
+	Creator     *User             `json:"creator"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Key is a unique key identifying this commit status in the context of its
+// pull request.
+func (s *CommitStatus) Key() string {
+	return strconv.FormatInt(s.ID, 10)
+}
+
+// CreatePullRequestInput is the payload to create a pull request. Head is the
+// branch to merge, prefixed with "owner:" when it lives in a fork.
+type CreatePullRequestInput struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+}
+
+// EditPullRequestInput is the payload to update a pull request. Only non-nil
+// fields are changed.
+type EditPullRequestInput struct {
+	Title *string           `json:"title,omitempty"`
+	Body  *string           `json:"body,omitempty"`
+	Base  *string           `json:"base,omitempty"`
+	State *PullRequestState `json:"state,omitempty"`
+}
+
+// MergeStyle is the way a pull request is merged.
+type MergeStyle string
+
+const (
+	MergeStyleMerge  MergeStyle = "merge"
+	MergeStyleSquash MergeStyle = "squash"
+)
+
+// MergePullRequestInput is the payload to merge a pull request.
+type MergePullRequestInput struct {
+	Do                     MergeStyle `json:"Do"`
+	HeadCommitID           string     `json:"head_commit_id,omitempty"`
+	DeleteBranchAfterMerge bool       `json:"delete_branch_after_merge,omitempty"`
+}
+
+// CreateForkInput is the payload to fork a repository. An empty Organization
+// forks into the namespace of the authenticated user.
+type CreateForkInput struct {
+	Organization string `json:"organization,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gitea API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
+
+// IsConflict returns true if err is an HTTP 409 error, which Gitea returns
+// when creating a pull request that already exists.
+func IsConflict(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && e.StatusCode == http.StatusConflict
+}
+
+// IsNotMergeable returns true if err is the HTTP 405 error Gitea returns when
+// a pull request cannot be merged.
+func IsNotMergeable(err error) bool {
+	var e *httpError
+	return errors.As(err, &e) && e.StatusCode == http.StatusMethodNotAllowed
+}
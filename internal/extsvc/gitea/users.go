@@ -0,0 +1,45 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// GetAuthenticatedUser returns the user the client is authenticated as.
+func (c *client) GetAuthenticatedUser(ctx context.Context) (*User, error) {
+	req, err := http.NewRequest("GET", "api/v1/user", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if _, err = c.do(ctx, req, &user); err != nil {
+		if httpErr := (&httpError{}); errors.As(err, &httpErr) {
+			if httpErr.Unauthorized() {
+				return nil, errors.New("Invalid access token.")
+			}
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetUser returns the user with the given login.
+func (c *client) GetUser(ctx context.Context, login string) (*User, error) {
+	u := url.URL{Path: "api/v1/users/" + login}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if _, err = c.do(ctx, req, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
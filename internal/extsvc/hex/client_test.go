@@ -0,0 +1,46 @@
+package hex
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+)
+
+func TestGetPackageContents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/tarballs/phoenix-1.7.10.tar" {
+			w.Write([]byte("tarball contents"))
+			return
+		}
+		// repo.hex.pm is served from S3, which responds with 403 for unknown keys.
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := NewClient("urn", server.URL+"/", httpcli.TestExternalClientFactory)
+	require.NoError(t, err)
+	client.limiter = ratelimit.NewInstrumentedLimiter("hex", rate.NewLimiter(100, 10))
+
+	body, err := client.GetPackageContents(ctx, reposource.ParseHexVersionedPackage("phoenix@1.7.10"))
+	require.NoError(t, err)
+	defer body.Close()
+	contents, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "tarball contents", string(contents))
+
+	_, err = client.GetPackageContents(ctx, reposource.ParseHexVersionedPackage("phoenix@0.0.1"))
+	require.Error(t, err)
+	assert.True(t, errcode.IsNotFound(err))
+}
@@ -0,0 +1,152 @@
+package nuget
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// packageBaseAddressType is the type of the service index resource that serves
+// .nupkg files. See https://learn.microsoft.com/en-us/nuget/api/package-base-address-resource.
+const packageBaseAddressType = "PackageBaseAddress/3.0.0"
+
+// DefaultServiceIndexURL is the service index of nuget.org, which is used if
+// no other feed is configured.
+const DefaultServiceIndexURL = "https://api.nuget.org/v3/index.json"
+
+type Client struct {
+	// serviceIndexURL is the URL of the V3 service index of the package feed,
+	// e.g. https://api.nuget.org/v3/index.json.
+	serviceIndexURL string
+
+	uncachedClient httpcli.Doer
+
+	// Self-imposed rate-limiter.
+	limiter *ratelimit.InstrumentedLimiter
+
+	// The package base address is resolved from the service index on first
+	// use and then reused.
+	mu                 sync.Mutex
+	packageBaseAddress string
+}
+
+func NewClient(urn string, serviceIndexURL string, httpfactory *httpcli.Factory) (*Client, error) {
+	uncached, err := httpfactory.Doer(httpcli.NewCachedTransportOpt(httpcli.NoopCache{}, false))
+	if err != nil {
+		return nil, err
+	}
+	if serviceIndexURL == "" {
+		serviceIndexURL = DefaultServiceIndexURL
+	}
+	return &Client{
+		serviceIndexURL: serviceIndexURL,
+		uncachedClient:  uncached,
+		limiter:         ratelimit.NewInstrumentedLimiter(urn, ratelimit.NewGlobalRateLimiter(log.Scoped("NuGetClient"), urn)),
+	}, nil
+}
+
+// GetPackageContents returns the contents of the .nupkg file of the given
+// package, which is a zip archive.
+func (c *Client) GetPackageContents(ctx context.Context, dep reposource.VersionedPackage) (body io.ReadCloser, err error) {
+	baseAddress, err := c.getPackageBaseAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The package base address resource only accepts lowercased IDs and versions.
+	id := strings.ToLower(string(dep.PackageSyntax()))
+	version := strings.ToLower(dep.PackageVersion())
+	url := fmt.Sprintf("%s/%s/%s/%s.%s.nupkg", strings.TrimSuffix(baseAddress, "/"), id, version, id, version)
+
+	return c.get(ctx, url)
+}
+
+type serviceIndex struct {
+	Resources []struct {
+		ID   string `json:"@id"`
+		Type string `json:"@type"`
+	} `json:"resources"`
+}
+
+func (c *Client) getPackageBaseAddress(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.packageBaseAddress != "" {
+		return c.packageBaseAddress, nil
+	}
+
+	body, err := c.get(ctx, c.serviceIndexURL)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch NuGet service index")
+	}
+	defer body.Close()
+
+	var index serviceIndex
+	if err := json.NewDecoder(body).Decode(&index); err != nil {
+		return "", errors.Wrap(err, "failed to decode NuGet service index")
+	}
+	for _, r := range index.Resources {
+		if r.Type == packageBaseAddressType {
+			c.packageBaseAddress = r.ID
+			return r.ID, nil
+		}
+	}
+	return "", errors.Newf("NuGet service index %s has no %s resource", c.serviceIndexURL, packageBaseAddressType)
+}
+
+func (c *Client) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", "sourcegraph-nuget-syncer (sourcegraph.com)")
+
+	return c.do(c.uncachedClient, req)
+}
+
+type Error struct {
+	path    string
+	code    int
+	message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("bad response with status code %d for %s: %s", e.code, e.path, e.message)
+}
+
+func (e *Error) NotFound() bool {
+	return e.code == http.StatusNotFound
+}
+
+func (c *Client) do(doer httpcli.Doer, req *http.Request) (io.ReadCloser, error) {
+	resp, err := doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		bs, err := io.ReadAll(resp.Body)
+		if err != nil {
+			bs = []byte(errors.Wrap(err, "failed to read body").Error())
+		}
+		return nil, &Error{path: req.URL.Path, code: resp.StatusCode, message: string(bs)}
+	}
+	return resp.Body, nil
+}
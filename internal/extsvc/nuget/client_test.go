@@ -0,0 +1,75 @@
+package nuget
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+)
+
+func TestGetPackageContents(t *testing.T) {
+	var serviceIndexRequests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v3/index.json":
+			serviceIndexRequests++
+			fmt.Fprintf(w, `{
+				"version": "3.0.0",
+				"resources": [
+					{"@id": "%[1]s/query", "@type": "SearchQueryService"},
+					{"@id": "%[1]s/v3-flatcontainer/", "@type": "PackageBaseAddress/3.0.0"}
+				]
+			}`, server.URL)
+		case "/v3-flatcontainer/newtonsoft.json/13.0.3/newtonsoft.json.13.0.3.nupkg":
+			w.Write([]byte("nupkg contents"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := NewClient("urn", server.URL+"/v3/index.json", httpcli.TestExternalClientFactory)
+	require.NoError(t, err)
+	client.limiter = ratelimit.NewInstrumentedLimiter("nuget", rate.NewLimiter(100, 10))
+
+	for i := 0; i < 2; i++ {
+		body, err := client.GetPackageContents(ctx, reposource.ParseNuGetVersionedPackage("Newtonsoft.Json@13.0.3"))
+		require.NoError(t, err)
+		contents, err := io.ReadAll(body)
+		require.NoError(t, err)
+		require.NoError(t, body.Close())
+		assert.Equal(t, "nupkg contents", string(contents))
+	}
+	assert.Equal(t, 1, serviceIndexRequests, "service index must only be fetched once")
+
+	_, err = client.GetPackageContents(ctx, reposource.ParseNuGetVersionedPackage("Newtonsoft.Json@0.0.1"))
+	require.Error(t, err)
+	assert.True(t, errcode.IsNotFound(err))
+}
+
+func TestGetPackageContents_NoPackageBaseAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(`{"version": "3.0.0", "resources": []}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("urn", server.URL+"/v3/index.json", httpcli.TestExternalClientFactory)
+	require.NoError(t, err)
+	client.limiter = ratelimit.NewInstrumentedLimiter("nuget", rate.NewLimiter(100, 10))
+
+	_, err = client.GetPackageContents(context.Background(), reposource.ParseNuGetVersionedPackage("Newtonsoft.Json@13.0.3"))
+	require.ErrorContains(t, err, "has no PackageBaseAddress/3.0.0 resource")
+}
@@ -0,0 +1,179 @@
+package packagist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// DefaultRepositoryURL is the URL of packagist.org, which is used if no other
+// Composer repository is configured.
+const DefaultRepositoryURL = "https://repo.packagist.org"
+
+type Client struct {
+	repositoryURL string
+
+	uncachedClient httpcli.Doer
+
+	// Self-imposed rate-limiter.
+	limiter *ratelimit.InstrumentedLimiter
+}
+
+func NewClient(urn string, repositoryURL string, httpfactory *httpcli.Factory) (*Client, error) {
+	uncached, err := httpfactory.Doer(httpcli.NewCachedTransportOpt(httpcli.NoopCache{}, false))
+	if err != nil {
+		return nil, err
+	}
+	if repositoryURL == "" {
+		repositoryURL = DefaultRepositoryURL
+	}
+	return &Client{
+		repositoryURL:  repositoryURL,
+		uncachedClient: uncached,
+		limiter:        ratelimit.NewInstrumentedLimiter(urn, ratelimit.NewGlobalRateLimiter(log.Scoped("PackagistClient"), urn)),
+	}, nil
+}
+
+// Version is a single version of a package in the metadata of a Composer
+// repository.
+type Version struct {
+	Version string `json:"version"`
+	Dist    *Dist  `json:"dist"`
+}
+
+// Dist describes the archive that a version of a package is distributed as.
+type Dist struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// GetVersion returns the metadata of the given version of a package.
+func (c *Client) GetVersion(ctx context.Context, dep reposource.VersionedPackage) (*Version, error) {
+	// The metadata of tagged releases is served by the Composer v2 metadata
+	// API. See https://packagist.org/apidoc#get-package-metadata-v2.
+	url := fmt.Sprintf("%s/p2/%s.json", strings.TrimSuffix(c.repositoryURL, "/"), dep.PackageSyntax())
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var metadata struct {
+		Packages map[string][]map[string]json.RawMessage `json:"packages"`
+	}
+	if err := json.NewDecoder(body).Decode(&metadata); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode metadata of %s", dep.PackageSyntax())
+	}
+
+	want := strings.TrimPrefix(dep.PackageVersion(), "v")
+	for _, fields := range expandMinifiedVersions(metadata.Packages[string(dep.PackageSyntax())]) {
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		var v Version
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode metadata of %s", dep.PackageSyntax())
+		}
+		if strings.TrimPrefix(v.Version, "v") == want {
+			return &v, nil
+		}
+	}
+
+	return nil, &Error{path: url, code: http.StatusNotFound, message: fmt.Sprintf("version %s not found", dep.PackageVersion())}
+}
+
+// expandMinifiedVersions expands versions in the minified format of the
+// Composer v2 metadata API, in which every version only contains the fields
+// that differ from the previous version. Fields that are removed have the
+// value "__unset".
+func expandMinifiedVersions(versions []map[string]json.RawMessage) []map[string]json.RawMessage {
+	expanded := make([]map[string]json.RawMessage, 0, len(versions))
+	prev := map[string]json.RawMessage{}
+	for _, v := range versions {
+		cur := make(map[string]json.RawMessage, len(prev))
+		for k, field := range prev {
+			cur[k] = field
+		}
+		for k, field := range v {
+			if string(field) == `"__unset"` {
+				delete(cur, k)
+			} else {
+				cur[k] = field
+			}
+		}
+		expanded = append(expanded, cur)
+		prev = cur
+	}
+	return expanded
+}
+
+// GetPackageContents returns the zip archive of the given version of a package.
+func (c *Client) GetPackageContents(ctx context.Context, dep reposource.VersionedPackage) (io.ReadCloser, error) {
+	v, err := c.GetVersion(ctx, dep)
+	if err != nil {
+		return nil, err
+	}
+	if v.Dist == nil || v.Dist.URL == "" {
+		return nil, errors.Newf("%s has no dist archive", dep.VersionedPackageSyntax())
+	}
+	if v.Dist.Type != "zip" {
+		return nil, errors.Newf("unsupported dist type %q for %s", v.Dist.Type, dep.VersionedPackageSyntax())
+	}
+	return c.get(ctx, v.Dist.URL)
+}
+
+func (c *Client) get(ctx context.Context, url string) (io.ReadCloser, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", "sourcegraph-packagist-syncer (sourcegraph.com)")
+
+	return c.do(c.uncachedClient, req)
+}
+
+type Error struct {
+	path    string
+	code    int
+	message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("bad response with status code %d for %s: %s", e.code, e.path, e.message)
+}
+
+func (e *Error) NotFound() bool {
+	return e.code == http.StatusNotFound
+}
+
+func (c *Client) do(doer httpcli.Doer, req *http.Request) (io.ReadCloser, error) {
+	resp, err := doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		bs, err := io.ReadAll(resp.Body)
+		if err != nil {
+			bs = []byte(errors.Wrap(err, "failed to read body").Error())
+		}
+		return nil, &Error{path: req.URL.Path, code: resp.StatusCode, message: string(bs)}
+	}
+	return resp.Body, nil
+}
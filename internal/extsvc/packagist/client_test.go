@@ -0,0 +1,97 @@
+package packagist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+)
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/p2/monolog/monolog.json":
+			// Versions are listed newest first in the minified format, in
+			// which each version only contains the fields that changed.
+			fmt.Fprintf(w, `{
+				"minified": "composer/2.0",
+				"packages": {
+					"monolog/monolog": [
+						{"name": "monolog/monolog", "version": "3.5.0", "dist": {"type": "zip", "url": "%[1]s/dist/monolog-3.5.0.zip"}, "extra": {"branch-alias": {"dev-main": "3.x-dev"}}},
+						{"version": "3.4.0", "dist": {"type": "zip", "url": "%[1]s/dist/monolog-3.4.0.zip"}, "extra": "__unset"},
+						{"version": "v1.0.0", "dist": {"type": "tar", "url": "%[1]s/dist/monolog-1.0.0.tar"}}
+					]
+				}
+			}`, server.URL)
+		case "/dist/monolog-3.4.0.zip":
+			w.Write([]byte("zip contents"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient("urn", server.URL, httpcli.TestExternalClientFactory)
+	require.NoError(t, err)
+	client.limiter = ratelimit.NewInstrumentedLimiter("packagist", rate.NewLimiter(100, 10))
+	return client
+}
+
+func parsePHPPackage(t *testing.T, dep string) *reposource.PHPVersionedPackage {
+	t.Helper()
+	pkg, err := reposource.ParsePHPVersionedPackage(dep)
+	require.NoError(t, err)
+	return pkg
+}
+
+func TestGetPackageContents(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+
+	body, err := client.GetPackageContents(ctx, parsePHPPackage(t, "monolog/monolog@3.4.0"))
+	require.NoError(t, err)
+	defer body.Close()
+	contents, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "zip contents", string(contents))
+
+	_, err = client.GetPackageContents(ctx, parsePHPPackage(t, "monolog/monolog@2.0.0"))
+	require.Error(t, err)
+	assert.True(t, errcode.IsNotFound(err))
+
+	_, err = client.GetPackageContents(ctx, parsePHPPackage(t, "monolog/monolog@1.0.0"))
+	require.ErrorContains(t, err, `unsupported dist type "tar"`)
+
+	_, err = client.GetPackageContents(ctx, parsePHPPackage(t, "acme/unknown@1.0.0"))
+	require.Error(t, err)
+	assert.True(t, errcode.IsNotFound(err))
+}
+
+func TestExpandMinifiedVersions(t *testing.T) {
+	versions := []map[string]json.RawMessage{
+		{"name": json.RawMessage(`"monolog/monolog"`), "version": json.RawMessage(`"3.5.0"`), "extra": json.RawMessage(`{}`)},
+		{"version": json.RawMessage(`"3.4.0"`), "extra": json.RawMessage(`"__unset"`)},
+		{"version": json.RawMessage(`"3.3.0"`)},
+	}
+
+	assert.Equal(t, []map[string]json.RawMessage{
+		{"name": json.RawMessage(`"monolog/monolog"`), "version": json.RawMessage(`"3.5.0"`), "extra": json.RawMessage(`{}`)},
+		{"name": json.RawMessage(`"monolog/monolog"`), "version": json.RawMessage(`"3.4.0"`)},
+		{"name": json.RawMessage(`"monolog/monolog"`), "version": json.RawMessage(`"3.3.0"`)},
+	}, expandMinifiedVersions(versions))
+}
@@ -207,6 +207,19 @@ const (
 	// VariantRubyPackages is the (api.ExternalRepoSpec).ServiceType value for Ruby packages.
 	VariantRubyPackages
 
+	// VariantNuGetPackages is the (api.ExternalRepoSpec).ServiceType value for NuGet packages (.NET ecosystem libraries).
+	VariantNuGetPackages
+
+	// VariantPHPPackages is the (api.ExternalRepoSpec).ServiceType value for PHP packages served by Composer repositories such as Packagist.
+	VariantPHPPackages
+
+	// VariantHexPackages is the (api.ExternalRepoSpec).ServiceType value for Hex packages (Erlang/Elixir ecosystem libraries).
+	VariantHexPackages
+
+	// VariantGitea is the (api.ExternalRepoSpec).ServiceType value for Gitea and Forgejo
+	// repositories. The ServiceID value is the base URL to the Gitea instance.
+	VariantGitea
+
 	// VariantOther is the (api.ExternalRepoSpec).ServiceType value for other projects.
 	VariantOther
 )
@@ -225,16 +238,20 @@ var variantValuesMap = map[Variant]variantValues{
 	VariantBitbucketCloud:  {AsKind: "BITBUCKETCLOUD", AsType: "bitbucketCloud", ConfigPrototype: func() any { return &schema.BitbucketCloudConnection{} }, WebhookURLPath: "bitbucket-cloud-webhooks", SupportsRepoExclusion: true},
 	VariantBitbucketServer: {AsKind: "BITBUCKETSERVER", AsType: "bitbucketServer", ConfigPrototype: func() any { return &schema.BitbucketServerConnection{} }, WebhookURLPath: "bitbucket-server-webhooks", SupportsRepoExclusion: true},
 	VariantGerrit:          {AsKind: "GERRIT", AsType: "gerrit", ConfigPrototype: func() any { return &schema.GerritConnection{} }},
+	VariantGitea:           {AsKind: "GITEA", AsType: "gitea", ConfigPrototype: func() any { return &schema.GiteaConnection{} }, SupportsRepoExclusion: true},
 	VariantGitHub:          {AsKind: "GITHUB", AsType: "github", ConfigPrototype: func() any { return &schema.GitHubConnection{} }, WebhookURLPath: "github-webhooks", SupportsRepoExclusion: true},
 	VariantGitLab:          {AsKind: "GITLAB", AsType: "gitlab", ConfigPrototype: func() any { return &schema.GitLabConnection{} }, WebhookURLPath: "gitlab-webhooks", SupportsRepoExclusion: true},
 	VariantGitolite:        {AsKind: "GITOLITE", AsType: "gitolite", ConfigPrototype: func() any { return &schema.GitoliteConnection{} }, SupportsRepoExclusion: true},
 	VariantGoPackages:      {AsKind: "GOMODULES", AsType: "goModules", ConfigPrototype: func() any { return &schema.GoModulesConnection{} }},
+	VariantHexPackages:     {AsKind: "HEXPACKAGES", AsType: "hexPackages", ConfigPrototype: func() any { return &schema.HexPackagesConnection{} }},
 	VariantJVMPackages:     {AsKind: "JVMPACKAGES", AsType: "jvmPackages", ConfigPrototype: func() any { return &schema.JVMPackagesConnection{} }},
 	VariantNpmPackages:     {AsKind: "NPMPACKAGES", AsType: "npmPackages", ConfigPrototype: func() any { return &schema.NpmPackagesConnection{} }},
+	VariantNuGetPackages:   {AsKind: "NUGETPACKAGES", AsType: "nugetPackages", ConfigPrototype: func() any { return &schema.NuGetPackagesConnection{} }},
 	VariantOther:           {AsKind: "OTHER", AsType: "other", ConfigPrototype: func() any { return &schema.OtherExternalServiceConnection{} }},
 	VariantPagure:          {AsKind: "PAGURE", AsType: "pagure", ConfigPrototype: func() any { return &schema.PagureConnection{} }},
 	VariantPerforce:        {AsKind: "PERFORCE", AsType: "perforce", ConfigPrototype: func() any { return &schema.PerforceConnection{} }},
 	VariantPhabricator:     {AsKind: "PHABRICATOR", AsType: "phabricator", ConfigPrototype: func() any { return &schema.PhabricatorConnection{} }},
+	VariantPHPPackages:     {AsKind: "PHPPACKAGES", AsType: "phpPackages", ConfigPrototype: func() any { return &schema.PHPPackagesConnection{} }},
 	VariantPythonPackages:  {AsKind: "PYTHONPACKAGES", AsType: "pythonPackages", ConfigPrototype: func() any { return &schema.PythonPackagesConnection{} }},
 	VariantRubyPackages:    {AsKind: "RUBYPACKAGES", AsType: "rubyPackages", ConfigPrototype: func() any { return &schema.RubyPackagesConnection{} }},
 	VariantRustPackages:    {AsKind: "RUSTPACKAGES", AsType: "rustPackages", ConfigPrototype: func() any { return &schema.RustPackagesConnection{} }},
@@ -291,6 +308,7 @@ var (
 	KindBitbucketServer = VariantBitbucketServer.AsKind()
 	KindBitbucketCloud  = VariantBitbucketCloud.AsKind()
 	KindGerrit          = VariantGerrit.AsKind()
+	KindGitea           = VariantGitea.AsKind()
 	KindGitHub          = VariantGitHub.AsKind()
 	KindGitLab          = VariantGitLab.AsKind()
 	KindGitolite        = VariantGitolite.AsKind()
@@ -302,6 +320,9 @@ var (
 	KindRustPackages    = VariantRustPackages.AsKind()
 	KindRubyPackages    = VariantRubyPackages.AsKind()
 	KindNpmPackages     = VariantNpmPackages.AsKind()
+	KindNuGetPackages   = VariantNuGetPackages.AsKind()
+	KindPHPPackages     = VariantPHPPackages.AsKind()
+	KindHexPackages     = VariantHexPackages.AsKind()
 	KindPagure          = VariantPagure.AsKind()
 	KindAzureDevOps     = VariantAzureDevOps.AsKind()
 	KindSCIM            = VariantSCIM.AsKind()
@@ -328,6 +349,10 @@ var (
 	// TypeGerrit is the (api.ExternalRepoSpec).ServiceType value for Gerrit projects.
 	TypeGerrit = VariantGerrit.AsType()
 
+	// TypeGitea is the (api.ExternalRepoSpec).ServiceType value for Gitea and Forgejo repositories. The
+	// ServiceID value is the base URL to the Gitea instance.
+	TypeGitea = VariantGitea.AsType()
+
 	// TypeGitHub is the (api.ExternalRepoSpec).ServiceType value for GitHub repositories. The ServiceID value
 	// is the base URL to the GitHub instance (https://github.com or the GitHub Enterprise URL).
 	TypeGitHub = VariantGitHub.AsType()
@@ -369,6 +394,16 @@ var (
 	// TypeRubyPackages is the (api.ExternalRepoSpec).ServiceType value for Ruby packages.
 	TypeRubyPackages = VariantRubyPackages.AsType()
 
+	// TypeNuGetPackages is the (api.ExternalRepoSpec).ServiceType value for NuGet packages (.NET ecosystem libraries).
+	TypeNuGetPackages = VariantNuGetPackages.AsType()
+
+	// TypePHPPackages is the (api.ExternalRepoSpec).ServiceType value for PHP packages served by Composer
+	// repositories such as Packagist.
+	TypePHPPackages = VariantPHPPackages.AsType()
+
+	// TypeHexPackages is the (api.ExternalRepoSpec).ServiceType value for Hex packages (Erlang/Elixir ecosystem libraries).
+	TypeHexPackages = VariantHexPackages.AsType()
+
 	// TypeOther is the (api.ExternalRepoSpec).ServiceType value for other projects.
 	TypeOther = VariantOther.AsType()
 )
@@ -558,6 +593,8 @@ func extractToken(parsed any, kind string) (string, error) {
 		return c.Token, nil
 	case *schema.PagureConnection:
 		return c.Token, nil
+	case *schema.GiteaConnection:
+		return c.Token, nil
 	default:
 		return "", errors.Errorf("unable to extract token for service kind %q", kind)
 	}
@@ -638,6 +675,12 @@ func GetLimitFromConfig(config any, kind string) (limit rate.Limit, isDefault bo
 			isDefault = false
 			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
 		}
+	case *schema.GiteaConnection:
+		limit = GetDefaultRateLimit(KindGitea)
+		if c != nil && c.RateLimit != nil {
+			isDefault = false
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
 	case *schema.NpmPackagesConnection:
 		limit = GetDefaultRateLimit(KindNpmPackages)
 		if c != nil && c.RateLimit != nil {
@@ -668,6 +711,24 @@ func GetLimitFromConfig(config any, kind string) (limit rate.Limit, isDefault bo
 			isDefault = false
 			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
 		}
+	case *schema.NuGetPackagesConnection:
+		limit = GetDefaultRateLimit(KindNuGetPackages)
+		if c != nil && c.RateLimit != nil {
+			isDefault = false
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
+	case *schema.PHPPackagesConnection:
+		limit = GetDefaultRateLimit(KindPHPPackages)
+		if c != nil && c.RateLimit != nil {
+			isDefault = false
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
+	case *schema.HexPackagesConnection:
+		limit = GetDefaultRateLimit(KindHexPackages)
+		if c != nil && c.RateLimit != nil {
+			isDefault = false
+			limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+		}
 	default:
 		return limit, isDefault, ErrRateLimitUnsupported{codehostKind: kind}
 	}
@@ -695,6 +756,9 @@ func GetDefaultRateLimit(kind string) rate.Limit {
 	case KindPagure:
 		// 8/s is the default limit we enforce
 		return rate.Limit(8)
+	case KindGitea:
+		// 8/s is the default limit we enforce
+		return rate.Limit(8)
 	case KindNpmPackages:
 		return rate.Limit(6000 / 3600.0)
 	case KindGoPackages:
@@ -712,6 +776,10 @@ func GetDefaultRateLimit(kind string) rate.Limit {
 	case KindRubyPackages:
 		// The rubygems.org API allows 10 rps https://guides.rubygems.org/rubygems-org-rate-limits/
 		return rate.Limit(10)
+	case KindNuGetPackages, KindPHPPackages, KindHexPackages:
+		// Like pypi.org, neither nuget.org, packagist.org nor hex.pm document an
+		// enforced req/s rate limit for downloading packages.
+		return rate.Limit(57600.0 / 3600.0)
 	default:
 		return rate.Inf
 	}
@@ -839,8 +907,16 @@ func uniqueCodeHostIdentifier(kind string, cfg any) (string, error) {
 		return VariantRustPackages.AsKind(), nil
 	case *schema.RubyPackagesConnection:
 		return VariantRubyPackages.AsKind(), nil
+	case *schema.NuGetPackagesConnection:
+		return KindNuGetPackages, nil
+	case *schema.PHPPackagesConnection:
+		return KindPHPPackages, nil
+	case *schema.HexPackagesConnection:
+		return KindHexPackages, nil
 	case *schema.PagureConnection:
 		rawURL = c.Url
+	case *schema.GiteaConnection:
+		rawURL = c.Url
 	default:
 		return "", errors.Errorf("unknown external service kind: %s", kind)
 	}
@@ -37,6 +37,9 @@ func TestVariantConfigPrototypePointers(t *testing.T) {
 	if y, ok := VariantGerrit.ConfigPrototype().(*schema.GerritConnection); !ok {
 		t.Errorf("wrong type for Gerrit configuration prototype: %T", y)
 	}
+	if y, ok := VariantGitea.ConfigPrototype().(*schema.GiteaConnection); !ok {
+		t.Errorf("wrong type for Gitea configuration prototype: %T", y)
+	}
 	if y, ok := VariantGitHub.ConfigPrototype().(*schema.GitHubConnection); !ok {
 		t.Errorf("wrong type for GitHub configuration prototype: %T", y)
 	}
@@ -49,12 +52,18 @@ func TestVariantConfigPrototypePointers(t *testing.T) {
 	if y, ok := VariantGoPackages.ConfigPrototype().(*schema.GoModulesConnection); !ok {
 		t.Errorf("wrong type for Go Packages configuration prototype: %T", y)
 	}
+	if y, ok := VariantHexPackages.ConfigPrototype().(*schema.HexPackagesConnection); !ok {
+		t.Errorf("wrong type for Hex Packages configuration prototype: %T", y)
+	}
 	if y, ok := VariantJVMPackages.ConfigPrototype().(*schema.JVMPackagesConnection); !ok {
 		t.Errorf("wrong type for JVM Packages configuration prototype: %T", y)
 	}
 	if y, ok := VariantNpmPackages.ConfigPrototype().(*schema.NpmPackagesConnection); !ok {
 		t.Errorf("wrong type for NPM Packages configuration prototype: %T", y)
 	}
+	if y, ok := VariantNuGetPackages.ConfigPrototype().(*schema.NuGetPackagesConnection); !ok {
+		t.Errorf("wrong type for NuGet Packages configuration prototype: %T", y)
+	}
 	if y, ok := VariantOther.ConfigPrototype().(*schema.OtherExternalServiceConnection); !ok {
 		t.Errorf("wrong type for Other configuration prototype: %T", y)
 	}
@@ -67,6 +76,9 @@ func TestVariantConfigPrototypePointers(t *testing.T) {
 	if y, ok := VariantPhabricator.ConfigPrototype().(*schema.PhabricatorConnection); !ok {
 		t.Errorf("wrong type for Phabricator configuration prototype: %T", y)
 	}
+	if y, ok := VariantPHPPackages.ConfigPrototype().(*schema.PHPPackagesConnection); !ok {
+		t.Errorf("wrong type for PHP Packages configuration prototype: %T", y)
+	}
 	if y, ok := VariantPythonPackages.ConfigPrototype().(*schema.PythonPackagesConnection); !ok {
 		t.Errorf("wrong type for Python Packages configuration prototype: %T", y)
 	}
@@ -378,6 +390,11 @@ func TestUniqueCodeHostIdentifier(t *testing.T) {
 			config: `{"url": "https://example.com"}`,
 			want:   "https://example.com/",
 		},
+		{
+			kind:   KindGitea,
+			config: `{"url": "https://gitea.example.com"}`,
+			want:   "https://gitea.example.com/",
+		},
 		{
 			kind:   KindBitbucketServer,
 			config: `{"url": "https://bitbucket.sgdev.org/"}`,
@@ -40,6 +40,7 @@ func NewGitserverAddresses(cfg *conf.Unified) GitserverAddresses {
 	}
 	if cfg.ExperimentalFeatures != nil {
 		addrs.PinnedServers = cfg.ExperimentalFeatures.GitServerPinnedRepos
+		addrs.ReplicationFactor = cfg.ExperimentalFeatures.GitServerReplicationFactor
 	}
 	return addrs
 }
@@ -160,6 +161,10 @@ type GitserverAddresses struct {
 	// ensures that, even if the number of gitservers changes, these repos will
 	// not be moved.
 	PinnedServers map[string]string
+
+	// The number of gitserver instances that hold a copy of each repo. Values
+	// smaller than 2 disable replication.
+	ReplicationFactor int
 }
 
 // AddrForRepo returns the gitserver address to use for the given repo name.
@@ -175,6 +180,34 @@ func (g *GitserverAddresses) AddrForRepo(ctx context.Context, userAgent string,
 	return addrForKey(name, g.Addresses)
 }
 
+// AddrsForRepo returns the addresses of all gitserver instances that hold a
+// copy of the given repo. The first address is the primary, which is the one
+// returned by AddrForRepo. It is followed by the secondaries, which are the
+// next ReplicationFactor-1 addresses in the list of addresses.
+func (g *GitserverAddresses) AddrsForRepo(ctx context.Context, userAgent string, repoName api.RepoName) []string {
+	if len(g.Addresses) == 0 {
+		return nil
+	}
+
+	primary := g.AddrForRepo(ctx, userAgent, repoName)
+	n := g.ReplicationFactor
+	if n > len(g.Addresses) {
+		n = len(g.Addresses)
+	}
+	idx := slices.Index(g.Addresses, primary)
+	if n < 2 || idx < 0 {
+		// Pinned repos whose server isn't in the list of addresses are not
+		// replicated.
+		return []string{primary}
+	}
+
+	addrs := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		addrs = append(addrs, g.Addresses[(idx+i)%len(g.Addresses)])
+	}
+	return addrs
+}
+
 // addrForKey returns the gitserver address to use for the given string key,
 // which is hashed for sharding purposes.
 func addrForKey(key string, addrs []string) string {
@@ -192,6 +225,25 @@ type GitserverConns struct {
 
 func (g *GitserverConns) ConnForRepo(ctx context.Context, userAgent string, repo api.RepoName) (*grpc.ClientConn, error) {
 	addr := g.AddrForRepo(ctx, userAgent, repo)
+	return g.connForAddr(addr)
+}
+
+// ConnsForRepo returns the connections to all gitserver instances that hold a
+// copy of the given repo, starting with the primary. See AddrsForRepo.
+func (g *GitserverConns) ConnsForRepo(ctx context.Context, userAgent string, repo api.RepoName) ([]*grpc.ClientConn, error) {
+	addrs := g.AddrsForRepo(ctx, userAgent, repo)
+	conns := make([]*grpc.ClientConn, 0, len(addrs))
+	for _, addr := range addrs {
+		conn, err := g.connForAddr(addr)
+		if err != nil {
+			return nil, err
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+func (g *GitserverConns) connForAddr(addr string) (*grpc.ClientConn, error) {
 	ce, ok := g.grpcConns[addr]
 	if !ok {
 		return nil, errors.Newf("no gRPC connection found for address %q", addr)
@@ -229,13 +281,19 @@ func (a *atomicGitServerConns) AddrForRepo(ctx context.Context, userAgent string
 }
 
 func (a *atomicGitServerConns) ClientForRepo(ctx context.Context, userAgent string, repo api.RepoName) (proto.GitserverServiceClient, error) {
-	conn, err := a.get().ConnForRepo(ctx, userAgent, repo)
+	conns, err := a.get().ConnsForRepo(ctx, userAgent, repo)
 	if err != nil {
 		return nil, err
 	}
+	if len(conns) == 0 {
+		return nil, errors.New("no gitserver addresses configured")
+	}
 
-	client := &automaticRetryClient{base: proto.NewGitserverServiceClient(conn)}
-	return client, nil
+	clients := make([]proto.GitserverServiceClient, 0, len(conns))
+	for _, conn := range conns {
+		clients = append(clients, &automaticRetryClient{base: proto.NewGitserverServiceClient(conn)})
+	}
+	return newReplicaClient(clients), nil
 }
 
 func (a *atomicGitServerConns) Addresses() []AddressWithClient {
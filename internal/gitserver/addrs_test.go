@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
@@ -54,3 +56,62 @@ func TestAddrForRepo(t *testing.T) {
 		}
 	})
 }
+
+func TestAddrsForRepo(t *testing.T) {
+	ga := GitserverAddresses{
+		Addresses: []string{"gitserver-1", "gitserver-2", "gitserver-3"},
+		PinnedServers: map[string]string{
+			"repo2":   "gitserver-1",
+			"unknown": "gitserver-4",
+		},
+	}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name              string
+		repo              api.RepoName
+		replicationFactor int
+		want              []string
+	}{
+		{
+			name:              "replication disabled",
+			repo:              api.RepoName("repo1"),
+			replicationFactor: 0,
+			want:              []string{"gitserver-3"},
+		},
+		{
+			name:              "secondaries wrap around",
+			repo:              api.RepoName("repo1"),
+			replicationFactor: 2,
+			want:              []string{"gitserver-3", "gitserver-1"},
+		},
+		{
+			name:              "replication factor larger than the number of addresses",
+			repo:              api.RepoName("github.com/sourcegraph/sourcegraph.git"),
+			replicationFactor: 5,
+			want:              []string{"gitserver-2", "gitserver-3", "gitserver-1"},
+		},
+		{
+			name:              "pinned repo",
+			repo:              api.RepoName("repo2"),
+			replicationFactor: 2,
+			want:              []string{"gitserver-1", "gitserver-2"},
+		},
+		{
+			name:              "pinned to unknown address",
+			repo:              api.RepoName("unknown"),
+			replicationFactor: 2,
+			want:              []string{"gitserver-4"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ga.ReplicationFactor = tc.replicationFactor
+			got := ga.AddrsForRepo(ctx, "gitserver", tc.repo)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected addresses (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
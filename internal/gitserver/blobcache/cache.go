@@ -0,0 +1,234 @@
+// Package blobcache implements an on-disk cache of the contents of git blobs
+// that services can use to read the files of a repository from gitserver.
+//
+// Blobs are immutable and keyed by their object ID, so cached contents never
+// have to be invalidated and are shared between commits and repositories.
+// Reading the tree of a commit that is close to one that was read before only
+// transfers the blobs that changed in between.
+package blobcache
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Cache is an on-disk cache of the contents of git blobs.
+type Cache struct {
+	client gitserver.Client
+	store  diskcache.Store
+
+	// maxSizeBytes is the maximum size of the cache in bytes. The cache can
+	// temporarily grow larger than that between runs of the evicter.
+	maxSizeBytes int64
+
+	metrics *metrics
+}
+
+// New returns a cache that stores blobs read with client in dir. If
+// maxSizeBytes is greater than zero, the routine returned by NewEvicter keeps
+// the cache below that size.
+func New(observationCtx *observation.Context, client gitserver.Client, dir string, maxSizeBytes int64) *Cache {
+	return &Cache{
+		client:       client,
+		store:        diskcache.NewStore(dir, "blobcache", diskcache.WithobservationCtx(observationCtx)),
+		maxSizeBytes: maxSizeBytes,
+		metrics:      newMetrics(observationCtx.Registerer),
+	}
+}
+
+// ReadTree calls fn with the contents of every file below path at commit, in
+// the order returned by ReadDir. Submodules are skipped. All blobs that aren't
+// cached yet are fetched from gitserver in a single request before fn is
+// called for the first time.
+func (c *Cache) ReadTree(ctx context.Context, repo api.RepoName, commit api.CommitID, path string, fn func(fi fs.FileInfo, r io.Reader) error) error {
+	entries, err := c.client.ReadDir(ctx, repo, commit, path, true)
+	if err != nil {
+		return err
+	}
+
+	var (
+		files   []fs.FileInfo
+		missing []gitdomain.OID
+		seen    = make(map[gitdomain.OID]struct{})
+	)
+	for _, fi := range entries {
+		oid, ok := blobOID(fi)
+		if !ok {
+			continue
+		}
+		files = append(files, fi)
+		if _, ok := seen[oid]; ok {
+			continue
+		}
+		seen[oid] = struct{}{}
+		if c.store.Contains(key(oid)) {
+			c.metrics.hits.Inc()
+		} else {
+			c.metrics.misses.Inc()
+			missing = append(missing, oid)
+		}
+	}
+
+	if len(missing) > 0 {
+		err := c.client.ReadBlobs(ctx, repo, missing, func(oid gitdomain.OID, size int64, r io.Reader) error {
+			f, err := c.store.OpenWithPath(ctx, key(oid), writeBlob(r))
+			if err != nil {
+				return err
+			}
+			c.metrics.fetchedBytes.Add(float64(size))
+			return f.Close()
+		})
+		if err != nil {
+			return errors.Wrap(err, "reading blobs")
+		}
+	}
+
+	for _, fi := range files {
+		if err := c.readFile(ctx, repo, fi, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache) readFile(ctx context.Context, repo api.RepoName, fi fs.FileInfo, fn func(fi fs.FileInfo, r io.Reader) error) error {
+	oid, _ := blobOID(fi)
+	// The blob is usually cached at this point, but it might have been
+	// evicted in the meantime.
+	f, err := c.store.OpenWithPath(ctx, key(oid), func(ctx context.Context, path string) error {
+		return c.client.ReadBlobs(ctx, repo, []gitdomain.OID{oid}, func(_ gitdomain.OID, size int64, r io.Reader) error {
+			c.metrics.fetchedBytes.Add(float64(size))
+			return writeBlob(r)(ctx, path)
+		})
+	})
+	if err != nil {
+		return errors.Wrapf(err, "reading blob of %q", fi.Name())
+	}
+	defer f.Close()
+
+	// gitserver returns the contents of Git LFS objects instead of the
+	// pointers stored in the tree, so the size of a blob can differ from the
+	// size of the file in the tree.
+	if st, err := f.Stat(); err == nil && st.Size() != fi.Size() {
+		fi = sizedFileInfo{FileInfo: fi, size: st.Size()}
+	}
+	return fn(fi, f)
+}
+
+// sizedFileInfo is a fs.FileInfo with a different size.
+type sizedFileInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (fi sizedFileInfo) Size() int64 { return fi.size }
+
+// NewEvicter returns a periodic goroutine that evicts the least recently used
+// blobs once the cache is larger than its maximum size.
+func (c *Cache) NewEvicter(ctx context.Context, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			if c.maxSizeBytes <= 0 {
+				return nil
+			}
+			stats, err := c.store.Evict(c.maxSizeBytes)
+			if err != nil {
+				c.metrics.evictionErrors.Inc()
+				return errors.Wrap(err, "evicting blobs")
+			}
+			c.metrics.sizeBytes.Set(float64(stats.CacheSize))
+			c.metrics.evictions.Add(float64(stats.Evicted))
+			return nil
+		}),
+		goroutine.WithName("gitserver.blobcache-evicter"),
+		goroutine.WithDescription("evicts blobs from the blob cache"),
+		goroutine.WithInterval(interval),
+	)
+}
+
+// blobOID returns the object ID of the blob of a file listed by ReadDir.
+func blobOID(fi fs.FileInfo) (gitdomain.OID, bool) {
+	if fi.IsDir() {
+		return gitdomain.OID{}, false
+	}
+	// Submodules have a gitdomain.Submodule instead.
+	info, ok := fi.Sys().(gitdomain.ObjectInfo)
+	if !ok {
+		return gitdomain.OID{}, false
+	}
+	return info.OID(), true
+}
+
+func key(oid gitdomain.OID) []string {
+	return []string{oid.String()}
+}
+
+// writeBlob returns a fetcher that writes the contents of a blob from r to the
+// cache.
+func writeBlob(r io.Reader) diskcache.FetcherWithPath {
+	return func(_ context.Context, path string) error {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+}
+
+type metrics struct {
+	hits           prometheus.Counter
+	misses         prometheus.Counter
+	fetchedBytes   prometheus.Counter
+	sizeBytes      prometheus.Gauge
+	evictions      prometheus.Counter
+	evictionErrors prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	m := &metrics{
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "src_blobcache_hits_total",
+			Help: "The total number of blobs read from the cache.",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "src_blobcache_misses_total",
+			Help: "The total number of blobs that had to be fetched from gitserver.",
+		}),
+		fetchedBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "src_blobcache_fetched_bytes_total",
+			Help: "The total size of the blobs fetched from gitserver.",
+		}),
+		sizeBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "src_blobcache_size_bytes",
+			Help: "The total size of the cached blobs before the last eviction.",
+		}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "src_blobcache_evictions_total",
+			Help: "The total number of blobs evicted from the cache.",
+		}),
+		evictionErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "src_blobcache_eviction_errors_total",
+			Help: "The total number of failures evicting blobs from the cache.",
+		}),
+	}
+	r.MustRegister(m.hits, m.misses, m.fetchedBytes, m.sizeBytes, m.evictions, m.evictionErrors)
+	return m
+}
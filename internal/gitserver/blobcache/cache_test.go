@@ -0,0 +1,100 @@
+package blobcache
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+type objectInfo gitdomain.OID
+
+func (oid objectInfo) OID() gitdomain.OID { return gitdomain.OID(oid) }
+
+func TestCache_ReadTree(t *testing.T) {
+	ctx := context.Background()
+
+	blobs := map[gitdomain.OID]string{
+		{1}: "package main",
+		{2}: "# README",
+		{3}: "# README v2",
+	}
+	file := func(name string, oid gitdomain.OID) fs.FileInfo {
+		return &fileutil.FileInfo{Name_: name, Size_: int64(len(blobs[oid])), Sys_: objectInfo(oid)}
+	}
+	trees := map[api.CommitID][]fs.FileInfo{
+		"a": {
+			&fileutil.FileInfo{Name_: "cmd", Mode_: os.ModeDir},
+			file("cmd/main.go", gitdomain.OID{1}),
+			file("README.md", gitdomain.OID{2}),
+			&fileutil.FileInfo{Name_: "vendor", Sys_: gitdomain.Submodule{URL: "https://example.com/vendor"}},
+		},
+		"b": {
+			file("cmd/main.go", gitdomain.OID{1}),
+			file("README.md", gitdomain.OID{3}),
+			file("README-copy.md", gitdomain.OID{3}),
+		},
+	}
+
+	client := gitserver.NewMockClient()
+	client.ReadDirFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, commit api.CommitID, _ string, _ bool) ([]fs.FileInfo, error) {
+		return trees[commit], nil
+	})
+	var fetched [][]gitdomain.OID
+	client.ReadBlobsFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, oids []gitdomain.OID, fn func(gitdomain.OID, int64, io.Reader) error) error {
+		fetched = append(fetched, oids)
+		for _, oid := range oids {
+			if err := fn(oid, int64(len(blobs[oid])), strings.NewReader(blobs[oid])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	cache := New(observation.TestContextTB(t), client, t.TempDir(), 0)
+
+	readTree := func(commit api.CommitID) map[string]string {
+		t.Helper()
+		files := make(map[string]string)
+		err := cache.ReadTree(ctx, "repo", commit, "", func(fi fs.FileInfo, r io.Reader) error {
+			data, err := io.ReadAll(r)
+			files[fi.Name()] = string(data)
+			return err
+		})
+		require.NoError(t, err)
+		return files
+	}
+
+	assert.Equal(t, map[string]string{
+		"cmd/main.go": "package main",
+		"README.md":   "# README",
+	}, readTree("a"))
+	assert.Equal(t, [][]gitdomain.OID{{{1}, {2}}}, fetched)
+
+	// Only the blob that changed is fetched, and only once.
+	fetched = nil
+	assert.Equal(t, map[string]string{
+		"cmd/main.go":    "package main",
+		"README.md":      "# README v2",
+		"README-copy.md": "# README v2",
+	}, readTree("b"))
+	assert.Equal(t, [][]gitdomain.OID{{{3}}}, fetched)
+
+	// Evicted blobs are fetched again on demand.
+	_, err := cache.store.Evict(0)
+	require.NoError(t, err)
+	fetched = nil
+	readTree("a")
+	assert.Equal(t, [][]gitdomain.OID{{{1}, {2}}}, fetched)
+}
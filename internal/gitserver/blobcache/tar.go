@@ -0,0 +1,59 @@
+package blobcache
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"io/fs"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// TarReader returns a tar archive of all files at commit that is assembled
+// from cached blobs. Like the archives created by gitserver, it contains
+// regular files and symlinks, but no directories or submodules. gitserver
+// creates archives with --worktree-attributes, which ignores the
+// .gitattributes files of the repository, so neither drops export-ignore
+// files nor expands export-subst placeholders.
+func (c *Cache) TarReader(ctx context.Context, repo api.RepoName, commit api.CommitID) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		err := c.ReadTree(ctx, repo, commit, "", func(fi fs.FileInfo, r io.Reader) error {
+			return writeTarEntry(tw, fi, r)
+		})
+		if err == nil {
+			err = tw.Close()
+		}
+		// CloseWithError is guaranteed to return a nil error
+		_ = pw.CloseWithError(errors.Wrapf(err, "failed to read tree of %s@%s", repo, commit))
+	}()
+	return pr, nil
+}
+
+func writeTarEntry(tw *tar.Writer, fi fs.FileInfo, r io.Reader) error {
+	hdr := &tar.Header{
+		Name:     fi.Name(),
+		Mode:     int64(fi.Mode().Perm()),
+		Typeflag: tar.TypeReg,
+		Size:     fi.Size(),
+	}
+	if fi.Mode()&fs.ModeSymlink != 0 {
+		// The blob of a symlink holds the path it links to.
+		target, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		hdr.Typeflag = tar.TypeSymlink
+		hdr.Linkname = string(target)
+		hdr.Size = 0
+		return tw.WriteHeader(hdr)
+	}
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
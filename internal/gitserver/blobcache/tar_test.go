@@ -0,0 +1,77 @@
+package blobcache
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestCache_TarReader(t *testing.T) {
+	blobs := map[gitdomain.OID]string{
+		{1}: "package main",
+		{2}: "main.go",
+		// gitserver returns the contents of Git LFS objects instead of their
+		// pointers, so the blob is larger than the file in the tree.
+		{3}: "lfs contents",
+	}
+
+	client := gitserver.NewMockClient()
+	client.ReadDirFunc.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, string, bool) ([]fs.FileInfo, error) {
+		return []fs.FileInfo{
+			&fileutil.FileInfo{Name_: "cmd", Mode_: os.ModeDir},
+			&fileutil.FileInfo{Name_: "cmd/main.go", Mode_: 0o644, Size_: 12, Sys_: objectInfo(gitdomain.OID{1})},
+			&fileutil.FileInfo{Name_: "cmd/link.go", Mode_: os.ModeSymlink, Size_: 7, Sys_: objectInfo(gitdomain.OID{2})},
+			&fileutil.FileInfo{Name_: "data.bin", Mode_: 0o644, Size_: 3, Sys_: objectInfo(gitdomain.OID{3})},
+			&fileutil.FileInfo{Name_: "vendor", Sys_: gitdomain.Submodule{URL: "https://example.com/vendor"}},
+		}, nil
+	})
+	client.ReadBlobsFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, oids []gitdomain.OID, fn func(gitdomain.OID, int64, io.Reader) error) error {
+		for _, oid := range oids {
+			if err := fn(oid, int64(len(blobs[oid])), strings.NewReader(blobs[oid])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	cache := New(observation.TestContextTB(t), client, t.TempDir(), 0)
+	rc, err := cache.TarReader(context.Background(), "repo", "a")
+	require.NoError(t, err)
+	defer rc.Close()
+
+	type entry struct {
+		typeflag byte
+		linkname string
+		content  string
+	}
+	entries := make(map[string]entry)
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		entries[hdr.Name] = entry{typeflag: hdr.Typeflag, linkname: hdr.Linkname, content: string(content)}
+	}
+	assert.Equal(t, map[string]entry{
+		"cmd/main.go": {typeflag: tar.TypeReg, content: "package main"},
+		"cmd/link.go": {typeflag: tar.TypeSymlink, linkname: "main.go"},
+		"data.bin":    {typeflag: tar.TypeReg, content: "lfs contents"},
+	}, entries)
+}
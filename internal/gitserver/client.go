@@ -273,7 +273,7 @@ type RawBatchLogResult struct {
 type BatchLogCallback func(repoCommit api.RepoCommit, gitLogResult RawBatchLogResult) error
 
 type HunkReader interface {
-	Read() (*Hunk, error)
+	Read() (*gitdomain.Hunk, error)
 	Close() error
 }
 
@@ -392,8 +392,19 @@ type Client interface {
 	// The caller should always close the reader after use.
 	NewFileReader(ctx context.Context, repo api.RepoName, commit api.CommitID, name string) (io.ReadCloser, error)
 
-	// DiffSymbols performs a diff command which is expected to be parsed by our symbols package
-	DiffSymbols(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]byte, error)
+	// ReadBlobs calls fn with the contents of each of the given blobs, in the
+	// order of oids. The reader passed to fn is only valid until fn returns.
+	// Since blobs are immutable, callers can cache their contents by object ID
+	// and only read the blobs they haven't seen yet, for example the ones
+	// listed by ReadDir that changed between two commits.
+	//
+	// ReadBlobs can't apply sub-repo permissions because blobs aren't
+	// addressed by path, so it fails for repos that have them enabled.
+	ReadBlobs(ctx context.Context, repo api.RepoName, oids []gitdomain.OID, fn func(oid gitdomain.OID, size int64, r io.Reader) error) error
+
+	// DiffSymbols returns the files that were added, modified or deleted between
+	// commitA and commitB. Renames are reported as a deletion and an addition.
+	DiffSymbols(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) ([]gitdomain.PathStatus, error)
 
 	// Commits returns all commits matching the options.
 	Commits(ctx context.Context, repo api.RepoName, opt CommitsOptions) ([]*gitdomain.Commit, error)
@@ -401,6 +412,12 @@ type Client interface {
 	// FirstEverCommit returns the first commit ever made to the repository.
 	FirstEverCommit(ctx context.Context, repo api.RepoName) (*gitdomain.Commit, error)
 
+	// HgChangesetCommit returns the commit that the Mercurial changeset with the
+	// given ID, or a prefix of it, was converted to. It only finds changesets of
+	// repositories synced from Mercurial, which record the changeset ID of each
+	// commit as a note in refs/notes/hg.
+	HgChangesetCommit(ctx context.Context, repo api.RepoName, changeset string) (api.CommitID, error)
+
 	// ListTags returns a list of all tags in the repository. If commitObjs is non-empty, only all tags pointing at those commits are returned.
 	ListTags(ctx context.Context, repo api.RepoName, commitObjs ...string) ([]*gitdomain.Tag, error)
 
@@ -839,6 +856,14 @@ func (c *RemoteGitCommand) sendExec(ctx context.Context) (_ io.ReadCloser, err e
 	}
 }
 
+// useTypedRPCs returns true if git operations that have a dedicated gitserver
+// RPC should use it instead of running git commands through Exec. Tests that
+// run git locally (see ClientMocks.LocalGitserver) have no gitserver to talk
+// to, so they keep running the git commands directly.
+func useTypedRPCs(ctx context.Context) bool {
+	return conf.IsGRPCEnabled(ctx) && !ClientMocks.LocalGitserver
+}
+
 type readCloseWrapper struct {
 	r       io.Reader
 	closeFn func()
@@ -890,6 +915,19 @@ func convertGRPCErrorToGitDomainError(err error) error {
 				CloneInProgress: payload.CloneInProgress,
 				CloneProgress:   payload.CloneProgress,
 			}
+
+		case *proto.RevisionNotFoundPayload:
+			return &gitdomain.RevisionNotFoundError{
+				Repo: api.RepoName(payload.Repo),
+				Spec: payload.Spec,
+			}
+
+		case *proto.FileNotFoundPayload:
+			return &os.PathError{
+				Op:   "open",
+				Path: payload.Path,
+				Err:  os.ErrNotExist,
+			}
 		}
 	}
 
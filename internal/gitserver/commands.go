@@ -1,7 +1,6 @@
 package gitserver
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/hex"
@@ -10,7 +9,6 @@ import (
 	"io"
 	"io/fs"
 	"net/http"
-	"net/mail"
 	"os"
 	stdlibpath "path"
 	"path/filepath"
@@ -20,7 +18,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/go-git/go-git/v5/plumbing/format/config"
 	"github.com/golang/groupcache/lru"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -36,6 +33,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/fileutil"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
 	"github.com/sourcegraph/sourcegraph/internal/grpc/streamio"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -186,6 +184,30 @@ func (c *clientImplementor) ContributorCount(ctx context.Context, repo api.RepoN
 		return nil, err
 	}
 
+	if useTypedRPCs(ctx) {
+		client, err := c.ClientForRepo(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.ContributorCounts(ctx, &proto.ContributorCountsRequest{
+			Repo:  string(repo),
+			Range: opt.Range,
+			After: opt.After,
+			Path:  opt.Path,
+		})
+		if err != nil {
+			return nil, convertGRPCErrorToGitDomainError(err)
+		}
+
+		counts := make([]*gitdomain.ContributorCount, len(resp.GetCounts()))
+		for i, count := range resp.GetCounts() {
+			counts[i] = &gitdomain.ContributorCount{}
+			counts[i].FromProto(count)
+		}
+		return counts, nil
+	}
+
 	// We split the individual args for the shortlog command instead of -sne for easier arg checking in the allowlist.
 	args := []string{"shortlog", "-s", "-n", "-e", "--no-merges"}
 	if opt.After != "" {
@@ -200,66 +222,7 @@ func (c *clientImplementor) ContributorCount(ctx context.Context, repo api.RepoN
 	if err != nil {
 		return nil, errors.Errorf("exec `git shortlog -s -n -e` failed: %v", err)
 	}
-	return parseShortLog(out)
-}
-
-// logEntryPattern is the regexp pattern that matches entries in the output of the `git shortlog
-// -sne` command.
-var logEntryPattern = lazyregexp.New(`^\s*([0-9]+)\s+(.*)$`)
-
-func parseShortLog(out []byte) ([]*gitdomain.ContributorCount, error) {
-	out = bytes.TrimSpace(out)
-	if len(out) == 0 {
-		return nil, nil
-	}
-	lines := bytes.Split(out, []byte{'\n'})
-	results := make([]*gitdomain.ContributorCount, len(lines))
-	for i, line := range lines {
-		// example line: "1125\tJane Doe <jane@sourcegraph.com>"
-		match := logEntryPattern.FindSubmatch(line)
-		if match == nil {
-			return nil, errors.Errorf("invalid git shortlog line: %q", line)
-		}
-		// example match: ["1125\tJane Doe <jane@sourcegraph.com>" "1125" "Jane Doe <jane@sourcegraph.com>"]
-		count, err := strconv.Atoi(string(match[1]))
-		if err != nil {
-			return nil, err
-		}
-		addr, err := lenientParseAddress(string(match[2]))
-		if err != nil || addr == nil {
-			addr = &mail.Address{Name: string(match[2])}
-		}
-		results[i] = &gitdomain.ContributorCount{
-			Count: int32(count),
-			Name:  addr.Name,
-			Email: addr.Address,
-		}
-	}
-	return results, nil
-}
-
-// lenientParseAddress is just like mail.ParseAddress, except that it treats
-// the following somewhat-common malformed syntax where a user has misconfigured
-// their email address as their name:
-//
-//	foo@gmail.com <foo@gmail.com>
-//
-// As a valid name, whereas mail.ParseAddress would return an error:
-//
-//	mail: expected single address, got "<foo@gmail.com>"
-func lenientParseAddress(address string) (*mail.Address, error) {
-	addr, err := mail.ParseAddress(address)
-	if err != nil && strings.Contains(err.Error(), "expected single address") {
-		p := strings.LastIndex(address, "<")
-		if p == -1 {
-			return addr, err
-		}
-		return &mail.Address{
-			Name:    strings.TrimSpace(address[:p]),
-			Address: strings.Trim(address[p:], " <>"),
-		}, nil
-	}
-	return addr, err
+	return gitdomain.ParseShortLog(out)
 }
 
 // checkSpecArgSafety returns a non-nil err if spec begins with a "-", which
@@ -427,7 +390,7 @@ func (c *clientImplementor) DiffPath(ctx context.Context, repo api.RepoName, sou
 }
 
 // DiffSymbols performs a diff command which is expected to be parsed by our symbols package
-func (c *clientImplementor) DiffSymbols(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) (_ []byte, err error) {
+func (c *clientImplementor) DiffSymbols(ctx context.Context, repo api.RepoName, commitA, commitB api.CommitID) (_ []gitdomain.PathStatus, err error) {
 	ctx, _, endObservation := c.operations.diffSymbols.With(ctx, &err, observation.Args{
 		MetricLabelValues: []string{c.scope},
 		Attrs: []attribute.KeyValue{
@@ -444,12 +407,38 @@ func (c *clientImplementor) DiffSymbols(ctx context.Context, repo api.RepoName,
 		return nil, errors.Wrapf(err, "failed to lookup revisions for git diff on %s between %s and %s", repo, commitA, commitB)
 	}
 
-	command := c.gitCommand(repo, "diff", "-z", "--name-status", "--no-renames", string(commitA), string(commitB))
-	out, err := command.Output(ctx)
+	client, err := c.ClientForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.DiffSymbols(ctx, &proto.DiffSymbolsRequest{
+		Repo:    string(repo),
+		CommitA: string(commitA),
+		CommitB: string(commitB),
+	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to run git diff on %s between %s and %s", repo, commitA, commitB)
+		return nil, convertGRPCErrorToGitDomainError(err)
+	}
+
+	var changes []gitdomain.PathStatus
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return changes, nil
+			}
+			return nil, errors.Wrapf(convertGRPCErrorToGitDomainError(err), "failed to run git diff on %s between %s and %s", repo, commitA, commitB)
+		}
+		for _, f := range msg.GetFiles() {
+			var change gitdomain.PathStatus
+			change.FromProto(f)
+			changes = append(changes, change)
+		}
 	}
-	return out, nil
 }
 
 // ReadDir reads the contents of the named directory at commit.
@@ -623,31 +612,15 @@ func (c *clientImplementor) lsTreeUncached(ctx context.Context, repo api.RepoNam
 		return nil, err
 	}
 
-	args := []string{
-		"ls-tree",
-		"--long", // show size
-		"--full-name",
-		"-z",
-		string(commit),
-	}
-	if recurse {
-		args = append(args, "-r", "-t")
-	}
-	if path != "" {
-		args = append(args, "--", filepath.ToSlash(path))
-	}
-	cmd := c.gitCommand(repo, args...)
-	out, err := cmd.CombinedOutput(ctx)
+	entries, err := c.readDir(ctx, repo, commit, filepath.ToSlash(path), recurse)
 	if err != nil {
-		if bytes.Contains(out, []byte("exists on disk, but not in")) {
+		if os.IsNotExist(err) {
 			return nil, &os.PathError{Op: "ls-tree", Path: filepath.ToSlash(path), Err: os.ErrNotExist}
 		}
-
-		message := errorMessageTruncatedOutput(cmd.Args(), out)
-		return nil, errors.WithMessage(err, message)
+		return nil, err
 	}
 
-	if len(out) == 0 {
+	if len(entries) == 0 {
 		// If we are listing the empty root tree, we will have no output.
 		if stdlibpath.Clean(path) == "." {
 			return []fs.FileInfo{}, nil
@@ -656,57 +629,24 @@ func (c *clientImplementor) lsTreeUncached(ctx context.Context, repo api.RepoNam
 	}
 
 	trimPath := strings.TrimPrefix(path, "./")
-	lines := strings.Split(string(out), "\x00")
-	fis := make([]fs.FileInfo, len(lines)-1)
-	for i, line := range lines {
-		if i == len(lines)-1 {
-			// last entry is empty
-			continue
-		}
-
-		tabPos := strings.IndexByte(line, '\t')
-		if tabPos == -1 {
-			return nil, errors.Errorf("invalid `git ls-tree` output: %q", out)
-		}
-		info := strings.SplitN(line[:tabPos], " ", 4)
-		name := line[tabPos+1:]
+	fis := make([]fs.FileInfo, len(entries))
+	for i, entry := range entries {
+		name := string(entry.GetPath())
 		if len(name) < len(trimPath) {
 			// This is in a submodule; return the original path to avoid a slice out of bounds panic
 			// when setting the FileInfo._Name below.
 			name = trimPath
 		}
 
-		if len(info) != 4 {
-			return nil, errors.Errorf("invalid `git ls-tree` output: %q", out)
-		}
-		typ := info[1]
-		sha := info[2]
-		if !gitdomain.IsAbsoluteRevision(sha) {
-			return nil, errors.Errorf("invalid `git ls-tree` SHA output: %q", sha)
-		}
-		oid, err := decodeOID(sha)
+		oid, err := decodeOID(entry.GetOid())
 		if err != nil {
 			return nil, err
 		}
 
-		sizeStr := strings.TrimSpace(info[3])
-		var size int64
-		if sizeStr != "-" {
-			// Size of "-" indicates a dir or submodule.
-			size, err = strconv.ParseInt(sizeStr, 10, 64)
-			if err != nil || size < 0 {
-				return nil, errors.Errorf("invalid `git ls-tree` size output: %q (error: %s)", sizeStr, err)
-			}
-		}
-
 		var sys any
-		modeVal, err := strconv.ParseInt(info[0], 8, 32)
-		if err != nil {
-			return nil, err
-		}
-		mode := os.FileMode(modeVal)
-		switch typ {
-		case "blob":
+		mode := os.FileMode(entry.GetMode())
+		switch entry.GetType() {
+		case proto.GitObject_OBJECT_TYPE_BLOB:
 			const gitModeSymlink = 0o20000
 			if mode&gitModeSymlink != 0 {
 				mode = os.ModeSymlink
@@ -714,24 +654,15 @@ func (c *clientImplementor) lsTreeUncached(ctx context.Context, repo api.RepoNam
 				// Regular file.
 				mode = mode | 0o644
 			}
-		case "commit":
+		case proto.GitObject_OBJECT_TYPE_COMMIT:
 			mode = mode | gitdomain.ModeSubmodule
-			cmd := c.gitCommand(repo, "show", fmt.Sprintf("%s:.gitmodules", commit))
-			var submodule gitdomain.Submodule
-			if out, err := cmd.Output(ctx); err == nil {
-
-				var cfg config.Config
-				err := config.NewDecoder(bytes.NewBuffer(out)).Decode(&cfg)
-				if err != nil {
-					return nil, errors.Errorf("error parsing .gitmodules: %s", err)
-				}
-
-				submodule.Path = cfg.Section("submodule").Subsection(name).Option("path")
-				submodule.URL = cfg.Section("submodule").Subsection(name).Option("url")
+			sys = gitdomain.Submodule{
+				Path:     string(entry.GetSubmodule().GetPath()),
+				URL:      entry.GetSubmodule().GetUrl(),
+				CommitID: api.CommitID(oid.String()),
+				Repo:     api.RepoName(entry.GetSubmodule().GetRepo()),
 			}
-			submodule.CommitID = api.CommitID(oid.String())
-			sys = submodule
-		case "tree":
+		case proto.GitObject_OBJECT_TYPE_TREE:
 			mode = mode | os.ModeDir
 		}
 
@@ -743,7 +674,7 @@ func (c *clientImplementor) lsTreeUncached(ctx context.Context, repo api.RepoNam
 		fis[i] = &fileutil.FileInfo{
 			Name_: name, // full path relative to root (not just basename)
 			Mode_: mode,
-			Size_: size,
+			Size_: entry.GetSize(),
 			Sys_:  sys,
 		}
 	}
@@ -752,6 +683,40 @@ func (c *clientImplementor) lsTreeUncached(ctx context.Context, repo api.RepoNam
 	return fis, nil
 }
 
+// readDir returns the entries of the tree at path in commit, as returned by
+// gitserver's ReadDir RPC.
+func (c *clientImplementor) readDir(ctx context.Context, repo api.RepoName, commit api.CommitID, path string, recurse bool) ([]*proto.TreeEntry, error) {
+	client, err := c.ClientForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.ReadDir(ctx, &proto.ReadDirRequest{
+		Repo:      string(repo),
+		CommitSha: string(commit),
+		Path:      path,
+		Recursive: recurse,
+	})
+	if err != nil {
+		return nil, convertGRPCErrorToGitDomainError(err)
+	}
+
+	var entries []*proto.TreeEntry
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return entries, nil
+			}
+			return nil, convertGRPCErrorToGitDomainError(err)
+		}
+		entries = append(entries, msg.GetEntries()...)
+	}
+}
+
 func decodeOID(sha string) (gitdomain.OID, error) {
 	oidBytes, err := hex.DecodeString(sha)
 	if err != nil {
@@ -806,20 +771,12 @@ func (o *BlameOptions) Attrs() []attribute.KeyValue {
 	}
 }
 
-// A Hunk is a contiguous portion of a file associated with a commit.
-type Hunk struct {
-	StartLine int // 1-indexed start line number
-	EndLine   int // 1-indexed end line number
-	StartByte int // 0-indexed start byte position (inclusive)
-	EndByte   int // 0-indexed end byte position (exclusive)
-	api.CommitID
-	Author   gitdomain.Signature
-	Message  string
-	Filename string
-}
-
 // StreamBlameFile returns Git blame information about a file.
 func (c *clientImplementor) StreamBlameFile(ctx context.Context, repo api.RepoName, path string, opt *BlameOptions) (_ HunkReader, err error) {
+	if opt == nil {
+		opt = &BlameOptions{}
+	}
+
 	ctx, _, endObservation := c.operations.streamBlameFile.With(ctx, &err, observation.Args{
 		MetricLabelValues: []string{c.scope},
 		Attrs: append([]attribute.KeyValue{
@@ -829,62 +786,57 @@ func (c *clientImplementor) StreamBlameFile(ctx context.Context, repo api.RepoNa
 	})
 	defer endObservation(1, observation.Args{})
 
-	return streamBlameFileCmd(ctx, c.subRepoPermsChecker, repo, path, opt, c.gitserverGitCommandFunc(repo))
-}
-
-type errUnauthorizedStreamBlame struct {
-	Repo api.RepoName
-}
-
-func (e errUnauthorizedStreamBlame) Unauthorized() bool {
-	return true
-}
-
-func (e errUnauthorizedStreamBlame) Error() string {
-	return fmt.Sprintf("not authorized (name=%s)", e.Repo)
-}
-
-func streamBlameFileCmd(ctx context.Context, checker authz.SubRepoPermissionChecker, repo api.RepoName, path string, opt *BlameOptions, command gitCommandFunc) (HunkReader, error) {
 	a := actor.FromContext(ctx)
-	hasAccess, err := authz.FilterActorPath(ctx, checker, a, repo, path)
+	hasAccess, err := authz.FilterActorPath(ctx, c.subRepoPermsChecker, a, repo, path)
 	if err != nil {
 		return nil, err
 	}
 	if !hasAccess {
 		return nil, errUnauthorizedStreamBlame{Repo: repo}
 	}
-	if opt == nil {
-		opt = &BlameOptions{}
-	}
 	if err := checkSpecArgSafety(string(opt.NewestCommit)); err != nil {
 		return nil, err
 	}
 
-	args := []string{"blame", "--porcelain", "--incremental"}
-	if opt.IgnoreWhitespace {
-		args = append(args, "-w")
+	client, err := c.ClientForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &proto.BlameRequest{
+		Repo:             string(repo),
+		Commit:           string(opt.NewestCommit),
+		Path:             filepath.ToSlash(path),
+		IgnoreWhitespace: opt.IgnoreWhitespace,
 	}
 	if opt.StartLine != 0 || opt.EndLine != 0 {
-		args = append(args, fmt.Sprintf("-L%d,%d", opt.StartLine, opt.EndLine))
+		req.Range = &proto.BlameRange{
+			StartLine: uint32(opt.StartLine),
+			EndLine:   uint32(opt.EndLine),
+		}
 	}
-	args = append(args, string(opt.NewestCommit), "--", filepath.ToSlash(path))
 
-	rc, err := command(args).StdoutReader(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := client.Blame(ctx, req)
 	if err != nil {
-		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed", args))
+		cancel()
+		return nil, convertGRPCErrorToGitDomainError(err)
 	}
 
-	return newBlameHunkReader(rc), nil
+	return &grpcBlameHunkReader{stream: stream, cancel: cancel}, nil
 }
 
-func (c *clientImplementor) gitserverGitCommandFunc(repo api.RepoName) gitCommandFunc {
-	return func(args []string) GitCommand {
-		return c.gitCommand(repo, args...)
-	}
+type errUnauthorizedStreamBlame struct {
+	Repo api.RepoName
 }
 
-// gitCommandFunc is a func that creates a new executable Git command.
-type gitCommandFunc func(args []string) GitCommand
+func (e errUnauthorizedStreamBlame) Unauthorized() bool {
+	return true
+}
+
+func (e errUnauthorizedStreamBlame) Error() string {
+	return fmt.Sprintf("not authorized (name=%s)", e.Repo)
+}
 
 // ResolveRevisionOptions configure how we resolve revisions.
 // The zero value should contain appropriate default values.
@@ -988,6 +940,14 @@ func (c *clientImplementor) LsFiles(ctx context.Context, repo api.RepoName, comm
 	})
 	defer endObservation(1, observation.Args{})
 
+	if useTypedRPCs(ctx) {
+		files, err := c.lsFiles(ctx, repo, commit, pathspecs)
+		if err != nil {
+			return nil, err
+		}
+		return filterPaths(ctx, c.subRepoPermsChecker, repo, files)
+	}
+
 	args := []string{
 		"ls-files",
 		"-z",
@@ -1016,6 +976,209 @@ func (c *clientImplementor) LsFiles(ctx context.Context, repo api.RepoName, comm
 	return filterPaths(ctx, c.subRepoPermsChecker, repo, files)
 }
 
+// lsFiles returns the paths reported by gitserver's LsFiles RPC.
+func (c *clientImplementor) lsFiles(ctx context.Context, repo api.RepoName, commit api.CommitID, pathspecs []gitdomain.Pathspec) ([]string, error) {
+	client, err := c.ClientForRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &proto.LsFilesRequest{
+		Repo:      string(repo),
+		CommitSha: string(commit),
+	}
+	for _, pathspec := range pathspecs {
+		req.Pathspecs = append(req.Pathspecs, string(pathspec))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.LsFiles(ctx, req)
+	if err != nil {
+		return nil, convertGRPCErrorToGitDomainError(err)
+	}
+
+	var files []string
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return files, nil
+			}
+			return nil, convertGRPCErrorToGitDomainError(err)
+		}
+		files = append(files, msg.GetFiles()...)
+	}
+}
+
+func (c *clientImplementor) ReadBlobs(ctx context.Context, repo api.RepoName, oids []gitdomain.OID, fn func(oid gitdomain.OID, size int64, r io.Reader) error) (err error) {
+	ctx, _, endObservation := c.operations.readBlobs.With(ctx, &err, observation.Args{
+		MetricLabelValues: []string{c.scope},
+		Attrs: []attribute.KeyValue{
+			repo.Attr(),
+			attribute.Int("oids", len(oids)),
+		},
+	})
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: Blobs are addressed by object ID, so there is no path to
+	// check sub-repo permissions against.
+	if authz.SubRepoEnabled(c.subRepoPermsChecker) {
+		if enabled, err := authz.SubRepoEnabledForRepo(ctx, c.subRepoPermsChecker, repo); err != nil {
+			return errors.Wrap(err, "sub-repo permissions check")
+		} else if enabled {
+			return errors.New("ReadBlobs invoked for a repo with sub-repo permissions")
+		}
+	}
+
+	if len(oids) == 0 {
+		return nil
+	}
+
+	if useTypedRPCs(ctx) {
+		return c.readBlobs(ctx, repo, oids, fn)
+	}
+
+	cmd := c.gitCommand(repo, "cat-file", "--batch")
+	cmd.SetStdin([]byte(joinOIDs(oids)))
+	rc, err := cmd.StdoutReader(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	r := gitdomain.NewCatFileBatchReader(rc)
+	for _, oid := range oids {
+		h, err := r.Next()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		if h.Missing {
+			return &gitdomain.RevisionNotFoundError{Repo: repo, Spec: oid.String()}
+		}
+		if h.Type != gitdomain.ObjectTypeBlob {
+			return errors.Errorf("object %s is a %s, not a blob", h.OID, h.Type)
+		}
+		if err := fn(oid, h.Size, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBlobs reads the blobs from gitserver's GetBlobs RPC.
+func (c *clientImplementor) readBlobs(ctx context.Context, repo api.RepoName, oids []gitdomain.OID, fn func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+	client, err := c.ClientForRepo(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	req := &proto.GetBlobsRequest{
+		Repo: string(repo),
+		Oids: make([]string, 0, len(oids)),
+	}
+	for _, oid := range oids {
+		req.Oids = append(req.Oids, oid.String())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.GetBlobs(ctx, req)
+	if err != nil {
+		return convertGRPCErrorToGitDomainError(err)
+	}
+
+	r := &blobStreamReader{stream: stream}
+	for _, oid := range oids {
+		size, err := r.next(oid.String())
+		if err != nil {
+			return err
+		}
+		if err := fn(oid, size, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blobStreamReader reads the contents of consecutive blobs from a GetBlobs
+// stream.
+type blobStreamReader struct {
+	stream proto.GitserverService_GetBlobsClient
+	// data is the received but unread data of the current blob.
+	data []byte
+	// remaining is the number of bytes of the current blob that haven't been
+	// read yet, including data.
+	remaining int64
+}
+
+// next skips the unread contents of the current blob and returns the size of
+// the next one, which must have the given object ID.
+func (r *blobStreamReader) next(oid string) (int64, error) {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return 0, err
+	}
+	msg, err := r.recv()
+	if err != nil {
+		return 0, err
+	}
+	if msg.GetOid() != oid {
+		return 0, errors.Errorf("GetBlobs: got blob %q, want %q", msg.GetOid(), oid)
+	}
+	r.data, r.remaining = msg.GetData(), msg.GetSize()
+	return msg.GetSize(), nil
+}
+
+func (r *blobStreamReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if len(r.data) == 0 {
+		msg, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		if msg.GetOid() != "" {
+			return 0, errors.Errorf("GetBlobs: blob truncated before %q", msg.GetOid())
+		}
+		r.data = msg.GetData()
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, errors.New("GetBlobs: blob larger than its size")
+	}
+	return n, nil
+}
+
+func (r *blobStreamReader) recv() (*proto.GetBlobsResponse, error) {
+	msg, err := r.stream.Recv()
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, convertGRPCErrorToGitDomainError(err)
+	}
+	return msg, nil
+}
+
+// joinOIDs returns the object IDs as newline terminated lines, the format git
+// cat-file --batch reads from stdin.
+func joinOIDs(oids []gitdomain.OID) string {
+	var sb strings.Builder
+	for _, oid := range oids {
+		sb.WriteString(oid.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
 // 🚨 SECURITY: All git methods that deal with file or path access need to have
 // sub-repo permissions applied
 func filterPaths(ctx context.Context, checker authz.SubRepoPermissionChecker, repo api.RepoName, paths []string) ([]string, error) {
@@ -1241,6 +1404,23 @@ func (c *clientImplementor) MergeBase(ctx context.Context, repo api.RepoName, a,
 	})
 	defer endObservation(1, observation.Args{})
 
+	if useTypedRPCs(ctx) {
+		client, err := c.ClientForRepo(ctx, repo)
+		if err != nil {
+			return "", err
+		}
+
+		resp, err := client.MergeBase(ctx, &proto.MergeBaseRequest{
+			Repo: string(repo),
+			Base: string(a),
+			Head: string(b),
+		})
+		if err != nil {
+			return "", convertGRPCErrorToGitDomainError(err)
+		}
+		return api.CommitID(resp.GetMergeBaseCommitSha()), nil
+	}
+
 	cmd := c.gitCommand(repo, "merge-base", "--", string(a), string(b))
 	out, err := cmd.CombinedOutput(ctx)
 	if err != nil {
@@ -1532,6 +1712,29 @@ type CommitsOptions struct {
 
 	// When true return the names of the files changed in the commit
 	NameOnly bool
+
+	// When true only the first parent of merge commits is followed
+	FirstParent bool
+}
+
+func (opt *CommitsOptions) ToProto(repo api.RepoName) *proto.CommitsRequest {
+	return &proto.CommitsRequest{
+		Repo:             string(repo),
+		Range:            opt.Range,
+		MaxCommits:       uint32(opt.N),
+		Skip:             uint32(opt.Skip),
+		MessageQuery:     []byte(opt.MessageQuery),
+		Author:           []byte(opt.Author),
+		After:            opt.After,
+		Before:           opt.Before,
+		Reverse:          opt.Reverse,
+		DateOrder:        opt.DateOrder,
+		Path:             []byte(opt.Path),
+		Follow:           opt.Follow,
+		NoEnsureRevision: opt.NoEnsureRevision,
+		NameOnly:         opt.NameOnly,
+		FirstParent:      opt.FirstParent,
+	}
 }
 
 var recordGetCommitQueries = os.Getenv("RECORD_GET_COMMIT_QUERIES") == "1"
@@ -1819,20 +2022,42 @@ func (c *clientImplementor) commitLog(ctx context.Context, repo api.RepoName, op
 }
 
 func (c *clientImplementor) getWrappedCommits(ctx context.Context, repo api.RepoName, opt CommitsOptions) ([]*wrappedCommit, error) {
-	args, err := commitLogArgs([]string{"log", logFormatWithoutRefs}, opt)
-	if err != nil {
+	if err := checkSpecArgSafety(opt.Range); err != nil {
 		return nil, err
 	}
 
-	cmd := c.gitCommand(repo, args...)
-	if !opt.NoEnsureRevision {
-		cmd.SetEnsureRevision(opt.Range)
-	}
-	wrappedCommits, err := runCommitLog(ctx, cmd, opt)
+	client, err := c.ClientForRepo(ctx, repo)
 	if err != nil {
 		return nil, err
 	}
-	return wrappedCommits, nil
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Commits(ctx, opt.ToProto(repo))
+	if err != nil {
+		return nil, convertGRPCErrorToGitDomainError(err)
+	}
+
+	var commits []*wrappedCommit
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return commits, nil
+			}
+			return nil, convertGRPCErrorToGitDomainError(err)
+		}
+		for _, c := range msg.GetCommits() {
+			var commit gitdomain.Commit
+			commit.FromProto(c)
+			var files []string
+			for _, f := range c.GetModifiedFiles() {
+				files = append(files, string(f))
+			}
+			commits = append(commits, &wrappedCommit{Commit: &commit, files: files})
+		}
+	}
 }
 
 func needMoreCommits(filtered []*gitdomain.Commit, commits []*wrappedCommit, opt CommitsOptions, checker authz.SubRepoPermissionChecker) bool {
@@ -1896,70 +2121,18 @@ func joinCommits(previous, next []*gitdomain.Commit, desiredTotal uint) []*gitdo
 	return allCommits
 }
 
-// runCommitLog sends the git command to gitserver. It interprets missing
-// revision responses and converts them into RevisionNotFoundError.
-// It is declared as a variable so that we can swap it out in tests
-var runCommitLog = func(ctx context.Context, cmd GitCommand, opt CommitsOptions) ([]*wrappedCommit, error) {
-	data, stderr, err := cmd.DividedOutput(ctx)
-	if err != nil {
-		data = bytes.TrimSpace(data)
-		if isBadObjectErr(string(stderr), opt.Range) {
-			return nil, &gitdomain.RevisionNotFoundError{Repo: cmd.Repo(), Spec: opt.Range}
-		}
-		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args(), data))
-	}
-
-	return parseCommitLogOutput(bytes.NewReader(data))
-}
-
 func parseCommitLogOutput(r io.Reader) ([]*wrappedCommit, error) {
-	commitScanner := bufio.NewScanner(r)
-	commitScanner.Split(commitSplitFunc)
-
 	var commits []*wrappedCommit
-	for commitScanner.Scan() {
-		rawCommit := commitScanner.Bytes()
-		parts := bytes.Split(rawCommit, []byte{'\x00'})
-		if len(parts) != partsPerCommit {
-			return nil, errors.Newf("internal error: expected %d parts, got %d", partsPerCommit, len(parts))
-		}
-
-		commit, err := parseCommitFromLog(parts)
-		if err != nil {
-			return nil, err
-		}
-		commits = append(commits, commit)
+	err := gitdomain.ParseCommitLog(r, func(commit *gitdomain.Commit, modifiedFiles []string) error {
+		commits = append(commits, &wrappedCommit{Commit: commit, files: modifiedFiles})
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return commits, nil
 }
 
-func commitSplitFunc(data []byte, atEOF bool) (advance int, token []byte, err error) {
-	if len(data) == 0 {
-		// Request more data
-		return 0, nil, nil
-	}
-
-	// Safety check: ensure we are always starting with a record separator
-	if data[0] != '\x1e' {
-		return 0, nil, errors.New("internal error: data should always start with an ASCII record separator")
-	}
-
-	loc := bytes.IndexByte(data[1:], '\x1e')
-	if loc < 0 {
-		// We can't find the start of the next record
-		if atEOF {
-			// If we're at the end of the stream, just return the rest as the last record
-			return len(data), data[1:], bufio.ErrFinalToken
-		} else {
-			// If we're not at the end of the stream, request more data
-			return 0, nil, nil
-		}
-	}
-	nextStart := loc + 1 // correct for searching at an offset
-
-	return nextStart, data[1:nextStart], nil
-}
-
 type wrappedCommit struct {
 	*gitdomain.Commit
 	files []string
@@ -1994,6 +2167,9 @@ func commitLogArgs(initialArgs []string, opt CommitsOptions) (args []string, err
 	if opt.DateOrder {
 		args = append(args, "--date-order")
 	}
+	if opt.FirstParent {
+		args = append(args, "--first-parent")
+	}
 
 	if opt.MessageQuery != "" {
 		args = append(args, "--fixed-strings", "--regexp-ignore-case", "--grep="+opt.MessageQuery)
@@ -2040,6 +2216,43 @@ func (c *clientImplementor) FirstEverCommit(ctx context.Context, repo api.RepoNa
 	return c.GetCommit(ctx, repo, id, ResolveRevisionOptions{NoEnsureRevision: true})
 }
 
+// hgChangesetPattern matches Mercurial changeset IDs and prefixes of them that
+// are long enough to be unambiguous in practice.
+var hgChangesetPattern = lazyregexp.New(`^[0-9a-f]{12,40}$`)
+
+func (c *clientImplementor) HgChangesetCommit(ctx context.Context, repo api.RepoName, changeset string) (_ api.CommitID, err error) {
+	ctx, _, endObservation := c.operations.hgChangesetCommit.With(ctx, &err, observation.Args{
+		MetricLabelValues: []string{c.scope},
+		Attrs: []attribute.KeyValue{
+			repo.Attr(),
+			attribute.String("changeset", changeset),
+		},
+	})
+	defer endObservation(1, observation.Args{})
+
+	if !hgChangesetPattern.MatchString(changeset) {
+		return "", errors.Newf("invalid Mercurial changeset ID %q", changeset)
+	}
+
+	// With --notes, --grep also matches the notes of a commit. It matches the
+	// commit message as well, so the notes of the candidates are checked below.
+	args := []string{"log", "--all", "--notes=hg", "--fixed-strings", "--grep=" + changeset, "--format=%x00%H%x00%N"}
+	cmd := c.gitCommand(repo, args...)
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		return "", errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", args, out))
+	}
+
+	fields := strings.Split(string(out), "\x00")
+	for i := 1; i+1 < len(fields); i += 2 {
+		commit, note := fields[i], fields[i+1]
+		if strings.HasPrefix(strings.TrimSpace(note), changeset) {
+			return api.CommitID(commit), nil
+		}
+	}
+	return "", &gitdomain.RevisionNotFoundError{Repo: repo, Spec: changeset}
+}
+
 // CommitExists determines if the given commit exists in the given repository.
 func (c *clientImplementor) CommitExists(ctx context.Context, repo api.RepoName, id api.CommitID) (_ bool, err error) {
 	ctx, _, endObservation := c.operations.commitExists.With(ctx, &err, observation.Args{
@@ -2168,7 +2381,7 @@ func (c *clientImplementor) GetCommits(ctx context.Context, repoCommits []api.Re
 
 	opts := BatchLogOptions{
 		RepoCommits: repoCommits,
-		Format:      logFormatWithoutRefs,
+		Format:      gitdomain.CommitLogFormat,
 	}
 	if err := c.BatchLog(ctx, opts, callback); err != nil {
 		return nil, errors.Wrap(err, "gitserver.BatchLog")
@@ -2217,68 +2430,6 @@ func checkError(err error) (string, bool, error) {
 	return "", false, err
 }
 
-const (
-	partsPerCommit = 10 // number of \x00-separated fields per commit
-
-	// This format string has 10 parts:
-	//  1) oid
-	//  2) author name
-	//  3) author email
-	//  4) author time
-	//  5) committer name
-	//  6) committer email
-	//  7) committer time
-	//  8) message body
-	//  9) parent hashes
-	// 10) modified files (optional)
-	//
-	// Each commit starts with an ASCII record separator byte (0x1E), and
-	// each field of the commit is separated by a null byte (0x00).
-	//
-	// Refs are slow, and are intentionally not included because they are usually not needed.
-	logFormatWithoutRefs = "--format=format:%x1e%H%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct%x00%B%x00%P%x00"
-)
-
-// parseCommitFromLog parses the next commit from data and returns the commit and the remaining
-// data. The data arg is a byte array that contains NUL-separated log fields as formatted by
-// logFormatFlag.
-func parseCommitFromLog(parts [][]byte) (*wrappedCommit, error) {
-	// log outputs are newline separated, so all but the 1st commit ID part
-	// has an erroneous leading newline.
-	parts[0] = bytes.TrimPrefix(parts[0], []byte{'\n'})
-	commitID := api.CommitID(parts[0])
-
-	authorTime, err := strconv.ParseInt(string(parts[3]), 10, 64)
-	if err != nil {
-		return nil, errors.Errorf("parsing git commit author time: %s", err)
-	}
-	committerTime, err := strconv.ParseInt(string(parts[6]), 10, 64)
-	if err != nil {
-		return nil, errors.Errorf("parsing git commit committer time: %s", err)
-	}
-
-	var parents []api.CommitID
-	if parentPart := parts[8]; len(parentPart) > 0 {
-		parentIDs := bytes.Split(parentPart, []byte{' '})
-		parents = make([]api.CommitID, len(parentIDs))
-		for i, id := range parentIDs {
-			parents[i] = api.CommitID(id)
-		}
-	}
-
-	fileNames := strings.Split(string(bytes.TrimSpace(parts[9])), "\n")
-
-	return &wrappedCommit{
-		Commit: &gitdomain.Commit{
-			ID:        commitID,
-			Author:    gitdomain.Signature{Name: string(parts[1]), Email: string(parts[2]), Date: time.Unix(authorTime, 0).UTC()},
-			Committer: &gitdomain.Signature{Name: string(parts[4]), Email: string(parts[5]), Date: time.Unix(committerTime, 0).UTC()},
-			Message:   gitdomain.Message(strings.TrimSuffix(string(parts[7]), "\n")),
-			Parents:   parents,
-		}, files: fileNames,
-	}, nil
-}
-
 // BranchesContaining returns a map from branch names to branch tip hashes for
 // each branch containing the given commit.
 func (c *clientImplementor) BranchesContaining(ctx context.Context, repo api.RepoName, commit api.CommitID) (_ []string, err error) {
@@ -5,11 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"io/fs"
 	"os"
-	"path/filepath"
-	"reflect"
-	"runtime"
 	"sort"
 	"strings"
 	"testing"
@@ -18,182 +14,19 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/google/go-cmp/cmp"
-	godiff "github.com/sourcegraph/go-diff/diff"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
-func TestParseShortLog(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   string // in the format of `git shortlog -sne`
-		want    []*gitdomain.ContributorCount
-		wantErr error
-	}{
-		{
-			name: "basic",
-			input: `
-  1125	Jane Doe <jane@sourcegraph.com>
-   390	Bot Of Doom <bot@doombot.com>
-`,
-			want: []*gitdomain.ContributorCount{
-				{
-					Name:  "Jane Doe",
-					Email: "jane@sourcegraph.com",
-					Count: 1125,
-				},
-				{
-					Name:  "Bot Of Doom",
-					Email: "bot@doombot.com",
-					Count: 390,
-				},
-			},
-		},
-		{
-			name: "commonly malformed (email address as name)",
-			input: `  1125	jane@sourcegraph.com <jane@sourcegraph.com>
-   390	Bot Of Doom <bot@doombot.com>
-`,
-			want: []*gitdomain.ContributorCount{
-				{
-					Name:  "jane@sourcegraph.com",
-					Email: "jane@sourcegraph.com",
-					Count: 1125,
-				},
-				{
-					Name:  "Bot Of Doom",
-					Email: "bot@doombot.com",
-					Count: 390,
-				},
-			},
-		},
-	}
-	for _, tst := range tests {
-		t.Run(tst.name, func(t *testing.T) {
-			got, gotErr := parseShortLog([]byte(tst.input))
-			if (gotErr == nil) != (tst.wantErr == nil) {
-				t.Fatalf("gotErr %+v wantErr %+v", gotErr, tst.wantErr)
-			}
-			if !reflect.DeepEqual(got, tst.want) {
-				t.Logf("got %q", got)
-				t.Fatalf("want %q", tst.want)
-			}
-		})
-	}
-}
-
-func TestDiffWithSubRepoFiltering(t *testing.T) {
-	ctx := context.Background()
-	ctx = actor.WithActor(ctx, &actor.Actor{
-		UID: 1,
-	})
-
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-
-	cmds := getGitCommandsWithFileLists([]string{"file0"}, []string{"file1", "file1.1"}, []string{"file2"}, []string{"file3", "file3.3"})
-	checker := getTestSubRepoPermsChecker("file1.1", "file2")
-	testCases := []struct {
-		label               string
-		extraGitCommands    []string
-		expectedDiffFiles   []string
-		expectedFileStat    *godiff.Stat
-		rangeOverAllCommits bool
-	}{
-		{
-			label:               "adding files",
-			expectedDiffFiles:   []string{"file1", "file3", "file3.3"},
-			expectedFileStat:    &godiff.Stat{Added: 3},
-			rangeOverAllCommits: true,
-		},
-		{
-			label: "changing filename",
-			extraGitCommands: []string{
-				"mv file1.1 file_can_access",
-				"git add file_can_access",
-				makeGitCommit("rename", 7),
-			},
-			expectedDiffFiles: []string{"file_can_access"},
-			expectedFileStat:  &godiff.Stat{Added: 1},
-		},
-		{
-			label: "file modified",
-			extraGitCommands: []string{
-				"echo new_file_content > file2",
-				"echo more_new_file_content > file1",
-				"git add file2",
-				"git add file1",
-				makeGitCommit("edit_files", 7),
-			},
-			expectedDiffFiles: []string{"file1"}, // file2 is updated but user doesn't have access
-			expectedFileStat:  &godiff.Stat{Changed: 1},
-		},
-		{
-			label: "diff for commit w/ no access returns empty result",
-			extraGitCommands: []string{
-				"echo new_file_content > file2",
-				"git add file2",
-				makeGitCommit("no_access", 7),
-			},
-			expectedDiffFiles: []string{},
-			expectedFileStat:  &godiff.Stat{},
-		},
-	}
-	for _, tc := range testCases {
-		t.Run(tc.label, func(t *testing.T) {
-			repo := MakeGitRepository(t, append(cmds, tc.extraGitCommands...)...)
-			c := NewTestClient(t)
-			commits, err := c.Commits(ctx, repo, CommitsOptions{})
-			if err != nil {
-				t.Fatalf("err fetching commits: %s", err)
-			}
-			baseCommit := commits[1]
-			headCommit := commits[0]
-			if tc.rangeOverAllCommits {
-				baseCommit = commits[len(commits)-1]
-			}
-
-			c = c.WithChecker(checker)
-			iter, err := c.Diff(ctx, DiffOptions{Base: string(baseCommit.ID), Head: string(headCommit.ID), Repo: repo})
-			if err != nil {
-				t.Fatalf("error fetching diff: %s", err)
-			}
-			defer iter.Close()
-
-			stat := &godiff.Stat{}
-			fileNames := make([]string, 0, 3)
-			for {
-				file, err := iter.Next()
-				if err == io.EOF {
-					break
-				} else if err != nil {
-					t.Error(err)
-				}
-
-				fileNames = append(fileNames, file.NewName)
-
-				fileStat := file.Stat()
-				stat.Added += fileStat.Added
-				stat.Changed += fileStat.Changed
-				stat.Deleted += fileStat.Deleted
-			}
-			if diff := cmp.Diff(fileNames, tc.expectedDiffFiles); diff != "" {
-				t.Fatal(diff)
-			}
-			if diff := cmp.Diff(stat, tc.expectedFileStat); diff != "" {
-				t.Fatal(diff)
-			}
-		})
-	}
-}
-
 func TestDiff(t *testing.T) {
 	ctx := context.Background()
 
@@ -814,1043 +647,123 @@ func TestParseTags_WithoutCreatorDate(t *testing.T) {
 		{
 			Name:     "v2.6.11",
 			CommitID: "c39ae07f393806ccf406ef966e9a15afc43cc36a",
-		},
-	}
-
-	if diff := cmp.Diff(have, want); diff != "" {
-		t.Fatal(diff)
-	}
-}
-
-func TestMerger_MergeBase(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-
-	ctx := context.Background()
-	client := NewClient("test")
-
-	// TODO(sqs): implement for hg
-	// TODO(sqs): make a more complex test case
-
-	cmds := []string{
-		"echo line1 > f",
-		"git add f",
-		"git commit -m foo",
-		"git tag testbase",
-		"git checkout -b b2",
-		"echo line2 >> f",
-		"git add f",
-		"git commit -m foo",
-		"git checkout master",
-		"echo line3 > h",
-		"git add h",
-		"git commit -m qux",
-	}
-	tests := map[string]struct {
-		repo api.RepoName
-		a, b string // can be any revspec; is resolved during the test
-
-		wantMergeBase string // can be any revspec; is resolved during test
-	}{
-		"git cmd": {
-			repo: MakeGitRepository(t, cmds...),
-			a:    "master", b: "b2",
-			wantMergeBase: "testbase",
-		},
-	}
-
-	for label, test := range tests {
-		a, err := client.ResolveRevision(ctx, test.repo, test.a, ResolveRevisionOptions{})
-		if err != nil {
-			t.Errorf("%s: ResolveRevision(%q) on a: %s", label, test.a, err)
-			continue
-		}
-
-		b, err := client.ResolveRevision(ctx, test.repo, test.b, ResolveRevisionOptions{})
-		if err != nil {
-			t.Errorf("%s: ResolveRevision(%q) on b: %s", label, test.b, err)
-			continue
-		}
-
-		want, err := client.ResolveRevision(ctx, test.repo, test.wantMergeBase, ResolveRevisionOptions{})
-		if err != nil {
-			t.Errorf("%s: ResolveRevision(%q) on wantMergeBase: %s", label, test.wantMergeBase, err)
-			continue
-		}
-
-		mb, err := client.MergeBase(ctx, test.repo, a, b)
-		if err != nil {
-			t.Errorf("%s: MergeBase(%s, %s): %s", label, a, b, err)
-			continue
-		}
-
-		if mb != want {
-			t.Errorf("%s: MergeBase(%s, %s): got %q, want %q", label, a, b, mb, want)
-			continue
-		}
-	}
-}
-
-func TestRepository_FileSystem_Symlinks(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-
-	gitCommands := []string{
-		"touch file1",
-		"mkdir dir1",
-		"ln -s file1 link1",
-		"ln -s ../file1 dir1/link2",
-		"touch --date=2006-01-02T15:04:05Z file1 link1 dir1/link2 || touch -t " + Times[0] + " file1 link1 dir1/link2",
-		"git add link1 file1 dir1/link2",
-		"git commit -m commit1",
-	}
-
-	// map of path to size of content
-	symlinks := map[string]int64{
-		"link1":      5, // file1
-		"dir1/link2": 8, // ../file1
-	}
-
-	dir := InitGitRepository(t, gitCommands...)
-	repo := api.RepoName(filepath.Base(dir))
-
-	client := NewClient("test")
-
-	commitID := api.CommitID(ComputeCommitHash(dir, true))
-
-	ctx := context.Background()
-
-	// file1 should be a file.
-	file1Info, err := client.Stat(ctx, repo, commitID, "file1")
-	if err != nil {
-		t.Fatalf("fs.Stat(file1): %s", err)
-	}
-	if !file1Info.Mode().IsRegular() {
-		t.Errorf("file1 Stat !IsRegular (mode: %o)", file1Info.Mode())
-	}
-
-	checkSymlinkFileInfo := func(name string, link fs.FileInfo) {
-		t.Helper()
-		if link.Mode()&os.ModeSymlink == 0 {
-			t.Errorf("link mode is not symlink (mode: %o)", link.Mode())
-		}
-		if link.Name() != name {
-			t.Errorf("got link.Name() == %q, want %q", link.Name(), name)
-		}
-	}
-
-	// Check symlinks are links
-	for symlink := range symlinks {
-		fi, err := client.Stat(ctx, repo, commitID, symlink)
-		if err != nil {
-			t.Fatalf("fs.Stat(%s): %s", symlink, err)
-		}
-		if runtime.GOOS != "windows" {
-			// TODO(alexsaveliev) make it work on Windows too
-			checkSymlinkFileInfo(symlink, fi)
-		}
-	}
-
-	// Also check the FileInfo returned by ReadDir to ensure it's
-	// consistent with the FileInfo returned by lStat.
-	entries, err := client.ReadDir(ctx, repo, commitID, ".", false)
-	if err != nil {
-		t.Fatalf("fs.ReadDir(.): %s", err)
-	}
-	found := false
-	for _, entry := range entries {
-		if entry.Name() == "link1" {
-			found = true
-			if runtime.GOOS != "windows" {
-				checkSymlinkFileInfo("link1", entry)
-			}
-		}
-	}
-	if !found {
-		t.Fatal("readdir did not return link1")
-	}
-
-	for symlink, size := range symlinks {
-		fi, err := client.Stat(ctx, repo, commitID, symlink)
-		if err != nil {
-			t.Fatalf("fs.Stat(%s): %s", symlink, err)
-		}
-		if fi.Mode()&fs.ModeSymlink == 0 {
-			t.Errorf("%s Stat is not a symlink (mode: %o)", symlink, fi.Mode())
-		}
-		if fi.Name() != symlink {
-			t.Errorf("got Name %q, want %q", fi.Name(), symlink)
-		}
-		if fi.Size() != size {
-			t.Errorf("got %s Size %d, want %d", symlink, fi.Size(), size)
-		}
-	}
-}
-
-func TestStat(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-
-	gitCommands := []string{
-		"mkdir dir1",
-		"touch dir1/file1",
-		"git add dir1/file1",
-		"git commit -m commit1",
-	}
-
-	dir := InitGitRepository(t, gitCommands...)
-	repo := api.RepoName(filepath.Base(dir))
-	checker := authz.NewMockSubRepoPermissionChecker()
-	// Start disabled
-	checker.EnabledFunc.SetDefaultHook(func() bool {
-		return false
-	})
-	client := NewTestClient(t).WithChecker(checker)
-
-	commitID := api.CommitID(ComputeCommitHash(dir, true))
-
-	ctx := context.Background()
-
-	fileInfo, err := client.Stat(ctx, repo, commitID, "dir1/file1")
-	if err != nil {
-		t.Fatal(err)
-	}
-	want := "dir1/file1"
-	if diff := cmp.Diff(want, fileInfo.Name()); diff != "" {
-		t.Fatal(diff)
-	}
-
-	ctx = actor.WithActor(ctx, &actor.Actor{
-		UID: 1,
-	})
-
-	// With filtering
-	checker.EnabledFunc.SetDefaultHook(func() bool {
-		return true
-	})
-	checker.PermissionsFunc.SetDefaultHook(func(ctx context.Context, i int32, content authz.RepoContent) (authz.Perms, error) {
-		if strings.HasPrefix(content.Path, "dir2") {
-			return authz.Read, nil
-		}
-		return authz.None, nil
-	})
-	usePermissionsForFilePermissionsFunc(checker)
-	_, err = client.Stat(ctx, repo, commitID, "dir1/file1")
-	if err == nil {
-		t.Fatal(err)
-	}
-	want = "ls-tree dir1/file1: file does not exist"
-	if diff := cmp.Diff(want, err.Error()); diff != "" {
-		t.Fatal(diff)
-	}
-}
-
-var (
-	fileWithAccess      = "file-with-access"
-	fileWithoutAccess   = "file-without-access"
-	NonExistentCommitID = api.CommitID(strings.Repeat("a", 40))
-)
-
-func TestLogPartsPerCommitInSync(t *testing.T) {
-	require.Equal(t, partsPerCommit-1, strings.Count(logFormatWithoutRefs, "%x00"))
-}
-
-func TestRepository_GetCommit(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-	gitCommands := []string{
-		"git commit --allow-empty -m foo",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
-	}
-	gitCommandsWithFiles := getGitCommandsWithFiles(fileWithAccess, fileWithoutAccess)
-
-	oldRunCommitLog := runCommitLog
-
-	type testCase struct {
-		gitCmds               []string
-		id                    api.CommitID
-		wantCommit            *gitdomain.Commit
-		noEnsureRevision      bool
-		revisionNotFoundError bool
-	}
-
-	runGetCommitTests := func(checker authz.SubRepoPermissionChecker, tests map[string]testCase) {
-		for label, test := range tests {
-			t.Run(label, func(t *testing.T) {
-				client := NewTestClient(t).WithChecker(checker)
-
-				testRepo := MakeGitRepository(t, test.gitCmds...)
-				var noEnsureRevision bool
-				t.Cleanup(func() {
-					runCommitLog = oldRunCommitLog
-				})
-				runCommitLog = func(ctx context.Context, cmd GitCommand, opt CommitsOptions) ([]*wrappedCommit, error) {
-					// Track the value of NoEnsureRevision we pass to gitserver
-					noEnsureRevision = opt.NoEnsureRevision
-					return oldRunCommitLog(ctx, cmd, opt)
-				}
-
-				resolveRevisionOptions := ResolveRevisionOptions{
-					NoEnsureRevision: test.noEnsureRevision,
-				}
-				commit, err := client.GetCommit(ctx, testRepo, test.id, resolveRevisionOptions)
-				if err != nil {
-					if test.revisionNotFoundError {
-						if !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
-							t.Errorf("%s: GetCommit: expected a RevisionNotFoundError, got %s", label, err)
-						}
-						return
-					}
-					t.Errorf("%s: GetCommit: %s", label, err)
-				}
-
-				if !CommitsEqual(commit, test.wantCommit) {
-					t.Errorf("%s: got commit == %+v, want %+v", label, commit, test.wantCommit)
-					return
-				}
-
-				// Test that trying to get a nonexistent commit returns RevisionNotFoundError.
-				if _, err := client.GetCommit(ctx, testRepo, NonExistentCommitID, resolveRevisionOptions); !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
-					t.Errorf("%s: for nonexistent commit: got err %v, want RevisionNotFoundError", label, err)
-				}
-
-				if noEnsureRevision != test.noEnsureRevision {
-					t.Fatalf("Expected %t, got %t", test.noEnsureRevision, noEnsureRevision)
-				}
-			})
-		}
-	}
-
-	wantGitCommit := &gitdomain.Commit{
-		ID:        "b266c7e3ca00b1a17ad0b1449825d0854225c007",
-		Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
-		Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
-		Message:   "bar",
-		Parents:   []api.CommitID{"ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
-	}
-	tests := map[string]testCase{
-		"git cmd with NoEnsureRevision false": {
-			gitCmds:          gitCommands,
-			id:               "b266c7e3ca00b1a17ad0b1449825d0854225c007",
-			wantCommit:       wantGitCommit,
-			noEnsureRevision: false,
-		},
-		"git cmd with NoEnsureRevision true": {
-			gitCmds:          gitCommands,
-			id:               "b266c7e3ca00b1a17ad0b1449825d0854225c007",
-			wantCommit:       wantGitCommit,
-			noEnsureRevision: true,
-		},
-	}
-	// Run basic tests w/o sub-repo permissions checker
-	runGetCommitTests(nil, tests)
-	checker := getTestSubRepoPermsChecker(fileWithoutAccess)
-	// Add test cases with file names for sub-repo permissions testing
-	tests["with sub-repo permissions and access to file"] = testCase{
-		gitCmds: gitCommandsWithFiles,
-		id:      "da50eed82c8ff3c17bb642000d8aad9d434283c1",
-		wantCommit: &gitdomain.Commit{
-			ID:        "da50eed82c8ff3c17bb642000d8aad9d434283c1",
-			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-			Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-			Message:   "commit1",
-		},
-		noEnsureRevision: true,
-	}
-	tests["with sub-repo permissions and NO access to file"] = testCase{
-		gitCmds:               gitCommandsWithFiles,
-		id:                    "ee7773505e98390e809cbf518b2a92e4748b0187",
-		wantCommit:            &gitdomain.Commit{},
-		noEnsureRevision:      true,
-		revisionNotFoundError: true,
-	}
-	// Run test w/ sub-repo permissions filtering
-	runGetCommitTests(checker, tests)
-}
-
-func TestRepository_HasCommitAfter(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-
-	testCases := []struct {
-		label                 string
-		commitDates           []string
-		after                 string
-		revspec               string
-		want, wantSubRepoTest bool
-	}{
-		{
-			label: "after specific date",
-			commitDates: []string{
-				"2006-01-02T15:04:05Z",
-				"2007-01-02T15:04:05Z",
-				"2008-01-02T15:04:05Z",
-			},
-			after:           "2006-01-02T15:04:05Z",
-			revspec:         "master",
-			want:            true,
-			wantSubRepoTest: true,
-		},
-		{
-			label: "after 1 year ago",
-			commitDates: []string{
-				"2016-01-02T15:04:05Z",
-				"2017-01-02T15:04:05Z",
-				"2017-01-02T15:04:06Z",
-			},
-			after:           "1 year ago",
-			revspec:         "master",
-			want:            false,
-			wantSubRepoTest: false,
-		},
-		{
-			label: "after too recent date",
-			commitDates: []string{
-				"2006-01-02T15:04:05Z",
-				"2007-01-02T15:04:05Z",
-				"2008-01-02T15:04:05Z",
-			},
-			after:           "2010-01-02T15:04:05Z",
-			revspec:         "HEAD",
-			want:            false,
-			wantSubRepoTest: false,
-		},
-		{
-			label: "commit 1 second after",
-			commitDates: []string{
-				"2006-01-02T15:04:05Z",
-				"2007-01-02T15:04:05Z",
-				"2007-01-02T15:04:06Z",
-			},
-			after:           "2007-01-02T15:04:05Z",
-			revspec:         "HEAD",
-			want:            true,
-			wantSubRepoTest: false,
-		},
-		{
-			label: "after 10 years ago",
-			commitDates: []string{
-				"2016-01-02T15:04:05Z",
-				"2017-01-02T15:04:05Z",
-				"2017-01-02T15:04:06Z",
-			},
-			after:           "10 years ago",
-			revspec:         "HEAD",
-			want:            true,
-			wantSubRepoTest: true,
-		},
-	}
-
-	t.Run("basic", func(t *testing.T) {
-		client := NewClient("test")
-		for _, tc := range testCases {
-			t.Run(tc.label, func(t *testing.T) {
-				gitCommands := make([]string, len(tc.commitDates))
-				for i, date := range tc.commitDates {
-					gitCommands[i] = fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=%s git commit --allow-empty -m foo --author='a <a@a.com>'", date)
-				}
-				repo := MakeGitRepository(t, gitCommands...)
-				got, err := client.HasCommitAfter(ctx, repo, tc.after, tc.revspec)
-				if err != nil || got != tc.want {
-					t.Errorf("got %t hascommitafter, want %t", got, tc.want)
-				}
-			})
-		}
-	})
-
-	t.Run("with sub-repo permissions", func(t *testing.T) {
-		for _, tc := range testCases {
-			t.Run(tc.label, func(t *testing.T) {
-				gitCommands := make([]string, len(tc.commitDates))
-				for i, date := range tc.commitDates {
-					fileName := fmt.Sprintf("file%d", i)
-					gitCommands = append(gitCommands, fmt.Sprintf("touch %s", fileName), fmt.Sprintf("git add %s", fileName))
-					gitCommands = append(gitCommands, fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=%s git commit -m commit%d --author='a <a@a.com>'", date, i))
-				}
-				// Case where user can't view commit 2, but can view commits 0 and 1. In each test case the result should match the case where no sub-repo perms enabled
-				checker := getTestSubRepoPermsChecker("file2")
-				client := NewTestClient(t).WithChecker(checker)
-				repo := MakeGitRepository(t, gitCommands...)
-				got, err := client.HasCommitAfter(ctx, repo, tc.after, tc.revspec)
-				if err != nil {
-					t.Errorf("got error: %s", err)
-				}
-				if got != tc.want {
-					t.Errorf("got %t hascommitafter, want %t", got, tc.want)
-				}
-
-				// Case where user can't view commit 1 or commit 2, which will mean in some cases since HasCommitAfter will be false due to those commits not being visible.
-				checker = getTestSubRepoPermsChecker("file1", "file2")
-				client = NewTestClient(t).WithChecker(checker)
-				got, err = client.HasCommitAfter(ctx, repo, tc.after, tc.revspec)
-				if err != nil {
-					t.Errorf("got error: %s", err)
-				}
-				if got != tc.wantSubRepoTest {
-					t.Errorf("got %t hascommitafter, want %t", got, tc.wantSubRepoTest)
-				}
-			})
-		}
-	})
-}
-
-func TestRepository_FirstEverCommit(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-
-	testCases := []struct {
-		commitDates []string
-		want        string
-	}{
-		{
-			commitDates: []string{
-				"2006-01-02T15:04:05Z",
-				"2007-01-02T15:04:05Z",
-				"2008-01-02T15:04:05Z",
-			},
-			want: "2006-01-02T15:04:05Z",
-		},
-		{
-			commitDates: []string{
-				"2007-01-02T15:04:05Z", // Don't think this is possible, but if it is we still want the first commit (not strictly "oldest")
-				"2006-01-02T15:04:05Z",
-				"2007-01-02T15:04:06Z",
-			},
-			want: "2007-01-02T15:04:05Z",
-		},
-	}
-	client := NewClient("test")
-	t.Run("basic", func(t *testing.T) {
-		for _, tc := range testCases {
-			gitCommands := make([]string, len(tc.commitDates))
-			for i, date := range tc.commitDates {
-				gitCommands[i] = fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=%s git commit --allow-empty -m foo --author='a <a@a.com>'", date)
-			}
-
-			repo := MakeGitRepository(t, gitCommands...)
-			gotCommit, err := client.FirstEverCommit(ctx, repo)
-			if err != nil {
-				t.Fatal(err)
-			}
-			got := gotCommit.Committer.Date.Format(time.RFC3339)
-			if got != tc.want {
-				t.Errorf("got %q, want %q", got, tc.want)
-			}
-		}
-	})
-
-	// Added for awareness if this error message changes. Insights skip over empty repos and check against error message
-	t.Run("empty repo", func(t *testing.T) {
-		repo := MakeGitRepository(t)
-		_, err := client.FirstEverCommit(ctx, repo)
-		wantErr := `git command [rev-list --reverse --date-order --max-parents=0 HEAD] failed (output: ""): exit status 128`
-		if err.Error() != wantErr {
-			t.Errorf("expected :%s, got :%s", wantErr, err)
-		}
-	})
-
-	t.Run("with sub-repo permissions", func(t *testing.T) {
-		clientWithoutAccessFirstCommit := NewTestClient(t).WithChecker(getTestSubRepoPermsChecker("file0"))
-		clientWithAccessFirstCommit := NewTestClient(t).WithChecker(getTestSubRepoPermsChecker("file1"))
-		for _, tc := range testCases {
-			gitCommands := make([]string, 0, len(tc.commitDates))
-			for i, date := range tc.commitDates {
-				fileName := fmt.Sprintf("file%d", i)
-				gitCommands = append(gitCommands, fmt.Sprintf("touch %s", fileName))
-				gitCommands = append(gitCommands, fmt.Sprintf("git add %s", fileName))
-				gitCommands = append(gitCommands, fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=%s git commit -m foo --author='a <a@a.com>'", date))
-			}
-
-			repo := MakeGitRepository(t, gitCommands...)
-
-			// Try to get first commit when user doesn't have permission to view
-			_, err := clientWithoutAccessFirstCommit.FirstEverCommit(ctx, repo)
-			if !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
-				t.Errorf("expected a RevisionNotFoundError since the user does not have access to view this commit, got :%s", err)
-			}
-			// Try to get first commit when user does have permission to view, should succeed
-			gotCommit, err := clientWithAccessFirstCommit.FirstEverCommit(ctx, repo)
-			if err != nil {
-				t.Fatal(err)
-			}
-			got := gotCommit.Committer.Date.Format(time.RFC3339)
-			if got != tc.want {
-				t.Errorf("got %q, want %q", got, tc.want)
-			}
-			// Internal actor should always have access and ignore sub-repo permissions
-			newCtx := actor.WithActor(context.Background(), &actor.Actor{
-				UID:      1,
-				Internal: true,
-			})
-			gotCommit, err = clientWithoutAccessFirstCommit.FirstEverCommit(newCtx, repo)
-			if err != nil {
-				t.Fatal(err)
-			}
-			got = gotCommit.Committer.Date.Format(time.RFC3339)
-			if got != tc.want {
-				t.Errorf("got %q, want %q", got, tc.want)
-			}
-		}
-	})
-}
-
-func TestCommitExists(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-	testCommitExists := func(label string, gitCommands []string, commitID, nonExistentCommitID api.CommitID, checker authz.SubRepoPermissionChecker) {
-		t.Run(label, func(t *testing.T) {
-			client := NewTestClient(t).WithChecker(checker)
-			repo := MakeGitRepository(t, gitCommands...)
-
-			exists, err := client.CommitExists(ctx, repo, commitID)
-			if err != nil {
-				t.Fatal(err)
-			}
-			if !exists {
-				t.Fatal("Should exist")
-			}
-
-			exists, err = client.CommitExists(ctx, repo, nonExistentCommitID)
-			if err != nil {
-				t.Fatal(err)
-			}
-			if exists {
-				t.Fatal("Should not exist")
-			}
-		})
-	}
-
-	gitCommands := []string{
-		"git commit --allow-empty -m foo",
-	}
-	testCommitExists("basic", gitCommands, "ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8", NonExistentCommitID, nil)
-	gitCommandsWithFiles := getGitCommandsWithFiles(fileWithAccess, fileWithoutAccess)
-	commitIDWithAccess := api.CommitID("da50eed82c8ff3c17bb642000d8aad9d434283c1")
-	commitIDWithoutAccess := api.CommitID("ee7773505e98390e809cbf518b2a92e4748b0187")
-	// Test that the commit ID the user has access to exists, and CommitExists returns false for the commit ID the user
-	// doesn't have access to (since a file was modified in the commit that the user doesn't have permissions to view)
-	testCommitExists("with sub-repo permissions filtering", gitCommandsWithFiles, commitIDWithAccess, commitIDWithoutAccess, getTestSubRepoPermsChecker(fileWithoutAccess))
-}
-
-func TestRepository_Commits(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-
-	// TODO(sqs): test CommitsOptions.Base
-
-	gitCommands := []string{
-		"git commit --allow-empty -m foo",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
-	}
-	wantGitCommits := []*gitdomain.Commit{
-		{
-			ID:        "b266c7e3ca00b1a17ad0b1449825d0854225c007",
-			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
-			Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
-			Message:   "bar",
-			Parents:   []api.CommitID{"ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
-		},
-		{
-			ID:        "ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8",
-			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-			Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-			Message:   "foo",
-			Parents:   nil,
-		},
-	}
-	tests := map[string]struct {
-		repo        api.RepoName
-		id          api.CommitID
-		wantCommits []*gitdomain.Commit
-		wantTotal   uint
-	}{
-		"git cmd": {
-			repo:        MakeGitRepository(t, gitCommands...),
-			id:          "b266c7e3ca00b1a17ad0b1449825d0854225c007",
-			wantCommits: wantGitCommits,
-			wantTotal:   2,
-		},
-	}
-	client := NewClient("test")
-	runCommitsTests := func(checker authz.SubRepoPermissionChecker) {
-		for label, test := range tests {
-			t.Run(label, func(t *testing.T) {
-				testCommits(ctx, label, test.repo, CommitsOptions{Range: string(test.id)}, checker, test.wantCommits, t)
-
-				// Test that trying to get a nonexistent commit returns RevisionNotFoundError.
-				if _, err := client.Commits(ctx, test.repo, CommitsOptions{Range: string(NonExistentCommitID)}); !errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
-					t.Errorf("%s: for nonexistent commit: got err %v, want RevisionNotFoundError", label, err)
-				}
-			})
-		}
-	}
-	runCommitsTests(nil)
-	checker := getTestSubRepoPermsChecker()
-	runCommitsTests(checker)
-}
-
-func TestCommits_SubRepoPerms(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-	gitCommands := []string{
-		"touch file1",
-		"git add file1",
-		"git commit -m commit1",
-		"touch file2",
-		"git add file2",
-		"touch file2.2",
-		"git add file2.2",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m commit2 --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
-		"touch file3",
-		"git add file3",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m commit3 --author='a <a@a.com>' --date 2006-01-02T15:04:07Z",
-	}
-	repo := MakeGitRepository(t, gitCommands...)
-
-	tests := map[string]struct {
-		wantCommits   []*gitdomain.Commit
-		opt           CommitsOptions
-		wantTotal     uint
-		noAccessPaths []string
-	}{
-		"if no read perms on at least one file in the commit should filter out commit": {
-			wantTotal: 2,
-			wantCommits: []*gitdomain.Commit{
-				{
-					ID:        "b96d097108fa49e339ca88bc97ab07f833e62131",
-					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
-					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
-					Message:   "commit2",
-					Parents:   []api.CommitID{"d38233a79e037d2ab8170b0d0bc0aa438473e6da"},
-				},
-				{
-					ID:        "d38233a79e037d2ab8170b0d0bc0aa438473e6da",
-					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-					Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-					Message:   "commit1",
-				},
-			},
-			noAccessPaths: []string{"file2", "file3"},
-		},
-		"sub-repo perms with path (w/ no access) specified should return no commits": {
-			wantTotal: 1,
-			opt: CommitsOptions{
-				Path: "file2",
-			},
-			wantCommits:   []*gitdomain.Commit{},
-			noAccessPaths: []string{"file2", "file3"},
-		},
-		"sub-repo perms with path (w/ access) specified should return that commit": {
-			wantTotal: 1,
-			opt: CommitsOptions{
-				Path: "file1",
-			},
-			wantCommits: []*gitdomain.Commit{
-				{
-					ID:        "d38233a79e037d2ab8170b0d0bc0aa438473e6da",
-					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-					Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-					Message:   "commit1",
-				},
-			},
-			noAccessPaths: []string{"file2", "file3"},
-		},
-	}
-
-	for label, test := range tests {
-		t.Run(label, func(t *testing.T) {
-			checker := getTestSubRepoPermsChecker(test.noAccessPaths...)
-			client := NewTestClient(t).WithChecker(checker)
-			commits, err := client.Commits(ctx, repo, test.opt)
-			if err != nil {
-				t.Errorf("%s: Commits(): %s", label, err)
-				return
-			}
-
-			if len(commits) != len(test.wantCommits) {
-				t.Errorf("%s: got %d commits, want %d", label, len(commits), len(test.wantCommits))
-			}
-
-			checkCommits(t, commits, test.wantCommits)
-		})
-	}
-}
-
-func TestCommits_SubRepoPerms_ReturnNCommits(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-	gitCommands := []string{
-		"touch file1",
-		"git add file1",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:01Z git commit -m commit1 --author='a <a@a.com>' --date 2006-01-02T15:04:01Z",
-		"touch file2",
-		"git add file2",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:02Z git commit -m commit2 --author='a <a@a.com>' --date 2006-01-02T15:04:02Z",
-		"echo foo > file1",
-		"git add file1",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:03Z git commit -m commit3 --author='a <a@a.com>' --date 2006-01-02T15:04:03Z",
-		"echo asdf > file1",
-		"git add file1",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:04Z git commit -m commit4 --author='a <a@a.com>' --date 2006-01-02T15:04:04Z",
-		"echo bar > file1",
-		"git add file1",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit5 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
-		"echo asdf2 > file2",
-		"git add file2",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit -m commit6 --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
-		"echo bazz > file1",
-		"git add file1",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit -m commit7 --author='a <a@a.com>' --date 2006-01-02T15:04:07Z",
-		"echo bazz > file2",
-		"git add file2",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:08Z git commit -m commit8 --author='a <a@a.com>' --date 2006-01-02T15:04:08Z",
-	}
-
-	tests := map[string]struct {
-		repo          api.RepoName
-		wantCommits   []*gitdomain.Commit
-		opt           CommitsOptions
-		wantTotal     uint
-		noAccessPaths []string
-	}{
-		"return the requested number of commits": {
-			repo:      MakeGitRepository(t, gitCommands...),
-			wantTotal: 3,
-			opt: CommitsOptions{
-				N: 3,
-			},
-			wantCommits: []*gitdomain.Commit{
-				{
-					ID:        "61dbc35f719c53810904a2d359309d4e1e98a6be",
-					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
-					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
-					Message:   "commit7",
-					Parents:   []api.CommitID{"66566c8aa223f3e1b94ebe09e6cdb14c3a5bfb36"},
-				},
-				{
-					ID:        "2e6b2c94293e9e339f781b2a2f7172e15460f88c",
-					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-					Parents: []api.CommitID{
-						"9a7ec70986d657c4c86d6ac476f0c5181ece509a",
-					},
-					Message: "commit5",
-				},
-				{
-					ID:        "9a7ec70986d657c4c86d6ac476f0c5181ece509a",
-					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:04Z")},
-					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:04Z")},
-					Message:   "commit4",
-					Parents: []api.CommitID{
-						"f3fa8cf6ec56d0469402523385d6ca4b7cb222d8",
-					},
-				},
-			},
-			noAccessPaths: []string{"file2"},
-		},
-	}
-
-	for label, test := range tests {
-		t.Run(label, func(t *testing.T) {
-			checker := getTestSubRepoPermsChecker(test.noAccessPaths...)
-			client := NewTestClient(t).WithChecker(checker)
-			commits, err := client.Commits(ctx, test.repo, test.opt)
-			if err != nil {
-				t.Errorf("%s: Commits(): %s", label, err)
-				return
-			}
+		},
+	}
 
-			if diff := cmp.Diff(test.wantCommits, commits); diff != "" {
-				t.Fatal(diff)
-			}
-		})
+	if diff := cmp.Diff(have, want); diff != "" {
+		t.Fatal(diff)
 	}
 }
 
-func TestRepository_Commits_options(t *testing.T) {
+func TestMerger_MergeBase(t *testing.T) {
 	ClientMocks.LocalGitserver = true
 	defer ResetClientMocks()
+
 	ctx := context.Background()
-	ctx = actor.WithActor(ctx, actor.FromUser(42))
+	client := NewClient("test")
 
-	gitCommands := []string{
-		"git commit --allow-empty -m foo",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:08Z git commit --allow-empty -m qux --author='a <a@a.com>' --date 2006-01-02T15:04:08Z",
-	}
-	wantGitCommits := []*gitdomain.Commit{
-		{
-			ID:        "b266c7e3ca00b1a17ad0b1449825d0854225c007",
-			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
-			Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
-			Message:   "bar",
-			Parents:   []api.CommitID{"ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
-		},
-	}
-	wantGitCommits2 := []*gitdomain.Commit{
-		{
-			ID:        "ade564eba4cf904492fb56dcd287ac633e6e082c",
-			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:08Z")},
-			Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:08Z")},
-			Message:   "qux",
-			Parents:   []api.CommitID{"b266c7e3ca00b1a17ad0b1449825d0854225c007"},
-		},
+	// TODO(sqs): implement for hg
+	// TODO(sqs): make a more complex test case
+
+	cmds := []string{
+		"echo line1 > f",
+		"git add f",
+		"git commit -m foo",
+		"git tag testbase",
+		"git checkout -b b2",
+		"echo line2 >> f",
+		"git add f",
+		"git commit -m foo",
+		"git checkout master",
+		"echo line3 > h",
+		"git add h",
+		"git commit -m qux",
 	}
 	tests := map[string]struct {
-		opt         CommitsOptions
-		wantCommits []*gitdomain.Commit
-		wantTotal   uint
+		repo api.RepoName
+		a, b string // can be any revspec; is resolved during the test
+
+		wantMergeBase string // can be any revspec; is resolved during test
 	}{
 		"git cmd": {
-			opt:         CommitsOptions{Range: "ade564eba4cf904492fb56dcd287ac633e6e082c", N: 1, Skip: 1},
-			wantCommits: wantGitCommits,
-			wantTotal:   1,
-		},
-		"git cmd Head": {
-			opt: CommitsOptions{
-				Range: "b266c7e3ca00b1a17ad0b1449825d0854225c007...ade564eba4cf904492fb56dcd287ac633e6e082c",
-			},
-			wantCommits: wantGitCommits2,
-			wantTotal:   1,
-		},
-		"before": {
-			opt: CommitsOptions{
-				Before: "2006-01-02T15:04:07Z",
-				Range:  "HEAD",
-				N:      1,
-			},
-			wantCommits: []*gitdomain.Commit{
-				{
-					ID:        "b266c7e3ca00b1a17ad0b1449825d0854225c007",
-					Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
-					Committer: &gitdomain.Signature{Name: "c", Email: "c@c.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:07Z")},
-					Message:   "bar",
-					Parents:   []api.CommitID{"ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
-				},
-			},
-			wantTotal: 1,
+			repo: MakeGitRepository(t, cmds...),
+			a:    "master", b: "b2",
+			wantMergeBase: "testbase",
 		},
 	}
-	runCommitsTests := func(checker authz.SubRepoPermissionChecker) {
-		for label, test := range tests {
-			t.Run(label, func(t *testing.T) {
-				repo := MakeGitRepository(t, gitCommands...)
-				testCommits(ctx, label, repo, test.opt, checker, test.wantCommits, t)
-			})
+
+	for label, test := range tests {
+		a, err := client.ResolveRevision(ctx, test.repo, test.a, ResolveRevisionOptions{})
+		if err != nil {
+			t.Errorf("%s: ResolveRevision(%q) on a: %s", label, test.a, err)
+			continue
 		}
-		// Added for awareness if this error message changes. Insights record last repo indexing and consider empty
-		// repos a success case.
-		subRepo := ""
-		if checker != nil {
-			subRepo = " sub repo enabled"
+
+		b, err := client.ResolveRevision(ctx, test.repo, test.b, ResolveRevisionOptions{})
+		if err != nil {
+			t.Errorf("%s: ResolveRevision(%q) on b: %s", label, test.b, err)
+			continue
+		}
+
+		want, err := client.ResolveRevision(ctx, test.repo, test.wantMergeBase, ResolveRevisionOptions{})
+		if err != nil {
+			t.Errorf("%s: ResolveRevision(%q) on wantMergeBase: %s", label, test.wantMergeBase, err)
+			continue
+		}
+
+		mb, err := client.MergeBase(ctx, test.repo, a, b)
+		if err != nil {
+			t.Errorf("%s: MergeBase(%s, %s): %s", label, a, b, err)
+			continue
+		}
+
+		if mb != want {
+			t.Errorf("%s: MergeBase(%s, %s): got %q, want %q", label, a, b, mb, want)
+			continue
 		}
-		t.Run("empty repo"+subRepo, func(t *testing.T) {
-			repo := MakeGitRepository(t)
-			before := ""
-			after := time.Date(2022, 11, 11, 12, 10, 0, 4, time.UTC).Format(time.RFC3339)
-			client := NewTestClient(t).WithChecker(checker)
-			_, err := client.Commits(ctx, repo, CommitsOptions{N: 0, DateOrder: true, NoEnsureRevision: true, After: after, Before: before})
-			if err == nil {
-				t.Error("expected error, got nil")
-			}
-			wantErr := `git command [git log --format=format:%x1e%H%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct%x00%B%x00%P%x00 --after=` + after + " --date-order"
-			if subRepo != "" {
-				wantErr += " --name-only"
-			}
-			wantErr += `] failed (output: ""): exit status 128`
-			if err.Error() != wantErr {
-				t.Errorf("expected:%v got:%v", wantErr, err.Error())
-			}
-		})
 	}
-	runCommitsTests(nil)
-	checker := getTestSubRepoPermsChecker()
-	runCommitsTests(checker)
 }
 
-func TestRepository_Commits_options_path(t *testing.T) {
+func TestHgChangesetCommit(t *testing.T) {
 	ClientMocks.LocalGitserver = true
 	defer ResetClientMocks()
 	ctx := actor.WithActor(context.Background(), &actor.Actor{
 		UID: 1,
 	})
 
-	gitCommands := []string{
-		"git commit --allow-empty -m commit1",
-		"touch file1",
-		"touch --date=2006-01-02T15:04:05Z file1 || touch -t " + Times[0] + " file1",
-		"git add file1",
-		"git commit -m commit2",
-		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m commit3 --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
-	}
-	wantGitCommits := []*gitdomain.Commit{
-		{
-			ID:        "546a3ef26e581624ef997cb8c0ba01ee475fc1dc",
-			Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-			Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-			Message:   "commit2",
-			Parents:   []api.CommitID{"a04652fa1998a0a7d2f2f77ecb7021de943d3aab"},
-		},
-	}
-	tests := map[string]struct {
-		opt         CommitsOptions
-		wantCommits []*gitdomain.Commit
-	}{
-		"git cmd Path 0": {
-			opt: CommitsOptions{
-				Range: "master",
-				Path:  "doesnt-exist",
-			},
-			wantCommits: nil,
-		},
-		"git cmd Path 1": {
-			opt: CommitsOptions{
-				Range: "master",
-				Path:  "file1",
-			},
-			wantCommits: wantGitCommits,
-		},
-		"git cmd non utf8": {
-			opt: CommitsOptions{
-				Range:  "master",
-				Author: "a\xc0rn",
-			},
-			wantCommits: nil,
-		},
-	}
+	const (
+		first  = "4f7e8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f"
+		second = "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"
+	)
+	repo := MakeGitRepository(t,
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo --author='a <a@a.com>'",
+		"git notes --ref=hg add -m "+first+" HEAD",
+		// The message of the second commit mentions the first changeset.
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit --allow-empty -m 'backout "+first+"' --author='a <a@a.com>'",
+		"git notes --ref=hg add -m "+second+" HEAD",
+	)
 
-	runCommitsTest := func(checker authz.SubRepoPermissionChecker) {
-		for label, test := range tests {
-			t.Run(label, func(t *testing.T) {
-				repo := MakeGitRepository(t, gitCommands...)
-				testCommits(ctx, label, repo, test.opt, checker, test.wantCommits, t)
-			})
-		}
+	client := NewClient("test")
+	resolve := func(rev string) api.CommitID {
+		t.Helper()
+		commitID, err := client.ResolveRevision(ctx, repo, rev, ResolveRevisionOptions{})
+		require.NoError(t, err)
+		return commitID
 	}
-	runCommitsTest(nil)
-	checker := getTestSubRepoPermsChecker()
-	runCommitsTest(checker)
+
+	got, err := client.HgChangesetCommit(ctx, repo, first)
+	require.NoError(t, err)
+	require.Equal(t, resolve("HEAD~1"), got)
+
+	got, err = client.HgChangesetCommit(ctx, repo, second[:12])
+	require.NoError(t, err)
+	require.Equal(t, resolve("HEAD"), got)
+
+	_, err = client.HgChangesetCommit(ctx, repo, "0123456789ab")
+	require.True(t, errors.HasType(err, &gitdomain.RevisionNotFoundError{}), "got %v", err)
+
+	_, err = client.HgChangesetCommit(ctx, repo, "HEAD")
+	require.Error(t, err)
 }
 
 func TestMessage(t *testing.T) { // KEEP
@@ -2059,10 +972,27 @@ func TestFilterRefDescriptions(t *testing.T) { // KEEP
 	ctx := actor.WithActor(context.Background(), &actor.Actor{
 		UID: 1,
 	})
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	gitCommands := append(getGitCommandsWithFiles("file1", "file2"), getGitCommandsWithFiles("file3", "file4")...)
-	repo := MakeGitRepository(t, gitCommands...)
+
+	// The file modified by each commit, as reported by gitserver.
+	modifiedFiles := map[string]string{
+		"d38233a79e037d2ab8170b0d0bc0aa438473e6da": "file1",
+		"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": "file2",
+		"2775e60f523d3151a2a34ffdc659f500d0e73022": "file3",
+		"9019942b8b92d5a70a7f546d97c451621c5059a6": "file4",
+	}
+	source := NewTestClientSource(t, []string{"gitserver"}, func(o *TestClientSourceOptions) {
+		o.ClientFunc = func(conn *grpc.ClientConn) proto.GitserverServiceClient {
+			c := NewMockGitserverServiceClient()
+			c.CommitsFunc.SetDefaultHook(func(_ context.Context, req *proto.CommitsRequest, _ ...grpc.CallOption) (proto.GitserverService_CommitsClient, error) {
+				require.True(t, req.GetNameOnly())
+				return &fakeCommitsStream{commits: []*proto.GitCommit{{
+					Oid:           req.GetRange(),
+					ModifiedFiles: [][]byte{[]byte(modifiedFiles[req.GetRange()])},
+				}}}, nil
+			})
+			return c
+		}
+	})
 
 	refDescriptions := map[string][]gitdomain.RefDescription{
 		"d38233a79e037d2ab8170b0d0bc0aa438473e6da": {},
@@ -2072,8 +1002,8 @@ func TestFilterRefDescriptions(t *testing.T) { // KEEP
 	}
 
 	checker := getTestSubRepoPermsChecker("file3")
-	client := NewTestClient(t).WithChecker(checker).(*clientImplementor)
-	filtered := client.filterRefDescriptions(ctx, repo, refDescriptions)
+	client := NewTestClient(t).WithClientSource(source).WithChecker(checker).(*clientImplementor)
+	filtered := client.filterRefDescriptions(ctx, "repo", refDescriptions)
 	expectedRefDescriptions := map[string][]gitdomain.RefDescription{
 		"d38233a79e037d2ab8170b0d0bc0aa438473e6da": {},
 		"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": {},
@@ -2084,168 +1014,18 @@ func TestFilterRefDescriptions(t *testing.T) { // KEEP
 	}
 }
 
-func TestRefDescriptions(t *testing.T) { // KEEP
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-	client := NewClient("test")
-	gitCommands := append(getGitCommandsWithFiles("file1", "file2"), "git checkout -b my-other-branch")
-	gitCommands = append(gitCommands, getGitCommandsWithFiles("file1-b2", "file2-b2")...)
-	gitCommands = append(gitCommands, "git checkout -b my-branch-no-access")
-	gitCommands = append(gitCommands, getGitCommandsWithFiles("file", "file-with-no-access")...)
-	repo := MakeGitRepository(t, gitCommands...)
-
-	makeBranch := func(name, createdDate string, isDefaultBranch bool) gitdomain.RefDescription {
-		return gitdomain.RefDescription{Name: name, Type: gitdomain.RefTypeBranch, IsDefaultBranch: isDefaultBranch, CreatedDate: mustParseDate(createdDate, t)}
-	}
-
-	t.Run("basic", func(t *testing.T) {
-		refDescriptions, err := client.RefDescriptions(ctx, repo)
-		if err != nil {
-			t.Errorf("err calling RefDescriptions: %s", err)
-		}
-		expectedRefDescriptions := map[string][]gitdomain.RefDescription{
-			"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": {makeBranch("master", "2006-01-02T15:04:05Z", false)},
-			"9d7a382983098eed6cf911bd933dfacb13116e42": {makeBranch("my-other-branch", "2006-01-02T15:04:05Z", false)},
-			"7cf006d0599531db799c08d3b00d7fd06da33015": {makeBranch("my-branch-no-access", "2006-01-02T15:04:05Z", true)},
-		}
-		if diff := cmp.Diff(expectedRefDescriptions, refDescriptions); diff != "" {
-			t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
-		}
-	})
-
-	t.Run("with sub-repo enabled", func(t *testing.T) {
-		checker := getTestSubRepoPermsChecker("file-with-no-access")
-		client2 := NewTestClient(t).WithChecker(checker)
-		refDescriptions, err := client2.RefDescriptions(ctx, repo)
-		if err != nil {
-			t.Errorf("err calling RefDescriptions: %s", err)
-		}
-		expectedRefDescriptions := map[string][]gitdomain.RefDescription{
-			"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": {makeBranch("master", "2006-01-02T15:04:05Z", false)},
-			"9d7a382983098eed6cf911bd933dfacb13116e42": {makeBranch("my-other-branch", "2006-01-02T15:04:05Z", false)},
-		}
-		if diff := cmp.Diff(expectedRefDescriptions, refDescriptions); diff != "" {
-			t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
-		}
-	})
-}
-
-func TestCommitsUniqueToBranch(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-	gitCommands := append([]string{"git checkout -b my-branch"}, getGitCommandsWithFiles("file1", "file2")...)
-	gitCommands = append(gitCommands, getGitCommandsWithFiles("file3", "file-with-no-access")...)
-	repo := MakeGitRepository(t, gitCommands...)
-
-	t.Run("basic", func(t *testing.T) {
-		client := NewClient("test")
-		commits, err := client.CommitsUniqueToBranch(ctx, repo, "my-branch", true, &time.Time{})
-		if err != nil {
-			t.Errorf("err calling RefDescriptions: %s", err)
-		}
-		expectedCommits := map[string]time.Time{
-			"2775e60f523d3151a2a34ffdc659f500d0e73022": *mustParseDate("2006-01-02T15:04:05-00:00", t),
-			"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": *mustParseDate("2006-01-02T15:04:05-00:00", t),
-			"791ce7cd8ca2d855e12f47f8692a62bc42477edc": *mustParseDate("2006-01-02T15:04:05-00:00", t),
-			"d38233a79e037d2ab8170b0d0bc0aa438473e6da": *mustParseDate("2006-01-02T15:04:05-00:00", t),
-		}
-		if diff := cmp.Diff(expectedCommits, commits); diff != "" {
-			t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
-		}
-	})
-
-	t.Run("with sub-repo enabled", func(t *testing.T) {
-		checker := getTestSubRepoPermsChecker("file-with-no-access")
-		client := NewTestClient(t).WithChecker(checker)
-		commits, err := client.CommitsUniqueToBranch(ctx, repo, "my-branch", true, &time.Time{})
-		if err != nil {
-			t.Errorf("err calling RefDescriptions: %s", err)
-		}
-		expectedCommits := map[string]time.Time{
-			"2775e60f523d3151a2a34ffdc659f500d0e73022": *mustParseDate("2006-01-02T15:04:05-00:00", t),
-			"2ba4dd2b9a27ec125fea7d72e12b9824ead18631": *mustParseDate("2006-01-02T15:04:05-00:00", t),
-			"d38233a79e037d2ab8170b0d0bc0aa438473e6da": *mustParseDate("2006-01-02T15:04:05-00:00", t),
-		}
-		if diff := cmp.Diff(expectedCommits, commits); diff != "" {
-			t.Errorf("unexpected ref descriptions (-want +got):\n%s", diff)
-		}
-	})
-}
-
-func TestCommitDate(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	defer ResetClientMocks()
-	ctx := actor.WithActor(context.Background(), &actor.Actor{
-		UID: 1,
-	})
-	gitCommands := getGitCommandsWithFiles("file1", "file2")
-	repo := MakeGitRepository(t, gitCommands...)
-
-	t.Run("basic", func(t *testing.T) {
-		client := NewClient("test")
-		_, date, commitExists, err := client.CommitDate(ctx, repo, "d38233a79e037d2ab8170b0d0bc0aa438473e6da")
-		if err != nil {
-			t.Errorf("error fetching CommitDate: %s", err)
-		}
-		if !commitExists {
-			t.Errorf("commit should exist")
-		}
-		if !date.Equal(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)) {
-			t.Errorf("unexpected date: %s", date)
-		}
-	})
-
-	t.Run("with sub-repo permissions enabled", func(t *testing.T) {
-		checker := getTestSubRepoPermsChecker("file1")
-		client := NewTestClient(t).WithChecker(checker)
-		_, date, commitExists, err := client.CommitDate(ctx, repo, "d38233a79e037d2ab8170b0d0bc0aa438473e6da")
-		if err != nil {
-			t.Errorf("error fetching CommitDate: %s", err)
-		}
-		if commitExists {
-			t.Errorf("expect commit to not exist since the user doesn't have access")
-		}
-		if !date.IsZero() {
-			t.Errorf("expected date to be empty, got: %s", date)
-		}
-	})
-}
-
-func testCommits(ctx context.Context, label string, repo api.RepoName, opt CommitsOptions, checker authz.SubRepoPermissionChecker, wantCommits []*gitdomain.Commit, t *testing.T) {
-	t.Helper()
-	client := NewTestClient(t).WithChecker(checker)
-	commits, err := client.Commits(ctx, repo, opt)
-	if err != nil {
-		t.Errorf("%s: Commits(): %s", label, err)
-		return
-	}
-
-	if len(commits) != len(wantCommits) {
-		t.Errorf("%s: got %d commits, want %d", label, len(commits), len(wantCommits))
-	}
-	checkCommits(t, commits, wantCommits)
+type fakeCommitsStream struct {
+	grpc.ClientStream
+	commits []*proto.GitCommit
 }
 
-func checkCommits(t *testing.T, commits, wantCommits []*gitdomain.Commit) {
-	t.Helper()
-	for i := 0; i < len(commits) || i < len(wantCommits); i++ {
-		var gotC, wantC *gitdomain.Commit
-		if i < len(commits) {
-			gotC = commits[i]
-		}
-		if i < len(wantCommits) {
-			wantC = wantCommits[i]
-		}
-		if diff := cmp.Diff(gotC, wantC); diff != "" {
-			t.Fatal(diff)
-		}
+func (s *fakeCommitsStream) Recv() (*proto.CommitsResponse, error) {
+	if len(s.commits) == 0 {
+		return nil, io.EOF
 	}
+	resp := &proto.CommitsResponse{Commits: s.commits}
+	s.commits = nil
+	return resp, nil
 }
 
 // get a test sub-repo permissions checker which allows access to all files (so should be a no-op)
@@ -2281,10 +1061,6 @@ func getGitCommandsWithFileLists(filenamesPerCommit ...[]string) []string {
 	return cmds
 }
 
-func makeGitCommit(commitMessage string, seconds int) string {
-	return fmt.Sprintf("GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05=%dZ git commit -m %s --author='a <a@a.com>' --date 2006-01-02T15:04:0%dZ", seconds, commitMessage, seconds)
-}
-
 func getGitCommandsWithFiles(fileName1, fileName2 string) []string {
 	return []string{
 		fmt.Sprintf("touch %s", fileName1),
@@ -2305,25 +1081,6 @@ func mustParseDate(s string, t *testing.T) *time.Time {
 	return &date
 }
 
-func CommitsEqual(a, b *gitdomain.Commit) bool {
-	if (a == nil) != (b == nil) {
-		return false
-	}
-	if a.Author.Date != b.Author.Date {
-		return false
-	}
-	a.Author.Date = b.Author.Date
-	if ac, bc := a.Committer, b.Committer; ac != nil && bc != nil {
-		if ac.Date != bc.Date {
-			return false
-		}
-		ac.Date = bc.Date
-	} else if !(ac == nil && bc == nil) {
-		return false
-	}
-	return reflect.DeepEqual(a, b)
-}
-
 func TestArchiveReaderForRepoWithSubRepoPermissions(t *testing.T) {
 	repoName := MakeGitRepository(t,
 		"echo abcd > file1",
@@ -2700,43 +1457,6 @@ func TestRepository_Branches_BehindAheadCounts(t *testing.T) {
 	testBranches(t, gitCommands, wantBranches, BranchesOptions{BehindAheadBranch: "master"})
 }
 
-func TestRepository_Branches_IncludeCommit(t *testing.T) {
-	ClientMocks.LocalGitserver = true
-	t.Cleanup(func() {
-		ResetClientMocks()
-	})
-
-	gitCommands := []string{
-		"git commit --allow-empty -m foo0",
-		"git checkout -b b0",
-		"GIT_COMMITTER_NAME=b GIT_COMMITTER_EMAIL=b@b.com GIT_COMMITTER_DATE=2006-01-02T15:04:06Z git commit --allow-empty -m foo1 --author='b <b@b.com>' --date 2006-01-02T15:04:06Z",
-	}
-	wantBranches := []*gitdomain.Branch{
-		{
-			Name: "b0", Head: "c4a53701494d1d788b1ceeb8bf32e90224962473",
-			Commit: &gitdomain.Commit{
-				ID:        "c4a53701494d1d788b1ceeb8bf32e90224962473",
-				Author:    gitdomain.Signature{Name: "b", Email: "b@b.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
-				Committer: &gitdomain.Signature{Name: "b", Email: "b@b.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:06Z")},
-				Message:   "foo1",
-				Parents:   []api.CommitID{"a3c1537db9797215208eec56f8e7c9c37f8358ca"},
-			},
-		},
-		{
-			Name: "master", Head: "a3c1537db9797215208eec56f8e7c9c37f8358ca",
-			Commit: &gitdomain.Commit{
-				ID:        "a3c1537db9797215208eec56f8e7c9c37f8358ca",
-				Author:    gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-				Committer: &gitdomain.Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
-				Message:   "foo0",
-				Parents:   nil,
-			},
-		},
-	}
-
-	testBranches(t, gitCommands, wantBranches, BranchesOptions{IncludeCommit: true})
-}
-
 func testBranches(t *testing.T, gitCommands []string, wantBranches []*gitdomain.Branch, options BranchesOptions) {
 	t.Helper()
 
@@ -2760,199 +1480,6 @@ func usePermissionsForFilePermissionsFunc(m *authz.MockSubRepoPermissionChecker)
 	})
 }
 
-// testGitBlameOutputIncremental is produced by running
-//
-//	git blame -w --porcelain release.sh
-//
-// `sourcegraph/src-cli`
-var testGitBlameOutputIncremental = `8a75c6f8b4cbe2a2f3c8be0f2c50bc766499f498 15 15 1
-author Adam Harvey
-author-mail <adam@adamharvey.name>
-author-time 1660860583
-author-tz -0700
-committer GitHub
-committer-mail <noreply@github.com>
-committer-time 1660860583
-committer-tz +0000
-summary release.sh: allow -rc.X suffixes (#829)
-previous e6e03e850770dd0ba745f0fa4b23127e9d72ad30 release.sh
-filename release.sh
-fbb98e0b7ff0752798463d9f49d922858a4188f6 5 5 10
-author Adam Harvey
-author-mail <aharvey@sourcegraph.com>
-author-time 1602630694
-author-tz -0700
-committer GitHub
-committer-mail <noreply@github.com>
-committer-time 1602630694
-committer-tz -0700
-summary release: add a prompt about DEVELOPMENT.md (#349)
-previous 18f59760f4260518c29f0f07056245ed5d1d0f08 release.sh
-filename release.sh
-67b7b725a7ff913da520b997d71c840230351e30 10 20 1
-author Thorsten Ball
-author-mail <mrnugget@gmail.com>
-author-time 1600334460
-author-tz +0200
-committer Thorsten Ball
-committer-mail <mrnugget@gmail.com>
-committer-time 1600334460
-committer-tz +0200
-summary Fix goreleaser GitHub action setup and release script
-previous 6e931cc9745502184ce32d48b01f9a8706a4dfe8 release.sh
-filename release.sh
-67b7b725a7ff913da520b997d71c840230351e30 12 22 2
-previous 6e931cc9745502184ce32d48b01f9a8706a4dfe8 release.sh
-filename release.sh
-3f61310114082d6179c23f75950b88d1842fe2de 1 1 4
-author Thorsten Ball
-author-mail <mrnugget@gmail.com>
-author-time 1592827635
-author-tz +0200
-committer GitHub
-committer-mail <noreply@github.com>
-committer-time 1592827635
-committer-tz +0200
-summary Check that $VERSION is in MAJOR.MINOR.PATCH format in release.sh (#227)
-previous ec809e79094cbcd05825446ee14c6d072466a0b7 release.sh
-filename release.sh
-3f61310114082d6179c23f75950b88d1842fe2de 6 16 4
-previous ec809e79094cbcd05825446ee14c6d072466a0b7 release.sh
-filename release.sh
-3f61310114082d6179c23f75950b88d1842fe2de 10 21 1
-previous ec809e79094cbcd05825446ee14c6d072466a0b7 release.sh
-filename release.sh
-`
-
-// This test-data includes the boundary keyword, which is not present in the previous one.
-var testGitBlameOutputIncremental2 = `bbca6551549492486ca1b0f8dee45553dd6aa6d7 16 16 1
-author French Ben
-author-mail <frenchben@docker.com>
-author-time 1517407262
-author-tz +0100
-committer French Ben
-committer-mail <frenchben@docker.com>
-committer-time 1517407262
-committer-tz +0100
-summary Update error output to be clean
-previous b7773ae218740a7be65057fc60b366a49b538a44 format.go
-filename format.go
-bbca6551549492486ca1b0f8dee45553dd6aa6d7 25 25 2
-previous b7773ae218740a7be65057fc60b366a49b538a44 format.go
-filename format.go
-2c87fda17de1def6ea288141b8e7600b888e535b 15 15 1
-author David Tolnay
-author-mail <dtolnay@gmail.com>
-author-time 1478451741
-author-tz -0800
-committer David Tolnay
-committer-mail <dtolnay@gmail.com>
-committer-time 1478451741
-committer-tz -0800
-summary Singular message for a single error
-previous 8c5f0ad9360406a3807ce7de6bc73269a91a6e51 format.go
-filename format.go
-2c87fda17de1def6ea288141b8e7600b888e535b 17 17 2
-previous 8c5f0ad9360406a3807ce7de6bc73269a91a6e51 format.go
-filename format.go
-31fee45604949934710ada68f0b307c4726fb4e8 1 1 14
-author Mitchell Hashimoto
-author-mail <mitchell.hashimoto@gmail.com>
-author-time 1418673320
-author-tz -0800
-committer Mitchell Hashimoto
-committer-mail <mitchell.hashimoto@gmail.com>
-committer-time 1418673320
-committer-tz -0800
-summary Initial commit
-boundary
-filename format.go
-31fee45604949934710ada68f0b307c4726fb4e8 15 19 6
-filename format.go
-31fee45604949934710ada68f0b307c4726fb4e8 23 27 1
-filename format.go
-`
-
-var testGitBlameOutputHunks = []*Hunk{
-	{
-		StartLine: 1, EndLine: 5, StartByte: 0, EndByte: 41,
-		CommitID: "3f61310114082d6179c23f75950b88d1842fe2de",
-		Author: gitdomain.Signature{
-			Name:  "Thorsten Ball",
-			Email: "mrnugget@gmail.com",
-			Date:  MustParseTime(time.RFC3339, "2020-06-22T12:07:15Z"),
-		},
-		Message:  "Check that $VERSION is in MAJOR.MINOR.PATCH format in release.sh (#227)",
-		Filename: "release.sh",
-	},
-	{
-		StartLine: 5, EndLine: 15, StartByte: 41, EndByte: 249,
-		CommitID: "fbb98e0b7ff0752798463d9f49d922858a4188f6",
-		Author: gitdomain.Signature{
-			Name:  "Adam Harvey",
-			Email: "aharvey@sourcegraph.com",
-			Date:  MustParseTime(time.RFC3339, "2020-10-13T23:11:34Z"),
-		},
-		Message:  "release: add a prompt about DEVELOPMENT.md (#349)",
-		Filename: "release.sh",
-	},
-	{
-		StartLine: 15, EndLine: 16, StartByte: 249, EndByte: 328,
-		CommitID: "8a75c6f8b4cbe2a2f3c8be0f2c50bc766499f498",
-		Author: gitdomain.Signature{
-			Name:  "Adam Harvey",
-			Email: "adam@adamharvey.name",
-			Date:  MustParseTime(time.RFC3339, "2022-08-18T22:09:43Z"),
-		},
-		Message:  "release.sh: allow -rc.X suffixes (#829)",
-		Filename: "release.sh",
-	},
-	{
-		StartLine: 16, EndLine: 20, StartByte: 328, EndByte: 394,
-		CommitID: "3f61310114082d6179c23f75950b88d1842fe2de",
-		Author: gitdomain.Signature{
-			Name:  "Thorsten Ball",
-			Email: "mrnugget@gmail.com",
-			Date:  MustParseTime(time.RFC3339, "2020-06-22T12:07:15Z"),
-		},
-		Message:  "Check that $VERSION is in MAJOR.MINOR.PATCH format in release.sh (#227)",
-		Filename: "release.sh",
-	},
-	{
-		StartLine: 20, EndLine: 21, StartByte: 394, EndByte: 504,
-		CommitID: "67b7b725a7ff913da520b997d71c840230351e30",
-		Author: gitdomain.Signature{
-			Name:  "Thorsten Ball",
-			Email: "mrnugget@gmail.com",
-			Date:  MustParseTime(time.RFC3339, "2020-09-17T09:21:00Z"),
-		},
-		Message:  "Fix goreleaser GitHub action setup and release script",
-		Filename: "release.sh",
-	},
-	{
-		StartLine: 21, EndLine: 22, StartByte: 504, EndByte: 553,
-		CommitID: "3f61310114082d6179c23f75950b88d1842fe2de",
-		Author: gitdomain.Signature{
-			Name:  "Thorsten Ball",
-			Email: "mrnugget@gmail.com",
-			Date:  MustParseTime(time.RFC3339, "2020-06-22T12:07:15Z"),
-		},
-		Message:  "Check that $VERSION is in MAJOR.MINOR.PATCH format in release.sh (#227)",
-		Filename: "release.sh",
-	},
-	{
-		StartLine: 22, EndLine: 24, StartByte: 553, EndByte: 695,
-		CommitID: "67b7b725a7ff913da520b997d71c840230351e30",
-		Author: gitdomain.Signature{
-			Name:  "Thorsten Ball",
-			Email: "mrnugget@gmail.com",
-			Date:  MustParseTime(time.RFC3339, "2020-09-17T09:21:00Z"),
-		},
-		Message:  "Fix goreleaser GitHub action setup and release script",
-		Filename: "release.sh",
-	},
-}
-
 func TestStreamBlameFile(t *testing.T) {
 	t.Run("NOK unauthorized", func(t *testing.T) {
 		ctx := actor.WithActor(context.Background(), &actor.Actor{
@@ -2966,7 +1493,7 @@ func TestStreamBlameFile(t *testing.T) {
 		checker.PermissionsFunc.SetDefaultHook(func(ctx context.Context, i int32, content authz.RepoContent) (authz.Perms, error) {
 			return authz.None, nil
 		})
-		hr, err := streamBlameFileCmd(ctx, checker, "foobar", "README.md", nil, func(_ []string) GitCommand { return nil })
+		hr, err := NewTestClient(t).WithChecker(checker).StreamBlameFile(ctx, "foobar", "README.md", nil)
 		if hr != nil {
 			t.Fatalf("expected nil HunkReader")
 		}
@@ -2979,62 +1506,6 @@ func TestStreamBlameFile(t *testing.T) {
 	})
 }
 
-func TestBlameHunkReader(t *testing.T) {
-	t.Run("OK matching hunks", func(t *testing.T) {
-		rc := io.NopCloser(strings.NewReader(testGitBlameOutputIncremental))
-		reader := newBlameHunkReader(rc)
-		defer reader.Close()
-
-		hunks := []*Hunk{}
-		for {
-			hunk, err := reader.Read()
-			if errors.Is(err, io.EOF) {
-				break
-			} else if err != nil {
-				t.Fatalf("blameHunkReader.Read failed: %s", err)
-			}
-			hunks = append(hunks, hunk)
-		}
-
-		sortFn := func(x []*Hunk) func(i, j int) bool {
-			return func(i, j int) bool {
-				return x[i].Author.Date.After(x[j].Author.Date)
-			}
-		}
-
-		// We're not giving back bytes, as the output of --incremental only gives back annotations.
-		expectedHunks := make([]*Hunk, 0, len(testGitBlameOutputHunks))
-		for _, h := range testGitBlameOutputHunks {
-			dup := *h
-			dup.EndByte = 0
-			dup.StartByte = 0
-			expectedHunks = append(expectedHunks, &dup)
-		}
-
-		// Sort expected hunks by the most recent first, as --incremental does.
-		sort.SliceStable(expectedHunks, sortFn(expectedHunks))
-
-		if d := cmp.Diff(expectedHunks, hunks); d != "" {
-			t.Fatalf("unexpected hunks (-want, +got):\n%s", d)
-		}
-	})
-
-	t.Run("OK parsing hunks", func(t *testing.T) {
-		rc := io.NopCloser(strings.NewReader(testGitBlameOutputIncremental2))
-		reader := newBlameHunkReader(rc)
-		defer reader.Close()
-
-		for {
-			_, err := reader.Read()
-			if errors.Is(err, io.EOF) {
-				break
-			} else if err != nil {
-				t.Fatalf("blameHunkReader.Read failed: %s", err)
-			}
-		}
-	})
-}
-
 func Test_CommitLog(t *testing.T) {
 	ClientMocks.LocalGitserver = true
 	defer ResetClientMocks()
@@ -0,0 +1,111 @@
+package gitdomain
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// CatFileBatchHeader is the header that `git cat-file --batch` prints before
+// the contents of each object.
+type CatFileBatchHeader struct {
+	// OID is the hex-encoded object ID, or the object name given on stdin if
+	// the object is missing.
+	OID  string
+	Type ObjectType
+	Size int64
+	// Missing is true if the object doesn't exist. Missing objects have no
+	// contents.
+	Missing bool
+}
+
+// CatFileBatchReader reads the output of `git cat-file --batch`. Call Next to
+// advance to the next object, then read its contents from the reader.
+type CatFileBatchReader struct {
+	r *bufio.Reader
+	// remaining is the number of bytes of the contents of the current object
+	// that haven't been read yet.
+	remaining int64
+	// trailer is true if the newline following the contents of the current
+	// object hasn't been consumed yet.
+	trailer bool
+}
+
+// NewCatFileBatchReader returns a reader for the output of
+// `git cat-file --batch`.
+func NewCatFileBatchReader(r io.Reader) *CatFileBatchReader {
+	return &CatFileBatchReader{r: bufio.NewReader(r)}
+}
+
+// Next skips the unread contents of the current object and returns the header
+// of the next one. It returns io.EOF if there are no more objects.
+func (r *CatFileBatchReader) Next() (CatFileBatchHeader, error) {
+	if r.remaining > 0 {
+		if _, err := io.CopyN(io.Discard, r.r, r.remaining); err != nil {
+			return CatFileBatchHeader{}, errors.Wrap(err, "skipping object contents")
+		}
+		r.remaining = 0
+	}
+	if r.trailer {
+		if b, err := r.r.ReadByte(); err != nil {
+			return CatFileBatchHeader{}, errors.Wrap(err, "reading object trailer")
+		} else if b != '\n' {
+			return CatFileBatchHeader{}, errors.Errorf("unexpected object trailer %q", b)
+		}
+		r.trailer = false
+	}
+
+	line, err := r.r.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line == "" {
+			return CatFileBatchHeader{}, io.EOF
+		}
+		return CatFileBatchHeader{}, errors.Wrap(err, "reading object header")
+	}
+	h, err := parseCatFileBatchHeader(strings.TrimSuffix(line, "\n"))
+	if err != nil {
+		return CatFileBatchHeader{}, err
+	}
+	if !h.Missing {
+		r.remaining = h.Size
+		r.trailer = true
+	}
+	return h, nil
+}
+
+// Read reads from the contents of the current object. It returns io.EOF at
+// the end of the contents.
+func (r *CatFileBatchReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func parseCatFileBatchHeader(line string) (CatFileBatchHeader, error) {
+	// Missing objects are reported as "<object> missing", all others as
+	// "<oid> SP <type> SP <size>".
+	fields := strings.Split(line, " ")
+	if len(fields) == 2 && fields[1] == "missing" {
+		return CatFileBatchHeader{OID: fields[0], Missing: true}, nil
+	}
+	if len(fields) != 3 {
+		return CatFileBatchHeader{}, errors.Errorf("unexpected object header %q", line)
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return CatFileBatchHeader{}, errors.Errorf("unexpected object size in header %q", line)
+	}
+	return CatFileBatchHeader{OID: fields[0], Type: ObjectType(fields[1]), Size: size}, nil
+}
@@ -0,0 +1,56 @@
+package gitdomain
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatFileBatchReader(t *testing.T) {
+	output := "aaaa blob 5\nhello\n" +
+		"bbbb missing\n" +
+		"cccc blob 0\n\n" +
+		"dddd tree 6\nskipme\n" +
+		"eeee blob 3\nbye\n"
+	r := NewCatFileBatchReader(strings.NewReader(output))
+
+	next := func(want CatFileBatchHeader) {
+		t.Helper()
+		h, err := r.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, want, h)
+	}
+	readAll := func(want string) {
+		t.Helper()
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+
+	next(CatFileBatchHeader{OID: "aaaa", Type: ObjectTypeBlob, Size: 5})
+	readAll("hello")
+	next(CatFileBatchHeader{OID: "bbbb", Missing: true})
+	readAll("")
+	next(CatFileBatchHeader{OID: "cccc", Type: ObjectTypeBlob, Size: 0})
+	readAll("")
+	// The contents of objects that aren't read are skipped.
+	next(CatFileBatchHeader{OID: "dddd", Type: ObjectTypeTree, Size: 6})
+	next(CatFileBatchHeader{OID: "eeee", Type: ObjectTypeBlob, Size: 3})
+	readAll("bye")
+
+	_, err := r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestCatFileBatchReader_Truncated(t *testing.T) {
+	r := NewCatFileBatchReader(strings.NewReader("aaaa blob 5\nhel"))
+	_, err := r.Next()
+	assert.NoError(t, err)
+	_, err = io.ReadAll(r)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = NewCatFileBatchReader(strings.NewReader("garbage\n")).Next()
+	assert.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/gobwas/glob"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
 
@@ -51,6 +52,11 @@ type Submodule struct {
 	// CommitID is the pinned commit ID of the submodule (in the
 	// submodule repository's commit ID space).
 	CommitID api.CommitID
+
+	// Repo is the name of the submodule repository if it is known to this
+	// instance, and empty otherwise. It doesn't mean that the current user
+	// has access to the repository.
+	Repo api.RepoName
 }
 
 // ObjectInfo holds information about a Git object and is returned in (fs.FileInfo).Sys for blobs
@@ -158,6 +164,40 @@ type Commit struct {
 	Parents []api.CommitID `json:"Parents,omitempty"`
 }
 
+func (c *Commit) ToProto() *proto.GitCommit {
+	p := &proto.GitCommit{
+		Oid:     string(c.ID),
+		Author:  c.Author.ToProto(),
+		Message: []byte(c.Message),
+		Parents: make([]string, 0, len(c.Parents)),
+	}
+	if c.Committer != nil {
+		p.Committer = c.Committer.ToProto()
+	}
+	for _, parent := range c.Parents {
+		p.Parents = append(p.Parents, string(parent))
+	}
+	return p
+}
+
+func (c *Commit) FromProto(p *proto.GitCommit) {
+	var author Signature
+	author.FromProto(p.GetAuthor())
+	*c = Commit{
+		ID:      api.CommitID(p.GetOid()),
+		Author:  author,
+		Message: Message(p.GetMessage()),
+	}
+	if p.GetCommitter() != nil {
+		var committer Signature
+		committer.FromProto(p.GetCommitter())
+		c.Committer = &committer
+	}
+	for _, parent := range p.GetParents() {
+		c.Parents = append(c.Parents, api.CommitID(parent))
+	}
+}
+
 // Message represents a git commit message
 type Message string
 
@@ -188,6 +228,22 @@ type Signature struct {
 	Date  time.Time `json:"Date"`
 }
 
+func (s *Signature) ToProto() *proto.GitSignature {
+	return &proto.GitSignature{
+		Name:  []byte(s.Name),
+		Email: []byte(s.Email),
+		Date:  timestamppb.New(s.Date),
+	}
+}
+
+func (s *Signature) FromProto(p *proto.GitSignature) {
+	*s = Signature{
+		Name:  string(p.GetName()),
+		Email: string(p.GetEmail()),
+		Date:  p.GetDate().AsTime(),
+	}
+}
+
 type RefType int
 
 const (
@@ -215,6 +271,22 @@ func (p *ContributorCount) String() string {
 	return fmt.Sprintf("%d %s <%s>", p.Count, p.Name, p.Email)
 }
 
+func (p *ContributorCount) ToProto() *proto.ContributorCount {
+	return &proto.ContributorCount{
+		Name:  p.Name,
+		Email: p.Email,
+		Count: p.Count,
+	}
+}
+
+func (p *ContributorCount) FromProto(c *proto.ContributorCount) {
+	*p = ContributorCount{
+		Name:  c.GetName(),
+		Email: c.GetEmail(),
+		Count: c.GetCount(),
+	}
+}
+
 // A Tag is a VCS tag.
 type Tag struct {
 	Name         string `json:"Name,omitempty"`
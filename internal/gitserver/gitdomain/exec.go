@@ -35,7 +35,7 @@ var (
 		"merge-base":   {"--"},
 		"show-ref":     {"--heads"},
 		"shortlog":     {"-s", "-n", "-e", "--no-merges", "--after", "--before"},
-		"cat-file":     {"-p"},
+		"cat-file":     {"-p", "--batch"},
 		"lfs":          {},
 
 		// Commands used by Batch Changes when publishing changesets.
@@ -53,6 +53,41 @@ var (
 		"testcat":     {},
 	}
 
+	// gitCmdsWithDedicatedRPC are commands that gitserver exposes through a typed
+	// RPC. They remain in gitCmdAllowlist so that gitserver can run them on behalf
+	// of those RPCs, but clients may no longer send them over the generic gRPC
+	// Exec endpoint. If arguments are listed for a command, only invocations
+	// with one of them are served by the typed RPC.
+	gitCmdsWithDedicatedRPC = map[string][]string{
+		"blame":      nil,
+		"cat-file":   {"--batch"},
+		"ls-files":   nil,
+		"merge-base": nil,
+		"shortlog":   nil,
+	}
+
+	// gitCmdsWithNewDedicatedRPC are like gitCmdsWithDedicatedRPC, but their
+	// typed RPCs are recent enough that clients which still send them over
+	// Exec may be running during a rolling upgrade. Only the invocations that
+	// the typed RPCs construct are listed, since other forms of these commands
+	// are still sent over Exec.
+	gitCmdsWithNewDedicatedRPC = map[string][]string{
+		"diff":    {"--name-status"},
+		"log":     {CommitLogFormat},
+		"ls-tree": {"--long"},
+	}
+
+	// gitCmdsModifyingRepo are the allowed commands that write to the
+	// repository. They must run on the primary copy of a repository.
+	gitCmdsModifyingRepo = map[string]struct{}{
+		"apply":      {},
+		"commit":     {},
+		"init":       {},
+		"push":       {},
+		"reset":      {},
+		"update-ref": {},
+	}
+
 	// `git log`, `git show`, `git diff`, etc., share a large common set of allowed args.
 	gitCommonAllowlist = []string{
 		"--name-only", "--name-status", "--full-history", "-M", "--date", "--format", "-i", "-n", "-n1", "-m", "--", "-n200", "-n2", "--follow", "--author", "--grep", "--date-order", "--decorate", "--skip", "--max-count", "--numstat", "--pretty", "--parents", "--topo-order", "--raw", "--follow", "--all", "--before", "--no-merges", "--fixed-strings",
@@ -60,7 +95,7 @@ var (
 		"--regexp-ignore-case", "--glob", "--cherry", "-z", "--reverse", "--ignore-submodules",
 		"--until", "--since", "--author", "--committer",
 		"--all-match", "--invert-grep", "--extended-regexp",
-		"--no-color", "--decorate", "--no-patch", "--exclude",
+		"--no-color", "--decorate", "--no-patch", "--exclude", "--notes",
 		"--no-merges",
 		"--no-renames",
 		"--full-index",
@@ -240,3 +275,47 @@ func IsAllowedGitCmd(logger log.Logger, args []string, dir string) bool {
 	}
 	return true
 }
+
+// HasDedicatedRPC returns true if the git command in args is served by a typed
+// gitserver RPC and must not be sent through the generic gRPC Exec endpoint.
+// Commands whose typed RPC was added recently are only included if
+// includeNew is true, see gitCmdsWithNewDedicatedRPC.
+func HasDedicatedRPC(args []string, includeNew bool) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if rpcArgs, ok := gitCmdsWithDedicatedRPC[args[0]]; ok && hasAnyRPCArg(rpcArgs, args[1:]) {
+		return true
+	}
+	if rpcArgs, ok := gitCmdsWithNewDedicatedRPC[args[0]]; ok && includeNew && hasAnyRPCArg(rpcArgs, args[1:]) {
+		return true
+	}
+	return false
+}
+
+// hasAnyRPCArg returns true if rpcArgs is empty or if one of args is in
+// rpcArgs, either as a whole or by its flag name.
+func hasAnyRPCArg(rpcArgs, args []string) bool {
+	if len(rpcArgs) == 0 {
+		return true
+	}
+	for _, arg := range args {
+		if slices.Contains(rpcArgs, arg) || slices.Contains(rpcArgs, strings.Split(arg, "=")[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReadOnlyGitCmd returns true if the git command in args only reads from the
+// repository, so that it can be served by any copy of it.
+func IsReadOnlyGitCmd(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	if _, ok := gitCmdAllowlist[args[0]]; !ok {
+		return false
+	}
+	_, ok := gitCmdsModifyingRepo[args[0]]
+	return !ok
+}
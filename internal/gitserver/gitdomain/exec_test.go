@@ -83,3 +83,57 @@ func TestIsAllowedDiffGitCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestHasDedicatedRPC(t *testing.T) {
+	for _, args := range [][]string{
+		{"blame", "--porcelain", "HEAD", "--", "README.md"},
+		{"ls-files", "-z", "--with-tree", "HEAD"},
+		{"merge-base", "--", "a", "b"},
+		{"shortlog", "-s", "-n", "-e", "HEAD"},
+		{"cat-file", "--batch"},
+		{"cat-file", "--batch=%(objectname)"},
+	} {
+		assert.True(t, HasDedicatedRPC(args, false), "expected %q to require a dedicated RPC", args)
+	}
+	newRPCArgs := [][]string{
+		{"log", CommitLogFormat, "-n", "1", "HEAD"},
+		{"ls-tree", "--long", "--full-name", "-z", "HEAD"},
+		{"diff", "-z", "--name-status", "--no-renames", "a", "b"},
+	}
+	for _, args := range newRPCArgs {
+		assert.False(t, HasDedicatedRPC(args, false), "expected %q to be allowed over Exec", args)
+		assert.True(t, HasDedicatedRPC(args, true), "expected %q to require a dedicated RPC", args)
+	}
+	for _, args := range [][]string{
+		nil,
+		{"log", "-n", "1"},
+		{"log", "--format=%x00%H%x00%N", "HEAD"},
+		{"ls-tree", "HEAD"},
+		{"ls-tree", "--name-only", "HEAD", "--"},
+		{"diff", "a", "b", "--", "README.md"},
+		{"rev-parse", "HEAD"},
+		{"cat-file", "-p", "HEAD:README.md"},
+	} {
+		assert.False(t, HasDedicatedRPC(args, true), "expected %q to be allowed over Exec", args)
+	}
+}
+
+func TestIsReadOnlyGitCmd(t *testing.T) {
+	for _, args := range [][]string{
+		{"show", "HEAD:README.md"},
+		{"cat-file", "-p", "HEAD:README.md"},
+		{"log", "-n", "1"},
+		{"archive", "--format=zip", "HEAD"},
+	} {
+		assert.True(t, IsReadOnlyGitCmd(args), "expected %q to be read-only", args)
+	}
+	for _, args := range [][]string{
+		nil,
+		{"push", "--force", "origin", "HEAD"},
+		{"update-ref", "refs/heads/main", "HEAD"},
+		{"apply", "--cached", "-p0"},
+		{"gc"},
+	} {
+		assert.False(t, IsReadOnlyGitCmd(args), "expected %q not to be read-only", args)
+	}
+}
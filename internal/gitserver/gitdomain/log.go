@@ -2,9 +2,17 @@ package gitdomain
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -26,6 +34,22 @@ const (
 	DeletedAMD  StatusAMD = 2
 )
 
+func (p *PathStatus) FromProto(f *proto.ChangedFile) {
+	var status StatusAMD
+	switch f.GetStatus() {
+	case proto.ChangedFile_STATUS_ADDED:
+		status = AddedAMD
+	case proto.ChangedFile_STATUS_MODIFIED:
+		status = ModifiedAMD
+	case proto.ChangedFile_STATUS_DELETED:
+		status = DeletedAMD
+	}
+	*p = PathStatus{
+		Path:   string(f.GetPath()),
+		Status: status,
+	}
+}
+
 func LogReverseArgs(n int, givenCommit string) []string {
 	return []string{
 		"log",
@@ -202,3 +226,176 @@ func ParseLogReverseEach(stdout io.Reader, onLogEntry func(entry LogEntry) error
 
 	return nil
 }
+
+// CommitLogFormat is the --format argument for `git log` whose output is
+// parsed by ParseCommitLog. Each commit starts with an ASCII record separator
+// byte (0x1E), and each of its fields is terminated by a null byte (0x00).
+//
+// The format has 10 parts:
+//  1. oid
+//  2. author name
+//  3. author email
+//  4. author time
+//  5. committer name
+//  6. committer email
+//  7. committer time
+//  8. message body
+//  9. parent hashes
+//  10. modified files (optional)
+//
+// Refs are slow, and are intentionally not included because they are usually not needed.
+const CommitLogFormat = "--format=format:%x1e%H%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct%x00%B%x00%P%x00"
+
+// commitLogPartsPerCommit is the number of \x00-separated fields per commit in
+// the output of `git log` with CommitLogFormat.
+const commitLogPartsPerCommit = 10
+
+// ParseCommitLog parses the output of `git log` with CommitLogFormat and calls
+// onCommit for each commit, along with the files it modified if --name-only
+// was passed to git log.
+func ParseCommitLog(r io.Reader, onCommit func(commit *Commit, modifiedFiles []string) error) error {
+	commitScanner := bufio.NewScanner(r)
+	commitScanner.Split(commitSplitFunc)
+
+	for commitScanner.Scan() {
+		rawCommit := commitScanner.Bytes()
+		parts := bytes.Split(rawCommit, []byte{'\x00'})
+		if len(parts) != commitLogPartsPerCommit {
+			return errors.Newf("internal error: expected %d parts, got %d", commitLogPartsPerCommit, len(parts))
+		}
+
+		commit, modifiedFiles, err := parseCommitFromLog(parts)
+		if err != nil {
+			return err
+		}
+		if err := onCommit(commit, modifiedFiles); err != nil {
+			return err
+		}
+	}
+	return commitScanner.Err()
+}
+
+func commitSplitFunc(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if len(data) == 0 {
+		// Request more data
+		return 0, nil, nil
+	}
+
+	// Safety check: ensure we are always starting with a record separator
+	if data[0] != '\x1e' {
+		return 0, nil, errors.New("internal error: data should always start with an ASCII record separator")
+	}
+
+	loc := bytes.IndexByte(data[1:], '\x1e')
+	if loc < 0 {
+		// We can't find the start of the next record
+		if atEOF {
+			// If we're at the end of the stream, just return the rest as the last record
+			return len(data), data[1:], bufio.ErrFinalToken
+		} else {
+			// If we're not at the end of the stream, request more data
+			return 0, nil, nil
+		}
+	}
+	nextStart := loc + 1 // correct for searching at an offset
+
+	return nextStart, data[1:nextStart], nil
+}
+
+// parseCommitFromLog parses a commit from the NUL-separated log fields as
+// formatted by CommitLogFormat.
+func parseCommitFromLog(parts [][]byte) (*Commit, []string, error) {
+	// log outputs are newline separated, so all but the 1st commit ID part
+	// has an erroneous leading newline.
+	parts[0] = bytes.TrimPrefix(parts[0], []byte{'\n'})
+	commitID := api.CommitID(parts[0])
+
+	authorTime, err := strconv.ParseInt(string(parts[3]), 10, 64)
+	if err != nil {
+		return nil, nil, errors.Errorf("parsing git commit author time: %s", err)
+	}
+	committerTime, err := strconv.ParseInt(string(parts[6]), 10, 64)
+	if err != nil {
+		return nil, nil, errors.Errorf("parsing git commit committer time: %s", err)
+	}
+
+	var parents []api.CommitID
+	if parentPart := parts[8]; len(parentPart) > 0 {
+		parentIDs := bytes.Split(parentPart, []byte{' '})
+		parents = make([]api.CommitID, len(parentIDs))
+		for i, id := range parentIDs {
+			parents[i] = api.CommitID(id)
+		}
+	}
+
+	fileNames := strings.Split(string(bytes.TrimSpace(parts[9])), "\n")
+
+	return &Commit{
+		ID:        commitID,
+		Author:    Signature{Name: string(parts[1]), Email: string(parts[2]), Date: time.Unix(authorTime, 0).UTC()},
+		Committer: &Signature{Name: string(parts[4]), Email: string(parts[5]), Date: time.Unix(committerTime, 0).UTC()},
+		Message:   Message(strings.TrimSuffix(string(parts[7]), "\n")),
+		Parents:   parents,
+	}, fileNames, nil
+}
+
+// shortLogEntryPattern is the regexp pattern that matches entries in the output
+// of the `git shortlog -sne` command.
+var shortLogEntryPattern = lazyregexp.New(`^\s*([0-9]+)\s+(.*)$`)
+
+// ParseShortLog parses the output of `git shortlog -sne` into contributor
+// counts.
+func ParseShortLog(out []byte) ([]*ContributorCount, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, nil
+	}
+	lines := bytes.Split(out, []byte{'\n'})
+	results := make([]*ContributorCount, len(lines))
+	for i, line := range lines {
+		// example line: "1125\tJane Doe <jane@sourcegraph.com>"
+		match := shortLogEntryPattern.FindSubmatch(line)
+		if match == nil {
+			return nil, errors.Errorf("invalid git shortlog line: %q", line)
+		}
+		// example match: ["1125\tJane Doe <jane@sourcegraph.com>" "1125" "Jane Doe <jane@sourcegraph.com>"]
+		count, err := strconv.Atoi(string(match[1]))
+		if err != nil {
+			return nil, err
+		}
+		addr, err := lenientParseAddress(string(match[2]))
+		if err != nil || addr == nil {
+			addr = &mail.Address{Name: string(match[2])}
+		}
+		results[i] = &ContributorCount{
+			Count: int32(count),
+			Name:  addr.Name,
+			Email: addr.Address,
+		}
+	}
+	return results, nil
+}
+
+// lenientParseAddress is just like mail.ParseAddress, except that it treats
+// the following somewhat-common malformed syntax where a user has misconfigured
+// their email address as their name:
+//
+//	foo@gmail.com <foo@gmail.com>
+//
+// As a valid name, whereas mail.ParseAddress would return an error:
+//
+//	mail: expected single address, got "<foo@gmail.com>"
+func lenientParseAddress(address string) (*mail.Address, error) {
+	addr, err := mail.ParseAddress(address)
+	if err != nil && strings.Contains(err.Error(), "expected single address") {
+		p := strings.LastIndex(address, "<")
+		if p == -1 {
+			return addr, err
+		}
+		return &mail.Address{
+			Name:    strings.TrimSpace(address[:p]),
+			Address: strings.Trim(address[p:], " <>"),
+		}, nil
+	}
+	return addr, err
+}
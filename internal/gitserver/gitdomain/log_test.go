@@ -0,0 +1,72 @@
+package gitdomain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseShortLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string // in the format of `git shortlog -sne`
+		want    []*ContributorCount
+		wantErr error
+	}{
+		{
+			name: "basic",
+			input: `
+  1125	Jane Doe <jane@sourcegraph.com>
+   390	Bot Of Doom <bot@doombot.com>
+`,
+			want: []*ContributorCount{
+				{
+					Name:  "Jane Doe",
+					Email: "jane@sourcegraph.com",
+					Count: 1125,
+				},
+				{
+					Name:  "Bot Of Doom",
+					Email: "bot@doombot.com",
+					Count: 390,
+				},
+			},
+		},
+		{
+			name: "commonly malformed (email address as name)",
+			input: `  1125	jane@sourcegraph.com <jane@sourcegraph.com>
+   390	Bot Of Doom <bot@doombot.com>
+`,
+			want: []*ContributorCount{
+				{
+					Name:  "jane@sourcegraph.com",
+					Email: "jane@sourcegraph.com",
+					Count: 1125,
+				},
+				{
+					Name:  "Bot Of Doom",
+					Email: "bot@doombot.com",
+					Count: 390,
+				},
+			},
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			got, gotErr := ParseShortLog([]byte(tst.input))
+			if (gotErr == nil) != (tst.wantErr == nil) {
+				t.Fatalf("gotErr %+v wantErr %+v", gotErr, tst.wantErr)
+			}
+			if !reflect.DeepEqual(got, tst.want) {
+				t.Logf("got %q", got)
+				t.Fatalf("want %q", tst.want)
+			}
+		})
+	}
+}
+
+func TestCommitLogPartsPerCommitInSync(t *testing.T) {
+	if got := strings.Count(CommitLogFormat, "%x00"); got != commitLogPartsPerCommit-1 {
+		t.Fatalf("CommitLogFormat has %d separators, want %d", got, commitLogPartsPerCommit-1)
+	}
+}
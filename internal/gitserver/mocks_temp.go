@@ -102,6 +102,9 @@ type MockClient struct {
 	// HeadFunc is an instance of a mock function object controlling the
 	// behavior of the method Head.
 	HeadFunc *ClientHeadFunc
+	// HgChangesetCommitFunc is an instance of a mock function object
+	// controlling the behavior of the method HgChangesetCommit.
+	HgChangesetCommitFunc *ClientHgChangesetCommitFunc
 	// IsPerforcePathCloneableFunc is an instance of a mock function object
 	// controlling the behavior of the method IsPerforcePathCloneable.
 	IsPerforcePathCloneableFunc *ClientIsPerforcePathCloneableFunc
@@ -150,6 +153,9 @@ type MockClient struct {
 	// PerforceUsersFunc is an instance of a mock function object
 	// controlling the behavior of the method PerforceUsers.
 	PerforceUsersFunc *ClientPerforceUsersFunc
+	// ReadBlobsFunc is an instance of a mock function object controlling
+	// the behavior of the method ReadBlobs.
+	ReadBlobsFunc *ClientReadBlobsFunc
 	// ReadDirFunc is an instance of a mock function object controlling the
 	// behavior of the method ReadDir.
 	ReadDirFunc *ClientReadDirFunc
@@ -290,7 +296,7 @@ func NewMockClient() *MockClient {
 			},
 		},
 		DiffSymbolsFunc: &ClientDiffSymbolsFunc{
-			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) (r0 []byte, r1 error) {
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) (r0 []gitdomain.PathStatus, r1 error) {
 				return
 			},
 		},
@@ -334,6 +340,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		HgChangesetCommitFunc: &ClientHgChangesetCommitFunc{
+			defaultHook: func(context.Context, api.RepoName, string) (r0 api.CommitID, r1 error) {
+				return
+			},
+		},
 		IsPerforcePathCloneableFunc: &ClientIsPerforcePathCloneableFunc{
 			defaultHook: func(context.Context, protocol.PerforceConnectionDetails, string) (r0 error) {
 				return
@@ -414,6 +425,11 @@ func NewMockClient() *MockClient {
 				return
 			},
 		},
+		ReadBlobsFunc: &ClientReadBlobsFunc{
+			defaultHook: func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) (r0 error) {
+				return
+			},
+		},
 		ReadDirFunc: &ClientReadDirFunc{
 			defaultHook: func(context.Context, api.RepoName, api.CommitID, string, bool) (r0 []fs.FileInfo, r1 error) {
 				return
@@ -587,7 +603,7 @@ func NewStrictMockClient() *MockClient {
 			},
 		},
 		DiffSymbolsFunc: &ClientDiffSymbolsFunc{
-			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error) {
+			defaultHook: func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error) {
 				panic("unexpected invocation of MockClient.DiffSymbols")
 			},
 		},
@@ -631,6 +647,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.Head")
 			},
 		},
+		HgChangesetCommitFunc: &ClientHgChangesetCommitFunc{
+			defaultHook: func(context.Context, api.RepoName, string) (api.CommitID, error) {
+				panic("unexpected invocation of MockClient.HgChangesetCommit")
+			},
+		},
 		IsPerforcePathCloneableFunc: &ClientIsPerforcePathCloneableFunc{
 			defaultHook: func(context.Context, protocol.PerforceConnectionDetails, string) error {
 				panic("unexpected invocation of MockClient.IsPerforcePathCloneable")
@@ -711,6 +732,11 @@ func NewStrictMockClient() *MockClient {
 				panic("unexpected invocation of MockClient.PerforceUsers")
 			},
 		},
+		ReadBlobsFunc: &ClientReadBlobsFunc{
+			defaultHook: func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+				panic("unexpected invocation of MockClient.ReadBlobs")
+			},
+		},
 		ReadDirFunc: &ClientReadDirFunc{
 			defaultHook: func(context.Context, api.RepoName, api.CommitID, string, bool) ([]fs.FileInfo, error) {
 				panic("unexpected invocation of MockClient.ReadDir")
@@ -876,6 +902,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		HeadFunc: &ClientHeadFunc{
 			defaultHook: i.Head,
 		},
+		HgChangesetCommitFunc: &ClientHgChangesetCommitFunc{
+			defaultHook: i.HgChangesetCommit,
+		},
 		IsPerforcePathCloneableFunc: &ClientIsPerforcePathCloneableFunc{
 			defaultHook: i.IsPerforcePathCloneable,
 		},
@@ -924,6 +953,9 @@ func NewMockClientFrom(i Client) *MockClient {
 		PerforceUsersFunc: &ClientPerforceUsersFunc{
 			defaultHook: i.PerforceUsers,
 		},
+		ReadBlobsFunc: &ClientReadBlobsFunc{
+			defaultHook: i.ReadBlobs,
+		},
 		ReadDirFunc: &ClientReadDirFunc{
 			defaultHook: i.ReadDir,
 		},
@@ -2839,15 +2871,15 @@ func (c ClientDiffPathFuncCall) Results() []interface{} {
 // ClientDiffSymbolsFunc describes the behavior when the DiffSymbols method
 // of the parent MockClient instance is invoked.
 type ClientDiffSymbolsFunc struct {
-	defaultHook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error)
-	hooks       []func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error)
+	defaultHook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error)
+	hooks       []func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error)
 	history     []ClientDiffSymbolsFuncCall
 	mutex       sync.Mutex
 }
 
 // DiffSymbols delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockClient) DiffSymbols(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 api.CommitID) ([]byte, error) {
+func (m *MockClient) DiffSymbols(v0 context.Context, v1 api.RepoName, v2 api.CommitID, v3 api.CommitID) ([]gitdomain.PathStatus, error) {
 	r0, r1 := m.DiffSymbolsFunc.nextHook()(v0, v1, v2, v3)
 	m.DiffSymbolsFunc.appendCall(ClientDiffSymbolsFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
@@ -2855,7 +2887,7 @@ func (m *MockClient) DiffSymbols(v0 context.Context, v1 api.RepoName, v2 api.Com
 
 // SetDefaultHook sets function that is called when the DiffSymbols method
 // of the parent MockClient instance is invoked and the hook queue is empty.
-func (f *ClientDiffSymbolsFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error)) {
+func (f *ClientDiffSymbolsFunc) SetDefaultHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error)) {
 	f.defaultHook = hook
 }
 
@@ -2863,7 +2895,7 @@ func (f *ClientDiffSymbolsFunc) SetDefaultHook(hook func(context.Context, api.Re
 // DiffSymbols method of the parent MockClient instance invokes the hook at
 // the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *ClientDiffSymbolsFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error)) {
+func (f *ClientDiffSymbolsFunc) PushHook(hook func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -2871,20 +2903,20 @@ func (f *ClientDiffSymbolsFunc) PushHook(hook func(context.Context, api.RepoName
 
 // SetDefaultReturn calls SetDefaultHook with a function that returns the
 // given values.
-func (f *ClientDiffSymbolsFunc) SetDefaultReturn(r0 []byte, r1 error) {
-	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error) {
+func (f *ClientDiffSymbolsFunc) SetDefaultReturn(r0 []gitdomain.PathStatus, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushHook with a function that returns the given values.
-func (f *ClientDiffSymbolsFunc) PushReturn(r0 []byte, r1 error) {
-	f.PushHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error) {
+func (f *ClientDiffSymbolsFunc) PushReturn(r0 []gitdomain.PathStatus, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error) {
 		return r0, r1
 	})
 }
 
-func (f *ClientDiffSymbolsFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]byte, error) {
+func (f *ClientDiffSymbolsFunc) nextHook() func(context.Context, api.RepoName, api.CommitID, api.CommitID) ([]gitdomain.PathStatus, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2931,7 +2963,7 @@ type ClientDiffSymbolsFuncCall struct {
 	Arg3 api.CommitID
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []byte
+	Result0 []gitdomain.PathStatus
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -3842,6 +3874,117 @@ func (c ClientHeadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// ClientHgChangesetCommitFunc describes the behavior when the
+// HgChangesetCommit method of the parent MockClient instance is invoked.
+type ClientHgChangesetCommitFunc struct {
+	defaultHook func(context.Context, api.RepoName, string) (api.CommitID, error)
+	hooks       []func(context.Context, api.RepoName, string) (api.CommitID, error)
+	history     []ClientHgChangesetCommitFuncCall
+	mutex       sync.Mutex
+}
+
+// HgChangesetCommit delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockClient) HgChangesetCommit(v0 context.Context, v1 api.RepoName, v2 string) (api.CommitID, error) {
+	r0, r1 := m.HgChangesetCommitFunc.nextHook()(v0, v1, v2)
+	m.HgChangesetCommitFunc.appendCall(ClientHgChangesetCommitFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the HgChangesetCommit
+// method of the parent MockClient instance is invoked and the hook queue is
+// empty.
+func (f *ClientHgChangesetCommitFunc) SetDefaultHook(hook func(context.Context, api.RepoName, string) (api.CommitID, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// HgChangesetCommit method of the parent MockClient instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *ClientHgChangesetCommitFunc) PushHook(hook func(context.Context, api.RepoName, string) (api.CommitID, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientHgChangesetCommitFunc) SetDefaultReturn(r0 api.CommitID, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, string) (api.CommitID, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientHgChangesetCommitFunc) PushReturn(r0 api.CommitID, r1 error) {
+	f.PushHook(func(context.Context, api.RepoName, string) (api.CommitID, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientHgChangesetCommitFunc) nextHook() func(context.Context, api.RepoName, string) (api.CommitID, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientHgChangesetCommitFunc) appendCall(r0 ClientHgChangesetCommitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientHgChangesetCommitFuncCall objects
+// describing the invocations of this function.
+func (f *ClientHgChangesetCommitFunc) History() []ClientHgChangesetCommitFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientHgChangesetCommitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientHgChangesetCommitFuncCall is an object that describes an invocation
+// of method HgChangesetCommit on an instance of MockClient.
+type ClientHgChangesetCommitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 api.CommitID
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientHgChangesetCommitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientHgChangesetCommitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientIsPerforcePathCloneableFunc describes the behavior when the
 // IsPerforcePathCloneable method of the parent MockClient instance is
 // invoked.
@@ -5627,6 +5770,116 @@ func (c ClientPerforceUsersFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientReadBlobsFunc describes the behavior when the ReadBlobs method of
+// the parent MockClient instance is invoked.
+type ClientReadBlobsFunc struct {
+	defaultHook func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error
+	hooks       []func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error
+	history     []ClientReadBlobsFuncCall
+	mutex       sync.Mutex
+}
+
+// ReadBlobs delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockClient) ReadBlobs(v0 context.Context, v1 api.RepoName, v2 []gitdomain.OID, v3 func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+	r0 := m.ReadBlobsFunc.nextHook()(v0, v1, v2, v3)
+	m.ReadBlobsFunc.appendCall(ClientReadBlobsFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the ReadBlobs method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientReadBlobsFunc) SetDefaultHook(hook func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ReadBlobs method of the parent MockClient instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientReadBlobsFunc) PushHook(hook func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *ClientReadBlobsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *ClientReadBlobsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+		return r0
+	})
+}
+
+func (f *ClientReadBlobsFunc) nextHook() func(context.Context, api.RepoName, []gitdomain.OID, func(oid gitdomain.OID, size int64, r io.Reader) error) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientReadBlobsFunc) appendCall(r0 ClientReadBlobsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientReadBlobsFuncCall objects describing
+// the invocations of this function.
+func (f *ClientReadBlobsFunc) History() []ClientReadBlobsFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientReadBlobsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientReadBlobsFuncCall is an object that describes an invocation of
+// method ReadBlobs on an instance of MockClient.
+type ClientReadBlobsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoName
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []gitdomain.OID
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 func(oid gitdomain.OID, size int64, r io.Reader) error
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientReadBlobsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientReadBlobsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // ClientReadDirFunc describes the behavior when the ReadDir method of the
 // parent MockClient instance is invoked.
 type ClientReadDirFunc struct {
@@ -25,12 +25,14 @@ type operations struct {
 	getCommit                *observation.Operation
 	getCommits               *observation.Operation
 	hasCommitAfter           *observation.Operation
+	hgChangesetCommit        *observation.Operation
 	listBranches             *observation.Operation
 	listRefs                 *observation.Operation
 	listTags                 *observation.Operation
 	lstat                    *observation.Operation
 	mergeBase                *observation.Operation
 	newFileReader            *observation.Operation
+	readBlobs                *observation.Operation
 	readDir                  *observation.Operation
 	readFile                 *observation.Operation
 	resolveRevision          *observation.Operation
@@ -131,12 +133,14 @@ func newOperations(observationCtx *observation.Context) *operations {
 		getCommit:                op("GetCommit"),
 		getCommits:               op("GetCommits"),
 		hasCommitAfter:           op("HasCommitAfter"),
+		hgChangesetCommit:        op("HgChangesetCommit"),
 		listBranches:             op("ListBranches"),
 		listRefs:                 op("ListRefs"),
 		listTags:                 op("ListTags"),
 		lstat:                    subOp("lStat"),
 		mergeBase:                op("MergeBase"),
 		newFileReader:            op("NewFileReader"),
+		readBlobs:                op("ReadBlobs"),
 		readDir:                  op("ReadDir"),
 		readFile:                 op("ReadFile"),
 		resolveRevision:          resolveRevisionOperation,
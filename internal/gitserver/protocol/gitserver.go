@@ -22,6 +22,10 @@ type SearchRequest struct {
 	IncludeDiff          bool
 	Limit                int
 	IncludeModifiedFiles bool
+
+	// IncludeContainingRefs makes the SourceRefs of each match list every
+	// searched revision which contains the commit.
+	IncludeContainingRefs bool
 }
 
 func (r *SearchRequest) ToProto() *proto.SearchRequest {
@@ -30,12 +34,13 @@ func (r *SearchRequest) ToProto() *proto.SearchRequest {
 		revs = append(revs, rev.ToProto())
 	}
 	return &proto.SearchRequest{
-		Repo:                 string(r.Repo),
-		Revisions:            revs,
-		Query:                r.Query.ToProto(),
-		IncludeDiff:          r.IncludeDiff,
-		Limit:                int64(r.Limit),
-		IncludeModifiedFiles: r.IncludeModifiedFiles,
+		Repo:                  string(r.Repo),
+		Revisions:             revs,
+		Query:                 r.Query.ToProto(),
+		IncludeDiff:           r.IncludeDiff,
+		Limit:                 int64(r.Limit),
+		IncludeModifiedFiles:  r.IncludeModifiedFiles,
+		IncludeContainingRefs: r.IncludeContainingRefs,
 	}
 }
 
@@ -51,12 +56,13 @@ func SearchRequestFromProto(p *proto.SearchRequest) (*SearchRequest, error) {
 	}
 
 	return &SearchRequest{
-		Repo:                 api.RepoName(p.GetRepo()),
-		Revisions:            revisions,
-		Query:                query,
-		IncludeDiff:          p.GetIncludeDiff(),
-		Limit:                int(p.GetLimit()),
-		IncludeModifiedFiles: p.GetIncludeModifiedFiles(),
+		Repo:                  api.RepoName(p.GetRepo()),
+		Revisions:             revisions,
+		Query:                 query,
+		IncludeDiff:           p.GetIncludeDiff(),
+		Limit:                 int(p.GetLimit()),
+		IncludeModifiedFiles:  p.GetIncludeModifiedFiles(),
+		IncludeContainingRefs: p.GetIncludeContainingRefs(),
 	}, nil
 }
 
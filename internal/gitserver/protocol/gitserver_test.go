@@ -20,7 +20,8 @@ func TestSearchRequestProtoRoundtrip(t *testing.T) {
 				&CommitAfter{Time: time.Date(2021, 12, 3, 12, 3, 45, 0, time.UTC)},
 			},
 		},
-		Limit: 42,
+		Limit:                 42,
+		IncludeContainingRefs: true,
 	}
 
 	protoReq := req.ToProto()
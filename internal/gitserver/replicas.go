@@ -0,0 +1,196 @@
+package gitserver
+
+import (
+	"context"
+	"math/rand"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
+)
+
+// replicaClient is a proto.GitserverServiceClient for a repo that is
+// replicated across several gitserver instances. Reads that can be served by
+// any copy of the repo are spread across all replicas and fail over to the next
+// replica if one is unavailable or hasn't cloned the repo yet, or to the
+// primary if a secondary hasn't fetched a revision yet. All other methods,
+// including writes and fetches, are sent to the primary.
+type replicaClient struct {
+	// GitserverServiceClient is the client of the primary.
+	proto.GitserverServiceClient
+
+	// replicas are the clients of all replicas, starting with the primary.
+	replicas []proto.GitserverServiceClient
+}
+
+// newReplicaClient returns a client for the given replicas of a repo. The
+// first client must be the one of the primary.
+func newReplicaClient(replicas []proto.GitserverServiceClient) proto.GitserverServiceClient {
+	if len(replicas) == 1 {
+		return replicas[0]
+	}
+	return &replicaClient{
+		GitserverServiceClient: replicas[0],
+		replicas:               replicas,
+	}
+}
+
+func (r *replicaClient) Exec(ctx context.Context, in *proto.ExecRequest, opts ...grpc.CallOption) (proto.GitserverService_ExecClient, error) {
+	args := make([]string, 0, len(in.GetArgs()))
+	for _, arg := range in.GetArgs() {
+		args = append(args, string(arg))
+	}
+	if !gitdomain.IsReadOnlyGitCmd(args) {
+		return r.GitserverServiceClient.Exec(ctx, in, opts...)
+	}
+
+	return recvFromReplicas[proto.ExecResponse](r.GitserverServiceClient, r.readOrder(), func(c proto.GitserverServiceClient) (proto.GitserverService_ExecClient, error) {
+		return c.Exec(ctx, in, opts...)
+	})
+}
+
+func (r *replicaClient) Archive(ctx context.Context, in *proto.ArchiveRequest, opts ...grpc.CallOption) (proto.GitserverService_ArchiveClient, error) {
+	return recvFromReplicas[proto.ArchiveResponse](r.GitserverServiceClient, r.readOrder(), func(c proto.GitserverServiceClient) (proto.GitserverService_ArchiveClient, error) {
+		return c.Archive(ctx, in, opts...)
+	})
+}
+
+func (r *replicaClient) Search(ctx context.Context, in *proto.SearchRequest, opts ...grpc.CallOption) (proto.GitserverService_SearchClient, error) {
+	return recvFromReplicas[proto.SearchResponse](r.GitserverServiceClient, r.readOrder(), func(c proto.GitserverServiceClient) (proto.GitserverService_SearchClient, error) {
+		return c.Search(ctx, in, opts...)
+	})
+}
+
+func (r *replicaClient) GetBlobs(ctx context.Context, in *proto.GetBlobsRequest, opts ...grpc.CallOption) (proto.GitserverService_GetBlobsClient, error) {
+	return recvFromReplicas[proto.GetBlobsResponse](r.GitserverServiceClient, r.readOrder(), func(c proto.GitserverServiceClient) (proto.GitserverService_GetBlobsClient, error) {
+		return c.GetBlobs(ctx, in, opts...)
+	})
+}
+
+// readOrder returns the replicas in the order in which a read tries them. The
+// first replica is picked at random to spread the load across all of them.
+func (r *replicaClient) readOrder() []proto.GitserverServiceClient {
+	start := rand.Intn(len(r.replicas))
+	order := make([]proto.GitserverServiceClient, 0, len(r.replicas))
+	order = append(order, r.replicas[start:]...)
+	return append(order, r.replicas[:start]...)
+}
+
+// recvStream is the client side of a server-streaming RPC.
+type recvStream[T any] interface {
+	grpc.ClientStream
+	Recv() (*T, error)
+}
+
+// recvFromReplicas starts the streaming RPC with call on each of the replicas
+// in turn, until one of them responds with something other than an error that
+// allows failing over to the next replica. Once the first message has been
+// received, the stream is committed to that replica.
+//
+// Secondary copies are fetched from the primary periodically, so a revision
+// that was pushed recently may be missing from them. If a secondary can't find
+// a revision, the RPC is retried on the primary.
+//
+// If all replicas fail, the error of the last one is returned from the stream
+// so that callers handle it like any other error of the RPC.
+func recvFromReplicas[T any, S recvStream[T]](primary proto.GitserverServiceClient, replicas []proto.GitserverServiceClient, call func(proto.GitserverServiceClient) (S, error)) (*peekedStream[T], error) {
+	start := func(c proto.GitserverServiceClient) (*peekedStream[T], error) {
+		stream, err := call(c)
+		if err != nil {
+			return nil, err
+		}
+		first, err := stream.Recv()
+		return &peekedStream[T]{ClientStream: stream, recv: stream.Recv, first: first, firstErr: err}, err
+	}
+	// Errors received from the stream are returned by the stream.
+	result := func(stream *peekedStream[T], err error) (*peekedStream[T], error) {
+		if stream != nil {
+			return stream, nil
+		}
+		return nil, err
+	}
+
+	var (
+		lastStream    *peekedStream[T]
+		lastErr       error
+		primaryFailed bool
+	)
+	for _, c := range replicas {
+		stream, err := start(c)
+		if err != nil && c != primary && isRevisionNotFoundError(err) {
+			if !primaryFailed {
+				if primaryStream, primaryErr := start(primary); !isReplicaFailoverError(primaryErr) {
+					return result(primaryStream, primaryErr)
+				}
+			}
+			return result(stream, err)
+		}
+		if err == nil || !isReplicaFailoverError(err) {
+			return result(stream, err)
+		}
+		lastStream, lastErr = stream, err
+		primaryFailed = primaryFailed || c == primary
+	}
+	return result(lastStream, lastErr)
+}
+
+// isReplicaFailoverError returns true if err indicates that the replica can't
+// serve the request, but another replica of the repo may.
+func isReplicaFailoverError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Code() {
+	case codes.Unavailable:
+		return true
+	case codes.NotFound:
+		// The repo is not cloned yet on this replica. Other NotFound errors,
+		// such as missing revisions or files, are returned as is.
+		for _, d := range s.Details() {
+			if _, ok := d.(*proto.NotFoundPayload); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRevisionNotFoundError returns true if err indicates that a revision doesn't
+// exist in the repo.
+func isRevisionNotFoundError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	for _, d := range s.Details() {
+		switch payload := d.(type) {
+		case *proto.RevisionNotFoundPayload:
+			return true
+		case *proto.ExecStatusPayload:
+			return isRevisionNotFound(payload.GetStderr())
+		}
+	}
+	return false
+}
+
+// peekedStream is a stream whose first message has already been received.
+type peekedStream[T any] struct {
+	grpc.ClientStream
+	recv func() (*T, error)
+
+	first    *T
+	firstErr error
+	peeked   bool
+}
+
+func (s *peekedStream[T]) Recv() (*T, error) {
+	if !s.peeked {
+		s.peeked = true
+		return s.first, s.firstErr
+	}
+	return s.recv()
+}
@@ -0,0 +1,170 @@
+package gitserver
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	proto "github.com/sourcegraph/sourcegraph/internal/gitserver/v1"
+)
+
+func TestReplicaClient(t *testing.T) {
+	ctx := context.Background()
+
+	notCloned, err := status.New(codes.NotFound, "repo not found").WithDetails(&proto.NotFoundPayload{Repo: "repo", CloneInProgress: true})
+	require.NoError(t, err)
+
+	newReplica := func(calls *int, data string, err error) *MockGitserverServiceClient {
+		c := NewStrictMockGitserverServiceClient()
+		c.ArchiveFunc.SetDefaultHook(func(context.Context, *proto.ArchiveRequest, ...grpc.CallOption) (proto.GitserverService_ArchiveClient, error) {
+			*calls++
+			return &fakeArchiveStream{data: data, err: err}, nil
+		})
+		c.ExecFunc.SetDefaultHook(func(context.Context, *proto.ExecRequest, ...grpc.CallOption) (proto.GitserverService_ExecClient, error) {
+			*calls++
+			return nil, err
+		})
+		return c
+	}
+
+	readAll := func(t *testing.T, stream proto.GitserverService_ArchiveClient) (string, error) {
+		var data []byte
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return string(data), nil
+			}
+			if err != nil {
+				return string(data), err
+			}
+			data = append(data, resp.GetData()...)
+		}
+	}
+
+	t.Run("fails over to the next replica", func(t *testing.T) {
+		// The first replica is picked at random, so try several times to cover
+		// all orders.
+		for i := 0; i < 20; i++ {
+			var unavailableCalls, notClonedCalls, okCalls int
+			client := newReplicaClient([]proto.GitserverServiceClient{
+				newReplica(&unavailableCalls, "", status.Error(codes.Unavailable, "connection refused")),
+				newReplica(&notClonedCalls, "", notCloned.Err()),
+				newReplica(&okCalls, "archive", nil),
+			})
+
+			stream, err := client.Archive(ctx, &proto.ArchiveRequest{Repo: "repo"})
+			require.NoError(t, err)
+			data, err := readAll(t, stream)
+			require.NoError(t, err)
+			assert.Equal(t, "archive", data)
+			assert.Equal(t, 1, okCalls)
+		}
+	})
+
+	t.Run("returns the error of the last replica", func(t *testing.T) {
+		var calls int
+		client := newReplicaClient([]proto.GitserverServiceClient{
+			newReplica(&calls, "", notCloned.Err()),
+			newReplica(&calls, "", notCloned.Err()),
+		})
+
+		stream, err := client.Archive(ctx, &proto.ArchiveRequest{Repo: "repo"})
+		require.NoError(t, err)
+		_, err = readAll(t, stream)
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("does not fail over on other errors", func(t *testing.T) {
+		var calls int
+		client := newReplicaClient([]proto.GitserverServiceClient{
+			newReplica(&calls, "", status.Error(codes.InvalidArgument, "invalid treeish")),
+			newReplica(&calls, "", status.Error(codes.InvalidArgument, "invalid treeish")),
+		})
+
+		stream, err := client.Archive(ctx, &proto.ArchiveRequest{Repo: "repo"})
+		require.NoError(t, err)
+		_, err = readAll(t, stream)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("fails over to the primary if a secondary is stale", func(t *testing.T) {
+		revisionNotFound, err := status.New(codes.NotFound, "revision not found").WithDetails(&proto.RevisionNotFoundPayload{Repo: "repo", Spec: "deadbeef"})
+		require.NoError(t, err)
+
+		// The first replica is picked at random, so try several times to cover
+		// all orders.
+		for i := 0; i < 20; i++ {
+			var primaryCalls, secondaryCalls int
+			client := newReplicaClient([]proto.GitserverServiceClient{
+				newReplica(&primaryCalls, "archive", nil),
+				newReplica(&secondaryCalls, "", revisionNotFound.Err()),
+				newReplica(&secondaryCalls, "", revisionNotFound.Err()),
+			})
+
+			stream, err := client.Archive(ctx, &proto.ArchiveRequest{Repo: "repo", Treeish: "deadbeef"})
+			require.NoError(t, err)
+			data, err := readAll(t, stream)
+			require.NoError(t, err)
+			assert.Equal(t, "archive", data)
+			assert.Equal(t, 1, primaryCalls)
+			assert.LessOrEqual(t, secondaryCalls, 1)
+		}
+	})
+
+	t.Run("returns revision not found errors of the primary", func(t *testing.T) {
+		revisionNotFound, err := status.New(codes.Unknown, "exit status 128").WithDetails(&proto.ExecStatusPayload{StatusCode: 128, Stderr: "fatal: Not a valid object name deadbeef"})
+		require.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			var primaryCalls, secondaryCalls int
+			client := newReplicaClient([]proto.GitserverServiceClient{
+				newReplica(&primaryCalls, "", revisionNotFound.Err()),
+				newReplica(&secondaryCalls, "", revisionNotFound.Err()),
+			})
+
+			_, err := client.Exec(ctx, &proto.ExecRequest{Repo: "repo", Args: [][]byte{[]byte("show"), []byte("deadbeef")}})
+			assert.Equal(t, codes.Unknown, status.Code(err))
+			assert.Equal(t, 1, primaryCalls)
+			assert.LessOrEqual(t, secondaryCalls, 1)
+		}
+	})
+
+	t.Run("sends writes to the primary", func(t *testing.T) {
+		var primaryCalls, secondaryCalls int
+		client := newReplicaClient([]proto.GitserverServiceClient{
+			newReplica(&primaryCalls, "", status.Error(codes.Unavailable, "connection refused")),
+			newReplica(&secondaryCalls, "", nil),
+		})
+
+		_, err := client.Exec(ctx, &proto.ExecRequest{Repo: "repo", Args: [][]byte{[]byte("push"), []byte("--force")}})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, 1, primaryCalls)
+		assert.Equal(t, 0, secondaryCalls)
+	})
+}
+
+type fakeArchiveStream struct {
+	grpc.ClientStream
+	data string
+	err  error
+}
+
+func (s *fakeArchiveStream) Recv() (*proto.ArchiveResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if s.data == "" {
+		return nil, io.EOF
+	}
+	resp := &proto.ArchiveResponse{Data: []byte(s.data)}
+	s.data = ""
+	return resp, nil
+}
@@ -140,4 +140,44 @@ func (r *automaticRetryClient) PerforceGetChangelist(ctx context.Context, in *pr
 	return r.base.PerforceGetChangelist(ctx, in, opts...)
 }
 
+func (r *automaticRetryClient) MergeBase(ctx context.Context, in *proto.MergeBaseRequest, opts ...grpc.CallOption) (*proto.MergeBaseResponse, error) {
+	opts = append(defaults.RetryPolicy, opts...)
+	return r.base.MergeBase(ctx, in, opts...)
+}
+
+func (r *automaticRetryClient) Blame(ctx context.Context, in *proto.BlameRequest, opts ...grpc.CallOption) (proto.GitserverService_BlameClient, error) {
+	opts = append(defaults.RetryPolicy, opts...)
+	return r.base.Blame(ctx, in, opts...)
+}
+
+func (r *automaticRetryClient) ReadDir(ctx context.Context, in *proto.ReadDirRequest, opts ...grpc.CallOption) (proto.GitserverService_ReadDirClient, error) {
+	opts = append(defaults.RetryPolicy, opts...)
+	return r.base.ReadDir(ctx, in, opts...)
+}
+
+func (r *automaticRetryClient) LsFiles(ctx context.Context, in *proto.LsFilesRequest, opts ...grpc.CallOption) (proto.GitserverService_LsFilesClient, error) {
+	opts = append(defaults.RetryPolicy, opts...)
+	return r.base.LsFiles(ctx, in, opts...)
+}
+
+func (r *automaticRetryClient) Commits(ctx context.Context, in *proto.CommitsRequest, opts ...grpc.CallOption) (proto.GitserverService_CommitsClient, error) {
+	opts = append(defaults.RetryPolicy, opts...)
+	return r.base.Commits(ctx, in, opts...)
+}
+
+func (r *automaticRetryClient) DiffSymbols(ctx context.Context, in *proto.DiffSymbolsRequest, opts ...grpc.CallOption) (proto.GitserverService_DiffSymbolsClient, error) {
+	opts = append(defaults.RetryPolicy, opts...)
+	return r.base.DiffSymbols(ctx, in, opts...)
+}
+
+func (r *automaticRetryClient) ContributorCounts(ctx context.Context, in *proto.ContributorCountsRequest, opts ...grpc.CallOption) (*proto.ContributorCountsResponse, error) {
+	opts = append(defaults.RetryPolicy, opts...)
+	return r.base.ContributorCounts(ctx, in, opts...)
+}
+
+func (r *automaticRetryClient) GetBlobs(ctx context.Context, in *proto.GetBlobsRequest, opts ...grpc.CallOption) (proto.GitserverService_GetBlobsClient, error) {
+	opts = append(defaults.RetryPolicy, opts...)
+	return r.base.GetBlobs(ctx, in, opts...)
+}
+
 var _ proto.GitserverServiceClient = &automaticRetryClient{}
@@ -14,7 +14,8 @@ import (
 // DiffFetcher is a handle to the stdin and stdout of a git diff-tree subprocess
 // started with StartDiffFetcher
 type DiffFetcher struct {
-	dir string
+	dir       string
+	configure func(*exec.Cmd)
 
 	startOnce sync.Once
 	stdin     io.Writer
@@ -25,10 +26,11 @@ type DiffFetcher struct {
 }
 
 // NewDiffFetcher starts a git diff-tree subprocess that waits, listening on stdin
-// for comimt hashes to generate patches for.
-func NewDiffFetcher(dir string) (*DiffFetcher, error) {
+// for comimt hashes to generate patches for. If configure is not nil, it is
+// called with the subprocess before it starts.
+func NewDiffFetcher(dir string, configure func(*exec.Cmd)) (*DiffFetcher, error) {
 
-	return &DiffFetcher{dir: dir}, nil
+	return &DiffFetcher{dir: dir, configure: configure}, nil
 }
 
 func (d *DiffFetcher) Stop() {
@@ -51,6 +53,9 @@ func (d *DiffFetcher) start() (err error) {
 			"--root",           // Treat the root commit as a big creation event (otherwise the diff would be empty)
 		)
 		d.cmd.Dir = d.dir
+		if d.configure != nil {
+			d.configure(d.cmd)
+		}
 
 		var stdoutReader io.ReadCloser
 		stdoutReader, err = d.cmd.StdoutPipe()
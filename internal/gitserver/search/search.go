@@ -89,6 +89,15 @@ type CommitSearcher struct {
 	IncludeDiff          bool
 	IncludeModifiedFiles bool
 	RepoName             api.RepoName
+
+	// IncludeContainingRefs replaces the SourceRefs of each match with every
+	// searched revision which contains the commit.
+	IncludeContainingRefs bool
+
+	// ConfigureCommand, if set, is called with every git command the
+	// searcher runs, for example so that partial clones can fetch missing
+	// blobs when diffs are searched.
+	ConfigureCommand func(*exec.Cmd)
 }
 
 // Search runs a search for commits matching the given predicate across the revisions passed in as revisionArgs.
@@ -134,18 +143,32 @@ func (cs *CommitSearcher) Search(ctx context.Context, onMatch func(*protocol.Com
 	return g.Wait()
 }
 
-func (cs *CommitSearcher) gitArgs() []string {
-	revArgs := revsToGitArgs(cs.Revisions)
-	args := append(logArgs, revArgs...)
+// gitArgs returns the arguments to git log along with the revisions to pass
+// to it on stdin. Revisions resolved from a glob like rev:*refs/heads/* can
+// number in the thousands, so only ref glob options are passed as arguments.
+// git log walks all revisions at once, so commits reachable from multiple
+// refs are only searched once.
+func (cs *CommitSearcher) gitArgs() (args []string, stdin string) {
+	args = append(args, logArgs...)
+	var revs []string
+	for _, arg := range revsToGitArgs(cs.Revisions) {
+		if strings.HasPrefix(arg, "--") {
+			args = append(args, arg)
+		} else {
+			revs = append(revs, arg)
+		}
+	}
+	args = append(args, "--stdin")
 	if cs.IncludeModifiedFiles {
 		args = append(args, "--name-status")
 	}
-	return args
+	return args, strings.Join(revs, "\n")
 }
 
 func (cs *CommitSearcher) feedBatches(ctx context.Context, jobs chan job, resultChans chan chan *protocol.CommitMatch) (err error) {
-	cmd := exec.CommandContext(ctx, "git", cs.gitArgs()...)
-	cmd.Dir = cs.RepoDir
+	args, stdin := cs.gitArgs()
+	cmd := cs.command(ctx, args...)
+	cmd.Stdin = strings.NewReader(stdin)
 	stdoutReader, err := cmd.StdoutPipe()
 	if err != nil {
 		return err
@@ -194,6 +217,16 @@ func (cs *CommitSearcher) feedBatches(ctx context.Context, jobs chan job, result
 	return scanner.Err()
 }
 
+// command returns a git command running in the repository directory.
+func (cs *CommitSearcher) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = cs.RepoDir
+	if cs.ConfigureCommand != nil {
+		cs.ConfigureCommand(cmd)
+	}
+	return cmd
+}
+
 func tryInterpretErrorWithStderr(ctx context.Context, err error, stderr string, logger log.Logger) error {
 	if ctx.Err() != nil {
 		// Ignore errors when context is cancelled
@@ -219,7 +252,7 @@ func getSubRepoFilterFunc(ctx context.Context, checker authz.SubRepoPermissionCh
 
 func (cs *CommitSearcher) runJobs(ctx context.Context, jobs chan job) error {
 	// Create a new diff fetcher subprocess for each worker
-	diffFetcher, err := NewDiffFetcher(cs.RepoDir)
+	diffFetcher, err := NewDiffFetcher(cs.RepoDir, cs.ConfigureCommand)
 	if err != nil {
 		return err
 	}
@@ -230,6 +263,16 @@ func (cs *CommitSearcher) runJobs(ctx context.Context, jobs chan job) error {
 	runJob := func(j job) error {
 		defer close(j.resultChan)
 
+		var matches []*protocol.CommitMatch
+		defer func() {
+			if cs.IncludeContainingRefs {
+				cs.setContainingRefs(ctx, matches)
+			}
+			for _, cm := range matches {
+				j.resultChan <- cm
+			}
+		}()
+
 		for _, cv := range j.batch {
 			if ctx.Err() != nil {
 				// ignore context error, and don't spend time running the job
@@ -250,7 +293,7 @@ func (cs *CommitSearcher) runJobs(ctx context.Context, jobs chan job) error {
 				if err != nil {
 					return err
 				}
-				j.resultChan <- cm
+				matches = append(matches, cm)
 			}
 		}
 		return nil
@@ -263,6 +306,104 @@ func (cs *CommitSearcher) runJobs(ctx context.Context, jobs chan job) error {
 	return errs
 }
 
+// setContainingRefs sets the SourceRefs of each match to the searched
+// revisions which contain it. git log only reports the first ref it reached a
+// commit from, so the refs are listed with git for-each-ref --contains. A
+// single invocation for the whole batch narrows the refs down to those
+// containing any of its commits, which are then checked per match. Failing to
+// list refs is not fatal, the matches are still returned.
+func (cs *CommitSearcher) setContainingRefs(ctx context.Context, matches []*protocol.CommitMatch) {
+	if len(matches) == 0 || ctx.Err() != nil {
+		return
+	}
+
+	commits := make([]string, 0, len(matches))
+	for _, cm := range matches {
+		commits = append(commits, string(cm.Oid))
+	}
+	candidates, err := cs.refsContaining(ctx, commits, nil)
+	if err != nil {
+		cs.logContainingRefsError(ctx, err)
+		return
+	}
+
+	searched := make(map[string]struct{}, len(cs.Revisions))
+	for _, rev := range cs.Revisions {
+		if rev.RevSpec != "" {
+			searched[rev.RevSpec] = struct{}{}
+		}
+	}
+	var refNames []string
+	for _, ref := range candidates {
+		if searchedRev(searched, ref) != "" {
+			refNames = append(refNames, ref)
+		}
+	}
+
+	for _, cm := range matches {
+		cm.SourceRefs = nil
+		if len(refNames) == 0 {
+			continue
+		}
+		refs, err := cs.refsContaining(ctx, []string{string(cm.Oid)}, refNames)
+		if err != nil {
+			cs.logContainingRefsError(ctx, err)
+			continue
+		}
+		for _, ref := range refs {
+			if rev := searchedRev(searched, ref); rev != "" {
+				cm.SourceRefs = append(cm.SourceRefs, rev)
+			}
+		}
+	}
+}
+
+// refsContaining returns the names of the refs matching patterns which
+// contain any of the given commits. All refs are considered if patterns is
+// empty.
+func (cs *CommitSearcher) refsContaining(ctx context.Context, commits []string, patterns []string) ([]string, error) {
+	args := []string{"for-each-ref", "--format=%(refname)"}
+	for _, commit := range commits {
+		args = append(args, "--contains="+commit)
+	}
+	args = append(args, patterns...)
+
+	cmd := cs.command(ctx, args...)
+	var stderrBuf bytes.Buffer
+	cmd.Stderr = &stderrBuf
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "git for-each-ref: %s", stderrBuf.String())
+	}
+	return strings.Fields(string(out)), nil
+}
+
+func (cs *CommitSearcher) logContainingRefsError(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	cs.Logger.Warn("failed to list refs containing commits",
+		log.String("repo", string(cs.RepoName)),
+		log.Error(err))
+}
+
+// searchedRev returns the searched revision naming ref, either by its full
+// name or by its branch or tag name, or the empty string if ref was not
+// searched.
+func searchedRev(searched map[string]struct{}, ref string) string {
+	if _, ok := searched[ref]; ok {
+		return ref
+	}
+	for _, prefix := range []string{"refs/heads/", "refs/tags/"} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			if _, ok := searched[name]; ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
 func revsToGitArgs(revs []protocol.RevisionSpecifier) []string {
 	revArgs := make([]string, 0, len(revs))
 	for _, rev := range revs {
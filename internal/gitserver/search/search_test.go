@@ -76,6 +76,7 @@ func TestSearch(t *testing.T) {
 			"GIT_AUTHOR_EMAIL=camden3@ccheek.com " +
 			"GIT_AUTHOR_DATE=2006-01-02T15:04:05Z " +
 			"git commit -m commit3",
+		"git branch feature HEAD~1",
 	}
 	dir := initGitRepository(t, cmds...)
 
@@ -115,6 +116,58 @@ func TestSearch(t *testing.T) {
 		require.Equal(t, matches[2].Author.Name, "camden1")
 	})
 
+	t.Run("commits shared by revisions match once", func(t *testing.T) {
+		query := &protocol.MessageMatches{Expr: "c"}
+		tree, err := ToMatchTree(query)
+		require.NoError(t, err)
+		for _, revs := range [][]protocol.RevisionSpecifier{
+			{{RevSpec: "feature"}, {RevSpec: "HEAD"}},
+			{{RefGlob: "refs/heads/*"}},
+		} {
+			searcher := &CommitSearcher{
+				RepoDir:   dir,
+				Query:     tree,
+				Revisions: revs,
+			}
+			var matches []*protocol.CommitMatch
+			err = searcher.Search(context.Background(), func(match *protocol.CommitMatch) {
+				matches = append(matches, match)
+			})
+			require.NoError(t, err)
+			var authors []string
+			for _, match := range matches {
+				authors = append(authors, match.Author.Name)
+			}
+			require.ElementsMatch(t, []string{"camden1", "camden2", "camden3"}, authors)
+		}
+	})
+
+	t.Run("containing refs", func(t *testing.T) {
+		out, err := gitCommand(dir, "git", "symbolic-ref", "--short", "HEAD").Output()
+		require.NoError(t, err)
+		head := strings.TrimSpace(string(out))
+
+		query := &protocol.MessageMatches{Expr: "c"}
+		tree, err := ToMatchTree(query)
+		require.NoError(t, err)
+		searcher := &CommitSearcher{
+			RepoDir:               dir,
+			Query:                 tree,
+			Revisions:             []protocol.RevisionSpecifier{{RevSpec: head}, {RevSpec: "refs/heads/feature"}},
+			IncludeContainingRefs: true,
+		}
+		sourceRefs := make(map[string][]string)
+		err = searcher.Search(context.Background(), func(match *protocol.CommitMatch) {
+			sourceRefs[string(match.Message.Content)] = match.SourceRefs
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{
+			"commit1": {"refs/heads/feature", head},
+			"commit2": {"refs/heads/feature", head},
+			"commit3": {head},
+		}, sourceRefs)
+	})
+
 	t.Run("and with no operands matches all", func(t *testing.T) {
 		query := protocol.NewAnd()
 		tree, err := ToMatchTree(query)
@@ -303,8 +356,10 @@ func TestCommitScanner(t *testing.T) {
 
 	getRaw := func(dir string, includeModifiedFiles bool) []byte {
 		var buf bytes.Buffer
-		cmd := exec.Command("git", (&CommitSearcher{IncludeModifiedFiles: includeModifiedFiles}).gitArgs()...)
+		args, stdin := (&CommitSearcher{IncludeModifiedFiles: includeModifiedFiles}).gitArgs()
+		cmd := exec.Command("git", args...)
 		cmd.Dir = dir
+		cmd.Stdin = strings.NewReader(stdin)
 		cmd.Stdout = &buf
 		cmd.Run()
 		return buf.Bytes()
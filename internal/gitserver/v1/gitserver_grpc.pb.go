@@ -41,6 +41,14 @@ const (
 	GitserverService_PerforceGroupMembers_FullMethodName        = "/gitserver.v1.GitserverService/PerforceGroupMembers"
 	GitserverService_IsPerforceSuperUser_FullMethodName         = "/gitserver.v1.GitserverService/IsPerforceSuperUser"
 	GitserverService_PerforceGetChangelist_FullMethodName       = "/gitserver.v1.GitserverService/PerforceGetChangelist"
+	GitserverService_MergeBase_FullMethodName                   = "/gitserver.v1.GitserverService/MergeBase"
+	GitserverService_Blame_FullMethodName                       = "/gitserver.v1.GitserverService/Blame"
+	GitserverService_ReadDir_FullMethodName                     = "/gitserver.v1.GitserverService/ReadDir"
+	GitserverService_LsFiles_FullMethodName                     = "/gitserver.v1.GitserverService/LsFiles"
+	GitserverService_Commits_FullMethodName                     = "/gitserver.v1.GitserverService/Commits"
+	GitserverService_DiffSymbols_FullMethodName                 = "/gitserver.v1.GitserverService/DiffSymbols"
+	GitserverService_ContributorCounts_FullMethodName           = "/gitserver.v1.GitserverService/ContributorCounts"
+	GitserverService_GetBlobs_FullMethodName                    = "/gitserver.v1.GitserverService/GetBlobs"
 )
 
 // GitserverServiceClient is the client API for GitserverService service.
@@ -70,6 +78,14 @@ type GitserverServiceClient interface {
 	PerforceGroupMembers(ctx context.Context, in *PerforceGroupMembersRequest, opts ...grpc.CallOption) (*PerforceGroupMembersResponse, error)
 	IsPerforceSuperUser(ctx context.Context, in *IsPerforceSuperUserRequest, opts ...grpc.CallOption) (*IsPerforceSuperUserResponse, error)
 	PerforceGetChangelist(ctx context.Context, in *PerforceGetChangelistRequest, opts ...grpc.CallOption) (*PerforceGetChangelistResponse, error)
+	MergeBase(ctx context.Context, in *MergeBaseRequest, opts ...grpc.CallOption) (*MergeBaseResponse, error)
+	Blame(ctx context.Context, in *BlameRequest, opts ...grpc.CallOption) (GitserverService_BlameClient, error)
+	ReadDir(ctx context.Context, in *ReadDirRequest, opts ...grpc.CallOption) (GitserverService_ReadDirClient, error)
+	LsFiles(ctx context.Context, in *LsFilesRequest, opts ...grpc.CallOption) (GitserverService_LsFilesClient, error)
+	Commits(ctx context.Context, in *CommitsRequest, opts ...grpc.CallOption) (GitserverService_CommitsClient, error)
+	DiffSymbols(ctx context.Context, in *DiffSymbolsRequest, opts ...grpc.CallOption) (GitserverService_DiffSymbolsClient, error)
+	ContributorCounts(ctx context.Context, in *ContributorCountsRequest, opts ...grpc.CallOption) (*ContributorCountsResponse, error)
+	GetBlobs(ctx context.Context, in *GetBlobsRequest, opts ...grpc.CallOption) (GitserverService_GetBlobsClient, error)
 }
 
 type gitserverServiceClient struct {
@@ -396,6 +412,216 @@ func (c *gitserverServiceClient) PerforceGetChangelist(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *gitserverServiceClient) MergeBase(ctx context.Context, in *MergeBaseRequest, opts ...grpc.CallOption) (*MergeBaseResponse, error) {
+	out := new(MergeBaseResponse)
+	err := c.cc.Invoke(ctx, GitserverService_MergeBase_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gitserverServiceClient) Blame(ctx context.Context, in *BlameRequest, opts ...grpc.CallOption) (GitserverService_BlameClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GitserverService_ServiceDesc.Streams[5], GitserverService_Blame_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gitserverServiceBlameClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GitserverService_BlameClient interface {
+	Recv() (*BlameResponse, error)
+	grpc.ClientStream
+}
+
+type gitserverServiceBlameClient struct {
+	grpc.ClientStream
+}
+
+func (x *gitserverServiceBlameClient) Recv() (*BlameResponse, error) {
+	m := new(BlameResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gitserverServiceClient) ReadDir(ctx context.Context, in *ReadDirRequest, opts ...grpc.CallOption) (GitserverService_ReadDirClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GitserverService_ServiceDesc.Streams[6], GitserverService_ReadDir_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gitserverServiceReadDirClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GitserverService_ReadDirClient interface {
+	Recv() (*ReadDirResponse, error)
+	grpc.ClientStream
+}
+
+type gitserverServiceReadDirClient struct {
+	grpc.ClientStream
+}
+
+func (x *gitserverServiceReadDirClient) Recv() (*ReadDirResponse, error) {
+	m := new(ReadDirResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gitserverServiceClient) LsFiles(ctx context.Context, in *LsFilesRequest, opts ...grpc.CallOption) (GitserverService_LsFilesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GitserverService_ServiceDesc.Streams[7], GitserverService_LsFiles_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gitserverServiceLsFilesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GitserverService_LsFilesClient interface {
+	Recv() (*LsFilesResponse, error)
+	grpc.ClientStream
+}
+
+type gitserverServiceLsFilesClient struct {
+	grpc.ClientStream
+}
+
+func (x *gitserverServiceLsFilesClient) Recv() (*LsFilesResponse, error) {
+	m := new(LsFilesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gitserverServiceClient) Commits(ctx context.Context, in *CommitsRequest, opts ...grpc.CallOption) (GitserverService_CommitsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GitserverService_ServiceDesc.Streams[8], GitserverService_Commits_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gitserverServiceCommitsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GitserverService_CommitsClient interface {
+	Recv() (*CommitsResponse, error)
+	grpc.ClientStream
+}
+
+type gitserverServiceCommitsClient struct {
+	grpc.ClientStream
+}
+
+func (x *gitserverServiceCommitsClient) Recv() (*CommitsResponse, error) {
+	m := new(CommitsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gitserverServiceClient) DiffSymbols(ctx context.Context, in *DiffSymbolsRequest, opts ...grpc.CallOption) (GitserverService_DiffSymbolsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GitserverService_ServiceDesc.Streams[9], GitserverService_DiffSymbols_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gitserverServiceDiffSymbolsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GitserverService_DiffSymbolsClient interface {
+	Recv() (*DiffSymbolsResponse, error)
+	grpc.ClientStream
+}
+
+type gitserverServiceDiffSymbolsClient struct {
+	grpc.ClientStream
+}
+
+func (x *gitserverServiceDiffSymbolsClient) Recv() (*DiffSymbolsResponse, error) {
+	m := new(DiffSymbolsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *gitserverServiceClient) ContributorCounts(ctx context.Context, in *ContributorCountsRequest, opts ...grpc.CallOption) (*ContributorCountsResponse, error) {
+	out := new(ContributorCountsResponse)
+	err := c.cc.Invoke(ctx, GitserverService_ContributorCounts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gitserverServiceClient) GetBlobs(ctx context.Context, in *GetBlobsRequest, opts ...grpc.CallOption) (GitserverService_GetBlobsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &GitserverService_ServiceDesc.Streams[10], GitserverService_GetBlobs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &gitserverServiceGetBlobsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type GitserverService_GetBlobsClient interface {
+	Recv() (*GetBlobsResponse, error)
+	grpc.ClientStream
+}
+
+type gitserverServiceGetBlobsClient struct {
+	grpc.ClientStream
+}
+
+func (x *gitserverServiceGetBlobsClient) Recv() (*GetBlobsResponse, error) {
+	m := new(GetBlobsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // GitserverServiceServer is the server API for GitserverService service.
 // All implementations must embed UnimplementedGitserverServiceServer
 // for forward compatibility
@@ -423,6 +649,14 @@ type GitserverServiceServer interface {
 	PerforceGroupMembers(context.Context, *PerforceGroupMembersRequest) (*PerforceGroupMembersResponse, error)
 	IsPerforceSuperUser(context.Context, *IsPerforceSuperUserRequest) (*IsPerforceSuperUserResponse, error)
 	PerforceGetChangelist(context.Context, *PerforceGetChangelistRequest) (*PerforceGetChangelistResponse, error)
+	MergeBase(context.Context, *MergeBaseRequest) (*MergeBaseResponse, error)
+	Blame(*BlameRequest, GitserverService_BlameServer) error
+	ReadDir(*ReadDirRequest, GitserverService_ReadDirServer) error
+	LsFiles(*LsFilesRequest, GitserverService_LsFilesServer) error
+	Commits(*CommitsRequest, GitserverService_CommitsServer) error
+	DiffSymbols(*DiffSymbolsRequest, GitserverService_DiffSymbolsServer) error
+	ContributorCounts(context.Context, *ContributorCountsRequest) (*ContributorCountsResponse, error)
+	GetBlobs(*GetBlobsRequest, GitserverService_GetBlobsServer) error
 	mustEmbedUnimplementedGitserverServiceServer()
 }
 
@@ -496,6 +730,30 @@ func (UnimplementedGitserverServiceServer) IsPerforceSuperUser(context.Context,
 func (UnimplementedGitserverServiceServer) PerforceGetChangelist(context.Context, *PerforceGetChangelistRequest) (*PerforceGetChangelistResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PerforceGetChangelist not implemented")
 }
+func (UnimplementedGitserverServiceServer) MergeBase(context.Context, *MergeBaseRequest) (*MergeBaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeBase not implemented")
+}
+func (UnimplementedGitserverServiceServer) Blame(*BlameRequest, GitserverService_BlameServer) error {
+	return status.Errorf(codes.Unimplemented, "method Blame not implemented")
+}
+func (UnimplementedGitserverServiceServer) ReadDir(*ReadDirRequest, GitserverService_ReadDirServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReadDir not implemented")
+}
+func (UnimplementedGitserverServiceServer) LsFiles(*LsFilesRequest, GitserverService_LsFilesServer) error {
+	return status.Errorf(codes.Unimplemented, "method LsFiles not implemented")
+}
+func (UnimplementedGitserverServiceServer) Commits(*CommitsRequest, GitserverService_CommitsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Commits not implemented")
+}
+func (UnimplementedGitserverServiceServer) DiffSymbols(*DiffSymbolsRequest, GitserverService_DiffSymbolsServer) error {
+	return status.Errorf(codes.Unimplemented, "method DiffSymbols not implemented")
+}
+func (UnimplementedGitserverServiceServer) ContributorCounts(context.Context, *ContributorCountsRequest) (*ContributorCountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ContributorCounts not implemented")
+}
+func (UnimplementedGitserverServiceServer) GetBlobs(*GetBlobsRequest, GitserverService_GetBlobsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetBlobs not implemented")
+}
 func (UnimplementedGitserverServiceServer) mustEmbedUnimplementedGitserverServiceServer() {}
 
 // UnsafeGitserverServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -925,6 +1183,168 @@ func _GitserverService_PerforceGetChangelist_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GitserverService_MergeBase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeBaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GitserverServiceServer).MergeBase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GitserverService_MergeBase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GitserverServiceServer).MergeBase(ctx, req.(*MergeBaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GitserverService_Blame_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlameRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitserverServiceServer).Blame(m, &gitserverServiceBlameServer{stream})
+}
+
+type GitserverService_BlameServer interface {
+	Send(*BlameResponse) error
+	grpc.ServerStream
+}
+
+type gitserverServiceBlameServer struct {
+	grpc.ServerStream
+}
+
+func (x *gitserverServiceBlameServer) Send(m *BlameResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GitserverService_ReadDir_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadDirRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitserverServiceServer).ReadDir(m, &gitserverServiceReadDirServer{stream})
+}
+
+type GitserverService_ReadDirServer interface {
+	Send(*ReadDirResponse) error
+	grpc.ServerStream
+}
+
+type gitserverServiceReadDirServer struct {
+	grpc.ServerStream
+}
+
+func (x *gitserverServiceReadDirServer) Send(m *ReadDirResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GitserverService_LsFiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LsFilesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitserverServiceServer).LsFiles(m, &gitserverServiceLsFilesServer{stream})
+}
+
+type GitserverService_LsFilesServer interface {
+	Send(*LsFilesResponse) error
+	grpc.ServerStream
+}
+
+type gitserverServiceLsFilesServer struct {
+	grpc.ServerStream
+}
+
+func (x *gitserverServiceLsFilesServer) Send(m *LsFilesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GitserverService_Commits_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CommitsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitserverServiceServer).Commits(m, &gitserverServiceCommitsServer{stream})
+}
+
+type GitserverService_CommitsServer interface {
+	Send(*CommitsResponse) error
+	grpc.ServerStream
+}
+
+type gitserverServiceCommitsServer struct {
+	grpc.ServerStream
+}
+
+func (x *gitserverServiceCommitsServer) Send(m *CommitsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GitserverService_DiffSymbols_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DiffSymbolsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitserverServiceServer).DiffSymbols(m, &gitserverServiceDiffSymbolsServer{stream})
+}
+
+type GitserverService_DiffSymbolsServer interface {
+	Send(*DiffSymbolsResponse) error
+	grpc.ServerStream
+}
+
+type gitserverServiceDiffSymbolsServer struct {
+	grpc.ServerStream
+}
+
+func (x *gitserverServiceDiffSymbolsServer) Send(m *DiffSymbolsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _GitserverService_ContributorCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContributorCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GitserverServiceServer).ContributorCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GitserverService_ContributorCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GitserverServiceServer).ContributorCounts(ctx, req.(*ContributorCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GitserverService_GetBlobs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetBlobsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GitserverServiceServer).GetBlobs(m, &gitserverServiceGetBlobsServer{stream})
+}
+
+type GitserverService_GetBlobsServer interface {
+	Send(*GetBlobsResponse) error
+	grpc.ServerStream
+}
+
+type gitserverServiceGetBlobsServer struct {
+	grpc.ServerStream
+}
+
+func (x *gitserverServiceGetBlobsServer) Send(m *GetBlobsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // GitserverService_ServiceDesc is the grpc.ServiceDesc for GitserverService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1000,6 +1420,14 @@ var GitserverService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PerforceGetChangelist",
 			Handler:    _GitserverService_PerforceGetChangelist_Handler,
 		},
+		{
+			MethodName: "MergeBase",
+			Handler:    _GitserverService_MergeBase_Handler,
+		},
+		{
+			MethodName: "ContributorCounts",
+			Handler:    _GitserverService_ContributorCounts_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1027,6 +1455,36 @@ var GitserverService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _GitserverService_P4Exec_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Blame",
+			Handler:       _GitserverService_Blame_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReadDir",
+			Handler:       _GitserverService_ReadDir_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "LsFiles",
+			Handler:       _GitserverService_LsFiles_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Commits",
+			Handler:       _GitserverService_Commits_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "DiffSymbols",
+			Handler:       _GitserverService_DiffSymbols_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetBlobs",
+			Handler:       _GitserverService_GetBlobs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "gitserver.proto",
 }
@@ -0,0 +1,206 @@
+package repos
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitea"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GiteaSource yields repositories from a single Gitea or Forgejo connection
+// configured in Sourcegraph via the external services configuration.
+type GiteaSource struct {
+	svc       *types.ExternalService
+	config    *schema.GiteaConnection
+	cli       gitea.Client
+	excluder  repoExcluder
+	serviceID string
+	perPage   int
+}
+
+var (
+	_ Source     = &GiteaSource{}
+	_ UserSource = &GiteaSource{}
+)
+
+// NewGiteaSource returns a new GiteaSource from the given external service.
+func NewGiteaSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*GiteaSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.GiteaConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	if cf == nil {
+		cf = httpcli.ExternalClientFactory
+	}
+
+	httpCli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	var ex repoExcluder
+	for _, r := range c.Exclude {
+		ex.AddRule().
+			Exact(r.Name).
+			Pattern(r.Pattern)
+		// A rule only holds a single exact value, so the ID gets its own.
+		if r.Id != 0 {
+			ex.AddRule().Exact(strconv.Itoa(r.Id))
+		}
+	}
+	if err := ex.RuleErrors(); err != nil {
+		return nil, err
+	}
+
+	cli, err := gitea.NewClient(svc.URN(), u, c.Token, httpCli)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GiteaSource{
+		svc:       svc,
+		config:    &c,
+		cli:       cli,
+		excluder:  ex,
+		serviceID: extsvc.NormalizeBaseURL(cli.GetURL()).String(),
+	}, nil
+}
+
+// CheckConnection checks that the configured token can be used to fetch the
+// authenticated user.
+func (s *GiteaSource) CheckConnection(ctx context.Context) error {
+	if _, err := s.cli.GetAuthenticatedUser(ctx); err != nil {
+		return errors.Wrap(err, "connection check failed. could not fetch authenticated user")
+	}
+	return nil
+}
+
+// ValidateAuthenticator validates the currently set authenticator is usable.
+func (s *GiteaSource) ValidateAuthenticator(ctx context.Context) error {
+	_, err := s.cli.GetAuthenticatedUser(ctx)
+	return err
+}
+
+// ListRepos returns all Gitea repositories configured with this GiteaSource's
+// config. Without orgs or repos configured, all repositories the token can
+// access are returned.
+func (s *GiteaSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	seen := make(map[int64]bool)
+	emit := func(r *gitea.Repository) {
+		if seen[r.ID] || s.excludes(r) {
+			return
+		}
+		seen[r.ID] = true
+		results <- SourceResult{Source: s, Repo: s.makeRepo(r)}
+	}
+
+	list := func(args gitea.ListReposArgs) {
+		it := s.cli.ListRepos(ctx, args)
+		for it.Next() {
+			emit(it.Current())
+		}
+		if err := it.Err(); err != nil {
+			if args.Org != "" {
+				err = errors.Wrapf(err, "gitea.orgs: item=%q", args.Org)
+			}
+			results <- SourceResult{Source: s, Err: err}
+		}
+	}
+
+	if len(s.config.Orgs) == 0 && len(s.config.Repos) == 0 {
+		list(gitea.ListReposArgs{PerPage: s.perPage})
+		return
+	}
+
+	for _, org := range s.config.Orgs {
+		list(gitea.ListReposArgs{Org: org, PerPage: s.perPage})
+	}
+
+	for _, nameWithOwner := range s.config.Repos {
+		owner, name, ok := strings.Cut(nameWithOwner, "/")
+		if !ok {
+			results <- SourceResult{Source: s, Err: errors.Errorf("gitea.repos: invalid repository %q, expected \"owner/name\"", nameWithOwner)}
+			continue
+		}
+
+		r, err := s.cli.GetRepo(ctx, owner, name)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gitea.repos: item=%q", nameWithOwner)}
+			continue
+		}
+		emit(r)
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s *GiteaSource) ExternalServices() types.ExternalServices {
+	return types.ExternalServices{s.svc}
+}
+
+func (s *GiteaSource) excludes(r *gitea.Repository) bool {
+	return s.excluder.ShouldExclude(r.FullName) || s.excluder.ShouldExclude(strconv.FormatInt(r.ID, 10))
+}
+
+func (s *GiteaSource) makeRepo(r *gitea.Repository) *types.Repo {
+	urn := s.svc.URN()
+	host := s.cli.GetURL().Host
+
+	return &types.Repo{
+		Name: reposource.GiteaRepoName(s.config.RepositoryPathPattern, host, r.FullName),
+		URI:  string(reposource.GiteaRepoName("", host, r.FullName)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          strconv.FormatInt(r.ID, 10),
+			ServiceType: extsvc.TypeGitea,
+			ServiceID:   s.serviceID,
+		},
+		Description: r.Description,
+		Fork:        r.Fork,
+		Archived:    r.Archived,
+		Stars:       r.StarsCount,
+		// Internal repositories are only visible to signed in users of the
+		// instance, so we treat them as private.
+		Private: r.Private || r.Internal,
+		Sources: map[string]*types.SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: r.CloneURL,
+			},
+		},
+		Metadata: r,
+	}
+}
+
+// WithAuthenticator returns a copy of the original Source configured to use the
+// given authenticator, provided that authenticator type is supported by the
+// code host.
+func (s *GiteaSource) WithAuthenticator(a auth.Authenticator) (Source, error) {
+	sc := *s
+	cli, err := sc.cli.WithAuthenticator(a)
+	if err != nil {
+		return nil, err
+	}
+	sc.cli = cli
+
+	return &sc, nil
+}
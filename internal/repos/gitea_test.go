@@ -0,0 +1,80 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+	"github.com/sourcegraph/sourcegraph/internal/types/typestest"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGiteaSource_ListRepos(t *testing.T) {
+	ratelimit.SetupForTest(t)
+
+	t.Run("all", func(t *testing.T) {
+		cf, save := NewClientFactory(t, t.Name())
+		defer save(t)
+
+		svc := typestest.MakeExternalService(t, extsvc.VariantGitea, &schema.GiteaConnection{
+			Url:   "https://gitea.sgdev.org",
+			Token: os.Getenv("GITEA_TOKEN"),
+			Exclude: []*schema.ExcludedGiteaRepo{
+				{Id: 106},
+				{Pattern: "^alice/dot"},
+			},
+		})
+
+		ctx := context.Background()
+		src, err := NewGiteaSource(ctx, svc, cf)
+		require.NoError(t, err)
+
+		src.perPage = 2
+
+		repos, err := ListAll(ctx, src)
+		require.NoError(t, err)
+
+		testutil.AssertGolden(t, "testdata/sources/GITEA/"+t.Name(), Update(t.Name()), repos)
+	})
+
+	t.Run("orgs and repos", func(t *testing.T) {
+		cf, save := NewClientFactory(t, t.Name())
+		defer save(t)
+
+		svc := typestest.MakeExternalService(t, extsvc.VariantGitea, &schema.GiteaConnection{
+			Url:                   "https://gitea.sgdev.org",
+			Token:                 os.Getenv("GITEA_TOKEN"),
+			Orgs:                  []string{"sourcegraph"},
+			Repos:                 []string{"alice/dotfiles", "sourcegraph/go-diff", "alice/missing"},
+			RepositoryPathPattern: "gitea/{nameWithOwner}",
+			Exclude: []*schema.ExcludedGiteaRepo{
+				{Name: "sourcegraph/legacy"},
+			},
+		})
+
+		ctx := context.Background()
+		src, err := NewGiteaSource(ctx, svc, cf)
+		require.NoError(t, err)
+
+		repos, err := ListAll(ctx, src)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `gitea.repos: item="alice/missing"`)
+
+		repoNames := make([]string, 0, len(repos))
+		for _, repo := range repos {
+			repoNames = append(repoNames, string(repo.Name))
+		}
+		assert.Equal(t, []string{
+			"gitea/sourcegraph/go-diff",
+			"gitea/sourcegraph/secret-notes",
+			"gitea/sourcegraph/handbook",
+			"gitea/alice/dotfiles",
+		}, repoNames)
+	})
+}
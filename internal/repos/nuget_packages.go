@@ -0,0 +1,57 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/nuget"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewNuGetPackagesSource returns a new nugetPackagesSource from the given external service.
+func NewNuGetPackagesSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*PackagesSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.NuGetPackagesConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+
+	client, err := nuget.NewClient(svc.URN(), c.Repository, cf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackagesSource{
+		svc:        svc,
+		configDeps: c.Dependencies,
+		scheme:     dependencies.NuGetPackagesScheme,
+		src:        &nugetPackagesSource{client},
+	}, nil
+}
+
+type nugetPackagesSource struct {
+	client *nuget.Client
+}
+
+var _ packagesSource = &nugetPackagesSource{}
+
+func (nugetPackagesSource) ParseVersionedPackageFromConfiguration(dep string) (reposource.VersionedPackage, error) {
+	return reposource.ParseNuGetVersionedPackage(dep), nil
+}
+
+func (nugetPackagesSource) ParsePackageFromName(name reposource.PackageName) (reposource.Package, error) {
+	return reposource.ParseNuGetPackageFromName(name), nil
+}
+
+func (nugetPackagesSource) ParsePackageFromRepoName(repoName api.RepoName) (reposource.Package, error) {
+	return reposource.ParseNuGetPackageFromRepoName(repoName)
+}
@@ -0,0 +1,57 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/packagist"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// NewPHPPackagesSource returns a new phpPackagesSource from the given external service.
+func NewPHPPackagesSource(ctx context.Context, svc *types.ExternalService, cf *httpcli.Factory) (*PackagesSource, error) {
+	rawConfig, err := svc.Config.Decrypt(ctx)
+	if err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	var c schema.PHPPackagesConnection
+	if err := jsonc.Unmarshal(rawConfig, &c); err != nil {
+		return nil, errors.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+
+	client, err := packagist.NewClient(svc.URN(), c.Repository, cf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PackagesSource{
+		svc:        svc,
+		configDeps: c.Dependencies,
+		scheme:     dependencies.PHPPackagesScheme,
+		src:        &phpPackagesSource{client},
+	}, nil
+}
+
+type phpPackagesSource struct {
+	client *packagist.Client
+}
+
+var _ packagesSource = &phpPackagesSource{}
+
+func (phpPackagesSource) ParseVersionedPackageFromConfiguration(dep string) (reposource.VersionedPackage, error) {
+	return reposource.ParsePHPVersionedPackage(dep)
+}
+
+func (phpPackagesSource) ParsePackageFromName(name reposource.PackageName) (reposource.Package, error) {
+	return reposource.ParsePHPPackageFromName(name)
+}
+
+func (phpPackagesSource) ParsePackageFromRepoName(repoName api.RepoName) (reposource.Package, error) {
+	return reposource.ParsePHPPackageFromRepoName(repoName)
+}
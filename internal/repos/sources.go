@@ -51,6 +51,8 @@ func NewSource(ctx context.Context, logger log.Logger, db database.DB, svc *type
 		return NewAzureDevOpsSource(ctx, logger.Scoped("AzureDevOpsSource"), svc, cf)
 	case extsvc.KindGerrit:
 		return NewGerritSource(ctx, svc, cf)
+	case extsvc.KindGitea:
+		return NewGiteaSource(ctx, svc, cf)
 	case extsvc.KindBitbucketServer:
 		return NewBitbucketServerSource(ctx, logger.Scoped("BitbucketServerSource"), svc, cf)
 	case extsvc.KindBitbucketCloud:
@@ -78,6 +80,12 @@ func NewSource(ctx context.Context, logger log.Logger, db database.DB, svc *type
 		return NewRustPackagesSource(ctx, svc, cf)
 	case extsvc.KindRubyPackages:
 		return NewRubyPackagesSource(ctx, svc, cf)
+	case extsvc.KindNuGetPackages:
+		return NewNuGetPackagesSource(ctx, svc, cf)
+	case extsvc.KindPHPPackages:
+		return NewPHPPackagesSource(ctx, svc, cf)
+	case extsvc.KindHexPackages:
+		return NewHexPackagesSource(ctx, svc, cf)
 	case extsvc.KindOther:
 		return NewOtherSource(ctx, svc, cf, logger.Scoped("OtherSource"))
 	default:
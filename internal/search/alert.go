@@ -91,6 +91,14 @@ func AlertForQuery(queryString string, err error) *Alert {
 			Description:    `I'm having trouble understanding that query. Putting parentheses around the search pattern may help.`,
 		}
 	}
+	var macroErr *query.MacroError
+	if errors.As(err, &macroErr) {
+		return &Alert{
+			PrometheusType: "invalid_search_macro",
+			Title:          "Unable To Expand Search Macro",
+			Description:    fmt.Sprintf("%s. Check the definition of @%s in the search.macros setting.", capFirst(macroErr.Error()), macroErr.Name),
+		}
+	}
 	return &Alert{
 		PrometheusType: "generic_invalid_query",
 		Title:          "Unable To Process Query",
@@ -166,6 +174,17 @@ func AlertForInvalidRevision(revision string) *Alert {
 	}
 }
 
+// AlertForTruncatedRevisionCompare returns an alert for a rev:base...head
+// search which didn't get all matches at one of the revisions.
+func AlertForTruncatedRevisionCompare() *Alert {
+	return &Alert{
+		PrometheusType: "revision_compare_truncated",
+		Title:          "Comparison incomplete",
+		Description:    "The search didn't return all matches at one of the compared revisions, so the changes between them can't be determined. Try narrowing your search with repo: or file: filters, or increase the timeout with timeout:.",
+		Priority:       2,
+	}
+}
+
 func AlertForUnownedResult() *Alert {
 	return &Alert{
 		Kind:        "unowned-results",
@@ -217,3 +217,10 @@ func TestQuoteSuggestions(t *testing.T) {
 		}
 	})
 }
+
+func TestAlertForQuery_Macro(t *testing.T) {
+	_, err := query.Pipeline(query.InitWithMacros("@loop", query.SearchTypeStandard, map[string]string{"loop": "foo @loop"}))
+	alert := AlertForQuery("@loop", err)
+	require.Equal(t, "invalid_search_macro", alert.PrometheusType)
+	require.Equal(t, "Cannot expand search macro @loop: the macro refers to itself. Check the definition of @loop in the search.macros setting.", alert.Description)
+}
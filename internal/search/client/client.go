@@ -120,7 +120,7 @@ func (s *searchClient) Plan(
 
 	var plan query.Plan
 	plan, err = query.Pipeline(
-		query.Init(searchQuery, searchType),
+		query.InitWithMacros(searchQuery, searchType, settings.SearchMacros),
 		query.With(searchContextsQueryEnabled, substituteContextsStep),
 	)
 	if err != nil {
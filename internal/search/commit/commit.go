@@ -33,6 +33,15 @@ type SearchJob struct {
 	IncludeModifiedFiles bool
 	Concurrency          int
 
+	// ContainsRefGlobs is true if the query searches revisions like
+	// rev:*refs/heads/*. Each match then lists the searched refs which
+	// contain it in SourceRefs.
+	ContainsRefGlobs bool
+
+	// Repos, if set, are the repository revisions to search instead of the
+	// ones RepoOpts resolves to.
+	Repos []*search.RepositoryRevisions
+
 	// CodeMonitorSearchWrapper, if set, will wrap the commit search with extra logic specific to code monitors.
 	CodeMonitorSearchWrapper CodeMonitorHook `json:"-"`
 }
@@ -60,18 +69,23 @@ func (j *SearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream
 		}
 
 		args := &protocol.SearchRequest{
-			Repo:                 repoRev.Repo.Name,
-			Revisions:            searchRevsToGitserverRevs(repoRev.Revs),
-			Query:                j.Query,
-			IncludeDiff:          j.Diff,
-			Limit:                j.Limit,
-			IncludeModifiedFiles: j.IncludeModifiedFiles,
+			Repo:                  repoRev.Repo.Name,
+			Revisions:             searchRevsToGitserverRevs(repoRev.Revs),
+			Query:                 j.Query,
+			IncludeDiff:           j.Diff,
+			Limit:                 j.Limit,
+			IncludeModifiedFiles:  j.IncludeModifiedFiles,
+			IncludeContainingRefs: j.ContainsRefGlobs,
 		}
 
 		onMatches := func(in []protocol.CommitMatch) {
 			res := make([]result.Match, 0, len(in))
 			for _, protocolMatch := range in {
-				res = append(res, protocolMatchToCommitMatch(repoRev.Repo, j.Diff, protocolMatch))
+				match := protocolMatchToCommitMatch(repoRev.Repo, j.Diff, protocolMatch)
+				if j.ContainsRefGlobs {
+					match.SourceRefs = protocolMatch.SourceRefs
+				}
+				res = append(res, match)
 			}
 			stream.Send(streaming.SearchEvent{
 				Results: res,
@@ -96,11 +110,21 @@ func (j *SearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream
 		return doSearch(args)
 	}
 
+	p := pool.New().WithContext(ctx).WithMaxGoroutines(4).WithFirstError()
+
+	if j.Repos != nil {
+		for _, repoRev := range j.Repos {
+			repoRev := repoRev
+			p.Go(func(ctx context.Context) error {
+				return searchRepoRev(ctx, repoRev)
+			})
+		}
+		return nil, p.Wait()
+	}
+
 	repos := searchrepos.NewResolver(clients.Logger, clients.DB, clients.Gitserver, clients.SearcherURLs, clients.Zoekt)
 	it := repos.Iterator(ctx, j.RepoOpts)
 
-	p := pool.New().WithContext(ctx).WithMaxGoroutines(4).WithFirstError()
-
 	for it.Next() {
 		page := it.Current()
 		page.MaybeSendStats(stream)
@@ -131,6 +155,7 @@ func (j *SearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	case job.VerbosityMax:
 		res = append(res,
 			attribute.Bool("includeModifiedFiles", j.IncludeModifiedFiles),
+			attribute.Bool("containsRefGlobs", j.ContainsRefGlobs),
 		)
 		fallthrough
 	case job.VerbosityBasic:
@@ -140,6 +165,9 @@ func (j *SearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 			attribute.Int("limit", j.Limit),
 		)
 		res = append(res, trace.Scoped("repoOpts", j.RepoOpts.Attributes()...)...)
+		if j.Repos != nil {
+			res = append(res, attribute.Int("numRepos", len(j.Repos)))
+		}
 	}
 	return res
 }
@@ -293,9 +321,9 @@ func patternNodesToPredicates(nodes []query.Node, caseSensitive, diff bool) []gi
 }
 
 func patternAtomToPredicate(pattern query.Pattern, caseSensitive, diff bool) gitprotocol.Node {
-	patString := pattern.Value
-	if pattern.Annotation.Labels.IsSet(query.Literal) {
-		patString = regexp.QuoteMeta(pattern.Value)
+	patString := patternToRegexp(pattern)
+	if near := pattern.Annotation.Near; near != nil {
+		patString = query.NearRegexp(patString, patternToRegexp(near.Pattern), near.Distance)
 	}
 
 	var newPred gitprotocol.Node
@@ -311,6 +339,13 @@ func patternAtomToPredicate(pattern query.Pattern, caseSensitive, diff bool) git
 	return newPred
 }
 
+func patternToRegexp(pattern query.Pattern) string {
+	if pattern.Annotation.Labels.IsSet(query.Literal) {
+		return regexp.QuoteMeta(pattern.Value)
+	}
+	return pattern.Value
+}
+
 func queryParameterToPredicate(parameter query.Parameter, caseSensitive, diff bool) gitprotocol.Node {
 	var newPred gitprotocol.Node
 	switch parameter.Field {
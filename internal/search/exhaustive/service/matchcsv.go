@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
@@ -32,7 +34,21 @@ func (w *matchCSVWriter) Write(match result.Match) error {
 
 	switch m := match.(type) {
 	case *result.FileMatch:
+		if len(m.Symbols) > 0 {
+			return w.writeSymbolMatches(m)
+		}
 		return w.writeFileMatch(m)
+	case *result.CommitMatch:
+		if m.DiffPreview != nil {
+			return w.writeDiffMatch(m)
+		}
+		return w.writeCommitMatch(m)
+	case *result.RepoMatch:
+		return w.writeRepoMatch(m)
+	case *result.OwnerMatch:
+		return w.writeOwnerMatch(m)
+	case *result.DirectoryMatch:
+		return w.writeDirectoryMatch(m)
 	default:
 		return errors.Errorf("match type %T not yet supported", match)
 	}
@@ -46,8 +62,8 @@ func (w *matchCSVWriter) writeFileMatch(fm *result.FileMatch) error {
 	// needing to quote them. This makes processing of the output more
 	// pleasant in tools like shell pipelines, sqlite's csv mode, etc.
 	//
-	// Match type :: Excluded since we only have one type for now. When we add
-	// other types we may want to include them in different ways.
+	// Match type :: Excluded since every match type has its own schema. A
+	// search job only produces a single type of match, see writeHeader.
 	//
 	// Repository export URL :: We don't like it. It is verbose and is just
 	// repo + rev fields. Unsure why someone would want to click on it.
@@ -109,6 +125,326 @@ func (w *matchCSVWriter) writeFileMatch(fm *result.FileMatch) error {
 	)
 }
 
+func (w *matchCSVWriter) writeSymbolMatches(fm *result.FileMatch) error {
+	// We write a row per symbol rather than a row per file like the webapp
+	// does. This avoids encoding a list of symbols in a single column.
+	if ok, err := w.writeHeader("symbol"); err != nil {
+		return err
+	} else if ok {
+		if err := w.w.WriteHeader(
+			"repository",
+			"revision",
+			"file_path",
+			"symbol_name",
+			"symbol_kind",
+			"symbol_container",
+			"symbol_url",
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, sm := range fm.Symbols {
+		symbolURL := *w.host
+		symbolURL.Path = fm.File.URLAtCommit().Path
+		symbolURL.RawQuery = sm.URL().RawQuery
+
+		if err := w.w.WriteRow(
+			// repository
+			string(fm.Repo.Name),
+
+			// revision
+			string(fm.CommitID),
+
+			// file_path
+			fm.Path,
+
+			// symbol_name
+			sm.Symbol.Name,
+
+			// symbol_kind
+			symbolKind(sm.Symbol),
+
+			// symbol_container
+			sm.Symbol.Parent,
+
+			// symbol_url
+			symbolURL.String(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// symbolKind returns the normalized kind of s as used by select:symbol.kind.
+// It falls back to the kind reported by the symbol parser.
+func symbolKind(s result.Symbol) string {
+	if kind, ok := result.ToSelectKind[strings.ToLower(s.Kind)]; ok {
+		return kind
+	}
+	return strings.ToLower(s.Kind)
+}
+
+func (w *matchCSVWriter) writeCommitMatch(cm *result.CommitMatch) error {
+	if ok, err := w.writeHeader("commit"); err != nil {
+		return err
+	} else if ok {
+		if err := w.w.WriteHeader(
+			"repository",
+			"commit",
+			"author_name",
+			"author_email",
+			"author_date",
+			"subject",
+			"match_count",
+			"commit_url",
+		); err != nil {
+			return err
+		}
+	}
+
+	matchCount := 0
+	if cm.MessagePreview != nil {
+		matchCount = len(cm.MessagePreview.MatchedRanges)
+	}
+
+	return w.w.WriteRow(
+		// repository
+		string(cm.Repo.Name),
+
+		// commit
+		string(cm.Commit.ID),
+
+		// author_name
+		cm.Commit.Author.Name,
+
+		// author_email
+		cm.Commit.Author.Email,
+
+		// author_date
+		cm.Commit.Author.Date.UTC().Format(time.RFC3339),
+
+		// subject
+		cm.Commit.Message.Subject(),
+
+		// match_count
+		strconv.Itoa(matchCount),
+
+		// commit_url
+		w.commitURL(cm),
+	)
+}
+
+func (w *matchCSVWriter) writeDiffMatch(cm *result.CommitMatch) error {
+	// We write a row per modified file in the diff. The diff itself is not
+	// included since it is hard to consume from a CSV. Instead we include the
+	// number of lines added and removed in the matching hunks.
+	if ok, err := w.writeHeader("diff"); err != nil {
+		return err
+	} else if ok {
+		if err := w.w.WriteHeader(
+			"repository",
+			"commit",
+			"author_name",
+			"author_email",
+			"author_date",
+			"subject",
+			"old_file_path",
+			"new_file_path",
+			"lines_added",
+			"lines_removed",
+			"commit_url",
+		); err != nil {
+			return err
+		}
+	}
+
+	commitURL := w.commitURL(cm)
+	for _, fd := range cm.Diff {
+		added, removed := 0, 0
+		for _, hunk := range fd.Hunks {
+			for _, line := range hunk.Lines {
+				switch {
+				case strings.HasPrefix(line, "+"):
+					added++
+				case strings.HasPrefix(line, "-"):
+					removed++
+				}
+			}
+		}
+
+		if err := w.w.WriteRow(
+			// repository
+			string(cm.Repo.Name),
+
+			// commit
+			string(cm.Commit.ID),
+
+			// author_name
+			cm.Commit.Author.Name,
+
+			// author_email
+			cm.Commit.Author.Email,
+
+			// author_date
+			cm.Commit.Author.Date.UTC().Format(time.RFC3339),
+
+			// subject
+			cm.Commit.Message.Subject(),
+
+			// old_file_path
+			fd.OrigName,
+
+			// new_file_path
+			fd.NewName,
+
+			// lines_added
+			strconv.Itoa(added),
+
+			// lines_removed
+			strconv.Itoa(removed),
+
+			// commit_url
+			commitURL,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *matchCSVWriter) commitURL(cm *result.CommitMatch) string {
+	u := *w.host
+	u.Path = cm.URL().Path
+	return u.String()
+}
+
+func (w *matchCSVWriter) writeRepoMatch(rm *result.RepoMatch) error {
+	if ok, err := w.writeHeader("repo"); err != nil {
+		return err
+	} else if ok {
+		if err := w.w.WriteHeader(
+			"repository",
+			"revision",
+			"repository_url",
+		); err != nil {
+			return err
+		}
+	}
+
+	repoURL := *w.host
+	repoURL.Path = rm.URL().Path
+
+	return w.w.WriteRow(
+		// repository
+		string(rm.Name),
+
+		// revision
+		rm.Rev,
+
+		// repository_url
+		repoURL.String(),
+	)
+}
+
+func (w *matchCSVWriter) writeOwnerMatch(om *result.OwnerMatch) error {
+	// Owners are derived from the files of a repository, so the same owner
+	// can appear once per repository revision searched.
+	if ok, err := w.writeHeader("owner"); err != nil {
+		return err
+	} else if ok {
+		if err := w.w.WriteHeader(
+			"repository",
+			"revision",
+			"owner_type",
+			"handle",
+			"email",
+			"name",
+		); err != nil {
+			return err
+		}
+	}
+
+	var handle, email, name string
+	switch o := om.ResolvedOwner.(type) {
+	case *result.OwnerPerson:
+		handle, email = o.Handle, o.Email
+		if o.User != nil {
+			name = o.User.Username
+		}
+	case *result.OwnerTeam:
+		handle, email = o.Handle, o.Email
+		if o.Team != nil {
+			name = o.Team.Name
+		}
+	}
+
+	return w.w.WriteRow(
+		// repository
+		string(om.Repo.Name),
+
+		// revision
+		string(om.CommitID),
+
+		// owner_type
+		om.ResolvedOwner.Type(),
+
+		// handle
+		handle,
+
+		// email
+		email,
+
+		// name
+		name,
+	)
+}
+
+func (w *matchCSVWriter) writeDirectoryMatch(dm *result.DirectoryMatch) error {
+	// The counts cover all files in the directory and its subdirectories,
+	// see select:file.rollup.
+	if ok, err := w.writeHeader("directory"); err != nil {
+		return err
+	} else if ok {
+		if err := w.w.WriteHeader(
+			"repository",
+			"revision",
+			"directory",
+			"file_count",
+			"match_count",
+			"directory_url",
+		); err != nil {
+			return err
+		}
+	}
+
+	directoryURL := *w.host
+	directoryURL.Path = dm.URLAtCommit().Path
+
+	return w.w.WriteRow(
+		// repository
+		string(dm.Repo.Name),
+
+		// revision
+		string(dm.CommitID),
+
+		// directory
+		dm.Path,
+
+		// file_count
+		strconv.Itoa(dm.FileCount),
+
+		// match_count
+		strconv.Itoa(dm.MatchCount),
+
+		// directory_url
+		directoryURL.String(),
+	)
+}
+
 // firstMatchRawQuery returns the raw query parameter for the location of the
 // first match. This is what is appended to the sourcegraph URL when clicking
 // on a search result. eg if the match is on line 11 it is "L11". If it is
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestMatchCSVWriter(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "repo"}
+	file := result.File{
+		Repo:     repo,
+		CommitID: "abc",
+		Path:     "a/b.go",
+	}
+	commit := gitdomain.Commit{
+		ID: "abc",
+		Author: gitdomain.Signature{
+			Name:  "Alice",
+			Email: "alice@example.com",
+			Date:  time.Date(2023, 11, 27, 10, 0, 0, 0, time.UTC),
+		},
+		Message: "fix bug\n\nlonger description",
+	}
+
+	do := func(name string, matches []result.Match, want autogold.Value) {
+		t.Run(name, func(t *testing.T) {
+			host, err := url.Parse("https://sourcegraph.test")
+			require.NoError(t, err)
+
+			var w csvBuffer
+			matchWriter := &matchCSVWriter{w: &w, host: host}
+			for _, match := range matches {
+				require.NoError(t, matchWriter.Write(match))
+			}
+			want.Equal(t, w.buf.String())
+		})
+	}
+
+	do("content", []result.Match{&result.FileMatch{
+		File: file,
+		ChunkMatches: result.ChunkMatches{{
+			Ranges: result.Ranges{{
+				Start: result.Location{Line: 2},
+				End:   result.Location{Line: 2},
+			}},
+		}},
+	}}, autogold.Expect(`repository,revision,file_path,match_count,first_match_url
+repo,abc,a/b.go,1,https://sourcegraph.test/repo@abc/-/blob/a/b.go?L3
+`))
+
+	do("symbol", []result.Match{&result.FileMatch{
+		File: file,
+		Symbols: []*result.SymbolMatch{{
+			File:   &file,
+			Symbol: result.Symbol{Name: "Store", Kind: "interface", Parent: "store", Line: 10},
+		}, {
+			File:   &file,
+			Symbol: result.Symbol{Name: "New", Kind: "func", Line: 20},
+		}},
+	}}, autogold.Expect(`repository,revision,file_path,symbol_name,symbol_kind,symbol_container,symbol_url
+repo,abc,a/b.go,Store,interface,store,https://sourcegraph.test/repo@abc/-/blob/a/b.go?L10:1-10:6
+repo,abc,a/b.go,New,function,,https://sourcegraph.test/repo@abc/-/blob/a/b.go?L20:1-20:4
+`))
+
+	do("commit", []result.Match{&result.CommitMatch{
+		Repo:   repo,
+		Commit: commit,
+		MessagePreview: &result.MatchedString{
+			Content:       string(commit.Message),
+			MatchedRanges: result.Ranges{{}, {}},
+		},
+	}}, autogold.Expect(`repository,commit,author_name,author_email,author_date,subject,match_count,commit_url
+repo,abc,Alice,alice@example.com,2023-11-27T10:00:00Z,fix bug,2,https://sourcegraph.test/repo/-/commit/abc
+`))
+
+	do("diff", []result.Match{&result.CommitMatch{
+		Repo:        repo,
+		Commit:      commit,
+		DiffPreview: &result.MatchedString{},
+		Diff: []result.DiffFile{{
+			OrigName: "a/b.go",
+			NewName:  "a/c.go",
+			Hunks: []result.Hunk{{
+				Lines: []string{" ctx", "-old", "+new", "+newer"},
+			}},
+		}},
+	}}, autogold.Expect(`repository,commit,author_name,author_email,author_date,subject,old_file_path,new_file_path,lines_added,lines_removed,commit_url
+repo,abc,Alice,alice@example.com,2023-11-27T10:00:00Z,fix bug,a/b.go,a/c.go,2,1,https://sourcegraph.test/repo/-/commit/abc
+`))
+
+	do("repo", []result.Match{&result.RepoMatch{
+		Name: "repo",
+		ID:   1,
+		Rev:  "main",
+	}}, autogold.Expect(`repository,revision,repository_url
+repo,main,https://sourcegraph.test/repo@main
+`))
+
+	do("directory", []result.Match{&result.DirectoryMatch{
+		Repo:       repo,
+		CommitID:   "abc",
+		Path:       "a/",
+		FileCount:  2,
+		MatchCount: 3,
+	}}, autogold.Expect(`repository,revision,directory,file_count,match_count,directory_url
+repo,abc,a/,2,3,https://sourcegraph.test/repo@abc/-/tree/a
+`))
+
+	do("owner", []result.Match{&result.OwnerMatch{
+		ResolvedOwner: &result.OwnerPerson{Handle: "alice", Email: "alice@example.com"},
+		Repo:          repo,
+		CommitID:      "abc",
+	}, &result.OwnerMatch{
+		ResolvedOwner: &result.OwnerTeam{Handle: "owners", Team: &types.Team{Name: "owners"}},
+		Repo:          repo,
+		CommitID:      "abc",
+	}}, autogold.Expect(`repository,revision,owner_type,handle,email,name
+repo,abc,person,alice,alice@example.com,
+repo,abc,team,owners,,owners
+`))
+}
+
+func TestMatchCSVWriter_MixedTypes(t *testing.T) {
+	host, err := url.Parse("https://sourcegraph.test")
+	require.NoError(t, err)
+
+	matchWriter := &matchCSVWriter{w: &csvBuffer{}, host: host}
+	require.NoError(t, matchWriter.Write(&result.RepoMatch{Name: "repo", ID: 1}))
+	require.Error(t, matchWriter.Write(&result.CommitMatch{Repo: types.MinimalRepo{ID: 1, Name: "repo"}}))
+}
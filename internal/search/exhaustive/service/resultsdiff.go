@@ -0,0 +1,193 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// diffResults compares the results of two runs of a search job and writes a
+// row to w for every match which was added or removed. The rows have the
+// columns of the results with an extra "change" column in front, which is
+// either "added" or "removed". Added rows are written first, in the order of
+// current, followed by the removed rows in the order of previous.
+//
+// Matches are compared on all columns except the revision and the URLs. Those
+// change whenever a repository gets a new commit, even if the match itself
+// didn't change.
+func diffResults(previous, current io.Reader, format types.OutputFormat, w CSVWriter) (types.ResultsDiff, error) {
+	var diff types.ResultsDiff
+
+	type previousRow struct {
+		row   []string
+		count int
+	}
+	previousRows := map[string]*previousRow{}
+	var previousOrder []string
+
+	var header []string
+	checkHeader := func(h []string) error {
+		if header == nil {
+			header = h
+			return w.WriteHeader(append([]string{"change"}, h...)...)
+		}
+		if !slices.Equal(header, h) {
+			return errors.Errorf("can't diff results with different columns: %v != %v", header, h)
+		}
+		return nil
+	}
+
+	err := readResultRows(previous, format, func(h, row []string) error {
+		if err := checkHeader(h); err != nil {
+			return err
+		}
+		key := resultRowKey(h, row)
+		p, ok := previousRows[key]
+		if !ok {
+			p = &previousRow{row: row}
+			previousRows[key] = p
+			previousOrder = append(previousOrder, key)
+		}
+		p.count++
+		return nil
+	})
+	if err != nil {
+		return diff, errors.Wrap(err, "reading previous results")
+	}
+
+	err = readResultRows(current, format, func(h, row []string) error {
+		if err := checkHeader(h); err != nil {
+			return err
+		}
+		if p, ok := previousRows[resultRowKey(h, row)]; ok && p.count > 0 {
+			p.count--
+			return nil
+		}
+		diff.Added++
+		return w.WriteRow(append([]string{"added"}, row...)...)
+	})
+	if err != nil {
+		return diff, errors.Wrap(err, "reading current results")
+	}
+
+	for _, key := range previousOrder {
+		p := previousRows[key]
+		for ; p.count > 0; p.count-- {
+			diff.Removed++
+			if err := w.WriteRow(append([]string{"removed"}, p.row...)...); err != nil {
+				return diff, err
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// resultRowKey returns the key we use to compare a row of results across runs.
+func resultRowKey(header, row []string) string {
+	var b strings.Builder
+	for i, column := range header {
+		if column == "revision" || strings.HasSuffix(column, "_url") {
+			continue
+		}
+		b.WriteString(column)
+		b.WriteByte(0)
+		if i < len(row) {
+			b.WriteString(row[i])
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// readResultRows calls f with every row of the results in r. r contains the
+// concatenated results of a search job in format, as returned by
+// writeSearchJobResults.
+func readResultRows(r io.Reader, format types.OutputFormat, f func(header, row []string) error) error {
+	if format == types.OutputFormatJSONLines {
+		return readJSONLinesRows(r, f)
+	}
+
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := f(header, row); err != nil {
+			return err
+		}
+	}
+}
+
+// readJSONLinesRows reads the objects written by jsonLinesEncoder. The keys of
+// each object are its header.
+func readJSONLinesRows(r io.Reader, f func(header, row []string) error) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			header, row, parseErr := parseJSONLinesRow(line)
+			if parseErr != nil {
+				return parseErr
+			}
+			if fErr := f(header, row); fErr != nil {
+				return fErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// parseJSONLinesRow parses a JSON object with string values while keeping the
+// order of its keys.
+func parseJSONLinesRow(line []byte) (header, row []string, err error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil, errors.Errorf("expected a JSON object, got %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := tok.(string)
+
+		tok, err = dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		value, ok := tok.(string)
+		if !ok {
+			return nil, nil, errors.Errorf("expected a string value for %q, got %v", key, tok)
+		}
+
+		header = append(header, key)
+		row = append(row, value)
+	}
+
+	return header, row, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+)
+
+func TestDiffResults(t *testing.T) {
+	do := func(name string, format types.OutputFormat, previous, current string, wantDiff types.ResultsDiff, want autogold.Value) {
+		t.Run(name, func(t *testing.T) {
+			var w csvBuffer
+			diff, err := diffResults(strings.NewReader(previous), strings.NewReader(current), format, &w)
+			require.NoError(t, err)
+			require.Equal(t, wantDiff, diff)
+			want.Equal(t, w.buf.String())
+		})
+	}
+
+	// b.go moved to a new commit without changing, c.go lost its match and
+	// d.go gained one.
+	do("csv", types.OutputFormatCSV, `repository,revision,file_path,match_count,first_match_url
+repo,abc,a.go,1,https://sourcegraph.test/repo@abc/-/blob/a.go?L1
+repo,abc,b.go,2,https://sourcegraph.test/repo@abc/-/blob/b.go?L1
+repo,abc,c.go,1,https://sourcegraph.test/repo@abc/-/blob/c.go?L1
+`, `repository,revision,file_path,match_count,first_match_url
+repo,def,a.go,1,https://sourcegraph.test/repo@def/-/blob/a.go?L1
+repo,def,b.go,2,https://sourcegraph.test/repo@def/-/blob/b.go?L4
+repo,def,d.go,1,https://sourcegraph.test/repo@def/-/blob/d.go?L1
+`, types.ResultsDiff{Added: 1, Removed: 1}, autogold.Expect(`change,repository,revision,file_path,match_count,first_match_url
+added,repo,def,d.go,1,https://sourcegraph.test/repo@def/-/blob/d.go?L1
+removed,repo,abc,c.go,1,https://sourcegraph.test/repo@abc/-/blob/c.go?L1
+`))
+
+	do("jsonl", types.OutputFormatJSONLines, `{"repository":"repo","revision":"main","repository_url":"https://sourcegraph.test/repo@main"}
+{"repository":"other","revision":"main","repository_url":"https://sourcegraph.test/other@main"}
+`, `{"repository":"repo","revision":"main","repository_url":"https://sourcegraph.test/repo@main"}
+{"repository":"new","revision":"main","repository_url":"https://sourcegraph.test/new@main"}
+`, types.ResultsDiff{Added: 1, Removed: 1}, autogold.Expect(`change,repository,revision,repository_url
+added,new,main,https://sourcegraph.test/new@main
+removed,other,main,https://sourcegraph.test/other@main
+`))
+
+	do("no previous results", types.OutputFormatCSV, "", `repository,revision,repository_url
+repo,main,https://sourcegraph.test/repo@main
+`, types.ResultsDiff{Added: 1}, autogold.Expect(`change,repository,revision,repository_url
+added,repo,main,https://sourcegraph.test/repo@main
+`))
+
+	do("unchanged", types.OutputFormatCSV, `repository,revision,repository_url
+repo,main,https://sourcegraph.test/repo@main
+`, `repository,revision,repository_url
+repo,main,https://sourcegraph.test/repo@main
+`, types.ResultsDiff{}, autogold.Expect(`change,repository,revision,repository_url
+`))
+}
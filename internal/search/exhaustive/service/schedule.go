@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/store"
+	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+// Scheduled search jobs are run again on a cron schedule. Before a run starts
+// we copy the results of the last run to previousResultsKey. Once the run
+// finished, we compare its results to the copy and write the diff as blobs
+// with diffPrefix. Both keys are outside of getPrefix, so they don't show up
+// in the results of the job.
+
+func previousResultsKey(id int64) string {
+	return fmt.Sprintf("previous-%d", id)
+}
+
+func diffPrefix(id int64) string {
+	return fmt.Sprintf("diff-%d", id)
+}
+
+// SetSearchJobSchedule sets the cron schedule of search job id, see
+// types.ParseSchedule. An empty schedule stops scheduled runs of the job.
+func (s *Service) SetSearchJobSchedule(ctx context.Context, id int64, schedule string) (_ *types.ExhaustiveSearchJob, err error) {
+	ctx, _, endObservation := s.operations.setSearchJobSchedule.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+		attribute.String("schedule", schedule),
+	))
+	defer endObservation(1, observation.Args{})
+
+	var nextRunAt time.Time
+	if schedule != "" {
+		expr, err := types.ParseSchedule(schedule)
+		if err != nil {
+			return nil, err
+		}
+		nextRunAt = expr.Next(time.Now())
+	}
+
+	if err := s.store.SetSearchJobSchedule(ctx, id, schedule, nextRunAt); err != nil {
+		return nil, err
+	}
+
+	return s.store.GetExhaustiveSearchJob(ctx, id)
+}
+
+// RunDueScheduledSearchJobs starts a run of every scheduled search job which
+// is due at now. It returns the number of runs started. Jobs which are still
+// running, or whose last run wasn't diffed yet, are started once they are
+// done.
+func (s *Service) RunDueScheduledSearchJobs(ctx context.Context, now time.Time) (started int, err error) {
+	ctx, _, endObservation := s.operations.runDueScheduledSearchJobs.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("started", started)))
+	}()
+
+	jobs, err := s.store.ListDueScheduledSearchJobs(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs error
+	for _, job := range jobs {
+		if err := s.startScheduledRun(ctx, job, now); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "starting scheduled run of search job %d", job.ID))
+			continue
+		}
+		started++
+	}
+
+	return started, errs
+}
+
+func (s *Service) startScheduledRun(ctx context.Context, job *types.ExhaustiveSearchJob, now time.Time) (err error) {
+	expr, err := types.ParseSchedule(job.Schedule)
+	if err != nil {
+		return err
+	}
+
+	// Keep the results of the last run, so we can compare them to the
+	// results of this run.
+	rc, err := s.openResults(ctx, job)
+	if err != nil {
+		return err
+	}
+	_, err = s.uploadStore.Upload(ctx, previousResultsKey(job.ID), rc)
+	rc.Close()
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// Revisions which didn't change since the last run keep their results,
+	// see types.RetryModeChanged.
+	if _, err := tx.RetrySearchJob(ctx, job.ID, types.RetryModeChanged); err != nil {
+		return err
+	}
+
+	return tx.StartScheduledRun(ctx, job.ID, expr.Next(now))
+}
+
+// DiffFinishedScheduledRuns compares the results of every finished scheduled
+// run to the results of the run before. It returns the number of runs
+// diffed.
+func (s *Service) DiffFinishedScheduledRuns(ctx context.Context) (diffed int, err error) {
+	ctx, _, endObservation := s.operations.diffFinishedScheduledRuns.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("diffed", diffed)))
+	}()
+
+	jobs, err := s.store.ListSearchJobsWithPendingDiff(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs error
+	for _, job := range jobs {
+		if err := s.diffScheduledRun(ctx, job); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "diffing scheduled run of search job %d", job.ID))
+			continue
+		}
+		diffed++
+	}
+
+	return diffed, errs
+}
+
+func (s *Service) diffScheduledRun(ctx context.Context, job *types.ExhaustiveSearchJob) error {
+	previous, err := s.uploadStore.Get(ctx, previousResultsKey(job.ID))
+	if err != nil {
+		return err
+	}
+	defer previous.Close()
+
+	current, err := s.openResults(ctx, job)
+	if err != nil {
+		return err
+	}
+	defer current.Close()
+
+	if err := DeleteBlobstoreShards(ctx, s.uploadStore, diffPrefix(job.ID)); err != nil {
+		return err
+	}
+
+	w := NewBlobstoreWriter(ctx, s.uploadStore, diffPrefix(job.ID), job.Format)
+	diff, err := diffResults(previous, current, job.Format, w)
+	if closeErr := w.Close(); closeErr != nil {
+		err = errors.Append(err, closeErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	diff.ComputedAt = time.Now()
+	return s.store.SetResultsDiff(ctx, job.ID, diff)
+}
+
+// openResults returns the concatenated results of job, as they would be
+// downloaded.
+func (s *Service) openResults(ctx context.Context, job *types.ExhaustiveSearchJob) (io.ReadCloser, error) {
+	iter, err := s.uploadStore.List(ctx, getPrefix(job.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	hasHeader := job.Format != types.OutputFormatJSONLines
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := writeSearchJobResults(ctx, iter, s.uploadStore, pw, hasHeader)
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// GetSearchJobDiffWriterTo is like GetSearchJobResultsWriterTo, except that it
+// writes the diff between the latest scheduled run of job id and the run
+// before. See diffResults for the shape of the diff.
+func (s *Service) GetSearchJobDiffWriterTo(parentCtx context.Context, id int64) (_ io.WriterTo, _ types.OutputFormat, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobDiffWriterTo.get.With(parentCtx, &err, opAttrs(
+		attribute.Int64("id", id)))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may copy the blobs.
+	// GetExhaustiveSearchJob checks access.
+	job, err := s.store.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if job.Diff.ComputedAt.IsZero() {
+		return nil, "", errors.Wrapf(store.ErrNoResults, "search job %d has no diff", id)
+	}
+
+	keys, err := listBlobstoreShards(ctx, s.uploadStore, diffPrefix(id))
+	if err != nil {
+		return nil, "", err
+	}
+
+	hasHeader := job.Format != types.OutputFormatJSONLines
+
+	return writerToFunc(func(w io.Writer) (n int64, err error) {
+		ctx, _, endObservation := s.operations.getSearchJobDiffWriterTo.writerTo.With(parentCtx, &err, opAttrs(
+			attribute.Int64("id", id)))
+		defer func() {
+			endObservation(1, opAttrs(attribute.Int64("bytesWritten", n)))
+		}()
+
+		return writeSearchJobResults(ctx, iterator.From(keys), s.uploadStore, w, hasHeader)
+	}), job.Format, nil
+}
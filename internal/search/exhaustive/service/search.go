@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search/exhaustive/types"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -72,6 +75,12 @@ type SearchQuery interface {
 
 	ResolveRepositoryRevSpec(context.Context, types.RepositoryRevSpecs) ([]types.RepositoryRevision, error)
 
+	// ResolveCommit returns the commit the revision currently points to. It
+	// returns an empty CommitID if the revision is HEAD of an empty
+	// repository. This is used to skip searching revisions which haven't
+	// changed since the last time they were searched.
+	ResolveCommit(context.Context, types.RepositoryRevision) (api.CommitID, error)
+
 	Search(context.Context, types.RepositoryRevision, CSVWriter) error
 }
 
@@ -101,12 +110,70 @@ type CSVWriter interface {
 // The caller is expected to call Close() once and only once after the last call
 // to WriteRow.
 func NewBlobstoreCSVWriter(ctx context.Context, store uploadstore.Store, prefix string) *BlobstoreCSVWriter {
+	return newBlobstoreWriter(ctx, store, prefix, func(w io.Writer) rowEncoder {
+		return csv.NewWriter(w)
+	})
+}
+
+// NewBlobstoreJSONLinesWriter is like NewBlobstoreCSVWriter, except that rows
+// are written as JSON Lines. Each row is a JSON object whose keys are the
+// values passed to WriteHeader. The header itself is not written.
+func NewBlobstoreJSONLinesWriter(ctx context.Context, store uploadstore.Store, prefix string) *BlobstoreCSVWriter {
+	return newBlobstoreWriter(ctx, store, prefix, func(w io.Writer) rowEncoder {
+		return &jsonLinesEncoder{w: w}
+	})
+}
+
+// NewBlobstoreWriter returns the blobstore writer for format.
+func NewBlobstoreWriter(ctx context.Context, store uploadstore.Store, prefix string, format types.OutputFormat) *BlobstoreCSVWriter {
+	if format == types.OutputFormatJSONLines {
+		return NewBlobstoreJSONLinesWriter(ctx, store, prefix)
+	}
+	return NewBlobstoreCSVWriter(ctx, store, prefix)
+}
 
+// DeleteBlobstoreShards deletes all blobs written by a BlobstoreCSVWriter
+// with the given prefix. It is used before searching a revision again, so
+// that shards left behind by an earlier run are not mixed into the results.
+func DeleteBlobstoreShards(ctx context.Context, store uploadstore.Store, prefix string) error {
+	keys, err := listBlobstoreShards(ctx, store, prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			return errors.Wrapf(err, "deleting key %q", key)
+		}
+	}
+	return nil
+}
+
+// listBlobstoreShards returns the keys of all blobs written by a
+// BlobstoreCSVWriter with the given prefix.
+func listBlobstoreShards(ctx context.Context, store uploadstore.Store, prefix string) ([]string, error) {
+	iter, err := store.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for iter.Next() {
+		key := iter.Current()
+		// List matches on prefix, so "1-1" would also return the blobs of
+		// "1-10". Only keep {prefix} and {prefix}-{shard}.
+		if key == prefix || strings.HasPrefix(key, prefix+"-") {
+			keys = append(keys, key)
+		}
+	}
+	return keys, iter.Err()
+}
+
+func newBlobstoreWriter(ctx context.Context, store uploadstore.Store, prefix string, newEncoder func(io.Writer) rowEncoder) *BlobstoreCSVWriter {
 	c := &BlobstoreCSVWriter{
 		maxBlobSizeBytes: 100 * 1024 * 1024,
 		ctx:              ctx,
 		prefix:           prefix,
 		store:            store,
+		newEncoder:       newEncoder,
 		// Start with "1" because we increment it before creating a new file. The second
 		// shard will be called {prefix}-2.
 		shard: 1,
@@ -117,6 +184,13 @@ func NewBlobstoreCSVWriter(ctx context.Context, store uploadstore.Store, prefix
 	return c
 }
 
+// rowEncoder encodes rows into a blob. The first row written to a rowEncoder
+// is the header.
+type rowEncoder interface {
+	Write(record []string) error
+	Flush()
+}
+
 type BlobstoreCSVWriter struct {
 	// ctx is the context we use for uploading blobs.
 	ctx context.Context
@@ -125,7 +199,10 @@ type BlobstoreCSVWriter struct {
 
 	prefix string
 
-	w *csv.Writer
+	// newEncoder returns the rowEncoder used for each blob.
+	newEncoder func(io.Writer) rowEncoder
+
+	w rowEncoder
 
 	// local buffer for the current blob.
 	buf bytes.Buffer
@@ -183,16 +260,16 @@ func (c *BlobstoreCSVWriter) WriteRow(s ...string) error {
 	return c.write(s)
 }
 
-// startNewFile creates a new blob and sets up the CSV writer to write to it.
+// startNewFile creates a new blob and sets up the row encoder to write to it.
 //
 // The caller is expected to call c.Close() before calling startNewFile if a
 // previous file was open.
 func (c *BlobstoreCSVWriter) startNewFile(ctx context.Context, key string) {
 	c.buf = bytes.Buffer{}
-	csvWriter := csv.NewWriter(&c.buf)
+	encoder := c.newEncoder(&c.buf)
 
 	closeFn := func() error {
-		csvWriter.Flush()
+		encoder.Flush()
 		// Don't upload empty files.
 		if c.buf.Len() == 0 {
 			return nil
@@ -201,7 +278,7 @@ func (c *BlobstoreCSVWriter) startNewFile(ctx context.Context, key string) {
 		return err
 	}
 
-	c.w = csvWriter
+	c.w = encoder
 	c.close = closeFn
 	c.n = 0
 }
@@ -229,6 +306,55 @@ func (c *BlobstoreCSVWriter) Close() error {
 	return c.close()
 }
 
+// jsonLinesEncoder is a rowEncoder which writes each row as a JSON object
+// keyed by the header. We don't use a map with encoding/json since that would
+// not preserve the order of the columns.
+type jsonLinesEncoder struct {
+	w      io.Writer
+	header []string
+	buf    bytes.Buffer
+}
+
+func (e *jsonLinesEncoder) Write(record []string) error {
+	if e.header == nil {
+		e.header = record
+		return nil
+	}
+	if len(record) != len(e.header) {
+		return errors.Errorf("row has %d values but header has %d", len(record), len(e.header))
+	}
+
+	e.buf.Reset()
+	e.buf.WriteByte('{')
+	for i, v := range record {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		if err := writeJSONString(&e.buf, e.header[i]); err != nil {
+			return err
+		}
+		e.buf.WriteByte(':')
+		if err := writeJSONString(&e.buf, v); err != nil {
+			return err
+		}
+	}
+	e.buf.WriteString("}\n")
+
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+func (e *jsonLinesEncoder) Flush() {}
+
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
 // NewSearcherFake is a convenient working implementation of SearchQuery which
 // always will write results generated from the repoRevs. It expects a query
 // string which looks like
@@ -312,6 +438,15 @@ func (s searcherFake) ResolveRepositoryRevSpec(ctx context.Context, repoRevSpec
 	return repoRevs, nil
 }
 
+func (s searcherFake) ResolveCommit(ctx context.Context, r types.RepositoryRevision) (api.CommitID, error) {
+	if err := isSameUser(ctx, s.userID); err != nil {
+		return "", err
+	}
+
+	// The fake treats every revision as a commit which never changes.
+	return api.CommitID(r.Revision), nil
+}
+
 func (s searcherFake) Search(ctx context.Context, r types.RepositoryRevision, w CSVWriter) error {
 	if err := isSameUser(ctx, s.userID); err != nil {
 		return err
@@ -114,6 +114,48 @@ func TestBlobstoreCSVWriter(t *testing.T) {
 	}
 }
 
+func TestBlobstoreJSONLinesWriter(t *testing.T) {
+	mockStore := setupMockStore(t)
+
+	jsonWriter := NewBlobstoreJSONLinesWriter(context.Background(), mockStore, "blob")
+	jsonWriter.maxBlobSizeBytes = 12
+
+	err := jsonWriter.WriteHeader("h1", "h2")
+	require.NoError(t, err)
+	err = jsonWriter.WriteRow("a", `"quoted"`)
+	require.NoError(t, err)
+	// We expect a new file to be created here because we have reached the max blob size.
+	err = jsonWriter.WriteRow("b", "b")
+	require.NoError(t, err)
+
+	err = jsonWriter.Close()
+	require.NoError(t, err)
+
+	tc := []struct {
+		wantKey  string
+		wantBlob string
+	}{
+		{
+			wantKey:  "blob",
+			wantBlob: `{"h1":"a","h2":"\"quoted\""}` + "\n",
+		},
+		{
+			wantKey:  "blob-2",
+			wantBlob: `{"h1":"b","h2":"b"}` + "\n",
+		},
+	}
+
+	for _, c := range tc {
+		blob, err := mockStore.Get(context.Background(), c.wantKey)
+		require.NoError(t, err)
+
+		blobBytes, err := io.ReadAll(blob)
+		require.NoError(t, err)
+
+		require.Equal(t, c.wantBlob, string(blobBytes))
+	}
+}
+
 func TestNoUploadIfNotData(t *testing.T) {
 	mockStore := setupMockStore(t)
 	csvWriter := NewBlobstoreCSVWriter(context.Background(), mockStore, "blob")
@@ -129,6 +171,25 @@ func TestNoUploadIfNotData(t *testing.T) {
 	}
 }
 
+func TestDeleteBlobstoreShards(t *testing.T) {
+	ctx := context.Background()
+	mockStore := setupMockStore(t)
+
+	for _, key := range []string{"1-1", "1-1-2", "1-1-3", "1-10", "1-10-2", "1-2"} {
+		_, err := mockStore.Upload(ctx, key, strings.NewReader("data"))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, DeleteBlobstoreShards(ctx, mockStore, "1-1"))
+
+	iter, err := mockStore.List(ctx, "")
+	require.NoError(t, err)
+	keys, err := iterator.Collect(iter)
+	require.NoError(t, err)
+	slices.Sort(keys)
+	require.Equal(t, []string{"1-10", "1-10-2", "1-2"}, keys)
+}
+
 func setupMockStore(t *testing.T) *mocks.MockStore {
 	t.Helper()
 
@@ -156,6 +217,11 @@ func setupMockStore(t *testing.T) *mocks.MockStore {
 		return iterator.From(keys), nil
 	})
 
+	mockStore.DeleteFunc.SetDefaultHook(func(ctx context.Context, key string) error {
+		delete(bucket, key)
+		return nil
+	})
+
 	mockStore.GetFunc.SetDefaultHook(func(ctx context.Context, key string) (io.ReadCloser, error) {
 		if b, ok := bucket[key]; ok {
 			return io.NopCloser(bytes.NewReader(b)), nil
@@ -2,11 +2,11 @@ package service
 
 import (
 	"context"
-	"strings"
 	"sync"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/client"
@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/search/job/jobutil"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/repos"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	sgtypes "github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -32,25 +33,32 @@ func FromSearchClient(client client.SearchClient) NewSearcher {
 		// latency is not a priority of Search Jobs.
 		q = "index:no " + q
 
-		// TODO this hack is an ugly workaround to limit searches to type:file only.
-		// This is OK for the EAP but we should remove the limitation soon.
-		if !strings.Contains(q, "type:file") {
-			q = "type:file " + q
+		plan := func(q string) (*search.Inputs, error) {
+			return client.Plan(
+				ctx,
+				"V3",
+				nil,
+				q,
+				search.Precise,
+				search.Exhaustive,
+				pointers.Ptr(int32(0)),
+			)
 		}
 
-		inputs, err := client.Plan(
-			ctx,
-			"V3",
-			nil,
-			q,
-			search.Precise,
-			search.Exhaustive,
-			pointers.Ptr(int32(0)),
-		)
+		inputs, err := plan(q)
 		if err != nil {
 			return nil, err
 		}
 
+		// Search Jobs export results of a single type, which defaults to
+		// file matches.
+		if types, _ := inputs.Query.StringValues(query.FieldType); len(types) == 0 {
+			inputs, err = plan("type:file " + q)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		exhaustive, err := jobutil.NewExhaustive(inputs)
 		if err != nil {
 			return nil, err
@@ -161,6 +169,25 @@ func (s searchQuery) toRepoRevSpecs(ctx context.Context, repoRevSpec types.Repos
 	}, nil
 }
 
+func (s searchQuery) ResolveCommit(ctx context.Context, repoRev types.RepositoryRevision) (api.CommitID, error) {
+	if err := isSameUser(ctx, s.userID); err != nil {
+		return "", err
+	}
+
+	repo, err := s.minimalRepo(ctx, repoRev.Repository)
+	if err != nil {
+		return "", err
+	}
+
+	commitID, err := s.clients.Gitserver.ResolveRevision(ctx, repo.Name, repoRev.Revision, gitserver.ResolveRevisionOptions{NoEnsureRevision: true})
+	// An empty repository has no commit to resolve HEAD to. Search treats
+	// this as success, so we do the same here.
+	if repoRev.Revision == "HEAD" && errors.HasType(err, &gitdomain.RevisionNotFoundError{}) {
+		return "", nil
+	}
+	return commitID, err
+}
+
 func (s searchQuery) Search(ctx context.Context, repoRev types.RepositoryRevision, w CSVWriter) error {
 	if err := isSameUser(ctx, s.userID); err != nil {
 		return err
@@ -186,6 +213,10 @@ func (s searchQuery) Search(ctx context.Context, repoRev types.RepositoryRevisio
 		return err
 	}
 
+	// Directories are sent again whenever more matches are found in them, so
+	// we sum them up and write them once the search is done.
+	directories := result.NewDeduper()
+
 	// TODO currently ignoring returned Alert
 	_, err = job.Run(ctx, s.clients, streaming.StreamFunc(func(se streaming.SearchEvent) {
 		// TODO fail if se.Stats indicate missing backends or other things
@@ -195,6 +226,10 @@ func (s searchQuery) Search(ctx context.Context, repoRev types.RepositoryRevisio
 		defer mu.Unlock()
 
 		for _, match := range se.Results {
+			if dm, ok := match.(*result.DirectoryMatch); ok {
+				directories.Add(dm)
+				continue
+			}
 			err := matchWriter.Write(match)
 			if err != nil {
 				cancel()
@@ -207,6 +242,12 @@ func (s searchQuery) Search(ctx context.Context, repoRev types.RepositoryRevisio
 		return writeRowErr
 	}
 
+	for _, match := range directories.Results() {
+		if err := matchWriter.Write(match); err != nil {
+			return err
+		}
+	}
+
 	// TODO how should we handle cloning (gitdomain.RepoNotExistError)?
 
 	// An empty repository we treat as success. When searching HEAD we haven't
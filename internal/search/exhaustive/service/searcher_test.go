@@ -20,6 +20,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/featureflag"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/internal/search/client"
@@ -167,6 +168,34 @@ foo1,commitfoo2,,1,/foo1@commitfoo2/-/blob/?L2
 		Query: "repo:doesnotmatch content",
 	})
 
+	do("type:symbol", newSearcherTestCase{
+		Query:        "repo:foo type:symbol content",
+		WantRefSpecs: "RepositoryRevSpec{1@HEAD}",
+		WantRepoRevs: "RepositoryRevision{1@HEAD}",
+		WantCSV: autogold.Expect(`repository,revision,file_path,symbol_name,symbol_kind,symbol_container,symbol_url
+foo1,commitfoo0,main.go,content,function,,/foo1@commitfoo0/-/blob/main.go?L2:1-2:8
+`),
+	})
+
+	do("type:commit", newSearcherTestCase{
+		Query:        "type:commit content",
+		WantRefSpecs: "RepositoryRevSpec{1@HEAD} RepositoryRevSpec{2@HEAD} RepositoryRevSpec{3@HEAD}",
+		WantRepoRevs: "RepositoryRevision{1@HEAD} RepositoryRevision{2@HEAD} RepositoryRevision{3@HEAD}",
+		WantCSV: autogold.Expect(`repository,commit,author_name,author_email,author_date,subject,match_count,commit_url
+foo1,commitfoo0,Alice,alice@example.com,2023-11-27T10:00:00Z,add content,1,/foo1/-/commit/commitfoo0
+bar2,commitbar0,Alice,alice@example.com,2023-11-27T10:00:00Z,add content,1,/bar2/-/commit/commitbar0
+`),
+	})
+
+	do("type:diff", newSearcherTestCase{
+		Query:        "repo:foo rev:dev1 type:diff content",
+		WantRefSpecs: "RepositoryRevSpec{1@dev1}",
+		WantRepoRevs: "RepositoryRevision{1@dev1}",
+		WantCSV: autogold.Expect(`repository,commit,author_name,author_email,author_date,subject,old_file_path,new_file_path,lines_added,lines_removed,commit_url
+foo1,commitfoo1,Alice,alice@example.com,2023-11-27T10:00:00Z,add content,README.md,README.md,1,0,/foo1/-/commit/commitfoo1
+`),
+	})
+
 	do("missingrev", newSearcherTestCase{
 		Query:        "repo:foo rev:dev1:missing content",
 		WantRefSpecs: "RepositoryRevSpec{1@dev1:missing}",
@@ -249,6 +278,39 @@ func mockGitserver(repoMocks []repoMock) *gitserver.MockClient {
 		})
 		return refs, nil
 	})
+	gsClient.SearchFunc.SetDefaultHook(func(_ context.Context, args *protocol.SearchRequest, onMatches func([]protocol.CommitMatch)) (bool, error) {
+		repo, err := get(args.Repo)
+		if err != nil {
+			return false, err
+		}
+		var matches []protocol.CommitMatch
+		for _, rev := range args.Revisions {
+			commit, ok := repo.Branches[rev.RevSpec]
+			if !ok {
+				return false, &gitdomain.RevisionNotFoundError{Repo: args.Repo, Spec: rev.RevSpec}
+			}
+			match := protocol.CommitMatch{
+				Oid: api.CommitID(commit),
+				Author: protocol.Signature{
+					Name:  "Alice",
+					Email: "alice@example.com",
+					Date:  time.Date(2023, 11, 27, 10, 0, 0, 0, time.UTC),
+				},
+				Message: result.MatchedString{
+					Content:       "add content",
+					MatchedRanges: result.Ranges{{Start: result.Location{Offset: 4, Column: 4}, End: result.Location{Offset: 11, Column: 11}}},
+				},
+			}
+			if args.IncludeDiff {
+				match.Diff = result.MatchedString{
+					Content: "README.md README.md\n@@ -1,1 +1,2 @@\n title\n+content\n",
+				}
+			}
+			matches = append(matches, match)
+		}
+		onMatches(matches)
+		return false, nil
+	})
 	return gsClient
 }
 
@@ -334,8 +396,17 @@ func mockSearcher(t *testing.T, repoMocks []repoMock) *endpoint.Map {
 		}
 		return false, nil
 	}
+	searcher.MockSearchSymbols = func(ctx context.Context, args search.SymbolsParameters) (result.Symbols, error) {
+		return result.Symbols{{
+			Name: "content",
+			Path: "main.go",
+			Line: 1,
+			Kind: "function",
+		}}, nil
+	}
 	t.Cleanup(func() {
 		searcher.MockSearchFilesInRepo = nil
+		searcher.MockSearchSymbols = nil
 	})
 	return endpoint.Static("test")
 }
@@ -61,10 +61,16 @@ type operations struct {
 	deleteSearchJob          *observation.Operation
 	listSearchJobs           *observation.Operation
 	cancelSearchJob          *observation.Operation
+	retrySearchJob           *observation.Operation
 	getAggregateRepoRevState *observation.Operation
 
-	getSearchJobCSVWriterTo  operationWithWriterTo
-	getSearchJobLogsWriterTo operationWithWriterTo
+	setSearchJobSchedule      *observation.Operation
+	runDueScheduledSearchJobs *observation.Operation
+	diffFinishedScheduledRuns *observation.Operation
+
+	getSearchJobResultsWriterTo operationWithWriterTo
+	getSearchJobLogsWriterTo    operationWithWriterTo
+	getSearchJobDiffWriterTo    operationWithWriterTo
 }
 
 // operationWithWriterTo encodes our pattern around our CSV WriterTo were we
@@ -107,16 +113,25 @@ func newOperations(observationCtx *observation.Context) *operations {
 			deleteSearchJob:          op("DeleteSearchJob"),
 			listSearchJobs:           op("ListSearchJobs"),
 			cancelSearchJob:          op("CancelSearchJob"),
+			retrySearchJob:           op("RetrySearchJob"),
 			getAggregateRepoRevState: op("GetAggregateRepoRevState"),
 
-			getSearchJobCSVWriterTo: operationWithWriterTo{
-				get:      op("GetSearchJobCSVWriterTo"),
-				writerTo: op("GetSearchJobCSVWriterTo.WriteTo"),
+			setSearchJobSchedule:      op("SetSearchJobSchedule"),
+			runDueScheduledSearchJobs: op("RunDueScheduledSearchJobs"),
+			diffFinishedScheduledRuns: op("DiffFinishedScheduledRuns"),
+
+			getSearchJobResultsWriterTo: operationWithWriterTo{
+				get:      op("GetSearchJobResultsWriterTo"),
+				writerTo: op("GetSearchJobResultsWriterTo.WriteTo"),
 			},
 			getSearchJobLogsWriterTo: operationWithWriterTo{
 				get:      op("GetSearchJobLogsWriterTo"),
 				writerTo: op("GetSearchJobLogsWriterTo.WriteTo"),
 			},
+			getSearchJobDiffWriterTo: operationWithWriterTo{
+				get:      op("GetSearchJobDiffWriterTo"),
+				writerTo: op("GetSearchJobDiffWriterTo.WriteTo"),
+			},
 		}
 	})
 	return singletonOperations
@@ -131,9 +146,12 @@ func (s *Service) ValidateSearchJob(ctx context.Context, query string) error {
 	return err
 }
 
-func (s *Service) CreateSearchJob(ctx context.Context, query string) (_ *types.ExhaustiveSearchJob, err error) {
+// CreateSearchJob creates a search job for query. The results of the job are
+// written in format.
+func (s *Service) CreateSearchJob(ctx context.Context, query string, format types.OutputFormat) (_ *types.ExhaustiveSearchJob, err error) {
 	ctx, _, endObservation := s.operations.createSearchJob.With(ctx, &err, opAttrs(
 		attribute.String("query", query),
+		attribute.String("format", string(format)),
 	))
 	defer endObservation(1, observation.Args{})
 
@@ -152,6 +170,11 @@ func (s *Service) CreateSearchJob(ctx context.Context, query string) (_ *types.E
 		return nil, err
 	}
 
+	format, err = types.ParseOutputFormat(string(format))
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
 		return nil, err
@@ -160,10 +183,11 @@ func (s *Service) CreateSearchJob(ctx context.Context, query string) (_ *types.E
 
 	// XXX(keegancsmith) this API for creating seems easy to mess up since the
 	// ExhaustiveSearchJob type has lots of fields, but reading the store
-	// implementation only two fields are read.
+	// implementation only three fields are read.
 	jobID, err := tx.CreateExhaustiveSearchJob(ctx, types.ExhaustiveSearchJob{
 		InitiatorID: actor.UID,
 		Query:       query,
+		Format:      format,
 	})
 	if err != nil {
 		return nil, err
@@ -188,6 +212,48 @@ func (s *Service) CancelSearchJob(ctx context.Context, id int64) (err error) {
 	return err
 }
 
+// RetrySearchJob runs the finished search job id again. mode decides which
+// parts of the job are run again, see types.RetryMode. Results of revisions
+// which are not searched again are kept and included in the results of the
+// job.
+func (s *Service) RetrySearchJob(ctx context.Context, id int64, mode types.RetryMode) (_ *types.ExhaustiveSearchJob, err error) {
+	ctx, _, endObservation := s.operations.retrySearchJob.With(ctx, &err, opAttrs(
+		attribute.Int64("id", id),
+		attribute.String("mode", string(mode)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	mode, err = types.ParseRetryMode(string(mode))
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// 🚨 SECURITY: GetExhaustiveSearchJob checks that the user has access to
+	// the job.
+	job, err := tx.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch job.AggState {
+	case types.JobStateCompleted, types.JobStateFailed, types.JobStateCanceled:
+	default:
+		return nil, errors.Errorf("search job %d is still running, only finished search jobs can be retried", id)
+	}
+
+	if _, err := tx.RetrySearchJob(ctx, id, mode); err != nil {
+		return nil, err
+	}
+
+	return tx.GetExhaustiveSearchJob(ctx, id)
+}
+
 func (s *Service) GetSearchJob(ctx context.Context, id int64) (_ *types.ExhaustiveSearchJob, err error) {
 	ctx, _, endObservation := s.operations.getSearchJob.With(ctx, &err, opAttrs(
 		attribute.Int64("id", id),
@@ -314,43 +380,53 @@ func (s *Service) DeleteSearchJob(ctx context.Context, id int64) (err error) {
 		return err
 	}
 
+	// Blobs of scheduled runs, see schedule.go.
+	for _, prefix := range []string{previousResultsKey(id), diffPrefix(id)} {
+		if err := DeleteBlobstoreShards(ctx, s.uploadStore, prefix); err != nil {
+			return err
+		}
+	}
+
 	return s.store.DeleteExhaustiveSearchJob(ctx, id)
 }
 
-// WriteSearchJobCSV copies all CSVs associated with a search job to the given
-// writer. It returns the number of bytes written and any error encountered.
-
-// GetSearchJobCSVWriterTo returns a WriterTo which can be called once to
-// write all CSVs associated with a search job to the given writer for job id.
-// Note: ctx is used by WriterTo.
+// GetSearchJobResultsWriterTo returns a WriterTo which can be called once to
+// write all results associated with a search job to the given writer for job
+// id. The results are written in the format the job was created with, which
+// is also returned. Note: ctx is used by WriterTo.
 //
 // io.WriterTo is a specialization of an io.Reader. We expect callers of this
 // function to want to write an http response, so we avoid an io.Pipe and
 // instead pass a more direct use.
-func (s *Service) GetSearchJobCSVWriterTo(parentCtx context.Context, id int64) (_ io.WriterTo, err error) {
-	ctx, _, endObservation := s.operations.getSearchJobCSVWriterTo.get.With(parentCtx, &err, opAttrs(
+func (s *Service) GetSearchJobResultsWriterTo(parentCtx context.Context, id int64) (_ io.WriterTo, _ types.OutputFormat, err error) {
+	ctx, _, endObservation := s.operations.getSearchJobResultsWriterTo.get.With(parentCtx, &err, opAttrs(
 		attribute.Int64("id", id)))
 	defer endObservation(1, observation.Args{})
 
-	// 🚨 SECURITY: only someone with access to the job may copy the blobs
-	if err := s.store.UserHasAccess(ctx, id); err != nil {
-		return nil, err
+	// 🚨 SECURITY: only someone with access to the job may copy the blobs.
+	// GetExhaustiveSearchJob checks access.
+	job, err := s.store.GetExhaustiveSearchJob(ctx, id)
+	if err != nil {
+		return nil, "", err
 	}
 
 	iter, err := s.uploadStore.List(ctx, getPrefix(id))
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
+	// Every CSV blob starts with the same header, JSON Lines blobs have none.
+	hasHeader := job.Format != types.OutputFormatJSONLines
+
 	return writerToFunc(func(w io.Writer) (n int64, err error) {
-		ctx, _, endObservation := s.operations.getSearchJobCSVWriterTo.writerTo.With(parentCtx, &err, opAttrs(
+		ctx, _, endObservation := s.operations.getSearchJobResultsWriterTo.writerTo.With(parentCtx, &err, opAttrs(
 			attribute.Int64("id", id)))
 		defer func() {
 			endObservation(1, opAttrs(attribute.Int64("bytesWritten", n)))
 		}()
 
-		return writeSearchJobCSV(ctx, iter, s.uploadStore, w)
-	}), nil
+		return writeSearchJobResults(ctx, iter, s.uploadStore, w, hasHeader)
+	}), job.Format, nil
 }
 
 // GetAggregateRepoRevState returns the map of state -> count for all repo
@@ -401,7 +477,9 @@ func discardUntil(br *bufio.Reader, delim byte) error {
 	}
 }
 
-func writeSearchJobCSV(ctx context.Context, iter *iterator.Iterator[string], uploadStore uploadstore.Store, w io.Writer) (int64, error) {
+// writeSearchJobResults copies all blobs in iter to w. If hasHeader is true,
+// only the header of the first blob is written.
+func writeSearchJobResults(ctx context.Context, iter *iterator.Iterator[string], uploadStore uploadstore.Store, w io.Writer, hasHeader bool) (int64, error) {
 	// keep a single bufio.Reader so we can reuse its buffer.
 	var br bufio.Reader
 	writeKey := func(key string, skipHeader bool) (int64, error) {
@@ -436,9 +514,9 @@ func writeSearchJobCSV(ctx context.Context, iter *iterator.Iterator[string], upl
 		m, err := writeKey(key, skipHeader)
 		n += m
 		if err != nil {
-			return n, errors.Wrapf(err, "writing csv for key %q", key)
+			return n, errors.Wrapf(err, "writing results for key %q", key)
 		}
-		skipHeader = true
+		skipHeader = hasHeader
 	}
 
 	return n, iter.Err()
@@ -28,10 +28,33 @@ func Test_copyBlobs(t *testing.T) {
 
 	w := &bytes.Buffer{}
 
-	n, err := writeSearchJobCSV(context.Background(), keysIter, blobstore, w)
+	n, err := writeSearchJobResults(context.Background(), keysIter, blobstore, w, true)
 	require.NoError(t, err)
 	require.Equal(t, int64(24), n)
 
 	want := "h/h/h\na/a/a\nb/b/b\nc/c/c\n"
 	require.Equal(t, want, w.String())
 }
+
+func Test_copyBlobsWithoutHeader(t *testing.T) {
+	keysIter := iterator.From([]string{"a", "b"})
+
+	blobs := map[string]io.Reader{
+		"a": bytes.NewReader([]byte("{\"h\":\"a\"}\n")),
+		"b": bytes.NewReader([]byte("{\"h\":\"b\"}\n")),
+	}
+
+	blobstore := mocks.NewMockStore()
+	blobstore.GetFunc.SetDefaultHook(func(ctx context.Context, key string) (io.ReadCloser, error) {
+		return io.NopCloser(blobs[key]), nil
+	})
+
+	w := &bytes.Buffer{}
+
+	n, err := writeSearchJobResults(context.Background(), keysIter, blobstore, w, false)
+	require.NoError(t, err)
+	require.Equal(t, int64(20), n)
+
+	want := "{\"h\":\"a\"}\n{\"h\":\"b\"}\n"
+	require.Equal(t, want, w.String())
+}
@@ -49,6 +49,13 @@ var exhaustiveSearchJobColumns = []*sqlf.Query{
 	sqlf.Sprintf("initiator_id"),
 	sqlf.Sprintf("state"),
 	sqlf.Sprintf("query"),
+	sqlf.Sprintf("format"),
+	sqlf.Sprintf("schedule"),
+	sqlf.Sprintf("next_run_at"),
+	sqlf.Sprintf("diff_pending"),
+	sqlf.Sprintf("diff_added"),
+	sqlf.Sprintf("diff_removed"),
+	sqlf.Sprintf("diff_computed_at"),
 	sqlf.Sprintf("failure_message"),
 	sqlf.Sprintf("started_at"),
 	sqlf.Sprintf("finished_at"),
@@ -66,12 +73,16 @@ func (s *Store) CreateExhaustiveSearchJob(ctx context.Context, job types.Exhaust
 	ctx, _, endObservation := s.operations.createExhaustiveSearchJob.With(ctx, &err, opAttrs(
 		attribute.String("query", job.Query),
 		attribute.Int("initiator_id", int(job.InitiatorID)),
+		attribute.String("format", string(job.Format)),
 	))
 	defer endObservation(1, observation.Args{})
 
 	if job.Query == "" {
 		return 0, MissingQueryErr
 	}
+	if job.Format == "" {
+		job.Format = types.OutputFormatCSV
+	}
 	if job.InitiatorID <= 0 {
 		return 0, MissingInitiatorIDErr
 	}
@@ -83,7 +94,7 @@ func (s *Store) CreateExhaustiveSearchJob(ctx context.Context, job types.Exhaust
 
 	return basestore.ScanAny[int64](s.Store.QueryRow(
 		ctx,
-		sqlf.Sprintf(createExhaustiveSearchJobQueryFmtr, job.Query, job.InitiatorID),
+		sqlf.Sprintf(createExhaustiveSearchJobQueryFmtr, job.Query, job.InitiatorID, job.Format),
 	))
 }
 
@@ -94,8 +105,8 @@ var MissingQueryErr = errors.New("missing query")
 var MissingInitiatorIDErr = errors.New("missing initiator ID")
 
 const createExhaustiveSearchJobQueryFmtr = `
-INSERT INTO exhaustive_search_jobs (query, initiator_id)
-VALUES (%s, %s)
+INSERT INTO exhaustive_search_jobs (query, initiator_id, format)
+VALUES (%s, %s, %s)
 RETURNING id
 `
 
@@ -161,6 +172,211 @@ updated_repo_revision_jobs AS (
 SELECT (SELECT count(*) FROM updated_jobs) + (SELECT count(*) FROM updated_repo_jobs) + (SELECT count(*) FROM updated_repo_revision_jobs) as total_canceled
 `
 
+// RetrySearchJob queues the parts of search job id again which match mode. It
+// returns the number of jobs across all tables which were queued.
+//
+// With types.RetryModeFailed only failed and canceled jobs are queued. With
+// types.RetryModeChanged completed jobs are queued as well. The workers skip
+// work which has already been done, see the handlers in
+// cmd/worker/internal/search.
+func (s *Store) RetrySearchJob(ctx context.Context, id int64, mode types.RetryMode) (totalQueued int, err error) {
+	ctx, _, endObservation := s.operations.retrySearchJob.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.String("mode", string(mode)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may retry the job
+	err = s.UserHasAccess(ctx, id)
+	if err != nil {
+		return -1, err
+	}
+
+	var states []*sqlf.Query
+	switch mode {
+	case types.RetryModeFailed:
+		states = []*sqlf.Query{sqlf.Sprintf("'failed'"), sqlf.Sprintf("'canceled'")}
+	case types.RetryModeChanged:
+		states = []*sqlf.Query{sqlf.Sprintf("'failed'"), sqlf.Sprintf("'canceled'"), sqlf.Sprintf("'completed'")}
+	default:
+		return -1, errors.Errorf("unsupported retry mode %q", mode)
+	}
+	stateList := sqlf.Join(states, ",")
+
+	now := time.Now()
+	q := sqlf.Sprintf(
+		retryJobFmtStr,
+		now, id, stateList,
+		now, id, stateList,
+		now, id, stateList,
+	)
+
+	err = s.QueryRow(ctx, q).Scan(&totalQueued)
+	if err != nil {
+		return -1, err
+	}
+
+	return totalQueued, nil
+}
+
+const retryJobFmtStr = `
+WITH updated_jobs AS (
+    UPDATE exhaustive_search_jobs
+    SET state = 'queued', failure_message = NULL, started_at = NULL, finished_at = NULL,
+    process_after = NULL, last_heartbeat_at = NULL, num_resets = 0, num_failures = 0,
+    worker_hostname = '', cancel = FALSE, queued_at = %s
+    WHERE id = %s AND state IN (%s)
+    RETURNING id
+),
+updated_repo_jobs AS (
+    UPDATE exhaustive_search_repo_jobs
+    SET state = 'queued', failure_message = NULL, started_at = NULL, finished_at = NULL,
+    process_after = NULL, last_heartbeat_at = NULL, num_resets = 0, num_failures = 0,
+    worker_hostname = '', cancel = FALSE, queued_at = %s
+    WHERE search_job_id = %s AND state IN (%s)
+    RETURNING id
+),
+updated_repo_revision_jobs AS (
+    UPDATE exhaustive_search_repo_revision_jobs rrj
+    SET state = 'queued', failure_message = NULL, started_at = NULL, finished_at = NULL,
+    process_after = NULL, last_heartbeat_at = NULL, num_resets = 0, num_failures = 0,
+    worker_hostname = '', cancel = FALSE, queued_at = %s
+    FROM exhaustive_search_repo_jobs rj
+    WHERE rrj.search_repo_job_id = rj.id AND rj.search_job_id = %s AND rrj.state IN (%s)
+    RETURNING rrj.id
+)
+SELECT (SELECT count(*) FROM updated_jobs) + (SELECT count(*) FROM updated_repo_jobs) + (SELECT count(*) FROM updated_repo_revision_jobs) as total_queued
+`
+
+// SetSearchJobSchedule sets the cron schedule of search job id. The job is run
+// again at nextRunAt. An empty schedule removes the schedule from the job.
+func (s *Store) SetSearchJobSchedule(ctx context.Context, id int64, schedule string, nextRunAt time.Time) (err error) {
+	ctx, _, endObservation := s.operations.setSearchJobSchedule.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.String("schedule", schedule),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may schedule the job
+	err = s.UserHasAccess(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if schedule == "" {
+		nextRunAt = time.Time{}
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(
+		setSearchJobScheduleFmtStr,
+		dbutil.NullStringColumn(schedule),
+		dbutil.NullTimeColumn(nextRunAt),
+		id,
+	))
+}
+
+const setSearchJobScheduleFmtStr = `
+UPDATE exhaustive_search_jobs
+SET schedule = %s, next_run_at = %s
+WHERE id = %s
+`
+
+// finishedAggStates are the aggregate states of a search job once all its
+// parts stopped running.
+var finishedAggStates = sqlf.Join([]*sqlf.Query{
+	sqlf.Sprintf("%s", types.JobStateCompleted),
+	sqlf.Sprintf("%s", types.JobStateFailed),
+	sqlf.Sprintf("%s", types.JobStateCanceled),
+}, ",")
+
+// ListDueScheduledSearchJobs returns the finished search jobs whose next
+// scheduled run is due at now and whose last run was already diffed.
+func (s *Store) ListDueScheduledSearchJobs(ctx context.Context, now time.Time) (jobs []*types.ExhaustiveSearchJob, err error) {
+	ctx, _, endObservation := s.operations.listDueScheduledSearchJobs.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("length", len(jobs))))
+	}()
+
+	// 🚨 SECURITY: this lists the jobs of all users, so it is only available
+	// to the worker which runs scheduled jobs.
+	if !actor.FromContext(ctx).IsInternal() {
+		return nil, errors.New("only internal actors can list due scheduled search jobs")
+	}
+
+	where := sqlf.Sprintf(
+		"WHERE schedule IS NOT NULL AND next_run_at <= %s AND NOT diff_pending AND agg_state IN (%s)",
+		now,
+		finishedAggStates,
+	)
+	return scanExhaustiveSearchJobsList(s.Store.Query(ctx, listSearchJobQuery(where)))
+}
+
+// StartScheduledRun records that a scheduled run of search job id started.
+// The run after this one is due at nextRunAt.
+func (s *Store) StartScheduledRun(ctx context.Context, id int64, nextRunAt time.Time) (err error) {
+	ctx, _, endObservation := s.operations.startScheduledRun.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may run the job
+	err = s.UserHasAccess(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(startScheduledRunFmtStr, nextRunAt, id))
+}
+
+const startScheduledRunFmtStr = `
+UPDATE exhaustive_search_jobs
+SET next_run_at = %s, diff_pending = TRUE
+WHERE id = %s
+`
+
+// ListSearchJobsWithPendingDiff returns the search jobs whose scheduled run
+// finished, but whose results haven't been compared to the previous run yet.
+func (s *Store) ListSearchJobsWithPendingDiff(ctx context.Context) (jobs []*types.ExhaustiveSearchJob, err error) {
+	ctx, _, endObservation := s.operations.listSearchJobsWithPendingDiff.With(ctx, &err, observation.Args{})
+	defer func() {
+		endObservation(1, opAttrs(attribute.Int("length", len(jobs))))
+	}()
+
+	// 🚨 SECURITY: this lists the jobs of all users, so it is only available
+	// to the worker which runs scheduled jobs.
+	if !actor.FromContext(ctx).IsInternal() {
+		return nil, errors.New("only internal actors can list search jobs with pending diffs")
+	}
+
+	where := sqlf.Sprintf("WHERE diff_pending AND agg_state IN (%s)", finishedAggStates)
+	return scanExhaustiveSearchJobsList(s.Store.Query(ctx, listSearchJobQuery(where)))
+}
+
+// SetResultsDiff stores the diff between the results of the latest scheduled
+// run of search job id and the run before. It clears DiffPending.
+func (s *Store) SetResultsDiff(ctx context.Context, id int64, diff types.ResultsDiff) (err error) {
+	ctx, _, endObservation := s.operations.setResultsDiff.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+		attribute.Int("added", int(diff.Added)),
+		attribute.Int("removed", int(diff.Removed)),
+	))
+	defer endObservation(1, observation.Args{})
+
+	// 🚨 SECURITY: only someone with access to the job may update the job
+	err = s.UserHasAccess(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(setResultsDiffFmtStr, diff.Added, diff.Removed, diff.ComputedAt, id))
+}
+
+const setResultsDiffFmtStr = `
+UPDATE exhaustive_search_jobs
+SET diff_added = %s, diff_removed = %s, diff_computed_at = %s, diff_pending = FALSE
+WHERE id = %s
+`
+
 func listSearchJobQuery(where *sqlf.Query) *sqlf.Query {
 	return sqlf.Sprintf(
 		listExhaustiveSearchJobsQueryFmtStr,
@@ -535,6 +751,13 @@ func defaultScanTargets(job *types.ExhaustiveSearchJob) []any {
 		&job.InitiatorID,
 		&job.State,
 		&job.Query,
+		&job.Format,
+		&dbutil.NullString{S: &job.Schedule},
+		&dbutil.NullTime{Time: &job.NextRunAt},
+		&job.DiffPending,
+		&dbutil.NullInt32{N: &job.Diff.Added},
+		&dbutil.NullInt32{N: &job.Diff.Removed},
+		&dbutil.NullTime{Time: &job.Diff.ComputedAt},
 		&dbutil.NullString{S: &job.FailureMessage},
 		&dbutil.NullTime{Time: &job.StartedAt},
 		&dbutil.NullTime{Time: &job.FinishedAt},
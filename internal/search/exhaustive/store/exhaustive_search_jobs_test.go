@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/keegancsmith/sqlf"
@@ -56,6 +57,15 @@ func TestStore_CreateExhaustiveSearchJob(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "New job with JSON Lines format",
+			job: types.ExhaustiveSearchJob{
+				InitiatorID: userID,
+				Query:       "repo:^github\\.com/hashicorp/errwrap$ CreateExhaustiveSearchJob_jsonl",
+				Format:      types.OutputFormatJSONLines,
+			},
+			expectedErr: nil,
+		},
 		{
 			name: "Missing user ID",
 			job: types.ExhaustiveSearchJob{
@@ -396,6 +406,134 @@ func TestStore_AggregateStatus(t *testing.T) {
 	}
 }
 
+func TestStore_RetrySearchJob(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	_, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+
+	s := store.New(db, &observation.TestContext)
+
+	casc := stateCascade{
+		searchJob: types.JobStateCompleted,
+		repoJobs:  []types.JobState{types.JobStateCompleted},
+		repoRevJobs: []types.JobState{
+			types.JobStateCompleted,
+			types.JobStateFailed,
+			types.JobStateCanceled,
+		},
+	}
+
+	tc := []struct {
+		mode       types.RetryMode
+		wantQueued int
+	}{
+		{
+			mode:       types.RetryModeFailed,
+			wantQueued: 2, // the failed and the canceled repo rev job
+		},
+		{
+			mode:       types.RetryModeChanged,
+			wantQueued: 5, // 1 search job + 1 repo job + 3 repo rev jobs
+		},
+	}
+
+	for i, tt := range tc {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			userID, err := createUser(bs, fmt.Sprintf("user_retry_%d", i))
+			require.NoError(t, err)
+
+			ctx := actor.WithActor(context.Background(), actor.FromUser(userID))
+			jobID := createJobCascade(t, ctx, s, casc)
+
+			job, err := s.GetExhaustiveSearchJob(ctx, jobID)
+			require.NoError(t, err)
+			require.Equal(t, types.JobStateFailed, job.AggState)
+
+			gotQueued, err := s.RetrySearchJob(ctx, jobID, tt.mode)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantQueued, gotQueued)
+
+			job, err = s.GetExhaustiveSearchJob(ctx, jobID)
+			require.NoError(t, err)
+			require.Equal(t, types.JobStateQueued, job.AggState)
+		})
+	}
+}
+
+func TestStore_ScheduledSearchJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	bs := basestore.NewWithHandle(db.Handle())
+
+	_, err := createRepo(db, "repo1")
+	require.NoError(t, err)
+	userID, err := createUser(bs, "alice")
+	require.NoError(t, err)
+
+	s := store.New(db, &observation.TestContext)
+
+	userCtx := actor.WithActor(context.Background(), actor.FromUser(userID))
+	internalCtx := actor.WithInternalActor(context.Background())
+
+	jobID := createJobCascade(t, userCtx, s, stateCascade{
+		searchJob:   types.JobStateCompleted,
+		repoJobs:    []types.JobState{types.JobStateCompleted},
+		repoRevJobs: []types.JobState{types.JobStateCompleted},
+	})
+
+	now := time.Now()
+	require.NoError(t, s.SetSearchJobSchedule(userCtx, jobID, "@daily", now.Add(-time.Minute)))
+
+	// Only the worker may list the jobs of all users.
+	_, err = s.ListDueScheduledSearchJobs(userCtx, now)
+	require.Error(t, err)
+
+	jobs, err := s.ListDueScheduledSearchJobs(internalCtx, now)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "@daily", jobs[0].Schedule)
+
+	require.NoError(t, s.StartScheduledRun(internalCtx, jobID, now.Add(24*time.Hour)))
+
+	// The next run is due tomorrow, and the diff of this run is pending.
+	jobs, err = s.ListDueScheduledSearchJobs(internalCtx, now.Add(48*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+
+	jobs, err = s.ListSearchJobsWithPendingDiff(internalCtx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	require.NoError(t, s.SetResultsDiff(internalCtx, jobID, types.ResultsDiff{Added: 2, Removed: 1, ComputedAt: now}))
+
+	jobs, err = s.ListSearchJobsWithPendingDiff(internalCtx)
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+
+	job, err := s.GetExhaustiveSearchJob(userCtx, jobID)
+	require.NoError(t, err)
+	require.False(t, job.DiffPending)
+	require.Equal(t, int32(2), job.Diff.Added)
+	require.Equal(t, int32(1), job.Diff.Removed)
+
+	// Removing the schedule stops scheduled runs.
+	require.NoError(t, s.SetSearchJobSchedule(userCtx, jobID, "", now))
+	jobs, err = s.ListDueScheduledSearchJobs(internalCtx, now.Add(48*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+}
+
 // createJobCascade creates a cascade of jobs (1 search job -> n repo jobs -> m
 // repo rev jobs) with states as defined in stateCascade.
 //
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
@@ -96,6 +97,29 @@ VALUES (%s, %s, %s)
 RETURNING id
 `
 
+// ListRepoRevSpecs returns the repository revision specifiers of all repo
+// jobs created for the search job searchJobID.
+func (s *Store) ListRepoRevSpecs(ctx context.Context, searchJobID int64) (_ []types.RepositoryRevSpecs, err error) {
+	ctx, _, endObservation := s.operations.listRepoRevSpecs.With(ctx, &err, opAttrs(
+		attribute.Int64("searchJobID", searchJobID),
+	))
+	defer endObservation(1, observation.Args{})
+
+	return scanRepoRevSpecs(s.Query(ctx, sqlf.Sprintf(listRepoRevSpecsFmtStr, searchJobID)))
+}
+
+const listRepoRevSpecsFmtStr = `
+SELECT repo_id, ref_spec
+FROM exhaustive_search_repo_jobs
+WHERE search_job_id = %s
+`
+
+var scanRepoRevSpecs = basestore.NewSliceScanner(func(sc dbutil.Scanner) (types.RepositoryRevSpecs, error) {
+	var r types.RepositoryRevSpecs
+	err := sc.Scan(&r.Repository, &r.RevisionSpecifiers)
+	return r, err
+})
+
 func scanRepoSearchJob(sc dbutil.Scanner) (*types.ExhaustiveSearchRepoJob, error) {
 	var job types.ExhaustiveSearchRepoJob
 	// required field for the sync worker, but
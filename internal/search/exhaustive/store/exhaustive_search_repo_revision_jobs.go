@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -40,6 +42,7 @@ var revSearchJobColumns = []*sqlf.Query{
 	sqlf.Sprintf("state"),
 	sqlf.Sprintf("search_repo_job_id"),
 	sqlf.Sprintf("revision"),
+	sqlf.Sprintf("commit_id"),
 	sqlf.Sprintf("failure_message"),
 	sqlf.Sprintf("started_at"),
 	sqlf.Sprintf("finished_at"),
@@ -89,8 +92,42 @@ VALUES (%s, %s)
 RETURNING id
 `
 
+// ListRevisions returns the revisions of all revision jobs created for the
+// repo job searchRepoJobID.
+func (s *Store) ListRevisions(ctx context.Context, searchRepoJobID int64) (_ []string, err error) {
+	ctx, _, endObservation := s.operations.listRevisions.With(ctx, &err, opAttrs(
+		attribute.Int64("searchRepoJobID", searchRepoJobID),
+	))
+	defer endObservation(1, observation.Args{})
+
+	return basestore.ScanStrings(s.Query(ctx, sqlf.Sprintf(listRevisionsFmtStr, searchRepoJobID)))
+}
+
+const listRevisionsFmtStr = `
+SELECT revision
+FROM exhaustive_search_repo_revision_jobs
+WHERE search_repo_job_id = %s
+`
+
+// SetRepoRevisionJobCommitID records the commit that the revision of job id
+// resolved to when it was searched. An empty commitID clears the column.
+func (s *Store) SetRepoRevisionJobCommitID(ctx context.Context, id int64, commitID api.CommitID) (err error) {
+	ctx, _, endObservation := s.operations.setRepoRevisionJobCommitID.With(ctx, &err, opAttrs(
+		attribute.Int64("ID", id),
+	))
+	defer endObservation(1, observation.Args{})
+
+	return s.Exec(ctx, sqlf.Sprintf(setRepoRevisionJobCommitIDFmtStr, dbutil.NullStringColumn(string(commitID)), id))
+}
+
+const setRepoRevisionJobCommitIDFmtStr = `
+UPDATE exhaustive_search_repo_revision_jobs
+SET commit_id = %s
+WHERE id = %s
+`
+
 const getQueryRepoRevFmtStr = `
-SELECT sj.id, sj.initiator_id, sj.query, srj.repo_id, srj.ref_spec
+SELECT sj.id, sj.initiator_id, sj.query, sj.format, srj.repo_id, srj.ref_spec
 FROM exhaustive_search_repo_jobs srj
 JOIN exhaustive_search_jobs sj ON srj.search_job_id = sj.id
 WHERE srj.id = %s
@@ -99,17 +136,18 @@ WHERE srj.id = %s
 func (s *Store) GetQueryRepoRev(ctx context.Context, job *types.ExhaustiveSearchRepoRevisionJob) (
 	id int64,
 	query string,
+	format types.OutputFormat,
 	repoRev types.RepositoryRevision,
 	initiatorID int32,
 	err error,
 ) {
 	row := s.QueryRow(ctx, sqlf.Sprintf(getQueryRepoRevFmtStr, job.SearchRepoJobID))
-	err = row.Scan(&id, &initiatorID, &query, &repoRev.Repository, &repoRev.RevisionSpecifiers)
+	err = row.Scan(&id, &initiatorID, &query, &format, &repoRev.Repository, &repoRev.RevisionSpecifiers)
 	if err != nil {
-		return 0, "", types.RepositoryRevision{}, -1, err
+		return 0, "", "", types.RepositoryRevision{}, -1, err
 	}
 	repoRev.Revision = job.Revision
-	return id, query, repoRev, initiatorID, nil
+	return id, query, format, repoRev, initiatorID, nil
 }
 
 func scanRevSearchJob(sc dbutil.Scanner) (*types.ExhaustiveSearchRepoRevisionJob, error) {
@@ -123,6 +161,7 @@ func scanRevSearchJob(sc dbutil.Scanner) (*types.ExhaustiveSearchRepoRevisionJob
 		&job.State,
 		&job.SearchRepoJobID,
 		&job.Revision,
+		&dbutil.NullString{S: (*string)(&job.CommitID)},
 		&dbutil.NullString{S: &job.FailureMessage},
 		&dbutil.NullTime{Time: &job.StartedAt},
 		&dbutil.NullTime{Time: &job.FinishedAt},
@@ -65,10 +65,20 @@ type operations struct {
 	userHasAccess             *observation.Operation
 	listExhaustiveSearchJobs  *observation.Operation
 	deleteExhaustiveSearchJob *observation.Operation
+	retrySearchJob            *observation.Operation
+
+	setSearchJobSchedule          *observation.Operation
+	listDueScheduledSearchJobs    *observation.Operation
+	startScheduledRun             *observation.Operation
+	listSearchJobsWithPendingDiff *observation.Operation
+	setResultsDiff                *observation.Operation
 
 	createExhaustiveSearchRepoJob         *observation.Operation
 	createExhaustiveSearchRepoRevisionJob *observation.Operation
 	getAggregateRepoRevState              *observation.Operation
+	listRepoRevSpecs                      *observation.Operation
+	listRevisions                         *observation.Operation
+	setRepoRevisionJobCommitID            *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -98,9 +108,19 @@ func newOperations(observationCtx *observation.Context) *operations {
 		userHasAccess:             op("UserHasAccess"),
 		listExhaustiveSearchJobs:  op("ListExhaustiveSearchJobs"),
 		deleteExhaustiveSearchJob: op("DeleteExhaustiveSearchJob"),
+		retrySearchJob:            op("RetrySearchJob"),
+
+		setSearchJobSchedule:          op("SetSearchJobSchedule"),
+		listDueScheduledSearchJobs:    op("ListDueScheduledSearchJobs"),
+		startScheduledRun:             op("StartScheduledRun"),
+		listSearchJobsWithPendingDiff: op("ListSearchJobsWithPendingDiff"),
+		setResultsDiff:                op("SetResultsDiff"),
 
 		createExhaustiveSearchRepoJob:         op("CreateExhaustiveSearchRepoJob"),
 		createExhaustiveSearchRepoRevisionJob: op("CreateExhaustiveSearchRepoRevisionJob"),
 		getAggregateRepoRevState:              op("GetAggregateRepoRevState"),
+		listRepoRevSpecs:                      op("ListRepoRevSpecs"),
+		listRevisions:                         op("ListRevisions"),
+		setRepoRevisionJobCommitID:            op("SetRepoRevisionJobCommitID"),
 	}
 }
@@ -2,7 +2,12 @@ package types
 
 import (
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/hashicorp/cronexpr"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 // ExhaustiveSearchJob is a job that runs the exhaustive search.
@@ -18,6 +23,26 @@ type ExhaustiveSearchJob struct {
 
 	Query string
 
+	// Format is the format the results of the job are written in.
+	Format OutputFormat
+
+	// Schedule is a cron expression. If set, the job is run again on this
+	// schedule, see ParseSchedule.
+	Schedule string
+
+	// NextRunAt is when the job is run again next. It is only set if
+	// Schedule is set.
+	NextRunAt time.Time
+
+	// DiffPending is true while a scheduled run is in progress. Once the run
+	// finished, its results are compared to the results of the previous run
+	// and Diff is updated.
+	DiffPending bool
+
+	// Diff compares the results of the latest scheduled run to the results
+	// of the run before.
+	Diff ResultsDiff
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
@@ -34,3 +59,101 @@ func (j *ExhaustiveSearchJob) RecordID() int {
 func (j *ExhaustiveSearchJob) RecordUID() string {
 	return strconv.FormatInt(j.ID, 10)
 }
+
+// ResultsDiff summarizes the matches which were added and removed between
+// two runs of a scheduled ExhaustiveSearchJob.
+type ResultsDiff struct {
+	Added   int32
+	Removed int32
+
+	// ComputedAt is zero if no diff was computed yet.
+	ComputedAt time.Time
+}
+
+// MinScheduleInterval is the shortest allowed time between two scheduled runs
+// of an ExhaustiveSearchJob.
+const MinScheduleInterval = time.Hour
+
+// ParseSchedule parses the cron expression s. Schedules which run a job more
+// often than MinScheduleInterval are rejected.
+func ParseSchedule(s string) (*cronexpr.Expression, error) {
+	expr, err := cronexpr.Parse(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid schedule %q", s)
+	}
+
+	next := expr.NextN(time.Now(), 10)
+	if len(next) == 0 {
+		return nil, errors.Errorf("schedule %q never runs", s)
+	}
+	for i := 1; i < len(next); i++ {
+		if next[i].Sub(next[i-1]) < MinScheduleInterval {
+			return nil, errors.Errorf("schedule %q runs more often than every %s", s, MinScheduleInterval)
+		}
+	}
+	return expr, nil
+}
+
+// OutputFormat is the format the results of an ExhaustiveSearchJob are
+// written in. It is chosen when the job is created.
+type OutputFormat string
+
+const (
+	// OutputFormatCSV writes a header row followed by a row per match.
+	OutputFormatCSV OutputFormat = "csv"
+
+	// OutputFormatJSONLines writes a JSON object per match. The keys of each
+	// object are the column names of the CSV header.
+	OutputFormatJSONLines OutputFormat = "jsonl"
+)
+
+// FileExtension returns the extension, without a leading dot, used for files
+// in format f.
+func (f OutputFormat) FileExtension() string {
+	if f == OutputFormatJSONLines {
+		return "jsonl"
+	}
+	return "csv"
+}
+
+// ParseOutputFormat returns the OutputFormat for s. An empty s defaults to
+// OutputFormatCSV.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch f := OutputFormat(strings.ToLower(s)); f {
+	case "":
+		return OutputFormatCSV, nil
+	case OutputFormatCSV, OutputFormatJSONLines:
+		return f, nil
+	default:
+		return "", errors.Errorf("unsupported search job output format %q", s)
+	}
+}
+
+// RetryMode controls which parts of a finished ExhaustiveSearchJob are run
+// again when it is retried. Results of the parts which are not run again are
+// kept.
+type RetryMode string
+
+const (
+	// RetryModeFailed runs the failed and canceled parts of a job again.
+	RetryModeFailed RetryMode = "failed"
+
+	// RetryModeChanged runs a job again on repositories that changed since
+	// the last run. Repository revisions which still resolve to the same
+	// commit keep their results. Repositories and revisions which match the
+	// query now, but did not match on the last run, are searched as well.
+	RetryModeChanged RetryMode = "changed"
+)
+
+// ParseRetryMode returns the RetryMode for s. An empty s defaults to
+// RetryModeFailed.
+func ParseRetryMode(s string) (RetryMode, error) {
+	switch m := RetryMode(strings.ToLower(s)); m {
+	case "":
+		return RetryModeFailed, nil
+	case RetryModeFailed, RetryModeChanged:
+		return m, nil
+	default:
+		return "", errors.Errorf("unsupported search job retry mode %q", s)
+	}
+}
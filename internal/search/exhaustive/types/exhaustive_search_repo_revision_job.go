@@ -17,6 +17,12 @@ type ExhaustiveSearchRepoRevisionJob struct {
 	SearchRepoJobID int64
 	Revision        string
 
+	// CommitID is the commit Revision resolved to the last time the job
+	// completed. It is empty if the job never completed. When a search job is
+	// rerun, the job reuses its stored results if Revision still resolves to
+	// CommitID.
+	CommitID api.CommitID
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -30,6 +31,17 @@ func (sp SelectPath) Root() string {
 	return ""
 }
 
+// DirectoryDepth returns N for select:file.rollup.N, which rolls up file
+// results to their directory at most N levels deep. It returns 0 if there is
+// no depth, in which case file results roll up to their parent directory.
+func (sp SelectPath) DirectoryDepth() int {
+	if len(sp) != 3 || sp[0] != File || sp[1] != "rollup" {
+		return 0
+	}
+	depth, _ := strconv.Atoi(sp[2]) // Invariant: validated by SelectPathFromString.
+	return depth
+}
+
 type object map[string]object
 
 var validSelectors = object{
@@ -44,6 +56,7 @@ var validSelectors = object{
 		"directory": nil,
 		"path":      nil,
 		"owners":    nil,
+		"rollup":    nil,
 	},
 	Repository: nil,
 	Symbol: object{
@@ -80,9 +93,15 @@ var validSelectors = object{
 func SelectPathFromString(s string) (SelectPath, error) {
 	fields := strings.Split(s, ".")
 	cur := validSelectors
-	for _, field := range fields {
+	for i, field := range fields {
 		child, ok := cur[field]
 		if !ok {
+			if i == 2 && i == len(fields)-1 && fields[0] == File && fields[1] == "rollup" {
+				if depth, err := strconv.Atoi(field); err != nil || depth < 1 {
+					return SelectPath{}, errors.Errorf("invalid directory depth %q on select path %q, expected a positive number", field, s)
+				}
+				continue
+			}
 			return SelectPath{}, errors.Errorf("invalid field %q on select path %q", field, s)
 		}
 		cur = child
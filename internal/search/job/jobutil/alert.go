@@ -9,6 +9,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	searchalert "github.com/sourcegraph/sourcegraph/internal/search/alert"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -86,7 +87,9 @@ func (j *alertJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 		fallthrough
 	case job.VerbosityBasic:
 		res = append(res,
-			attribute.Stringer("query", j.inputs.Query),
+			// query is the query after search macros were expanded, in the
+			// syntax users type.
+			attribute.String("query", query.StringHuman(j.inputs.Query)),
 			attribute.String("originalQuery", j.inputs.OriginalQuery),
 			attribute.Stringer("patternType", j.inputs.PatternType),
 		)
@@ -3,7 +3,10 @@ package jobutil
 import (
 	"context"
 
+	"github.com/sourcegraph/sourcegraph/internal/authz"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/commit"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/repos"
@@ -17,6 +20,9 @@ import (
 // differentiate ourselves from the infrastructure.
 type Exhaustive struct {
 	repoPagerJob *repoPagerJob
+
+	// selectPath is the value of select: in the query, if any.
+	selectPath filter.SelectPath
 }
 
 // NewExhaustive constructs Exhaustive from the search inputs.
@@ -27,11 +33,16 @@ func NewExhaustive(inputs *search.Inputs) (Exhaustive, error) {
 		return Exhaustive{}, errors.New("only works for exhaustive search inputs")
 	}
 
-	// This doesn't lead to an error, but we will drop result types other than
-	// "file" which might be surprising to users.
+	// The results of a search job are written to files with a single header,
+	// so they all need to be of the same type.
 	types, _ := inputs.Query.StringValues(query.FieldType)
-	if len(types) != 1 || types[0] != "file" {
-		return Exhaustive{}, errors.Errorf("expected \"type:file\" only. Got %v", types)
+	if len(types) != 1 {
+		return Exhaustive{}, errors.Errorf("expected a single type filter. Got %v", types)
+	}
+	switch types[0] {
+	case "file", "path", "symbol", "commit", "diff", "repo":
+	default:
+		return Exhaustive{}, errors.Errorf("type:%s is not supported", types[0])
 	}
 
 	if len(inputs.Plan) != 1 {
@@ -55,14 +66,32 @@ func NewExhaustive(inputs *search.Inputs) (Exhaustive, error) {
 		return Exhaustive{}, errors.Errorf("file predicates are not supported. Got %v", pred)
 	}
 
+	// Search Jobs search each repository revision on its own, so there is
+	// nothing to compare the results with.
+	if _, _, ok := query.SplitRevisionCompare(b); ok {
+		return Exhaustive{}, errors.New("comparing revisions is not supported")
+	}
+
 	// This is a very weak protection but should be enough to catch simple misuse.
 	if inputs.PatternType == query.SearchTypeRegex && term.Value == ".*" {
 		return Exhaustive{}, errors.Errorf("regex search with .* is not supported")
 	}
 
-	planJob, err := NewFlatJob(inputs, query.Flat{Parameters: b.Parameters, Pattern: &term})
-	if err != nil {
-		return Exhaustive{}, err
+	var planJob job.Job
+	var err error
+	switch types[0] {
+	case "commit", "diff":
+		planJob = newExhaustiveCommitJob(inputs, b, types[0] == "diff")
+	case "repo":
+		planJob, err = newExhaustiveRepoJob(inputs, b)
+		if err != nil {
+			return Exhaustive{}, err
+		}
+	default:
+		planJob, err = NewFlatJob(inputs, query.Flat{Parameters: b.Parameters, Pattern: &term})
+		if err != nil {
+			return Exhaustive{}, err
+		}
 	}
 
 	repoPagerJob, ok := planJob.(*repoPagerJob)
@@ -70,8 +99,51 @@ func NewExhaustive(inputs *search.Inputs) (Exhaustive, error) {
 		return Exhaustive{}, errors.Errorf("internal error: expected a repo pager job when converting plan into search jobs got %T", planJob)
 	}
 
+	var selectPath filter.SelectPath
+	if v := b.FindValue(query.FieldSelect); v != "" {
+		selectPath, _ = filter.SelectPathFromString(v) // Invariant: select already validated
+	}
+
 	return Exhaustive{
 		repoPagerJob: repoPagerJob,
+		selectPath:   selectPath,
+	}, nil
+}
+
+// newExhaustiveCommitJob returns a repo pager job for commit or diff search.
+// Unlike for interactive search, the commit search job is only run over the
+// repository revisions it is given.
+func newExhaustiveCommitJob(inputs *search.Inputs, b query.Basic, diff bool) *repoPagerJob {
+	repoOptions := toRepoOptions(b, inputs.UserSettings)
+	repoOptions.OnlyCloned = true
+	containsRefGlobs := query.ContainsRefGlobs(b.ToParseTree())
+	return &repoPagerJob{
+		child: &reposPartialJob{&commit.SearchJob{
+			Query:                commit.QueryToGitQuery(b, diff),
+			RepoOpts:             repoOptions,
+			Diff:                 diff,
+			Limit:                computeFileMatchLimit(b, inputs.DefaultLimit()),
+			IncludeModifiedFiles: authz.SubRepoEnabled(authz.DefaultSubRepoPermsChecker),
+			ContainsRefGlobs:     containsRefGlobs,
+		}},
+		repoOpts:         repoOptions,
+		containsRefGlobs: containsRefGlobs,
+	}
+}
+
+// newExhaustiveRepoJob returns a repo pager job for repository search. The
+// pattern is matched against the repository names when the repositories are
+// resolved, so each repository revision the job is given is a match.
+func newExhaustiveRepoJob(inputs *search.Inputs, b query.Basic) (*repoPagerJob, error) {
+	repoOptions := toRepoOptions(b, inputs.UserSettings)
+	repoJob, ok := newRepoSearchJob(b, repoOptions)
+	if !ok {
+		return nil, errors.Errorf("query is not supported for type:repo. Got %v", b)
+	}
+	return &repoPagerJob{
+		child:            &reposPartialJob{repoJob},
+		repoOpts:         repoJob.RepoOpts,
+		containsRefGlobs: query.ContainsRefGlobs(b.ToParseTree()),
 	}, nil
 }
 
@@ -89,9 +161,13 @@ func hasPredicates(field string, q query.Q) (pred string, ok bool) {
 func (e Exhaustive) Job(repoRevs *search.RepositoryRevisions) job.Job {
 	// TODO should we add in a timeout and limit here?
 	// TODO should we support indexed search and run through zoekt.PartitionRepos?
-	return e.repoPagerJob.child.Resolve(resolvedRepos{
+	j := e.repoPagerJob.child.Resolve(resolvedRepos{
 		unindexed: []*search.RepositoryRevisions{repoRevs},
 	})
+	if len(e.selectPath) > 0 {
+		j = newSelectJob(e.selectPath, j)
+	}
+	return j
 }
 
 // RepositoryRevSpecs is a wrapper around repos.Resolver.IterateRepoRevs.
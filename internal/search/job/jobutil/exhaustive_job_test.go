@@ -156,6 +156,142 @@ func TestNewExhaustive(t *testing.T) {
   (numRepos . 1)
   (pathRegexps . [])
   (indexed . false))
+`),
+		},
+		{
+			Name:  "symbol",
+			Query: "type:symbol index:no foo",
+			WantPager: autogold.Expect(`
+(REPOPAGER
+  (containsRefGlobs . false)
+  (repoOpts.useIndex . no)
+  (PARTIALREPOS
+    (SEARCHERSYMBOLSEARCH
+      (patternInfo.pattern . foo)
+      (patternInfo.isRegexp . true)
+      (patternInfo.fileMatchLimit . 1000000)
+      (patternInfo.index . no)
+      (numRepos . 0)
+      (limit . 1000000))))
+`),
+			WantJob: autogold.Expect(`
+(SEARCHERSYMBOLSEARCH
+  (patternInfo.pattern . foo)
+  (patternInfo.isRegexp . true)
+  (patternInfo.fileMatchLimit . 1000000)
+  (patternInfo.index . no)
+  (numRepos . 1)
+  (limit . 1000000))
+`),
+		},
+		{
+			Name:  "commit",
+			Query: "type:commit index:no repo:foo author:alice foo",
+			WantPager: autogold.Expect(`
+(REPOPAGER
+  (containsRefGlobs . false)
+  (repoOpts.repoFilters . [foo])
+  (repoOpts.useIndex . no)
+  (repoOpts.onlyCloned . true)
+  (PARTIALREPOS
+    (COMMITSEARCH
+      (includeModifiedFiles . false)
+      (containsRefGlobs . false)
+      (query . (*protocol.AuthorMatches(alice) AND *protocol.MessageMatches(foo)))
+      (diff . false)
+      (limit . 1000000)
+      (repoOpts.repoFilters . [foo])
+      (repoOpts.useIndex . no)
+      (repoOpts.onlyCloned . true))))
+`),
+			WantJob: autogold.Expect(`
+(COMMITSEARCH
+  (includeModifiedFiles . false)
+  (containsRefGlobs . false)
+  (query . (*protocol.AuthorMatches(alice) AND *protocol.MessageMatches(foo)))
+  (diff . false)
+  (limit . 1000000)
+  (repoOpts.repoFilters . [foo])
+  (repoOpts.useIndex . no)
+  (repoOpts.onlyCloned . true)
+  (numRepos . 1))
+`),
+		},
+		{
+			Name:  "diff",
+			Query: "type:diff index:no foo",
+			WantPager: autogold.Expect(`
+(REPOPAGER
+  (containsRefGlobs . false)
+  (repoOpts.useIndex . no)
+  (repoOpts.onlyCloned . true)
+  (PARTIALREPOS
+    (DIFFSEARCH
+      (includeModifiedFiles . false)
+      (containsRefGlobs . false)
+      (query . *protocol.DiffMatches(foo))
+      (diff . true)
+      (limit . 1000000)
+      (repoOpts.useIndex . no)
+      (repoOpts.onlyCloned . true))))
+`),
+			WantJob: autogold.Expect(`
+(DIFFSEARCH
+  (includeModifiedFiles . false)
+  (containsRefGlobs . false)
+  (query . *protocol.DiffMatches(foo))
+  (diff . true)
+  (limit . 1000000)
+  (repoOpts.useIndex . no)
+  (repoOpts.onlyCloned . true)
+  (numRepos . 1))
+`),
+		},
+		{
+			Name:  "repo",
+			Query: "type:repo index:no repo:^github\\.com/ foo",
+			WantPager: autogold.Expect(`
+(REPOPAGER
+  (containsRefGlobs . false)
+  (repoOpts.repoFilters . [^github\.com/ foo])
+  (repoOpts.useIndex . no)
+  (PARTIALREPOS
+    (REPOSEARCH
+      (repoOpts.repoFilters . [^github\.com/ foo])
+      (repoOpts.useIndex . no)
+      (repoNamePatterns . [(?i)^github\.com/ (?i)foo]))))
+`),
+			WantJob: autogold.Expect(`
+(REPOSEARCH
+  (repoOpts.repoFilters . [^github\.com/ foo])
+  (repoOpts.useIndex . no)
+  (repoNamePatterns . [(?i)^github\.com/ (?i)foo])
+  (numRepos . 1))
+`),
+		},
+		{
+			Name:  "owner",
+			Query: "type:file index:no select:file.owners foo",
+			WantPager: autogold.Expect(`
+(REPOPAGER
+  (containsRefGlobs . false)
+  (repoOpts.useIndex . no)
+  (PARTIALREPOS
+    (SEARCHERTEXTSEARCH
+      (useFullDeadline . true)
+      (patternInfo . TextPatternInfo{"foo",re,nopath,filematchlimit:1000000})
+      (numRepos . 0)
+      (pathRegexps . [])
+      (indexed . false))))
+`),
+			WantJob: autogold.Expect(`
+(SELECTOWNERSSEARCH
+  (SEARCHERTEXTSEARCH
+    (useFullDeadline . true)
+    (patternInfo . TextPatternInfo{"foo",re,nopath,filematchlimit:1000000})
+    (numRepos . 1)
+    (pathRegexps . [])
+    (indexed . false)))
 `),
 		},
 	}
@@ -202,6 +338,8 @@ func TestNewExhaustive_negative(t *testing.T) {
 		// >1 type filter.
 		{query: `type:file index:no type:diff content`},
 		{query: `type:file index:no type:path content`},
+		// repo search with fields that don't apply to repositories
+		{query: `type:repo index:no file:foo content`},
 		// AND, OR
 		{query: `type:file index:no repo:repo1 rev:branch1 content1 OR content2`},
 		{query: `type:file index:no repo:repo1 rev:branch1 content1 AND content2`},
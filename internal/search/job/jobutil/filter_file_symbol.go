@@ -0,0 +1,369 @@
+package jobutil
+
+import (
+	"context"
+	"regexp/syntax" //nolint:depguard // using the grafana fork of regexp clashes with zoekt, which uses the std regexp/syntax.
+	"strings"
+	"sync"
+
+	"github.com/grafana/regexp"
+	"github.com/sourcegraph/zoekt"
+	zoektquery "github.com/sourcegraph/zoekt/query"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/symbols"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// fileSymbolsLimit is the maximum number of symbols we ask the symbols
+// service for when checking the files of a single repository commit. If the
+// limit is hit, files may be filtered incorrectly and the search reports
+// that it hit a limit.
+const fileSymbolsLimit = 10000
+
+// NewFileHasSymbolFilterJob creates a filter job to post-filter results for
+// the file:has.symbol() predicate.
+//
+// has.symbol() predicates are grouped together by inclusivity vs. exclusivity
+// before being passed to the constructor. A file is kept only if it defines a
+// matching symbol for every include predicate and for none of the exclude
+// predicates.
+//
+// Symbols are looked up per repository commit. Indexed files are checked
+// against Zoekt's symbol index first. Any file that Zoekt does not confirm
+// (because it is not indexed at that commit, or because it does not define a
+// matching symbol) is checked against the symbols service. If the symbols
+// service returns fileSymbolsLimit symbols for a repository commit, the
+// filtered event is marked with IsLimitHit.
+func NewFileHasSymbolFilterJob(include, exclude []query.FileHasSymbolPredicate, caseSensitive bool, child job.Job) (job.Job, error) {
+	compile := func(preds []query.FileHasSymbolPredicate) ([]symbolFilter, error) {
+		filters := make([]symbolFilter, 0, len(preds))
+		for _, pred := range preds {
+			f := symbolFilter{kind: pred.Kind}
+			if pred.Pattern != "" {
+				pattern := pred.Pattern
+				if !caseSensitive {
+					pattern = "(?i:" + pattern + ")"
+				}
+				re, err := regexp.Compile(pattern)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to regexp.Compile(%q) for file:has.symbol()", pattern)
+				}
+				f.name = re
+			}
+			filters = append(filters, f)
+		}
+		return filters, nil
+	}
+
+	includeFilters, err := compile(include)
+	if err != nil {
+		return nil, err
+	}
+	excludeFilters, err := compile(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileHasSymbolFilterJob{
+		child:         child,
+		include:       includeFilters,
+		exclude:       excludeFilters,
+		searchSymbols: symbols.DefaultClient.Search,
+		symbolsLimit:  fileSymbolsLimit,
+	}, nil
+}
+
+type fileHasSymbolFilterJob struct {
+	child job.Job
+
+	include []symbolFilter
+	exclude []symbolFilter
+
+	// searchSymbols queries the symbols service. It is overridden in tests.
+	searchSymbols func(context.Context, search.SymbolsParameters) (result.Symbols, error)
+	// symbolsLimit is the maximum number of symbols requested from the
+	// symbols service per repository commit.
+	symbolsLimit int
+}
+
+// symbolFilter matches a symbol by name and, if set, by kind. kind is a
+// normalized select kind, such as "struct" or "function".
+type symbolFilter struct {
+	name *regexp.Regexp
+	kind string
+}
+
+func (f symbolFilter) String() string {
+	var parts []string
+	if f.name != nil {
+		parts = append(parts, "name:"+f.name.String())
+	}
+	if f.kind != "" {
+		parts = append(parts, "kind:"+f.kind)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f symbolFilter) matches(s result.Symbol) bool {
+	if f.kind != "" && result.ToSelectKind[strings.ToLower(s.Kind)] != f.kind {
+		return false
+	}
+	return f.name == nil || f.name.MatchString(s.Name)
+}
+
+func (j *fileHasSymbolFilterJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu   sync.Mutex
+		errs error
+	)
+
+	filteredStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		filtered, limitHit, err := j.filterEvent(ctx, clients.Zoekt, event)
+		if err != nil {
+			mu.Lock()
+			errs = errors.Append(errs, err)
+			mu.Unlock()
+		}
+		event.Results = filtered
+		if limitHit {
+			event.Stats.IsLimitHit = true
+		}
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, filteredStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+	return alert, errs
+}
+
+// repoCommit identifies the set of files whose symbols can be looked up with
+// a single request.
+type repoCommit struct {
+	repo   api.RepoID
+	commit api.CommitID
+}
+
+// filterEvent returns the file matches of event that pass the job's filters.
+// limitHit is true if the symbols of some repository commit were truncated.
+func (j *fileHasSymbolFilterJob) filterEvent(ctx context.Context, zoektClient zoekt.Streamer, event streaming.SearchEvent) (_ result.Matches, limitHit bool, _ error) {
+	groups := make(map[repoCommit][]*result.FileMatch)
+	for _, res := range event.Results {
+		if fm, ok := res.(*result.FileMatch); ok {
+			key := repoCommit{repo: fm.Repo.ID, commit: fm.CommitID}
+			groups[key] = append(groups[key], fm)
+		}
+	}
+
+	var errs error
+	keep := make(map[*result.FileMatch]bool)
+	for _, fms := range groups {
+		symbolsByPath, truncated, err := j.fileSymbols(ctx, zoektClient, fms)
+		if err != nil {
+			// Drop any files whose symbols we could not determine
+			errs = errors.Append(errs, err)
+			continue
+		}
+		limitHit = limitHit || truncated
+		for _, fm := range fms {
+			keep[fm] = j.passes(symbolsByPath[fm.Path])
+		}
+	}
+
+	// Filter out any result that is not a file
+	filtered := event.Results[:0]
+	for _, res := range event.Results {
+		if fm, ok := res.(*result.FileMatch); ok && keep[fm] {
+			filtered = append(filtered, fm)
+		}
+	}
+	return filtered, limitHit, errs
+}
+
+// passes returns true if the symbols defined in a file satisfy every include
+// filter and no exclude filter.
+func (j *fileHasSymbolFilterJob) passes(fileSymbols []result.Symbol) bool {
+	anyMatches := func(f symbolFilter) bool {
+		for _, s := range fileSymbols {
+			if f.matches(s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, f := range j.include {
+		if !anyMatches(f) {
+			return false
+		}
+	}
+	for _, f := range j.exclude {
+		if anyMatches(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// fileSymbols returns the symbols matching any of the job's filters for a set
+// of file matches, which must all belong to the same repository commit.
+// Symbols are keyed by path. truncated is true if the symbols service returned
+// as many symbols as we asked for, in which case some may be missing.
+func (j *fileHasSymbolFilterJob) fileSymbols(ctx context.Context, zoektClient zoekt.Streamer, fms []*result.FileMatch) (_ map[string][]result.Symbol, truncated bool, _ error) {
+	repo, commitID := fms[0].Repo, fms[0].CommitID
+	namesPattern := symbolNamesPattern(append(append([]symbolFilter{}, j.include...), j.exclude...))
+
+	paths := make([]string, 0, len(fms))
+	for _, fm := range fms {
+		paths = append(paths, fm.Path)
+	}
+
+	symbolsByPath := make(map[string][]result.Symbol, len(fms))
+	// Zoekt can only confirm files that define a symbol with a matching name,
+	// so we skip it if a filter only constrains the symbol kind.
+	if zoektClient != nil && namesPattern != "" {
+		q, err := zoektSymbolQuery(repo.ID, paths, namesPattern)
+		if err != nil {
+			return nil, false, err
+		}
+		res, err := zoektClient.Search(ctx, q, &zoekt.SearchOptions{ChunkMatches: true})
+		if err != nil {
+			return nil, false, err
+		}
+		for _, file := range res.Files {
+			if api.CommitID(file.Version) != commitID {
+				// Zoekt indexed a different commit than the one we matched
+				continue
+			}
+			for _, sm := range zoektFileMatchToSymbols(file) {
+				symbolsByPath[sm.Path] = append(symbolsByPath[sm.Path], sm)
+			}
+		}
+	}
+
+	// Ask the symbols service about all the files Zoekt could not confirm.
+	var unconfirmed []string
+	for _, path := range paths {
+		if _, ok := symbolsByPath[path]; !ok {
+			unconfirmed = append(unconfirmed, regexp.QuoteMeta(path))
+		}
+	}
+	if len(unconfirmed) == 0 {
+		return symbolsByPath, false, nil
+	}
+
+	syms, err := j.searchSymbols(ctx, search.SymbolsParameters{
+		Repo:            repo.Name,
+		CommitID:        commitID,
+		Query:           namesPattern,
+		IsRegExp:        true,
+		IsCaseSensitive: true, // case sensitivity is encoded in the filters
+		IncludePatterns: []string{"^(?:" + strings.Join(unconfirmed, "|") + ")$"},
+		First:           j.symbolsLimit,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	for _, s := range syms {
+		symbolsByPath[s.Path] = append(symbolsByPath[s.Path], s)
+	}
+	return symbolsByPath, len(syms) >= j.symbolsLimit, nil
+}
+
+// symbolNamesPattern returns a regular expression matching the union of the
+// symbol names of filters. It matches everything if any filter only
+// constrains the kind.
+func symbolNamesPattern(filters []symbolFilter) string {
+	names := make([]string, 0, len(filters))
+	for _, f := range filters {
+		if f.name == nil {
+			return ""
+		}
+		names = append(names, f.name.String())
+	}
+	return query.UnionRegExps(names)
+}
+
+func zoektSymbolQuery(repoID api.RepoID, paths []string, namesPattern string) (zoektquery.Q, error) {
+	re, err := syntax.Parse(namesPattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return zoektquery.NewAnd(
+		zoektquery.NewRepoIDs(uint32(repoID)),
+		zoektquery.NewFileNameSet(paths...),
+		&zoektquery.Symbol{Expr: &zoektquery.Regexp{
+			Regexp:        re,
+			CaseSensitive: true,
+			Content:       true,
+		}},
+	), nil
+}
+
+func zoektFileMatchToSymbols(file zoekt.FileMatch) []result.Symbol {
+	var syms []result.Symbol
+	for _, cm := range file.ChunkMatches {
+		if cm.FileName {
+			continue
+		}
+		for _, si := range cm.SymbolInfo {
+			if si == nil {
+				continue
+			}
+			syms = append(syms, result.Symbol{
+				Name:       si.Sym,
+				Kind:       si.Kind,
+				Parent:     si.Parent,
+				ParentKind: si.ParentKind,
+				Path:       file.FileName,
+				Language:   file.Language,
+			})
+		}
+	}
+	return syms
+}
+
+func (j *fileHasSymbolFilterJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+func (j *fileHasSymbolFilterJob) Name() string {
+	return "FileHasSymbolFilterJob"
+}
+
+func (j *fileHasSymbolFilterJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *fileHasSymbolFilterJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		toStrings := func(filters []symbolFilter) []string {
+			strs := make([]string, 0, len(filters))
+			for _, f := range filters {
+				strs = append(strs, f.String())
+			}
+			return strs
+		}
+		res = append(res,
+			attribute.StringSlice("includeSymbols", toStrings(j.include)),
+			attribute.StringSlice("excludeSymbols", toStrings(j.exclude)),
+		)
+	}
+	return res
+}
@@ -0,0 +1,164 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/zoekt"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestFileHasSymbolFilterJob(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "repo"}
+
+	fm := func(path string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{
+				Repo:     repo,
+				Path:     path,
+				CommitID: "commitID",
+			},
+		}
+	}
+
+	sym := func(path, name, kind string) result.Symbol {
+		return result.Symbol{Path: path, Name: name, Kind: kind}
+	}
+
+	tests := []struct {
+		name          string
+		caseSensitive bool
+		include       []query.FileHasSymbolPredicate
+		exclude       []query.FileHasSymbolPredicate
+		matches       result.Matches
+		symbols       result.Symbols
+		zoektFiles    []zoekt.FileMatch
+		symbolsLimit  int
+		output        []string
+		limitHit      bool
+	}{{
+		name:    "include by name",
+		include: []query.FileHasSymbolPredicate{{Pattern: "Resolver$"}},
+		matches: result.Matches{fm("a.go"), fm("b.go")},
+		symbols: result.Symbols{sym("a.go", "repoResolver", "struct")},
+		output:  []string{"a.go"},
+	}, {
+		name:    "include by name and kind",
+		include: []query.FileHasSymbolPredicate{{Pattern: "Store$", Kind: "struct"}},
+		matches: result.Matches{fm("a.go"), fm("b.go")},
+		symbols: result.Symbols{sym("a.go", "NewStore", "func"), sym("b.go", "store", "struct")},
+		output:  []string{"b.go"},
+	}, {
+		name:    "include by kind only",
+		include: []query.FileHasSymbolPredicate{{Kind: "interface"}},
+		matches: result.Matches{fm("a.go"), fm("b.go")},
+		symbols: result.Symbols{sym("a.go", "Store", "interface"), sym("b.go", "store", "struct")},
+		output:  []string{"a.go"},
+	}, {
+		name:    "every include must match",
+		include: []query.FileHasSymbolPredicate{{Pattern: "^New"}, {Pattern: "Store$"}},
+		matches: result.Matches{fm("a.go"), fm("b.go")},
+		symbols: result.Symbols{sym("a.go", "NewStore", "func"), sym("b.go", "NewResolver", "func")},
+		output:  []string{"a.go"},
+	}, {
+		name:    "exclude",
+		exclude: []query.FileHasSymbolPredicate{{Pattern: "^Test"}},
+		matches: result.Matches{fm("a.go"), fm("a_test.go")},
+		symbols: result.Symbols{sym("a.go", "New", "func"), sym("a_test.go", "TestNew", "func")},
+		output:  []string{"a.go"},
+	}, {
+		name:          "case sensitive",
+		caseSensitive: true,
+		include:       []query.FileHasSymbolPredicate{{Pattern: "Store"}},
+		matches:       result.Matches{fm("a.go"), fm("b.go")},
+		symbols:       result.Symbols{sym("a.go", "Store", "struct"), sym("b.go", "store", "struct")},
+		output:        []string{"a.go"},
+	}, {
+		name:    "confirmed by zoekt",
+		include: []query.FileHasSymbolPredicate{{Pattern: "Resolver$"}},
+		matches: result.Matches{fm("a.go"), fm("b.go")},
+		zoektFiles: []zoekt.FileMatch{{
+			FileName: "b.go",
+			Version:  "commitID",
+			ChunkMatches: []zoekt.ChunkMatch{{
+				Ranges:     []zoekt.Range{{}},
+				SymbolInfo: []*zoekt.Symbol{{Sym: "repoResolver", Kind: "struct"}},
+			}},
+		}},
+		output: []string{"b.go"},
+	}, {
+		name:    "zoekt at a different commit is ignored",
+		include: []query.FileHasSymbolPredicate{{Pattern: "Resolver$"}},
+		matches: result.Matches{fm("a.go")},
+		zoektFiles: []zoekt.FileMatch{{
+			FileName: "a.go",
+			Version:  "otherCommitID",
+			ChunkMatches: []zoekt.ChunkMatch{{
+				Ranges:     []zoekt.Range{{}},
+				SymbolInfo: []*zoekt.Symbol{{Sym: "repoResolver", Kind: "struct"}},
+			}},
+		}},
+		output: []string{},
+	}, {
+		name:    "non-file matches are dropped",
+		include: []query.FileHasSymbolPredicate{{Pattern: "Resolver$"}},
+		matches: result.Matches{&result.CommitMatch{}, fm("a.go")},
+		symbols: result.Symbols{sym("a.go", "repoResolver", "struct")},
+		output:  []string{"a.go"},
+	}, {
+		name:         "symbols limit hit",
+		include:      []query.FileHasSymbolPredicate{{Pattern: "Resolver$"}},
+		matches:      result.Matches{fm("a.go"), fm("b.go")},
+		symbols:      result.Symbols{sym("a.go", "repoResolver", "struct")},
+		symbolsLimit: 1,
+		output:       []string{"a.go"},
+		limitHit:     true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			childJob := mockjob.NewMockJob()
+			childJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+				s.Send(streaming.SearchEvent{Results: tc.matches})
+				return nil, nil
+			})
+
+			j, err := NewFileHasSymbolFilterJob(tc.include, tc.exclude, tc.caseSensitive, childJob)
+			require.NoError(t, err)
+			j.(*fileHasSymbolFilterJob).searchSymbols = func(_ context.Context, args search.SymbolsParameters) (result.Symbols, error) {
+				return tc.symbols, nil
+			}
+			if tc.symbolsLimit > 0 {
+				j.(*fileHasSymbolFilterJob).symbolsLimit = tc.symbolsLimit
+			}
+
+			zoektClient := &backend.FakeStreamer{Results: []*zoekt.SearchResult{{Files: tc.zoektFiles}}}
+
+			var (
+				paths    []string
+				limitHit bool
+			)
+			stream := streaming.StreamFunc(func(ev streaming.SearchEvent) {
+				for _, m := range ev.Results {
+					paths = append(paths, m.(*result.FileMatch).Path)
+				}
+				limitHit = limitHit || ev.Stats.IsLimitHit
+			})
+
+			alert, err := j.Run(context.Background(), job.RuntimeClients{Zoekt: zoektClient}, stream)
+			require.Nil(t, alert)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.output, paths)
+			require.Equal(t, tc.limitHit, limitHit)
+		})
+	}
+}
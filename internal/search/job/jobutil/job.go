@@ -69,6 +69,21 @@ func NewPlanJob(inputs *search.Inputs, plan query.Plan) (job.Job, error) {
 
 // NewBasicJob converts a query.Basic into its job tree representation.
 func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
+	// rev:base...head runs the search at both revisions and only returns
+	// the matches which changed between them. The comparison needs all
+	// matches at both revisions, so the searches are run with count:all.
+	if base, head, ok := query.SplitRevisionCompare(b); ok {
+		baseJob, err := NewBasicJob(inputs, base.WithCount(query.CountAllLimit))
+		if err != nil {
+			return nil, err
+		}
+		headJob, err := NewBasicJob(inputs, head.WithCount(query.CountAllLimit))
+		if err != nil {
+			return nil, err
+		}
+		return NewRevisionCompareJob(baseJob, headJob), nil
+	}
+
 	var children []job.Job
 	addJob := func(j job.Job) {
 		children = append(children, j)
@@ -166,6 +181,7 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 				Diff:                 diff,
 				Limit:                int(fileMatchLimit),
 				IncludeModifiedFiles: authz.SubRepoEnabled(authz.DefaultSubRepoPermsChecker) || own,
+				ContainsRefGlobs:     query.ContainsRefGlobs(b.ToParseTree()),
 			})
 		}
 
@@ -211,6 +227,16 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		}
 	}
 
+	{ // Apply file:has.symbol() post-search filter
+		if includeSymbols, excludeSymbols, ok := isSymbolSearch(b); ok {
+			var err error
+			basicJob, err = NewFileHasSymbolFilterJob(includeSymbols, excludeSymbols, b.IsCaseSensitive(), basicJob)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	{ // Apply subrepo permissions checks
 		checker := authz.DefaultSubRepoPermsChecker
 		if authz.SubRepoEnabled(checker) {
@@ -221,12 +247,7 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 	{ // Apply selectors
 		if v, _ := b.ToParseTree().StringValue(query.FieldSelect); v != "" {
 			sp, _ := filter.SelectPathFromString(v) // Invariant: select already validated
-			if isSelectOwnersSearch(sp) {
-				// the select owners job is ran separately as it requires state and can return multiple owners from one match.
-				basicJob = ownsearch.NewSelectOwnersJob(basicJob)
-			} else {
-				basicJob = NewSelectJob(sp, basicJob)
-			}
+			basicJob = newSelectJob(sp, basicJob)
 		}
 	}
 
@@ -399,97 +420,113 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 		}
 
 		if resultTypes.Has(result.TypeRepo) {
-			valid := func() bool {
-				fieldAllowlist := map[string]struct{}{
-					query.FieldRepo:               {},
-					query.FieldContext:            {},
-					query.FieldType:               {},
-					query.FieldDefault:            {},
-					query.FieldIndex:              {},
-					query.FieldCount:              {},
-					query.FieldTimeout:            {},
-					query.FieldFork:               {},
-					query.FieldArchived:           {},
-					query.FieldVisibility:         {},
-					query.FieldCase:               {},
-					query.FieldRepoHasFile:        {},
-					query.FieldRepoHasCommitAfter: {},
-					query.FieldPatternType:        {},
-					query.FieldSelect:             {},
-				}
-
-				// Don't run a repo search if the search contains fields that aren't on the allowlist.
-				exists := true
-				query.VisitParameter(f.ToBasic().ToParseTree(), func(field, _ string, _ bool, _ query.Annotation) {
-					if _, ok := fieldAllowlist[field]; !ok {
-						exists = false
-					}
-				})
-				return exists
+			if repoJob, ok := newRepoSearchJob(f.ToBasic(), repoOptions); ok {
+				addJob(repoJob)
 			}
+		}
+	}
 
-			// returns an updated RepoOptions if the pattern part of a query can be used to
-			// search repos. A problematic case we check for is when the pattern contains `@`,
-			// which may confuse downstream logic to interpret it as part of `repo@rev` syntax.
-			addPatternAsRepoFilter := func(pattern string, opts search.RepoOptions) (search.RepoOptions, bool) {
-				if pattern == "" {
-					return opts, true
-				}
+	return NewParallelJob(allJobs...), nil
+}
 
-				opts.RepoFilters = append(make([]query.ParsedRepoFilter, 0, len(opts.RepoFilters)), opts.RepoFilters...)
-				opts.CaseSensitiveRepoFilters = f.IsCaseSensitive()
-
-				patternPrefix := strings.SplitN(pattern, "@", 2)
-				if len(patternPrefix) == 1 || patternPrefix[0] != "" {
-					// Extend the repo search using the pattern value, but
-					// if the pattern contains @, only search the part
-					// prefixed by the first @. This because downstream
-					// logic will get confused by the presence of @ and try
-					// to resolve repo revisions. See #27816.
-					repoFilter, err := query.ParseRepositoryRevisions(patternPrefix[0])
-					if err != nil {
-						// Prefix is not valid regexp, so just reject it. This can happen for patterns where we've automatically added `(...).*?(...)`
-						// such as `foo @bar` which becomes `(foo).*?(@bar)`, which when stripped becomes `(foo).*?(` which is unbalanced and invalid.
-						// Why is this a mess? Because validation for everything, including repo values, should be done up front so far possible, not downtsream
-						// after possible modifications. By the time we reach this code, the pattern should already have been considered valid to continue with
-						// a search. But fixing the order of concerns for repo code is not something @rvantonder is doing today.
-						return search.RepoOptions{}, false
-					}
-					opts.RepoFilters = append(opts.RepoFilters, repoFilter)
-					return opts, true
-				}
+// newRepoSearchJob returns the job which searches for repositories matching
+// the pattern of b. It returns false if b can't be evaluated as a repository
+// search.
+func newRepoSearchJob(b query.Basic, repoOptions search.RepoOptions) (*RepoSearchJob, bool) {
+	valid := func() bool {
+		fieldAllowlist := map[string]struct{}{
+			query.FieldRepo:               {},
+			query.FieldContext:            {},
+			query.FieldType:               {},
+			query.FieldDefault:            {},
+			query.FieldIndex:              {},
+			query.FieldCount:              {},
+			query.FieldTimeout:            {},
+			query.FieldFork:               {},
+			query.FieldArchived:           {},
+			query.FieldVisibility:         {},
+			query.FieldCase:               {},
+			query.FieldRepoHasFile:        {},
+			query.FieldRepoHasCommitAfter: {},
+			query.FieldPatternType:        {},
+			query.FieldSelect:             {},
+		}
 
-				// This pattern starts with @, of the form "@thing". We can't
-				// consistently handle search repos of this form, because
-				// downstream logic will attempt to interpret "thing" as a repo
-				// revision, may fail, and cause us to raise an alert for any
-				// non `type:repo` search. Better to not attempt a repo search.
-				return search.RepoOptions{}, false
+		// Don't run a repo search if the search contains fields that aren't on the allowlist.
+		exists := true
+		query.VisitParameter(b.ToParseTree(), func(field, _ string, _ bool, _ query.Annotation) {
+			if _, ok := fieldAllowlist[field]; !ok {
+				exists = false
 			}
+		})
+		return exists
+	}
 
-			if valid() {
-				if repoOptions, ok := addPatternAsRepoFilter(f.ToBasic().PatternString(), repoOptions); ok {
-					descriptionPatterns := make([]*regexp.Regexp, 0, len(repoOptions.DescriptionPatterns))
-					for _, pat := range repoOptions.DescriptionPatterns {
-						descriptionPatterns = append(descriptionPatterns, regexp.MustCompile(`(?is)`+pat))
-					}
-
-					repoNamePatterns := make([]*regexp.Regexp, 0, len(repoOptions.RepoFilters))
-					for _, repoFilter := range repoOptions.RepoFilters {
-						repoNamePatterns = append(repoNamePatterns, repoFilter.RepoRegex)
-					}
-
-					addJob(&RepoSearchJob{
-						RepoOpts:            repoOptions,
-						DescriptionPatterns: descriptionPatterns,
-						RepoNamePatterns:    repoNamePatterns,
-					})
-				}
+	// returns an updated RepoOptions if the pattern part of a query can be used to
+	// search repos. A problematic case we check for is when the pattern contains `@`,
+	// which may confuse downstream logic to interpret it as part of `repo@rev` syntax.
+	addPatternAsRepoFilter := func(pattern string, opts search.RepoOptions) (search.RepoOptions, bool) {
+		if pattern == "" {
+			return opts, true
+		}
+
+		opts.RepoFilters = append(make([]query.ParsedRepoFilter, 0, len(opts.RepoFilters)), opts.RepoFilters...)
+		opts.CaseSensitiveRepoFilters = b.IsCaseSensitive()
+
+		patternPrefix := strings.SplitN(pattern, "@", 2)
+		if len(patternPrefix) == 1 || patternPrefix[0] != "" {
+			// Extend the repo search using the pattern value, but
+			// if the pattern contains @, only search the part
+			// prefixed by the first @. This because downstream
+			// logic will get confused by the presence of @ and try
+			// to resolve repo revisions. See #27816.
+			repoFilter, err := query.ParseRepositoryRevisions(patternPrefix[0])
+			if err != nil {
+				// Prefix is not valid regexp, so just reject it. This can happen for patterns where we've automatically added `(...).*?(...)`
+				// such as `foo @bar` which becomes `(foo).*?(@bar)`, which when stripped becomes `(foo).*?(` which is unbalanced and invalid.
+				// Why is this a mess? Because validation for everything, including repo values, should be done up front so far possible, not downtsream
+				// after possible modifications. By the time we reach this code, the pattern should already have been considered valid to continue with
+				// a search. But fixing the order of concerns for repo code is not something @rvantonder is doing today.
+				return search.RepoOptions{}, false
 			}
+			opts.RepoFilters = append(opts.RepoFilters, repoFilter)
+			return opts, true
 		}
+
+		// This pattern starts with @, of the form "@thing". We can't
+		// consistently handle search repos of this form, because
+		// downstream logic will attempt to interpret "thing" as a repo
+		// revision, may fail, and cause us to raise an alert for any
+		// non `type:repo` search. Better to not attempt a repo search.
+		return search.RepoOptions{}, false
 	}
 
-	return NewParallelJob(allJobs...), nil
+	// NEAR/n expressions don't apply to repository names.
+	_, _, isNear := b.NearPattern()
+
+	if !valid() || isNear {
+		return nil, false
+	}
+	repoOptions, ok := addPatternAsRepoFilter(b.PatternString(), repoOptions)
+	if !ok {
+		return nil, false
+	}
+
+	descriptionPatterns := make([]*regexp.Regexp, 0, len(repoOptions.DescriptionPatterns))
+	for _, pat := range repoOptions.DescriptionPatterns {
+		descriptionPatterns = append(descriptionPatterns, regexp.MustCompile(`(?is)`+pat))
+	}
+
+	repoNamePatterns := make([]*regexp.Regexp, 0, len(repoOptions.RepoFilters))
+	for _, repoFilter := range repoOptions.RepoFilters {
+		repoNamePatterns = append(repoNamePatterns, repoFilter.RepoRegex)
+	}
+
+	return &RepoSearchJob{
+		RepoOpts:            repoOptions,
+		DescriptionPatterns: descriptionPatterns,
+		RepoNamePatterns:    repoNamePatterns,
+	}, true
 }
 
 func getPathRegexpsFromTextPatternInfo(patternInfo *search.TextPatternInfo) (pathRegexps []*regexp.Regexp) {
@@ -530,7 +567,7 @@ func getPathRegexpsFromTextPatternInfo(patternInfo *search.TextPatternInfo) (pat
 
 func computeFileMatchLimit(b query.Basic, defaultLimit int) int {
 	// Temporary fix:
-	// If doing ownership or contributor search, we post-filter results so we may need more than
+	// If doing ownership, contributor or symbol search, we post-filter results so we may need more than
 	// b.Count() results from the search backends to end up with enough results
 	// sent down the stream.
 	//
@@ -544,6 +581,10 @@ func computeFileMatchLimit(b query.Basic, defaultLimit int) int {
 		// This is the int equivalent of count:all.
 		return query.CountAllLimit
 	}
+	if _, _, ok := isSymbolSearch(b); ok {
+		// This is the int equivalent of count:all.
+		return query.CountAllLimit
+	}
 	if _, _, ok := isOwnershipSearch(b); ok {
 		// This is the int equivalent of count:all.
 		return query.CountAllLimit
@@ -566,6 +607,15 @@ func isOwnershipSearch(b query.Basic) (include, exclude []string, ok bool) {
 	return nil, nil, false
 }
 
+// newSelectJob wraps child in the job which selects results with sp.
+func newSelectJob(sp filter.SelectPath, child job.Job) job.Job {
+	if isSelectOwnersSearch(sp) {
+		// the select owners job is ran separately as it requires state and can return multiple owners from one match.
+		return ownsearch.NewSelectOwnersJob(child)
+	}
+	return NewSelectJob(sp, child)
+}
+
 func isSelectOwnersSearch(sp filter.SelectPath) bool {
 	// If the filter is for file.owners, this is a select:file.owners search, and we should apply special limits.
 	return sp.Root() == filter.File && len(sp) == 2 && sp[1] == "owners"
@@ -578,6 +628,13 @@ func isContributorSearch(b query.Basic) (include, exclude []string, ok bool) {
 	return nil, nil, false
 }
 
+func isSymbolSearch(b query.Basic) (include, exclude []query.FileHasSymbolPredicate, ok bool) {
+	if includeSymbols, excludeSymbols := b.FileHasSymbol(); len(includeSymbols) > 0 || len(excludeSymbols) > 0 {
+		return includeSymbols, excludeSymbols, true
+	}
+	return nil, nil, false
+}
+
 func contributorsAsRegexp(contributors []string, isCaseSensitive bool) (res []*regexp.Regexp) {
 	for _, pattern := range contributors {
 		if isCaseSensitive {
@@ -653,16 +710,20 @@ func toTextPatternInfo(b query.Basic, resultTypes result.Types, defaultLimit int
 	if p, ok := b.Pattern.(query.Pattern); ok {
 		negated = p.Negated
 	}
+	nearPattern, nearDistance, _ := b.NearPattern()
 
 	return &search.TextPatternInfo{
 		// Values dependent on pattern atom.
-		Pattern:   b.PatternString(),
-		IsRegExp:  isRegexp,
-		IsNegated: negated,
+		Pattern:      b.PatternString(),
+		IsRegExp:     isRegexp,
+		IsNegated:    negated,
+		NearPattern:  nearPattern,
+		NearDistance: nearDistance,
 
 		// Values dependent on parameters.
 		IsStructuralPat:              b.IsStructural(),
 		IsCaseSensitive:              b.IsCaseSensitive(),
+		IsMultiline:                  b.IsMultiline(),
 		FileMatchLimit:               int32(count),
 		IncludePatterns:              filesInclude,
 		ExcludePattern:               query.UnionRegExps(filesExclude),
@@ -758,6 +819,7 @@ func toRepoOptions(b query.Basic, userSettings *schema.Settings) search.RepoOpti
 		Visibility:          visibility,
 		HasFileContent:      b.RepoHasFileContent(),
 		CommitAfter:         b.RepoContainsCommitAfter(),
+		At:                  b.At(time.Now),
 		UseIndex:            b.Index(),
 		HasKVPs:             b.RepoHasKVPs(),
 		HasTopics:           b.RepoHasTopics(),
@@ -1065,6 +1127,12 @@ func isGlobal(op search.RepoOptions) bool {
 		return false
 	}
 
+	// at: resolves revisions to commits during the repo resolution step.
+	// Zoekt only knows about the commits it has indexed.
+	if op.At != nil {
+		return false
+	}
+
 	// There should be no cursors when calling this, but if there are that
 	// means we're already paginating. Cursors should probably not live on this
 	// struct since they are an implementation detail of pagination.
@@ -6,6 +6,7 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/commit"
 	"github.com/sourcegraph/sourcegraph/internal/search/job"
 	"github.com/sourcegraph/sourcegraph/internal/search/repos"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
@@ -71,6 +72,14 @@ func setRepos(j job.Job, indexed *zoekt.IndexedRepoRevs, unindexed []*search.Rep
 			cp := *v
 			cp.Repos = unindexed
 			return &cp
+		case *commit.SearchJob:
+			cp := *v
+			cp.Repos = unindexed
+			return &cp
+		case *RepoSearchJob:
+			cp := *v
+			cp.Repos = unindexed
+			return &cp
 		default:
 			return j
 		}
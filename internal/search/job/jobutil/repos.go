@@ -22,12 +22,22 @@ type RepoSearchJob struct {
 	RepoOpts            search.RepoOptions
 	DescriptionPatterns []*regexp.Regexp
 	RepoNamePatterns    []*regexp.Regexp // used for getting repo name match ranges
+
+	// Repos are the repository revisions to return matches for. If nil, the
+	// repositories are resolved from RepoOpts. Search Jobs set Repos, since
+	// they resolve the repositories themselves.
+	Repos []*search.RepositoryRevisions
 }
 
 func (s *RepoSearchJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
 	tr, ctx, stream, finish := job.StartSpan(ctx, stream, s)
 	defer func() { finish(alert, err) }()
 
+	if s.Repos != nil {
+		tr.SetAttributes(attribute.Int("resolved.len", len(s.Repos)))
+		return nil, s.sendRepoMatches(ctx, clients.DB, stream, s.Repos)
+	}
+
 	repos := searchrepos.NewResolver(clients.Logger, clients.DB, clients.Gitserver, clients.SearcherURLs, clients.Zoekt)
 	it := repos.Iterator(ctx, s.RepoOpts)
 
@@ -36,18 +46,9 @@ func (s *RepoSearchJob) Run(ctx context.Context, clients job.RuntimeClients, str
 		tr.SetAttributes(attribute.Int("resolved.len", len(page.RepoRevs)))
 		page.MaybeSendStats(stream)
 
-		descriptionMatches := make(map[api.RepoID][]result.Range)
-		if len(s.DescriptionPatterns) > 0 {
-			repoDescriptionsSet, err := s.repoDescriptions(ctx, clients.DB, page.RepoRevs)
-			if err != nil {
-				return nil, err
-			}
-			descriptionMatches = s.descriptionMatchRanges(repoDescriptionsSet)
+		if err := s.sendRepoMatches(ctx, clients.DB, stream, page.RepoRevs); err != nil {
+			return nil, err
 		}
-
-		stream.Send(streaming.SearchEvent{
-			Results: repoRevsToRepoMatches(page.RepoRevs, s.RepoNamePatterns, descriptionMatches),
-		})
 	}
 
 	// Do not error with no results for repo search. For text search, this is an
@@ -56,6 +57,23 @@ func (s *RepoSearchJob) Run(ctx context.Context, clients job.RuntimeClients, str
 	return nil, err
 }
 
+// sendRepoMatches sends a repository match for each revision in repoRevs.
+func (s *RepoSearchJob) sendRepoMatches(ctx context.Context, db database.DB, stream streaming.Sender, repoRevs []*search.RepositoryRevisions) error {
+	descriptionMatches := make(map[api.RepoID][]result.Range)
+	if len(s.DescriptionPatterns) > 0 {
+		repoDescriptionsSet, err := s.repoDescriptions(ctx, db, repoRevs)
+		if err != nil {
+			return err
+		}
+		descriptionMatches = s.descriptionMatchRanges(repoDescriptionsSet)
+	}
+
+	stream.Send(streaming.SearchEvent{
+		Results: repoRevsToRepoMatches(repoRevs, s.RepoNamePatterns, descriptionMatches),
+	})
+	return nil
+}
+
 // repoDescriptions gets the repo ID and repo description from the database for each of the repos in repoRevs, and returns
 // a map of repo ID to repo description.
 func (s *RepoSearchJob) repoDescriptions(ctx context.Context, db database.DB, repoRevs []*search.RepositoryRevisions) (map[api.RepoID]string, error) {
@@ -112,6 +130,9 @@ func (s *RepoSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
 	case job.VerbosityBasic:
 		res = append(res, trace.Scoped("repoOpts", s.RepoOpts.Attributes()...)...)
 		res = append(res, trace.Stringers("repoNamePatterns", s.RepoNamePatterns))
+		if s.Repos != nil {
+			res = append(res, attribute.Int("numRepos", len(s.Repos)))
+		}
 	}
 	return res
 }
@@ -0,0 +1,136 @@
+package jobutil
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/sourcegraph/conc/pool"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+// NewRevisionCompareJob creates a job which runs the same search at the base
+// and the head revision of rev:base...head. Once both searches are done, it
+// streams a result.CompareMatch for each file whose matched lines were added,
+// removed or moved between the revisions.
+//
+// If either search didn't return all matches, for example because it timed
+// out, no results are streamed. Lines that weren't found at one revision
+// would otherwise be reported as added or removed.
+func NewRevisionCompareJob(base, head job.Job) job.Job {
+	return &RevisionCompareJob{base: base, head: head}
+}
+
+type RevisionCompareJob struct {
+	base job.Job
+	head job.Job
+}
+
+type compareFileKey struct {
+	repo api.RepoID
+	path string
+}
+
+func (j *RevisionCompareJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		p          = pool.New().WithContext(ctx)
+		maxAlerter search.MaxAlerter
+		mu         sync.Mutex
+		files      = [2]map[compareFileKey]*result.FileMatch{{}, {}}
+		truncated  bool
+	)
+	for side, child := range []job.Job{j.base, j.head} {
+		side, child := side, child
+		p.Go(func(ctx context.Context) error {
+			collectingStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+				mu.Lock()
+				if event.Stats.IsLimitHit || event.Stats.Status.Any(search.RepoStatusLimitHit|search.RepoStatusTimedOut) {
+					truncated = true
+				}
+				for _, match := range event.Results {
+					fm, ok := match.(*result.FileMatch)
+					if !ok {
+						continue
+					}
+					key := compareFileKey{repo: fm.Repo.ID, path: fm.Path}
+					if existing, ok := files[side][key]; ok {
+						existing.AppendMatches(fm)
+					} else {
+						files[side][key] = fm
+					}
+				}
+				mu.Unlock()
+
+				// Only the compared results are streamed, but stats like the
+				// searched repositories are passed on right away.
+				if !event.Stats.Zero() {
+					stream.Send(streaming.SearchEvent{Stats: event.Stats})
+				}
+			})
+
+			alert, err := child.Run(ctx, clients, collectingStream)
+			maxAlerter.Add(alert)
+			return err
+		})
+	}
+	err = p.Wait()
+
+	if truncated {
+		maxAlerter.Add(search.AlertForTruncatedRevisionCompare())
+		return maxAlerter.Alert, err
+	}
+
+	keys := make([]compareFileKey, 0, len(files[0])+len(files[1]))
+	for key := range files[0] {
+		keys = append(keys, key)
+	}
+	for key := range files[1] {
+		if _, ok := files[0][key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a].repo != keys[b].repo {
+			return keys[a].repo < keys[b].repo
+		}
+		return keys[a].path < keys[b].path
+	})
+
+	var matches result.Matches
+	for _, key := range keys {
+		if cm := result.NewCompareMatch(files[0][key], files[1][key]); cm != nil {
+			matches = append(matches, cm)
+		}
+	}
+	if len(matches) > 0 {
+		stream.Send(streaming.SearchEvent{Results: matches})
+	}
+
+	return maxAlerter.Alert, err
+}
+
+func (j *RevisionCompareJob) Name() string {
+	return "RevisionCompareJob"
+}
+
+func (j *RevisionCompareJob) Attributes(job.Verbosity) []attribute.KeyValue { return nil }
+
+func (j *RevisionCompareJob) Children() []job.Describer {
+	return []job.Describer{j.base, j.head}
+}
+
+func (j *RevisionCompareJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.base = job.Map(j.base, fn)
+	cp.head = job.Map(j.head, fn)
+	return &cp
+}
@@ -0,0 +1,119 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRevisionCompareJob(t *testing.T) {
+	fileMatch := func(rev, path string, lines ...string) *result.FileMatch {
+		fm := &result.FileMatch{
+			File: result.File{
+				Repo:     types.MinimalRepo{ID: 1, Name: "repo"},
+				InputRev: &rev,
+				Path:     path,
+			},
+		}
+		for i, line := range lines {
+			fm.ChunkMatches = append(fm.ChunkMatches, result.ChunkMatch{
+				Content:      line,
+				ContentStart: result.Location{Line: i},
+				Ranges: result.Ranges{{
+					Start: result.Location{Line: i},
+					End:   result.Location{Line: i, Column: len(line)},
+				}},
+			})
+		}
+		return fm
+	}
+
+	newChild := func(events ...[]result.Match) *mockjob.MockJob {
+		j := mockjob.NewMockJob()
+		j.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			for _, matches := range events {
+				s.Send(streaming.SearchEvent{Results: matches})
+			}
+			return nil, nil
+		})
+		return j
+	}
+
+	base := newChild(
+		[]result.Match{fileMatch("v1", "a.go", "foo"), fileMatch("v1", "same.go", "foo")},
+		[]result.Match{fileMatch("v1", "removed.go", "foo"), &result.RepoMatch{Name: "repo", ID: 1}},
+	)
+	head := newChild(
+		[]result.Match{fileMatch("v2", "a.go", "foo", "foo bar")},
+		[]result.Match{fileMatch("v2", "same.go", "foo")},
+	)
+
+	var sent []result.Match
+	stream := streaming.StreamFunc(func(e streaming.SearchEvent) {
+		sent = append(sent, e.Results...)
+	})
+	_, err := NewRevisionCompareJob(base, head).Run(context.Background(), job.RuntimeClients{}, stream)
+	require.NoError(t, err)
+
+	require.Len(t, sent, 2)
+	added := sent[0].(*result.CompareMatch)
+	require.Equal(t, "a.go", added.Path)
+	require.Equal(t, "v1", added.BaseRev)
+	require.Equal(t, "v2", added.HeadRev)
+	require.Equal(t, []result.CompareLine{{
+		Status:           result.CompareAdded,
+		Preview:          "foo bar",
+		BaseLineNumber:   -1,
+		HeadLineNumber:   1,
+		OffsetAndLengths: [][2]int32{{0, 7}},
+	}}, added.Lines)
+
+	removed := sent[1].(*result.CompareMatch)
+	require.Equal(t, "removed.go", removed.Path)
+	require.Equal(t, result.CompareRemoved, removed.Lines[0].Status)
+}
+
+func TestRevisionCompareJob_truncated(t *testing.T) {
+	newChild := func(event streaming.SearchEvent) *mockjob.MockJob {
+		j := mockjob.NewMockJob()
+		j.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			s.Send(event)
+			return nil, nil
+		})
+		return j
+	}
+	fileMatch := func(rev string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{Repo: types.MinimalRepo{ID: 1, Name: "repo"}, InputRev: &rev, Path: "a.go"},
+			ChunkMatches: result.ChunkMatches{{
+				Content: "foo",
+				Ranges:  result.Ranges{{End: result.Location{Column: 3}}},
+			}},
+		}
+	}
+
+	// The head search hit a limit, so a.go looks like it was removed even
+	// though it may not have been.
+	base := newChild(streaming.SearchEvent{Results: result.Matches{fileMatch("v1")}})
+	head := newChild(streaming.SearchEvent{Stats: streaming.Stats{IsLimitHit: true}})
+
+	var sent []result.Match
+	var limitHit bool
+	stream := streaming.StreamFunc(func(e streaming.SearchEvent) {
+		sent = append(sent, e.Results...)
+		limitHit = limitHit || e.Stats.IsLimitHit
+	})
+	alert, err := NewRevisionCompareJob(base, head).Run(context.Background(), job.RuntimeClients{}, stream)
+	require.NoError(t, err)
+	require.Empty(t, sent)
+	require.True(t, limitHit)
+	require.Equal(t, search.AlertForTruncatedRevisionCompare(), alert)
+}
@@ -62,17 +62,38 @@ func (j *selectJob) MapChildren(fn job.MapFunc) job.Job {
 func newSelectingStream(parent streaming.Sender, s filter.SelectPath) streaming.Sender {
 	var mux sync.Mutex
 	dedup := result.NewDeduper()
+	directoryFiles := map[result.Key]struct{}{}
 
 	return streaming.StreamFunc(func(e streaming.SearchEvent) {
 		mux.Lock()
 
 		selected := e.Results[:0]
+		directories := map[result.Key]*result.DirectoryMatch{}
 		for _, match := range e.Results {
+			matchKey := match.Key()
 			current := match.Select(s)
 			if current == nil {
 				continue
 			}
 
+			// Directory matches only count what is new in this event, see
+			// result.DirectoryMatch. A file which is sent again only adds its
+			// matches, not another file.
+			if dm, ok := current.(*result.DirectoryMatch); ok {
+				if _, seen := directoryFiles[matchKey]; seen {
+					dm.FileCount = 0
+				}
+				directoryFiles[matchKey] = struct{}{}
+
+				if prev, ok := directories[dm.Key()]; ok {
+					prev.AppendMatches(dm)
+					continue
+				}
+				directories[dm.Key()] = dm
+				selected = append(selected, dm)
+				continue
+			}
+
 			// If the selected file is a file match send it unconditionally
 			// to ensure we get all line matches for a file. One exception:
 			// if we are only interested in the path (via `select:file`),
@@ -31,6 +31,10 @@ func TestWithSelect(t *testing.T) {
 					File:         result.File{Path: "digiman/ummm"},
 					ChunkMatches: result.ChunkMatches{{Ranges: make(result.Ranges, 1)}},
 				},
+				&result.FileMatch{
+					File:         result.File{Path: "pokeman/evolutions/charmeleon"},
+					ChunkMatches: result.ChunkMatches{{Ranges: make(result.Ranges, 2)}},
+				},
 			},
 		}
 	}
@@ -46,6 +50,42 @@ func TestWithSelect(t *testing.T) {
 
 	autogold.Expect(`[
   {
+    "Path": "pokeman/",
+    "FileCount": 2,
+    "MatchCount": 3,
+    "LimitHit": false
+  },
+  {
+    "Path": "digiman/",
+    "FileCount": 1,
+    "MatchCount": 1,
+    "LimitHit": false
+  },
+  {
+    "Path": "pokeman/evolutions/",
+    "FileCount": 1,
+    "MatchCount": 2,
+    "LimitHit": false
+  }
+]`).Equal(t, test("file.rollup"))
+
+	autogold.Expect(`[
+  {
+    "Path": "pokeman/",
+    "FileCount": 3,
+    "MatchCount": 5,
+    "LimitHit": false
+  },
+  {
+    "Path": "digiman/",
+    "FileCount": 1,
+    "MatchCount": 1,
+    "LimitHit": false
+  }
+]`).Equal(t, test("file.rollup.1"))
+
+	autogold.Expect(`[
+  {
     "Path": "pokeman/",
     "ChunkMatches": null,
     "PathMatches": null,
@@ -56,6 +96,12 @@ func TestWithSelect(t *testing.T) {
     "ChunkMatches": null,
     "PathMatches": null,
     "LimitHit": false
+  },
+  {
+    "Path": "pokeman/evolutions/",
+    "ChunkMatches": null,
+    "PathMatches": null,
+    "LimitHit": false
   }
 ]`).Equal(t, test("file.directory"))
 
@@ -77,6 +123,12 @@ func TestWithSelect(t *testing.T) {
     "ChunkMatches": null,
     "PathMatches": null,
     "LimitHit": false
+  },
+  {
+    "Path": "pokeman/evolutions/charmeleon",
+    "ChunkMatches": null,
+    "PathMatches": null,
+    "LimitHit": false
   }
 ]`).Equal(t, test("file"))
 
@@ -218,6 +270,47 @@ func TestWithSelect(t *testing.T) {
     ],
     "PathMatches": null,
     "LimitHit": false
+  },
+  {
+    "Path": "pokeman/evolutions/charmeleon",
+    "ChunkMatches": [
+      {
+        "Content": "",
+        "ContentStart": [
+          0,
+          0,
+          0
+        ],
+        "Ranges": [
+          {
+            "start": [
+              0,
+              0,
+              0
+            ],
+            "end": [
+              0,
+              0,
+              0
+            ]
+          },
+          {
+            "start": [
+              0,
+              0,
+              0
+            ],
+            "end": [
+              0,
+              0,
+              0
+            ]
+          }
+        ]
+      }
+    ],
+    "PathMatches": null,
+    "LimitHit": false
   }
 ]`).Equal(t, test("content"))
 }
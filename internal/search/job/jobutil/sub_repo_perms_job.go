@@ -145,8 +145,8 @@ func applySubRepoFiltering(ctx context.Context, checker authz.SubRepoPermissionC
 		case *result.RepoMatch:
 			// Repo filtering is taken care of by our usual repo filtering logic
 			filtered = append(filtered, m)
-			// Owner matches are found after the sub-repo permissions filtering, hence why we don't have
-			// an OwnerMatch case here.
+			// Owner and directory matches are found after the sub-repo permissions filtering, hence why
+			// we don't have an OwnerMatch or DirectoryMatch case here.
 		}
 	}
 
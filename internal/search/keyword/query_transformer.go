@@ -13,24 +13,40 @@ type keywordQuery struct {
 	patterns []string
 }
 
-func concatNodeToPatterns(concat query.Operator) []string {
+// isRegexpPattern returns whether pattern is a regular expression, such as
+// /foo.*bar/, or a NEAR/n expression. We keep those as they are, instead of
+// splitting them into keywords.
+func isRegexpPattern(pattern query.Pattern) bool {
+	return pattern.Annotation.Labels.IsSet(query.Regexp) || pattern.Annotation.Near != nil
+}
+
+func concatNodeToPatterns(concat query.Operator) ([]string, []query.Node) {
 	patterns := make([]string, 0, len(concat.Operands))
+	var regexps []query.Node
 	for _, operand := range concat.Operands {
 		pattern, ok := operand.(query.Pattern)
-		if ok {
+		if !ok {
+			continue
+		}
+		if isRegexpPattern(pattern) {
+			regexps = append(regexps, pattern)
+		} else {
 			patterns = append(patterns, pattern.Value)
 		}
 	}
-	return patterns
+	return patterns, regexps
 }
 
-func nodeToPatternsAndParameters(rootNode query.Node) ([]string, []query.Parameter) {
+// nodeToPatternsAndParameters returns the keywords, the regular expression
+// patterns and the parameters of the query rooted at rootNode.
+func nodeToPatternsAndParameters(rootNode query.Node) ([]string, []query.Node, []query.Parameter) {
 	operator, ok := rootNode.(query.Operator)
 	if !ok {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	patterns := []string{}
+	var regexps []query.Node
 	parameters := []query.Parameter{
 		// Only search file content
 		{Field: query.FieldType, Value: "file"},
@@ -42,7 +58,9 @@ func nodeToPatternsAndParameters(rootNode query.Node) ([]string, []query.Paramet
 			switch op := operand.(type) {
 			case query.Operator:
 				if op.Kind == query.Concat {
-					patterns = append(patterns, concatNodeToPatterns(op)...)
+					concatPatterns, concatRegexps := concatNodeToPatterns(op)
+					patterns = append(patterns, concatPatterns...)
+					regexps = append(regexps, concatRegexps...)
 				}
 			case query.Parameter:
 				if op.Field == query.FieldContent {
@@ -52,14 +70,18 @@ func nodeToPatternsAndParameters(rootNode query.Node) ([]string, []query.Paramet
 					parameters = append(parameters, op)
 				}
 			case query.Pattern:
-				patterns = append(patterns, op.Value)
+				if isRegexpPattern(op) {
+					regexps = append(regexps, op)
+				} else {
+					patterns = append(patterns, op.Value)
+				}
 			}
 		}
 	case query.Concat:
-		patterns = concatNodeToPatterns(operator)
+		patterns, regexps = concatNodeToPatterns(operator)
 	}
 
-	return patterns, parameters
+	return patterns, regexps, parameters
 }
 
 // transformPatterns applies stops words and stemming. The returned slice
@@ -109,10 +131,10 @@ func queryStringToKeywordQuery(queryString string) (*keywordQuery, error) {
 		return nil, nil
 	}
 
-	patterns, parameters := nodeToPatternsAndParameters(rawParseTree[0])
+	patterns, regexps, parameters := nodeToPatternsAndParameters(rawParseTree[0])
 
 	transformedPatterns := transformPatterns(patterns)
-	if len(transformedPatterns) == 0 {
+	if len(transformedPatterns) == 0 && len(regexps) == 0 {
 		return nil, nil
 	}
 
@@ -121,6 +143,9 @@ func queryStringToKeywordQuery(queryString string) (*keywordQuery, error) {
 		nodes = append(nodes, p)
 	}
 
+	// Regular expression patterns must match, the keywords are ranked.
+	nodes = append(nodes, regexps...)
+
 	patternNodes := make([]query.Node, 0, len(transformedPatterns))
 	for _, p := range transformedPatterns {
 		patternNodes = append(patternNodes, query.Pattern{Value: p})
@@ -75,6 +75,11 @@ func TestQueryStringToKeywordQuery(t *testing.T) {
 				"outer",
 			}),
 		},
+		{
+			query:        "lang:go backoff and retry NEAR/3 timeout",
+			wantQuery:    autogold.Expect("type:file lang:go (retry NEAR/3 timeout AND backoff)"),
+			wantPatterns: autogold.Expect([]string{"backoff"}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -6,6 +6,7 @@ var empty = struct{}{}
 const (
 	FieldDefault            = ""
 	FieldCase               = "case"
+	FieldMultiline          = "multiline"
 	FieldRepo               = "repo"
 	FieldFile               = "file"
 	FieldFork               = "fork"
@@ -18,6 +19,7 @@ const (
 	FieldContent            = "content"
 	FieldVisibility         = "visibility"
 	FieldRev                = "rev"
+	FieldAt                 = "at"
 	FieldContext            = "context"
 
 	// For diff and commit search only:
@@ -37,6 +39,7 @@ const (
 
 var allFields = map[string]struct{}{
 	FieldCase:               empty,
+	FieldMultiline:          empty,
 	FieldRepo:               empty,
 	"r":                     empty,
 	FieldContext:            empty,
@@ -70,6 +73,7 @@ var allFields = map[string]struct{}{
 	FieldCombyRule:          empty,
 	FieldRev:                empty,
 	"revision":              empty,
+	FieldAt:                 empty,
 	FieldSelect:             empty,
 }
 
@@ -0,0 +1,216 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// macroPattern matches a pattern which refers to a search macro, e.g. @prod-go.
+var macroPattern = lazyregexp.New(`^@([A-Za-z0-9_][A-Za-z0-9_.-]*)$`)
+
+// MacroError is returned when a search macro used in a query cannot be
+// expanded.
+type MacroError struct {
+	Name string
+	Err  error
+}
+
+func (e *MacroError) Error() string {
+	return fmt.Sprintf("cannot expand search macro @%s: %s", e.Name, e.Err)
+}
+
+func (e *MacroError) Unwrap() error {
+	return e.Err
+}
+
+// InitWithMacros is Init, but first expands the search macros in the input,
+// see ExpandMacros.
+func InitWithMacros(in string, searchType SearchType, macros map[string]string) step {
+	parser := func([]Node) ([]Node, error) {
+		return Parse(in, searchType)
+	}
+	return Sequence(parser, ExpandMacros(macros, searchType), For(searchType))
+}
+
+// ExpandMacros creates a step which replaces each pattern @name, where name is
+// a key of macros, with the parsed query of that macro. Macros may refer to
+// other macros. Patterns which do not refer to a macro, like @Override, are
+// left alone.
+//
+// A macro used in a sequence of patterns is taken out of the sequence, so
+// `foo @m bar` searches for `foo bar` in addition to the query of @m. Macros
+// are also expanded in groups like `(@m foo) or bar`.
+//
+// This step must run on the nodes returned by Parse, before the nodes are
+// processed for the search type.
+func ExpandMacros(macros map[string]string, searchType SearchType) step {
+	return func(nodes []Node) ([]Node, error) {
+		if len(macros) == 0 {
+			return nodes, nil
+		}
+		e := &macroExpander{macros: macros, searchType: searchType}
+		return e.expandNodes(nodes, nil)
+	}
+}
+
+type macroExpander struct {
+	macros     map[string]string
+	searchType SearchType
+}
+
+// macroName returns the name of the macro node refers to, if any.
+func (e *macroExpander) macroName(node Node) (string, bool) {
+	p, ok := node.(Pattern)
+	if !ok || p.Negated || p.Annotation.Labels.IsSet(Quoted) {
+		return "", false
+	}
+	m := macroPattern.FindStringSubmatch(p.Value)
+	if m == nil {
+		return "", false
+	}
+	_, ok = e.macros[m[1]]
+	return m[1], ok
+}
+
+// macroGroup returns the contents of a pattern like (@m foo) which refers to a
+// macro. The parser reads such groups as a single pattern, because it can't
+// tell them apart from parentheses in code.
+func (e *macroExpander) macroGroup(node Node) (string, bool) {
+	p, ok := node.(Pattern)
+	if !ok || p.Negated || !p.Annotation.Labels.IsSet(HeuristicParensAsPatterns) {
+		return "", false
+	}
+	inner, ok := strings.CutPrefix(p.Value, "(")
+	if !ok {
+		return "", false
+	}
+	inner, ok = strings.CutSuffix(inner, ")")
+	if !ok {
+		return "", false
+	}
+	fields := strings.FieldsFunc(inner, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '(' || r == ')'
+	})
+	for _, field := range fields {
+		if _, ok := e.macroName(Pattern{Value: field}); ok {
+			return inner, true
+		}
+	}
+	return "", false
+}
+
+// expandMacro returns the expansion of node if it is a macro or a group which
+// contains a macro.
+func (e *macroExpander) expandMacro(node Node, stack []string) (_ Node, ok bool, _ error) {
+	if name, ok := e.macroName(node); ok {
+		macro, err := e.expand(name, stack)
+		return macro, true, err
+	}
+	if inner, ok := e.macroGroup(node); ok {
+		nodes, err := Parse(inner, e.searchType)
+		if err != nil {
+			return nil, true, err
+		}
+		nodes, err = e.expandNodes(nodes, stack)
+		if err != nil {
+			return nil, true, err
+		}
+		return Operator{Kind: And, Operands: nodes}, true, nil
+	}
+	return nil, false, nil
+}
+
+// expand parses the query of the macro name. stack contains the macros which
+// are currently being expanded, to detect macros which refer to themselves.
+func (e *macroExpander) expand(name string, stack []string) (Node, error) {
+	for _, expanding := range stack {
+		if expanding == name {
+			return nil, &MacroError{Name: name, Err: errors.New("the macro refers to itself")}
+		}
+	}
+
+	nodes, err := Parse(e.macros[name], e.searchType)
+	if err != nil {
+		return nil, &MacroError{Name: name, Err: err}
+	}
+	nodes, err = e.expandNodes(nodes, append(stack, name))
+	if err != nil {
+		return nil, err
+	}
+	return Operator{Kind: And, Operands: nodes}, nil
+}
+
+func (e *macroExpander) expandNodes(nodes []Node, stack []string) ([]Node, error) {
+	expanded := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		if macro, ok, err := e.expandMacro(node, stack); err != nil {
+			return nil, err
+		} else if ok {
+			expanded = append(expanded, macro)
+			continue
+		}
+
+		operator, ok := node.(Operator)
+		if !ok {
+			expanded = append(expanded, node)
+			continue
+		}
+
+		if operator.Kind != Concat {
+			operands, err := e.expandNodes(operator.Operands, stack)
+			if err != nil {
+				return nil, err
+			}
+			operator.Operands = operands
+			expanded = append(expanded, operator)
+			continue
+		}
+
+		// Take macros out of a sequence of patterns, so that the remaining
+		// patterns are still concatenated for the search type. The remaining
+		// patterns take the place of the first of them, so that the patterns
+		// keep the order they have in the query.
+		var operands, rest []Node
+		restIndex, hasMacro := -1, false
+		for _, operand := range operator.Operands {
+			if macro, ok, err := e.expandMacro(operand, stack); err != nil {
+				return nil, err
+			} else if ok {
+				operands = append(operands, macro)
+				hasMacro = true
+			} else {
+				if restIndex < 0 {
+					restIndex = len(operands)
+					operands = append(operands, nil)
+				}
+				rest = append(rest, operand)
+			}
+		}
+		if !hasMacro {
+			operands, err := e.expandNodes(operator.Operands, stack)
+			if err != nil {
+				return nil, err
+			}
+			operator.Operands = operands
+			expanded = append(expanded, operator)
+			continue
+		}
+		rest, err := e.expandNodes(rest, stack)
+		if err != nil {
+			return nil, err
+		}
+		switch len(rest) {
+		case 0:
+		case 1:
+			operands[restIndex] = rest[0]
+		default:
+			operands[restIndex] = Operator{Kind: Concat, Operands: rest, Annotation: operator.Annotation}
+		}
+		expanded = append(expanded, Operator{Kind: And, Operands: operands})
+	}
+	return expanded, nil
+}
@@ -0,0 +1,47 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandMacros(t *testing.T) {
+	macros := map[string]string{
+		"prod-go": "lang:go -file:_test.go -file:vendor/ -repo:^archive/",
+		"nested":  "@prod-go repo:^github\\.com/",
+		"loop":    "@loop2 foo",
+		"loop2":   "@loop",
+		"broken":  `repo:"foo`,
+	}
+
+	test := func(input string, searchType SearchType) string {
+		plan, err := Pipeline(InitWithMacros(input, searchType, macros))
+		if err != nil {
+			return err.Error()
+		}
+		return plan.ToQ().String()
+	}
+
+	autogold.Expect(`(and "lang:go" "-file:_test.go" "-file:vendor/" "-repo:^archive/" "Client")`).Equal(t, test("@prod-go Client", SearchTypeStandard))
+	autogold.Expect(`(and "lang:go" "-file:_test.go" "-file:vendor/" "-repo:^archive/" "new client")`).Equal(t, test("new @prod-go client", SearchTypeLiteral))
+	autogold.Expect(`(and "repo:^github\\.com/" "lang:go" "-file:_test.go" "-file:vendor/" "-repo:^archive/" "Client")`).Equal(t, test("@nested Client", SearchTypeStandard))
+	autogold.Expect(`(or (and "lang:go" "-file:_test.go" "-file:vendor/" "-repo:^archive/" "foo") (and "lang:go" "bar"))`).Equal(t, test("(@prod-go foo) or (lang:go bar)", SearchTypeStandard))
+	autogold.Expect(`(or (and "lang:go" "-file:_test.go" "-file:vendor/" "-repo:^archive/") "bar")`).Equal(t, test("((@prod-go)) or bar", SearchTypeStandard))
+	autogold.Expect(`(and "lang:go" "-file:_test.go" "-file:vendor/" "-repo:^archive/" "foo" "bar")`).Equal(t, test("foo (bar @prod-go)", SearchTypeStandard))
+	autogold.Expect(`(and "lang:go" "-file:_test.go" "-file:vendor/" "-repo:^archive/" "bar" "foo")`).Equal(t, test("(bar @prod-go) foo", SearchTypeStandard))
+	autogold.Expect(`(and "lang:go" "-file:_test.go" "-file:vendor/" "-repo:^archive/" "foo bar" "baz")`).Equal(t, test("foo bar (baz @prod-go)", SearchTypeLiteral))
+	autogold.Expect(`(or "(@nope foo)" "bar")`).Equal(t, test("(@nope foo) or bar", SearchTypeStandard))
+	autogold.Expect("cannot expand search macro @loop: the macro refers to itself").Equal(t, test("(@loop foo) or bar", SearchTypeStandard))
+	autogold.Expect(`"@Override"`).Equal(t, test("@Override", SearchTypeStandard))
+	autogold.Expect(`"\"@prod-go\""`).Equal(t, test(`"@prod-go"`, SearchTypeStandard))
+	autogold.Expect("cannot expand search macro @loop: the macro refers to itself").Equal(t, test("@loop", SearchTypeStandard))
+
+	t.Run("parse errors", func(t *testing.T) {
+		_, err := Pipeline(InitWithMacros("@broken", SearchTypeStandard, macros))
+		var macroErr *MacroError
+		require.ErrorAs(t, err, &macroErr)
+		require.Equal(t, "broken", macroErr.Name)
+	})
+}
@@ -15,7 +15,8 @@ import (
 Parser implements a parser for the following grammar:
 
 OrTerm     → AndTerm { OR AndTerm }
-AndTerm    → Term { AND Term }
+AndTerm    → NearTerm { AND NearTerm }
+NearTerm   → Term { NEAR/n Term }
 Term       → (OrTerm) | Parameters
 Parameters → Parameter { " " Parameter }
 */
@@ -34,6 +35,18 @@ func (Operator) node()  {}
 type Annotation struct {
 	Labels labels `json:"labels"`
 	Range  Range  `json:"range"`
+
+	// Near is set for the pattern on the left-hand side of a NEAR/n
+	// expression. It is part of the annotation so that it is kept when
+	// patterns are mapped.
+	Near *Near `json:"near,omitempty"`
+}
+
+// Near is the right-hand side of a NEAR/n expression. The pattern it is
+// attached to only matches where Pattern matches at most Distance lines away.
+type Near struct {
+	Pattern  Pattern `json:"pattern"`
+	Distance int     `json:"distance"`
 }
 
 // Pattern is a leaf node of expressions representing a search pattern fragment.
@@ -67,6 +80,11 @@ type Operator struct {
 }
 
 func (node Pattern) String() string {
+	if near := node.Annotation.Near; near != nil {
+		left := node
+		left.Annotation.Near = nil
+		return fmt.Sprintf("(near/%d %s %s)", near.Distance, left.String(), near.Pattern.String())
+	}
 	if node.Negated {
 		return fmt.Sprintf("(not %s)", strconv.Quote(node.Value))
 	}
@@ -116,8 +134,13 @@ const (
 	DQUOTE keyword = "\""
 	SLASH  keyword = "/"
 	NOT    keyword = "not"
+	NEAR   keyword = "near/"
 )
 
+// maxNearDistance is the largest n allowed in NEAR/n. Zoekt evaluates NEAR/n
+// as a regular expression whose size grows with n, see NearRegexp.
+const maxNearDistance = 100
+
 func isSpace(buf []byte) bool {
 	r, _ := utf8.DecodeRune(buf)
 	return unicode.IsSpace(r)
@@ -250,6 +273,35 @@ func (p *parser) matchUnaryKeyword(keyword keyword) bool {
 	return strings.EqualFold(v, string(keyword))
 }
 
+// scanNear scans a NEAR/n operator at the current position, which must be
+// preceded and followed by whitespace. It returns the distance n and the number
+// of bytes of the operator. It does not advance the position.
+func (p *parser) scanNear() (distance, advance int, ok bool) {
+	if p.pos == 0 || !isSpace(p.buf[p.pos-1:p.pos]) || !p.match(NEAR) {
+		return 0, 0, false
+	}
+	start := p.pos + len(string(NEAR))
+	end := start
+	for end < len(p.buf) && '0' <= p.buf[end] && p.buf[end] <= '9' {
+		end++
+	}
+	if end == start || end >= len(p.buf) || !isSpace(p.buf[end:end+1]) {
+		return 0, 0, false
+	}
+	distance, err := strconv.Atoi(string(p.buf[start:end]))
+	if err != nil {
+		return 0, 0, false
+	}
+	return distance, end - p.pos, true
+}
+
+// matchNear returns whether there is a NEAR/n operator at the current
+// position. It does not advance the position.
+func (p *parser) matchNear() bool {
+	_, _, ok := p.scanNear()
+	return ok
+}
+
 // skipSpaces advances the input and places the parser position at the next
 // non-space value.
 func (p *parser) skipSpaces() error {
@@ -336,7 +388,8 @@ func ScanBalancedPattern(buf []byte) (scanned string, count int, ok bool) {
 		}
 		if lookahead("and ") ||
 			lookahead("or ") ||
-			lookahead("not ") {
+			lookahead("not ") ||
+			lookahead("near/") {
 			// This "pattern" contains a recognized keyword, reject it.
 			return false
 		}
@@ -971,7 +1024,7 @@ loop:
 				}
 			}
 			break loop
-		case p.matchKeyword(AND), p.matchKeyword(OR):
+		case p.matchKeyword(AND), p.matchKeyword(OR), p.matchNear():
 			// Caller advances.
 			break loop
 		case p.matchUnaryKeyword(NOT):
@@ -1085,21 +1138,143 @@ func NewOperator(nodes []Node, kind OperatorKind) []Node {
 	return []Node{Operator{Kind: kind, Operands: reduced}}
 }
 
+var errNearOperand = errors.New("NEAR/n expects a single pattern on either side, not an expression")
+
+// nearOperand splits the nodes on one side of a NEAR/n operator into their
+// parameters and the pattern next to the operator. Contiguous patterns are
+// concatenated the same way as for the rest of the query, so that `foo bar
+// NEAR/3 baz` searches for "foo bar" near "baz".
+func (p *parser) nearOperand(nodes []Node) (params []Node, pattern Pattern, err error) {
+	if len(nodes) == 1 {
+		if operator, ok := nodes[0].(Operator); ok && operator.Kind == And {
+			nodes = operator.Operands
+		}
+	}
+
+	var patterns []Pattern
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case Parameter:
+			params = append(params, n)
+			continue
+		case Pattern:
+			if len(patterns) > 0 {
+				return nil, Pattern{}, errNearOperand
+			}
+			patterns = append(patterns, n)
+		case Operator:
+			if len(patterns) > 0 || n.Kind != Concat {
+				return nil, Pattern{}, errNearOperand
+			}
+			for _, operand := range n.Operands {
+				p, ok := operand.(Pattern)
+				if !ok {
+					return nil, Pattern{}, errNearOperand
+				}
+				patterns = append(patterns, p)
+			}
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, Pattern{}, errors.New("NEAR/n expects a pattern on either side")
+	}
+	for _, p := range patterns {
+		if p.Negated {
+			return nil, Pattern{}, errors.New("NEAR/n does not support negated patterns")
+		}
+	}
+
+	var concat func([]Pattern) []Node
+	switch p.leafParser {
+	case SearchTypeRegex:
+		concat = fuzzyRegexp
+	case SearchTypeLiteral:
+		concat = space
+	case SearchTypeNewStandardRC1:
+		concat = and
+	default:
+		concat = standard
+	}
+	concatenated := concat(patterns)
+	if len(concatenated) != 1 {
+		return nil, Pattern{}, errors.New("NEAR/n can't combine literal and regular expression patterns on the same side. Try quoting the pattern")
+	}
+
+	pattern = concatenated[0].(Pattern)
+	pattern.Annotation.Range = Range{
+		Start: patterns[0].Annotation.Range.Start,
+		End:   patterns[len(patterns)-1].Annotation.Range.End,
+	}
+	return params, pattern, nil
+}
+
+// parseNear parses near-expressions. Near operators have higher precedence
+// than And operators. The patterns on either side of a NEAR/n operator are
+// replaced by the left pattern, with the right one attached as Near to its
+// annotation.
+func (p *parser) parseNear(label labels) ([]Node, error) {
+	left, err := p.parseLeaves(label)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		distance, advance, ok := p.scanNear()
+		if !ok {
+			return left, nil
+		}
+		if left == nil {
+			return nil, &ExpectedOperand{Msg: fmt.Sprintf("expected operand at %d", p.pos)}
+		}
+		if p.leafParser == SearchTypeStructural {
+			return nil, errors.New("NEAR/n is not supported for structural search")
+		}
+		if distance > maxNearDistance {
+			return nil, errors.Errorf("the distance of NEAR/%d is too large, it can be at most %d lines", distance, maxNearDistance)
+		}
+		p.pos += advance
+
+		right, err := p.parseLeaves(label)
+		if err != nil {
+			return nil, err
+		}
+		if right == nil {
+			return nil, &ExpectedOperand{Msg: fmt.Sprintf("expected operand at %d", p.pos)}
+		}
+
+		leftParams, leftPattern, err := p.nearOperand(left)
+		if err != nil {
+			return nil, err
+		}
+		rightParams, rightPattern, err := p.nearOperand(right)
+		if err != nil {
+			return nil, err
+		}
+		if leftPattern.Annotation.Near != nil {
+			return nil, errors.New("NEAR/n expressions can't be chained")
+		}
+		near := leftPattern
+		near.Annotation.Near = &Near{Pattern: rightPattern, Distance: distance}
+		near.Annotation.Range = Range{Start: leftPattern.Annotation.Range.Start, End: rightPattern.Annotation.Range.End}
+		nodes := append(leftParams, near)
+		left = NewOperator(append(nodes, rightParams...), And)
+	}
+}
+
 // parseAnd parses and-expressions.
 func (p *parser) parseAnd() ([]Node, error) {
 	var left []Node
 	var err error
 	switch p.leafParser {
 	case SearchTypeRegex:
-		left, err = p.parseLeaves(Regexp)
+		left, err = p.parseNear(Regexp)
 	case SearchTypeLiteral, SearchTypeStructural:
-		left, err = p.parseLeaves(Literal)
+		left, err = p.parseNear(Literal)
 	case SearchTypeStandard, SearchTypeLucky:
-		left, err = p.parseLeaves(Literal | Standard)
+		left, err = p.parseNear(Literal | Standard)
 	case SearchTypeNewStandardRC1:
-		left, err = p.parseLeaves(Literal | Standard | QuotesAsLiterals)
+		left, err = p.parseNear(Literal | Standard | QuotesAsLiterals)
 	default:
-		left, err = p.parseLeaves(Literal | Standard)
+		left, err = p.parseNear(Literal | Standard)
 	}
 	if err != nil {
 		return nil, err
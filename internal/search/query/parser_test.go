@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/grafana/regexp"
 	"github.com/hexops/autogold/v2"
 	"github.com/stretchr/testify/require"
 
@@ -852,3 +853,45 @@ func TestParseNewStandard(t *testing.T) {
 		autogold.ExpectFile(t, autogold.Raw(test(`"foo \"bar\""`)))
 	})
 }
+
+func TestParseNear(t *testing.T) {
+	test := func(input string, searchType SearchType) string {
+		result, err := Parse(input, searchType)
+		if err != nil {
+			return fmt.Sprintf("ERROR: %s", err.Error())
+		}
+		var resultStr []string
+		for _, node := range result {
+			resultStr = append(resultStr, node.String())
+		}
+		return strings.Join(resultStr, " ")
+	}
+
+	autogold.Expect(`(near/2 "retry" "timeout")`).Equal(t, test("retry NEAR/2 timeout", SearchTypeStandard))
+	autogold.Expect(`(and "repo:foo" (near/0 "retry" "timeout") "lang:go")`).Equal(t, test("repo:foo retry near/0 timeout lang:go", SearchTypeStandard))
+	autogold.Expect(`(near/1 "foo bar" "ba.z")`).Equal(t, test("foo bar NEAR/1 /ba.z/", SearchTypeStandard))
+	autogold.Expect(`(or (near/1 "a" "b") "c")`).Equal(t, test("(a NEAR/1 b) or c", SearchTypeStandard))
+	autogold.Expect(`(near/1 "a.b" "c")`).Equal(t, test("a.b NEAR/1 c", SearchTypeLiteral))
+	autogold.Expect(`(near/1 "a.b" "c")`).Equal(t, test("a.b NEAR/1 c", SearchTypeRegex))
+	autogold.Expect(`(concat "a" "NEAR/x" "b")`).Equal(t, test("a NEAR/x b", SearchTypeStandard))
+	autogold.Expect(`(concat "a" "NEAR/1")`).Equal(t, test("a NEAR/1", SearchTypeStandard))
+	autogold.Expect("ERROR: NEAR/n expects a single pattern on either side, not an expression").Equal(t, test("(a or b) NEAR/1 c", SearchTypeStandard))
+	autogold.Expect("ERROR: NEAR/n does not support negated patterns").Equal(t, test("not a NEAR/1 c", SearchTypeStandard))
+	autogold.Expect("ERROR: the distance of NEAR/101 is too large, it can be at most 100 lines").Equal(t, test("a NEAR/101 b", SearchTypeStandard))
+	autogold.Expect("ERROR: NEAR/n is not supported for structural search").Equal(t, test("a NEAR/1 b", SearchTypeStructural))
+	autogold.Expect("ERROR: NEAR/n expressions can't be chained").Equal(t, test("a NEAR/1 b NEAR/2 c", SearchTypeStandard))
+}
+
+func TestNearRegexp(t *testing.T) {
+	test := func(distance int, content string) []string {
+		return regexp.MustCompile(NearRegexp("retry", "timeout", distance)).FindAllString(content, -1)
+	}
+
+	content := "retry := 1\ntimeout := 2\n\n\nretry()\n"
+
+	autogold.Expect([]string{}).Equal(t, test(0, content))
+	autogold.Expect([]string{"retry := 1\ntimeout"}).Equal(t, test(1, content))
+	autogold.Expect([]string{"retry := 1\ntimeout"}).Equal(t, test(3, content))
+	autogold.Expect([]string{}).Equal(t, test(2, "timeout\n\n\nretry"))
+	autogold.Expect([]string{"timeout\n\n\nretry"}).Equal(t, test(3, "timeout\n\n\nretry"))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/regexp"
 	"github.com/grafana/regexp/syntax"
 
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
@@ -49,6 +50,7 @@ var DefaultPredicateRegistry = PredicateRegistry{
 		"has.content":      func() Predicate { return &FileContainsContentPredicate{} },
 		"has.owner":        func() Predicate { return &FileHasOwnerPredicate{} },
 		"has.contributor":  func() Predicate { return &FileHasContributorPredicate{} },
+		"has.symbol":       func() Predicate { return &FileHasSymbolPredicate{} },
 	},
 }
 
@@ -614,3 +616,74 @@ func (f *FileHasContributorPredicate) Unmarshal(params string, negated bool) err
 
 func (f FileHasContributorPredicate) Field() string { return FieldFile }
 func (f FileHasContributorPredicate) Name() string  { return "has.contributor" }
+
+/* file:has.symbol(pattern) */
+
+// FileHasSymbolPredicate represents the `file:has.symbol()` predicate, which
+// filters to files that define a symbol whose name matches Pattern and,
+// optionally, a symbol kind. The short form `file:has.symbol(Resolver$)` is
+// equivalent to `file:has.symbol(name:Resolver$)`.
+type FileHasSymbolPredicate struct {
+	Pattern string
+	Kind    string
+	Negated bool
+}
+
+func (f *FileHasSymbolPredicate) Unmarshal(params string, negated bool) error {
+	for _, arg := range strings.Fields(params) {
+		if err := f.parseArg(arg); err != nil {
+			return err
+		}
+	}
+
+	if f.Pattern == "" && f.Kind == "" {
+		return errors.New("the file:has.symbol() predicate requires one of name or kind to be set")
+	}
+	f.Negated = negated
+	return nil
+}
+
+// parseArg parses a single whitespace-separated argument, which is either of
+// the form `name:pattern`, `kind:kind`, or a bare name pattern.
+func (f *FileHasSymbolPredicate) parseArg(arg string) error {
+	if strings.HasPrefix(arg, "-") && strings.Contains(arg, ":") {
+		return errors.New("the file:has.symbol() predicate does not support negated values")
+	}
+
+	field, value, ok := strings.Cut(arg, ":")
+	if !ok {
+		return f.setName(arg)
+	}
+	switch strings.ToLower(field) {
+	case "name":
+		return f.setName(value)
+	case "kind":
+		if f.Kind != "" {
+			return errors.New("cannot specify kind multiple times")
+		}
+		kind := strings.ToLower(value)
+		if _, err := filter.SelectPathFromString(filter.Symbol + "." + kind); err != nil || kind == "" {
+			return errors.Errorf("the file:has.symbol() predicate has invalid `kind` argument %q", value)
+		}
+		f.Kind = kind
+		return nil
+	default:
+		// Not a recognized option, so treat the argument as a name pattern,
+		// e.g. a pattern containing `(?:...)`.
+		return f.setName(arg)
+	}
+}
+
+func (f *FileHasSymbolPredicate) setName(name string) error {
+	if f.Pattern != "" {
+		return errors.New("cannot specify name multiple times")
+	}
+	if _, err := syntax.Parse(name, syntax.Perl); err != nil {
+		return errors.Errorf("the file:has.symbol() predicate has invalid `name` argument: %w", err)
+	}
+	f.Pattern = name
+	return nil
+}
+
+func (f FileHasSymbolPredicate) Field() string { return FieldFile }
+func (f FileHasSymbolPredicate) Name() string  { return "has.symbol" }
@@ -366,3 +366,57 @@ func TestFileHasContributorPredicate(t *testing.T) {
 		}
 	})
 }
+
+func TestFileHasSymbolPredicate(t *testing.T) {
+	t.Run("Unmarshal", func(t *testing.T) {
+		type test struct {
+			name     string
+			params   string
+			expected *FileHasSymbolPredicate
+		}
+
+		valid := []test{
+			{`bare pattern`, `Resolver$`, &FileHasSymbolPredicate{Pattern: "Resolver$"}},
+			{`name`, `name:^New`, &FileHasSymbolPredicate{Pattern: "^New"}},
+			{`kind`, `kind:struct`, &FileHasSymbolPredicate{Kind: "struct"}},
+			{`kind is normalized`, `kind:Struct`, &FileHasSymbolPredicate{Kind: "struct"}},
+			{`kind and name`, `kind:struct name:^.*Store$`, &FileHasSymbolPredicate{Pattern: "^.*Store$", Kind: "struct"}},
+			{`kind and bare pattern`, `kind:function ^Test`, &FileHasSymbolPredicate{Pattern: "^Test", Kind: "function"}},
+			{`pattern containing colon`, `std::vector`, &FileHasSymbolPredicate{Pattern: "std::vector"}},
+		}
+
+		for _, tc := range valid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &FileHasSymbolPredicate{}
+				err := p.Unmarshal(tc.params, false)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !reflect.DeepEqual(tc.expected, p) {
+					t.Fatalf("expected %#v, got %#v", tc.expected, p)
+				}
+			})
+		}
+
+		invalid := []test{
+			{`empty`, ``, nil},
+			{`invalid kind`, `kind:banana`, nil},
+			{`invalid name regexp`, `name:(((`, nil},
+			{`name specified twice`, `name:a name:b`, nil},
+			{`name and bare pattern`, `name:a b`, nil},
+			{`negated name`, `-name:a`, nil},
+			{`or query`, `a or b`, nil},
+		}
+
+		for _, tc := range invalid {
+			t.Run(tc.name, func(t *testing.T) {
+				p := &FileHasSymbolPredicate{}
+				err := p.Unmarshal(tc.params, false)
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+			})
+		}
+	})
+}
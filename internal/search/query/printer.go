@@ -12,6 +12,12 @@ func stringHumanPattern(nodes []Node) string {
 	for _, node := range nodes {
 		switch n := node.(type) {
 		case Pattern:
+			if near := n.Annotation.Near; near != nil {
+				left := n
+				left.Annotation.Near = nil
+				result = append(result, fmt.Sprintf("%s NEAR/%d %s", stringHumanPattern([]Node{left}), near.Distance, stringHumanPattern([]Node{near.Pattern})))
+				continue
+			}
 			v := n.Value
 			if n.Annotation.Labels.IsSet(Quoted) {
 				v = strconv.Quote(v)
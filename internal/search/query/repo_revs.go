@@ -51,6 +51,16 @@ func (r1 RevisionSpecifier) HasRefGlob() bool {
 	return r1.RefGlob != "" || r1.ExcludeRefGlob != ""
 }
 
+// revisionCompareSeparator separates the base and head revision of a revision
+// compare, e.g. rev:v1.0...v2.0.
+const revisionCompareSeparator = "..."
+
+// CompareRange returns the base and head revision if r1 compares two
+// revisions with base...head.
+func (r1 RevisionSpecifier) CompareRange() (base, head string, ok bool) {
+	return strings.Cut(r1.RevSpec, revisionCompareSeparator)
+}
+
 type ParsedRepoFilter struct {
 	Repo      string
 	RepoRegex *regexp.Regexp // A case-insensitive regex matching the Repo pattern
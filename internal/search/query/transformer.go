@@ -545,6 +545,29 @@ func ConcatRevFilters(b Basic) Basic {
 	return Basic{Parameters: toParameters(modified), Pattern: b.Pattern}
 }
 
+// SplitRevisionCompare returns a query for the base and a query for the head
+// revision if b compares two revisions, i.e. it contains a repo:foo@base...head
+// filter. It expects rev: filters to already be concatenated by
+// ConcatRevFilters.
+// Invariant: Guaranteed to succeed on a validated Basic query.
+func SplitRevisionCompare(b Basic) (base, head Basic, ok bool) {
+	split := func(side func(base, head string) string) Basic {
+		nodes := MapField(toNodes(b.Parameters), FieldRepo, func(value string, negated bool, ann Annotation) Node {
+			if repo, revs, found := strings.Cut(value, "@"); found && !negated && !ann.Labels.IsSet(IsPredicate) {
+				if baseRev, headRev, isCompare := strings.Cut(revs, revisionCompareSeparator); isCompare {
+					ok = true
+					value = repo + "@" + side(baseRev, headRev)
+				}
+			}
+			return Parameter{Value: value, Field: FieldRepo, Negated: negated, Annotation: ann}
+		})
+		return Basic{Parameters: toParameters(nodes), Pattern: b.Pattern}
+	}
+	base = split(func(base, _ string) string { return base })
+	head = split(func(_, head string) string { return head })
+	return base, head, ok
+}
+
 // labelStructural converts Literal labels to Structural labels. Structural
 // queries are parsed the same as literal queries, we just convert the labels as
 // a postprocessing step to keep the parser lean.
@@ -466,6 +466,53 @@ func TestConcatRevFiltersTopLevelAnd(t *testing.T) {
 	}
 }
 
+func TestSplitRevisionCompare(t *testing.T) {
+	cases := []struct {
+		input    string
+		wantBase string
+		wantHead string
+		wantOk   bool
+	}{
+		{
+			input:  "repo:foo rev:a bar",
+			wantOk: false,
+		},
+		{
+			input:    "repo:foo rev:v1.0...v2.0 bar",
+			wantBase: `"repo:foo@v1.0" "bar"`,
+			wantHead: `"repo:foo@v2.0" "bar"`,
+			wantOk:   true,
+		},
+		{
+			input:    "repo:foo@main...feature -repo:baz bar",
+			wantBase: `"repo:foo@main" "-repo:baz" "bar"`,
+			wantHead: `"repo:foo@feature" "-repo:baz" "bar"`,
+			wantOk:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			plan, err := Pipeline(InitLiteral(c.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			base, head, ok := SplitRevisionCompare(plan[0])
+			if ok != c.wantOk {
+				t.Fatalf("got ok %v, want %v", ok, c.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(c.wantBase, toString(base.ToParseTree())); diff != "" {
+				t.Error(diff)
+			}
+			if diff := cmp.Diff(c.wantHead, toString(head.ToParseTree())); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
 func TestQueryField(t *testing.T) {
 	test := func(input, field string) string {
 		q, _ := ParseLiteral(input)
@@ -257,6 +257,17 @@ func (b Basic) MapCount(count int) Basic {
 	return Basic{Parameters: toParameters(parameters), Pattern: b.Pattern}
 }
 
+// WithCount returns b with the count parameter set to count. The parameter
+// is added if b doesn't have one.
+func (b Basic) WithCount(count int) Basic {
+	if b.Count() != nil {
+		return b.MapCount(count)
+	}
+	parameters := append(Parameters{}, b.Parameters...)
+	b.Parameters = append(parameters, Parameter{Field: FieldCount, Value: strconv.Itoa(count)})
+	return b
+}
+
 func (b Basic) String() string {
 	return b.toString(func(nodes []Node) string {
 		return Q(nodes).String()
@@ -318,6 +329,30 @@ func (b Basic) PatternString() string {
 	return ""
 }
 
+// NearPattern returns the regular expression pattern of the right-hand side of
+// a NEAR/n expression and its distance, if the pattern of a basic query is
+// one. The left-hand side is returned by PatternString.
+func (b Basic) NearPattern() (pattern string, distance int, ok bool) {
+	p, isPattern := b.Pattern.(Pattern)
+	if !isPattern || p.Annotation.Near == nil {
+		return "", 0, false
+	}
+	near := p.Annotation.Near
+	if near.Pattern.Annotation.Labels.IsSet(Literal) {
+		return regexp.QuoteMeta(near.Pattern.Value), near.Distance, true
+	}
+	return near.Pattern.Value, near.Distance, true
+}
+
+// NearRegexp returns a regular expression that matches the regular
+// expressions left and right, in either order, when they are at most distance
+// lines apart. A match spans from the first term to the second. It is used
+// where NEAR/n can't be evaluated natively, like in Zoekt and commit search.
+func NearRegexp(left, right string, distance int) string {
+	gap := fmt.Sprintf(`(?:[^\n]*\n){0,%d}?[^\n]*?`, distance)
+	return fmt.Sprintf("(?:%s)%s(?:%s)|(?:%s)%s(?:%s)", left, gap, right, right, gap, left)
+}
+
 func (b Basic) IsEmptyPattern() bool {
 	if b.Pattern == nil {
 		return true
@@ -508,6 +543,17 @@ func (p Parameters) FileHasContributor() (include []string, exclude []string) {
 	return include, exclude
 }
 
+func (p Parameters) FileHasSymbol() (include []FileHasSymbolPredicate, exclude []FileHasSymbolPredicate) {
+	VisitTypedPredicate(toNodes(p), func(pred *FileHasSymbolPredicate) {
+		if pred.Negated {
+			exclude = append(exclude, *pred)
+		} else {
+			include = append(include, *pred)
+		}
+	})
+	return include, exclude
+}
+
 // Exists returns whether a parameter exists in the query (whether negated or not).
 func (p Parameters) Exists(field string) bool {
 	found := false
@@ -562,6 +608,19 @@ func (p Parameters) GetTimeout() *time.Duration {
 	return timeout
 }
 
+// At returns the point in time specified by the `at:` field, relative to now.
+func (p Parameters) At(now func() time.Time) *time.Time {
+	var at *time.Time
+	VisitField(toNodes(p), FieldAt, func(value string, _ bool, _ Annotation) {
+		t, err := ParseGitDate(value, now)
+		if err != nil {
+			panic(fmt.Sprintf("Value %q for at cannot be parsed as a date: %s", value, err))
+		}
+		at = &t
+	})
+	return at
+}
+
 func (p Parameters) VisitParameter(field string, f func(value string, negated bool, annotation Annotation)) {
 	for _, parameter := range p {
 		if parameter.Field == field {
@@ -582,6 +641,12 @@ func (p Parameters) IsCaseSensitive() bool {
 	return p.boolValue(FieldCase)
 }
 
+// IsMultiline returns whether "." in regular expression patterns matches
+// newlines, as set by multiline:yes.
+func (p Parameters) IsMultiline() bool {
+	return p.boolValue(FieldMultiline)
+}
+
 func (p Parameters) yesNoOnlyValue(field string) *YesNoOnly {
 	var res *YesNoOnly
 	VisitField(toNodes(p), field, func(value string, _ bool, _ Annotation) {
@@ -241,7 +241,8 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		FieldDefault:
 		// Search patterns are not validated here, as it depends on the search type.
 	case
-		FieldCase:
+		FieldCase,
+		FieldMultiline:
 		return satisfies(isSingular, isBoolean, isNotNegated)
 	case
 		FieldRepo:
@@ -295,6 +296,9 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldRev:
 		return satisfies(isSingular, isNotNegated)
+	case
+		FieldAt:
+		return satisfies(isSingular, isNotNegated, isValidGitDate)
 	case
 		FieldSelect:
 		return satisfies(isSingular, isNotNegated, isValidSelect)
@@ -396,6 +400,77 @@ func validateRefGlobs(nodes []Node) error {
 	return nil
 }
 
+// validateAt validates that a query containing at: can be executed. Revisions
+// resolved for a point in time are plain commits, which indexed search cannot
+// search unless they happen to be the indexed commit.
+func validateAt(nodes []Node) error {
+	if !Exists(nodes, func(node Node) bool {
+		n, ok := node.(Parameter)
+		return ok && n.Field == FieldAt
+	}) {
+		return nil
+	}
+	var indexValue string
+	VisitField(nodes, FieldIndex, func(value string, _ bool, _ Annotation) {
+		indexValue = value
+	})
+	if parseYesNoOnly(indexValue) == Only {
+		return errors.Errorf("invalid index:%s (revisions at a point in time cannot be resolved for indexed searches)", indexValue)
+	}
+	return nil
+}
+
+// validateRevisionCompare validates revisions which compare two revisions
+// with base...head. Only file contents can be compared, and each side must be a
+// single revision.
+func validateRevisionCompare(nodes []Node) error {
+	var compares []string
+	visitRevs := func(revs string) {
+		if strings.Contains(revs, revisionCompareSeparator) {
+			compares = append(compares, revs)
+		}
+	}
+	VisitField(nodes, FieldRev, func(value string, _ bool, _ Annotation) {
+		visitRevs(value)
+	})
+	VisitField(nodes, FieldRepo, func(value string, negated bool, ann Annotation) {
+		if _, revs, found := strings.Cut(value, "@"); found && !negated && !ann.Labels.IsSet(IsPredicate) {
+			visitRevs(revs)
+		}
+	})
+	if len(compares) == 0 {
+		return nil
+	}
+
+	for _, revs := range compares {
+		if strings.Contains(revs, ":") {
+			return errors.Errorf("invalid revision %q (only one pair of revisions can be compared)", revs)
+		}
+		base, head, _ := ParseRevisionSpecifier(revs).CompareRange()
+		if base == "" || head == "" || strings.Contains(head, revisionCompareSeparator) || strings.HasPrefix(base, "*") || strings.HasPrefix(head, "*") {
+			return errors.Errorf("invalid revision %q (expected two revisions to compare, e.g. v1.0...v2.0)", revs)
+		}
+	}
+
+	var err error
+	VisitParameter(nodes, func(field, value string, _ bool, _ Annotation) {
+		if err != nil {
+			return
+		}
+		switch field {
+		case FieldType:
+			if value != "file" {
+				err = errors.Errorf("invalid type:%s (comparing revisions only supports searching file contents)", value)
+			}
+		case FieldSelect:
+			err = errors.New("select: cannot be used when comparing revisions")
+		case FieldAt:
+			err = errors.New("at: cannot be used when comparing revisions")
+		}
+	})
+	return err
+}
+
 // validatePredicates validates predicate parameters with respect to their validation logic.
 func validatePredicate(field, value string, negated bool) error {
 	name, params := ParseAsPredicate(value)                // guaranteed to succeed
@@ -475,6 +550,9 @@ func validatePattern(nodes []Node) error {
 		if annotation.Labels.IsSet(Regexp) {
 			_, err = regexp.Compile(value)
 		}
+		if near := annotation.Near; err == nil && near != nil && near.Pattern.Annotation.Labels.IsSet(Regexp) {
+			_, err = regexp.Compile(near.Pattern.Value)
+		}
 		if annotation.Labels.IsSet(Structural) && negated {
 			err = errors.New("the query contains a negated search pattern. Structural search does not support negated search patterns at the moment")
 		}
@@ -500,6 +578,8 @@ func validate(nodes []Node) error {
 		validateCommitParameters,
 		validateTypeStructural,
 		validateRefGlobs,
+		validateAt,
+		validateRevisionCompare,
 	)
 }
 
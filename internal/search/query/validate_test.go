@@ -21,6 +21,10 @@ func TestValidation(t *testing.T) {
 			input: "case:yes case:no",
 			want:  `field "case" may not be used more than once`,
 		},
+		{
+			input: "multiline:maybe",
+			want:  `invalid boolean "maybe"`,
+		},
 		{
 			input: "repo:[",
 			want:  "error parsing regexp: missing closing ]: `[`",
@@ -107,10 +111,46 @@ func TestValidation(t *testing.T) {
 			input: "-context:a",
 			want:  `field "context" does not support negation`,
 		},
+		{
+			input: "foo at:2025-06-01 at:2025-07-01",
+			want:  `field "at" may not be used more than once`,
+		},
+		{
+			input: "foo -at:2025-06-01",
+			want:  `field "at" does not support negation`,
+		},
+		{
+			input: "foo at:2025-06-01 index:only",
+			want:  "invalid index:only (revisions at a point in time cannot be resolved for indexed searches)",
+		},
 		{
 			input: "type:symbol select:symbol.timelime",
 			want:  `invalid field "timelime" on select path "symbol.timelime"`,
 		},
+		{
+			input: "repo:foo rev:v1.0...v2.0:main bar",
+			want:  `invalid revision "v1.0...v2.0:main" (only one pair of revisions can be compared)`,
+		},
+		{
+			input: "repo:foo rev:v1.0... bar",
+			want:  `invalid revision "v1.0..." (expected two revisions to compare, e.g. v1.0...v2.0)`,
+		},
+		{
+			input: "repo:foo@*refs/tags/*...main bar",
+			want:  `invalid revision "*refs/tags/*...main" (expected two revisions to compare, e.g. v1.0...v2.0)`,
+		},
+		{
+			input: "repo:foo rev:v1.0...v2.0 type:diff bar",
+			want:  "invalid type:diff (comparing revisions only supports searching file contents)",
+		},
+		{
+			input: "repo:foo rev:v1.0...v2.0 select:repo bar",
+			want:  "select: cannot be used when comparing revisions",
+		},
+		{
+			input: "foo select:file.rollup.0",
+			want:  `invalid directory depth "0" on select path "file.rollup.0", expected a positive number`,
+		},
 		{
 			input:      "nice try type:repo",
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents",
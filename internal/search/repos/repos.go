@@ -1,14 +1,17 @@
 package repos
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/go-git/go-git/v5/plumbing/format/config"
 	"github.com/grafana/regexp"
 	regexpsyntax "github.com/grafana/regexp/syntax"
 	"github.com/sourcegraph/conc/pool"
@@ -25,6 +28,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search"
@@ -399,6 +403,24 @@ func (r *Resolver) filterGitserver(ctx context.Context, tr trace.Trace, op searc
 	}
 	tr.AddEvent("completed rev filtering")
 
+	tr.AddEvent("starting point in time resolution")
+	filteredRepoRevs, err = r.resolveRevsAt(ctx, filteredRepoRevs, op)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "resolve revisions at")
+	}
+	tr.AddEvent("completed point in time resolution")
+
+	// Listing the submodules requires a few gitserver calls for each
+	// revision, so we only search them when searching a single repository.
+	if ExactlyOneRepo(op.RepoFilters) {
+		tr.AddEvent("starting submodule resolution")
+		filteredRepoRevs, err = r.addSubmoduleRepoRevs(ctx, filteredRepoRevs)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "add submodule revisions")
+		}
+		tr.AddEvent("completed submodule resolution")
+	}
+
 	return filteredRepoRevs, normalizedMissingRepoRevs, nil
 }
 
@@ -635,6 +657,193 @@ func (r *Resolver) filterHasCommitAfter(
 	return filteredRepoRevs, nil
 }
 
+// resolveRevsAt replaces the revisions on each of a set of RepositoryRevisions
+// with the last commit on that revision before the point in time specified by
+// `at:`. Only first parents are followed, so that the commits of a branch
+// that was merged later don't count as being on the revision at that time.
+// Revisions without any commit before that point in time are dropped.
+func (r *Resolver) resolveRevsAt(
+	ctx context.Context,
+	repoRevs []*search.RepositoryRevisions,
+	op search.RepoOptions,
+) (
+	[]*search.RepositoryRevisions,
+	error,
+) {
+	// Early return if at: is not set
+	if op.At == nil {
+		return repoRevs, nil
+	}
+	before := op.At.Format(time.RFC3339)
+
+	p := pool.New().WithContext(ctx).WithMaxGoroutines(128)
+
+	for _, repoRev := range repoRevs {
+		repoRev := repoRev
+
+		allRevs := repoRev.Revs
+		commits := make([]string, len(allRevs))
+		repoRev.Revs = commits
+
+		for i, rev := range allRevs {
+			i, rev := i, rev
+			if rev == "" {
+				rev = "HEAD"
+			}
+			p.Go(func(ctx context.Context) error {
+				found, err := r.gitserver.Commits(ctx, repoRev.Repo.Name, gitserver.CommitsOptions{
+					Range:            rev,
+					Before:           before,
+					N:                1,
+					FirstParent:      true,
+					NoEnsureRevision: true,
+				})
+				if err != nil {
+					if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) || gitdomain.IsRepoNotExist(err) {
+						// If the revision does not exist or the repo does not exist,
+						// there is nothing to search at that point in time.
+						return nil
+					}
+					return err
+				}
+				if len(found) > 0 {
+					commits[i] = string(found[0].ID)
+				}
+				return nil
+			})
+		}
+	}
+
+	if err := p.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Filter out unresolved and duplicate commits, and any repo revs left
+	// with empty revs
+	filteredRepoRevs := repoRevs[:0]
+	for _, repoRev := range repoRevs {
+		seen := make(map[string]struct{}, len(repoRev.Revs))
+		filteredRevs := repoRev.Revs[:0]
+		for _, commit := range repoRev.Revs {
+			if _, ok := seen[commit]; ok || commit == "" {
+				continue
+			}
+			seen[commit] = struct{}{}
+			filteredRevs = append(filteredRevs, commit)
+		}
+		repoRev.Revs = filteredRevs
+		if len(repoRev.Revs) > 0 {
+			filteredRepoRevs = append(filteredRepoRevs, repoRev)
+		}
+	}
+
+	return filteredRepoRevs, nil
+}
+
+// addSubmoduleRepoRevs adds the commits that the submodules of repoRevs point
+// to, if the repositories of the submodules are known to this instance and
+// visible to the current user. Submodules of submodules aren't added.
+func (r *Resolver) addSubmoduleRepoRevs(ctx context.Context, repoRevs []*search.RepositoryRevisions) ([]*search.RepositoryRevisions, error) {
+	var (
+		mu         sync.Mutex
+		submodules []gitdomain.Submodule
+	)
+
+	p := pool.New().WithContext(ctx).WithMaxGoroutines(16)
+	for _, repoRev := range repoRevs {
+		for _, rev := range repoRev.Revs {
+			repo, rev := repoRev.Repo.Name, rev
+			p.Go(func(ctx context.Context) error {
+				found, err := r.submodules(ctx, repo, rev)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				submodules = append(submodules, found...)
+				mu.Unlock()
+				return nil
+			})
+		}
+	}
+	if err := p.Wait(); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[api.RepoName]*search.RepositoryRevisions, len(repoRevs))
+	for _, repoRev := range repoRevs {
+		byName[repoRev.Repo.Name] = repoRev
+	}
+	for _, submodule := range submodules {
+		repoRev, ok := byName[submodule.Repo]
+		if !ok {
+			// 🚨 SECURITY: The repos store only returns repositories the
+			// current user has access to.
+			repo, err := r.db.Repos().GetByName(ctx, submodule.Repo)
+			if err != nil {
+				if errcode.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			repoRev = &search.RepositoryRevisions{
+				Repo: types.MinimalRepo{ID: repo.ID, Name: repo.Name, Stars: repo.Stars},
+			}
+			byName[repo.Name] = repoRev
+			repoRevs = append(repoRevs, repoRev)
+		}
+		if !slices.Contains(repoRev.Revs, string(submodule.CommitID)) {
+			repoRev.Revs = append(repoRev.Revs, string(submodule.CommitID))
+		}
+	}
+
+	return repoRevs, nil
+}
+
+// submodules returns the submodules of repo at rev that point to a repository
+// known to this instance.
+func (r *Resolver) submodules(ctx context.Context, repo api.RepoName, rev string) ([]gitdomain.Submodule, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	commit, err := r.gitserver.ResolveRevision(ctx, repo, rev, gitserver.ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		if errors.HasType(err, &gitdomain.RevisionNotFoundError{}) || gitdomain.IsRepoNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	gitmodules, err := r.gitserver.ReadFile(ctx, repo, commit, ".gitmodules")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var cfg config.Config
+	if err := config.NewDecoder(bytes.NewReader(gitmodules)).Decode(&cfg); err != nil {
+		// Like git, treat a malformed .gitmodules file as not configuring
+		// any submodules.
+		return nil, nil
+	}
+
+	var submodules []gitdomain.Submodule
+	for _, subsection := range cfg.Section("submodule").Subsections {
+		fi, err := r.gitserver.Stat(ctx, repo, commit, subsection.Option("path"))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The submodule is configured but wasn't added.
+				continue
+			}
+			return nil, err
+		}
+		if submodule, ok := fi.Sys().(gitdomain.Submodule); ok && submodule.Repo != "" {
+			submodules = append(submodules, submodule)
+		}
+	}
+	return submodules, nil
+}
+
 // filterRepoHasFileContent filters a page of repos to only those that match the
 // given contains predicates in RepoOptions.HasFileContent.
 // Brief overview of the method:
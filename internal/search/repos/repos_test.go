@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
 	"reflect"
 	"testing"
@@ -24,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	"github.com/sourcegraph/sourcegraph/internal/fileutil"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search"
@@ -876,3 +878,179 @@ func TestRepoHasCommitAfter(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveRevsAt(t *testing.T) {
+	repoA := types.MinimalRepo{ID: 1, Name: "example.com/1"}
+	repoB := types.MinimalRepo{ID: 2, Name: "example.com/2"}
+	repoC := types.MinimalRepo{ID: 3, Name: "example.com/3"}
+
+	at := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	mockGitserver := gitserver.NewMockClient()
+	mockGitserver.CommitsFunc.SetDefaultHook(func(_ context.Context, repoName api.RepoName, opts gitserver.CommitsOptions) ([]*gitdomain.Commit, error) {
+		require.Equal(t, "2025-06-01T00:00:00Z", opts.Before)
+		require.Equal(t, uint(1), opts.N)
+		require.True(t, opts.FirstParent)
+		switch repoName {
+		case repoA.Name:
+			return []*gitdomain.Commit{{ID: api.CommitID("a-" + opts.Range)}}, nil
+		case repoB.Name:
+			// No commits before the point in time
+			return nil, nil
+		case repoC.Name:
+			return nil, &gitdomain.RevisionNotFoundError{}
+		default:
+			panic("unreachable")
+		}
+	})
+
+	repos := dbmocks.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultHook(func(_ context.Context, opts database.ReposListOptions) ([]types.MinimalRepo, error) {
+		res := []types.MinimalRepo{}
+		for _, r := range []types.MinimalRepo{repoA, repoB, repoC} {
+			if matched, _ := regexp.MatchString(opts.IncludePatterns[0], string(r.Name)); matched {
+				res = append(res, r)
+			}
+		}
+		return res, nil
+	})
+
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	cases := []struct {
+		name       string
+		nameFilter string
+		at         *time.Time
+		expected   []*search.RepositoryRevisions
+		err        error
+	}{{
+		name:       "no at",
+		nameFilter: "example.com/1",
+		expected:   []*search.RepositoryRevisions{{Repo: repoA, Revs: []string{""}}},
+	}, {
+		name:       "at resolves HEAD",
+		nameFilter: ".*",
+		at:         &at,
+		expected:   []*search.RepositoryRevisions{{Repo: repoA, Revs: []string{"a-HEAD"}}},
+	}, {
+		name:       "no commit before at",
+		nameFilter: "example.com/2",
+		at:         &at,
+		expected:   []*search.RepositoryRevisions{},
+	}, {
+		name:       "missing revision",
+		nameFilter: "example.com/3",
+		at:         &at,
+		expected:   []*search.RepositoryRevisions{},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NewResolver(logtest.Scoped(t), db, nil, endpoint.Static("test"), nil)
+			res.gitserver = mockGitserver
+			resolved, _, err := res.resolve(context.Background(), search.RepoOptions{
+				RepoFilters: toParsedRepoFilters(tc.nameFilter),
+				At:          tc.at,
+			})
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.expected, resolved.RepoRevs)
+		})
+	}
+}
+
+func TestAddSubmoduleRepoRevs(t *testing.T) {
+	repoA := types.MinimalRepo{ID: 1, Name: "example.com/1"}
+	repoB := types.MinimalRepo{ID: 2, Name: "example.com/2"}
+	repoC := types.MinimalRepo{ID: 3, Name: "example.com/3"}
+
+	const gitmodules = `[submodule "lib"]
+	path = lib
+	url = https://example.com/2
+[submodule "private"]
+	path = private
+	url = https://example.com/3
+[submodule "unknown"]
+	path = unknown
+	url = https://git.example.org/unknown
+[submodule "removed"]
+	path = removed
+	url = https://example.com/2
+`
+	submodules := map[string]gitdomain.Submodule{
+		"lib":     {Path: "lib", CommitID: "b-commit", Repo: repoB.Name},
+		"private": {Path: "private", CommitID: "c-commit", Repo: repoC.Name},
+		"unknown": {Path: "unknown", CommitID: "d-commit"},
+	}
+
+	mockGitserver := gitserver.NewMockClient()
+	mockGitserver.ResolveRevisionFunc.SetDefaultHook(func(_ context.Context, repo api.RepoName, spec string, _ gitserver.ResolveRevisionOptions) (api.CommitID, error) {
+		require.Equal(t, repoA.Name, repo)
+		require.Equal(t, "HEAD", spec)
+		return "a-commit", nil
+	})
+	mockGitserver.ReadFileFunc.SetDefaultHook(func(_ context.Context, repo api.RepoName, commit api.CommitID, name string) ([]byte, error) {
+		require.Equal(t, api.CommitID("a-commit"), commit)
+		require.Equal(t, ".gitmodules", name)
+		return []byte(gitmodules), nil
+	})
+	mockGitserver.StatFunc.SetDefaultHook(func(_ context.Context, repo api.RepoName, commit api.CommitID, path string) (fs.FileInfo, error) {
+		submodule, ok := submodules[path]
+		if !ok {
+			return nil, &os.PathError{Op: "ls-tree", Path: path, Err: os.ErrNotExist}
+		}
+		return &fileutil.FileInfo{Name_: path, Mode_: gitdomain.ModeSubmodule, Sys_: submodule}, nil
+	})
+
+	repos := dbmocks.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultHook(func(_ context.Context, opts database.ReposListOptions) ([]types.MinimalRepo, error) {
+		res := []types.MinimalRepo{}
+		for _, r := range []types.MinimalRepo{repoA, repoB} {
+			if matched, _ := regexp.MatchString(opts.IncludePatterns[0], string(r.Name)); matched {
+				res = append(res, r)
+			}
+		}
+		return res, nil
+	})
+	repos.GetByNameFunc.SetDefaultHook(func(_ context.Context, name api.RepoName) (*types.Repo, error) {
+		// repoC isn't visible to the current user.
+		if name == repoB.Name {
+			return &types.Repo{ID: repoB.ID, Name: repoB.Name}, nil
+		}
+		return nil, &database.RepoNotFoundErr{Name: name}
+	})
+
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	cases := []struct {
+		name       string
+		nameFilter string
+		expected   []*search.RepositoryRevisions
+	}{{
+		name:       "single repository",
+		nameFilter: `^example\.com/1$`,
+		expected: []*search.RepositoryRevisions{
+			{Repo: repoA, Revs: []string{""}},
+			{Repo: repoB, Revs: []string{"b-commit"}},
+		},
+	}, {
+		name:       "multiple repositories",
+		nameFilter: `example\.com`,
+		expected: []*search.RepositoryRevisions{
+			{Repo: repoA, Revs: []string{""}},
+			{Repo: repoB, Revs: []string{""}},
+		},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := NewResolver(logtest.Scoped(t), db, mockGitserver, endpoint.Static("test"), nil)
+			resolved, _, err := res.resolve(context.Background(), search.RepoOptions{
+				RepoFilters: toParsedRepoFilters(tc.nameFilter),
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, resolved.RepoRevs)
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package result
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// CompareStatus describes how a matched line changed between the base and the
+// head revision of a revision compare.
+type CompareStatus string
+
+const (
+	// CompareAdded is a line which only matches in the head revision.
+	CompareAdded CompareStatus = "added"
+	// CompareRemoved is a line which only matches in the base revision.
+	CompareRemoved CompareStatus = "removed"
+	// CompareMoved is a line which matches in both revisions, but moved
+	// relative to the other matched lines of the file.
+	CompareMoved CompareStatus = "moved"
+)
+
+// CompareLine is a matched line which differs between the base and the head
+// revision of a revision compare.
+type CompareLine struct {
+	Status CompareStatus
+
+	// Preview is the content of the line.
+	Preview string
+
+	// BaseLineNumber and HeadLineNumber are the 0-based line numbers of the
+	// line in the base and head revision. They are -1 if the line does not
+	// match in that revision.
+	BaseLineNumber int32
+	HeadLineNumber int32
+
+	// OffsetAndLengths are the matched ranges in the line.
+	OffsetAndLengths [][2]int32
+}
+
+// CompareMatch is a file whose matches differ between the base and the head
+// revision of a search with rev:base...head.
+type CompareMatch struct {
+	Repo types.MinimalRepo `json:"-"`
+	Path string
+
+	BaseRev    string
+	HeadRev    string
+	BaseCommit api.CommitID `json:"-"`
+	HeadCommit api.CommitID `json:"-"`
+
+	Lines []CompareLine
+
+	LimitHit bool
+}
+
+// maxCompareLinesProduct bounds the size of the table used to find the
+// longest sequence of unchanged lines. Files with more matches than that are
+// compared without detecting moved lines.
+const maxCompareLinesProduct = 1 << 20
+
+// NewCompareMatch compares the matched lines of a file in the base and the
+// head revision. Either file match may be nil if the file has no matches in
+// that revision. It returns nil if the same lines match in the same order in
+// both revisions.
+//
+// Lines which match in both revisions and keep their order relative to each
+// other are unchanged, even if their line numbers changed. Lines which match in
+// both revisions but not in that order are moved, the remaining lines are
+// added or removed.
+func NewCompareMatch(base, head *FileMatch) *CompareMatch {
+	baseLines := matchedLines(base)
+	headLines := matchedLines(head)
+
+	var lines []CompareLine
+	removed := func(l *LineMatch) {
+		lines = append(lines, CompareLine{
+			Status:           CompareRemoved,
+			Preview:          l.Preview,
+			BaseLineNumber:   l.LineNumber,
+			HeadLineNumber:   -1,
+			OffsetAndLengths: l.OffsetAndLengths,
+		})
+	}
+
+	// moved pairs up the lines of the base and head revision with the same
+	// content which are not part of the common subsequence.
+	unchanged := commonLines(baseLines, headLines)
+	movedFrom := map[string][]*LineMatch{}
+	for i, l := range baseLines {
+		if _, ok := unchanged[i]; !ok {
+			movedFrom[l.Preview] = append(movedFrom[l.Preview], l)
+		}
+	}
+	headIndexes := make(map[int]struct{}, len(unchanged))
+	for _, j := range unchanged {
+		headIndexes[j] = struct{}{}
+	}
+	movedTo := map[*LineMatch]*LineMatch{}
+	for j, l := range headLines {
+		if _, ok := headIndexes[j]; ok {
+			continue
+		}
+		if from := movedFrom[l.Preview]; len(from) > 0 {
+			movedTo[from[0]] = l
+			movedFrom[l.Preview] = from[1:]
+		}
+	}
+	moved := make(map[*LineMatch]*LineMatch, len(movedTo))
+	for from, to := range movedTo {
+		moved[to] = from
+	}
+
+	// Walk both revisions like a diff: before each unchanged line, emit the
+	// lines removed from base and then the lines added to or moved in head.
+	i, j := 0, 0
+	flush := func(untilBase, untilHead int) {
+		for ; i < untilBase; i++ {
+			if _, ok := movedTo[baseLines[i]]; !ok {
+				removed(baseLines[i])
+			}
+		}
+		for ; j < untilHead; j++ {
+			l := headLines[j]
+			if from, ok := moved[l]; ok {
+				lines = append(lines, CompareLine{
+					Status:           CompareMoved,
+					Preview:          l.Preview,
+					BaseLineNumber:   from.LineNumber,
+					HeadLineNumber:   l.LineNumber,
+					OffsetAndLengths: l.OffsetAndLengths,
+				})
+			} else {
+				lines = append(lines, CompareLine{
+					Status:           CompareAdded,
+					Preview:          l.Preview,
+					BaseLineNumber:   -1,
+					HeadLineNumber:   l.LineNumber,
+					OffsetAndLengths: l.OffsetAndLengths,
+				})
+			}
+		}
+	}
+	for bi := range baseLines {
+		if hj, ok := unchanged[bi]; ok {
+			flush(bi, hj)
+			i, j = bi+1, hj+1
+		}
+	}
+	flush(len(baseLines), len(headLines))
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	cm := &CompareMatch{Lines: lines}
+	for _, fm := range []*FileMatch{base, head} {
+		if fm != nil {
+			cm.Repo = fm.Repo
+			cm.Path = fm.Path
+			cm.LimitHit = cm.LimitHit || fm.LimitHit
+		}
+	}
+	if base != nil {
+		cm.BaseCommit = base.CommitID
+		if base.InputRev != nil {
+			cm.BaseRev = *base.InputRev
+		}
+	}
+	if head != nil {
+		cm.HeadCommit = head.CommitID
+		if head.InputRev != nil {
+			cm.HeadRev = *head.InputRev
+		}
+	}
+	return cm
+}
+
+// matchedLines returns the lines of fm which contain a match, in order.
+func matchedLines(fm *FileMatch) []*LineMatch {
+	if fm == nil {
+		return nil
+	}
+	var lines []*LineMatch
+	for _, l := range fm.ChunkMatches.AsLineMatches() {
+		if len(l.OffsetAndLengths) > 0 {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// commonLines returns the longest common subsequence of base and head by
+// content, as a map from the index in base to the index in head.
+func commonLines(base, head []*LineMatch) map[int]int {
+	common := map[int]int{}
+	if len(base)*len(head) > maxCompareLinesProduct {
+		// Too large to compare, treat lines with the same content as
+		// unchanged in order.
+		j := 0
+		for i, l := range base {
+			for k := j; k < len(head); k++ {
+				if head[k].Preview == l.Preview {
+					common[i] = k
+					j = k + 1
+					break
+				}
+			}
+		}
+		return common
+	}
+
+	// lengths[i][j] is the length of the longest common subsequence of
+	// base[i:] and head[j:].
+	lengths := make([][]int, len(base)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(head)+1)
+	}
+	for i := len(base) - 1; i >= 0; i-- {
+		for j := len(head) - 1; j >= 0; j-- {
+			if base[i].Preview == head[j].Preview {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(base) && j < len(head); {
+		switch {
+		case base[i].Preview == head[j].Preview:
+			common[i] = j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return common
+}
+
+func (cm *CompareMatch) RepoName() types.MinimalRepo {
+	return cm.Repo
+}
+
+func (cm *CompareMatch) ResultCount() int {
+	return len(cm.Lines)
+}
+
+func (cm *CompareMatch) Select(path filter.SelectPath) Match {
+	switch path.Root() {
+	case filter.Repository:
+		return &RepoMatch{
+			Name: cm.Repo.Name,
+			ID:   cm.Repo.ID,
+		}
+	}
+	return nil
+}
+
+// Limit will mutate cm such that it only has limit lines.
+func (cm *CompareMatch) Limit(limit int) int {
+	if limit >= len(cm.Lines) {
+		return limit - len(cm.Lines)
+	}
+	cm.Lines = cm.Lines[:limit]
+	cm.LimitHit = true
+	return 0
+}
+
+func (cm *CompareMatch) Key() Key {
+	return Key{
+		TypeRank: rankCompareMatch,
+		Repo:     cm.Repo.Name,
+		Rev:      cm.BaseRev + "..." + cm.HeadRev,
+		Commit:   cm.HeadCommit,
+		Path:     cm.Path,
+	}
+}
+
+func (cm *CompareMatch) searchResultMarker() {}
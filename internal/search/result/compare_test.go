@@ -0,0 +1,81 @@
+package result
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestNewCompareMatch(t *testing.T) {
+	// fileMatch returns a file match with a match on each of the lines in
+	// content which contain "x".
+	fileMatch := func(rev, content string) *FileMatch {
+		fm := &FileMatch{
+			File: File{
+				Repo:     types.MinimalRepo{ID: 1, Name: "repo"},
+				CommitID: api.CommitID("commit-" + rev),
+				InputRev: &rev,
+				Path:     "a.go",
+			},
+		}
+		for i, line := range strings.Split(content, "\n") {
+			if !strings.Contains(line, "x") {
+				continue
+			}
+			fm.ChunkMatches = append(fm.ChunkMatches, ChunkMatch{
+				Content:      line,
+				ContentStart: Location{Line: i},
+				Ranges: Ranges{{
+					Start: Location{Line: i, Column: 0},
+					End:   Location{Line: i, Column: 1},
+				}},
+			})
+		}
+		return fm
+	}
+
+	format := func(cm *CompareMatch) string {
+		if cm == nil {
+			return "<nil>"
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s %s...%s\n", cm.Path, cm.BaseRev, cm.HeadRev)
+		for _, l := range cm.Lines {
+			fmt.Fprintf(&b, "%s %d %d %s\n", l.Status, l.BaseLineNumber, l.HeadLineNumber, l.Preview)
+		}
+		return b.String()
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		cm := NewCompareMatch(fileMatch("a", "x1\ny\nx2"), fileMatch("b", "new\nx1\ny\nx2"))
+		require.Nil(t, cm)
+	})
+
+	t.Run("added and removed", func(t *testing.T) {
+		cm := NewCompareMatch(fileMatch("a", "x1\nx2\nx3"), fileMatch("b", "x1\nx4\nx3"))
+		autogold.Expect(`a.go a...b
+removed 1 -1 x2
+added -1 1 x4
+`).Equal(t, format(cm))
+	})
+
+	t.Run("moved", func(t *testing.T) {
+		cm := NewCompareMatch(fileMatch("a", "x1\nx2\nx3\nx4"), fileMatch("b", "x2\nx3\nx4\nx1"))
+		autogold.Expect(`a.go a...b
+moved 0 3 x1
+`).Equal(t, format(cm))
+	})
+
+	t.Run("only in base", func(t *testing.T) {
+		cm := NewCompareMatch(fileMatch("a", "x1\ny"), nil)
+		autogold.Expect(`a.go a...
+removed 0 -1 x1
+`).Equal(t, format(cm))
+	})
+}
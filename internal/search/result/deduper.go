@@ -23,6 +23,8 @@ func (d *Deduper) Add(m Match) {
 			prevMatch.AppendMatches(m.(*FileMatch))
 		case *CommitMatch:
 			prevMatch.AppendMatches(m.(*CommitMatch))
+		case *DirectoryMatch:
+			prevMatch.AppendMatches(m.(*DirectoryMatch))
 		}
 		return
 	}
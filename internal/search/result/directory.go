@@ -0,0 +1,137 @@
+package result
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// DirectoryMatch rolls up the file matches in a directory, see
+// select:file.rollup.
+//
+// A search may return several DirectoryMatches for the same directory, each
+// counting only the files and matches found since the last one. Consumers sum
+// them up with AppendMatches, just like they merge the line matches of a file
+// returned more than once.
+type DirectoryMatch struct {
+	// The following contain information about what search the directory was
+	// matched from.
+	InputRev *string           `json:"-"`
+	Repo     types.MinimalRepo `json:"-"`
+	CommitID api.CommitID      `json:"-"`
+
+	// Path is the path of the directory with a trailing slash.
+	Path string
+
+	// FileCount is the number of files with matches in the directory or its
+	// subdirectories.
+	FileCount int
+
+	// MatchCount is the number of matches in those files.
+	MatchCount int
+
+	LimitHit bool
+}
+
+// newDirectoryMatch returns the DirectoryMatch for the directory of fm. If
+// depth is positive, the directory is cut off after depth levels.
+func newDirectoryMatch(fm *FileMatch, depth int) *DirectoryMatch {
+	return &DirectoryMatch{
+		InputRev:   fm.InputRev,
+		Repo:       fm.Repo,
+		CommitID:   fm.CommitID,
+		Path:       directoryAtDepth(fm.Path, depth),
+		FileCount:  1,
+		MatchCount: fm.ResultCount(),
+		LimitHit:   fm.LimitHit,
+	}
+}
+
+func directoryAtDepth(filePath string, depth int) string {
+	dir := path.Clean(path.Dir(filePath))
+	if depth > 0 && dir != "." {
+		if parts := strings.Split(dir, "/"); len(parts) > depth {
+			dir = strings.Join(parts[:depth], "/")
+		}
+	}
+	return dir + "/" // Add trailing slash for clarity.
+}
+
+func (dm *DirectoryMatch) RepoName() types.MinimalRepo {
+	return dm.Repo
+}
+
+func (dm *DirectoryMatch) ResultCount() int {
+	return dm.MatchCount
+}
+
+func (dm *DirectoryMatch) Select(path filter.SelectPath) Match {
+	switch path.Root() {
+	case filter.Repository:
+		return &RepoMatch{
+			Name: dm.Repo.Name,
+			ID:   dm.Repo.ID,
+		}
+	}
+	return nil
+}
+
+// Limit will mutate dm such that it counts at most limit matches.
+func (dm *DirectoryMatch) Limit(limit int) int {
+	if limit >= dm.MatchCount {
+		return limit - dm.MatchCount
+	}
+	dm.MatchCount = limit
+	dm.LimitHit = true
+	return 0
+}
+
+// AppendMatches adds the files and matches counted by src.
+func (dm *DirectoryMatch) AppendMatches(src *DirectoryMatch) {
+	dm.FileCount += src.FileCount
+	dm.MatchCount += src.MatchCount
+	dm.LimitHit = dm.LimitHit || src.LimitHit
+}
+
+func (dm *DirectoryMatch) URL() *url.URL {
+	return dm.url(false)
+}
+
+func (dm *DirectoryMatch) URLAtCommit() *url.URL {
+	return dm.url(true)
+}
+
+func (dm *DirectoryMatch) url(atCommit bool) *url.URL {
+	var urlPath strings.Builder
+	urlPath.WriteRune('/')
+	urlPath.WriteString(string(dm.Repo.Name))
+	if atCommit {
+		urlPath.WriteRune('@')
+		urlPath.WriteString(string(dm.CommitID))
+	} else if dm.InputRev != nil && len(*dm.InputRev) > 0 {
+		urlPath.WriteRune('@')
+		urlPath.WriteString(*dm.InputRev)
+	}
+	urlPath.WriteString("/-/tree/")
+	urlPath.WriteString(strings.TrimSuffix(strings.TrimPrefix(dm.Path, "./"), "/"))
+	return &url.URL{Path: urlPath.String()}
+}
+
+func (dm *DirectoryMatch) Key() Key {
+	k := Key{
+		TypeRank: rankDirectoryMatch,
+		Repo:     dm.Repo.Name,
+		Commit:   dm.CommitID,
+		Path:     dm.Path,
+	}
+	if dm.InputRev != nil {
+		k.Rev = *dm.InputRev
+	}
+	return k
+}
+
+func (dm *DirectoryMatch) searchResultMarker() {}
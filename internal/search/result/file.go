@@ -96,6 +96,9 @@ func (fm *FileMatch) Select(selectPath filter.SelectPath) Match {
 			ID:   fm.Repo.ID,
 		}
 	case filter.File:
+		if len(selectPath) > 1 && selectPath[1] == "rollup" {
+			return newDirectoryMatch(fm, selectPath.DirectoryDepth())
+		}
 		fm.ChunkMatches = nil
 		fm.Symbols = nil
 		if len(selectPath) > 1 && selectPath[1] == "directory" {
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 func TestConvertMatches(t *testing.T) {
@@ -377,3 +380,42 @@ func TestFileMatch_Limit(t *testing.T) {
 		})
 	}
 }
+
+func TestFileMatch_SelectRollup(t *testing.T) {
+	fm := &FileMatch{
+		File: File{
+			Repo:     types.MinimalRepo{Name: "repo"},
+			CommitID: "abc",
+			Path:     "a/b/c/d.go",
+		},
+		ChunkMatches: ChunkMatches{{Ranges: Ranges{{}, {}}}},
+	}
+
+	for depth, want := range map[string]string{
+		"file.rollup":   "a/b/c/",
+		"file.rollup.1": "a/",
+		"file.rollup.2": "a/b/",
+		"file.rollup.9": "a/b/c/",
+	} {
+		sp, err := filter.SelectPathFromString(depth)
+		require.NoError(t, err)
+		dm, ok := fm.Select(sp).(*DirectoryMatch)
+		require.True(t, ok)
+		require.Equal(t, want, dm.Path)
+		require.Equal(t, 1, dm.FileCount)
+		require.Equal(t, 2, dm.MatchCount)
+	}
+
+	sp, err := filter.SelectPathFromString("file.rollup.1")
+	require.NoError(t, err)
+	dm := fm.Select(sp).(*DirectoryMatch)
+	require.Equal(t, "/repo@abc/-/tree/a", dm.URLAtCommit().String())
+
+	// select:file.directory returns the directory as a file match.
+	sp, err = filter.SelectPathFromString("file.directory")
+	require.NoError(t, err)
+	dir, ok := fm.Select(sp).(*FileMatch)
+	require.True(t, ok)
+	require.Equal(t, "a/b/c/", dir.Path)
+	require.Nil(t, dir.ChunkMatches)
+}
@@ -35,17 +35,21 @@ var (
 	_ Match = (*CommitMatch)(nil)
 	_ Match = (*CommitDiffMatch)(nil)
 	_ Match = (*OwnerMatch)(nil)
+	_ Match = (*DirectoryMatch)(nil)
+	_ Match = (*CompareMatch)(nil)
 )
 
 // Match ranks are used for sorting the different match types.
 // Match types with lower ranks will be sorted before match types
 // with higher ranks.
 const (
-	rankFileMatch   = 0
-	rankCommitMatch = 1
-	rankDiffMatch   = 2
-	rankRepoMatch   = 3
-	rankOwnerMatch  = 4
+	rankFileMatch      = 0
+	rankCommitMatch    = 1
+	rankDiffMatch      = 2
+	rankRepoMatch      = 3
+	rankOwnerMatch     = 4
+	rankDirectoryMatch = 5
+	rankCompareMatch   = 6
 )
 
 // Key is a sorting or deduplicating key for a Match. It contains all the
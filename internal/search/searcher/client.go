@@ -56,6 +56,8 @@ func Search(
 		Branch: branch,
 		PatternInfo: protocol.PatternInfo{
 			Pattern:                      p.Pattern,
+			NearPattern:                  p.NearPattern,
+			NearDistance:                 p.NearDistance,
 			ExcludePattern:               p.ExcludePattern,
 			IncludePatterns:              p.IncludePatterns,
 			Languages:                    p.Languages,
@@ -65,6 +67,7 @@ func Search(
 			IsRegExp:                     p.IsRegExp,
 			IsStructuralPat:              p.IsStructuralPat,
 			IsCaseSensitive:              p.IsCaseSensitive,
+			IsMultiline:                  p.IsMultiline,
 			PathPatternsAreCaseSensitive: p.PathPatternsAreCaseSensitive,
 			IsNegated:                    p.IsNegated,
 			PatternMatchesContent:        p.PatternMatchesContent,
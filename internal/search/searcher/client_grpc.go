@@ -44,6 +44,8 @@ func SearchGRPC(
 		Branch: branch,
 		PatternInfo: protocol.PatternInfo{
 			Pattern:                      p.Pattern,
+			NearPattern:                  p.NearPattern,
+			NearDistance:                 p.NearDistance,
 			ExcludePattern:               p.ExcludePattern,
 			IncludePatterns:              p.IncludePatterns,
 			Languages:                    p.Languages,
@@ -53,6 +55,7 @@ func SearchGRPC(
 			IsRegExp:                     p.IsRegExp,
 			IsStructuralPat:              p.IsStructuralPat,
 			IsCaseSensitive:              p.IsCaseSensitive,
+			IsMultiline:                  p.IsMultiline,
 			PathPatternsAreCaseSensitive: p.PathPatternsAreCaseSensitive,
 			IsNegated:                    p.IsNegated,
 			PatternMatchesContent:        p.PatternMatchesContent,
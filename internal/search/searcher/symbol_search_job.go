@@ -86,6 +86,8 @@ func (s *SymbolSearchJob) Attributes(v job.Verbosity) (res []attribute.KeyValue)
 func (s *SymbolSearchJob) Children() []job.Describer       { return nil }
 func (s *SymbolSearchJob) MapChildren(job.MapFunc) job.Job { return s }
 
+var MockSearchSymbols func(ctx context.Context, args search.SymbolsParameters) (result.Symbols, error)
+
 func searchInRepo(ctx context.Context, gitserverClient gitserver.Client, repoRevs *search.RepositoryRevisions, patternInfo *search.TextPatternInfo, limit int) (res []result.Match, err error) {
 	inputRev := repoRevs.Revs[0]
 	tr, ctx := trace.New(ctx, "symbols.searchInRepo",
@@ -103,7 +105,11 @@ func searchInRepo(ctx context.Context, gitserverClient gitserver.Client, repoRev
 	}
 	tr.SetAttributes(commitID.Attr())
 
-	symbols, err := symbols.DefaultClient.Search(ctx, search.SymbolsParameters{
+	searchSymbols := symbols.DefaultClient.Search
+	if MockSearchSymbols != nil {
+		searchSymbols = MockSearchSymbols
+	}
+	symbols, err := searchSymbols(ctx, search.SymbolsParameters{
 		Repo:            repoRevs.Repo.Name,
 		CommitID:        commitID,
 		Query:           patternInfo.Pattern,
@@ -0,0 +1,130 @@
+package smartsearch
+
+import (
+	"fmt"
+
+	"github.com/grafana/regexp"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func init() {
+	conf.ContributeValidator(func(c conftypes.SiteConfigQuerier) (problems conf.Problems) {
+		for _, r := range c.SiteConfig().SearchSmartSearchRules {
+			if _, err := compileRulePattern(r.Pattern); err != nil {
+				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("search.smartSearch.rules: %q is not a valid regexp: %s", r.Pattern, err)))
+			}
+		}
+		return problems
+	})
+}
+
+// siteRules are the narrowing and widening rules defined in the
+// search.smartSearch.rules site configuration.
+var siteRules = conf.Cached(func() customRuleSet {
+	return customRules(conf.Get().SearchSmartSearchRules)
+})
+
+type customRuleSet struct {
+	narrow, widen []rule
+}
+
+// customRules converts rules from site configuration. Rules with an invalid
+// pattern are skipped, the site configuration validator reports them.
+func customRules(configured []*schema.SmartSearchRule) customRuleSet {
+	var set customRuleSet
+	for _, c := range configured {
+		re, err := compileRulePattern(c.Pattern)
+		if err != nil {
+			log.Scoped("smartsearch").Error("site config: unable to compile smart search rule pattern", log.String("pattern", c.Pattern), log.Error(err))
+			continue
+		}
+		r := rule{
+			description: c.Description,
+			transform:   []transform{rewritePatterns(re, c.Rewrite)},
+		}
+		if c.Kind == "widen" {
+			set.widen = append(set.widen, r)
+		} else {
+			set.narrow = append(set.narrow, r)
+		}
+	}
+	return set
+}
+
+// compileRulePattern compiles the pattern of a rule so that it only matches
+// entire search patterns.
+func compileRulePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
+
+// rewritePatterns returns a transform which replaces the first pattern
+// matching re with the query fragment rewrite, in which $0 and capture group
+// references like $1 are expanded. Filters in the fragment are added to the
+// query and patterns in the fragment take the place of the matched pattern.
+func rewritePatterns(re *regexp.Regexp, rewrite string) transform {
+	return func(b query.Basic) *query.Basic {
+		if b.Pattern == nil {
+			return nil
+		}
+		rawPatternTree, err := query.Parse(query.StringHuman([]query.Node{b.Pattern}), query.SearchTypeStandard)
+		if err != nil {
+			return nil
+		}
+
+		changed := false
+		var filterParams []query.Parameter
+		newPattern := query.MapPattern(rawPatternTree, func(value string, negated bool, annotation query.Annotation) query.Node {
+			unchanged := query.Pattern{
+				Value:      value,
+				Negated:    negated,
+				Annotation: annotation,
+			}
+			if changed || negated {
+				return unchanged
+			}
+			submatches := re.FindStringSubmatchIndex(value)
+			if submatches == nil {
+				return unchanged
+			}
+			fragment := string(re.ExpandString(nil, rewrite, value, submatches))
+			fragmentNodes, err := query.Parse(fragment, query.SearchTypeStandard)
+			if err != nil {
+				return unchanged
+			}
+			params, pattern, err := query.PartitionSearchPattern(fragmentNodes)
+			if err != nil {
+				return unchanged
+			}
+			changed = true
+			filterParams = params
+			// A nil pattern removes this node.
+			return pattern
+		})
+
+		if !changed {
+			return nil
+		}
+
+		var pattern query.Node
+		if len(newPattern) > 0 {
+			// Process concat nodes
+			nodes, err := query.Sequence(query.For(query.SearchTypeStandard))(newPattern)
+			if err != nil {
+				return nil
+			}
+			pattern = nodes[0] // guaranteed root at first node
+		}
+
+		params := make([]query.Parameter, 0, len(b.Parameters)+len(filterParams))
+		params = append(params, b.Parameters...)
+		return &query.Basic{
+			Parameters: append(params, filterParams...),
+			Pattern:    pattern,
+		}
+	}
+}
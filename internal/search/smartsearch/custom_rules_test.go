@@ -0,0 +1,59 @@
+package smartsearch
+
+import (
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func Test_customRules(t *testing.T) {
+	rules := customRules([]*schema.SmartSearchRule{
+		{
+			Description: "search commit messages for Jira keys",
+			Pattern:     "[A-Z][A-Z0-9]+-[0-9]+",
+			Rewrite:     "type:commit $0",
+			Kind:        "widen",
+		},
+		{
+			Description: "scope service names to their repository",
+			Pattern:     "(billing|payments)-service",
+			Rewrite:     `repo:^github\.com/acme/$1$`,
+		},
+		{
+			Description: "invalid rules are skipped",
+			Pattern:     "(",
+			Rewrite:     "x",
+		},
+	})
+	autogold.Expect([]string{"scope service names to their repository"}).Equal(t, descriptions(rules.narrow))
+	autogold.Expect([]string{"search commit messages for Jira keys"}).Equal(t, descriptions(rules.widen))
+
+	test := func(input string, r rule) string {
+		q, _ := query.ParseStandard(input)
+		b, _ := query.ToBasicQuery(q)
+		out := applyTransformation(b, r.transform)
+		if out == nil {
+			return "DOES NOT APPLY"
+		}
+		return query.StringHuman(out.ToParseTree())
+	}
+
+	jira, service := rules.widen[0], rules.narrow[0]
+	autogold.Expect("type:commit SRC-123").Equal(t, test("SRC-123", jira))
+	autogold.Expect("repo:foo type:commit timeout SRC-123").Equal(t, test("repo:foo timeout SRC-123", jira))
+	autogold.Expect(`repo:^github\.com/acme/billing$ retry`).Equal(t, test("billing-service retry", service))
+	// The pattern must match an entire search pattern.
+	autogold.Expect("DOES NOT APPLY").Equal(t, test("old-billing-service retry", service))
+	autogold.Expect("DOES NOT APPLY").Equal(t, test("-billing-service retry", service))
+}
+
+func descriptions(rules []rule) []string {
+	var ds []string
+	for _, r := range rules {
+		ds = append(ds, r.description)
+	}
+	return ds
+}
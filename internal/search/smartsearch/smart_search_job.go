@@ -34,9 +34,13 @@ type newJob func(query.Basic) (job.Job, error)
 // not, attempt to search the pattern as a regexp, and so on). There is no
 // random choice when applying rules.
 func NewSmartSearchJob(initialJob job.Job, newJob newJob, plan query.Plan) *FeelingLuckySearchJob {
+	custom := siteRules()
+	narrow := append(append([]rule{}, rulesNarrow...), custom.narrow...)
+	widen := append(append([]rule{}, rulesWiden...), custom.widen...)
+
 	generators := make([]next, 0, len(plan))
 	for _, b := range plan {
-		generators = append(generators, NewGenerator(b, rulesNarrow, rulesWiden))
+		generators = append(generators, NewGenerator(b, narrow, widen))
 	}
 
 	newGeneratedJob := func(autoQ *autoQuery) job.Job {
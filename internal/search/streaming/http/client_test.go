@@ -48,6 +48,14 @@ func TestFrontendClient(t *testing.T) {
 				Type:   CommitMatchType,
 				Detail: "test",
 			},
+			&EventCompareMatch{
+				Type: CompareMatchType,
+				Path: "test",
+				Lines: []EventCompareLine{{
+					Status:         "added",
+					BaseLineNumber: -1,
+				}},
+			},
 		},
 	}, {
 		Name: "filters",
@@ -39,6 +39,12 @@ func (e *EventContentMatch) eventMatch() {}
 // It is used for result.FileMatch results with no line matches and
 // no symbol matches, indicating it represents a match of the file itself
 // and not its content.
+//
+// It is also used for result.DirectoryMatch, in which case Path ends with a
+// slash and FileCount and MatchCount are set. Those counts only cover the
+// matches found since the directory was last sent, so clients should sum them
+// up for events with the same repository, commit and path. FileCount is only
+// set for directories, even if it is zero, so clients can tell them apart.
 type EventPathMatch struct {
 	// Type is always PathMatchType. Included here for marshalling.
 	Type MatchType `json:"type"`
@@ -51,6 +57,8 @@ type EventPathMatch struct {
 	RepoLastFetched *time.Time `json:"repoLastFetched,omitempty"`
 	Branches        []string   `json:"branches,omitempty"`
 	Commit          string     `json:"commit,omitempty"`
+	FileCount       *int       `json:"fileCount,omitempty"`
+	MatchCount      int        `json:"matchCount,omitempty"`
 	Debug           string     `json:"debug,omitempty"`
 }
 
@@ -168,6 +176,38 @@ type EventCommitMatch struct {
 
 func (e *EventCommitMatch) eventMatch() {}
 
+// EventCompareMatch is a file whose matched lines differ between the base and
+// the head revision of a search with rev:base...head.
+type EventCompareMatch struct {
+	// Type is always CompareMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Path            string     `json:"path"`
+	RepositoryID    int32      `json:"repositoryID"`
+	Repository      string     `json:"repository"`
+	RepoStars       int        `json:"repoStars,omitempty"`
+	RepoLastFetched *time.Time `json:"repoLastFetched,omitempty"`
+	BaseRevision    string     `json:"baseRevision"`
+	HeadRevision    string     `json:"headRevision"`
+	BaseCommit      string     `json:"baseCommit,omitempty"`
+	HeadCommit      string     `json:"headCommit,omitempty"`
+
+	Lines []EventCompareLine `json:"lines"`
+}
+
+func (e *EventCompareMatch) eventMatch() {}
+
+// EventCompareLine is a matched line tagged with how it changed between the
+// revisions. Status is one of "added", "removed" or "moved". The line numbers
+// are 0-based and -1 for the revision the line does not match in.
+type EventCompareLine struct {
+	Status           string     `json:"status"`
+	Line             string     `json:"line"`
+	BaseLineNumber   int32      `json:"baseLineNumber"`
+	HeadLineNumber   int32      `json:"headLineNumber"`
+	OffsetAndLengths [][2]int32 `json:"offsetAndLengths"`
+}
+
 type EventPersonMatch struct {
 	// Type is always PersonMatchType. Included here for marshalling.
 	Type MatchType `json:"type"`
@@ -248,6 +288,7 @@ const (
 	PathMatchType
 	PersonMatchType
 	TeamMatchType
+	CompareMatchType
 )
 
 func (t MatchType) MarshalJSON() ([]byte, error) {
@@ -266,6 +307,8 @@ func (t MatchType) MarshalJSON() ([]byte, error) {
 		return []byte(`"person"`), nil
 	case TeamMatchType:
 		return []byte(`"team"`), nil
+	case CompareMatchType:
+		return []byte(`"compare"`), nil
 	default:
 		return nil, errors.Errorf("unknown MatchType: %d", t)
 	}
@@ -286,6 +329,8 @@ func (t *MatchType) UnmarshalJSON(b []byte) error {
 		*t = PersonMatchType
 	} else if bytes.Equal(b, []byte(`"team"`)) {
 		*t = TeamMatchType
+	} else if bytes.Equal(b, []byte(`"compare"`)) {
+		*t = CompareMatchType
 	} else {
 		return errors.Errorf("unknown MatchType: %s", b)
 	}
@@ -209,6 +209,19 @@ func (s *SearchFilters) Update(event SearchEvent) {
 			addFileFilter(v.Path, lines)
 			addSymbolFilter(v.Symbols)
 			s.Dirty = true
+		case *result.DirectoryMatch:
+			rev := ""
+			if v.InputRev != nil {
+				rev = *v.InputRev
+			}
+			addRepoFilter(v.Repo.Name, rev, int32(v.ResultCount()))
+			s.Dirty = true
+		case *result.CompareMatch:
+			lines := int32(v.ResultCount())
+			addRepoFilter(v.Repo.Name, "", lines)
+			addLangFilter(v.Path, lines)
+			addFileFilter(v.Path, lines)
+			s.Dirty = true
 		case *result.RepoMatch:
 			// It should be fine to leave this blank since revision specifiers
 			// can only be used with the 'repo:' scope. In that case,
@@ -302,10 +302,17 @@ type TextPatternInfo struct {
 	IsNegated bool
 	IsRegExp  bool
 
+	// NearPattern, if set, is the regular expression pattern of the
+	// right-hand side of a NEAR/n expression. Pattern then only matches
+	// where NearPattern matches at most NearDistance lines away.
+	NearPattern  string
+	NearDistance int
+
 	// Values dependent on parameters.
 	IsStructuralPat bool
 	CombyRule       string
 	IsCaseSensitive bool
+	IsMultiline     bool
 	FileMatchLimit  int32
 	Index           query.YesNoOnly
 	Select          filter.SelectPath
@@ -335,6 +342,10 @@ func (p *TextPatternInfo) Fields() []attribute.KeyValue {
 	if p.IsRegExp {
 		add(attribute.Bool("isRegexp", p.IsRegExp))
 	}
+	if p.NearPattern != "" {
+		add(attribute.String("nearPattern", p.NearPattern))
+		add(attribute.Int("nearDistance", p.NearDistance))
+	}
 	if p.IsStructuralPat {
 		add(attribute.Bool("isStructural", p.IsStructuralPat))
 	}
@@ -344,6 +355,9 @@ func (p *TextPatternInfo) Fields() []attribute.KeyValue {
 	if p.IsCaseSensitive {
 		add(attribute.Bool("isCaseSensitive", p.IsCaseSensitive))
 	}
+	if p.IsMultiline {
+		add(attribute.Bool("isMultiline", p.IsMultiline))
+	}
 	add(attribute.Int("fileMatchLimit", int(p.FileMatchLimit)))
 
 	if p.Index != query.Yes {
@@ -375,6 +389,9 @@ func (p *TextPatternInfo) String() string {
 	if p.IsRegExp {
 		args = append(args, "re")
 	}
+	if p.NearPattern != "" {
+		args = append(args, fmt.Sprintf("near/%d:%q", p.NearDistance, p.NearPattern))
+	}
 	if p.IsStructuralPat {
 		if p.CombyRule != "" {
 			args = append(args, fmt.Sprintf("comby:%s", p.CombyRule))
@@ -385,6 +402,9 @@ func (p *TextPatternInfo) String() string {
 	if p.IsCaseSensitive {
 		args = append(args, "case")
 	}
+	if p.IsMultiline {
+		args = append(args, "multiline")
+	}
 	if !p.PatternMatchesContent {
 		args = append(args, "nocontent")
 	}
@@ -464,8 +484,13 @@ type RepoOptions struct {
 
 	CommitAfter *query.RepoHasCommitAfterArgs
 	Visibility  query.RepoVisibility
-	Limit       int
-	Cursors     []*types.Cursor
+
+	// At, if set, resolves each revision to the last commit on it before
+	// this point in time.
+	At *time.Time
+
+	Limit   int
+	Cursors []*types.Cursor
 
 	// Whether we should depend on Zoekt for resolving repositories
 	UseIndex       query.YesNoOnly
@@ -513,6 +538,9 @@ func (op *RepoOptions) Attributes() []attribute.KeyValue {
 		add(attribute.String("commitAfter.time", op.CommitAfter.TimeRef))
 		add(attribute.Bool("commitAfter.negated", op.CommitAfter.Negated))
 	}
+	if op.At != nil {
+		add(attribute.String("at", op.At.Format(time.RFC3339)))
+	}
 	if op.Visibility != query.Any {
 		add(attribute.String("visibility", string(op.Visibility)))
 	}
@@ -612,6 +640,9 @@ func (op *RepoOptions) String() string {
 	if op.CommitAfter != nil {
 		fmt.Fprintf(&b, "CommitAfter: %s\n", op.CommitAfter.TimeRef)
 	}
+	if op.At != nil {
+		fmt.Fprintf(&b, "At: %s\n", op.At.Format(time.RFC3339))
+	}
 	fmt.Fprintf(&b, "Visibility: %s\n", string(op.Visibility))
 
 	if op.UseIndex != query.Yes {
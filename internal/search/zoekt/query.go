@@ -21,6 +21,7 @@ func QueryToZoektQuery(b query.Basic, resultTypes result.Types, feat *search.Fea
 		q, err = toZoektPattern(
 			b.Pattern,
 			isCaseSensitive,
+			b.IsMultiline(),
 			resultTypes.Has(result.TypeFile),
 			resultTypes.Has(result.TypePath),
 			typ,
@@ -113,7 +114,7 @@ func QueryForFileContentArgs(opt query.RepoHasFileContentArgs, caseSensitive boo
 	return q
 }
 
-func toZoektPatternNew(expression query.Node, isCaseSensitive, patternMatchesContent, patternMatchesPath bool, typ search.IndexedRequestType) (zoekt.Q, error) {
+func toZoektPatternNew(expression query.Node, isCaseSensitive, isMultiline, patternMatchesContent, patternMatchesPath bool, typ search.IndexedRequestType) (zoekt.Q, error) {
 	q, err := zoekt.Parse(query.StringHuman([]query.Node{expression}))
 	if err != nil {
 		return nil, err
@@ -127,6 +128,9 @@ func toZoektPatternNew(expression query.Node, isCaseSensitive, patternMatchesCon
 			s.CaseSensitive = isCaseSensitive
 			s.Content = contentOnly
 			s.FileName = fileNameOnly
+			if isMultiline {
+				dotMatchNL(s.Regexp)
+			}
 		}
 		if s, ok := r.(*zoekt.Substring); ok {
 			s.CaseSensitive = isCaseSensitive
@@ -161,7 +165,7 @@ func toZoektPatternNew(expression query.Node, isCaseSensitive, patternMatchesCon
 }
 
 func toZoektPattern(
-	expression query.Node, isCaseSensitive, patternMatchesContent, patternMatchesPath bool, typ search.IndexedRequestType) (zoekt.Q, error) {
+	expression query.Node, isCaseSensitive, isMultiline, patternMatchesContent, patternMatchesPath bool, typ search.IndexedRequestType) (zoekt.Q, error) {
 	var fold func(node query.Node) (zoekt.Q, error)
 	fold = func(node query.Node) (zoekt.Q, error) {
 		switch n := node.(type) {
@@ -190,9 +194,11 @@ func toZoektPattern(
 			fileNameOnly := patternMatchesPath && !patternMatchesContent
 			contentOnly := !patternMatchesPath && patternMatchesContent
 
-			pattern := n.Value
-			if n.Annotation.Labels.IsSet(query.Literal) {
-				pattern = regexp.QuoteMeta(pattern)
+			pattern := toZoektRegexp(n, isMultiline)
+			if near := n.Annotation.Near; near != nil {
+				// Zoekt can't evaluate NEAR/n natively, so we search for a
+				// regular expression that matches the same spans.
+				pattern = query.NearRegexp(pattern, toZoektRegexp(near.Pattern, isMultiline), near.Distance)
 			}
 
 			q, err = parseRe(pattern, fileNameOnly, contentOnly, isCaseSensitive)
@@ -224,6 +230,30 @@ func toZoektPattern(
 	return q, nil
 }
 
+// toZoektRegexp returns the regular expression pattern p stands for.
+func toZoektRegexp(p query.Pattern, isMultiline bool) string {
+	if p.Annotation.Labels.IsSet(query.Literal) {
+		return regexp.QuoteMeta(p.Value)
+	}
+	if isMultiline {
+		// Let "." match newlines, like searcher does for multiline:yes.
+		return "(?s:" + p.Value + ")"
+	}
+	return p.Value
+}
+
+// dotMatchNL lets every "." in re match newlines, like (?s) does when
+// parsing.
+func dotMatchNL(re *syntax.Regexp) {
+	if re.Op == syntax.OpAnyCharNotNL {
+		re.Op = syntax.OpAnyChar
+	}
+	re.Flags |= syntax.DotNL
+	for _, sub := range re.Sub {
+		dotMatchNL(sub)
+	}
+}
+
 func mapSlice(values []string, f func(string) string) []string {
 	out := make([]string, len(values))
 	for i, v := range values {
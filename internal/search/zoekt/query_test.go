@@ -39,6 +39,12 @@ func TestQueryToZoektQuery(t *testing.T) {
 			Pattern: `(foo).*?(bar) patterntype:regexp`,
 			Query:   "(foo).*?(bar) case:no",
 		},
+		{
+			Name:    "multiline",
+			Type:    search.TextRequest,
+			Pattern: `foo.*bar multiline:yes patterntype:regexp`,
+			Query:   "(?s:foo.*bar) case:no",
+		},
 		{
 			Name:    "path",
 			Type:    search.TextRequest,
@@ -127,7 +133,7 @@ func Test_toZoektPattern(t *testing.T) {
 		if err != nil {
 			return err.Error()
 		}
-		zoektQuery, err := toZoektPattern(p[0].Pattern, false, false, false, typ)
+		zoektQuery, err := toZoektPattern(p[0].Pattern, false, false, false, false, typ)
 		if err != nil {
 			return err.Error()
 		}
@@ -148,6 +154,32 @@ func Test_toZoektPattern(t *testing.T) {
 
 	autogold.Expect(`(and sym:substr:"foo" (not sym:substr:"bar"))`).
 		Equal(t, test(`type:symbol (foo and not bar)`, query.SearchTypeLiteral, search.SymbolRequest))
+
+	autogold.Expect(`regex:"retry(?:[^\\n]*\\n(?:[^\\n]*\\n(?:[^\\n]*\\n)??)??)??[^\\n]*?timeout|timeout(?:[^\\n]*\\n(?:[^\\n]*\\n(?:[^\\n]*\\n)??)??)??[^\\n]*?retry"`).
+		Equal(t, test(`retry NEAR/3 timeout`, query.SearchTypeStandard, search.TextRequest))
+
+	autogold.Expect(`(and regex:"retry(?:[^\\n]*\\n(?:[^\\n]*\\n(?:[^\\n]*\\n)??)??)??[^\\n]*?timeout|timeout(?:[^\\n]*\\n(?:[^\\n]*\\n(?:[^\\n]*\\n)??)??)??[^\\n]*?retry" (not substr:"backoff"))`).
+		Equal(t, test(`retry NEAR/3 timeout and not backoff`, query.SearchTypeStandard, search.TextRequest))
+}
+
+func Test_toZoektPatternNew(t *testing.T) {
+	test := func(input string, isMultiline bool) string {
+		p, err := query.Pipeline(query.Init(input, query.SearchTypeRegex))
+		if err != nil {
+			return err.Error()
+		}
+		zoektQuery, err := toZoektPatternNew(p[0].Pattern, false, isMultiline, false, false, search.TextRequest)
+		if err != nil {
+			return err.Error()
+		}
+		return zoektQuery.String()
+	}
+
+	autogold.Expect(`(or file_regex:"/foo(?-s:.)*bar/" regex:"/foo(?-s:.)*bar/")`).
+		Equal(t, test(`foo.*bar`, false))
+
+	autogold.Expect(`(or file_regex:"/foo(?s:.)*bar/" regex:"/foo(?s:.)*bar/")`).
+		Equal(t, test(`foo.*bar`, true))
 }
 
 func queryEqual(a, b zoekt.Q) bool {
@@ -178,6 +178,7 @@ func mergeSettingsLeft(left, right *schema.Settings) *schema.Settings {
 var settingsFieldMergeDepths = map[string]int{
 	"SearchScopes":         1,
 	"SearchSavedQueries":   1,
+	"SearchMacros":         1,
 	"Motd":                 1,
 	"Notices":              1,
 	"Extensions":           1,
@@ -61,6 +61,8 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		es.redactString(c.Token, "token")
 	case *schema.GerritConnection:
 		es.redactString(c.Password, "password")
+	case *schema.GiteaConnection:
+		es.redactString(c.Token, "token")
 	case *schema.BitbucketServerConnection:
 		es.redactString(c.Password, "password")
 		es.redactString(c.Token, "token")
@@ -94,6 +96,12 @@ func (e *ExternalService) RedactedConfig(ctx context.Context) (string, error) {
 		// Nothing to redact
 	case *schema.RubyPackagesConnection:
 		es.redactString(c.Repository, "repository")
+	case *schema.NuGetPackagesConnection:
+		es.redactString(c.Repository, "repository")
+	case *schema.PHPPackagesConnection:
+		es.redactString(c.Repository, "repository")
+	case *schema.HexPackagesConnection:
+		es.redactString(c.Repository, "repository")
 	case *schema.JVMPackagesConnection:
 		es.redactString(c.Maven.Credentials, "maven", "credentials")
 	case *schema.PagureConnection:
@@ -220,6 +228,12 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 		if c.Url != o.Url {
 			return errCodeHostIdentityChanged{"url", "password"}
 		}
+	case *schema.GiteaConnection:
+		o := oldCfg.(*schema.GiteaConnection)
+		if c.Token == RedactedSecret && c.Url != o.Url {
+			return errCodeHostIdentityChanged{"url", "token"}
+		}
+		es.unredactString(c.Token, o.Token, "token")
 	case *schema.GitoliteConnection:
 		// Nothing to redact
 	case *schema.GoModulesConnection:
@@ -237,6 +251,15 @@ func (e *ExternalService) UnredactConfig(ctx context.Context, old *ExternalServi
 	case *schema.RubyPackagesConnection:
 		o := oldCfg.(*schema.RubyPackagesConnection)
 		es.unredactString(c.Repository, o.Repository, "repository")
+	case *schema.NuGetPackagesConnection:
+		o := oldCfg.(*schema.NuGetPackagesConnection)
+		es.unredactString(c.Repository, o.Repository, "repository")
+	case *schema.PHPPackagesConnection:
+		o := oldCfg.(*schema.PHPPackagesConnection)
+		es.unredactString(c.Repository, o.Repository, "repository")
+	case *schema.HexPackagesConnection:
+		o := oldCfg.(*schema.HexPackagesConnection)
+		es.unredactString(c.Repository, o.Repository, "repository")
 	case *schema.JVMPackagesConnection:
 		o := oldCfg.(*schema.JVMPackagesConnection)
 		// credentials didn't change check if repositories did
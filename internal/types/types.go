@@ -610,6 +610,20 @@ type GitserverRepo struct {
 	CorruptionLogs []RepoCorruptionLog
 }
 
+// GitserverRepoReplica is the state of a secondary copy of a repo that is
+// replicated across several gitservers.
+type GitserverRepoReplica struct {
+	RepoID api.RepoID
+	// The gitserver that holds this copy
+	ShardID     string
+	CloneStatus CloneStatus
+	// The last error that occurred or empty if the last action was successful
+	LastError string
+	// The last time this copy was fetched from the primary.
+	LastFetched time.Time
+	UpdatedAt   time.Time
+}
+
 // RepoCorruptionLog represents a corruption event that has been detected on a repo.
 type RepoCorruptionLog struct {
 	// When the corruption event was detected
@@ -59,6 +59,14 @@ type Handler struct {
 	// internal networks more kindly.
 	CommandHook func(*exec.Cmd)
 
+	// RepoCommandHook if non-nil will run with the git upload command serving
+	// repo after CommandHook, before we start the command.
+	//
+	// Unlike CommandHook it has access to the request context and the name of
+	// the repo. For example, gitserver configures the remote that
+	// partially cloned repos lazily fetch missing objects from.
+	RepoCommandHook func(ctx context.Context, repo string, cmd *exec.Cmd)
+
 	// Trace if non-nil is called at the start of serving a request. It will
 	// call the returned function when done executing. If the executation
 	// failed, it will pass in a non-nil error.
@@ -147,6 +155,9 @@ func (s *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if s.CommandHook != nil {
 		s.CommandHook(cmd)
 	}
+	if s.RepoCommandHook != nil {
+		s.RepoCommandHook(r.Context(), repo, cmd)
+	}
 
 	err = cmd.Run()
 	if err != nil {
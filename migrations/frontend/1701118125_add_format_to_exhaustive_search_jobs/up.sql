@@ -0,0 +1 @@
+ALTER TABLE exhaustive_search_jobs ADD COLUMN IF NOT EXISTS format text DEFAULT 'csv'::text NOT NULL;
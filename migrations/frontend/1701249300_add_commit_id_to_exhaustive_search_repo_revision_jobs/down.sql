@@ -0,0 +1 @@
+ALTER TABLE exhaustive_search_repo_revision_jobs DROP COLUMN IF EXISTS commit_id;
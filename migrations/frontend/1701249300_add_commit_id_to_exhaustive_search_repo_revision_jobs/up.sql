@@ -0,0 +1 @@
+ALTER TABLE exhaustive_search_repo_revision_jobs ADD COLUMN IF NOT EXISTS commit_id text;
@@ -0,0 +1,7 @@
+ALTER TABLE exhaustive_search_jobs
+    DROP COLUMN IF EXISTS schedule,
+    DROP COLUMN IF EXISTS next_run_at,
+    DROP COLUMN IF EXISTS diff_pending,
+    DROP COLUMN IF EXISTS diff_added,
+    DROP COLUMN IF EXISTS diff_removed,
+    DROP COLUMN IF EXISTS diff_computed_at;
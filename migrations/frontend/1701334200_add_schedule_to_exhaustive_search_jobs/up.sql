@@ -0,0 +1,7 @@
+ALTER TABLE exhaustive_search_jobs
+    ADD COLUMN IF NOT EXISTS schedule text,
+    ADD COLUMN IF NOT EXISTS next_run_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS diff_pending boolean DEFAULT false NOT NULL,
+    ADD COLUMN IF NOT EXISTS diff_added integer,
+    ADD COLUMN IF NOT EXISTS diff_removed integer,
+    ADD COLUMN IF NOT EXISTS diff_computed_at timestamp with time zone;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS gitserver_repo_replicas (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    shard_id text NOT NULL,
+    clone_status text DEFAULT 'not_cloned'::text NOT NULL,
+    last_error text,
+    last_fetched timestamp with time zone,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (repo_id, shard_id)
+);
+
+COMMENT ON TABLE gitserver_repo_replicas IS 'Clone state of the secondary copies of repositories that are replicated across several gitservers. The state of the primary copy is stored in gitserver_repos.';
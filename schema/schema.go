@@ -860,6 +860,14 @@ type ExcludedGitLabProject struct {
 	// Pattern description: Regular expression which matches against the name of a GitLab project ("group/name").
 	Pattern string `json:"pattern,omitempty"`
 }
+type ExcludedGiteaRepo struct {
+	// Id description: The ID of a repository (as returned by the Gitea API) to exclude from mirroring. Use this to exclude the repository, even if renamed.
+	Id int `json:"id,omitempty"`
+	// Name description: The name of a repository ("owner/name") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a repository ("owner/name").
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitoliteRepo struct {
 	// Name description: The name of a Gitolite repo ("my-repo") to exclude from mirroring.
 	Name string `json:"name,omitempty"`
@@ -913,10 +921,16 @@ type ExperimentalFeatures struct {
 	EnableStorm bool `json:"enableStorm,omitempty"`
 	// EventLogging description: Enables user event logging inside of the Sourcegraph instance. This will allow admins to have greater visibility of user activity, such as frequently viewed pages, frequent searches, and more. These event logs (and any specific user actions) are only stored locally, and never leave this Sourcegraph instance.
 	EventLogging string `json:"eventLogging,omitempty"`
+	// GitLFS description: JSON array of configuration that maps from Git clone URL domain/path to Git LFS settings. The Git LFS objects referenced by the default branch of matching repositories are fetched from the code host over HTTP(S) after every clone and fetch, and file contents, archives and blobs read with GetBlobs return the contents of fetched objects instead of their pointer files. Files at other revisions that aren't referenced by the default branch are returned as pointer files.
+	GitLFS []*GitLFSMapping `json:"gitLFS,omitempty"`
 	// GitServerPinnedRepos description: List of repositories pinned to specific gitserver instances. The specified repositories will remain at their pinned servers on scaling the cluster. If the specified pinned server differs from the current server that stores the repository, then it must be re-cloned to the specified server.
 	GitServerPinnedRepos map[string]string `json:"gitServerPinnedRepos,omitempty"`
+	// GitServerReplicationFactor description: The number of gitserver instances that hold a copy of each repository. The primary instance clones and fetches the repository from the code host, and the following instances in the list of gitserver addresses keep copies that are fetched from the primary. Reads of files, archives and commit searches are spread across all copies and fail over to another copy if an instance is unavailable.
+	GitServerReplicationFactor int `json:"gitServerReplicationFactor,omitempty"`
 	// GoPackages description: Allow adding Go package host connections
 	GoPackages string `json:"goPackages,omitempty"`
+	// HexPackages description: Allow adding Hex package host connections
+	HexPackages string `json:"hexPackages,omitempty"`
 	// InsightsAlternateLoadingStrategy description: Use an in-memory strategy of loading Code Insights. Should only be used for benchmarking on large instances, not for customer use currently.
 	InsightsAlternateLoadingStrategy bool `json:"insightsAlternateLoadingStrategy,omitempty"`
 	// InsightsBackfillerV2 description: DEPRECATED: Setting any value to this flag has no effect.
@@ -929,8 +943,14 @@ type ExperimentalFeatures struct {
 	LanguageDetection *LanguageDetection `json:"languageDetection,omitempty"`
 	// NpmPackages description: Allow adding npm package code host connections
 	NpmPackages string `json:"npmPackages,omitempty"`
+	// NuGetPackages description: Allow adding NuGet package host connections
+	NuGetPackages string `json:"nugetPackages,omitempty"`
+	// PHPPackages description: Allow adding PHP package host connections
+	PHPPackages string `json:"phpPackages,omitempty"`
 	// Pagure description: Allow adding Pagure code host connections
 	Pagure string `json:"pagure,omitempty"`
+	// PartialClones description: JSON array of configuration that maps from Git clone URL domain/path to a partial clone mode. Matching repositories are cloned without file contents (blobs), which are fetched lazily from the code host when they are read. This is intended for very large repositories. Only new clones are affected; existing repositories must be recloned to switch modes.
+	PartialClones []*PartialCloneMapping `json:"partialClones,omitempty"`
 	// PasswordPolicy description: DEPRECATED: this is now a standard feature see: auth.passwordPolicy
 	PasswordPolicy *PasswordPolicy `json:"passwordPolicy,omitempty"`
 	// Perforce description: Allow adding Perforce code host connections
@@ -1003,18 +1023,24 @@ func (v *ExperimentalFeatures) UnmarshalJSON(data []byte) error {
 	delete(m, "enablePermissionsWebhooks")
 	delete(m, "enableStorm")
 	delete(m, "eventLogging")
+	delete(m, "gitLFS")
 	delete(m, "gitServerPinnedRepos")
+	delete(m, "gitServerReplicationFactor")
 	delete(m, "goPackages")
+	delete(m, "hexPackages")
 	delete(m, "insightsAlternateLoadingStrategy")
 	delete(m, "insightsBackfillerV2")
 	delete(m, "insightsDataRetention")
 	delete(m, "jvmPackages")
 	delete(m, "languageDetection")
 	delete(m, "npmPackages")
+	delete(m, "nugetPackages")
 	delete(m, "pagure")
+	delete(m, "partialClones")
 	delete(m, "passwordPolicy")
 	delete(m, "perforce")
 	delete(m, "perforceChangelistMapping")
+	delete(m, "phpPackages")
 	delete(m, "pythonPackages")
 	delete(m, "ranking")
 	delete(m, "rateLimitAnonymous")
@@ -1284,6 +1310,14 @@ type GitHubWebhook struct {
 	Secret string `json:"secret"`
 }
 
+// GitLFSMapping description: Mapping from Git clone URL domain/path to Git LFS settings. The `domainPath` field matches the Git clone URL domain/path exactly, or any repository below it (e.g. `github.com/myorg` matches all repositories of that organization).
+type GitLFSMapping struct {
+	// DomainPath description: Git clone URL domain/path, or a prefix of it ending at a path segment.
+	DomainPath string `json:"domainPath"`
+	// MaxObjectSize description: The maximum size in bytes of the Git LFS objects that are fetched. Files stored in larger objects are returned as pointer files.
+	MaxObjectSize int `json:"maxObjectSize,omitempty"`
+}
+
 // GitLabAuthProvider description: Configures the GitLab OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitLab instance: https://docs.gitlab.com/ee/integration/oauth_provider.html. The application should have `api` and `read_user` scopes and the callback URL set to the concatenation of your Sourcegraph instance URL and "/.auth/gitlab/callback".
 type GitLabAuthProvider struct {
 	// AllowGroups description: Restricts new logins and signups (if allowSignup is true) to members of these GitLab groups. Existing sessions won't be invalidated. Make sure to inform the full path for groups or subgroups instead of their names. Leave empty or unset for no group restrictions.
@@ -1403,6 +1437,44 @@ type GitRecorder struct {
 	Size int `json:"size,omitempty"`
 }
 
+// GiteaAuthorization description: If non-null, enforces Gitea repository permissions. Sourcegraph users are matched to Gitea users with the same username, so `auth.enableUsernameChanges` must be set to false for security reasons.
+type GiteaAuthorization struct {
+	// IdentityProvider description: The source of identity to use when computing permissions. Only "username" is supported, which assumes usernames are identical in Sourcegraph and Gitea.
+	IdentityProvider string `json:"identityProvider,omitempty"`
+}
+
+// GiteaConnection description: Configuration for a connection to Gitea or Forgejo.
+type GiteaConnection struct {
+	// Authorization description: If non-null, enforces Gitea repository permissions. Sourcegraph users are matched to Gitea users with the same username, so `auth.enableUsernameChanges` must be set to false for security reasons.
+	Authorization *GiteaAuthorization `json:"authorization,omitempty"`
+	// Exclude description: A list of repositories to never mirror from this Gitea instance. Takes precedence over "orgs" and "repos".
+	Exclude []*ExcludedGiteaRepo `json:"exclude,omitempty"`
+	// Orgs description: An array of organization names identifying repositories to mirror on Sourcegraph. All repositories of the organizations that the token can access are mirrored.
+	Orgs []string `json:"orgs,omitempty"`
+	// RateLimit description: Rate limit applied when making API requests to Gitea.
+	RateLimit *GiteaRateLimit `json:"rateLimit,omitempty"`
+	// Repos description: An array of repository "owner/name" strings specifying which repositories to mirror on Sourcegraph. If neither orgs nor repos are set, all repositories that the token can access are mirrored.
+	Repos []string `json:"repos,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a repository. In the pattern, the variable "{host}" is replaced with the Gitea URL's host (such as gitea.example.com), and "{nameWithOwner}" is replaced with the repository's "owner/name" path (such as "myorg/myrepo").
+	//
+	// For example, if your Gitea URL is https://gitea.example.com and your Sourcegraph URL is https://src.example.com, then a repositoryPathPattern of "{host}/{nameWithOwner}" would mean that a repository at https://gitea.example.com/myorg/myrepo is available on Sourcegraph at https://src.example.com/gitea.example.com/myorg/myrepo.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Token description: An access token for the Gitea or Forgejo instance. It needs the read:repository and read:organization scopes to mirror repositories, and write:repository to publish changesets. To sync permissions, the token must belong to a site administrator.
+	Token string `json:"token"`
+	// Url description: URL of a Gitea or Forgejo instance, such as https://gitea.example.com.
+	Url string `json:"url"`
+}
+
+// GiteaRateLimit description: Rate limit applied when making API requests to Gitea.
+type GiteaRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 500, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 500 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // Github description: GitHub configuration, both for queries and receiving release webhooks.
 type Github struct {
 	// Repository description: The repository to get the latest version of.
@@ -1465,6 +1537,24 @@ type Header struct {
 	Value     string `json:"value"`
 }
 
+// HexPackagesConnection description: Configuration for a connection to Hex packages
+type HexPackagesConnection struct {
+	// Dependencies description: An array of strings specifying Hex packages to mirror in Sourcegraph.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to the configured Hex repository APIs.
+	RateLimit *HexRateLimit `json:"rateLimit,omitempty"`
+	// Repository description: The URL of the Hex repository that serves package tarballs.
+	Repository string `json:"repository,omitempty"`
+}
+
+// HexRateLimit description: Rate limit applied when making background API requests to the configured Hex repository APIs.
+type HexRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // Hnsw description: Overrides for the HNSW index config.
 type Hnsw struct {
 	// EfConstruct description: Number of neighbours to consider during the index building. Larger the value, more accurate the search, more time required to build the index.
@@ -1726,6 +1816,24 @@ type NpmRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+
+// NuGetPackagesConnection description: Configuration for a connection to NuGet packages
+type NuGetPackagesConnection struct {
+	// Dependencies description: An array of strings specifying NuGet packages to mirror in Sourcegraph.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to the configured NuGet repository APIs.
+	RateLimit *NuGetRateLimit `json:"rateLimit,omitempty"`
+	// Repository description: The URL of the NuGet V3 service index of the package feed.
+	Repository string `json:"repository,omitempty"`
+}
+
+// NuGetRateLimit description: Rate limit applied when making background API requests to the configured NuGet repository APIs.
+type NuGetRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
 type OAuthIdentity struct {
 	Type string `json:"type"`
 }
@@ -1908,6 +2016,8 @@ type OtherExternalServiceConnection struct {
 	// Note: These patterns are ignored if using src-expose / src-serve.
 	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
 	Url                   string `json:"url,omitempty"`
+	// Vcs description: The version control system of the repositories. Mercurial repositories are converted to Git on gitserver, which requires Mercurial and git-remote-hg to be installed in the gitserver image.
+	Vcs string `json:"vcs,omitempty"`
 }
 type OutputVariable struct {
 	// Format description: The expected format of the output. If set, the output is being parsed in that format before being stored in the var. If not set, 'text' is assumed to the format.
@@ -1916,6 +2026,24 @@ type OutputVariable struct {
 	Value string `json:"value"`
 }
 
+// PHPPackagesConnection description: Configuration for a connection to PHP packages
+type PHPPackagesConnection struct {
+	// Dependencies description: An array of strings specifying PHP packages to mirror in Sourcegraph.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to the configured PHP repository APIs.
+	RateLimit *PHPRateLimit `json:"rateLimit,omitempty"`
+	// Repository description: The URL of the Composer repository that serves package metadata.
+	Repository string `json:"repository,omitempty"`
+}
+
+// PHPRateLimit description: Rate limit applied when making background API requests to the configured PHP repository APIs.
+type PHPRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second. Internally, the burst limit is set to 100, which implies that for a requests per hour limit as low as 1, users will continue to be able to send a maximum of 100 requests immediately, provided that the complexity cost of each request is 1.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
 // PagureConnection description: Configuration for a connection to Pagure.
 type PagureConnection struct {
 	// Forks description: If true, it includes forks in the returned projects.
@@ -1947,6 +2075,16 @@ type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
 }
 
+// PartialCloneMapping description: Mapping from Git clone URL domain/path to a partial clone mode. The `domainPath` field matches the Git clone URL domain/path exactly, or any repository below it (e.g. `github.com/myorg` matches all repositories of that organization).
+type PartialCloneMapping struct {
+	// DomainPath description: Git clone URL domain/path, or a prefix of it ending at a path segment.
+	DomainPath string `json:"domainPath"`
+	// Filter description: The object filter passed to `git fetch --filter`.
+	Filter string `json:"filter,omitempty"`
+	// SparsePaths description: Paths whose file contents are fetched eagerly for the default branch after every clone and fetch. Each path matches the file or directory with that name, relative to the repository root.
+	SparsePaths []string `json:"sparsePaths,omitempty"`
+}
+
 // PasswordPolicy description: DEPRECATED: this is now a standard feature see: auth.passwordPolicy
 type PasswordPolicy struct {
 	// Enabled description: Enables password policy
@@ -2386,6 +2524,8 @@ type Settings struct {
 	SearchIncludeArchived *bool `json:"search.includeArchived,omitempty"`
 	// SearchIncludeForks description: Whether searches should include searching forked repositories.
 	SearchIncludeForks *bool `json:"search.includeForks,omitempty"`
+	// SearchMacros description: Named query fragments which can be used in search queries as `@name`. For example, with `{"prod-go": "lang:go -file:_test.go -file:vendor/"}` the query `@prod-go Client` searches non-test Go files outside of vendor directories for `Client`. Macros may use other macros. Macros in user settings override macros with the same name in organization and global settings.
+	SearchMacros map[string]string `json:"search.macros,omitempty"`
 	// SearchSavedQueries description: DEPRECATED: Saved search queries
 	SearchSavedQueries []*SearchSavedQueries `json:"search.savedQueries,omitempty"`
 	// SearchScopes description: Predefined search snippets that can be appended to any search (also known as search scopes)
@@ -2453,6 +2593,7 @@ func (v *Settings) UnmarshalJSON(data []byte) error {
 	delete(m, "search.hideSuggestions")
 	delete(m, "search.includeArchived")
 	delete(m, "search.includeForks")
+	delete(m, "search.macros")
 	delete(m, "search.savedQueries")
 	delete(m, "search.scopes")
 	if len(m) > 0 {
@@ -2707,6 +2848,8 @@ type SiteConfiguration struct {
 	CodeIntelRankingDocumentReferenceCountsGraphKey string `json:"codeIntelRanking.documentReferenceCountsGraphKey,omitempty"`
 	// CodeIntelRankingStaleResultsAge description: The interval at which to run the reduce job that computes document reference counts. Default is 24hrs.
 	CodeIntelRankingStaleResultsAge int `json:"codeIntelRanking.staleResultsAge,omitempty"`
+	// CodeIntelSearchBasedTreeSitterLanguages description: Languages without built-in support for which search-based code navigation finds definitions using tree-sitter queries. Files are parsed with one of the tree-sitter grammars bundled with Sourcegraph.
+	CodeIntelSearchBasedTreeSitterLanguages []*TreeSitterLanguage `json:"codeIntelSearchBased.treeSitterLanguages,omitempty"`
 	// CodyEnabled description: Enable or disable Cody instance-wide. When Cody is disabled, all Cody endpoints and GraphQL queries will return errors, Cody will not show up in the site-admin sidebar, and Cody in the global navbar will only show a call-to-action for site-admins to enable Cody.
 	CodyEnabled *bool `json:"cody.enabled,omitempty"`
 	// CodyRestrictUsersFeatureFlag description: Restrict Cody to only be enabled for users that have a feature flag labeled "cody" set to true. You must create a feature flag with this ID after enabling this setting: https://docs.sourcegraph.com/dev/how-to/use_feature_flags#create-a-feature-flag. This setting only has an effect if cody.enabled is true.
@@ -2893,6 +3036,8 @@ type SiteConfiguration struct {
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
 	// SearchLimits description: Limits that search applies for number of repositories searched and timeouts.
 	SearchLimits *SearchLimits `json:"search.limits,omitempty"`
+	// SearchSmartSearchRules description: Additional rules that Smart Search tries when a query has no results. Each rule matches a search pattern of the query and rewrites it to a query fragment, for example to search commit messages for issue keys, or to scope service names to their repository. The descriptions of the rules that apply are shown with the queries Smart Search runs.
+	SearchSmartSearchRules []*SmartSearchRule `json:"search.smartSearch.rules,omitempty"`
 	// SyntaxHighlighting description: Syntax highlighting configuration
 	SyntaxHighlighting *SyntaxHighlighting `json:"syntaxHighlighting,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
@@ -2970,6 +3115,7 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "codeIntelRanking.documentReferenceCountsEnabled")
 	delete(m, "codeIntelRanking.documentReferenceCountsGraphKey")
 	delete(m, "codeIntelRanking.staleResultsAge")
+	delete(m, "codeIntelSearchBased.treeSitterLanguages")
 	delete(m, "cody.enabled")
 	delete(m, "cody.restrictUsersFeatureFlag")
 	delete(m, "completions")
@@ -3063,6 +3209,7 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "search.index.symbols.enabled")
 	delete(m, "search.largeFiles")
 	delete(m, "search.limits")
+	delete(m, "search.smartSearch.rules")
 	delete(m, "syntaxHighlighting")
 	delete(m, "update.channel")
 	delete(m, "webhook.logging")
@@ -3075,6 +3222,17 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+type SmartSearchRule struct {
+	// Description description: A short description of the rule, shown with the queries it generates.
+	Description string `json:"description"`
+	// Kind description: Whether the rule narrows the query, like adding a filter, or widens it, like searching other result types. Smart Search tries combinations of narrowing rules before widening rules.
+	Kind string `json:"kind,omitempty"`
+	// Pattern description: Regular expression that must match an entire search pattern of the query, like a single word.
+	Pattern string `json:"pattern"`
+	// Rewrite description: The query fragment that replaces the matched pattern. It may contain filters and patterns. $0 refers to the matched pattern, and $1, $2, ... or ${name} to the capture groups of `pattern`.
+	Rewrite string `json:"rewrite"`
+}
+
 // SrcCliVersionCache description: Configuration related to the src-cli version cache. This should only be used on sourcegraph.com.
 type SrcCliVersionCache struct {
 	// Enabled description: Enables the src-cli version cache API endpoint.
@@ -3165,6 +3323,22 @@ type TransformChangesGroup struct {
 	// Repository description: Only apply this transformation in the repository with this name (as it is known to Sourcegraph).
 	Repository string `json:"repository,omitempty"`
 }
+type TreeSitterLanguage struct {
+	// CommentNodeTypes description: The node types of comments shown in hover documentation.
+	CommentNodeTypes []string `json:"commentNodeTypes,omitempty"`
+	// CommentStripPattern description: A regular expression matching comment delimiters to strip from hover documentation, such as ^//.
+	CommentStripPattern string `json:"commentStripPattern,omitempty"`
+	// Extensions description: File extensions of this language, without the leading dot. Defaults to the extensions Sourcegraph associates with the language name.
+	Extensions []string `json:"extensions,omitempty"`
+	// Grammar description: The bundled tree-sitter grammar used to parse files of this language.
+	Grammar string `json:"grammar"`
+	// LocalsQuery description: A tree-sitter query which captures scopes with @scope and the definitions in them with @definition. The @local.scope and @local.definition captures of tree-sitter locals queries are also accepted.
+	LocalsQuery string `json:"localsQuery"`
+	// Name description: The name of the language, such as scala. Languages with built-in support cannot be overridden.
+	Name string `json:"name"`
+	// TagsQuery description: A tree-sitter query which captures the names of top-level definitions with @name or @symbol. It is used when a name is not defined in an enclosing scope.
+	TagsQuery string `json:"tagsQuery,omitempty"`
+}
 type UpdateIntervalRule struct {
 	// Interval description: An integer representing the number of minutes to wait until the next update
 	Interval int `json:"interval"`
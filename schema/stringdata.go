@@ -37,6 +37,11 @@ var ChangesetSpecSchemaJSON string
 //go:embed gerrit.schema.json
 var GerritSchemaJSON string
 
+// GiteaSchemaJSON is the content of the file "gitea.schema.json".
+//
+//go:embed gitea.schema.json
+var GiteaSchemaJSON string
+
 // GitHubSchemaJSON is the content of the file "github.schema.json".
 //
 //go:embed github.schema.json
@@ -70,6 +75,15 @@ var RustPackagesSchemaJSON string
 //go:embed ruby-packages.schema.json
 var RubyPackagesSchemaJSON string
 
+//go:embed nuget-packages.schema.json
+var NuGetPackagesSchemaJSON string
+
+//go:embed php-packages.schema.json
+var PHPPackagesSchemaJSON string
+
+//go:embed hex-packages.schema.json
+var HexPackagesSchemaJSON string
+
 // OtherExternalServiceSchemaJSON is the content of the file "other_external_service.schema.json".
 //
 //go:embed other_external_service.schema.json